@@ -0,0 +1,148 @@
+package fetcher
+
+import (
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// futuresIncomeTimeLimit is the time range of a futures income request.
+const futuresIncomeTimeLimit = time.Hour * 24 * 30 // 30 days
+
+func (f *Fetcher) getFuturesTradeHistoryWithRetry(symbol string, fromID uint64) ([]binance.FuturesTradeHistory, error) {
+	var (
+		tradeHistoriesResponse []binance.FuturesTradeHistory
+		err                    error
+		logger                 = f.sugar.With("func", caller.GetCurrentFunctionName())
+	)
+	for attempt := 0; attempt < f.attempt; attempt++ {
+		tradeHistoriesResponse, err = f.client.GetFuturesTradeHistory(symbol, fromID)
+		switch err {
+		case binance.ErrBadAPIKeyFormat, binance.ErrRejectedMBxKey:
+			return nil, err
+		case nil:
+			return tradeHistoriesResponse, nil
+		default:
+			logger.Warnw("get futures trade history failed", "error", err, "attempt", attempt)
+			time.Sleep(f.retryDelay)
+		}
+	}
+	return tradeHistoriesResponse, err
+}
+
+func (f *Fetcher) getFuturesTradeHistoryForOneSymbol(fromID uint64, symbol string) ([]binance.FuturesTradeHistory, error) {
+	var (
+		logger = f.sugar.With("func", caller.GetCurrentFunctionName())
+		result []binance.FuturesTradeHistory
+	)
+	for {
+		tradeHistoriesResponse, err := f.getFuturesTradeHistoryWithRetry(symbol, fromID)
+		if err != nil {
+			logger.Errorw("get futures trade history error", "symbol", symbol, "error", err)
+			return result, err
+		}
+		if len(tradeHistoriesResponse) == 0 {
+			break
+		}
+		result = append(result, tradeHistoriesResponse...)
+		lastTrade := tradeHistoriesResponse[len(tradeHistoriesResponse)-1]
+		fromID = lastTrade.ID + 1
+	}
+	return result, nil
+}
+
+// GetFuturesTradeHistory get USDⓈ-M futures trades of all symbols from fromIDs and save them into database
+func (f *Fetcher) GetFuturesTradeHistory(fromIDs map[string]uint64, symbols []binance.Symbol, account string) error {
+	var (
+		logger   = f.sugar.With("func", caller.GetCurrentFunctionName())
+		errGroup errgroup.Group
+	)
+	index := 0
+	for index < len(symbols) {
+		for count := 0; count < f.batchSize && index+count < len(symbols); count++ {
+			symbol := symbols[index+count]
+			errGroup.Go(
+				func(symbol binance.Symbol) func() error {
+					return func() error {
+						logger.Infow("futures symbol", "symbol", symbol.Symbol)
+						trades, err := f.getFuturesTradeHistoryForOneSymbol(fromIDs[symbol.Symbol], symbol.Symbol)
+						if err != nil {
+							return err
+						}
+						if len(trades) == 0 {
+							return nil
+						}
+						return f.storage.UpdateFuturesTradeHistory(trades, account)
+					}
+				}(symbol),
+			)
+		}
+		if err := errGroup.Wait(); err != nil {
+			return err
+		}
+		index += f.batchSize
+	}
+	return nil
+}
+
+func (f *Fetcher) getFuturesIncomeHistoryWithRetry(incomeType string, fromTime, toTime time.Time) ([]binance.FuturesIncome, error) {
+	var (
+		incomes []binance.FuturesIncome
+		err     error
+		logger  = f.sugar.With("func", caller.GetCurrentFunctionName())
+	)
+	for attempt := 0; attempt < f.attempt; attempt++ {
+		incomes, err = f.client.GetFuturesIncomeHistory(incomeType, fromTime, toTime)
+		switch err {
+		case binance.ErrBadAPIKeyFormat, binance.ErrRejectedMBxKey:
+			return nil, err
+		case nil:
+			return incomes, nil
+		default:
+			logger.Warnw("get futures income history failed", "income type", incomeType, "error", err, "attempt", attempt)
+			time.Sleep(f.retryDelay)
+		}
+	}
+	return incomes, err
+}
+
+// GetFuturesIncomeHistory get USDⓈ-M futures incomes of incomeType, e.g. funding fees or
+// transfers, in time range fromTime to toTime and save them into database
+func (f *Fetcher) GetFuturesIncomeHistory(incomeType string, fromTime, toTime time.Time, account string) error {
+	var (
+		logger = f.sugar.With("func", caller.GetCurrentFunctionName(),
+			"income type", incomeType, "account", account)
+	)
+	for toTime.After(fromTime) {
+		endTime := fromTime.Add(futuresIncomeTimeLimit)
+		if endTime.After(toTime) {
+			endTime = toTime
+		}
+		incomes, err := f.getFuturesIncomeHistoryWithRetry(incomeType, fromTime, endTime)
+		if err != nil {
+			logger.Errorw("get futures income history failed after retry", "attempts", f.attempt, "error", err)
+			return err
+		}
+		if len(incomes) != 0 {
+			if err := f.storage.UpdateFuturesIncomeHistory(incomes, account); err != nil {
+				return err
+			}
+		}
+		if len(incomes) < binance.FuturesIncomeLimit {
+			fromTime = endTime
+			continue
+		}
+		// the window has more records than a response can hold, continue from
+		// the last returned one, records of the same millisecond are deduplicated by storage
+		lastTime := timeutil.TimestampMsToTime(incomes[len(incomes)-1].Time)
+		if !lastTime.After(fromTime) {
+			lastTime = fromTime.Add(time.Millisecond)
+		}
+		fromTime = lastTime
+	}
+	return nil
+}
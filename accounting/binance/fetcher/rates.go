@@ -0,0 +1,74 @@
+package fetcher
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	// rateWindow is how far before a time aggregated trades are looked up, the same as convert trades.
+	rateWindow  = time.Hour
+	ethUSDQuote = "USDT"
+)
+
+// Rates returns the prices of assets at a time from Binance aggregated trades of the hour before, the way
+// the ETH prices of trades not in ETH markets are converted.
+type Rates struct {
+	f       *Fetcher
+	symbols map[string]bool
+}
+
+// NewRates creates Rates of the markets of symbols.
+func NewRates(f *Fetcher, symbols []binance.Symbol) *Rates {
+	r := &Rates{f: f, symbols: make(map[string]bool)}
+	for _, symbol := range symbols {
+		r.symbols[symbol.Symbol] = true
+	}
+	return r
+}
+
+func (r *Rates) price(symbol string, at time.Time) (float64, bool, error) {
+	endTime := timeutil.TimeToTimestampMs(at)
+	startTime := timeutil.TimeToTimestampMs(at.Add(-rateWindow))
+	trades, err := r.f.getGetAggregatedTradesWithRetry(symbol, startTime, endTime)
+	if err != nil {
+		return 0, false, err
+	}
+	if len(trades) == 0 {
+		return 0, false, nil
+	}
+	price, err := strconv.ParseFloat(trades[0].Price, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return price, price > 0, nil
+}
+
+// ETHRate returns the price of asset in ETH from the ASSETETH or ETHASSET market.
+func (r *Rates) ETHRate(asset string, at time.Time) (float64, bool, error) {
+	asset = strings.ToUpper(asset)
+	if symbol := asset + "ETH"; r.symbols[symbol] {
+		return r.price(symbol, at)
+	}
+	if symbol := "ETH" + asset; r.symbols[symbol] {
+		price, ok, err := r.price(symbol, at)
+		if err != nil || !ok {
+			return 0, false, err
+		}
+		return 1 / price, true, nil
+	}
+	return 0, false, nil
+}
+
+// ETHUSDRate returns the price of ETH in USD from the ETHUSDT market.
+func (r *Rates) ETHUSDRate(at time.Time) (float64, bool, error) {
+	symbol := "ETH" + ethUSDQuote
+	if !r.symbols[symbol] {
+		return 0, false, nil
+	}
+	return r.price(symbol, at)
+}
@@ -0,0 +1,64 @@
+package fetcher
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// ResolveSubAccounts returns configured sub-accounts of master that are listed
+// by the master key. Binance does not serve trade history of a sub-account to its
+// master key, so only sub-accounts with their own API key configured are returned.
+func ResolveSubAccounts(sugar *zap.SugaredLogger, master common.Account, listed []binance.SubAccount) []common.Account {
+	var (
+		logger = sugar.With("func", caller.GetCurrentFunctionName(), "master", master.Name)
+		result []common.Account
+	)
+	configured := make(map[string]common.Account)
+	for _, subAccount := range master.SubAccounts {
+		configured[strings.ToLower(subAccount.Email)] = subAccount
+	}
+	for _, subAccount := range listed {
+		email := strings.ToLower(subAccount.Email)
+		account, ok := configured[email]
+		if !ok {
+			logger.Warnw("sub-account has no API key configured, skip it", "email", subAccount.Email)
+			continue
+		}
+		delete(configured, email)
+		result = append(result, account)
+	}
+	for _, account := range configured {
+		logger.Warnw("configured sub-account is not listed by master account, skip it",
+			"name", account.Name, "email", account.Email)
+	}
+	return result
+}
+
+// WithSubAccounts returns accounts followed by their sub-accounts resolved by ResolveSubAccounts.
+// Sub-accounts are only listed for master accounts having sub-accounts configured.
+func WithSubAccounts(sugar *zap.SugaredLogger, accounts []common.Account, options ...binance.Option) ([]common.Account, error) {
+	var (
+		result []common.Account
+	)
+	for _, account := range accounts {
+		result = append(result, account)
+		if len(account.SubAccounts) == 0 {
+			continue
+		}
+		client, err := binance.NewBinance(account.APIKey, account.SecretKey, sugar, options...)
+		if err != nil {
+			return nil, err
+		}
+		listed, err := client.GetSubAccounts()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ResolveSubAccounts(sugar, account, listed)...)
+	}
+	return result, nil
+}
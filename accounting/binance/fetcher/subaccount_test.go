@@ -0,0 +1,33 @@
+package fetcher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+)
+
+func TestResolveSubAccounts(t *testing.T) {
+	var (
+		hedge = common.Account{Name: "binance_hedge", Email: "Hedge@example.com", APIKey: "hedge-key", SecretKey: "hedge-secret"}
+		gone  = common.Account{Name: "binance_gone", Email: "gone@example.com", APIKey: "gone-key", SecretKey: "gone-secret"}
+		main  = common.Account{
+			Name:        "binance_main",
+			APIKey:      "main-key",
+			SecretKey:   "main-secret",
+			SubAccounts: []common.Account{hedge, gone},
+		}
+		listed = []binance.SubAccount{
+			{Email: "hedge@example.com"},
+			{Email: "unconfigured@example.com"},
+		}
+	)
+
+	// emails are matched case-insensitively, sub-accounts without keys or not
+	// listed by the master account are skipped
+	assert.Equal(t, []common.Account{hedge}, ResolveSubAccounts(zap.NewNop().Sugar(), main, listed))
+	assert.Empty(t, ResolveSubAccounts(zap.NewNop().Sugar(), main, nil))
+}
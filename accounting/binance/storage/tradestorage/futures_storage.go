@@ -0,0 +1,192 @@
+package tradestorage
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// UpdateFuturesTradeHistory save USDⓈ-M futures trades of account into db
+func (bd *BinanceStorage) UpdateFuturesTradeHistory(trades []binance.FuturesTradeHistory, account string) (err error) {
+	var (
+		logger     = bd.sugar.With("func", caller.GetCurrentFunctionName())
+		tradeJSON  []byte
+		dataJSON   [][]byte
+		ids        []uint64
+		timestamps []time.Time
+		symbols    []string
+	)
+	const updateQuery = `INSERT INTO binance_futures_trades (id, data, timestamp, symbol, account)
+	VALUES(
+		unnest($1::BIGINT[]),
+		unnest($2::JSONB[]),
+		unnest($3::TIMESTAMP[]),
+		unnest($4::TEXT[]),
+		$5
+	) ON CONFLICT ON CONSTRAINT binance_futures_trades_pk DO NOTHING;
+	`
+
+	tx, err := bd.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer pgsql.CommitOrRollback(tx, bd.sugar, &err)
+
+	for _, trade := range trades {
+		tradeJSON, err = json.Marshal(trade)
+		if err != nil {
+			logger.Errorw("failed to marshal futures trade", "error", err)
+			return
+		}
+		ids = append(ids, trade.ID)
+		dataJSON = append(dataJSON, tradeJSON)
+		timestamps = append(timestamps, timeutil.TimestampMsToTime(trade.Time))
+		symbols = append(symbols, trade.Symbol)
+	}
+
+	_, err = tx.Exec(updateQuery, pq.Array(ids), pq.Array(dataJSON), pq.Array(timestamps), pq.Array(symbols), account)
+	return err
+}
+
+// GetFuturesTradeHistory return USDⓈ-M futures trades in time range, keyed by account
+func (bd *BinanceStorage) GetFuturesTradeHistory(fromTime, toTime time.Time) (map[string][]binance.FuturesTradeHistory, error) {
+	var (
+		logger   = bd.sugar.With("func", caller.GetCurrentFunctionName())
+		result   = make(map[string][]binance.FuturesTradeHistory)
+		dbResult []TradeHistoryDB
+	)
+	const selectStmt = `SELECT account, ARRAY_AGG(data ORDER BY timestamp, id) as data FROM binance_futures_trades WHERE timestamp >=$1::TIMESTAMP AND timestamp <=$2::TIMESTAMP GROUP BY account;`
+
+	logger.Debugw("querying futures trade history...", "query", selectStmt)
+
+	if err := bd.db.Select(&dbResult, selectStmt, fromTime.UTC(), toTime.UTC()); err != nil {
+		return result, err
+	}
+	for _, record := range dbResult {
+		arrResult := []binance.FuturesTradeHistory{}
+		for _, data := range record.Data {
+			var tmp binance.FuturesTradeHistory
+			if err := json.Unmarshal(data, &tmp); err != nil {
+				return result, err
+			}
+			arrResult = append(arrResult, tmp)
+		}
+		result[record.Account] = arrResult
+	}
+	return result, nil
+}
+
+// GetLastStoredFuturesTradeID return last stored USDⓈ-M futures trade id of symbol and account
+func (bd *BinanceStorage) GetLastStoredFuturesTradeID(symbol, account string) (uint64, error) {
+	var (
+		logger = bd.sugar.With("func", caller.GetCurrentFunctionName())
+		result uint64
+	)
+	const selectStmt = `SELECT COALESCE(MAX(id), 0) FROM binance_futures_trades WHERE symbol=$1 AND account=$2`
+
+	if err := bd.db.Get(&result, selectStmt, symbol, account); err != nil {
+		logger.Errorw("failed to get last stored futures trade id", "error", err)
+		return 0, err
+	}
+	return result, nil
+}
+
+// UpdateFuturesIncomeHistory save USDⓈ-M futures incomes of account into db
+func (bd *BinanceStorage) UpdateFuturesIncomeHistory(incomes []binance.FuturesIncome, account string) (err error) {
+	var (
+		logger      = bd.sugar.With("func", caller.GetCurrentFunctionName())
+		incomeJSON  []byte
+		dataJSON    [][]byte
+		tranIDs     []int64
+		incomeTypes []string
+		symbols     []string
+		timestamps  []time.Time
+	)
+	const updateQuery = `INSERT INTO binance_futures_incomes (tran_id, income_type, symbol, data, timestamp, account)
+	VALUES(
+		unnest($1::BIGINT[]),
+		unnest($2::TEXT[]),
+		unnest($3::TEXT[]),
+		unnest($4::JSONB[]),
+		unnest($5::TIMESTAMP[]),
+		$6
+	) ON CONFLICT ON CONSTRAINT binance_futures_incomes_pk DO NOTHING;
+	`
+
+	tx, err := bd.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	defer pgsql.CommitOrRollback(tx, bd.sugar, &err)
+
+	for _, income := range incomes {
+		incomeJSON, err = json.Marshal(income)
+		if err != nil {
+			logger.Errorw("failed to marshal futures income", "error", err)
+			return
+		}
+		tranIDs = append(tranIDs, income.TranID)
+		incomeTypes = append(incomeTypes, income.IncomeType)
+		symbols = append(symbols, income.Symbol)
+		dataJSON = append(dataJSON, incomeJSON)
+		timestamps = append(timestamps, timeutil.TimestampMsToTime(income.Time))
+	}
+
+	_, err = tx.Exec(updateQuery, pq.Array(tranIDs), pq.Array(incomeTypes), pq.Array(symbols), pq.Array(dataJSON), pq.Array(timestamps), account)
+	return err
+}
+
+// GetFuturesIncomeHistory return USDⓈ-M futures incomes in time range, keyed by account
+func (bd *BinanceStorage) GetFuturesIncomeHistory(fromTime, toTime time.Time) (map[string][]binance.FuturesIncome, error) {
+	var (
+		logger   = bd.sugar.With("func", caller.GetCurrentFunctionName())
+		result   = make(map[string][]binance.FuturesIncome)
+		dbResult []TradeHistoryDB
+	)
+	const selectStmt = `SELECT account, ARRAY_AGG(data ORDER BY timestamp, tran_id) as data FROM binance_futures_incomes WHERE timestamp >=$1::TIMESTAMP AND timestamp <=$2::TIMESTAMP GROUP BY account;`
+
+	logger.Debugw("querying futures income history...", "query", selectStmt)
+
+	if err := bd.db.Select(&dbResult, selectStmt, fromTime.UTC(), toTime.UTC()); err != nil {
+		return result, err
+	}
+	for _, record := range dbResult {
+		arrResult := []binance.FuturesIncome{}
+		for _, data := range record.Data {
+			var tmp binance.FuturesIncome
+			if err := json.Unmarshal(data, &tmp); err != nil {
+				return result, err
+			}
+			arrResult = append(arrResult, tmp)
+		}
+		result[record.Account] = arrResult
+	}
+	return result, nil
+}
+
+// GetLastStoredFuturesIncomeTime return time of the last stored USDⓈ-M futures income
+// of incomeType and account, zero time if there is none
+func (bd *BinanceStorage) GetLastStoredFuturesIncomeTime(incomeType, account string) (time.Time, error) {
+	var (
+		logger = bd.sugar.With("func", caller.GetCurrentFunctionName())
+		result *time.Time
+	)
+	const selectStmt = `SELECT MAX(timestamp) FROM binance_futures_incomes WHERE income_type=$1 AND account=$2`
+
+	if err := bd.db.Get(&result, selectStmt, incomeType, account); err != nil {
+		logger.Errorw("failed to get last stored futures income time", "error", err)
+		return time.Time{}, err
+	}
+	if result == nil {
+		return time.Time{}, nil
+	}
+	return result.UTC(), nil
+}
@@ -0,0 +1,115 @@
+package tradestorage
+
+import (
+	"testing"
+
+	_ "github.com/lib/pq" // sql driver name: "postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func TestBinanceFuturesStorage(t *testing.T) {
+	logger := testutil.MustNewDevelopmentSugaredLogger()
+	var (
+		trades = []binance.FuturesTradeHistory{
+			{
+				Symbol:          "BNBUSDT",
+				ID:              698759,
+				OrderID:         25851813,
+				Side:            "BUY",
+				PositionSide:    "BOTH",
+				Price:           "7.81900",
+				Quantity:        "1.00",
+				QuoteQuantity:   "7.81900",
+				RealizedPnl:     "0",
+				MarginAsset:     "USDT",
+				Commission:      "0.00312760",
+				CommissionAsset: "USDT",
+				Time:            1569514978020,
+				IsBuyer:         true,
+			},
+			{
+				Symbol:          "BNBUSDT",
+				ID:              698790,
+				OrderID:         25851901,
+				Side:            "SELL",
+				PositionSide:    "BOTH",
+				Price:           "7.92400",
+				Quantity:        "1.00",
+				QuoteQuantity:   "7.92400",
+				RealizedPnl:     "0.10500000",
+				MarginAsset:     "USDT",
+				Commission:      "0.00316960",
+				CommissionAsset: "USDT",
+				Time:            1569515978020,
+				IsMaker:         true,
+			},
+		}
+		incomes = []binance.FuturesIncome{
+			{
+				IncomeType: binance.FuturesIncomeTransfer,
+				Income:     "100.00000000",
+				Asset:      "USDT",
+				Time:       1569514000000,
+				TranID:     9689322392,
+			},
+			{
+				Symbol:     "BNBUSDT",
+				IncomeType: binance.FuturesIncomeFundingFee,
+				Income:     "-0.01000000",
+				Asset:      "USDT",
+				Time:       1569516000000,
+				TranID:     9689322393,
+			},
+		}
+		fromTime = timeutil.TimestampMsToTime(1569514000000)
+		toTime   = timeutil.TimestampMsToTime(1569516000000)
+	)
+
+	db, teardown := testutil.MustNewDevelopmentDB()
+	binanceStorage, err := NewDB(logger, db)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, teardown())
+	}()
+
+	lastStoredID, err := binanceStorage.GetLastStoredFuturesTradeID("BNBUSDT", "binance_1")
+	require.NoError(t, err)
+	assert.Zero(t, lastStoredID)
+
+	// storing twice does not duplicate trades
+	require.NoError(t, binanceStorage.UpdateFuturesTradeHistory(trades, "binance_1"))
+	require.NoError(t, binanceStorage.UpdateFuturesTradeHistory(trades, "binance_1"))
+	require.NoError(t, binanceStorage.UpdateFuturesTradeHistory(trades[:1], "binance_sub_account_1"))
+
+	lastStoredID, err = binanceStorage.GetLastStoredFuturesTradeID("BNBUSDT", "binance_1")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(698790), lastStoredID)
+
+	storedTrades, err := binanceStorage.GetFuturesTradeHistory(fromTime, toTime)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]binance.FuturesTradeHistory{
+		"binance_1":             trades,
+		"binance_sub_account_1": trades[:1],
+	}, storedTrades)
+
+	lastIncomeTime, err := binanceStorage.GetLastStoredFuturesIncomeTime(binance.FuturesIncomeFundingFee, "binance_1")
+	require.NoError(t, err)
+	assert.True(t, lastIncomeTime.IsZero())
+
+	require.NoError(t, binanceStorage.UpdateFuturesIncomeHistory(incomes, "binance_1"))
+	require.NoError(t, binanceStorage.UpdateFuturesIncomeHistory(incomes, "binance_1"))
+
+	lastIncomeTime, err = binanceStorage.GetLastStoredFuturesIncomeTime(binance.FuturesIncomeFundingFee, "binance_1")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1569516000000), timeutil.TimeToTimestampMs(lastIncomeTime))
+
+	storedIncomes, err := binanceStorage.GetFuturesIncomeHistory(fromTime, toTime)
+	require.NoError(t, err)
+	assert.Equal(t, incomes, storedIncomes["binance_1"])
+}
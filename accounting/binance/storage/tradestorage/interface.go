@@ -21,4 +21,12 @@ type Interface interface {
 	UpdateMarginTradeHistory([]binance.TradeHistory, string) error
 	GetMarginTradeHistory(fromTime, toTime time.Time) (map[string][]binance.TradeHistory, error)
 	GetLastStoredMarginTradeID(symbol, account string) (uint64, error)
+
+	UpdateFuturesTradeHistory([]binance.FuturesTradeHistory, string) error
+	GetFuturesTradeHistory(fromTime, toTime time.Time) (map[string][]binance.FuturesTradeHistory, error)
+	GetLastStoredFuturesTradeID(symbol, account string) (uint64, error)
+
+	UpdateFuturesIncomeHistory([]binance.FuturesIncome, string) error
+	GetFuturesIncomeHistory(fromTime, toTime time.Time) (map[string][]binance.FuturesIncome, error)
+	GetLastStoredFuturesIncomeTime(incomeType, account string) (time.Time, error)
 }
@@ -62,6 +62,29 @@ func NewDB(sugar *zap.SugaredLogger, db *sqlx.DB) (*BinanceStorage, error) {
 		CONSTRAINT binance_convert_to_eth_price_pk PRIMARY KEY(symbol, price, timestamp)
 	);
 	CREATE INDEX IF NOT EXISTS binance_convert_to_eth_price_time_idx ON binance_convert_to_eth_price(timestamp);
+
+	CREATE TABLE IF NOT EXISTS "binance_futures_trades"
+	(
+		id bigint NOT NULL,
+		symbol TEXT NOT NULL,
+		data JSONB,
+		timestamp TIMESTAMP NOT NULL,
+		account TEXT NOT NULL,
+		CONSTRAINT binance_futures_trades_pk PRIMARY KEY(id, symbol, account)
+	);
+	CREATE INDEX IF NOT EXISTS binance_futures_trades_time_idx ON binance_futures_trades (timestamp);
+
+	CREATE TABLE IF NOT EXISTS "binance_futures_incomes"
+	(
+		tran_id bigint NOT NULL,
+		income_type TEXT NOT NULL,
+		symbol TEXT NOT NULL,
+		data JSONB,
+		timestamp TIMESTAMP NOT NULL,
+		account TEXT NOT NULL,
+		CONSTRAINT binance_futures_incomes_pk PRIMARY KEY(tran_id, income_type, symbol, account)
+	);
+	CREATE INDEX IF NOT EXISTS binance_futures_incomes_time_idx ON binance_futures_incomes (timestamp);
 	`
 
 	s := &BinanceStorage{
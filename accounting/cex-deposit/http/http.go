@@ -10,9 +10,12 @@ import (
 	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/depositstorage"
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	_ "github.com/KyberNetwork/reserve-stats/accounting/common/validators" // import custom validator functions
+	huobiStorage "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/deposit-history"
 	"github.com/KyberNetwork/reserve-stats/lib/binance"
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 const (
@@ -22,10 +25,12 @@ const (
 
 // Server is the engine to serve cex-trade-withdrawal API query
 type Server struct {
-	r         *gin.Engine
-	binanceDB *depositstorage.BinanceStorage
-	host      string
-	sugar     *zap.SugaredLogger
+	r              *gin.Engine
+	binanceDB      *depositstorage.BinanceStorage
+	huobiDB        huobiStorage.Interface
+	host           string
+	sugar          *zap.SugaredLogger
+	openAPIOptions []openapi.Option
 }
 
 type queryInput struct {
@@ -34,14 +39,15 @@ type queryInput struct {
 }
 
 type response struct {
+	Huobi   map[string][]huobi.DepositHistory   `json:"huobi,omitempty"`
 	Binance map[string][]binance.DepositHistory `json:"binance,omitempty"`
 }
 
 func (sv *Server) get(c *gin.Context) {
 	var (
-		query  queryInput
-		logger = sv.sugar.With("func", caller.GetCurrentFunctionName())
-		// huobiWithdrawals = make(map[string][]huobi.WithdrawHistory)
+		query          queryInput
+		logger         = sv.sugar.With("func", caller.GetCurrentFunctionName())
+		huobiDeposit   = make(map[string][]huobi.DepositHistory)
 		binanceDeposit = make(map[string][]binance.DepositHistory) // map account with its trades
 	)
 
@@ -56,6 +62,7 @@ func (sv *Server) get(c *gin.Context) {
 
 	if len(query.Exchanges) == 0 {
 		query.Exchanges = []string{
+			common.Huobi.String(),
 			common.Binance.String(),
 		}
 	}
@@ -74,10 +81,21 @@ func (sv *Server) get(c *gin.Context) {
 	}
 
 	logger = logger.With("from", from, "to", to, "exchanges", query.Exchanges)
-	logger.Debug("querying withdrawals from database")
+	logger.Debug("querying deposits from database")
 
 	for _, cex := range query.Exchanges {
-		if cex == common.Binance.String() {
+		switch cex {
+		case common.Huobi.String():
+			huobiDeposit, err = sv.huobiDB.GetDepositHistory(from, to)
+			if err != nil {
+				httputil.ResponseFailure(
+					c,
+					http.StatusInternalServerError,
+					err,
+				)
+				return
+			}
+		case common.Binance.String():
 			binanceDeposit, err = sv.binanceDB.GetDepositHistory(from, to)
 			if err != nil {
 				httputil.ResponseFailure(
@@ -91,12 +109,18 @@ func (sv *Server) get(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, response{
+		Huobi:   huobiDeposit,
 		Binance: binanceDeposit,
 	})
 }
 
 func (sv *Server) register() {
-	sv.r.GET("/deposits", sv.get)
+	api := openapi.NewRouter(sv.r, openapi.NewSpec("cex deposits"), sv.openAPIOptions...)
+	api.GET("/deposits", openapi.Endpoint{
+		Summary:  "deposits of centralized exchanges, keyed by account",
+		Query:    queryInput{},
+		Response: response{},
+	}, sv.get)
 }
 
 // Run starts HTTP server on preconfigure-host. Return error if occurs
@@ -106,12 +130,14 @@ func (sv *Server) Run() error {
 }
 
 // NewServer create an instance of Server to serve API query
-func NewServer(host string, binanceDB *depositstorage.BinanceStorage, sugar *zap.SugaredLogger) (*Server, error) {
+func NewServer(host string, binanceDB *depositstorage.BinanceStorage, huobiDB huobiStorage.Interface, sugar *zap.SugaredLogger, openAPIOptions ...openapi.Option) (*Server, error) {
 	r := gin.Default()
 	return &Server{
-		r:         r,
-		binanceDB: binanceDB,
-		host:      host,
-		sugar:     sugar,
+		r:              r,
+		binanceDB:      binanceDB,
+		huobiDB:        huobiDB,
+		host:           host,
+		sugar:          sugar,
+		openAPIOptions: openAPIOptions,
 	}, nil
 }
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+type getBalancesQuery struct {
+	// At is the timestamp in milliseconds of the balances, default is now.
+	At        uint64   `form:"at"`
+	Exchanges []string `form:"cex"`
+}
+
+type getBalancesResponse struct {
+	At uint64 `json:"at"`
+	// Balances is the latest snapshot at or before the time, keyed by exchange and account.
+	Balances map[string]map[string][]cex.Balance `json:"balances"`
+}
+
+// getBalances returns the reported balances of centralized exchange accounts at a time.
+func (s *Server) getBalances(c *gin.Context) {
+	var (
+		logger   = s.sugar.With("func", caller.GetCurrentFunctionName())
+		query    getBalancesQuery
+		balances = make(map[string]map[string][]cex.Balance)
+	)
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		logger.Errorw("failed to validate query", "error", err)
+		httputil.ResponseFailure(
+			c,
+			http.StatusBadRequest,
+			err,
+		)
+		return
+	}
+	if s.cs == nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusInternalServerError,
+			errors.New("balance storage is not configured"),
+		)
+		return
+	}
+
+	at := time.Now()
+	if query.At != 0 {
+		at = timeutil.TimestampMsToTime(query.At)
+	}
+	if len(query.Exchanges) == 0 {
+		query.Exchanges = append([]string{
+			common.Huobi.String(),
+			common.Binance.String()}, cex.Names()...)
+	}
+
+	logger = logger.With("at", at, "exchanges", query.Exchanges)
+	logger.Debug("querying balances from database")
+
+	for _, exchange := range query.Exchanges {
+		accountBalances, err := s.cs.GetBalances(exchange, at)
+		if err != nil {
+			logger.Errorw("failed to get balances", "exchange", exchange, "error", err)
+			httputil.ResponseFailure(
+				c,
+				http.StatusInternalServerError,
+				err,
+			)
+			return
+		}
+		if len(accountBalances) != 0 {
+			balances[exchange] = accountBalances
+		}
+	}
+
+	c.JSON(http.StatusOK, getBalancesResponse{
+		At:       timeutil.TimeToTimestampMs(at),
+		Balances: balances,
+	})
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/commission"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+)
+
+// getCommissions returns the normalized commissions of CEX trades.
+func (s *Server) getCommissions(c *gin.Context) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+		query  httputil.TimeRangeQuery
+	)
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	from, to, err := query.Validate(
+		httputil.TimeRangeQueryWithMaxTimeFrame(maxTimeFrame),
+		httputil.TimeRangeQueryWithDefaultTimeFrame(defaultTimeFrame),
+	)
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	result := []commission.Commission{}
+	if s.cms != nil {
+		commissions, err := s.cms.GetCommissions(from, to)
+		if err != nil {
+			logger.Errorw("failed to get commissions", "error", err)
+			httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+			return
+		}
+		result = append(result, commissions...)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// binanceFees returns the ETH and USD values of binance commissions keyed by account and trade id.
+func (s *Server) binanceFees(from, to time.Time) (map[[2]string][2]float64, error) {
+	result := make(map[[2]string][2]float64)
+	if s.cms == nil {
+		return result, nil
+	}
+	commissions, err := s.cms.GetCommissions(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range commissions {
+		if c.Source != common.Binance.String() || c.PriceMissing {
+			continue
+		}
+		result[[2]string{c.Account, c.TradeID}] = [2]float64{c.ETH, c.USD}
+	}
+	return result, nil
+}
+
+// setFee sets the commission of a rebalance trade and deducts it from its ETH change.
+func setFee(trade *ConvertTrade, fee [2]float64) {
+	trade.FeeETH, trade.FeeUSD = fee[0], fee[1]
+	trade.ETHChange -= trade.FeeETH
+}
+
+// feeRate returns the commission of trades as a ratio of their ETH amount before commission.
+func feeRate(trades []*ConvertTrade) float64 {
+	var fees, amount float64
+	for _, t := range trades {
+		fees += t.FeeETH
+		amount += math.Abs(t.ETHChange + t.FeeETH)
+	}
+	if amount == 0 {
+		return 0
+	}
+	return fees / amount
+}
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectPricingGoodNetOfFees(t *testing.T) {
+	const delta = 0.000001
+	var (
+		s       = &Server{}
+		onchain = []*ConvertTrade{
+			{AccountName: "0xRFQ", Pair: "KNCETH", Type: buyType, Rate: 0.001, ETHChange: -1},
+		}
+		rebalance = ConvertTrade{AccountName: "binance_1", Pair: "KNCETH", Type: sellType, Rate: 0.0011, ETHChange: 1.1}
+	)
+	pnlRate, pricingGood := s.detectPricingGood("KNCETH", onchain, []*ConvertTrade{&rebalance})
+	assert.InDelta(t, 1.1, pnlRate, delta)
+	assert.True(t, pricingGood)
+
+	setFee(&rebalance, [2]float64{0.011, 20})
+	assert.InDelta(t, 1.089, rebalance.ETHChange, delta)
+	assert.Equal(t, 20.0, rebalance.FeeUSD)
+	pnlRate, pricingGood = s.detectPricingGood("KNCETH", onchain, []*ConvertTrade{&rebalance})
+	// commission is 1% of the rebalanced ETH amount
+	assert.InDelta(t, 1.09, pnlRate, delta)
+	assert.True(t, pricingGood)
+
+	rebalance.FeeETH, rebalance.ETHChange = 0.11, 0.99
+	_, pricingGood = s.detectPricingGood("KNCETH", onchain, []*ConvertTrade{&rebalance})
+	assert.False(t, pricingGood)
+}
@@ -83,6 +83,27 @@ var (
 			},
 		},
 	}
+	binanceFuturesTrades = map[string][]binance.FuturesTradeHistory{
+		"binance_sub_account_1": {
+			{
+				Symbol:          "BNBUSDT",
+				ID:              698759,
+				OrderID:         25851813,
+				Side:            "BUY",
+				PositionSide:    "BOTH",
+				Price:           "7.81900",
+				Quantity:        "1.00",
+				QuoteQuantity:   "7.81900",
+				RealizedPnl:     "-0.91539999",
+				MarginAsset:     "USDT",
+				Commission:      "-0.07819010",
+				CommissionAsset: "USDT",
+				Time:            1528675200000,
+				IsBuyer:         true,
+				IsMaker:         false,
+			},
+		},
+	}
 )
 
 func TestTrades(t *testing.T) {
@@ -104,8 +125,9 @@ func TestTrades(t *testing.T) {
 				err := json.NewDecoder(resp.Body).Decode(&trades)
 				require.NoError(t, err)
 				assert.Equal(t, getTradesResponse{
-					Huobi:   expectedHuobiTrades,
-					Binance: binanceTrades,
+					Huobi:          expectedHuobiTrades,
+					Binance:        binanceTrades,
+					BinanceFutures: binanceFuturesTrades,
 				}, trades)
 			},
 		},
@@ -127,6 +149,7 @@ func TestTrades(t *testing.T) {
 				require.NoError(t, err)
 				assert.Len(t, trades.Huobi, 0)
 				assert.Len(t, trades.Binance, 0)
+				assert.Len(t, trades.BinanceFutures, 0)
 			},
 		},
 		{
@@ -147,6 +170,7 @@ func TestTrades(t *testing.T) {
 				require.NoError(t, err)
 				assert.Len(t, trades.Huobi, 0)
 				assert.Len(t, trades.Binance, 0)
+				assert.Len(t, trades.BinanceFutures, 0)
 			},
 		},
 		{
@@ -189,7 +213,8 @@ func TestTrades(t *testing.T) {
 				err := json.NewDecoder(resp.Body).Decode(&trades)
 				require.NoError(t, err)
 				assert.Equal(t, getTradesResponse{
-					Binance: binanceTrades,
+					Binance:        binanceTrades,
+					BinanceFutures: binanceFuturesTrades,
 				}, trades)
 			},
 		},
@@ -222,7 +247,11 @@ func TestMain(m *testing.M) {
 		log.Fatal(err)
 	}
 
-	ts = NewServer(sugar, "", hs, bs, nil)
+	if err = bs.UpdateFuturesTradeHistory(binanceFuturesTrades["binance_sub_account_1"], "binance_sub_account_1"); err != nil {
+		log.Fatal(err)
+	}
+
+	ts = NewServer(sugar, "", hs, bs, nil, nil, nil)
 	ts.register()
 
 	ret := m.Run()
@@ -5,40 +5,76 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/tradestorage"
+	cexstorage "github.com/KyberNetwork/reserve-stats/accounting/cex/storage"
+	"github.com/KyberNetwork/reserve-stats/accounting/commission"
+	commissionstorage "github.com/KyberNetwork/reserve-stats/accounting/commission/storage"
 	huobistorage "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage"
 	"github.com/KyberNetwork/reserve-stats/accounting/zerox/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 // Server is the HTTP server of accounting CEX getTrades HTTP API.
 type Server struct {
-	sugar *zap.SugaredLogger
-	r     *gin.Engine
-	host  string
-	hs    huobistorage.Interface
-	bs    tradestorage.Interface
-	zs    *storage.ZeroxStorage
+	sugar          *zap.SugaredLogger
+	r              *gin.Engine
+	host           string
+	hs             huobistorage.Interface
+	bs             tradestorage.Interface
+	zs             *storage.ZeroxStorage
+	cs             cexstorage.Interface
+	cms            commissionstorage.Interface
+	openAPIOptions []openapi.Option
 }
 
 // NewServer creates a new instance of Server.
-func NewServer(sugar *zap.SugaredLogger, host string, hs huobistorage.Interface, bs tradestorage.Interface, zs *storage.ZeroxStorage) *Server {
+func NewServer(sugar *zap.SugaredLogger, host string, hs huobistorage.Interface, bs tradestorage.Interface, zs *storage.ZeroxStorage, cs cexstorage.Interface,
+	cms commissionstorage.Interface, openAPIOptions ...openapi.Option) *Server {
 	r := gin.Default()
 	return &Server{
-		sugar: sugar,
-		r:     r,
-		host:  host,
-		hs:    hs,
-		bs:    bs,
-		zs:    zs,
+		sugar:          sugar,
+		r:              r,
+		host:           host,
+		hs:             hs,
+		bs:             bs,
+		zs:             zs,
+		cs:             cs,
+		cms:            cms,
+		openAPIOptions: openAPIOptions,
 	}
 
 }
 
 func (s *Server) register() {
-	s.r.GET("/trades", s.getTrades)
-	s.r.GET("/convert_to_eth_price", s.getConvertToETHPrice)
+	api := openapi.NewRouter(s.r, openapi.NewSpec("cex trades"), s.openAPIOptions...)
+	api.GET("/trades", openapi.Endpoint{
+		Summary:  "trades of centralized exchanges, keyed by account",
+		Query:    getTradesQuery{},
+		Response: getTradesResponse{},
+	}, s.getTrades)
+	api.GET("/balances", openapi.Endpoint{
+		Summary:  "balance snapshots of centralized exchange accounts at a time, keyed by exchange and account",
+		Query:    getBalancesQuery{},
+		Response: getBalancesResponse{},
+	}, s.getBalances)
+	api.GET("/commissions", openapi.Endpoint{
+		Summary:  "commissions of CEX trades normalized to ETH and USD at the time of trades",
+		Query:    httputil.TimeRangeQuery{},
+		Response: []commission.Commission{},
+	}, s.getCommissions)
+	api.GET("/convert_to_eth_price", openapi.Endpoint{
+		Summary:  "ETH prices of binance convert trades",
+		Query:    getSpecialTradesQuery{},
+		Response: []binance.ConvertToETHPrice{},
+	}, s.getConvertToETHPrice)
 	// s.r.GET("/convert_cex_trades", s.getConvertCexTrades)
 	// s.r.GET("/convert_0x_trades", s.get0xConvertTrades)
-	s.r.GET("/convert_trades", s.getConvertTrades)
+	api.GET("/convert_trades", openapi.Endpoint{
+		Summary:  "0x convert trades matched with rebalance trades, ETH changes and PnL are net of commissions",
+		Query:    getSpecialTradesQuery{},
+		Response: []ConvertTrade{},
+	}, s.getConvertTrades)
 }
 
 // Run starts the HTTP server and runs in foreground until terminate by user.
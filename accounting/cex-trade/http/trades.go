@@ -11,8 +11,10 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	_ "github.com/KyberNetwork/reserve-stats/accounting/common/validators" // import custom validator functions
+	pnlstorage "github.com/KyberNetwork/reserve-stats/accounting/pnl/storage"
 	"github.com/KyberNetwork/reserve-stats/accounting/zerox"
 	"github.com/KyberNetwork/reserve-stats/lib/binance"
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
@@ -39,6 +41,10 @@ type getTradesQuery struct {
 type getTradesResponse struct {
 	Huobi   map[string][]huobi.TradeHistory   `json:"huobi,omitempty"`
 	Binance map[string][]binance.TradeHistory `json:"binance,omitempty"`
+	// BinanceFutures is the USDⓈ-M futures trades of binance accounts, keyed by account.
+	BinanceFutures map[string][]binance.FuturesTradeHistory `json:"binance_futures,omitempty"`
+	// Exchanges is the trades of exchanges with registered adapters, keyed by exchange and account.
+	Exchanges map[string]map[string][]cex.Trade `json:"exchanges,omitempty"`
 }
 
 // getTrades returns list of trades from centralized exchanges.
@@ -48,6 +54,8 @@ func (s *Server) getTrades(c *gin.Context) {
 		query         getTradesQuery
 		huobiTrades   = make(map[string][]huobi.TradeHistory)
 		binanceTrades = make(map[string][]binance.TradeHistory) // map account with its trades
+		futuresTrades = make(map[string][]binance.FuturesTradeHistory)
+		cexTrades     = make(map[string]map[string][]cex.Trade)
 	)
 
 	if err := c.ShouldBindQuery(&query); err != nil {
@@ -64,6 +72,9 @@ func (s *Server) getTrades(c *gin.Context) {
 		query.Exchanges = []string{
 			common.Huobi.String(),
 			common.Binance.String()}
+		if s.cs != nil {
+			query.Exchanges = append(query.Exchanges, cex.Names()...)
+		}
 	}
 
 	fromTime, toTime, err := query.Validate(
@@ -84,8 +95,8 @@ func (s *Server) getTrades(c *gin.Context) {
 	logger = logger.With("from", fromTime, "to", toTime, "exchanges", query.Exchanges)
 	logger.Debug("querying trades from database")
 
-	for _, cex := range query.Exchanges {
-		switch cex {
+	for _, exchange := range query.Exchanges {
+		switch exchange {
 		case common.Huobi.String():
 			huobiTrades, err = s.hs.GetTradeHistory(fromTime, toTime)
 			if err != nil {
@@ -121,12 +132,41 @@ func (s *Server) getTrades(c *gin.Context) {
 			for account := range binanceMarginTrades {
 				binanceTrades[account] = append(binanceTrades[account], binanceMarginTrades[account]...) // append margin trades into spot trades
 			}
+			futuresTrades, err = s.bs.GetFuturesTradeHistory(fromTime, toTime)
+			if err != nil {
+				s.sugar.Errorw("failed to get binance futures trade history", "error", err)
+				httputil.ResponseFailure(
+					c,
+					http.StatusInternalServerError,
+					err,
+				)
+				return
+			}
+		default:
+			if s.cs == nil || !cex.IsRegistered(exchange) {
+				continue
+			}
+			trades, err := s.cs.GetTrades(exchange, fromTime, toTime)
+			if err != nil {
+				s.sugar.Errorw("failed to get trade history", "exchange", exchange, "error", err)
+				httputil.ResponseFailure(
+					c,
+					http.StatusInternalServerError,
+					err,
+				)
+				return
+			}
+			if len(trades) != 0 {
+				cexTrades[exchange] = trades
+			}
 		}
 	}
 
 	c.JSON(http.StatusOK, getTradesResponse{
-		Huobi:   huobiTrades,
-		Binance: binanceTrades,
+		Huobi:          huobiTrades,
+		Binance:        binanceTrades,
+		BinanceFutures: futuresTrades,
+		Exchanges:      cexTrades,
 	})
 }
 
@@ -301,6 +341,16 @@ func (s *Server) getConvertTrades(c *gin.Context) {
 		)
 		return
 	}
+	fees, err := s.binanceFees(fromTime, toTime)
+	if err != nil {
+		s.sugar.Errorw("failed to get commissions", "error", err)
+		httputil.ResponseFailure(
+			c,
+			http.StatusInternalServerError,
+			err,
+		)
+		return
+	}
 	convertTrades := []ConvertTrade{}
 	var (
 		ethChange, tokenChange float64
@@ -341,7 +391,7 @@ func (s *Server) getConvertTrades(c *gin.Context) {
 						tokenChange = inTokenAmount
 					}
 				}
-				convertTrades = append(convertTrades, ConvertTrade{
+				convertTrade := ConvertTrade{
 					AccountName: accountName,
 					Timestamp:   int64(t.Time),
 					Pair:        t.Symbol,
@@ -350,14 +400,16 @@ func (s *Server) getConvertTrades(c *gin.Context) {
 					ETHChange:   ethChange,
 					TokenChange: tokenChange,
 					Qty:         qty,
-				})
+				}
+				setFee(&convertTrade, fees[[2]string{accountName, pnlstorage.BinanceTradeID(t.Symbol, t.ID)}])
+				convertTrades = append(convertTrades, convertTrade)
 				continue
 			}
 		}
 	}
 	response = append(response, convertTrades...)
 	for _, trade := range result {
-		r := s.processBinanceConvertTrade(trade, originalTrades)
+		r := s.processBinanceConvertTrade(trade, originalTrades, fees)
 		response = append(response, r...)
 	}
 
@@ -387,7 +439,8 @@ func (s *Server) getConvertTrades(c *gin.Context) {
 	)
 }
 
-func (s *Server) processBinanceConvertTrade(trade zerox.ConvertTradeInfo, originalTrades map[string][]binance.TradeHistory) []ConvertTrade {
+func (s *Server) processBinanceConvertTrade(trade zerox.ConvertTradeInfo, originalTrades map[string][]binance.TradeHistory,
+	fees map[[2]string][2]float64) []ConvertTrade {
 	var (
 		result       = []ConvertTrade{}
 		ethAmount    float64
@@ -398,7 +451,7 @@ func (s *Server) processBinanceConvertTrade(trade zerox.ConvertTradeInfo, origin
 	regexpString := fmt.Sprintf(".*(%s)$", strings.Join(quoteString, "|"))
 	re := regexp.MustCompile(regexpString)
 
-	for _, oTrades := range originalTrades {
+	for accountName, oTrades := range originalTrades {
 		for _, t := range oTrades {
 			if t.Time == uint64(trade.Timestamp) {
 				// find eth amount
@@ -420,7 +473,7 @@ func (s *Server) processBinanceConvertTrade(trade zerox.ConvertTradeInfo, origin
 					symbol, side, rate = convertRateToBinance(ethAmount, inTokenAmount, eth, inToken)
 				}
 				tradeType, ethChange, tokenChange := getAmountAndType(symbol, side, ethAmount, inTokenAmount)
-				result = append(result, ConvertTrade{
+				convertTrade := ConvertTrade{
 					AccountName: trade.AccountName, //
 					Timestamp:   trade.Timestamp,
 					Pair:        symbol,
@@ -429,8 +482,9 @@ func (s *Server) processBinanceConvertTrade(trade zerox.ConvertTradeInfo, origin
 					ETHChange:   ethChange,
 					TokenChange: tokenChange,
 					Qty:         inTokenAmount,
-				},
-				)
+				}
+				setFee(&convertTrade, fees[[2]string{accountName, pnlstorage.BinanceTradeID(t.Symbol, t.ID)}])
+				result = append(result, convertTrade)
 			}
 		}
 	}
@@ -467,6 +521,10 @@ func (s *Server) detectPricingGood(pair string, onchainTrades, rebalanceTrades [
 				pnlRate = onchainAVGPrice / rebalanceAVGPrice
 			}
 		}
+		if pnlRate != 0 {
+			// commission of rebalance trades is a cost of the round trip
+			pnlRate -= feeRate(rebalanceTrades)
+		}
 	} else {
 		pnlRate = 0
 	}
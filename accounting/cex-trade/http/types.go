@@ -7,11 +7,13 @@ type ConvertTrade struct {
 	AccountName  string  `json:"account_name"`
 	Pair         string  `json:"pair"`
 	Type         string  `json:"type"`
-	Qty          float64 `json:"qty"` // amount of base token
-	ETHChange    float64 `json:"eth_change"`
+	Qty          float64 `json:"qty"`        // amount of base token
+	ETHChange    float64 `json:"eth_change"` // net of commission
+	FeeETH       float64 `json:"fee_eth"`    // commission of rebalance trades
+	FeeUSD       float64 `json:"fee_usd"`
 	TokenChange  float64 `json:"token_change"`
 	Hash         string  `json:"hash"`
 	TakerAddress string  `json:"taker_address"`
 	PricingGood  bool    `json:"pricing_good"`
-	PnLBPS       float64 `json:"pnl_bps"`
+	PnLBPS       float64 `json:"pnl_bps"` // net of commission of rebalance trades
 }
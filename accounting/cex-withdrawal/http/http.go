@@ -15,6 +15,7 @@ import (
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
 	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 const (
@@ -24,11 +25,12 @@ const (
 
 // Server is the engine to serve cex-trade-withdrawal API query
 type Server struct {
-	r         *gin.Engine
-	huobiDB   huobiStorage.Interface
-	binanceDB withdrawalstorage.Interface
-	host      string
-	sugar     *zap.SugaredLogger
+	r              *gin.Engine
+	huobiDB        huobiStorage.Interface
+	binanceDB      withdrawalstorage.Interface
+	host           string
+	sugar          *zap.SugaredLogger
+	openAPIOptions []openapi.Option
 }
 
 type queryInput struct {
@@ -159,7 +161,12 @@ func (sv *Server) get(c *gin.Context) {
 }
 
 func (sv *Server) register() {
-	sv.r.GET("/withdrawals", sv.get)
+	api := openapi.NewRouter(sv.r, openapi.NewSpec("cex withdrawals"), sv.openAPIOptions...)
+	api.GET("/withdrawals", openapi.Endpoint{
+		Summary:  "withdrawals of centralized exchanges, keyed by account",
+		Query:    queryInput{},
+		Response: response{},
+	}, sv.get)
 }
 
 // Run starts HTTP server on preconfigure-host. Return error if occurs
@@ -169,13 +176,14 @@ func (sv *Server) Run() error {
 }
 
 // NewServer create an instance of Server to serve API query
-func NewServer(host string, huobiDB huobiStorage.Interface, binanceDB withdrawalstorage.Interface, sugar *zap.SugaredLogger) (*Server, error) {
+func NewServer(host string, huobiDB huobiStorage.Interface, binanceDB withdrawalstorage.Interface, sugar *zap.SugaredLogger, openAPIOptions ...openapi.Option) (*Server, error) {
 	r := gin.Default()
 	return &Server{
-		r:         r,
-		huobiDB:   huobiDB,
-		binanceDB: binanceDB,
-		host:      host,
-		sugar:     sugar,
+		r:              r,
+		huobiDB:        huobiDB,
+		binanceDB:      binanceDB,
+		host:           host,
+		sugar:          sugar,
+		openAPIOptions: openAPIOptions,
 	}, nil
 }
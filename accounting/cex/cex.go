@@ -0,0 +1,157 @@
+// Package cex is the framework of centralized exchange adapters. An adapter fetches trades, deposits,
+// withdrawals and balances of an exchange account and normalizes them, so they are stored in the generic
+// storage keyed by exchange and account.
+//
+// Adapters are registered by name in init functions of their packages, an exchange is supported by
+// importing its adapter package for side effects:
+//
+//	import _ "github.com/KyberNetwork/reserve-stats/accounting/cex/okx"
+package cex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// ErrNotSupported is returned by adapters for the records the exchange does not provide.
+var ErrNotSupported = errors.New("not supported by exchange")
+
+// Adapter fetches the records of an exchange account. The records are returned in ascending order of
+// timestamp, only the ones of timestamp in [from, to) are returned.
+type Adapter interface {
+	Trades(ctx context.Context, from, to time.Time) ([]Trade, error)
+	Deposits(ctx context.Context, from, to time.Time) ([]Transfer, error)
+	Withdrawals(ctx context.Context, from, to time.Time) ([]Transfer, error)
+	Balances(ctx context.Context) ([]Balance, error)
+}
+
+// Side is the side of a trade.
+type Side string
+
+const (
+	// Buy is a trade buying the base asset.
+	Buy Side = "buy"
+	// Sell is a trade selling the base asset.
+	Sell Side = "sell"
+)
+
+// Trade is a normalized trade of an exchange. Amounts are kept as the decimal strings returned by exchange.
+type Trade struct {
+	ID         string          `json:"id"`
+	OrderID    string          `json:"order_id"`
+	Symbol     string          `json:"symbol"`
+	BaseAsset  string          `json:"base_asset"`
+	QuoteAsset string          `json:"quote_asset"`
+	Side       Side            `json:"side"`
+	Price      string          `json:"price"`
+	Quantity   string          `json:"quantity"`
+	Fee        string          `json:"fee"`
+	FeeAsset   string          `json:"fee_asset"`
+	Timestamp  time.Time       `json:"timestamp"`
+	Raw        json.RawMessage `json:"raw,omitempty"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Trade to format timestamp in unix millis.
+func (t Trade) MarshalJSON() ([]byte, error) {
+	type AliasTrade Trade
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasTrade
+	}{
+		AliasTrade: (AliasTrade)(t),
+		Timestamp:  timeutil.TimeToTimestampMs(t.Timestamp),
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaler for Trade to parse timestamp in unix millis.
+func (t *Trade) UnmarshalJSON(data []byte) error {
+	type AliasTrade Trade
+	decoded := struct {
+		Timestamp uint64 `json:"timestamp"`
+		*AliasTrade
+	}{
+		AliasTrade: (*AliasTrade)(t),
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	t.Timestamp = timeutil.TimestampMsToTime(decoded.Timestamp)
+	return nil
+}
+
+// TransferStatus is the normalized status of a transfer.
+type TransferStatus string
+
+const (
+	// TransferPending is the status of transfers in progress.
+	TransferPending TransferStatus = "pending"
+	// TransferSuccess is the status of completed transfers.
+	TransferSuccess TransferStatus = "success"
+	// TransferFailed is the status of failed or canceled transfers.
+	TransferFailed TransferStatus = "failed"
+)
+
+// Transfer is a normalized deposit to or withdrawal from an exchange account.
+type Transfer struct {
+	ID        string          `json:"id"`
+	Asset     string          `json:"asset"`
+	Network   string          `json:"network"`
+	Amount    string          `json:"amount"`
+	Fee       string          `json:"fee"`
+	Address   string          `json:"address"`
+	TxID      string          `json:"tx_id"`
+	Status    TransferStatus  `json:"status"`
+	Timestamp time.Time       `json:"timestamp"`
+	Raw       json.RawMessage `json:"raw,omitempty"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Transfer to format timestamp in unix millis.
+func (t Transfer) MarshalJSON() ([]byte, error) {
+	type AliasTransfer Transfer
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasTransfer
+	}{
+		AliasTransfer: (AliasTransfer)(t),
+		Timestamp:     timeutil.TimeToTimestampMs(t.Timestamp),
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaler for Transfer to parse timestamp in unix millis.
+func (t *Transfer) UnmarshalJSON(data []byte) error {
+	type AliasTransfer Transfer
+	decoded := struct {
+		Timestamp uint64 `json:"timestamp"`
+		*AliasTransfer
+	}{
+		AliasTransfer: (*AliasTransfer)(t),
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	t.Timestamp = timeutil.TimestampMsToTime(decoded.Timestamp)
+	return nil
+}
+
+// Wallet is the wallet of an exchange account a balance is held in.
+type Wallet string
+
+const (
+	// WalletSpot is the spot trading wallet.
+	WalletSpot Wallet = "spot"
+	// WalletMargin is the margin trading wallet.
+	WalletMargin Wallet = "margin"
+)
+
+// Balance is the balance of an asset in an exchange account.
+type Balance struct {
+	Wallet   Wallet `json:"wallet"`
+	Asset    string `json:"asset"`
+	Free     string `json:"free"`
+	Locked   string `json:"locked"`
+	Borrowed string `json:"borrowed,omitempty"`
+}
@@ -0,0 +1,76 @@
+package cex
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+type mockAdapter struct {
+	account Account
+}
+
+func (m *mockAdapter) Trades(_ context.Context, _, _ time.Time) ([]Trade, error) {
+	return nil, ErrNotSupported
+}
+
+func (m *mockAdapter) Deposits(_ context.Context, _, _ time.Time) ([]Transfer, error) {
+	return nil, ErrNotSupported
+}
+
+func (m *mockAdapter) Withdrawals(_ context.Context, _, _ time.Time) ([]Transfer, error) {
+	return nil, ErrNotSupported
+}
+
+func (m *mockAdapter) Balances(_ context.Context) ([]Balance, error) {
+	return nil, ErrNotSupported
+}
+
+func TestRegistry(t *testing.T) {
+	sugar := testutil.MustNewDevelopmentSugaredLogger()
+	Register("mock", func(_ *zap.SugaredLogger, account Account) (Adapter, error) {
+		return &mockAdapter{account: account}, nil
+	})
+	assert.True(t, IsRegistered("mock"))
+	assert.False(t, IsRegistered("unknown"))
+	assert.Contains(t, Names(), "mock")
+	assert.Panics(t, func() {
+		Register("mock", func(_ *zap.SugaredLogger, _ Account) (Adapter, error) { return nil, nil })
+	})
+
+	adapter, err := New(sugar, Account{Exchange: "mock", Name: "mock_1"})
+	require.NoError(t, err)
+	assert.Equal(t, "mock_1", adapter.(*mockAdapter).account.Name)
+
+	_, err = New(sugar, Account{Exchange: "unknown", Name: "unknown_1"})
+	assert.Error(t, err)
+}
+
+func TestTradeJSON(t *testing.T) {
+	trade := Trade{
+		ID:        "1",
+		Symbol:    "KNC-ETH",
+		Side:      Buy,
+		Price:     "0.001",
+		Quantity:  "100",
+		Timestamp: timeutil.TimestampMsToTime(1696118700120),
+		Raw:       json.RawMessage(`{"id":1}`),
+	}
+	data, err := json.Marshal(trade)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"timestamp":1696118700120`)
+
+	var decoded Trade
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, trade.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, trade.ID, decoded.ID)
+	assert.JSONEq(t, string(trade.Raw), string(decoded.Raw))
+}
@@ -0,0 +1,158 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/accounting/cex/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// Fetcher fetches the records of exchange accounts with their adapters and stores them.
+type Fetcher struct {
+	sugar         *zap.SugaredLogger
+	storage       storage.Interface
+	retryDelay    time.Duration
+	attempt       int
+	batchDuration time.Duration
+}
+
+// NewFetcher returns a fetcher object. Records are fetched in batches of batchDuration to reduce
+// memory footprint, a batch is tried attempt times, at least once.
+func NewFetcher(sugar *zap.SugaredLogger, st storage.Interface, retryDelay time.Duration, attempt int, batchDuration time.Duration) *Fetcher {
+	return &Fetcher{
+		sugar:         sugar,
+		storage:       st,
+		retryDelay:    retryDelay,
+		attempt:       attempt,
+		batchDuration: batchDuration,
+	}
+}
+
+func (f *Fetcher) retry(ctx context.Context, logger *zap.SugaredLogger, fn func() error) error {
+	var err error
+	for i := 0; ; i++ {
+		if err = fn(); err == nil || errors.Is(err, cex.ErrNotSupported) {
+			return err
+		}
+		logger.Warnw("failed to fetch records", "error", err, "attempt", i+1)
+		if i+1 >= f.attempt {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(f.retryDelay):
+		}
+	}
+}
+
+// records are the fetch and store functions of a kind of record.
+type records struct {
+	name  string
+	last  func(exchange, account string) (time.Time, error)
+	fetch func(ctx context.Context, from, to time.Time) error
+}
+
+// Fetch fetches the trades, deposits and withdrawals of account in [from, to) and takes a snapshot of its
+// balances. If from is zero, records are fetched from the last stored one or defaultFrom if there is none.
+// Records not supported by the exchange are skipped.
+func (f *Fetcher) Fetch(ctx context.Context, account cex.Account, adapter cex.Adapter, from, defaultFrom, to time.Time) error {
+	var (
+		logger = f.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"exchange", account.Exchange,
+			"account", account.Name,
+		)
+		exchange, name = account.Exchange, account.Name
+	)
+	kinds := []records{
+		{
+			name: "trades",
+			last: f.storage.GetLastTradeTimestamp,
+			fetch: func(ctx context.Context, from, to time.Time) error {
+				trades, err := adapter.Trades(ctx, from, to)
+				if err != nil {
+					return err
+				}
+				return f.storage.UpdateTrades(exchange, name, trades)
+			},
+		},
+		{
+			name: "deposits",
+			last: f.storage.GetLastDepositTimestamp,
+			fetch: func(ctx context.Context, from, to time.Time) error {
+				deposits, err := adapter.Deposits(ctx, from, to)
+				if err != nil {
+					return err
+				}
+				return f.storage.UpdateDeposits(exchange, name, deposits)
+			},
+		},
+		{
+			name: "withdrawals",
+			last: f.storage.GetLastWithdrawalTimestamp,
+			fetch: func(ctx context.Context, from, to time.Time) error {
+				withdrawals, err := adapter.Withdrawals(ctx, from, to)
+				if err != nil {
+					return err
+				}
+				return f.storage.UpdateWithdrawals(exchange, name, withdrawals)
+			},
+		},
+	}
+
+	for _, kind := range kinds {
+		kindLogger := logger.With("records", kind.name)
+		start := from
+		if start.IsZero() {
+			last, err := kind.last(exchange, name)
+			if err != nil {
+				return err
+			}
+			// the last record is fetched again as others of the same timestamp might not be stored yet
+			start = last
+			if start.IsZero() {
+				start = defaultFrom
+			}
+		}
+		kindLogger.Infow("fetching records", "from", start, "to", to)
+		for start.Before(to) {
+			next := start.Add(f.batchDuration)
+			if to.Before(next) {
+				next = to
+			}
+			batchFrom := start
+			err := f.retry(ctx, kindLogger, func() error {
+				return kind.fetch(ctx, batchFrom, next)
+			})
+			if errors.Is(err, cex.ErrNotSupported) {
+				kindLogger.Infow("records are not supported by exchange")
+				break
+			}
+			if err != nil {
+				return err
+			}
+			start = next
+		}
+	}
+
+	var balances []cex.Balance
+	timestamp := time.Now()
+	err := f.retry(ctx, logger, func() (err error) {
+		balances, err = adapter.Balances(ctx)
+		return err
+	})
+	switch {
+	case errors.Is(err, cex.ErrNotSupported):
+		logger.Info("balances are not supported by exchange")
+		return nil
+	case err != nil:
+		return err
+	}
+	return f.storage.UpdateBalances(exchange, name, timestamp, balances)
+}
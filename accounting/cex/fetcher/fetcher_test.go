@@ -0,0 +1,148 @@
+package fetcher
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+)
+
+type timeRange struct {
+	from, to time.Time
+}
+
+// mockAdapter returns a trade at the start of every requested time range, deposits and withdrawals are
+// not supported. The first trades request fails.
+type mockAdapter struct {
+	tradeRequests []timeRange
+	failed        bool
+}
+
+func (m *mockAdapter) Trades(_ context.Context, from, to time.Time) ([]cex.Trade, error) {
+	if !m.failed {
+		m.failed = true
+		return nil, errors.New("temporary failure")
+	}
+	m.tradeRequests = append(m.tradeRequests, timeRange{from: from, to: to})
+	return []cex.Trade{{ID: from.String(), Timestamp: from}}, nil
+}
+
+func (m *mockAdapter) Deposits(_ context.Context, _, _ time.Time) ([]cex.Transfer, error) {
+	return nil, cex.ErrNotSupported
+}
+
+func (m *mockAdapter) Withdrawals(_ context.Context, _, _ time.Time) ([]cex.Transfer, error) {
+	return nil, cex.ErrNotSupported
+}
+
+func (m *mockAdapter) Balances(_ context.Context) ([]cex.Balance, error) {
+	return []cex.Balance{{Asset: "ETH", Free: "1", Locked: "0"}}, nil
+}
+
+// mockStorage stores trades and balances in memory.
+type mockStorage struct {
+	trades   []cex.Trade
+	balances []cex.Balance
+}
+
+func (m *mockStorage) UpdateTrades(_, _ string, trades []cex.Trade) error {
+	m.trades = append(m.trades, trades...)
+	return nil
+}
+
+func (m *mockStorage) GetTrades(_ string, _, _ time.Time) (map[string][]cex.Trade, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) GetLastTradeTimestamp(_, _ string) (time.Time, error) {
+	if len(m.trades) == 0 {
+		return time.Time{}, nil
+	}
+	return m.trades[len(m.trades)-1].Timestamp, nil
+}
+
+func (m *mockStorage) UpdateDeposits(_, _ string, _ []cex.Transfer) error {
+	return errors.New("unexpected deposits")
+}
+
+func (m *mockStorage) GetDeposits(_ string, _, _ time.Time) (map[string][]cex.Transfer, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) GetLastDepositTimestamp(_, _ string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (m *mockStorage) UpdateWithdrawals(_, _ string, _ []cex.Transfer) error {
+	return errors.New("unexpected withdrawals")
+}
+
+func (m *mockStorage) GetWithdrawals(_ string, _, _ time.Time) (map[string][]cex.Transfer, error) {
+	return nil, nil
+}
+
+func (m *mockStorage) GetLastWithdrawalTimestamp(_, _ string) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func (m *mockStorage) UpdateBalances(_, _ string, _ time.Time, balances []cex.Balance) error {
+	m.balances = balances
+	return nil
+}
+
+func (m *mockStorage) GetBalances(_ string, _ time.Time) (map[string][]cex.Balance, error) {
+	return nil, nil
+}
+
+func TestFetch(t *testing.T) {
+	var (
+		sugar       = testutil.MustNewDevelopmentSugaredLogger()
+		st          = &mockStorage{}
+		adapter     = &mockAdapter{}
+		account     = cex.Account{Exchange: "mock", Name: "mock_1"}
+		defaultFrom = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+		to          = defaultFrom.Add(60 * time.Hour)
+	)
+	f := NewFetcher(sugar, st, time.Millisecond, 2, 24*time.Hour)
+
+	require.NoError(t, f.Fetch(context.Background(), account, adapter, time.Time{}, defaultFrom, to))
+	assert.Equal(t, []timeRange{
+		{from: defaultFrom, to: defaultFrom.Add(24 * time.Hour)},
+		{from: defaultFrom.Add(24 * time.Hour), to: defaultFrom.Add(48 * time.Hour)},
+		{from: defaultFrom.Add(48 * time.Hour), to: to},
+	}, adapter.tradeRequests)
+	assert.Len(t, st.trades, 3)
+	assert.Equal(t, []cex.Balance{{Asset: "ETH", Free: "1", Locked: "0"}}, st.balances)
+
+	// the next fetch starts from the last stored trade
+	adapter.tradeRequests = nil
+	require.NoError(t, f.Fetch(context.Background(), account, adapter, time.Time{}, defaultFrom, to))
+	assert.Equal(t, []timeRange{{from: defaultFrom.Add(48 * time.Hour), to: to}}, adapter.tradeRequests)
+}
+
+func TestRetry(t *testing.T) {
+	var (
+		sugar  = testutil.MustNewDevelopmentSugaredLogger()
+		errFn  = errors.New("failure")
+		called int
+		fn     = func() error {
+			called++
+			return errFn
+		}
+	)
+	// fn is called at least once even if no attempt is configured
+	f := NewFetcher(sugar, &mockStorage{}, time.Millisecond, 0, 24*time.Hour)
+	assert.Equal(t, errFn, f.retry(context.Background(), sugar, fn))
+	assert.Equal(t, 1, called)
+
+	called = 0
+	f = NewFetcher(sugar, &mockStorage{}, time.Millisecond, 3, 24*time.Hour)
+	assert.Equal(t, errFn, f.retry(context.Background(), sugar, fn))
+	assert.Equal(t, 3, called)
+}
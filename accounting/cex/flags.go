@@ -0,0 +1,58 @@
+package cex
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/urfave/cli"
+)
+
+const cexAccountConfigFileFlag = "cex-account-config-file"
+
+// Account is the configuration of an exchange account.
+type Account struct {
+	// Exchange is the name the adapter of exchange is registered with.
+	Exchange   string `json:"exchange"`
+	Name       string `json:"name"`
+	APIKey     string `json:"api_key"`
+	SecretKey  string `json:"secret_key"`
+	Passphrase string `json:"passphrase,omitempty"`
+	// BaseURL overrides the default API endpoint of exchange.
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+// NewCliFlags returns cli flags to configure exchange accounts.
+func NewCliFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   cexAccountConfigFileFlag,
+			Usage:  "exchange account config file, a JSON list of accounts with exchange, name, api_key, secret_key, passphrase and base_url",
+			EnvVar: "CEX_ACCOUNT_CONFIG_FILE",
+		},
+	}
+}
+
+// AccountsFromContext gets accounts from the config file, accounts of unregistered exchanges are rejected.
+func AccountsFromContext(c *cli.Context) ([]Account, error) {
+	var accounts []Account
+	data, err := ioutil.ReadFile(c.String(cexAccountConfigFileFlag))
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &accounts); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, account := range accounts {
+		if !IsRegistered(account.Exchange) {
+			return nil, fmt.Errorf("account %s: unknown exchange %q", account.Name, account.Exchange)
+		}
+		key := account.Exchange + "/" + account.Name
+		if seen[key] {
+			return nil, fmt.Errorf("duplicated account %s of exchange %s", account.Name, account.Exchange)
+		}
+		seen[key] = true
+	}
+	return accounts, nil
+}
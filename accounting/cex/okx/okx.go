@@ -0,0 +1,163 @@
+// Package okx is the cex adapter of OKX, records are fetched from its v5 REST API.
+package okx
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	// Name is the exchange name the adapter is registered with.
+	Name = "okx"
+
+	defaultEndpoint = "https://www.okx.com"
+	// the lowest rate limit of used endpoints is 6 requests per second
+	defaultRateLimit = 5
+	defaultPageLimit = 100
+	successCode      = "0"
+	timestampLayout  = "2006-01-02T15:04:05.000Z"
+)
+
+func init() {
+	cex.Register(Name, func(sugar *zap.SugaredLogger, account cex.Account) (cex.Adapter, error) {
+		return NewClient(sugar, account)
+	})
+}
+
+// Client is the OKX adapter of an account.
+type Client struct {
+	sugar       *zap.SugaredLogger
+	client      *http.Client
+	endpoint    string
+	apiKey      string
+	secretKey   string
+	passphrase  string
+	rateLimiter *rate.Limiter
+	pageLimit   int
+}
+
+// Option configures the Client.
+type Option func(c *Client)
+
+// WithRateLimiter sets the rate limiter of requests.
+func WithRateLimiter(limiter *rate.Limiter) Option {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// NewClient creates the adapter of the account.
+func NewClient(sugar *zap.SugaredLogger, account cex.Account, options ...Option) (*Client, error) {
+	if len(account.APIKey) == 0 || len(account.SecretKey) == 0 || len(account.Passphrase) == 0 {
+		return nil, fmt.Errorf("okx account %s: api_key, secret_key and passphrase are required", account.Name)
+	}
+	endpoint := account.BaseURL
+	if len(endpoint) == 0 {
+		endpoint = defaultEndpoint
+	}
+	c := &Client{
+		sugar:       sugar,
+		client:      &http.Client{Timeout: 30 * time.Second},
+		endpoint:    endpoint,
+		apiKey:      account.APIKey,
+		secretKey:   account.SecretKey,
+		passphrase:  account.Passphrase,
+		rateLimiter: rate.NewLimiter(rate.Limit(defaultRateLimit), 1),
+		pageLimit:   defaultPageLimit,
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c, nil
+}
+
+// sign returns the signature of request, the prehash string is timestamp + method + request path + body.
+func (c *Client) sign(timestamp, method, requestPath string) string {
+	mac := hmac.New(sha256.New, []byte(c.secretKey))
+	// writing to hash never returns an error
+	_, _ = mac.Write([]byte(timestamp + method + requestPath))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type response struct {
+	Code    string          `json:"code"`
+	Message string          `json:"msg"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// get sends a signed GET request and decodes the data of response to result.
+func (c *Client) get(ctx context.Context, path string, params url.Values, result interface{}) error {
+	logger := c.sugar.With("func", caller.GetCurrentFunctionName(), "path", path, "params", params)
+
+	requestPath := path
+	if len(params) != 0 {
+		requestPath += "?" + params.Encode()
+	}
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+requestPath, nil)
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().UTC().Format(timestampLayout)
+	req.Header.Set("OK-ACCESS-KEY", c.apiKey)
+	req.Header.Set("OK-ACCESS-SIGN", c.sign(timestamp, http.MethodGet, requestPath))
+	req.Header.Set("OK-ACCESS-TIMESTAMP", timestamp)
+	req.Header.Set("OK-ACCESS-PASSPHRASE", c.passphrase)
+
+	logger.Debug("sending request")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := resp.Body.Close(); cErr != nil {
+			logger.Errorw("failed to close response body", "error", cErr)
+		}
+	}()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var r response
+	if err = json.Unmarshal(body, &r); err != nil {
+		return fmt.Errorf("unexpected response, status code: %d, body: %s", resp.StatusCode, body)
+	}
+	if resp.StatusCode != http.StatusOK || r.Code != successCode {
+		return fmt.Errorf("request failed, status code: %d, code: %s, message: %s", resp.StatusCode, r.Code, r.Message)
+	}
+	return json.Unmarshal(r.Data, result)
+}
+
+func millis(s string) (time.Time, error) {
+	ms, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", s, err)
+	}
+	return timeutil.TimestampMsToTime(ms), nil
+}
+
+func formatMillis(t time.Time) string {
+	return strconv.FormatUint(timeutil.TimeToTimestampMs(t), 10)
+}
+
+func inRange(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
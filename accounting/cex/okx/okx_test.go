@@ -0,0 +1,177 @@
+package okx
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+var testAccount = cex.Account{
+	Exchange:   Name,
+	Name:       "okx_main",
+	APIKey:     "test-api-key",
+	SecretKey:  "test-secret-key",
+	Passphrase: "test-passphrase",
+}
+
+// newFixtureServer serves the recorded responses of testdata, the first request of a path is responded
+// with the fixture and the next ones with the empty page.
+func newFixtureServer(t *testing.T) (*httptest.Server, map[string]int) {
+	fixtures := map[string]string{
+		fillsHistoryPath:      "fills-history.json",
+		depositHistoryPath:    "deposit-history.json",
+		withdrawalHistoryPath: "withdrawal-history.json",
+		balancePath:           "balance.json",
+	}
+	requests := make(map[string]int)
+	verifier := &Client{secretKey: testAccount.SecretKey}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, testAccount.APIKey, r.Header.Get("OK-ACCESS-KEY"))
+		assert.Equal(t, testAccount.Passphrase, r.Header.Get("OK-ACCESS-PASSPHRASE"))
+		expected := verifier.sign(r.Header.Get("OK-ACCESS-TIMESTAMP"), r.Method, r.URL.RequestURI())
+		if r.Header.Get("OK-ACCESS-SIGN") != expected {
+			http.ServeFile(w, r, filepath.Join("testdata", "error.json"))
+			return
+		}
+		fixture, ok := fixtures[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		requests[r.URL.Path]++
+		if requests[r.URL.Path] > 1 {
+			_, _ = w.Write([]byte(`{"code":"0","msg":"","data":[]}`))
+			return
+		}
+		data, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+		assert.NoError(t, err)
+		_, _ = w.Write(data)
+	}))
+	return ts, requests
+}
+
+func newTestClient(t *testing.T, endpoint string) *Client {
+	account := testAccount
+	account.BaseURL = endpoint
+	adapter, err := cex.New(testutil.MustNewDevelopmentSugaredLogger(), account)
+	require.NoError(t, err)
+	c, ok := adapter.(*Client)
+	require.True(t, ok)
+	return c
+}
+
+func TestClient(t *testing.T) {
+	ts, requests := newFixtureServer(t)
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	// responses are paginated if pages are full
+	c.pageLimit = 2
+
+	var (
+		ctx  = context.Background()
+		from = timeutil.TimestampMsToTime(1696110000000)
+		to   = timeutil.TimestampMsToTime(1696120000000)
+	)
+
+	trades, err := c.Trades(ctx, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests[fillsHistoryPath])
+	require.Len(t, trades, 3)
+	assert.Equal(t, "508911203", trades[0].ID)
+	assert.Equal(t, "508911201", trades[1].ID)
+	assert.Equal(t, cex.Trade{
+		ID:         "187362911",
+		OrderID:    "612438904772423680",
+		Symbol:     "KNC-USDT",
+		BaseAsset:  "KNC",
+		QuoteAsset: "USDT",
+		Side:       cex.Sell,
+		Price:      "1.0052",
+		Quantity:   "1500",
+		Fee:        "1.2078",
+		FeeAsset:   "USDT",
+		Timestamp:  timeutil.TimestampMsToTime(1696118700120),
+	}, withoutRaw(trades[2]))
+	// maker rebates are negative fees
+	assert.Equal(t, "-0.0001", trades[1].Fee)
+	assert.Contains(t, string(trades[0].Raw), `"execType": "T"`)
+
+	deposits, err := c.Deposits(ctx, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests[depositHistoryPath])
+	// the deposit after the time range is excluded
+	require.Len(t, deposits, 1)
+	assert.Equal(t, "97182119", deposits[0].ID)
+	assert.Equal(t, "ETH", deposits[0].Asset)
+	assert.Equal(t, "10.5", deposits[0].Amount)
+	assert.Equal(t, cex.TransferSuccess, deposits[0].Status)
+
+	withdrawals, err := c.Withdrawals(ctx, from, to)
+	require.NoError(t, err)
+	require.Len(t, withdrawals, 2)
+	assert.Equal(t, cex.Transfer{
+		ID:        "58213377",
+		Asset:     "KNC",
+		Network:   "KNC-ERC20",
+		Amount:    "1000",
+		Fee:       "4",
+		Address:   "0x2ecbe1b6ebf0c5ea7ec2ab2a5d2f2b6e7b4b6cf0",
+		Status:    cex.TransferFailed,
+		Timestamp: timeutil.TimestampMsToTime(1696110000000),
+	}, withoutRawTransfer(withdrawals[0]))
+	assert.Equal(t, cex.TransferSuccess, withdrawals[1].Status)
+	assert.Equal(t, "3.2", withdrawals[1].Fee)
+
+	balances, err := c.Balances(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []cex.Balance{
+		{Wallet: cex.WalletSpot, Asset: "ETH", Free: "123.456", Locked: "2"},
+		{Wallet: cex.WalletSpot, Asset: "KNC", Free: "40000.5", Locked: "0"},
+	}, balances)
+}
+
+func TestClientError(t *testing.T) {
+	ts, _ := newFixtureServer(t)
+	defer ts.Close()
+	c := newTestClient(t, ts.URL)
+	c.secretKey = "wrong-secret-key"
+
+	_, err := c.Balances(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid Sign")
+
+	_, err = NewClient(testutil.MustNewDevelopmentSugaredLogger(), cex.Account{Exchange: Name, Name: "no keys"})
+	assert.Error(t, err)
+}
+
+func TestNegate(t *testing.T) {
+	for fee, expected := range map[string]string{
+		"-1.5": "1.5",
+		"0.2":  "-0.2",
+		"0":    "0",
+		"0.00": "0.00",
+		"":     "",
+	} {
+		assert.Equal(t, expected, negate(fee), fee)
+	}
+}
+
+func withoutRaw(trade cex.Trade) cex.Trade {
+	trade.Raw = nil
+	return trade
+}
+
+func withoutRawTransfer(transfer cex.Transfer) cex.Transfer {
+	transfer.Raw = nil
+	return transfer
+}
@@ -0,0 +1,252 @@
+package okx
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	fillsHistoryPath      = "/api/v5/trade/fills-history"
+	depositHistoryPath    = "/api/v5/asset/deposit-history"
+	withdrawalHistoryPath = "/api/v5/asset/withdrawal-history"
+	balancePath           = "/api/v5/account/balance"
+)
+
+type fill struct {
+	InstrumentID string `json:"instId"`
+	TradeID      string `json:"tradeId"`
+	OrderID      string `json:"ordId"`
+	BillID       string `json:"billId"`
+	FillPrice    string `json:"fillPx"`
+	FillSize     string `json:"fillSz"`
+	Side         string `json:"side"`
+	Fee          string `json:"fee"`
+	FeeCurrency  string `json:"feeCcy"`
+	Timestamp    string `json:"ts"`
+}
+
+// negate returns the negation of a decimal string, OKX reports charged fees as negative numbers.
+func negate(s string) string {
+	switch {
+	case len(s) == 0:
+		return s
+	case strings.HasPrefix(s, "-"):
+		return s[1:]
+	case strings.Trim(s, "0.") == "":
+		return s
+	default:
+		return "-" + s
+	}
+}
+
+func (f fill) trade(raw json.RawMessage) (cex.Trade, error) {
+	timestamp, err := millis(f.Timestamp)
+	if err != nil {
+		return cex.Trade{}, err
+	}
+	base, quote := f.InstrumentID, ""
+	if i := strings.Index(f.InstrumentID, "-"); i >= 0 {
+		base, quote = f.InstrumentID[:i], f.InstrumentID[i+1:]
+	}
+	return cex.Trade{
+		ID:         f.TradeID,
+		OrderID:    f.OrderID,
+		Symbol:     f.InstrumentID,
+		BaseAsset:  base,
+		QuoteAsset: quote,
+		Side:       cex.Side(f.Side),
+		Price:      f.FillPrice,
+		Quantity:   f.FillSize,
+		Fee:        negate(f.Fee),
+		FeeAsset:   f.FeeCurrency,
+		Timestamp:  timestamp,
+		Raw:        raw,
+	}, nil
+}
+
+// Trades returns spot trades of the account, they are paginated by bill ID from the newest.
+func (c *Client) Trades(ctx context.Context, from, to time.Time) ([]cex.Trade, error) {
+	var trades []cex.Trade
+	params := url.Values{
+		"instType": {"SPOT"},
+		"begin":    {formatMillis(from)},
+		"end":      {formatMillis(to)},
+		"limit":    {strconv.Itoa(c.pageLimit)},
+	}
+	for {
+		var page []json.RawMessage
+		if err := c.get(ctx, fillsHistoryPath, params, &page); err != nil {
+			return nil, err
+		}
+		var lastBillID string
+		for _, raw := range page {
+			var f fill
+			if err := json.Unmarshal(raw, &f); err != nil {
+				return nil, err
+			}
+			lastBillID = f.BillID
+			trade, err := f.trade(raw)
+			if err != nil {
+				return nil, err
+			}
+			if inRange(trade.Timestamp, from, to) {
+				trades = append(trades, trade)
+			}
+		}
+		if len(page) < c.pageLimit {
+			break
+		}
+		params.Set("after", lastBillID)
+	}
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Timestamp.Before(trades[j].Timestamp)
+	})
+	return trades, nil
+}
+
+// transfer is a record of deposit or withdrawal history.
+type transfer struct {
+	DepositID    string `json:"depId"`
+	WithdrawalID string `json:"wdId"`
+	Currency     string `json:"ccy"`
+	Chain        string `json:"chain"`
+	Amount       string `json:"amt"`
+	Fee          string `json:"fee"`
+	To           string `json:"to"`
+	TxID         string `json:"txId"`
+	State        string `json:"state"`
+	Timestamp    string `json:"ts"`
+}
+
+var (
+	// depositStatuses maps deposit states to statuses, other states are failures like frozen accounts.
+	depositStatuses = map[string]cex.TransferStatus{
+		"0": cex.TransferPending, // waiting for confirmation
+		"1": cex.TransferPending, // credited but not withdrawable
+		"2": cex.TransferSuccess,
+		"8": cex.TransferPending, // temporary deposit suspension
+	}
+	// withdrawalStatuses maps withdrawal states to statuses, other states are waiting in progress or manual review.
+	withdrawalStatuses = map[string]cex.TransferStatus{
+		"-2": cex.TransferFailed, // canceled
+		"-1": cex.TransferFailed,
+		"2":  cex.TransferSuccess,
+	}
+)
+
+// transfers returns the deposits or withdrawals, they are paginated by timestamp from the newest.
+func (c *Client) transfers(ctx context.Context, path string, from, to time.Time, status func(state string) cex.TransferStatus) ([]cex.Transfer, error) {
+	var (
+		transfers []cex.Transfer
+		seen      = make(map[string]bool)
+		after     = timeutil.TimeToTimestampMs(to)
+	)
+	params := url.Values{
+		// before and after are exclusive
+		"before": {strconv.FormatUint(timeutil.TimeToTimestampMs(from)-1, 10)},
+		"limit":  {strconv.Itoa(c.pageLimit)},
+	}
+	for {
+		params.Set("after", strconv.FormatUint(after, 10))
+		var page []json.RawMessage
+		if err := c.get(ctx, path, params, &page); err != nil {
+			return nil, err
+		}
+		next := after
+		for _, raw := range page {
+			var r transfer
+			if err := json.Unmarshal(raw, &r); err != nil {
+				return nil, err
+			}
+			timestamp, err := millis(r.Timestamp)
+			if err != nil {
+				return nil, err
+			}
+			id := r.DepositID + r.WithdrawalID
+			if seen[id] || !inRange(timestamp, from, to) {
+				continue
+			}
+			seen[id] = true
+			transfers = append(transfers, cex.Transfer{
+				ID:        id,
+				Asset:     r.Currency,
+				Network:   r.Chain,
+				Amount:    r.Amount,
+				Fee:       r.Fee,
+				Address:   r.To,
+				TxID:      r.TxID,
+				Status:    status(r.State),
+				Timestamp: timestamp,
+				Raw:       raw,
+			})
+			// records of the same timestamp might be split across pages
+			next = timeutil.TimeToTimestampMs(timestamp) + 1
+		}
+		if len(page) < c.pageLimit || next >= after {
+			break
+		}
+		after = next
+	}
+	sort.SliceStable(transfers, func(i, j int) bool {
+		return transfers[i].Timestamp.Before(transfers[j].Timestamp)
+	})
+	return transfers, nil
+}
+
+// Deposits returns the deposits of the account.
+func (c *Client) Deposits(ctx context.Context, from, to time.Time) ([]cex.Transfer, error) {
+	return c.transfers(ctx, depositHistoryPath, from, to, func(state string) cex.TransferStatus {
+		if status, ok := depositStatuses[state]; ok {
+			return status
+		}
+		return cex.TransferFailed
+	})
+}
+
+// Withdrawals returns the withdrawals of the account.
+func (c *Client) Withdrawals(ctx context.Context, from, to time.Time) ([]cex.Transfer, error) {
+	return c.transfers(ctx, withdrawalHistoryPath, from, to, func(state string) cex.TransferStatus {
+		if status, ok := withdrawalStatuses[state]; ok {
+			return status
+		}
+		return cex.TransferPending
+	})
+}
+
+type accountBalance struct {
+	Details []struct {
+		Currency         string `json:"ccy"`
+		AvailableBalance string `json:"availBal"`
+		FrozenBalance    string `json:"frozenBal"`
+	} `json:"details"`
+}
+
+// Balances returns the balances of trading account.
+func (c *Client) Balances(ctx context.Context) ([]cex.Balance, error) {
+	var (
+		data     []accountBalance
+		balances []cex.Balance
+	)
+	if err := c.get(ctx, balancePath, nil, &data); err != nil {
+		return nil, err
+	}
+	for _, account := range data {
+		for _, detail := range account.Details {
+			balances = append(balances, cex.Balance{
+				Wallet: cex.WalletSpot,
+				Asset:  detail.Currency,
+				Free:   detail.AvailableBalance,
+				Locked: detail.FrozenBalance,
+			})
+		}
+	}
+	return balances, nil
+}
@@ -0,0 +1,62 @@
+package cex
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Factory creates the adapter of an account.
+type Factory func(sugar *zap.SugaredLogger, account Account) (Adapter, error)
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Register makes an adapter factory available by the exchange name. It panics if the factory is nil or
+// the name is registered twice.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if factory == nil {
+		panic("cex: Register factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic("cex: Register called twice for exchange " + name)
+	}
+	factories[name] = factory
+}
+
+// IsRegistered returns true if an adapter is registered with the exchange name.
+func IsRegistered(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := factories[name]
+	return ok
+}
+
+// Names returns the sorted names of registered exchanges.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	var names []string
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the adapter of the account with the factory registered for its exchange.
+func New(sugar *zap.SugaredLogger, account Account) (Adapter, error) {
+	mu.RLock()
+	factory, ok := factories[account.Exchange]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("cex: unknown exchange %q (forgotten import?)", account.Exchange)
+	}
+	return factory(sugar.With("exchange", account.Exchange, "account", account.Name), account)
+}
@@ -0,0 +1,127 @@
+// Package snapshot takes balance snapshots of Binance and Huobi accounts with their existing clients, the
+// snapshots are stored in cex storage, the same as ones of accounts with registered adapters.
+package snapshot
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+)
+
+const (
+	// huobi balance types, loan is the borrowed amount in margin accounts, it is negative
+	huobiTradeBalance  = "trade"
+	huobiFrozenBalance = "frozen"
+	huobiLoanBalance   = "loan"
+)
+
+// BinanceClient is the Binance client to get account balances.
+type BinanceClient interface {
+	GetAccountInfo() (binance.AccountInfo, error)
+	GetMarginAccount() (binance.MarginAccount, error)
+}
+
+// HuobiClient is the Huobi client to get account balances.
+type HuobiClient interface {
+	GetAccounts() ([]huobi.Account, error)
+	GetAccountBalance(accountID int) (huobi.AccountBalance, error)
+}
+
+// isZero returns true if all the amounts are zero.
+func isZero(amounts ...string) bool {
+	for _, amount := range amounts {
+		if amount == "" {
+			continue
+		}
+		if value, err := strconv.ParseFloat(amount, 64); err != nil || value != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// BinanceBalances returns the non zero balances of spot and cross margin wallets of a Binance account.
+func BinanceBalances(client BinanceClient) ([]cex.Balance, error) {
+	var result []cex.Balance
+	accountInfo, err := client.GetAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range accountInfo.Balances {
+		if isZero(b.Free, b.Locked) {
+			continue
+		}
+		result = append(result, cex.Balance{
+			Wallet: cex.WalletSpot,
+			Asset:  b.Asset,
+			Free:   b.Free,
+			Locked: b.Locked,
+		})
+	}
+
+	marginAccount, err := client.GetMarginAccount()
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range marginAccount.UserAssets {
+		if isZero(b.Free, b.Locked, b.Borrowed) {
+			continue
+		}
+		result = append(result, cex.Balance{
+			Wallet:   cex.WalletMargin,
+			Asset:    b.Asset,
+			Free:     b.Free,
+			Locked:   b.Locked,
+			Borrowed: b.Borrowed,
+		})
+	}
+	return result, nil
+}
+
+// HuobiBalances returns the non zero balances of all wallets of a Huobi account, the wallet is the Huobi
+// account type, e.g. spot, margin.
+func HuobiBalances(client HuobiClient) ([]cex.Balance, error) {
+	var result []cex.Balance
+	accounts, err := client.GetAccounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		accountBalance, err := client.GetAccountBalance(account.ID)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			assets   []string
+			balances = make(map[string]*cex.Balance)
+		)
+		for _, item := range accountBalance.List {
+			asset := strings.ToUpper(item.Currency)
+			b, ok := balances[asset]
+			if !ok {
+				b = &cex.Balance{Wallet: cex.Wallet(account.Type), Asset: asset, Free: "0", Locked: "0"}
+				balances[asset] = b
+				assets = append(assets, asset)
+			}
+			switch item.Type {
+			case huobiTradeBalance:
+				b.Free = item.Balance
+			case huobiFrozenBalance:
+				b.Locked = item.Balance
+			case huobiLoanBalance:
+				b.Borrowed = strings.TrimPrefix(item.Balance, "-")
+			}
+		}
+		for _, asset := range assets {
+			b := balances[asset]
+			if isZero(b.Free, b.Locked, b.Borrowed) {
+				continue
+			}
+			result = append(result, *b)
+		}
+	}
+	return result, nil
+}
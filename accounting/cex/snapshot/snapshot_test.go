@@ -0,0 +1,72 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+)
+
+type mockBinanceClient struct{}
+
+func (mockBinanceClient) GetAccountInfo() (binance.AccountInfo, error) {
+	return binance.AccountInfo{
+		Balances: []binance.Balance{
+			{Asset: "ETH", Free: "1.5", Locked: "0.5"},
+			{Asset: "BTC", Free: "0.00000000", Locked: "0.00000000"},
+		},
+	}, nil
+}
+
+func (mockBinanceClient) GetMarginAccount() (binance.MarginAccount, error) {
+	return binance.MarginAccount{
+		UserAssets: []binance.MarginAsset{
+			{Asset: "KNC", Free: "0.00000000", Locked: "0.00000000", Borrowed: "100.00000000"},
+			{Asset: "BTC", Free: "0.00000000", Locked: "0.00000000", Borrowed: "0.00000000"},
+		},
+	}, nil
+}
+
+type mockHuobiClient struct{}
+
+func (mockHuobiClient) GetAccounts() ([]huobi.Account, error) {
+	return []huobi.Account{{ID: 1, Type: "spot"}, {ID: 2, Type: "margin"}}, nil
+}
+
+func (mockHuobiClient) GetAccountBalance(accountID int) (huobi.AccountBalance, error) {
+	if accountID == 1 {
+		return huobi.AccountBalance{ID: 1, Type: "spot", List: []huobi.AccountBalanceItem{
+			{Currency: "eth", Type: "trade", Balance: "10.1"},
+			{Currency: "eth", Type: "frozen", Balance: "1"},
+			{Currency: "knc", Type: "trade", Balance: "0"},
+			{Currency: "knc", Type: "frozen", Balance: "0"},
+		}}, nil
+	}
+	return huobi.AccountBalance{ID: 2, Type: "margin", List: []huobi.AccountBalanceItem{
+		{Currency: "eth", Type: "trade", Balance: "2"},
+		{Currency: "eth", Type: "frozen", Balance: "0"},
+		{Currency: "eth", Type: "loan", Balance: "-1.5"},
+	}}, nil
+}
+
+func TestBinanceBalances(t *testing.T) {
+	balances, err := BinanceBalances(mockBinanceClient{})
+	require.NoError(t, err)
+	assert.Equal(t, []cex.Balance{
+		{Wallet: cex.WalletSpot, Asset: "ETH", Free: "1.5", Locked: "0.5"},
+		{Wallet: cex.WalletMargin, Asset: "KNC", Free: "0.00000000", Locked: "0.00000000", Borrowed: "100.00000000"},
+	}, balances)
+}
+
+func TestHuobiBalances(t *testing.T) {
+	balances, err := HuobiBalances(mockHuobiClient{})
+	require.NoError(t, err)
+	assert.Equal(t, []cex.Balance{
+		{Wallet: cex.WalletSpot, Asset: "ETH", Free: "10.1", Locked: "1"},
+		{Wallet: cex.WalletMargin, Asset: "ETH", Free: "2", Locked: "0", Borrowed: "1.5"},
+	}, balances)
+}
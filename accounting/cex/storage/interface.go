@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+)
+
+// Interface is the generic storage of exchange records, keyed by exchange and account. Get methods return
+// the records of timestamp in [from, to) keyed by account. GetLast methods return zero time if no record
+// of the account is stored.
+type Interface interface {
+	UpdateTrades(exchange, account string, trades []cex.Trade) error
+	GetTrades(exchange string, from, to time.Time) (map[string][]cex.Trade, error)
+	GetLastTradeTimestamp(exchange, account string) (time.Time, error)
+
+	UpdateDeposits(exchange, account string, deposits []cex.Transfer) error
+	GetDeposits(exchange string, from, to time.Time) (map[string][]cex.Transfer, error)
+	GetLastDepositTimestamp(exchange, account string) (time.Time, error)
+
+	UpdateWithdrawals(exchange, account string, withdrawals []cex.Transfer) error
+	GetWithdrawals(exchange string, from, to time.Time) (map[string][]cex.Transfer, error)
+	GetLastWithdrawalTimestamp(exchange, account string) (time.Time, error)
+
+	// UpdateBalances stores a snapshot of account balances taken at the timestamp, the snapshot is stored
+	// even if balances is empty.
+	UpdateBalances(exchange, account string, timestamp time.Time, balances []cex.Balance) error
+	// GetBalances returns the latest snapshot at or before the time of every account of exchange, accounts
+	// with an empty latest snapshot are returned with no balances.
+	GetBalances(exchange string, at time.Time) (map[string][]cex.Balance, error)
+}
@@ -0,0 +1,337 @@
+package postgres
+
+import (
+	"database/sql"
+	_ "embed" // embed database schema
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
+)
+
+const (
+	tradesTable      = "cex_trades"
+	depositsTable    = "cex_deposits"
+	withdrawalsTable = "cex_withdrawals"
+)
+
+//go:embed schema.sql
+var schema string
+
+// Storage is the PostgreSQL storage of exchange records. Trades, deposits and withdrawals are stored in
+// JSONB in tables of the same layout, so any exchange can be stored without schema changes.
+type Storage struct {
+	sugar *zap.SugaredLogger
+	db    *sqlx.DB
+}
+
+// NewStorage creates the storage and initializes the database schema.
+func NewStorage(sugar *zap.SugaredLogger, db *sqlx.DB) (*Storage, error) {
+	logger := sugar.With("func", caller.GetCurrentFunctionName())
+	logger.Debugw("initializing database schema", "query", schema)
+	if _, err := db.Exec(schema); err != nil {
+		return nil, err
+	}
+	return &Storage{sugar: sugar, db: db}, nil
+}
+
+// record is a row of the JSONB tables.
+type record struct {
+	id        string
+	timestamp time.Time
+	data      []byte
+}
+
+func (s *Storage) update(table, exchange, account string, records []record) (err error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"table", table,
+			"exchange", exchange,
+			"account", account,
+			"number of records", len(records),
+		)
+		ids        []string
+		timestamps []time.Time
+		data       [][]byte
+	)
+	if len(records) == 0 {
+		return nil
+	}
+	for _, r := range records {
+		ids = append(ids, r.id)
+		timestamps = append(timestamps, r.timestamp)
+		data = append(data, r.data)
+	}
+
+	// records are updated as the status of transfers changes after they are fetched
+	updateStmt := fmt.Sprintf(`INSERT INTO %[1]s (exchange, account, id, timestamp, data)
+	VALUES (
+		$1,
+		$2,
+		unnest($3::TEXT[]),
+		unnest($4::TIMESTAMPTZ[]),
+		unnest($5::JSONB[])
+	)
+	ON CONFLICT ON CONSTRAINT %[1]s_pk DO UPDATE SET timestamp = EXCLUDED.timestamp, data = EXCLUDED.data;`, table)
+	logger.Debugw("updating records", "query", updateStmt)
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+	_, err = tx.Exec(updateStmt, exchange, account, pq.StringArray(ids), pq.Array(timestamps), pq.Array(data))
+	return err
+}
+
+type recordsDB struct {
+	Account string        `db:"account"`
+	Data    pq.ByteaArray `db:"data"`
+}
+
+// get returns the JSON data of records in time range keyed by account, the records of an account are
+// sorted by timestamp.
+func (s *Storage) get(table, exchange string, from, to time.Time) ([]recordsDB, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"table", table,
+			"exchange", exchange,
+			"from", from,
+			"to", to,
+		)
+		dbResult []recordsDB
+	)
+	selectStmt := fmt.Sprintf(`SELECT account, ARRAY_AGG(data ORDER BY timestamp, id) AS data FROM %s
+	WHERE exchange = $1 AND timestamp >= $2 AND timestamp < $3 GROUP BY account;`, table)
+	logger.Debugw("querying records", "query", selectStmt)
+	if err := s.db.Select(&dbResult, selectStmt, exchange, from, to); err != nil {
+		return nil, err
+	}
+	return dbResult, nil
+}
+
+func (s *Storage) lastTimestamp(table, exchange, account string) (time.Time, error) {
+	var (
+		logger   = s.sugar.With("func", caller.GetCurrentFunctionName(), "table", table)
+		dbResult pq.NullTime
+	)
+	selectStmt := fmt.Sprintf(`SELECT MAX(timestamp) FROM %s WHERE exchange = $1 AND account = $2;`, table)
+	logger.Debugw("querying last stored timestamp", "query", selectStmt)
+	if err := s.db.Get(&dbResult, selectStmt, exchange, account); err != nil {
+		return time.Time{}, err
+	}
+	if !dbResult.Valid {
+		return time.Time{}, nil
+	}
+	return dbResult.Time, nil
+}
+
+// UpdateTrades stores the trades of account.
+func (s *Storage) UpdateTrades(exchange, account string, trades []cex.Trade) error {
+	var records []record
+	for _, trade := range trades {
+		data, err := json.Marshal(trade)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{id: trade.ID, timestamp: trade.Timestamp, data: data})
+	}
+	return s.update(tradesTable, exchange, account, records)
+}
+
+// GetTrades returns the trades of exchange in time range keyed by account.
+func (s *Storage) GetTrades(exchange string, from, to time.Time) (map[string][]cex.Trade, error) {
+	dbResult, err := s.get(tradesTable, exchange, from, to)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]cex.Trade)
+	for _, r := range dbResult {
+		trades := make([]cex.Trade, len(r.Data))
+		for i, data := range r.Data {
+			if err := json.Unmarshal(data, &trades[i]); err != nil {
+				return nil, err
+			}
+		}
+		result[r.Account] = trades
+	}
+	return result, nil
+}
+
+// GetLastTradeTimestamp returns the timestamp of the last stored trade of account.
+func (s *Storage) GetLastTradeTimestamp(exchange, account string) (time.Time, error) {
+	return s.lastTimestamp(tradesTable, exchange, account)
+}
+
+func (s *Storage) updateTransfers(table, exchange, account string, transfers []cex.Transfer) error {
+	var records []record
+	for _, transfer := range transfers {
+		data, err := json.Marshal(transfer)
+		if err != nil {
+			return err
+		}
+		records = append(records, record{id: transfer.ID, timestamp: transfer.Timestamp, data: data})
+	}
+	return s.update(table, exchange, account, records)
+}
+
+func (s *Storage) getTransfers(table, exchange string, from, to time.Time) (map[string][]cex.Transfer, error) {
+	dbResult, err := s.get(table, exchange, from, to)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]cex.Transfer)
+	for _, r := range dbResult {
+		transfers := make([]cex.Transfer, len(r.Data))
+		for i, data := range r.Data {
+			if err := json.Unmarshal(data, &transfers[i]); err != nil {
+				return nil, err
+			}
+		}
+		result[r.Account] = transfers
+	}
+	return result, nil
+}
+
+// UpdateDeposits stores the deposits of account.
+func (s *Storage) UpdateDeposits(exchange, account string, deposits []cex.Transfer) error {
+	return s.updateTransfers(depositsTable, exchange, account, deposits)
+}
+
+// GetDeposits returns the deposits of exchange in time range keyed by account.
+func (s *Storage) GetDeposits(exchange string, from, to time.Time) (map[string][]cex.Transfer, error) {
+	return s.getTransfers(depositsTable, exchange, from, to)
+}
+
+// GetLastDepositTimestamp returns the timestamp of the last stored deposit of account.
+func (s *Storage) GetLastDepositTimestamp(exchange, account string) (time.Time, error) {
+	return s.lastTimestamp(depositsTable, exchange, account)
+}
+
+// UpdateWithdrawals stores the withdrawals of account.
+func (s *Storage) UpdateWithdrawals(exchange, account string, withdrawals []cex.Transfer) error {
+	return s.updateTransfers(withdrawalsTable, exchange, account, withdrawals)
+}
+
+// GetWithdrawals returns the withdrawals of exchange in time range keyed by account.
+func (s *Storage) GetWithdrawals(exchange string, from, to time.Time) (map[string][]cex.Transfer, error) {
+	return s.getTransfers(withdrawalsTable, exchange, from, to)
+}
+
+// GetLastWithdrawalTimestamp returns the timestamp of the last stored withdrawal of account.
+func (s *Storage) GetLastWithdrawalTimestamp(exchange, account string) (time.Time, error) {
+	return s.lastTimestamp(withdrawalsTable, exchange, account)
+}
+
+// UpdateBalances stores a snapshot of account balances taken at the timestamp. The snapshot is stored even
+// if there is no balance, zero balances are not required to be stored.
+func (s *Storage) UpdateBalances(exchange, account string, timestamp time.Time, balances []cex.Balance) (err error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"exchange", exchange,
+			"account", account,
+			"timestamp", timestamp,
+		)
+		wallets, assets, free, locked, borrowed []string
+	)
+	for _, b := range balances {
+		wallet := b.Wallet
+		if wallet == "" {
+			wallet = cex.WalletSpot
+		}
+		wallets = append(wallets, string(wallet))
+		assets = append(assets, b.Asset)
+		free = append(free, b.Free)
+		locked = append(locked, b.Locked)
+		if b.Borrowed == "" {
+			borrowed = append(borrowed, "0")
+		} else {
+			borrowed = append(borrowed, b.Borrowed)
+		}
+	}
+	const snapshotStmt = `INSERT INTO cex_balance_snapshots (exchange, account, timestamp) VALUES ($1, $2, $3)
+	ON CONFLICT ON CONSTRAINT cex_balance_snapshots_pk DO NOTHING;`
+	const updateStmt = `INSERT INTO cex_balances (exchange, account, timestamp, wallet, asset, free, locked, borrowed)
+	VALUES (
+		$1,
+		$2,
+		$3,
+		unnest($4::TEXT[]),
+		unnest($5::TEXT[]),
+		unnest($6::NUMERIC[]),
+		unnest($7::NUMERIC[]),
+		unnest($8::NUMERIC[])
+	)
+	ON CONFLICT ON CONSTRAINT cex_balances_pk DO UPDATE SET free = EXCLUDED.free, locked = EXCLUDED.locked, borrowed = EXCLUDED.borrowed;`
+	logger.Debugw("updating balances", "query", updateStmt)
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+	if _, err = tx.Exec(snapshotStmt, exchange, account, timestamp); err != nil {
+		return err
+	}
+	if len(balances) == 0 {
+		return nil
+	}
+	_, err = tx.Exec(updateStmt, exchange, account, timestamp,
+		pq.StringArray(wallets), pq.StringArray(assets), pq.StringArray(free), pq.StringArray(locked), pq.StringArray(borrowed))
+	return err
+}
+
+// GetBalances returns the latest snapshot at or before the time of every account of exchange. Accounts
+// having no balance in their latest snapshot are returned with empty balances.
+func (s *Storage) GetBalances(exchange string, at time.Time) (map[string][]cex.Balance, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"exchange", exchange,
+			"at", at,
+		)
+		dbResult []struct {
+			Account  string         `db:"account"`
+			Wallet   sql.NullString `db:"wallet"`
+			Asset    sql.NullString `db:"asset"`
+			Free     sql.NullString `db:"free"`
+			Locked   sql.NullString `db:"locked"`
+			Borrowed sql.NullString `db:"borrowed"`
+		}
+	)
+	const selectStmt = `SELECT latest.account, b.wallet, b.asset, b.free, b.locked, b.borrowed
+	FROM (SELECT account, MAX(timestamp) AS timestamp FROM cex_balance_snapshots
+		WHERE exchange = $1 AND timestamp <= $2 GROUP BY account) AS latest
+	LEFT JOIN cex_balances AS b
+	ON b.exchange = $1 AND b.account = latest.account AND b.timestamp = latest.timestamp
+	ORDER BY latest.account, b.wallet, b.asset;`
+	logger.Debugw("querying balances", "query", selectStmt)
+	if err := s.db.Select(&dbResult, selectStmt, exchange, at); err != nil {
+		return nil, err
+	}
+	result := make(map[string][]cex.Balance)
+	for _, r := range dbResult {
+		if !r.Asset.Valid {
+			result[r.Account] = []cex.Balance{}
+			continue
+		}
+		result[r.Account] = append(result[r.Account], cex.Balance{
+			Wallet:   cex.Wallet(r.Wallet.String),
+			Asset:    r.Asset.String,
+			Free:     r.Free.String,
+			Locked:   r.Locked.String,
+			Borrowed: r.Borrowed.String,
+		})
+	}
+	return result, nil
+}
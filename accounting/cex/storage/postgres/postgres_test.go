@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq" // sql driver name: "postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func TestStorage(t *testing.T) {
+	const (
+		exchange = "okx"
+		account  = "okx_main"
+	)
+	sugar := testutil.MustNewDevelopmentSugaredLogger()
+	db, teardown := testutil.MustNewDevelopmentDB()
+	defer func() {
+		assert.NoError(t, teardown())
+	}()
+
+	s, err := NewStorage(sugar, db)
+	require.NoError(t, err)
+
+	last, err := s.GetLastTradeTimestamp(exchange, account)
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	trades := []cex.Trade{
+		{ID: "1", Symbol: "ETH-USDT", Side: cex.Buy, Price: "1671.4", Quantity: "0.5", Timestamp: timeutil.TimestampMsToTime(1696118678015)},
+		{ID: "2", Symbol: "KNC-USDT", Side: cex.Sell, Price: "1.0052", Quantity: "1500", Timestamp: timeutil.TimestampMsToTime(1696118700120)},
+	}
+	require.NoError(t, s.UpdateTrades(exchange, account, trades))
+	// updating twice does not duplicate trades
+	require.NoError(t, s.UpdateTrades(exchange, account, trades))
+
+	stored, err := s.GetTrades(exchange, trades[0].Timestamp, trades[1].Timestamp.Add(time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, stored[account], 2)
+	assert.Equal(t, "1", stored[account][0].ID)
+	assert.True(t, trades[1].Timestamp.Equal(stored[account][1].Timestamp))
+
+	stored, err = s.GetTrades("other", trades[0].Timestamp, trades[1].Timestamp.Add(time.Millisecond))
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+
+	last, err = s.GetLastTradeTimestamp(exchange, account)
+	require.NoError(t, err)
+	assert.True(t, trades[1].Timestamp.Equal(last))
+
+	withdrawal := cex.Transfer{ID: "58214403", Asset: "USDT", Amount: "5000", Status: cex.TransferPending, Timestamp: timeutil.TimestampMsToTime(1696117200000)}
+	require.NoError(t, s.UpdateWithdrawals(exchange, account, []cex.Transfer{withdrawal}))
+	// the status of stored transfer is updated
+	withdrawal.Status = cex.TransferSuccess
+	require.NoError(t, s.UpdateWithdrawals(exchange, account, []cex.Transfer{withdrawal}))
+	withdrawals, err := s.GetWithdrawals(exchange, withdrawal.Timestamp, withdrawal.Timestamp.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, withdrawals[account], 1)
+	assert.Equal(t, cex.TransferSuccess, withdrawals[account][0].Status)
+
+	deposits, err := s.GetDeposits(exchange, withdrawal.Timestamp, withdrawal.Timestamp.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, deposits)
+
+	snapshot := timeutil.TimestampMsToTime(1696118700000)
+	require.NoError(t, s.UpdateBalances(exchange, account, snapshot, []cex.Balance{
+		{Wallet: cex.WalletSpot, Asset: "ETH", Free: "123.456", Locked: "2"},
+		{Wallet: cex.WalletMargin, Asset: "ETH", Free: "10", Locked: "0", Borrowed: "5"},
+	}))
+	require.NoError(t, s.UpdateBalances(exchange, account, snapshot.Add(time.Hour), []cex.Balance{
+		{Asset: "ETH", Free: "100", Locked: "0"},
+	}))
+	balances, err := s.GetBalances(exchange, snapshot.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []cex.Balance{
+		{Wallet: cex.WalletMargin, Asset: "ETH", Free: "10", Locked: "0", Borrowed: "5"},
+		{Wallet: cex.WalletSpot, Asset: "ETH", Free: "123.456", Locked: "2", Borrowed: "0"},
+	}, balances[account])
+	balances, err = s.GetBalances(exchange, snapshot.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Empty(t, balances)
+
+	// drained account has an empty snapshot instead of the balances of the previous one
+	require.NoError(t, s.UpdateBalances(exchange, account, snapshot.Add(2*time.Hour), nil))
+	balances, err = s.GetBalances(exchange, snapshot.Add(3*time.Hour))
+	require.NoError(t, err)
+	require.Contains(t, balances, account)
+	assert.Empty(t, balances[account])
+}
@@ -0,0 +1,72 @@
+CREATE TABLE IF NOT EXISTS cex_trades
+(
+    exchange  TEXT        NOT NULL,
+    account   TEXT        NOT NULL,
+    id        TEXT        NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    data      JSONB       NOT NULL,
+    CONSTRAINT cex_trades_pk PRIMARY KEY (exchange, account, id)
+);
+CREATE INDEX IF NOT EXISTS cex_trades_timestamp_idx ON cex_trades (exchange, timestamp);
+
+CREATE TABLE IF NOT EXISTS cex_deposits
+(
+    exchange  TEXT        NOT NULL,
+    account   TEXT        NOT NULL,
+    id        TEXT        NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    data      JSONB       NOT NULL,
+    CONSTRAINT cex_deposits_pk PRIMARY KEY (exchange, account, id)
+);
+CREATE INDEX IF NOT EXISTS cex_deposits_timestamp_idx ON cex_deposits (exchange, timestamp);
+
+CREATE TABLE IF NOT EXISTS cex_withdrawals
+(
+    exchange  TEXT        NOT NULL,
+    account   TEXT        NOT NULL,
+    id        TEXT        NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    data      JSONB       NOT NULL,
+    CONSTRAINT cex_withdrawals_pk PRIMARY KEY (exchange, account, id)
+);
+CREATE INDEX IF NOT EXISTS cex_withdrawals_timestamp_idx ON cex_withdrawals (exchange, timestamp);
+
+CREATE TABLE IF NOT EXISTS cex_balances
+(
+    exchange  TEXT        NOT NULL,
+    account   TEXT        NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    wallet    TEXT        NOT NULL DEFAULT 'spot',
+    asset     TEXT        NOT NULL,
+    free      NUMERIC     NOT NULL,
+    locked    NUMERIC     NOT NULL,
+    borrowed  NUMERIC     NOT NULL DEFAULT 0,
+    CONSTRAINT cex_balances_pk PRIMARY KEY (exchange, account, timestamp, wallet, asset)
+);
+
+-- wallet and borrowed amounts were not tracked in the tables created before cross margin support
+ALTER TABLE cex_balances ADD COLUMN IF NOT EXISTS wallet TEXT NOT NULL DEFAULT 'spot';
+ALTER TABLE cex_balances ADD COLUMN IF NOT EXISTS borrowed NUMERIC NOT NULL DEFAULT 0;
+DO $$
+    BEGIN
+        IF NOT EXISTS (SELECT 1 FROM information_schema.key_column_usage
+            WHERE table_name = 'cex_balances' AND constraint_name = 'cex_balances_pk' AND column_name = 'wallet') THEN
+            ALTER TABLE cex_balances DROP CONSTRAINT cex_balances_pk;
+            ALTER TABLE cex_balances ADD CONSTRAINT cex_balances_pk PRIMARY KEY (exchange, account, timestamp, wallet, asset);
+        END IF;
+    END;
+$$;
+
+-- a snapshot is recorded even if the account has no balances, so a drained account is not reported with the
+-- balances of an older snapshot
+CREATE TABLE IF NOT EXISTS cex_balance_snapshots
+(
+    exchange  TEXT        NOT NULL,
+    account   TEXT        NOT NULL,
+    timestamp TIMESTAMPTZ NOT NULL,
+    CONSTRAINT cex_balance_snapshots_pk PRIMARY KEY (exchange, account, timestamp)
+);
+INSERT INTO cex_balance_snapshots (exchange, account, timestamp)
+SELECT DISTINCT exchange, account, timestamp FROM cex_balances
+WHERE NOT EXISTS (SELECT 1 FROM cex_balance_snapshots)
+ON CONFLICT DO NOTHING;
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
+	ethereum "github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli"
 	"go.uber.org/zap"
 
@@ -17,12 +19,22 @@ import (
 )
 
 const (
-	tradelogsBaseURLFlag    = "tradelogs-base-url"
-	defaultTradelogsBaseURL = "https://0x-exchange-proxy.knstats.com"
+	subgraphURLFlag   = "subgraph-url"
+	makerAddressFlag  = "maker-address"
+	pageSizeFlag      = "page-size"
+	retryDelayFlag    = "retry-delay"
+	attemptFlag       = "attempt"
+	defaultPageSize   = 1000
+	defaultRetryDelay = 5 * time.Second
+	defaultAttempt    = 3
 
 	fromTimeFlag    = "from-time"
 	defaultFromTime = 1633089600 //
 	toTimeFlag      = "to-time"
+
+	// indexingLag is how long the subgraph may take to index a fill, the checkpoint is kept behind
+	// the current time by this margin so fills indexed late are fetched on the next loop.
+	indexingLag = 10 * time.Minute
 )
 
 func main() {
@@ -37,10 +49,33 @@ func main() {
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.Accounting0xTradesPort)...)
 	app.Flags = append(app.Flags,
 		cli.StringFlag{
-			Name:   tradelogsBaseURLFlag,
-			Usage:  "default url to get tradelogs",
-			EnvVar: "tradelogs-base-url",
-			Value:  defaultTradelogsBaseURL,
+			Name:   subgraphURLFlag,
+			Usage:  "0x exchange subgraph endpoint to get tradelogs from",
+			EnvVar: "SUBGRAPH_URL",
+			Value:  zerox.DefaultSubgraphURL,
+		},
+		cli.StringSliceFlag{
+			Name:   makerAddressFlag,
+			Usage:  fmt.Sprintf("maker addresses to get tradelogs for, default to %s", zerox.DefaultMakerAddress),
+			EnvVar: "MAKER_ADDRESSES",
+		},
+		cli.IntFlag{
+			Name:   pageSizeFlag,
+			Usage:  "number of tradelogs to request per page",
+			EnvVar: "PAGE_SIZE",
+			Value:  defaultPageSize,
+		},
+		cli.DurationFlag{
+			Name:   retryDelayFlag,
+			Usage:  "delay time when do a retry",
+			EnvVar: "RETRY_DELAY",
+			Value:  defaultRetryDelay,
+		},
+		cli.IntFlag{
+			Name:   attemptFlag,
+			Usage:  "number of time doing retry",
+			EnvVar: "ATTEMPT",
+			Value:  defaultAttempt,
 		},
 		cli.Int64Flag{
 			Name:   fromTimeFlag,
@@ -80,31 +115,49 @@ func run(c *cli.Context) error {
 		return err
 	}
 
-	baseURL := c.String(tradelogsBaseURLFlag)
+	makers := c.StringSlice(makerAddressFlag)
+	if len(makers) == 0 {
+		makers = []string{zerox.DefaultMakerAddress}
+	}
+	for _, maker := range makers {
+		if !ethereum.IsHexAddress(maker) {
+			return fmt.Errorf("invalid maker address: %s", maker)
+		}
+	}
+
 	marketDataBaseURL := marketdata.GetMarketDataBaseURLFromContext(c)
 	marketDataClient := marketdata.NewMarketDataClient(marketDataBaseURL, l)
 	binanceClient := binance.NewClient("", "")
-	client := zerox.NewZeroXTradelogClient(baseURL, binanceClient, marketDataClient, l)
+	client, err := zerox.NewZeroXTradelogClient(binanceClient, marketDataClient, l,
+		zerox.WithSubgraphURL(c.String(subgraphURLFlag)),
+		zerox.WithPageSize(c.Int(pageSizeFlag)),
+		zerox.WithRetry(c.Int(attemptFlag), c.Duration(retryDelayFlag)),
+	)
+	if err != nil {
+		return err
+	}
 
 	ticker := time.NewTicker(time.Minute)
 	for ; true; <-ticker.C {
-		startTime := c.Int64(fromTimeFlag)
-		if startTime == 0 {
-			// get latest saved trade timestamp from db
-			startTime, err = st.GetLastTradeTimestamp()
-			if err != nil {
-				return err
-			}
+		for _, maker := range makers {
+			startTime := c.Int64(fromTimeFlag)
 			if startTime == 0 {
-				startTime = defaultFromTime
+				// continue from the saved checkpoint of this maker
+				startTime, err = st.GetCheckpoint(maker)
+				if err != nil {
+					return err
+				}
+				if startTime == 0 {
+					startTime = defaultFromTime
+				}
+			}
+			endTime := c.Int64(toTimeFlag)
+			if endTime == 0 {
+				endTime = time.Now().Unix() // default timestamp is now
+			}
+			if err := findConvertTrade(maker, startTime, endTime, client, st); err != nil {
+				return err
 			}
-		}
-		endTime := c.Int64(toTimeFlag)
-		if endTime == 0 {
-			endTime = time.Now().Unix() // default timestamp is now
-		}
-		if err := findConvertTrade(startTime, endTime, client, st); err != nil {
-			return err
 		}
 		l.Info("Done. Wait for next loop")
 	}
@@ -123,7 +176,7 @@ func findNonETHTrade(trades []zerox.Tradelog) []zerox.Tradelog {
 	return tradelogs
 }
 
-func findConvertTrade(startTime, endTime int64, client *zerox.TradelogClient, st *storage.ZeroxStorage) error {
+func findConvertTrade(maker string, startTime, endTime int64, client *zerox.TradelogClient, st *storage.ZeroxStorage) error {
 	var (
 		nonETHTradelogs []zerox.Tradelog
 	)
@@ -133,7 +186,7 @@ func findConvertTrade(startTime, endTime int64, client *zerox.TradelogClient, st
 		if toTime.Unix() > endTime {
 			toTime = time.Unix(endTime, 0)
 		}
-		tempTrades, err := client.GetTradelogsFromHTTP(fromTime, toTime)
+		tempTrades, err := client.GetTradelogs(maker, fromTime, toTime)
 		if err != nil {
 			return err
 		}
@@ -147,7 +200,15 @@ func findConvertTrade(startTime, endTime int64, client *zerox.TradelogClient, st
 			return err
 		}
 
-		if err := st.InsertTradelogs(tempTrades); err != nil {
+		if err := st.InsertTradelogs(maker, tempTrades); err != nil {
+			return err
+		}
+
+		checkpoint := toTime
+		if indexed := time.Now().Add(-indexingLag); checkpoint.After(indexed) {
+			checkpoint = indexed
+		}
+		if err := st.UpdateCheckpoint(maker, checkpoint.Unix()); err != nil {
 			return err
 		}
 
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/fetcher"
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/tradestorage"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	retryDelayFlag    = "retry-delay"
+	attemptFlag       = "attempt"
+	batchSizeFlag     = "batch-size"
+	defaultRetryDelay = 2 * time.Minute
+	defaultAttempt    = 4
+	defaultBatchSize  = 20
+	// binance only serves the recent 3 months of futures income history
+	defaultIncomeLookback = time.Hour * 24 * 90
+)
+
+var sugar *zap.SugaredLogger
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "Accounting binance futures fetcher"
+	app.Usage = "Fetch and store USDⓈ-M futures trades, funding fees and transfers of binance accounts and sub-accounts"
+	app.Action = run
+
+	app.Flags = append(app.Flags,
+		cli.DurationFlag{
+			Name:   retryDelayFlag,
+			Usage:  "delay time when do a retry",
+			EnvVar: "RETRY_DELAY",
+			Value:  defaultRetryDelay,
+		},
+		cli.IntFlag{
+			Name:   attemptFlag,
+			Usage:  "number of time doing retry",
+			EnvVar: "ATTEMPT",
+			Value:  defaultAttempt,
+		},
+		cli.IntFlag{
+			Name:   batchSizeFlag,
+			Usage:  "batch to request to binance",
+			EnvVar: "BATCH_SIZE",
+			Value:  defaultBatchSize,
+		},
+	)
+
+	app.Flags = append(app.Flags, binance.NewCliFlags()...)
+	app.Flags = append(app.Flags, timeutil.NewMilliTimeRangeCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexTradesDB)...)
+
+	if err := app.Run(os.Args); err != nil {
+		sugar.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	var (
+		flusher  func()
+		err      error
+		errGroup errgroup.Group
+	)
+	sugar, flusher, err = libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+
+	defer flusher()
+
+	sugar.Info("initiate fetcher")
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	binanceStorage, err := tradestorage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if cErr := binanceStorage.Close(); cErr != nil {
+			sugar.Errorw("Close database error", "error", cErr)
+		}
+	}()
+
+	options, err := binance.ClientOptionFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	publicClient, err := binance.NewBinance("", "", sugar, options...) // this is public client to get exchange info
+	if err != nil {
+		return err
+	}
+	exchangeInfo, err := publicClient.GetFuturesExchangeInfo()
+	if err != nil {
+		return err
+	}
+	symbols := exchangeInfo.Symbols
+
+	fromTime, err := timeutil.FromTimeMillisFromContext(c)
+	if err != nil {
+		return err
+	}
+	toTime, err := timeutil.ToTimeMillisFromContext(c)
+	if err != nil {
+		return err
+	}
+	if toTime.IsZero() {
+		toTime = time.Now()
+	}
+
+	retryDelay := c.Duration(retryDelayFlag)
+	attempt := c.Int(attemptFlag)
+	batchSize := c.Int(batchSizeFlag)
+	accounts, err := binance.AccountsFromContext(c)
+	if err != nil {
+		return err
+	}
+	accounts, err = fetcher.WithSubAccounts(sugar, accounts, options...)
+	if err != nil {
+		return err
+	}
+
+	for _, account := range accounts {
+		fromIDs := make(map[string]uint64)
+		for _, symbol := range symbols {
+			from, err := binanceStorage.GetLastStoredFuturesTradeID(symbol.Symbol, account.Name)
+			if err != nil {
+				return err
+			}
+			fromIDs[symbol.Symbol] = from
+		}
+
+		incomeFromTimes := make(map[string]time.Time)
+		for _, incomeType := range []string{binance.FuturesIncomeFundingFee, binance.FuturesIncomeTransfer} {
+			from := fromTime
+			if from.IsZero() {
+				sugar.Infow("from time is not provided, get latest timestamp from database", "income type", incomeType)
+				if from, err = binanceStorage.GetLastStoredFuturesIncomeTime(incomeType, account.Name); err != nil {
+					return err
+				}
+			}
+			if from.IsZero() {
+				from = toTime.Add(-defaultIncomeLookback)
+			}
+			incomeFromTimes[incomeType] = from
+		}
+
+		binanceClient, err := binance.NewBinance(account.APIKey, account.SecretKey, sugar, options...)
+		if err != nil {
+			return err
+		}
+
+		binanceFetcher := fetcher.NewFetcher(sugar, binanceClient, retryDelay, attempt, batchSize, binanceStorage, account.Name, nil)
+		errGroup.Go(
+			func(accountName string) func() error {
+				return func() error {
+					for incomeType, from := range incomeFromTimes {
+						if err := binanceFetcher.GetFuturesIncomeHistory(incomeType, from, toTime, accountName); err != nil {
+							return err
+						}
+					}
+					return binanceFetcher.GetFuturesTradeHistory(fromIDs, symbols, accountName)
+				}
+			}(account.Name))
+	}
+	return errGroup.Wait()
+}
@@ -122,6 +122,10 @@ func run(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	accounts, err = fetcher.WithSubAccounts(sugar, accounts)
+	if err != nil {
+		return err
+	}
 	for _, account := range accounts {
 		fromIDs := make(map[string]uint64)
 		for _, pair := range tokenPairs {
@@ -122,6 +122,10 @@ func run(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
+	accounts, err = fetcher.WithSubAccounts(sugar, accounts, options...)
+	if err != nil {
+		return err
+	}
 	// notETHTrades := make(map[*binance.Symbol][]binance.TradeHistory)
 	for _, account := range accounts {
 		fromIDs := make(map[string]uint64)
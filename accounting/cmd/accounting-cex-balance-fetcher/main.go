@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/accounting/cex/snapshot"
+	"github.com/KyberNetwork/reserve-stats/accounting/cex/storage"
+	"github.com/KyberNetwork/reserve-stats/accounting/cex/storage/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+)
+
+const (
+	retryDelayFlag    = "retry-delay"
+	maxAttemptFlag    = "max-attempts"
+	intervalFlag      = "interval"
+	defaultMaxAttempt = 3
+	defaultRetryDelay = time.Second
+	defaultInterval   = time.Hour
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "Accounting CEX Balance Fetcher"
+	app.Usage = "Take balance snapshots of Binance spot and margin wallets and Huobi accounts periodically"
+	app.Action = run
+	app.Version = "0.0.1"
+	app.Flags = append(app.Flags,
+		cli.IntFlag{
+			Name:   maxAttemptFlag,
+			Usage:  "The maximum number of attempts to retry fetching data",
+			EnvVar: "MAX_ATTEMPTS",
+			Value:  defaultMaxAttempt,
+		},
+		cli.DurationFlag{
+			Name:   retryDelayFlag,
+			Usage:  "The duration to put fetcher job to sleep after each fail attempt",
+			EnvVar: "RETRY_DELAY",
+			Value:  defaultRetryDelay,
+		},
+		cli.DurationFlag{
+			Name:   intervalFlag,
+			Usage:  "The duration between snapshots, snapshots are taken once if it is 0",
+			EnvVar: "INTERVAL",
+			Value:  defaultInterval,
+		},
+	)
+	app.Flags = append(app.Flags, binance.NewCliFlags()...)
+	app.Flags = append(app.Flags, huobi.NewCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexTradesDB)...)
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// account is an exchange account with the function to get its balances.
+type account struct {
+	exchange string
+	name     string
+	balances func() ([]cex.Balance, error)
+}
+
+func newAccounts(c *cli.Context, sugar *zap.SugaredLogger) ([]account, error) {
+	var accounts []account
+
+	binanceOptions, err := binance.ClientOptionFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+	binanceAccounts, err := binance.AccountsFromContext(c)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get binance accounts: %v", err)
+	}
+	for _, acc := range binanceAccounts {
+		client, err := binance.NewBinance(acc.APIKey, acc.SecretKey, sugar, binanceOptions...)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account{
+			exchange: common.Binance.String(),
+			name:     acc.Name,
+			balances: func() ([]cex.Balance, error) { return snapshot.BinanceBalances(client) },
+		})
+	}
+
+	huobiOptions, err := huobi.ClientOptionFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+	huobiAccounts, err := huobi.AccountsFromContext(c)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get huobi accounts: %v", err)
+	}
+	for _, acc := range huobiAccounts {
+		client, err := huobi.NewClient(acc.APIKey, acc.SecretKey, sugar, huobiOptions...)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account{
+			exchange: common.Huobi.String(),
+			name:     acc.Name,
+			balances: func() ([]cex.Balance, error) { return snapshot.HuobiBalances(client) },
+		})
+	}
+	return accounts, nil
+}
+
+func takeSnapshot(sugar *zap.SugaredLogger, st storage.Interface, acc account, attempt int, retryDelay time.Duration) error {
+	var (
+		logger   = sugar.With("exchange", acc.exchange, "account", acc.name)
+		balances []cex.Balance
+		err      error
+	)
+	for i := 0; i < attempt; i++ {
+		if balances, err = acc.balances(); err == nil {
+			break
+		}
+		logger.Warnw("failed to get balances", "error", err, "attempt", i+1)
+		if i+1 < attempt {
+			time.Sleep(retryDelay)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	logger.Infow("storing balance snapshot", "balances", len(balances))
+	return st.UpdateBalances(acc.exchange, acc.name, time.Now(), balances)
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	if c.Int(maxAttemptFlag) < 1 {
+		return fmt.Errorf("%s must be greater than 0", maxAttemptFlag)
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	accounts, err := newAccounts(c, sugar)
+	if err != nil {
+		return err
+	}
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return fmt.Errorf("cannot create db from flags: %v", err)
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorf("failed to close database: err=%s", cErr.Error())
+		}
+	}()
+
+	st, err := postgres.NewStorage(sugar, db)
+	if err != nil {
+		return fmt.Errorf("cannot create cex storage: %v", err)
+	}
+
+	var (
+		interval   = c.Duration(intervalFlag)
+		retryDelay = c.Duration(retryDelayFlag)
+		maxAttempt = c.Int(maxAttemptFlag)
+	)
+	if interval == 0 {
+		for _, acc := range accounts {
+			if err = takeSnapshot(sugar, st, acc, maxAttempt, retryDelay); err != nil {
+				return fmt.Errorf("failed to take balance snapshot of %s account %s: %v", acc.exchange, acc.name, err)
+			}
+		}
+		return nil
+	}
+
+	ticker := time.NewTicker(interval)
+	for ; true; <-ticker.C {
+		for _, acc := range accounts {
+			// a failed account is retried at the next snapshot, the other accounts are not affected
+			if err = takeSnapshot(sugar, st, acc, maxAttempt, retryDelay); err != nil {
+				sugar.Errorw("failed to take balance snapshot", "exchange", acc.exchange, "account", acc.name, "error", err)
+			}
+		}
+		sugar.Info("Done. Wait for next snapshot")
+	}
+	return nil
+}
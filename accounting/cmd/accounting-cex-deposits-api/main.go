@@ -9,8 +9,10 @@ import (
 	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/depositstorage"
 	"github.com/KyberNetwork/reserve-stats/accounting/cex-deposit/http"
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	huobiPostgres "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/deposit-history/postgres"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 func newServerCli() *cli.App {
@@ -18,6 +20,7 @@ func newServerCli() *cli.App {
 	app.Name = "cex-deposit-api"
 	app.Usage = "server for query accounting cex deposit"
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingCEXDepositPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexWithdrawalsDB)...)
 	app.Action = run
 	return app
@@ -50,8 +53,13 @@ func run(c *cli.Context) error {
 		return err
 	}
 
+	huobiDB, err := huobiPostgres.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+
 	host := httputil.NewHTTPAddressFromContext(c)
-	server, err := http.NewServer(host, binanceDB, sugar)
+	server, err := http.NewServer(host, binanceDB, huobiDB, sugar, openapi.NewOptionsFromContext(c)...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	"github.com/KyberNetwork/reserve-stats/accounting/cex/fetcher"
+	_ "github.com/KyberNetwork/reserve-stats/accounting/cex/okx" // register okx adapter
+	"github.com/KyberNetwork/reserve-stats/accounting/cex/storage/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	retryDelayFlag       = "retry-delay"
+	maxAttemptFlag       = "max-attempts"
+	batchDurationFlag    = "batch-duration"
+	defaultMaxAttempt    = 3
+	defaultRetryDelay    = time.Second
+	defaultBatchDuration = 24 * time.Hour
+	// records are fetched from 90 days ago for new accounts
+	defaultFetchDuration = 90 * 24 * time.Hour
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "Accounting CEX Fetcher"
+	app.Usage = "Fetch and store trades, deposits, withdrawals and balances of exchange accounts with registered adapters"
+	app.Action = run
+	app.Version = "0.0.1"
+	app.Flags = append(app.Flags,
+		cli.IntFlag{
+			Name:   maxAttemptFlag,
+			Usage:  "The maximum number of attempts to retry fetching data",
+			EnvVar: "MAX_ATTEMPTS",
+			Value:  defaultMaxAttempt,
+		},
+		cli.DurationFlag{
+			Name:   retryDelayFlag,
+			Usage:  "The duration to put fetcher job to sleep after each fail attempt",
+			EnvVar: "RETRY_DELAY",
+			Value:  defaultRetryDelay,
+		},
+		cli.DurationFlag{
+			Name:   batchDurationFlag,
+			Usage:  "The duration for a batch query. Exchanges limit the time range of a query, default is 1 day each batch",
+			EnvVar: "BATCH_DURATION",
+			Value:  defaultBatchDuration,
+		},
+	)
+	app.Flags = append(app.Flags, cex.NewCliFlags()...)
+	app.Flags = append(app.Flags, timeutil.NewMilliTimeRangeCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexTradesDB)...)
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	if c.Int(maxAttemptFlag) < 1 {
+		return fmt.Errorf("%s must be greater than 0", maxAttemptFlag)
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	accounts, err := cex.AccountsFromContext(c)
+	if err != nil {
+		return fmt.Errorf("cannot get exchange accounts: %v", err)
+	}
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return fmt.Errorf("cannot create db from flags: %v", err)
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorf("failed to close database: err=%s", cErr.Error())
+		}
+	}()
+
+	st, err := postgres.NewStorage(sugar, db)
+	if err != nil {
+		return fmt.Errorf("cannot create cex storage: %v", err)
+	}
+
+	from, err := timeutil.FromTimeMillisFromContext(c)
+	if err != nil {
+		return fmt.Errorf("cannot get from time: %v", err)
+	}
+	to, err := timeutil.ToTimeMillisFromContext(c)
+	if err != nil {
+		return fmt.Errorf("cannot get to time: %v", err)
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	f := fetcher.NewFetcher(sugar, st, c.Duration(retryDelayFlag), c.Int(maxAttemptFlag), c.Duration(batchDurationFlag))
+	for _, account := range accounts {
+		adapter, err := cex.New(sugar, account)
+		if err != nil {
+			return err
+		}
+		if err = f.Fetch(context.Background(), account, adapter, from, to.Add(-defaultFetchDuration), to); err != nil {
+			return fmt.Errorf("failed to fetch records of %s account %s: %v", account.Exchange, account.Name, err)
+		}
+	}
+	return nil
+}
@@ -8,11 +8,15 @@ import (
 
 	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/tradestorage"
 	"github.com/KyberNetwork/reserve-stats/accounting/cex-trade/http"
+	_ "github.com/KyberNetwork/reserve-stats/accounting/cex/okx" // register okx adapter
+	cexstorage "github.com/KyberNetwork/reserve-stats/accounting/cex/storage/postgres"
+	commissionstorage "github.com/KyberNetwork/reserve-stats/accounting/commission/storage/postgres"
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	huobistorage "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/postgres"
 	"github.com/KyberNetwork/reserve-stats/accounting/zerox/storage"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 func main() {
@@ -24,6 +28,7 @@ func main() {
 
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexTradesDB)...)
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingCEXTradesPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
 	}
@@ -65,7 +70,15 @@ func run(c *cli.Context) error {
 	if err != nil {
 		return err
 	}
-	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), hs, bs, st)
+	cs, err := cexstorage.NewStorage(sugar, db)
+	if err != nil {
+		return err
+	}
+	cms, err := commissionstorage.NewStorage(sugar, db)
+	if err != nil {
+		return err
+	}
+	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), hs, bs, st, cs, cms, openapi.NewOptionsFromContext(c)...)
 
 	if err = s.Run(); err != nil {
 		return err
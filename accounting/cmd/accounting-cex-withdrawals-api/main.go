@@ -12,6 +12,7 @@ import (
 	huobiPostgres "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/withdrawal-history/postgres"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 func newServerCli() *cli.App {
@@ -19,6 +20,7 @@ func newServerCli() *cli.App {
 	app.Name = "cex-trade-withdrawal-api"
 	app.Usage = "server for query accounting cex-trade withdrawal"
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingCEXWithdrawalsPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexWithdrawalsDB)...)
 	app.Action = run
 	return app
@@ -57,7 +59,7 @@ func run(c *cli.Context) error {
 	}
 
 	host := httputil.NewHTTPAddressFromContext(c)
-	server, err := http.NewServer(host, huobiDB, binanceDB, sugar)
+	server, err := http.NewServer(host, huobiDB, binanceDB, sugar, openapi.NewOptionsFromContext(c)...)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/fetcher"
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/tradestorage"
+	_ "github.com/KyberNetwork/reserve-stats/accounting/cex/okx" // register okx adapter
+	cexstorage "github.com/KyberNetwork/reserve-stats/accounting/cex/storage/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/commission"
+	commissionstorage "github.com/KyberNetwork/reserve-stats/accounting/commission/storage/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	huobistorage "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/postgres"
+	pnlstorage "github.com/KyberNetwork/reserve-stats/accounting/pnl/storage"
+	rrpostgres "github.com/KyberNetwork/reserve-stats/accounting/reserve-rate/storage/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/zerox"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	reserveRatesDBFlag = "reserve-rates-database"
+	retryDelayFlag     = "retry-delay"
+	attemptFlag        = "attempt"
+	revisitFlag        = "revisit"
+	defaultRetryDelay  = 2 * time.Minute
+	defaultAttempt     = 4
+	// defaultRevisit is how far before the last stored commission trades are normalized again, so
+	// commissions stored with missing price are resolved once rates are fetched.
+	defaultRevisit = time.Hour * 24 * 7
+	// defaultLookback is how far trades are normalized if no commission is stored.
+	defaultLookback = time.Hour * 24 * 90
+	day             = time.Hour * 24
+)
+
+var sugar *zap.SugaredLogger
+
+// noZeroxTrades is used in place of 0x storage as 0x fills pay no commission.
+type noZeroxTrades struct{}
+
+func (noZeroxTrades) Get0xTrades(int64, int64) ([]zerox.SimpleTradelog, error) {
+	return nil, nil
+}
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "Accounting commission normalizer"
+	app.Usage = "Normalize commission of stored CEX trades to ETH and USD"
+	app.Action = run
+
+	app.Flags = append(app.Flags,
+		cli.StringFlag{
+			Name:   reserveRatesDBFlag,
+			Usage:  "database of accounting-reserve-rate-fetcher, using the same PostgreSQL connection flags",
+			EnvVar: "RESERVE_RATES_DATABASE",
+			Value:  common.DefaultReserveRatesDB,
+		},
+		cli.DurationFlag{
+			Name:   retryDelayFlag,
+			Usage:  "delay time when do a retry",
+			EnvVar: "RETRY_DELAY",
+			Value:  defaultRetryDelay,
+		},
+		cli.IntFlag{
+			Name:   attemptFlag,
+			Usage:  "number of time doing retry",
+			EnvVar: "ATTEMPT",
+			Value:  defaultAttempt,
+		},
+		cli.DurationFlag{
+			Name:   revisitFlag,
+			Usage:  "how far before the last stored commission trades are normalized again to resolve missing prices",
+			EnvVar: "REVISIT",
+			Value:  defaultRevisit,
+		},
+	)
+
+	app.Flags = append(app.Flags, binance.NewCliFlags()...)
+	app.Flags = append(app.Flags, timeutil.NewMilliTimeRangeCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexTradesDB)...)
+
+	if err := app.Run(os.Args); err != nil {
+		sugar.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	var (
+		flusher func()
+		err     error
+	)
+	sugar, flusher, err = libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flusher()
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorw("failed to close database", "error", cErr)
+		}
+	}()
+
+	ratesDB, err := libapp.NewDBWithDatabaseFromContext(c, c.String(reserveRatesDBFlag))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := ratesDB.Close(); cErr != nil {
+			sugar.Errorw("failed to close reserve rates database", "error", cErr)
+		}
+	}()
+
+	bs, err := tradestorage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	hs, err := huobistorage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	cs, err := cexstorage.NewStorage(sugar, db)
+	if err != nil {
+		return err
+	}
+	cms, err := commissionstorage.NewStorage(sugar, db)
+	if err != nil {
+		return err
+	}
+	rs, err := rrpostgres.NewDB(sugar, ratesDB)
+	if err != nil {
+		return err
+	}
+
+	options, err := binance.ClientOptionFromContext(c)
+	if err != nil {
+		return err
+	}
+	binanceClient, err := binance.NewBinance("", "", sugar, options...) // this is public client to get market data
+	if err != nil {
+		return err
+	}
+	exchangeInfo, err := binanceClient.GetExchangeInfo()
+	if err != nil {
+		return err
+	}
+	binanceFetcher := fetcher.NewFetcher(sugar, binanceClient, c.Duration(retryDelayFlag), c.Int(attemptFlag), 1, bs, "", nil)
+	binanceRates := fetcher.NewRates(binanceFetcher, exchangeInfo.Symbols)
+
+	fromTime, err := timeutil.FromTimeMillisFromContext(c)
+	if err != nil {
+		return err
+	}
+	toTime, err := timeutil.ToTimeMillisFromContext(c)
+	if err != nil {
+		return err
+	}
+	if toTime.IsZero() {
+		toTime = time.Now()
+	}
+	if fromTime.IsZero() {
+		sugar.Info("from time is not provided, get latest timestamp from database")
+		last, err := cms.GetLastTimestamp()
+		if err != nil {
+			return err
+		}
+		if last.IsZero() {
+			fromTime = toTime.Add(-defaultLookback)
+		} else {
+			fromTime = last.Add(-c.Duration(revisitFlag))
+		}
+	}
+
+	trades := pnlstorage.NewTrades(sugar, bs, hs, cs, noZeroxTrades{})
+	prices := pnlstorage.NewReserveRates(sugar, rs)
+	for from := fromTime; from.Before(toTime); from = from.Add(day) {
+		to := from.Add(day)
+		if to.After(toTime) {
+			to = toTime
+		}
+		if err := normalize(trades, prices, binanceRates, cms, from, to); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalize stores the commission of trades in time range which have no commission with price stored.
+func normalize(trades pnlstorage.TradesInterface, prices pnlstorage.PricesInterface, binanceRates commission.RateProvider,
+	cms *commissionstorage.Storage, from, to time.Time) error {
+	logger := sugar.With("from", from, "to", to)
+	stored, err := cms.GetCommissions(from, to)
+	if err != nil {
+		return err
+	}
+	resolved := make(map[[3]string]bool)
+	for _, c := range stored {
+		if !c.PriceMissing {
+			resolved[[3]string{c.Source, c.Account, c.TradeID}] = true
+		}
+	}
+
+	tradeList, err := trades.GetTrades(from, to)
+	if err != nil {
+		return err
+	}
+	// the daily rate of the day before covers trades before the rate of their day is fetched
+	historicalPrices, err := prices.GetPrices(from.Add(-day), to)
+	if err != nil {
+		return err
+	}
+	normalizer := commission.NewNormalizer(binanceRates, commission.NewHistoricalRates(historicalPrices))
+
+	var (
+		commissions []commission.Commission
+		missing     int
+	)
+	for _, trade := range tradeList {
+		if trade.FeeAmount == 0 || resolved[[3]string{trade.Source, trade.Account, trade.ID}] {
+			continue
+		}
+		c, err := normalizer.Normalize(trade)
+		if err != nil {
+			return err
+		}
+		if c.PriceMissing {
+			missing++
+		}
+		commissions = append(commissions, c)
+	}
+	logger.Infow("normalized commissions", "commissions", len(commissions), "price missing", missing)
+	return cms.UpdateCommissions(commissions)
+}
@@ -9,36 +9,53 @@ import (
 	"github.com/go-ozzo/ozzo-validation/is"
 	"github.com/urfave/cli"
 
-	"github.com/KyberNetwork/httpsign-utils/authenticator"
+	"github.com/KyberNetwork/reserve-stats/gateway/apikeys"
+	"github.com/KyberNetwork/reserve-stats/gateway/audit"
+	"github.com/KyberNetwork/reserve-stats/gateway/cache"
 	"github.com/KyberNetwork/reserve-stats/gateway/http"
+	"github.com/KyberNetwork/reserve-stats/gateway/ratelimit"
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	libredis "github.com/KyberNetwork/reserve-stats/lib/redis"
 )
 
 const (
-	writeAccessKeyFlag         = "write-access-key"
-	writeSecretKeyFlag         = "write-secret-key"
-	readAccessKeyFlag          = "read-access-key"
-	readSecretKeyFlag          = "read-secret-key"
-	cexTradeAPIURLFlag         = "cex-trade-url"
-	reserveAddressesAPIURLFlag = "reserve-addresses-url"
-	cexWithdrawalURLFlag       = "cex-withdrawal-url"
-	cexDepositURLFlag          = "cex-deposit-url"
-	reserveTokenURLFlag        = "reserve-token-url"
-	reserveTransactionURLFlag  = "reserve-transaction-url"
-	erc20APIURLFlag            = "erc20-api-url"
-	reserveRatesAPIFlag        = "reserve-rates-url"
+	defaultAPIKeysDB = "accounting_gateway"
+
+	writeAccessKeyFlag            = "write-access-key"
+	writeSecretKeyFlag            = "write-secret-key"
+	readAccessKeyFlag             = "read-access-key"
+	readSecretKeyFlag             = "read-secret-key"
+	cexTradeAPIURLFlag            = "cex-trade-url"
+	reserveAddressesAPIURLFlag    = "reserve-addresses-url"
+	cexWithdrawalURLFlag          = "cex-withdrawal-url"
+	cexDepositURLFlag             = "cex-deposit-url"
+	reserveTokenURLFlag           = "reserve-token-url"
+	reserveTransactionURLFlag     = "reserve-transaction-url"
+	erc20APIURLFlag               = "erc20-api-url"
+	reserveRatesAPIFlag           = "reserve-rates-url"
+	tokenParamsAPIFlag            = "token-params-url"
+	reserveCompetitivenessAPIFlag = "reserve-competitiveness-url"
+	pnlAPIFlag                    = "pnl-url"
+	transferMatcherAPIFlag        = "transfer-matcher-url"
+	ledgerAPIFlag                 = "ledger-url"
 )
 
 var (
-	defaultCexTradeAPIValue           = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingCEXTradesPort)
-	defaultReserveAddressAPIValue     = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingReserveAddressPort)
-	defaultCexWithdrawalAPIValue      = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingCEXWithdrawalsPort)
-	defaultCexDepositAPIValue         = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingCEXDepositPort)
-	defaultReserveTokenAPIValue       = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingReserveTokensPort)
-	defaultReserveTransactionAPIValue = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingTransactionsPort)
-	defaultERC20APIValue              = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingWalletErc20Port)
-	defaultReserveRatesAPIValue       = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingReserveRatesPort)
+	defaultCexTradeAPIValue               = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingCEXTradesPort)
+	defaultReserveAddressAPIValue         = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingReserveAddressPort)
+	defaultCexWithdrawalAPIValue          = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingCEXWithdrawalsPort)
+	defaultCexDepositAPIValue             = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingCEXDepositPort)
+	defaultReserveTokenAPIValue           = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingReserveTokensPort)
+	defaultReserveTransactionAPIValue     = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingTransactionsPort)
+	defaultERC20APIValue                  = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingWalletErc20Port)
+	defaultReserveRatesAPIValue           = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingReserveRatesPort)
+	defaultTokenParamsAPIValue            = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingTokenParamsPort)
+	defaultReserveCompetitivenessAPIValue = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingReserveCompetitivenessPort)
+	defaultPnLAPIValue                    = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingPnLPort)
+	defaultTransferMatcherAPIValue        = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingTransferMatcherPort)
+	defaultLedgerAPIValue                 = fmt.Sprintf("http://127.0.0.1:%d", httputil.AccountingLedgerPort)
 )
 
 func main() {
@@ -117,8 +134,45 @@ func main() {
 			Value:  defaultReserveRatesAPIValue,
 			EnvVar: "RESERVE_RATES_URL",
 		},
+		cli.StringFlag{
+			Name:   tokenParamsAPIFlag,
+			Usage:  "token params api url",
+			Value:  defaultTokenParamsAPIValue,
+			EnvVar: "TOKEN_PARAMS_URL",
+		},
+		cli.StringFlag{
+			Name:   reserveCompetitivenessAPIFlag,
+			Usage:  "reserve competitiveness api url",
+			Value:  defaultReserveCompetitivenessAPIValue,
+			EnvVar: "RESERVE_COMPETITIVENESS_URL",
+		},
+		cli.StringFlag{
+			Name:   pnlAPIFlag,
+			Usage:  "pnl api url",
+			Value:  defaultPnLAPIValue,
+			EnvVar: "PNL_URL",
+		},
+		cli.StringFlag{
+			Name:   transferMatcherAPIFlag,
+			Usage:  "transfer matcher api url",
+			Value:  defaultTransferMatcherAPIValue,
+			EnvVar: "TRANSFER_MATCHER_URL",
+		},
+		cli.StringFlag{
+			Name:   ledgerAPIFlag,
+			Usage:  "ledger api url",
+			Value:  defaultLedgerAPIValue,
+			EnvVar: "LEDGER_URL",
+		},
 	)
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.GatewayPort)...)
+	app.Flags = append(app.Flags, apikeys.NewCliFlags(defaultAPIKeysDB)...)
+	app.Flags = append(app.Flags, audit.NewCliFlags()...)
+	app.Flags = append(app.Flags, ratelimit.NewCliFlags()...)
+	app.Flags = append(app.Flags, cache.NewCliFlags()...)
+	app.Flags = append(app.Flags, libredis.NewCliFlags()...)
+	app.Flags = append(app.Flags, http.NewRouteTableCliFlags()...)
+	app.Flags = append(app.Flags, upstream.NewCliFlags()...)
 
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
@@ -132,84 +186,161 @@ func run(c *cli.Context) error {
 	}
 	defer libapp.NewFlusher(logger)()
 
-	err = validation.Validate(c.String(cexTradeAPIURLFlag),
+	if err := validation.Validate(c.String(writeAccessKeyFlag), validation.Required); err != nil {
+		return fmt.Errorf("access key error: %s", err.Error())
+	}
+
+	if err := validation.Validate(c.String(writeSecretKeyFlag), validation.Required); err != nil {
+		return fmt.Errorf("secret key error: %s", err.Error())
+	}
+	staticKeys := apikeys.NewStaticKeys(
+		c.String(readAccessKeyFlag), c.String(readSecretKeyFlag),
+		c.String(writeAccessKeyFlag), c.String(writeSecretKeyFlag),
+	)
+	keyManager, err := apikeys.NewManagerFromContext(c, logger.Sugar(), staticKeys, c.String(writeAccessKeyFlag))
+	if err != nil {
+		return fmt.Errorf("api keys manager creation error: %s", err)
+	}
+	if keyManager.HasStorage() {
+		go keyManager.Run(apikeys.ReloadIntervalFromContext(c))
+	}
+	auditStorage, err := audit.NewStorageFromContext(c, logger.Sugar())
+	if err != nil {
+		return fmt.Errorf("audit log storage creation error: %s", err)
+	}
+	limiter, err := ratelimit.NewLimiterFromContext(c, logger.Sugar())
+	if err != nil {
+		return fmt.Errorf("rate limiter creation error: %s", err)
+	}
+	cacheStore, err := cache.NewStoreFromContext(c)
+	if err != nil {
+		return fmt.Errorf("response cache creation error: %s", err)
+	}
+	routeTable, err := http.NewRouteTableFromContext(c)
+	if err != nil {
+		return err
+	}
+	// upstream defaults, rate limiter, audit log and response cache must be registered before routes
+	options := []http.Option{http.WithUpstreamDefaults(upstream.NewDefaultConfigFromContext(c))}
+	if limiter != nil {
+		options = append(options, http.WithRateLimiter(limiter))
+	}
+	if auditStorage != nil {
+		options = append(options, http.WithAuditLog(logger.Sugar(), auditStorage))
+	}
+	// the route table has cache policy per route
+	if cacheStore != nil && routeTable == nil {
+		options = append(options, http.WithResponseCache(logger.Sugar(), cacheStore, cache.NewPolicyFromContext(c)))
+	}
+	options = append(options, http.WithAPIKeyManager(keyManager))
+	if routeTable != nil {
+		options = append(options, http.WithRouteTable(logger.Sugar(), *routeTable, cacheStore))
+	} else {
+		urlOptions, err := newURLRouteOptions(c)
+		if err != nil {
+			return err
+		}
+		options = append(options, urlOptions...)
+	}
+	svr, err := http.NewServer(httputil.NewHTTPAddressFromContext(c),
+		keyManager.Authenticated(),
+		keyManager.Permission(),
+		logger,
+		options...,
+	)
+	if err != nil {
+		return err
+	}
+	return svr.Start()
+}
+
+// newURLRouteOptions returns the routes of upstream URL flags, which are used if the route table is not configured.
+func newURLRouteOptions(c *cli.Context) ([]http.Option, error) {
+	err := validation.Validate(c.String(cexTradeAPIURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid cex trade API URL: %s", c.String(cexTradeAPIURLFlag))
+		return nil, fmt.Errorf("invalid cex trade API URL: %s", c.String(cexTradeAPIURLFlag))
 	}
 
 	err = validation.Validate(c.String(reserveAddressesAPIURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid reserve address API URL: %s", c.String(reserveAddressesAPIURLFlag))
+		return nil, fmt.Errorf("invalid reserve address API URL: %s", c.String(reserveAddressesAPIURLFlag))
 	}
 
 	err = validation.Validate(c.String(cexWithdrawalURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid cex withdrawal API URL: %s", c.String(cexWithdrawalURLFlag))
+		return nil, fmt.Errorf("invalid cex withdrawal API URL: %s", c.String(cexWithdrawalURLFlag))
 	}
 
 	err = validation.Validate(c.String(reserveTokenURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid reserve token API URL: %s", c.String(reserveTokenURLFlag))
+		return nil, fmt.Errorf("invalid reserve token API URL: %s", c.String(reserveTokenURLFlag))
 	}
 
 	err = validation.Validate(c.String(reserveTransactionURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid reserve transaction API URL: %s", c.String(reserveTransactionURLFlag))
+		return nil, fmt.Errorf("invalid reserve transaction API URL: %s", c.String(reserveTransactionURLFlag))
 	}
 
 	err = validation.Validate(c.String(erc20APIURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid erc20 transaction API URL: %s", c.String(erc20APIURLFlag))
+		return nil, fmt.Errorf("invalid erc20 transaction API URL: %s", c.String(erc20APIURLFlag))
 	}
 
 	err = validation.Validate(c.String(reserveRatesAPIFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid reserve rates API URL: %s", c.String(reserveRatesAPIFlag))
+		return nil, fmt.Errorf("invalid reserve rates API URL: %s", c.String(reserveRatesAPIFlag))
 	}
 
-	if err := validation.Validate(c.String(writeAccessKeyFlag), validation.Required); err != nil {
-		return fmt.Errorf("access key error: %s", err.Error())
+	err = validation.Validate(c.String(tokenParamsAPIFlag),
+		validation.Required,
+		is.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token params API URL: %s", c.String(tokenParamsAPIFlag))
 	}
 
-	if err := validation.Validate(c.String(writeSecretKeyFlag), validation.Required); err != nil {
-		return fmt.Errorf("secret key error: %s", err.Error())
+	err = validation.Validate(c.String(reserveCompetitivenessAPIFlag),
+		validation.Required,
+		is.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid reserve competitiveness API URL: %s", c.String(reserveCompetitivenessAPIFlag))
 	}
-	keyPairs := []authenticator.KeyPair{
-		{
-			AccessKeyID:     c.String(readAccessKeyFlag),
-			SecretAccessKey: c.String(readSecretKeyFlag),
-		},
-		{
-			AccessKeyID:     c.String(writeAccessKeyFlag),
-			SecretAccessKey: c.String(writeSecretKeyFlag),
-		},
+
+	err = validation.Validate(c.String(pnlAPIFlag),
+		validation.Required,
+		is.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pnl API URL: %s", c.String(pnlAPIFlag))
 	}
-	auth, err := authenticator.NewAuthenticator(keyPairs...)
+
+	err = validation.Validate(c.String(transferMatcherAPIFlag),
+		validation.Required,
+		is.URL)
 	if err != nil {
-		return fmt.Errorf("authentication object creation error: %s", err)
+		return nil, fmt.Errorf("invalid transfer matcher API URL: %s", c.String(transferMatcherAPIFlag))
 	}
-	perm, err := http.NewPermissioner(c.String(readAccessKeyFlag), c.String(writeAccessKeyFlag))
+
+	err = validation.Validate(c.String(ledgerAPIFlag),
+		validation.Required,
+		is.URL)
 	if err != nil {
-		return fmt.Errorf("permission object creation error: %s", err)
+		return nil, fmt.Errorf("invalid ledger API URL: %s", c.String(ledgerAPIFlag))
 	}
-	svr, err := http.NewServer(httputil.NewHTTPAddressFromContext(c),
-		auth,
-		perm,
-		logger,
+
+	return []http.Option{
 		http.WithCexTradesURL(c.String(cexTradeAPIURLFlag)),
 		http.WithResreveAddressesURL(c.String(reserveAddressesAPIURLFlag)),
 		http.WithCexWithdrawalURL(c.String(cexWithdrawalURLFlag)),
@@ -218,9 +349,10 @@ func run(c *cli.Context) error {
 		http.WithReserveTransactionURL(c.String(reserveTransactionURLFlag)),
 		http.WithERC20APIURL(c.String(erc20APIURLFlag)),
 		http.WithReserveRatesURL(c.String(reserveRatesAPIFlag)),
-	)
-	if err != nil {
-		return err
-	}
-	return svr.Start()
+		http.WithTokenParamsURL(c.String(tokenParamsAPIFlag)),
+		http.WithReserveCompetitivenessURL(c.String(reserveCompetitivenessAPIFlag)),
+		http.WithPnLURL(c.String(pnlAPIFlag)),
+		http.WithTransferMatcherURL(c.String(transferMatcherAPIFlag)),
+		http.WithLedgerURL(c.String(ledgerAPIFlag)),
+	}, nil
 }
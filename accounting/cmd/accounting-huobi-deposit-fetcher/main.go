@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	huobiFetcher "github.com/KyberNetwork/reserve-stats/accounting/huobi/fetcher"
+	"github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/deposit-history/postgres"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+)
+
+const (
+	retryDelayFlag    = "retry-delay"
+	maxAttemptFlag    = "max-attempts"
+	defaultMaxAttempt = 3
+	defaultRetryDelay = time.Second
+	fromIDFlag        = "from-id"
+	defaultFromID     = 0
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "Huobi Fetcher"
+	app.Usage = "Huobi Fetcher for deposit records. It will fetch from input ID or the cursor stored of each account to the latest deposit"
+	app.Action = run
+	app.Version = "0.0.1"
+	app.Flags = append(app.Flags,
+		cli.IntFlag{
+			Name:   maxAttemptFlag,
+			Usage:  "The maximum number of attempts to retry fetching data",
+			EnvVar: "MAX_ATTEMPTS",
+			Value:  defaultMaxAttempt,
+		},
+		cli.DurationFlag{
+			Name:   retryDelayFlag,
+			Usage:  "The duration to put fetcher job to sleep after each fail attempt",
+			EnvVar: "RETRY_DELAY",
+			Value:  defaultRetryDelay,
+		},
+		cli.Uint64Flag{
+			Name:   fromIDFlag,
+			Usage:  "The ID from which to query deposit history from. Default is 0 (fetch from the cursor stored of each account)",
+			EnvVar: "FROM_ID",
+			Value:  defaultFromID,
+		},
+	)
+	app.Flags = append(app.Flags, huobi.NewCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexWithdrawalsDB)...)
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	sugar, flusher, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flusher()
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return fmt.Errorf("cannot create db from flags: %v", err)
+	}
+
+	hdb, err := postgres.NewDB(sugar, db)
+	if err != nil {
+		return fmt.Errorf("cannot create huobi database instance: %v", err)
+	}
+	defer func() {
+		if cErr := hdb.Close(); cErr != nil {
+			sugar.Errorw("Close database error", "error", cErr)
+		}
+	}()
+
+	retryDelay := c.Duration(retryDelayFlag)
+	maxAttempts := c.Int(maxAttemptFlag)
+	options, err := huobi.ClientOptionFromContext(c)
+	if err != nil {
+		return err
+	}
+	accounts, err := huobi.AccountsFromContext(c)
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		fromID := c.Uint64(fromIDFlag)
+		if fromID == 0 {
+			sugar.Infow("From id is not provided, get cursor stored in db", "account", account.Name)
+			if fromID, err = hdb.GetCursor(account.Name); err != nil {
+				return err
+			}
+		}
+		sugar.Infow("get deposit history from", "account", account.Name, "ID", fromID+1)
+		huobiClient, err := huobi.NewClient(account.APIKey, account.SecretKey, sugar, options...)
+		if err != nil {
+			return err
+		}
+		fetcher := huobiFetcher.NewFetcher(sugar, huobiClient, retryDelay, maxAttempts)
+		deposits, err := fetcher.GetDepositHistory(fromID + 1)
+		if err != nil {
+			return err
+		}
+		if err = hdb.UpdateDepositHistory(deposits, account.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
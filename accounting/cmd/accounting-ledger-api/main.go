@@ -0,0 +1,59 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/http"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/storage/postgres"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "accounting-ledger-api"
+	app.Usage = "serve chart of accounts, trial balance and general ledger of reserve accounting, and close periods"
+	app.Action = run
+	app.Version = "0.0.1"
+
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultLedgerDB)...)
+	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingLedgerPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorf("failed to close database: err=%s", cErr.Error())
+		}
+	}()
+
+	st, err := postgres.NewStorage(sugar, db)
+	if err != nil {
+		return err
+	}
+	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), st, openapi.NewOptionsFromContext(c)...)
+	return s.Run()
+}
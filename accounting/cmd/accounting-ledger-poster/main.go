@@ -0,0 +1,292 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/depositstorage"
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/tradestorage"
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/withdrawalstorage"
+	_ "github.com/KyberNetwork/reserve-stats/accounting/cex/okx" // register okx adapter
+	cexstorage "github.com/KyberNetwork/reserve-stats/accounting/cex/storage/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	huobideposit "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/deposit-history/postgres"
+	huobistorage "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/postgres"
+	huobiwithdrawal "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/withdrawal-history/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/poster"
+	ledgerstorage "github.com/KyberNetwork/reserve-stats/accounting/ledger/storage"
+	ledgerpostgres "github.com/KyberNetwork/reserve-stats/accounting/ledger/storage/postgres"
+	pnlstorage "github.com/KyberNetwork/reserve-stats/accounting/pnl/storage"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/client"
+	txstorage "github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage/postgres"
+	matchercommon "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	matcherstorage "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/storage"
+	zeroxstorage "github.com/KyberNetwork/reserve-stats/accounting/zerox/storage"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+	tradelogclient "github.com/KyberNetwork/reserve-stats/tradelogs/client"
+)
+
+const (
+	cexTradesDBFlag      = "cex-trades-database"
+	cexWithdrawalsDBFlag = "cex-withdrawals-database"
+	transactionsDBFlag   = "transactions-database"
+	fromFlag             = "from"
+	lookbackFlag         = "lookback"
+	intervalFlag         = "interval"
+	matchWindowFlag      = "match-window"
+
+	defaultLookback = 24 * time.Hour
+	defaultInterval = time.Hour
+	// batchDuration is the time range of source records posted at once.
+	batchDuration = 24 * time.Hour
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "accounting-ledger-poster"
+	app.Usage = "post trades, fees, gas, deposits and withdrawals to the reserve ledger periodically"
+	app.Action = run
+	app.Version = "0.0.1"
+
+	app.Flags = append(app.Flags,
+		cli.StringFlag{
+			Name:   cexTradesDBFlag,
+			Usage:  "database of CEX trades, 0x trades and accounting-cex-fetcher, using the same PostgreSQL connection flags",
+			EnvVar: "CEX_TRADES_DATABASE",
+			Value:  common.DefaultCexTradesDB,
+		},
+		cli.StringFlag{
+			Name:   cexWithdrawalsDBFlag,
+			Usage:  "database of Binance and Huobi deposits and withdrawals, using the same PostgreSQL connection flags",
+			EnvVar: "CEX_WITHDRAWALS_DATABASE",
+			Value:  common.DefaultCexWithdrawalsDB,
+		},
+		cli.StringFlag{
+			Name:   transactionsDBFlag,
+			Usage:  "database of accounting-reserve-transaction-fetcher, using the same PostgreSQL connection flags",
+			EnvVar: "TRANSACTIONS_DATABASE",
+			Value:  common.DefaultTransactionsDB,
+		},
+		cli.Uint64Flag{
+			Name:   fromFlag,
+			Usage:  "timestamp in milliseconds to post source records from, required if the ledger is empty",
+			EnvVar: "FROM",
+		},
+		cli.DurationFlag{
+			Name:   lookbackFlag,
+			Usage:  "duration before the latest posted record to post again, for records fetched late or completed late",
+			EnvVar: "LOOKBACK",
+			Value:  defaultLookback,
+		},
+		cli.DurationFlag{
+			Name:   intervalFlag,
+			Usage:  "The duration between postings, records are posted once if it is 0",
+			EnvVar: "INTERVAL",
+			Value:  defaultInterval,
+		},
+		cli.DurationFlag{
+			Name:   matchWindowFlag,
+			Usage:  "maximum duration between records of a transfer without tx hash",
+			EnvVar: "MATCH_WINDOW",
+			Value:  6 * time.Hour,
+		},
+		blockchain.NewEthereumNodeFlags(),
+	)
+	app.Flags = append(app.Flags, client.NewClientFlags()...)
+	app.Flags = append(app.Flags, tradelogclient.NewTradeLogCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultLedgerDB)...)
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newDB(c *cli.Context, sugar *zap.SugaredLogger, database string, closers *[]func()) (*sqlx.DB, error) {
+	db, err := libapp.NewDBWithDatabaseFromContext(c, database)
+	if err != nil {
+		return nil, err
+	}
+	*closers = append(*closers, func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorf("failed to close database %s: err=%s", database, cErr.Error())
+		}
+	})
+	return db, nil
+}
+
+func newPoster(c *cli.Context, sugar *zap.SugaredLogger, ledger ledgerstorage.Interface, closers *[]func()) (*poster.Poster, error) {
+	tradesDB, err := newDB(c, sugar, c.String(cexTradesDBFlag), closers)
+	if err != nil {
+		return nil, err
+	}
+	transfersDB, err := newDB(c, sugar, c.String(cexWithdrawalsDBFlag), closers)
+	if err != nil {
+		return nil, err
+	}
+	txDB, err := newDB(c, sugar, c.String(transactionsDBFlag), closers)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err := tradestorage.NewDB(sugar, tradesDB)
+	if err != nil {
+		return nil, err
+	}
+	hs, err := huobistorage.NewDB(sugar, tradesDB)
+	if err != nil {
+		return nil, err
+	}
+	cs, err := cexstorage.NewStorage(sugar, tradesDB)
+	if err != nil {
+		return nil, err
+	}
+	zs, err := zeroxstorage.NewZeroxStorage(tradesDB, sugar)
+	if err != nil {
+		return nil, err
+	}
+	binanceDeposits, err := depositstorage.NewDB(sugar, transfersDB)
+	if err != nil {
+		return nil, err
+	}
+	binanceWithdrawals, err := withdrawalstorage.NewDB(sugar, transfersDB)
+	if err != nil {
+		return nil, err
+	}
+	huobiDeposits, err := huobideposit.NewDB(sugar, transfersDB)
+	if err != nil {
+		return nil, err
+	}
+	huobiWithdrawals, err := huobiwithdrawal.NewDB(sugar, transfersDB)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := txstorage.NewStorage(sugar, txDB)
+	if err != nil {
+		return nil, err
+	}
+	addressClient, err := client.NewClientFromContext(c, sugar)
+	if err != nil {
+		return nil, err
+	}
+	formatter, err := blockchain.NewToKenAmountFormatterFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+	symbols, err := blockchain.NewTokenInfoGetterFromContext(c, nil)
+	if err != nil {
+		return nil, err
+	}
+	tradeLogClient, err := tradelogclient.NewClientFromContext(sugar, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return poster.NewPoster(sugar, ledger,
+		pnlstorage.NewTrades(sugar, bs, hs, cs, zs,
+			pnlstorage.WithReserveTrades(pnlstorage.NewReserveTrades(sugar, tradeLogClient, addressClient, symbols, formatter)),
+		),
+		matcherstorage.NewCEXTransfers(sugar, binanceDeposits, binanceWithdrawals, huobiDeposits, huobiWithdrawals, cs),
+		matcherstorage.NewOnChainTransfers(sugar, ts, addressClient, symbols, formatter),
+		ledgerstorage.NewGas(sugar, ts, addressClient, formatter),
+		matchercommon.NewMatcher(matchercommon.WithMatchWindow(c.Duration(matchWindowFlag))),
+		symbols,
+	), nil
+}
+
+// post posts the source records from the latest posted record minus lookback to now, in batches.
+func post(sugar *zap.SugaredLogger, ledger ledgerstorage.Interface, p *poster.Poster, initialFrom time.Time, lookback time.Duration) error {
+	last, err := ledger.GetLastOccurredAt()
+	if err != nil {
+		return err
+	}
+	from := initialFrom
+	if !last.IsZero() {
+		from = last.Add(-lookback)
+	}
+	if from.IsZero() {
+		return fmt.Errorf("ledger is empty, %s flag is required", fromFlag)
+	}
+
+	to := time.Now()
+	for start := from; start.Before(to); start = start.Add(batchDuration) {
+		end := start.Add(batchDuration)
+		if end.After(to) {
+			end = to
+		}
+		if _, err = p.Post(start, end); err != nil {
+			return err
+		}
+	}
+	sugar.Infow("records posted", "from", from, "to", to)
+	return nil
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	var closers []func()
+	defer func() {
+		for _, closer := range closers {
+			closer()
+		}
+	}()
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+	closers = append(closers, func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorf("failed to close database: err=%s", cErr.Error())
+		}
+	})
+	ledger, err := ledgerpostgres.NewStorage(sugar, db)
+	if err != nil {
+		return err
+	}
+	p, err := newPoster(c, sugar, ledger, &closers)
+	if err != nil {
+		return err
+	}
+
+	var (
+		initialFrom time.Time
+		lookback    = c.Duration(lookbackFlag)
+		interval    = c.Duration(intervalFlag)
+	)
+	if ms := c.Uint64(fromFlag); ms != 0 {
+		initialFrom = timeutil.TimestampMsToTime(ms)
+	}
+	if lookback < 0 {
+		return errors.New("lookback must not be negative")
+	}
+	if interval == 0 {
+		return post(sugar, ledger, p, initialFrom, lookback)
+	}
+
+	ticker := time.NewTicker(interval)
+	for ; true; <-ticker.C {
+		// a failed posting is retried at the next interval from the latest posted record
+		if err = post(sugar, ledger, p, initialFrom, lookback); err != nil {
+			sugar.Errorw("failed to post records", "error", err)
+		}
+		sugar.Info("Done. Wait for next posting")
+	}
+	return nil
+}
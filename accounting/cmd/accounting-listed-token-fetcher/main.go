@@ -5,7 +5,9 @@ import (
 	"log"
 	"math/big"
 	"os"
+	"time"
 
+	ethereum "github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli"
 
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
@@ -14,12 +16,15 @@ import (
 	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/client"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/contracts"
 	"github.com/KyberNetwork/reserve-stats/lib/etherscan"
 )
 
 const (
 	blockFlag          = "block"
 	reserveAddressFlag = "reserve-address"
+	fromBlockFlag      = "from-block"
+	backfillStepFlag   = "backfill-step"
 )
 
 func main() {
@@ -38,6 +43,16 @@ func main() {
 			EnvVar: "RESERVE_ADDRESS",
 			Usage:  "reserve address to get listed token",
 		},
+		cli.Uint64Flag{
+			Name:   fromBlockFlag,
+			EnvVar: "FROM_BLOCK",
+			Usage:  "backfill listed tokens history from this block to block from ListReservePairs events of KyberStorage, disabled if not provided",
+		},
+		cli.Uint64Flag{
+			Name:   backfillStepFlag,
+			EnvVar: "BACKFILL_STEP",
+			Usage:  "also backfill listed tokens history from reserve contracts every this number of blocks, disabled if not provided",
+		},
 	)
 	app.Flags = append(app.Flags, blockchain.NewEthereumNodeFlags())
 	app.Flags = append(app.Flags, etherscan.NewCliFlags()...)
@@ -48,9 +63,22 @@ func main() {
 	}
 }
 
+// tokenAddresses returns current and old addresses of listed tokens.
+func tokenAddresses(listedTokens []common.ListedToken) []ethereum.Address {
+	var result []ethereum.Address
+	for _, token := range listedTokens {
+		result = append(result, token.Address)
+		for _, old := range token.Old {
+			result = append(result, old.Address)
+		}
+	}
+	return result
+}
+
 func run(c *cli.Context) error {
 	var (
 		block         *big.Int
+		blockTime     time.Time
 		addressClient client.Interface
 	)
 	sugar, flush, err := libapp.NewSugaredLogger(c)
@@ -71,11 +99,17 @@ func run(c *cli.Context) error {
 			log.Fatal(err)
 		}
 		block = header.Number
+		blockTime = time.Unix(int64(header.Time), 0).UTC()
 	} else {
 		block, err = libapp.ParseBigIntFlag(c, blockFlag)
 		if err != nil {
 			return err
 		}
+		header, err := ethClient.HeaderByNumber(context.Background(), block)
+		if err != nil {
+			return err
+		}
+		blockTime = time.Unix(int64(header.Time), 0).UTC()
 	}
 
 	addrs := c.StringSlice(reserveAddressFlag)
@@ -139,6 +173,33 @@ func run(c *cli.Context) error {
 		if err = listedTokenStorage.CreateOrUpdate(listedTokens, block, addr.Address); err != nil {
 			return err
 		}
+
+		if _, err = listedTokenStorage.SaveSnapshot(common.ListedTokensSnapshot{
+			Reserve:     addr.Address,
+			BlockNumber: block.Uint64(),
+			Timestamp:   blockTime,
+			Tokens:      tokenAddresses(listedTokens),
+			Source:      common.ListedTokensSourceReserve,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if fromBlock := c.Uint64(fromBlockFlag); fromBlock != 0 {
+		var reserves []ethereum.Address
+		for _, addr := range reserveAddrs {
+			reserves = append(reserves, addr.Address)
+		}
+		backfiller := fetcher.NewBackfiller(sugar, ethClient, f, listedTokenStorage,
+			contracts.KyberStorageContractAddress().MustGetFromContext(c))
+		if step := c.Uint64(backfillStepFlag); step != 0 {
+			if err = backfiller.BackfillFromReserve(reserves, fromBlock, block.Uint64(), step); err != nil {
+				return err
+			}
+		}
+		if err = backfiller.BackfillFromKyberStorage(reserves, fromBlock, block.Uint64()); err != nil {
+			return err
+		}
 	}
 
 	return listedTokenStorage.Close()
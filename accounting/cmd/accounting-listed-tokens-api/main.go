@@ -11,6 +11,7 @@ import (
 	"github.com/KyberNetwork/reserve-stats/accounting/listed-tokens/storage"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 func main() {
@@ -20,6 +21,7 @@ func main() {
 	app.Action = run
 
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingReserveTokensPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultListedTokenDB)...)
 
 	if err := app.Run(os.Args); err != nil {
@@ -50,7 +52,7 @@ func run(c *cli.Context) error {
 		}
 	}()
 
-	s := server.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), listedTokenStorage)
+	s := server.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), listedTokenStorage, openapi.NewOptionsFromContext(c)...)
 	if err = s.Run(); err != nil {
 		return err
 	}
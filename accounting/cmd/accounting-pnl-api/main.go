@@ -0,0 +1,217 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/depositstorage"
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/tradestorage"
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/withdrawalstorage"
+	_ "github.com/KyberNetwork/reserve-stats/accounting/cex/okx" // register okx adapter
+	cexstorage "github.com/KyberNetwork/reserve-stats/accounting/cex/storage/postgres"
+	commissionstorage "github.com/KyberNetwork/reserve-stats/accounting/commission/storage/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	huobideposit "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/deposit-history/postgres"
+	huobistorage "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/postgres"
+	huobiwithdrawal "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/withdrawal-history/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/http"
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/storage"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/client"
+	rrpostgres "github.com/KyberNetwork/reserve-stats/accounting/reserve-rate/storage/postgres"
+	txstorage "github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage/postgres"
+	matcherstorage "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/storage"
+	zeroxstorage "github.com/KyberNetwork/reserve-stats/accounting/zerox/storage"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+	tradelogclient "github.com/KyberNetwork/reserve-stats/tradelogs/client"
+)
+
+const (
+	reserveRatesDBFlag   = "reserve-rates-database"
+	transactionsDBFlag   = "transactions-database"
+	cexWithdrawalsDBFlag = "cex-withdrawals-database"
+	inventoryFromFlag    = "inventory-from"
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "accounting-pnl-api"
+	app.Usage = "serve daily inventory and PnL statements of reserve addresses and CEX accounts"
+	app.Action = run
+	app.Version = "0.0.1"
+
+	app.Flags = append(app.Flags,
+		cli.StringFlag{
+			Name:   reserveRatesDBFlag,
+			Usage:  "database of accounting-reserve-rate-fetcher, using the same PostgreSQL connection flags",
+			EnvVar: "RESERVE_RATES_DATABASE",
+			Value:  common.DefaultReserveRatesDB,
+		},
+		cli.StringFlag{
+			Name:   transactionsDBFlag,
+			Usage:  "database of accounting-reserve-transaction-fetcher, using the same PostgreSQL connection flags",
+			EnvVar: "TRANSACTIONS_DATABASE",
+			Value:  common.DefaultTransactionsDB,
+		},
+		cli.StringFlag{
+			Name:   cexWithdrawalsDBFlag,
+			Usage:  "database of Binance and Huobi deposits and withdrawals, using the same PostgreSQL connection flags",
+			EnvVar: "CEX_WITHDRAWALS_DATABASE",
+			Value:  common.DefaultCexWithdrawalsDB,
+		},
+		cli.Uint64Flag{
+			Name:   inventoryFromFlag,
+			Usage:  "timestamp in milliseconds the inventory is built from, default is the from time of each query",
+			EnvVar: "INVENTORY_FROM",
+		},
+		blockchain.NewEthereumNodeFlags(),
+	)
+	app.Flags = append(app.Flags, client.NewClientFlags()...)
+	app.Flags = append(app.Flags, tradelogclient.NewTradeLogCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexTradesDB)...)
+	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingPnLPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorf("failed to close database: err=%s", cErr.Error())
+		}
+	}()
+
+	ratesDB, err := libapp.NewDBWithDatabaseFromContext(c, c.String(reserveRatesDBFlag))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := ratesDB.Close(); cErr != nil {
+			sugar.Errorf("failed to close reserve rates database: err=%s", cErr.Error())
+		}
+	}()
+
+	txDB, err := libapp.NewDBWithDatabaseFromContext(c, c.String(transactionsDBFlag))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := txDB.Close(); cErr != nil {
+			sugar.Errorf("failed to close transactions database: err=%s", cErr.Error())
+		}
+	}()
+
+	withdrawalsDB, err := libapp.NewDBWithDatabaseFromContext(c, c.String(cexWithdrawalsDBFlag))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := withdrawalsDB.Close(); cErr != nil {
+			sugar.Errorf("failed to close cex withdrawals database: err=%s", cErr.Error())
+		}
+	}()
+
+	hs, err := huobistorage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	bs, err := tradestorage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	cs, err := cexstorage.NewStorage(sugar, db)
+	if err != nil {
+		return err
+	}
+	zs, err := zeroxstorage.NewZeroxStorage(db, sugar)
+	if err != nil {
+		return err
+	}
+	cms, err := commissionstorage.NewStorage(sugar, db)
+	if err != nil {
+		return err
+	}
+	rs, err := rrpostgres.NewDB(sugar, ratesDB)
+	if err != nil {
+		return err
+	}
+
+	binanceDeposits, err := depositstorage.NewDB(sugar, withdrawalsDB)
+	if err != nil {
+		return err
+	}
+	binanceWithdrawals, err := withdrawalstorage.NewDB(sugar, withdrawalsDB)
+	if err != nil {
+		return err
+	}
+	huobiDeposits, err := huobideposit.NewDB(sugar, withdrawalsDB)
+	if err != nil {
+		return err
+	}
+	huobiWithdrawals, err := huobiwithdrawal.NewDB(sugar, withdrawalsDB)
+	if err != nil {
+		return err
+	}
+	ts, err := txstorage.NewStorage(sugar, txDB)
+	if err != nil {
+		return err
+	}
+	addressClient, err := client.NewClientFromContext(c, sugar)
+	if err != nil {
+		return err
+	}
+	tradeLogClient, err := tradelogclient.NewClientFromContext(sugar, c)
+	if err != nil {
+		return err
+	}
+	formatter, err := blockchain.NewToKenAmountFormatterFromContext(c)
+	if err != nil {
+		return err
+	}
+	symbols, err := blockchain.NewTokenInfoGetterFromContext(c, nil)
+	if err != nil {
+		return err
+	}
+
+	var inventoryFrom time.Time
+	if ms := c.Uint64(inventoryFromFlag); ms != 0 {
+		inventoryFrom = timeutil.TimestampMsToTime(ms)
+	}
+
+	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c),
+		storage.NewTrades(sugar, bs, hs, cs, zs,
+			storage.WithCommissions(cms),
+			storage.WithReserveTrades(storage.NewReserveTrades(sugar, tradeLogClient, addressClient, symbols, formatter)),
+		),
+		storage.NewTransfers(sugar, ts, addressClient, symbols, formatter,
+			storage.WithCEXTransfers(matcherstorage.NewCEXTransfers(sugar, binanceDeposits, binanceWithdrawals,
+				huobiDeposits, huobiWithdrawals, cs)),
+		),
+		storage.NewReserveRates(sugar, rs),
+		inventoryFrom,
+		openapi.NewOptionsFromContext(c)...,
+	)
+	return s.Run()
+}
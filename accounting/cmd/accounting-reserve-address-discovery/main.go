@@ -0,0 +1,73 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/discovery"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/storage/postgresql"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/etherscan"
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "accounting-reserve-address-discovery"
+	app.Usage = "propose operators, alerters and admins of registered reserves from their contracts for review"
+	app.Action = run
+	app.Flags = append(app.Flags, blockchain.NewEthereumNodeFlags())
+	app.Flags = append(app.Flags, etherscan.NewCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultReserveAddressesDB)...)
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	ethClient, err := blockchain.NewEthereumClientFromFlag(c)
+	if err != nil {
+		return err
+	}
+
+	etherscanClient, err := etherscan.NewEtherscanClientFromContext(c)
+	if err != nil {
+		return err
+	}
+	resolv := blockchain.NewEtherscanContractTimestampResolver(sugar, etherscanClient)
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorw("failed to close database", "error", cErr)
+		}
+	}()
+
+	st, err := postgresql.NewStorage(sugar, db, resolv)
+	if err != nil {
+		return err
+	}
+
+	stored, err := discovery.NewDiscoverer(sugar, discovery.NewNodeContractReader(ethClient), st).Run()
+	if err != nil {
+		return err
+	}
+	sugar.Infow("address discovery done", "new proposals", stored)
+	return nil
+}
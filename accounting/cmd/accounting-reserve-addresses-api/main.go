@@ -13,6 +13,7 @@ import (
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 func main() {
@@ -24,6 +25,7 @@ func main() {
 
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultReserveAddressesDB)...)
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingReserveAddressPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 	app.Flags = append(app.Flags, etherscan.NewCliFlags()...)
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
@@ -58,7 +60,7 @@ func run(c *cli.Context) error {
 		return err
 	}
 
-	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), st)
+	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), st, openapi.NewOptionsFromContext(c)...)
 
 	return s.Run()
 }
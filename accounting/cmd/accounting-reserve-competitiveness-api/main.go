@@ -0,0 +1,93 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/tradestorage"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	huobistorage "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-competitiveness/http"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-competitiveness/storage"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+const (
+	reserveRatesDBFlag    = "reserve-rates-database"
+	defaultReserveRatesDB = "reserve_rates"
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "accounting-reserve-competitiveness-api"
+	app.Usage = "compare reserve quoted rates with CEX market prices"
+	app.Action = run
+	app.Version = "0.0.1"
+
+	app.Flags = append(app.Flags,
+		cli.StringFlag{
+			Name:   reserveRatesDBFlag,
+			Usage:  "database of reserve-rates-crawler, using the same PostgreSQL connection flags",
+			EnvVar: "RESERVE_RATES_DATABASE",
+			Value:  defaultReserveRatesDB,
+		},
+	)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexTradesDB)...)
+	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingReserveCompetitivenessPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorf("failed to close database: err=%s", cErr.Error())
+		}
+	}()
+
+	ratesDB, err := libapp.NewDBWithDatabaseFromContext(c, c.String(reserveRatesDBFlag))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := ratesDB.Close(); cErr != nil {
+			sugar.Errorf("failed to close reserve rates database: err=%s", cErr.Error())
+		}
+	}()
+
+	hs, err := huobistorage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	bs, err := tradestorage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+
+	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c),
+		storage.NewReserveRatesDB(sugar, ratesDB),
+		storage.NewCEXMarket(sugar, bs, hs),
+		openapi.NewOptionsFromContext(c)...,
+	)
+	return s.Run()
+}
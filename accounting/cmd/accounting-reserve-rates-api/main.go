@@ -11,6 +11,7 @@ import (
 	rrpostgres "github.com/KyberNetwork/reserve-stats/accounting/reserve-rate/storage/postgres"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 func newServerCli() *cli.App {
@@ -18,6 +19,7 @@ func newServerCli() *cli.App {
 	app.Name = "reserve-rates-api"
 	app.Usage = "server for query accounting reserve rate API"
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingReserveRatesPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultReserveRatesDB)...)
 	app.Action = run
 	return app
@@ -49,7 +51,7 @@ func run(c *cli.Context) error {
 		}
 	}()
 	hostStr := httputil.NewHTTPAddressFromContext(c)
-	server, err := http.NewServer(hostStr, ratesStorage, sugar)
+	server, err := http.NewServer(hostStr, ratesStorage, sugar, openapi.NewOptionsFromContext(c)...)
 	if err != nil {
 		return err
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage/postgres"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 func main() {
@@ -22,6 +23,7 @@ func main() {
 
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultTransactionsDB)...)
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingTransactionsPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
 	}
@@ -54,7 +56,7 @@ func run(c *cli.Context) error {
 		}
 	}()
 
-	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), rts)
+	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), rts, openapi.NewOptionsFromContext(c)...)
 
 	if err = s.Run(); err != nil {
 		return err
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/urfave/cli"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/http"
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/storage"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "accounting-token-params-api"
+	app.Usage = "serve history of token pricing parameters in ConversionRates contracts of reserves"
+	app.Action = run
+
+	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingTokenParamsPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultTokenParamsDB)...)
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+	st, err := storage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := st.Close(); cErr != nil {
+			sugar.Errorw("failed to close database", "error", cErr)
+		}
+	}()
+
+	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c), st, openapi.NewOptionsFromContext(c)...)
+	return s.Run()
+}
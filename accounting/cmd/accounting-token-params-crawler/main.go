@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/client"
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/fetcher"
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/storage"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/etherscan"
+)
+
+const (
+	fromBlockFlag      = "from-block"
+	toBlockFlag        = "to-block"
+	reserveAddressFlag = "reserve-address"
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "accounting-token-params-crawler"
+	app.Usage = "store token pricing parameters of reserves whenever ConversionRates setters are called"
+	app.Action = run
+	app.Flags = append(app.Flags,
+		cli.Uint64Flag{
+			Name:   fromBlockFlag,
+			EnvVar: "FROM_BLOCK",
+			Usage:  "block to store initial token params of reserves never crawled, default to to-block",
+		},
+		cli.Uint64Flag{
+			Name:   toBlockFlag,
+			EnvVar: "TO_BLOCK",
+			Usage:  "block to crawl token params to, default to latest block",
+		},
+		cli.StringSliceFlag{
+			Name:   reserveAddressFlag,
+			EnvVar: "RESERVE_ADDRESS",
+			Usage:  "reserve address to crawl token params, default to reserves from accounting-reserve-addresses service",
+		},
+	)
+	app.Flags = append(app.Flags, blockchain.NewEthereumNodeFlags())
+	app.Flags = append(app.Flags, etherscan.NewCliFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultTokenParamsDB)...)
+	app.Flags = append(app.Flags, client.NewClientFlags()...)
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	ethClient, err := blockchain.NewEthereumClientFromFlag(c)
+	if err != nil {
+		return err
+	}
+
+	etherscanClient, err := etherscan.NewEtherscanClientFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	toBlock := c.Uint64(toBlockFlag)
+	if toBlock == 0 {
+		sugar.Info("no to block provided, crawl token params to latest block")
+		header, err := ethClient.HeaderByNumber(context.Background(), nil)
+		if err != nil {
+			return err
+		}
+		toBlock = header.Number.Uint64()
+	}
+	fromBlock := c.Uint64(fromBlockFlag)
+	if fromBlock == 0 {
+		fromBlock = toBlock
+	}
+
+	var reserves []ethereum.Address
+	if addrs := c.StringSlice(reserveAddressFlag); len(addrs) != 0 {
+		sugar.Infow("using provided addresses instead of querying from accounting-reserve-addresses service")
+		for _, addr := range addrs {
+			reserves = append(reserves, ethereum.HexToAddress(addr))
+		}
+	} else {
+		addressClient, err := client.NewClientFromContext(c, sugar)
+		if err != nil {
+			return err
+		}
+		reserveAddrs, err := addressClient.ReserveAddresses(common.Reserve)
+		if err != nil {
+			return err
+		}
+		for _, addr := range reserveAddrs {
+			reserves = append(reserves, addr.Address)
+		}
+	}
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+	st, err := storage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := st.Close(); cErr != nil {
+			sugar.Errorw("failed to close database", "error", cErr)
+		}
+	}()
+
+	source, err := fetcher.NewEtherscanSetterSource(sugar, etherscanClient)
+	if err != nil {
+		return err
+	}
+	crawler := fetcher.NewCrawler(sugar, fetcher.NewNodeReader(ethClient), source, st)
+	for _, reserve := range reserves {
+		if err = crawler.Crawl(reserve, fromBlock, toBlock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
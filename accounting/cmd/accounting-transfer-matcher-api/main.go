@@ -0,0 +1,175 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/depositstorage"
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/withdrawalstorage"
+	_ "github.com/KyberNetwork/reserve-stats/accounting/cex/okx" // register okx adapter
+	cexstorage "github.com/KyberNetwork/reserve-stats/accounting/cex/storage/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	huobideposit "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/deposit-history/postgres"
+	huobiwithdrawal "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage/withdrawal-history/postgres"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/client"
+	txstorage "github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage/postgres"
+	matchercommon "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/http"
+	"github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/storage"
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+const (
+	transactionsDBFlag = "transactions-database"
+	cexTradesDBFlag    = "cex-trades-database"
+	inFlightWindowFlag = "in-flight-window"
+	matchWindowFlag    = "match-window"
+	toleranceFlag      = "tolerance"
+)
+
+func main() {
+	app := libapp.NewApp()
+	app.Name = "accounting-transfer-matcher-api"
+	app.Usage = "match CEX deposits and withdrawals with on-chain transfers of reserve addresses"
+	app.Action = run
+	app.Version = "0.0.1"
+
+	app.Flags = append(app.Flags,
+		cli.StringFlag{
+			Name:   transactionsDBFlag,
+			Usage:  "database of accounting-reserve-transaction-fetcher, using the same PostgreSQL connection flags",
+			EnvVar: "TRANSACTIONS_DATABASE",
+			Value:  common.DefaultTransactionsDB,
+		},
+		cli.StringFlag{
+			Name:   cexTradesDBFlag,
+			Usage:  "database of accounting-cex-fetcher, using the same PostgreSQL connection flags",
+			EnvVar: "CEX_TRADES_DATABASE",
+			Value:  common.DefaultCexTradesDB,
+		},
+		cli.DurationFlag{
+			Name:   inFlightWindowFlag,
+			Usage:  "duration a transfer found on one side only is reported as in flight",
+			EnvVar: "IN_FLIGHT_WINDOW",
+			Value:  time.Hour,
+		},
+		cli.DurationFlag{
+			Name:   matchWindowFlag,
+			Usage:  "maximum duration between records of a transfer without tx hash",
+			EnvVar: "MATCH_WINDOW",
+			Value:  6 * time.Hour,
+		},
+		cli.Float64Flag{
+			Name:   toleranceFlag,
+			Usage:  "relative difference of amounts that are considered the same",
+			EnvVar: "TOLERANCE",
+			Value:  0.001,
+		},
+		blockchain.NewEthereumNodeFlags(),
+	)
+	app.Flags = append(app.Flags, client.NewClientFlags()...)
+	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(common.DefaultCexWithdrawalsDB)...)
+	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AccountingTransferMatcherPort)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	if err := libapp.Validate(c); err != nil {
+		return err
+	}
+
+	sugar, flush, err := libapp.NewSugaredLogger(c)
+	if err != nil {
+		return err
+	}
+	defer flush()
+
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := db.Close(); cErr != nil {
+			sugar.Errorf("failed to close database: err=%s", cErr.Error())
+		}
+	}()
+
+	txDB, err := libapp.NewDBWithDatabaseFromContext(c, c.String(transactionsDBFlag))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := txDB.Close(); cErr != nil {
+			sugar.Errorf("failed to close transactions database: err=%s", cErr.Error())
+		}
+	}()
+
+	cexDB, err := libapp.NewDBWithDatabaseFromContext(c, c.String(cexTradesDBFlag))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := cexDB.Close(); cErr != nil {
+			sugar.Errorf("failed to close cex trades database: err=%s", cErr.Error())
+		}
+	}()
+
+	binanceDeposits, err := depositstorage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	binanceWithdrawals, err := withdrawalstorage.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	huobiDeposits, err := huobideposit.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	huobiWithdrawals, err := huobiwithdrawal.NewDB(sugar, db)
+	if err != nil {
+		return err
+	}
+	cs, err := cexstorage.NewStorage(sugar, cexDB)
+	if err != nil {
+		return err
+	}
+	ts, err := txstorage.NewStorage(sugar, txDB)
+	if err != nil {
+		return err
+	}
+	addressClient, err := client.NewClientFromContext(c, sugar)
+	if err != nil {
+		return err
+	}
+	formatter, err := blockchain.NewToKenAmountFormatterFromContext(c)
+	if err != nil {
+		return err
+	}
+	symbols, err := blockchain.NewTokenInfoGetterFromContext(c, nil)
+	if err != nil {
+		return err
+	}
+
+	matcher := matchercommon.NewMatcher(
+		matchercommon.WithInFlightWindow(c.Duration(inFlightWindowFlag)),
+		matchercommon.WithMatchWindow(c.Duration(matchWindowFlag)),
+		matchercommon.WithTolerance(c.Float64(toleranceFlag)),
+	)
+	s := http.NewServer(sugar, httputil.NewHTTPAddressFromContext(c),
+		storage.NewCEXTransfers(sugar, binanceDeposits, binanceWithdrawals, huobiDeposits, huobiWithdrawals, cs),
+		storage.NewOnChainTransfers(sugar, ts, addressClient, symbols, formatter),
+		matcher,
+		openapi.NewOptionsFromContext(c)...,
+	)
+	return s.Run()
+}
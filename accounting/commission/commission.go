@@ -0,0 +1,142 @@
+// Package commission normalizes the commission of CEX trades to ETH and USD. Exchanges charge commission in
+// many assets (BNB, the quote or the base asset of the market), the normalized values make them comparable
+// and let PnL figures be reported net of fees.
+package commission
+
+import (
+	"encoding/json"
+	"time"
+
+	pnlcommon "github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// Commission is the commission of a stored CEX trade, valued at the time of the trade.
+type Commission struct {
+	// Source is the exchange of the trade, e.g. binance, huobi.
+	Source    string    `json:"source"`
+	Account   string    `json:"account"`
+	TradeID   string    `json:"trade_id"`
+	Timestamp time.Time `json:"timestamp"`
+	Asset     string    `json:"asset"`
+	Amount    float64   `json:"amount"`
+	ETH       float64   `json:"eth"`
+	USD       float64   `json:"usd"`
+	// PriceMissing is true if the asset or ETH has no rate at the time of the trade, the missing values
+	// are zero and resolved in later runs.
+	PriceMissing bool `json:"price_missing,omitempty"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Commission to format timestamp in unix millis.
+func (c Commission) MarshalJSON() ([]byte, error) {
+	type AliasCommission Commission
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasCommission
+	}{
+		AliasCommission: (AliasCommission)(c),
+		Timestamp:       timeutil.TimeToTimestampMs(c.Timestamp),
+	})
+}
+
+// RateProvider returns the rates commission is valued at. The bool result is false if the provider has
+// no rate of the asset at the time.
+type RateProvider interface {
+	ETHRate(asset string, at time.Time) (float64, bool, error)
+	ETHUSDRate(at time.Time) (float64, bool, error)
+}
+
+// HistoricalRates provides the daily rates of reserve-rate fetcher used by PnL statements.
+type HistoricalRates struct {
+	prices *pnlcommon.Prices
+}
+
+// NewHistoricalRates creates a HistoricalRates from PnL prices.
+func NewHistoricalRates(prices *pnlcommon.Prices) *HistoricalRates {
+	return &HistoricalRates{prices: prices}
+}
+
+// ETHRate returns the price of asset in ETH at the time.
+func (r *HistoricalRates) ETHRate(asset string, at time.Time) (float64, bool, error) {
+	price, ok := r.prices.ETHPrice(asset, at)
+	return price, ok, nil
+}
+
+// ETHUSDRate returns the price of ETH in USD at the time.
+func (r *HistoricalRates) ETHUSDRate(at time.Time) (float64, bool, error) {
+	price, ok := r.prices.ETHUSD(at)
+	return price, ok, nil
+}
+
+// Normalizer values commission with the first provider that has a rate.
+type Normalizer struct {
+	providers []RateProvider
+}
+
+// NewNormalizer creates a Normalizer from rate providers in order of preference.
+func NewNormalizer(providers ...RateProvider) *Normalizer {
+	return &Normalizer{providers: providers}
+}
+
+func (n *Normalizer) ethRate(asset string, at time.Time) (float64, bool, error) {
+	if pnlcommon.NormalizeToken(asset) == "ETH" {
+		return 1, true, nil
+	}
+	for _, provider := range n.providers {
+		rate, ok, err := provider.ETHRate(asset, at)
+		if err != nil {
+			return 0, false, err
+		}
+		if ok {
+			return rate, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+func (n *Normalizer) ethUSDRate(at time.Time) (float64, bool, error) {
+	for _, provider := range n.providers {
+		rate, ok, err := provider.ETHUSDRate(at)
+		if err != nil {
+			return 0, false, err
+		}
+		if ok {
+			return rate, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// Normalize returns the commission of trade valued in ETH and USD at the time of the trade.
+func (n *Normalizer) Normalize(trade pnlcommon.Trade) (Commission, error) {
+	result := Commission{
+		Source:    trade.Source,
+		Account:   trade.Account,
+		TradeID:   trade.ID,
+		Timestamp: trade.Timestamp,
+		Asset:     trade.FeeToken,
+		Amount:    trade.FeeAmount,
+	}
+	if trade.FeeAmount == 0 {
+		return result, nil
+	}
+	ethRate, ok, err := n.ethRate(trade.FeeToken, trade.Timestamp)
+	if err != nil {
+		return Commission{}, err
+	}
+	if !ok {
+		result.PriceMissing = true
+		return result, nil
+	}
+	result.ETH = trade.FeeAmount * ethRate
+	ethUSD, ok, err := n.ethUSDRate(trade.Timestamp)
+	if err != nil {
+		return Commission{}, err
+	}
+	if !ok {
+		result.PriceMissing = true
+		return result, nil
+	}
+	result.USD = result.ETH * ethUSD
+	return result, nil
+}
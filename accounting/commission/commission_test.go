@@ -0,0 +1,63 @@
+package commission
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pnlcommon "github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+)
+
+// stubRates has the ETH rates of assets and a ETH/USD rate at any time.
+type stubRates struct {
+	rates  map[string]float64
+	ethUSD float64
+}
+
+func (r stubRates) ETHRate(asset string, _ time.Time) (float64, bool, error) {
+	rate, ok := r.rates[asset]
+	return rate, ok, nil
+}
+
+func (r stubRates) ETHUSDRate(_ time.Time) (float64, bool, error) {
+	return r.ethUSD, r.ethUSD != 0, nil
+}
+
+func TestNormalize(t *testing.T) {
+	var (
+		ts    = time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+		trade = pnlcommon.Trade{ID: "KNCETH:1", Timestamp: ts, Source: "binance", Account: "binance_1"}
+		day   = time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+		// BNB is only in historical rates, ETH/USD is preferred from the first provider
+		historical = NewHistoricalRates(pnlcommon.NewPrices(
+			map[string]map[time.Time]float64{"BNB": {day: 0.13}, "KNC": {day: 0.0005}},
+			map[time.Time]float64{day: 1600},
+		))
+		n = NewNormalizer(stubRates{rates: map[string]float64{"KNC": 0.0004}, ethUSD: 1650}, historical)
+	)
+
+	tests := []struct {
+		asset  string
+		amount float64
+		want   Commission
+	}{
+		{asset: "KNC", amount: 10, want: Commission{ETH: 0.004, USD: 0.004 * 1650}},
+		{asset: "BNB", amount: 0.1, want: Commission{ETH: 0.1 * 0.13, USD: 0.1 * 0.13 * 1650}},
+		{asset: "WETH", amount: 0.5, want: Commission{ETH: 0.5, USD: 0.5 * 1650}},
+		{asset: "XYZ", amount: 1, want: Commission{PriceMissing: true}},
+		{asset: "XYZ", amount: 0, want: Commission{}},
+	}
+	for _, tc := range tests {
+		trade.FeeToken, trade.FeeAmount = tc.asset, tc.amount
+		result, err := n.Normalize(trade)
+		require.NoError(t, err)
+		assert.Equal(t, "binance", result.Source)
+		assert.Equal(t, "KNCETH:1", result.TradeID)
+		assert.Equal(t, tc.amount, result.Amount)
+		assert.InDelta(t, tc.want.ETH, result.ETH, 1e-12, tc.asset)
+		assert.InDelta(t, tc.want.USD, result.USD, 1e-9, tc.asset)
+		assert.Equal(t, tc.want.PriceMissing, result.PriceMissing, tc.asset)
+	}
+}
@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/commission"
+)
+
+// Interface is the storage of normalized commission of CEX trades, keyed by source, account and trade id.
+type Interface interface {
+	// UpdateCommissions stores commissions, commissions stored with missing price are replaced.
+	UpdateCommissions(commissions []commission.Commission) error
+	// GetCommissions returns the commissions of trades with timestamp in [from, to) sorted by timestamp.
+	GetCommissions(from, to time.Time) ([]commission.Commission, error)
+	// GetLastTimestamp returns the timestamp of the last stored commission, zero time if nothing is stored.
+	GetLastTimestamp() (time.Time, error)
+}
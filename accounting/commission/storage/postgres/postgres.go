@@ -0,0 +1,164 @@
+package postgres
+
+import (
+	_ "embed" // embed database schema
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/commission"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
+)
+
+//go:embed schema.sql
+var schema string
+
+// Storage is the PostgreSQL storage of normalized trade commissions.
+type Storage struct {
+	sugar *zap.SugaredLogger
+	db    *sqlx.DB
+}
+
+// NewStorage creates the storage and initializes the database schema.
+func NewStorage(sugar *zap.SugaredLogger, db *sqlx.DB) (*Storage, error) {
+	logger := sugar.With("func", caller.GetCurrentFunctionName())
+	logger.Debugw("initializing database schema", "query", schema)
+	if _, err := db.Exec(schema); err != nil {
+		return nil, err
+	}
+	return &Storage{sugar: sugar, db: db}, nil
+}
+
+// UpdateCommissions stores commissions. A stored commission is only replaced if its price was missing, so
+// rerunning the normalizer fills the gaps without revaluing resolved commissions.
+func (s *Storage) UpdateCommissions(commissions []commission.Commission) (err error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"number of commissions", len(commissions),
+		)
+		sources, accounts, tradeIDs, assets []string
+		timestamps                          []time.Time
+		amounts, eths, usds                 []float64
+		priceMissings                       []bool
+	)
+	if len(commissions) == 0 {
+		return nil
+	}
+	for _, c := range commissions {
+		sources = append(sources, c.Source)
+		accounts = append(accounts, c.Account)
+		tradeIDs = append(tradeIDs, c.TradeID)
+		timestamps = append(timestamps, c.Timestamp)
+		assets = append(assets, c.Asset)
+		amounts = append(amounts, c.Amount)
+		eths = append(eths, c.ETH)
+		usds = append(usds, c.USD)
+		priceMissings = append(priceMissings, c.PriceMissing)
+	}
+
+	const updateStmt = `INSERT INTO trade_commissions (source, account, trade_id, timestamp, asset, amount, eth, usd, price_missing)
+	VALUES (
+		unnest($1::TEXT[]),
+		unnest($2::TEXT[]),
+		unnest($3::TEXT[]),
+		unnest($4::TIMESTAMPTZ[]),
+		unnest($5::TEXT[]),
+		unnest($6::FLOAT8[]),
+		unnest($7::FLOAT8[]),
+		unnest($8::FLOAT8[]),
+		unnest($9::BOOLEAN[])
+	)
+	ON CONFLICT ON CONSTRAINT trade_commissions_pk DO UPDATE SET
+		timestamp = EXCLUDED.timestamp,
+		asset = EXCLUDED.asset,
+		amount = EXCLUDED.amount,
+		eth = EXCLUDED.eth,
+		usd = EXCLUDED.usd,
+		price_missing = EXCLUDED.price_missing
+	WHERE trade_commissions.price_missing;`
+	logger.Debugw("updating commissions", "query", updateStmt)
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+	_, err = tx.Exec(updateStmt,
+		pq.StringArray(sources),
+		pq.StringArray(accounts),
+		pq.StringArray(tradeIDs),
+		pq.Array(timestamps),
+		pq.StringArray(assets),
+		pq.Float64Array(amounts),
+		pq.Float64Array(eths),
+		pq.Float64Array(usds),
+		pq.BoolArray(priceMissings),
+	)
+	return err
+}
+
+type commissionDB struct {
+	Source       string    `db:"source"`
+	Account      string    `db:"account"`
+	TradeID      string    `db:"trade_id"`
+	Timestamp    time.Time `db:"timestamp"`
+	Asset        string    `db:"asset"`
+	Amount       float64   `db:"amount"`
+	ETH          float64   `db:"eth"`
+	USD          float64   `db:"usd"`
+	PriceMissing bool      `db:"price_missing"`
+}
+
+// GetCommissions returns the commissions of trades in time range sorted by timestamp.
+func (s *Storage) GetCommissions(from, to time.Time) ([]commission.Commission, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		dbResult []commissionDB
+		result   []commission.Commission
+	)
+	const selectStmt = `SELECT source, account, trade_id, timestamp, asset, amount, eth, usd, price_missing
+	FROM trade_commissions WHERE timestamp >= $1 AND timestamp < $2 ORDER BY timestamp, source, account, trade_id;`
+	logger.Debugw("querying commissions", "query", selectStmt)
+	if err := s.db.Select(&dbResult, selectStmt, from, to); err != nil {
+		return nil, err
+	}
+	for _, r := range dbResult {
+		result = append(result, commission.Commission{
+			Source:       r.Source,
+			Account:      r.Account,
+			TradeID:      r.TradeID,
+			Timestamp:    r.Timestamp,
+			Asset:        r.Asset,
+			Amount:       r.Amount,
+			ETH:          r.ETH,
+			USD:          r.USD,
+			PriceMissing: r.PriceMissing,
+		})
+	}
+	return result, nil
+}
+
+// GetLastTimestamp returns the timestamp of the last stored commission.
+func (s *Storage) GetLastTimestamp() (time.Time, error) {
+	var (
+		logger   = s.sugar.With("func", caller.GetCurrentFunctionName())
+		dbResult pq.NullTime
+	)
+	const selectStmt = `SELECT MAX(timestamp) FROM trade_commissions;`
+	logger.Debugw("querying last stored timestamp", "query", selectStmt)
+	if err := s.db.Get(&dbResult, selectStmt); err != nil {
+		return time.Time{}, err
+	}
+	if !dbResult.Valid {
+		return time.Time{}, nil
+	}
+	return dbResult.Time, nil
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq" // sql driver name: "postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/commission"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func TestStorage(t *testing.T) {
+	sugar := testutil.MustNewDevelopmentSugaredLogger()
+	db, teardown := testutil.MustNewDevelopmentDB()
+	defer func() {
+		assert.NoError(t, teardown())
+	}()
+
+	s, err := NewStorage(sugar, db)
+	require.NoError(t, err)
+
+	last, err := s.GetLastTimestamp()
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	commissions := []commission.Commission{
+		{
+			Source: "binance", Account: "binance_1", TradeID: "KNCETH:1",
+			Timestamp: timeutil.TimestampMsToTime(1696118678015),
+			Asset:     "BNB", Amount: 0.01, ETH: 0.0013, USD: 2.17,
+		},
+		{
+			Source: "huobi", Account: "huobi_1", TradeID: "2",
+			Timestamp: timeutil.TimestampMsToTime(1696118700120),
+			Asset:     "KNC", Amount: 1.5, PriceMissing: true,
+		},
+	}
+	require.NoError(t, s.UpdateCommissions(commissions))
+
+	// resolved commissions are kept, missing ones are replaced
+	revalued := []commission.Commission{commissions[0], commissions[1]}
+	revalued[0].ETH = 1
+	revalued[1].ETH, revalued[1].USD, revalued[1].PriceMissing = 0.0006, 1.0, false
+	require.NoError(t, s.UpdateCommissions(revalued))
+
+	stored, err := s.GetCommissions(commissions[0].Timestamp, commissions[1].Timestamp.Add(time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	assert.Equal(t, 0.0013, stored[0].ETH)
+	assert.Equal(t, "KNC", stored[1].Asset)
+	assert.Equal(t, 0.0006, stored[1].ETH)
+	assert.False(t, stored[1].PriceMissing)
+
+	last, err = s.GetLastTimestamp()
+	require.NoError(t, err)
+	assert.True(t, commissions[1].Timestamp.Equal(last))
+}
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS trade_commissions
+(
+    source        TEXT        NOT NULL,
+    account       TEXT        NOT NULL,
+    trade_id      TEXT        NOT NULL,
+    timestamp     TIMESTAMPTZ NOT NULL,
+    asset         TEXT        NOT NULL,
+    amount        FLOAT8      NOT NULL,
+    eth           FLOAT8      NOT NULL,
+    usd           FLOAT8      NOT NULL,
+    price_missing BOOLEAN     NOT NULL DEFAULT FALSE,
+    CONSTRAINT trade_commissions_pk PRIMARY KEY (source, account, trade_id)
+);
+CREATE INDEX IF NOT EXISTS trade_commissions_timestamp_idx ON trade_commissions (timestamp);
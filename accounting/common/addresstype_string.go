@@ -4,9 +4,9 @@ package common
 
 import "strconv"
 
-const _AddressType_name = "reservepricing_operatorsanity_operatorintermediate_operatorcex_deposit_addresscompany_walletdeposit_operator"
+const _AddressType_name = "reservepricing_operatorsanity_operatorintermediate_operatorcex_deposit_addresscompany_walletdeposit_operatorreserve_operatoralerteradmin"
 
-var _AddressType_index = [...]uint8{0, 7, 23, 38, 59, 78, 92, 108}
+var _AddressType_index = [...]uint8{0, 7, 23, 38, 59, 78, 92, 108, 124, 131, 136}
 
 func (i AddressType) String() string {
 	if i < 0 || i >= AddressType(len(_AddressType_index)-1) {
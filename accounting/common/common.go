@@ -9,4 +9,6 @@ const (
 	DefaultReserveRatesDB     = "reserve_rates"
 	DefaultTransactionsDB     = "transactions"
 	DefaultReserveAddressesDB = "reserve_addresses"
+	DefaultLedgerDB           = "ledger"
+	DefaultTokenParamsDB      = "token_params"
 )
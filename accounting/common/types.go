@@ -52,6 +52,12 @@ const (
 	CompanyWallet // company_wallet
 	//DepositOperator is Ethereum address used to deposit
 	DepositOperator // deposit_operator
+	// ReserveOperator is operator address of reserve contract.
+	ReserveOperator // reserve_operator
+	// Alerter is alerter address of reserve, conversion rates or sanity rates contract.
+	Alerter // alerter
+	// Admin is admin address of reserve, conversion rates or sanity rates contract.
+	Admin // admin
 )
 
 var validAddressTypes = map[string]AddressType{
@@ -62,6 +68,9 @@ var validAddressTypes = map[string]AddressType{
 	CEXDepositAddress.String():    CEXDepositAddress,
 	CompanyWallet.String():        CompanyWallet,
 	DepositOperator.String():      DepositOperator,
+	ReserveOperator.String():      ReserveOperator,
+	Alerter.String():              Alerter,
+	Admin.String():                Admin,
 }
 
 // IsValidAddressType returns true if given
@@ -77,25 +86,43 @@ type ReserveAddress struct {
 	Type        AddressType      `json:"type"`
 	Description string           `json:"description"`
 	Timestamp   time.Time        `json:"timestamp"`
+	// EffectiveFrom and EffectiveUntil are the period the address is used in accounting, zero values are
+	// unbounded. EffectiveUntil is set when the address is deleted.
+	EffectiveFrom  time.Time `json:"effective_from"`
+	EffectiveUntil time.Time `json:"effective_until"`
+}
+
+// millisOrNil returns the unix millis of t or nil if t is zero.
+func millisOrNil(t time.Time) *uint64 {
+	if t.IsZero() {
+		return nil
+	}
+	millis := timeutil.TimeToTimestampMs(t)
+	return &millis
+}
+
+// timeOrZero returns the time of unix millis or zero time if millis is nil.
+func timeOrZero(millis *uint64) time.Time {
+	if millis == nil {
+		return time.Time{}
+	}
+	return timeutil.TimestampMsToTime(*millis)
 }
 
 // MarshalJSON implements custom JSON marshaller for ReserveAddress to
 // format timestamp in unix millis instead of RFC3339.
 func (r *ReserveAddress) MarshalJSON() ([]byte, error) {
 	type AliasReserveAddress ReserveAddress
-
-	var ts *uint64
-	if !r.Timestamp.IsZero() {
-		millis := timeutil.TimeToTimestampMs(r.Timestamp)
-		ts = &millis
-	}
-
 	return json.Marshal(struct {
-		Timestamp *uint64 `json:"timestamp,omitempty"`
+		Timestamp      *uint64 `json:"timestamp,omitempty"`
+		EffectiveFrom  *uint64 `json:"effective_from,omitempty"`
+		EffectiveUntil *uint64 `json:"effective_until,omitempty"`
 		*AliasReserveAddress
 	}{
 		AliasReserveAddress: (*AliasReserveAddress)(r),
-		Timestamp:           ts,
+		Timestamp:           millisOrNil(r.Timestamp),
+		EffectiveFrom:       millisOrNil(r.EffectiveFrom),
+		EffectiveUntil:      millisOrNil(r.EffectiveUntil),
 	})
 }
 
@@ -104,16 +131,18 @@ func (r *ReserveAddress) MarshalJSON() ([]byte, error) {
 func (r *ReserveAddress) UnmarshalJSON(data []byte) error {
 	type AliasReserveAddress ReserveAddress
 	decoded := new(struct {
-		Timestamp *uint64 `json:"timestamp,omitempty"`
+		Timestamp      *uint64 `json:"timestamp,omitempty"`
+		EffectiveFrom  *uint64 `json:"effective_from,omitempty"`
+		EffectiveUntil *uint64 `json:"effective_until,omitempty"`
 		AliasReserveAddress
 	})
 
 	if err := json.Unmarshal(data, decoded); err != nil {
 		return err
 	}
-	if decoded.Timestamp != nil {
-		r.Timestamp = timeutil.TimestampMsToTime(*decoded.Timestamp)
-	}
+	r.Timestamp = timeOrZero(decoded.Timestamp)
+	r.EffectiveFrom = timeOrZero(decoded.EffectiveFrom)
+	r.EffectiveUntil = timeOrZero(decoded.EffectiveUntil)
 	r.ID = decoded.ID
 	r.Address = decoded.Address
 	r.Type = decoded.Type
@@ -121,6 +150,138 @@ func (r *ReserveAddress) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// EffectiveAt returns true if the address is used in accounting at the time.
+func (r *ReserveAddress) EffectiveAt(at time.Time) bool {
+	return (r.EffectiveFrom.IsZero() || !r.EffectiveFrom.After(at)) &&
+		(r.EffectiveUntil.IsZero() || r.EffectiveUntil.After(at))
+}
+
+// ReserveAddressAction is the kind of change of a reserve address version.
+type ReserveAddressAction string
+
+const (
+	// ReserveAddressCreated is the action of the first version of an address.
+	ReserveAddressCreated ReserveAddressAction = "create"
+	// ReserveAddressUpdated is the action of versions changing values of an address.
+	ReserveAddressUpdated ReserveAddressAction = "update"
+	// ReserveAddressDeleted is the action of versions ending the effective period of an address.
+	ReserveAddressDeleted ReserveAddressAction = "delete"
+)
+
+// ReserveAddressVersion is a stored change of a reserve address. Before is nil for created addresses.
+type ReserveAddressVersion struct {
+	Version   uint64               `json:"version"`
+	AddressID uint64               `json:"address_id"`
+	Action    ReserveAddressAction `json:"action"`
+	// ChangedBy is the API key id of the change, empty if the change is not made through gateway.
+	ChangedBy string          `json:"changed_by"`
+	ChangedAt time.Time       `json:"changed_at"`
+	Before    *ReserveAddress `json:"before"`
+	After     *ReserveAddress `json:"after"`
+}
+
+// MarshalJSON implements custom JSON marshaller for ReserveAddressVersion to
+// format timestamp in unix millis instead of RFC3339.
+func (v ReserveAddressVersion) MarshalJSON() ([]byte, error) {
+	type AliasReserveAddressVersion ReserveAddressVersion
+	return json.Marshal(struct {
+		ChangedAt uint64 `json:"changed_at"`
+		AliasReserveAddressVersion
+	}{
+		AliasReserveAddressVersion: (AliasReserveAddressVersion)(v),
+		ChangedAt:                  timeutil.TimeToTimestampMs(v.ChangedAt),
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaller for ReserveAddressVersion to
+// format timestamp in unix millis instead of RFC3339.
+func (v *ReserveAddressVersion) UnmarshalJSON(data []byte) error {
+	type AliasReserveAddressVersion ReserveAddressVersion
+	decoded := new(struct {
+		ChangedAt uint64 `json:"changed_at"`
+		*AliasReserveAddressVersion
+	})
+	decoded.AliasReserveAddressVersion = (*AliasReserveAddressVersion)(v)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	v.ChangedAt = timeutil.TimestampMsToTime(decoded.ChangedAt)
+	return nil
+}
+
+// AddressProposalAction is the change an address proposal makes to reserve addresses when approved.
+type AddressProposalAction string
+
+const (
+	// AddressProposalAdd is the action of proposals creating an address found in contracts.
+	AddressProposalAdd AddressProposalAction = "add"
+	// AddressProposalRemove is the action of proposals deleting an address no longer found in contracts.
+	AddressProposalRemove AddressProposalAction = "remove"
+)
+
+// AddressProposalStatus is the review status of an address proposal.
+type AddressProposalStatus string
+
+const (
+	// AddressProposalPending is the status of proposals waiting for review.
+	AddressProposalPending AddressProposalStatus = "pending"
+	// AddressProposalApproved is the status of proposals applied to reserve addresses.
+	AddressProposalApproved AddressProposalStatus = "approved"
+	// AddressProposalRejected is the status of rejected proposals, they are not proposed again.
+	AddressProposalRejected AddressProposalStatus = "rejected"
+)
+
+// AddressProposal is a change of reserve addresses found by reading reserve contracts, it is applied
+// after being approved.
+type AddressProposal struct {
+	ID      uint64                `json:"id"`
+	Action  AddressProposalAction `json:"action"`
+	Address ethereum.Address      `json:"address"`
+	Type    AddressType           `json:"type"`
+	// AddressID is the id of the stored address to delete by remove proposals, or the deleted address to
+	// restore by add proposals.
+	AddressID   uint64                `json:"address_id,omitempty"`
+	Description string                `json:"description"`
+	Status      AddressProposalStatus `json:"status"`
+	ProposedAt  time.Time             `json:"proposed_at"`
+	// DecidedBy is the API key id approving or rejecting the proposal.
+	DecidedBy string    `json:"decided_by,omitempty"`
+	DecidedAt time.Time `json:"decided_at"`
+}
+
+// MarshalJSON implements custom JSON marshaller for AddressProposal to
+// format timestamp in unix millis instead of RFC3339.
+func (p *AddressProposal) MarshalJSON() ([]byte, error) {
+	type AliasAddressProposal AddressProposal
+	return json.Marshal(struct {
+		ProposedAt *uint64 `json:"proposed_at,omitempty"`
+		DecidedAt  *uint64 `json:"decided_at,omitempty"`
+		*AliasAddressProposal
+	}{
+		AliasAddressProposal: (*AliasAddressProposal)(p),
+		ProposedAt:           millisOrNil(p.ProposedAt),
+		DecidedAt:            millisOrNil(p.DecidedAt),
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaller for AddressProposal to
+// format timestamp in unix millis instead of RFC3339.
+func (p *AddressProposal) UnmarshalJSON(data []byte) error {
+	type AliasAddressProposal AddressProposal
+	decoded := new(struct {
+		ProposedAt *uint64 `json:"proposed_at,omitempty"`
+		DecidedAt  *uint64 `json:"decided_at,omitempty"`
+		*AliasAddressProposal
+	})
+	decoded.AliasAddressProposal = (*AliasAddressProposal)(p)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	p.ProposedAt = timeOrZero(decoded.ProposedAt)
+	p.DecidedAt = timeOrZero(decoded.DecidedAt)
+	return nil
+}
+
 //OldListedToken is information of an old token
 type OldListedToken struct {
 	Address   ethereum.Address `json:"address"`
@@ -158,9 +319,115 @@ func (l *ListedToken) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// ListedTokensSnapshot is the set of tokens listed in a reserve from a block, until the block of the next
+// snapshot of the reserve.
+type ListedTokensSnapshot struct {
+	// Version identifies the snapshot, versions of a reserve increase with block numbers. Backfilling a
+	// snapshot before stored ones increases versions of the later snapshots.
+	Version     uint64             `json:"version"`
+	Reserve     ethereum.Address   `json:"reserve"`
+	BlockNumber uint64             `json:"block_number"`
+	Timestamp   time.Time          `json:"timestamp"`
+	Tokens      []ethereum.Address `json:"tokens"`
+	// Source is where the listed tokens are read from: reserve contract or KyberStorage events.
+	Source string `json:"source"`
+}
+
+// MarshalJSON implements custom JSON marshaller for ListedTokensSnapshot to
+// format timestamp in unix millis instead of RFC3339.
+func (s *ListedTokensSnapshot) MarshalJSON() ([]byte, error) {
+	type AliasListedTokensSnapshot ListedTokensSnapshot
+	return json.Marshal(struct {
+		Timestamp *uint64 `json:"timestamp,omitempty"`
+		*AliasListedTokensSnapshot
+	}{
+		AliasListedTokensSnapshot: (*AliasListedTokensSnapshot)(s),
+		Timestamp:                 millisOrNil(s.Timestamp),
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaller for ListedTokensSnapshot to
+// format timestamp in unix millis instead of RFC3339.
+func (s *ListedTokensSnapshot) UnmarshalJSON(data []byte) error {
+	type AliasListedTokensSnapshot ListedTokensSnapshot
+	decoded := new(struct {
+		Timestamp *uint64 `json:"timestamp,omitempty"`
+		*AliasListedTokensSnapshot
+	})
+	decoded.AliasListedTokensSnapshot = (*AliasListedTokensSnapshot)(s)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	s.Timestamp = timeOrZero(decoded.Timestamp)
+	return nil
+}
+
+// Sources of listed tokens snapshots.
+const (
+	// ListedTokensSourceReserve is the source of snapshots read from conversion rates contract of reserves.
+	ListedTokensSourceReserve = "reserve"
+	// ListedTokensSourceKyberStorage is the source of snapshots built from ListReservePairs events of KyberStorage.
+	ListedTokensSourceKyberStorage = "kyber_storage"
+)
+
+// ListingEvent is a token listed to or delisted from a reserve at a block.
+type ListingEvent struct {
+	Reserve     ethereum.Address `json:"reserve"`
+	Token       ethereum.Address `json:"token"`
+	Listed      bool             `json:"listed"`
+	BlockNumber uint64           `json:"block_number"`
+	Timestamp   time.Time        `json:"timestamp"`
+	// Version is the version of the snapshot the event is found in.
+	Version uint64 `json:"version"`
+	// Source is the source of the snapshots the event is found between.
+	Source string `json:"source"`
+}
+
+// MarshalJSON implements custom JSON marshaller for ListingEvent to
+// format timestamp in unix millis instead of RFC3339.
+func (e ListingEvent) MarshalJSON() ([]byte, error) {
+	type AliasListingEvent ListingEvent
+	return json.Marshal(struct {
+		Timestamp *uint64 `json:"timestamp,omitempty"`
+		AliasListingEvent
+	}{
+		AliasListingEvent: (AliasListingEvent)(e),
+		Timestamp:         millisOrNil(e.Timestamp),
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaller for ListingEvent to
+// format timestamp in unix millis instead of RFC3339.
+func (e *ListingEvent) UnmarshalJSON(data []byte) error {
+	type AliasListingEvent ListingEvent
+	decoded := new(struct {
+		Timestamp *uint64 `json:"timestamp,omitempty"`
+		*AliasListingEvent
+	})
+	decoded.AliasListingEvent = (*AliasListingEvent)(e)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	e.Timestamp = timeOrZero(decoded.Timestamp)
+	return nil
+}
+
+// ListedTokensDiff is the tokens added and removed between two snapshots of a reserve.
+type ListedTokensDiff struct {
+	Reserve     ethereum.Address   `json:"reserve"`
+	FromVersion uint64             `json:"from_version"`
+	ToVersion   uint64             `json:"to_version"`
+	Added       []ethereum.Address `json:"added"`
+	Removed     []ethereum.Address `json:"removed"`
+}
+
 // Account represent an account in binance, huobi
 type Account struct {
 	Name      string `json:"name"`
 	APIKey    string `json:"api_key"`
 	SecretKey string `json:"secret_key"`
+	// Email identifies a binance sub-account in the sub-account list of its master account.
+	Email string `json:"email,omitempty"`
+	// SubAccounts are binance sub-accounts of this master account.
+	SubAccounts []Account `json:"sub_accounts,omitempty"`
 }
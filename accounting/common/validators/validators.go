@@ -4,17 +4,18 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	"github.com/gin-gonic/gin/binding"
 	validator "gopkg.in/go-playground/validator.v8"
 )
 
 // isValidCEXName is a validator.Func function that returns true if given field
-// is a valid cex-trade name address.
+// is a valid cex-trade name address or the name of an exchange with registered adapter.
 func isValidCEXName(_ *validator.Validate, _ reflect.Value, _ reflect.Value,
 	field reflect.Value, _ reflect.Type, _ reflect.Kind, _ string) bool {
 	cexNameInput := strings.ToLower(field.String())
-	return common.IsValidCEXName(cexNameInput)
+	return common.IsValidCEXName(cexNameInput) || cex.IsRegistered(cexNameInput)
 }
 
 func init() {
@@ -0,0 +1,90 @@
+package fetcher
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+)
+
+func (fc *Fetcher) retryGetDeposit(symbol string, fromID uint64) (huobi.DepositHistoryList, error) {
+	var (
+		result huobi.DepositHistoryList
+		err    error
+		logger = fc.sugar.With("func", caller.GetCurrentFunctionName())
+	)
+	for i := 0; i < fc.attempt; i++ {
+		result, err = fc.client.GetDepositHistory(symbol, fromID)
+		if err == nil {
+			return result, nil
+		}
+		logger.Warnw("fail to fetch deposit history", "error", err, "attempt", i+1)
+
+		time.Sleep(fc.retryDelay)
+	}
+	return result, err
+}
+
+func (fc *Fetcher) getDepositHistoryWithSymbol(symbol string, fromID uint64) ([]huobi.DepositHistory, error) {
+	var (
+		nextFromID = fromID
+		result     []huobi.DepositHistory
+		logger     = fc.sugar.With("func", caller.GetCurrentFunctionName(), "symbol", symbol)
+	)
+	for {
+		depositHistoriesResponse, err := fc.retryGetDeposit(symbol, nextFromID)
+		if err != nil {
+			logger.Errorw("failed to get deposit history", "attempts", fc.attempt, "error", err)
+			return result, err
+		}
+
+		// while result != empty, get deposits from the latest ID
+		if len(depositHistoriesResponse.Data) == 0 {
+			break
+		}
+		result = append(result, depositHistoriesResponse.Data...)
+		lastDeposit := depositHistoriesResponse.Data[0]
+		nextFromID = lastDeposit.ID + 1
+	}
+
+	return result, nil
+}
+
+// GetDepositHistory return all deposit history of all currencies from fromID to the latest deposit
+func (fc *Fetcher) GetDepositHistory(fromID uint64) ([]huobi.DepositHistory, error) {
+	var (
+		logger = fc.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", fromID,
+		)
+		mu       sync.Mutex
+		result   []huobi.DepositHistory
+		errGroup errgroup.Group
+	)
+
+	symbols, err := fc.client.GetCurrencies()
+	if err != nil {
+		return nil, err
+	}
+	for _, sym := range symbols {
+		symbol := sym
+		errGroup.Go(func() error {
+			deposits, err := fc.getDepositHistoryWithSymbol(symbol, fromID)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			result = append(result, deposits...)
+			mu.Unlock()
+			logger.Infow("Fetching done", "symbol", symbol, "deposits", len(deposits))
+			return nil
+		})
+	}
+	if err := errGroup.Wait(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,14 @@
+package deposithistory
+
+import (
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+)
+
+// Interface is the storage for deposit history
+type Interface interface {
+	UpdateDepositHistory(deposits []huobi.DepositHistory, account string) error
+	GetDepositHistory(from, to time.Time) (map[string][]huobi.DepositHistory, error)
+	GetCursor(account string) (uint64, error)
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// HuobiStorage defines the object to store Huobi deposits
+type HuobiStorage struct {
+	sugar *zap.SugaredLogger
+	db    *sqlx.DB
+}
+
+// NewDB return the HuobiStorage instance. User must call Close() before exit.
+func NewDB(sugar *zap.SugaredLogger, db *sqlx.DB) (*HuobiStorage, error) {
+	var (
+		logger = sugar.With("func", caller.GetCurrentFunctionName())
+	)
+
+	const schemaFMT = `
+	CREATE TABLE IF NOT EXISTS huobi_deposits
+(
+	id        BIGINT NOT NULL,
+	account   TEXT NOT NULL,
+	timestamp TIMESTAMPTZ NOT NULL,
+	data      JSONB,
+	CONSTRAINT huobi_deposits_pk PRIMARY KEY(id)
+) ;
+
+CREATE INDEX IF NOT EXISTS huobi_deposits_timestamp_idx ON huobi_deposits (timestamp);
+`
+
+	hs := &HuobiStorage{
+		sugar: sugar,
+		db:    db,
+	}
+	logger.Debugw("initializing database schema", "query", schemaFMT)
+
+	if _, err := db.Exec(schemaFMT); err != nil {
+		return nil, err
+	}
+	logger.Debug("database schema initialized successfully")
+	return hs, nil
+}
+
+// Close close DB connection
+func (hdb *HuobiStorage) Close() error {
+	if hdb.db != nil {
+		return hdb.db.Close()
+	}
+	return nil
+}
+
+// UpdateDepositHistory stores the deposits of account, the stored ones are updated as their states change
+func (hdb *HuobiStorage) UpdateDepositHistory(deposits []huobi.DepositHistory, account string) (err error) {
+	var (
+		logger = hdb.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"len(deposits)", len(deposits),
+		)
+		ids        []uint64
+		dataJSON   [][]byte
+		timestamps []time.Time
+	)
+	const updateStmt = `INSERT INTO huobi_deposits(id, data, timestamp, account)
+	VALUES (
+		unnest($1::BIGINT[]),
+		unnest($2::JSONB[]),
+		unnest($3::TIMESTAMPTZ[]),
+		$4
+	)
+	ON CONFLICT ON CONSTRAINT huobi_deposits_pk DO UPDATE SET data = EXCLUDED.data;`
+	logger.Debugw("updating deposit history...", "query", updateStmt)
+
+	tx, err := hdb.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+	for _, deposit := range deposits {
+		data, err := json.Marshal(deposit)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, deposit.ID)
+		dataJSON = append(dataJSON, data)
+		timestamps = append(timestamps, timeutil.TimestampMsToTime(deposit.CreatedAt))
+	}
+	_, err = tx.Exec(updateStmt, pq.Array(ids), pq.Array(dataJSON), pq.Array(timestamps), account)
+	return err
+}
+
+// DepositRecord is the deposits of an account in database.
+type DepositRecord struct {
+	Account string        `db:"account"`
+	Data    pq.ByteaArray `db:"data"`
+}
+
+// GetDepositHistory return deposit history between from.. to.. keyed by account
+func (hdb *HuobiStorage) GetDepositHistory(from, to time.Time) (map[string][]huobi.DepositHistory, error) {
+	var (
+		dbResult []DepositRecord
+		result   = make(map[string][]huobi.DepositHistory)
+		logger   = hdb.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from.String(),
+			"to", to.String(),
+		)
+	)
+	const selectStmt = `SELECT account, ARRAY_AGG(data ORDER BY id) as data FROM huobi_deposits WHERE timestamp >= $1 AND timestamp < $2 GROUP BY account;`
+	logger.Debugw("querying deposit history...", "query", selectStmt)
+	if err := hdb.db.Select(&dbResult, selectStmt, from, to); err != nil {
+		return result, err
+	}
+	for _, record := range dbResult {
+		deposits := make([]huobi.DepositHistory, len(record.Data))
+		for i, data := range record.Data {
+			if err := json.Unmarshal(data, &deposits[i]); err != nil {
+				return result, err
+			}
+		}
+		result[record.Account] = deposits
+	}
+	return result, nil
+}
+
+// GetCursor returns the ID of account deposits are fetched after. It is the ID before the first deposit not
+// in final states, so deposits are updated until they are safe or orphan, or the latest ID stored.
+func (hdb *HuobiStorage) GetCursor(account string) (uint64, error) {
+	var (
+		result uint64
+		logger = hdb.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"account", account,
+		)
+	)
+	const selectStmt = `SELECT COALESCE(
+		(SELECT MIN(id) - 1 FROM huobi_deposits WHERE account = $1 AND data ->> 'state' NOT IN ('safe', 'orphan')),
+		(SELECT MAX(id) FROM huobi_deposits WHERE account = $1),
+		0
+	)`
+	logger.Debugw("querying deposit cursor...", "query", selectStmt)
+
+	if err := hdb.db.Get(&result, selectStmt, account); err != nil {
+		return 0, err
+	}
+	return result, nil
+}
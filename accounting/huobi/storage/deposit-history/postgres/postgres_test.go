@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"testing"
+
+	_ "github.com/lib/pq" // sql driver name: "postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func TestSaveAndGetDepositHistory(t *testing.T) {
+	const account = "huobi_v1_main"
+	var (
+		testData = []huobi.DepositHistory{
+			{
+				ID:        3000012,
+				CreatedAt: 1525754125590,
+				UpdatedAt: 1525754753403,
+				Currency:  "eth",
+				Chain:     "eth",
+				Type:      "deposit",
+				Amount:    12.5,
+				State:     "safe",
+				Address:   "f6a605cdd9b2471ffdff706f8b7665a12b862158",
+				TxHash:    "cdef3adad017d9564e62282f5e0f0d87d72b995759f1f7f4e473137cc1b96e56",
+			},
+			{
+				ID:        3000013,
+				CreatedAt: 1525754125595,
+				UpdatedAt: 1525754125595,
+				Currency:  "knc",
+				Chain:     "knc",
+				Type:      "deposit",
+				Amount:    1000,
+				State:     "confirming",
+				Address:   "f6a605cdd9b2471ffdff706f8b7665a12b862158",
+				TxHash:    "0b5d6aa6b6a1c2e0e2a37ffd1c9e6a2e3c0d4f60e0f1a0a2b3c4d5e6f7a8b9c0",
+			},
+		}
+	)
+	sugar := testutil.MustNewDevelopmentSugaredLogger()
+	db, teardown := testutil.MustNewDevelopmentDB()
+	hdb, err := NewDB(sugar, db)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, teardown())
+	}()
+
+	cursor, err := hdb.GetCursor(account)
+	require.NoError(t, err)
+	assert.Zero(t, cursor)
+
+	require.NoError(t, hdb.UpdateDepositHistory(testData, account))
+
+	// the confirming deposit is fetched again
+	cursor, err = hdb.GetCursor(account)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3000012), cursor)
+
+	deposits, err := hdb.GetDepositHistory(timeutil.TimestampMsToTime(1525754125500), timeutil.TimestampMsToTime(1525754125600))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]huobi.DepositHistory{account: testData}, deposits)
+
+	testData[1].State = "safe"
+	require.NoError(t, hdb.UpdateDepositHistory(testData[1:], account))
+	deposits, err = hdb.GetDepositHistory(timeutil.TimestampMsToTime(1525754125500), timeutil.TimestampMsToTime(1525754125600))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]huobi.DepositHistory{account: testData}, deposits)
+
+	cursor, err = hdb.GetCursor(account)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3000013), cursor)
+}
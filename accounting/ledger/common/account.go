@@ -0,0 +1,87 @@
+// Package common contains the double-entry ledger of reserve accounting. Source records of trades, fees, gas,
+// deposits and withdrawals are turned into balanced journal entries against a chart of accounts, the balance
+// of an account is kept per token.
+package common
+
+import (
+	"strings"
+)
+
+// AccountType is the type of a ledger account, it is the first segment of the account code.
+type AccountType string
+
+const (
+	// Asset accounts are holdings of reserve addresses and CEX accounts.
+	Asset AccountType = "assets"
+	// Liability accounts are amounts owed by the reserve.
+	Liability AccountType = "liabilities"
+	// Equity accounts are the retained earnings of closed periods.
+	Equity AccountType = "equity"
+	// Income accounts are the amounts gained in a period, they are closed to retained earnings.
+	Income AccountType = "income"
+	// Expense accounts are the amounts spent in a period, they are closed to retained earnings.
+	Expense AccountType = "expenses"
+)
+
+const (
+	// OnChainSource is the source name of reserve address accounts.
+	OnChainSource = "onchain"
+
+	// InTransitAccount holds the tokens sent but not received between reserve addresses and CEX accounts,
+	// its balance is the in-flight and unmatched transfers.
+	InTransitAccount = "assets:in_transit"
+	// TradingAccount is the counterpart of trades, its balance of a token is the net amount traded.
+	TradingAccount = "income:trading"
+	// GasAccount is the gas spent by reserve addresses.
+	GasAccount = "expenses:gas"
+	// RetainedEarningsAccount receives the income and expense balances at period close.
+	RetainedEarningsAccount = "equity:retained_earnings"
+)
+
+// AssetAccount returns the asset account of an account of source, e.g. assets:binance:binance_1 or
+// assets:onchain:0x63825c174ab367968ec60f061753d3bbd36a0d8f.
+func AssetAccount(source, account string) string {
+	if source == OnChainSource {
+		account = strings.ToLower(account)
+	}
+	return string(Asset) + ":" + source + ":" + account
+}
+
+// TradingFeeAccount returns the expense account of trading fees paid in source.
+func TradingFeeAccount(source string) string {
+	return string(Expense) + ":trading_fees:" + source
+}
+
+// WithdrawalFeeAccount returns the expense account of withdrawal fees paid to exchange.
+func WithdrawalFeeAccount(exchange string) string {
+	return string(Expense) + ":withdrawal_fees:" + exchange
+}
+
+// TypeOf returns the type of account code.
+func TypeOf(code string) (AccountType, bool) {
+	typ := AccountType(strings.SplitN(code, ":", 2)[0])
+	switch typ {
+	case Asset, Liability, Equity, Income, Expense:
+		return typ, true
+	}
+	return "", false
+}
+
+// IsNominal returns true if the account is closed to retained earnings at period close.
+func IsNominal(code string) bool {
+	typ, _ := TypeOf(code)
+	return typ == Income || typ == Expense
+}
+
+// MatchAccount returns true if the account code is prefix or a sub account of prefix, an empty prefix
+// matches all accounts.
+func MatchAccount(code, prefix string) bool {
+	return prefix == "" || code == prefix || strings.HasPrefix(code, prefix+":")
+}
+
+// Account is an account of the chart of accounts, the balance is kept per token.
+type Account struct {
+	Code  string      `json:"code"`
+	Type  AccountType `json:"type"`
+	Token string      `json:"token"`
+}
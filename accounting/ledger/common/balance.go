@@ -0,0 +1,197 @@
+package common
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// Balance is the sum of debits and credits of an account in a token.
+type Balance struct {
+	Account string  `json:"account" db:"account"`
+	Token   string  `json:"token" db:"token"`
+	Debit   float64 `json:"debit" db:"debit"`
+	Credit  float64 `json:"credit" db:"credit"`
+}
+
+// TrialBalanceRow is the balance of an account in a token, on the debit side if the debits are greater than
+// the credits, on the credit side otherwise.
+type TrialBalanceRow struct {
+	Account string      `json:"account"`
+	Type    AccountType `json:"type"`
+	Token   string      `json:"token"`
+	Debit   float64     `json:"debit"`
+	Credit  float64     `json:"credit"`
+}
+
+// Totals is the total debits and credits of a token.
+type Totals struct {
+	Debit  float64 `json:"debit"`
+	Credit float64 `json:"credit"`
+}
+
+// TrialBalance is the balances of all accounts at a time, the debits and credits of each token are equal
+// if the ledger is balanced.
+type TrialBalance struct {
+	At     time.Time          `json:"at"`
+	Rows   []TrialBalanceRow  `json:"rows"`
+	Totals map[string]*Totals `json:"totals"`
+}
+
+// MarshalJSON implements custom JSON marshaler for TrialBalance to format timestamp in unix millis.
+func (tb TrialBalance) MarshalJSON() ([]byte, error) {
+	type AliasTrialBalance TrialBalance
+	return json.Marshal(struct {
+		At uint64 `json:"at"`
+		AliasTrialBalance
+	}{
+		AliasTrialBalance: (AliasTrialBalance)(tb),
+		At:                timeutil.TimeToTimestampMs(tb.At),
+	})
+}
+
+func sortBalances(balances []Balance) {
+	sort.SliceStable(balances, func(i, j int) bool {
+		if balances[i].Account != balances[j].Account {
+			return balances[i].Account < balances[j].Account
+		}
+		return balances[i].Token < balances[j].Token
+	})
+}
+
+// NewTrialBalance returns the trial balance of account balances at time at. Accounts with zero balance are
+// omitted.
+func NewTrialBalance(balances []Balance, at time.Time) TrialBalance {
+	tb := TrialBalance{
+		At:     at,
+		Rows:   []TrialBalanceRow{},
+		Totals: make(map[string]*Totals),
+	}
+	sortBalances(balances)
+	for _, b := range balances {
+		net := b.Debit - b.Credit
+		if net == 0 {
+			continue
+		}
+		typ, _ := TypeOf(b.Account)
+		row := TrialBalanceRow{Account: b.Account, Type: typ, Token: b.Token}
+		totals, ok := tb.Totals[b.Token]
+		if !ok {
+			totals = &Totals{}
+			tb.Totals[b.Token] = totals
+		}
+		if net > 0 {
+			row.Debit = net
+			totals.Debit += net
+		} else {
+			row.Credit = -net
+			totals.Credit -= net
+		}
+		tb.Rows = append(tb.Rows, row)
+	}
+	return tb
+}
+
+// LedgerLine is a posting of an entry in the general ledger, Balance is the running balance after it,
+// positive for debit.
+type LedgerLine struct {
+	EntryID     uint64    `json:"entry_id"`
+	Timestamp   time.Time `json:"timestamp"`
+	Source      Source    `json:"source"`
+	Reference   string    `json:"reference"`
+	Description string    `json:"description"`
+	Debit       float64   `json:"debit,omitempty"`
+	Credit      float64   `json:"credit,omitempty"`
+	Balance     float64   `json:"balance"`
+}
+
+// MarshalJSON implements custom JSON marshaler for LedgerLine to format timestamp in unix millis.
+func (l LedgerLine) MarshalJSON() ([]byte, error) {
+	type AliasLedgerLine LedgerLine
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasLedgerLine
+	}{
+		AliasLedgerLine: (AliasLedgerLine)(l),
+		Timestamp:       timeutil.TimeToTimestampMs(l.Timestamp),
+	})
+}
+
+// LedgerAccount is the postings of an account in a token in a time range, balances are positive for debit.
+type LedgerAccount struct {
+	Account string       `json:"account"`
+	Type    AccountType  `json:"type"`
+	Token   string       `json:"token"`
+	Opening float64      `json:"opening"`
+	Lines   []LedgerLine `json:"lines"`
+	Closing float64      `json:"closing"`
+}
+
+// NewGeneralLedger returns the general ledger of accounts matching accountPrefix and token, from opening
+// balances at the start of the time range and the entries of the time range sorted by timestamp. An empty
+// token matches all tokens.
+func NewGeneralLedger(opening []Balance, entries []Entry, accountPrefix, token string) []LedgerAccount {
+	type key struct {
+		account, token string
+	}
+	var (
+		accounts = make(map[key]*LedgerAccount)
+		keys     []key
+	)
+	get := func(account, tok string) *LedgerAccount {
+		k := key{account: account, token: tok}
+		la, ok := accounts[k]
+		if !ok {
+			typ, _ := TypeOf(account)
+			la = &LedgerAccount{Account: account, Type: typ, Token: tok, Lines: []LedgerLine{}}
+			accounts[k] = la
+			keys = append(keys, k)
+		}
+		return la
+	}
+	matches := func(account, tok string) bool {
+		return MatchAccount(account, accountPrefix) && (token == "" || token == tok)
+	}
+
+	for _, b := range opening {
+		if !matches(b.Account, b.Token) || b.Debit == b.Credit {
+			continue
+		}
+		la := get(b.Account, b.Token)
+		la.Opening = b.Debit - b.Credit
+		la.Closing = la.Opening
+	}
+	for _, e := range entries {
+		for _, p := range e.Postings {
+			if !matches(p.Account, p.Token) {
+				continue
+			}
+			la := get(p.Account, p.Token)
+			la.Closing += p.Debit - p.Credit
+			la.Lines = append(la.Lines, LedgerLine{
+				EntryID:     e.ID,
+				Timestamp:   e.Timestamp,
+				Source:      e.Source,
+				Reference:   e.Reference,
+				Description: e.Description,
+				Debit:       p.Debit,
+				Credit:      p.Credit,
+				Balance:     la.Closing,
+			})
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].account != keys[j].account {
+			return keys[i].account < keys[j].account
+		}
+		return keys[i].token < keys[j].token
+	})
+	result := make([]LedgerAccount, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, *accounts[k])
+	}
+	return result
+}
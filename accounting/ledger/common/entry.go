@@ -0,0 +1,139 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// Source is the kind of source record of a journal entry.
+type Source string
+
+const (
+	// SourceTrade is a trade of a CEX account or 0x fill.
+	SourceTrade Source = "trade"
+	// SourceCEXTransfer is a completed deposit or withdrawal of a CEX account.
+	SourceCEXTransfer Source = "cex_transfer"
+	// SourceOnChainTransfer is a transfer between a reserve address and a CEX account.
+	SourceOnChainTransfer Source = "onchain_transfer"
+	// SourceGas is the gas fee of a transaction sent by a reserve address.
+	SourceGas Source = "gas"
+	// SourceClose is the closing entry of a period.
+	SourceClose Source = "close"
+)
+
+// balanceTolerance is the relative difference of debits and credits allowed in an entry, for rounding errors.
+const balanceTolerance = 1e-9
+
+// Posting is a debit or credit of an account in a token.
+type Posting struct {
+	Account string  `json:"account"`
+	Token   string  `json:"token"`
+	Debit   float64 `json:"debit,omitempty"`
+	Credit  float64 `json:"credit,omitempty"`
+}
+
+// Entry is a balanced journal entry, the debits and credits of each token are equal.
+type Entry struct {
+	// ID is assigned by storage.
+	ID     uint64 `json:"id"`
+	Source Source `json:"source"`
+	// Reference identifies the source record, it is unique per source.
+	Reference string `json:"reference"`
+	// Timestamp is the posting time, entries of closed periods are posted at the end of the last closed period.
+	Timestamp time.Time `json:"timestamp"`
+	// OccurredAt is the time of the source record.
+	OccurredAt  time.Time `json:"occurred_at"`
+	Description string    `json:"description"`
+	Postings    []Posting `json:"postings"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Entry to format timestamps in unix millis.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	type AliasEntry Entry
+	return json.Marshal(struct {
+		Timestamp  uint64 `json:"timestamp"`
+		OccurredAt uint64 `json:"occurred_at"`
+		AliasEntry
+	}{
+		AliasEntry: (AliasEntry)(e),
+		Timestamp:  timeutil.TimeToTimestampMs(e.Timestamp),
+		OccurredAt: timeutil.TimeToTimestampMs(e.OccurredAt),
+	})
+}
+
+// ErrUnbalanced is returned if the debits and credits of a token in an entry are not equal.
+var ErrUnbalanced = errors.New("unbalanced entry")
+
+// Validate returns an error if the entry is not a valid double entry.
+func (e Entry) Validate() error {
+	if e.Source == "" || e.Reference == "" {
+		return fmt.Errorf("entry without source reference: %q %q", e.Source, e.Reference)
+	}
+	if len(e.Postings) < 2 {
+		return fmt.Errorf("entry %s %s has %d postings", e.Source, e.Reference, len(e.Postings))
+	}
+	var (
+		debits  = make(map[string]float64)
+		credits = make(map[string]float64)
+	)
+	for _, p := range e.Postings {
+		if _, ok := TypeOf(p.Account); !ok {
+			return fmt.Errorf("entry %s %s: invalid account %q", e.Source, e.Reference, p.Account)
+		}
+		if p.Token == "" {
+			return fmt.Errorf("entry %s %s: posting to %s without token", e.Source, e.Reference, p.Account)
+		}
+		if p.Debit < 0 || p.Credit < 0 || (p.Debit == 0) == (p.Credit == 0) {
+			return fmt.Errorf("entry %s %s: posting to %s must be either a positive debit or credit",
+				e.Source, e.Reference, p.Account)
+		}
+		debits[p.Token] += p.Debit
+		credits[p.Token] += p.Credit
+	}
+	for token, debit := range debits {
+		credit := credits[token]
+		if math.Abs(debit-credit) > balanceTolerance*math.Max(debit, credit) {
+			return fmt.Errorf("%w %s %s: %s debit %f credit %f", ErrUnbalanced, e.Source, e.Reference, token, debit, credit)
+		}
+	}
+	for token := range credits {
+		if _, ok := debits[token]; !ok {
+			return fmt.Errorf("%w %s %s: %s has no debit", ErrUnbalanced, e.Source, e.Reference, token)
+		}
+	}
+	return nil
+}
+
+// Period is an accounting period, entries with timestamp in [Start, End) belong to it. The closing entry of
+// a period is posted at End.
+type Period struct {
+	ID       uint64    `json:"id"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	ClosedAt time.Time `json:"closed_at"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Period to format timestamps in unix millis.
+func (p Period) MarshalJSON() ([]byte, error) {
+	type AliasPeriod Period
+	var start uint64
+	if !p.Start.IsZero() {
+		start = timeutil.TimeToTimestampMs(p.Start)
+	}
+	return json.Marshal(struct {
+		Start    uint64 `json:"start"`
+		End      uint64 `json:"end"`
+		ClosedAt uint64 `json:"closed_at"`
+		AliasPeriod
+	}{
+		AliasPeriod: (AliasPeriod)(p),
+		Start:       start,
+		End:         timeutil.TimeToTimestampMs(p.End),
+		ClosedAt:    timeutil.TimeToTimestampMs(p.ClosedAt),
+	})
+}
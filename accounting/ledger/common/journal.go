@@ -0,0 +1,193 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pnlcommon "github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	matchercommon "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// GasFee is the gas paid by a transaction sent by a reserve address, in ETH.
+type GasFee struct {
+	Hash      string
+	From      string
+	Amount    float64
+	Timestamp time.Time
+}
+
+func debit(account, token string, amount float64) Posting {
+	return Posting{Account: account, Token: strings.ToUpper(token), Debit: amount}
+}
+
+func credit(account, token string, amount float64) Posting {
+	return Posting{Account: account, Token: strings.ToUpper(token), Credit: amount}
+}
+
+// TradeEntry returns the entry of a trade. The bought and sold tokens are exchanged with the trading account,
+// the fee is an expense of the trade source. Legs of zero amount are not posted, on-chain trades of reserves
+// are posted to the reserve address accounts.
+func TradeEntry(trade pnlcommon.Trade) Entry {
+	account := AssetAccount(trade.Source, trade.Account)
+	if trade.Source == pnlcommon.ReserveSource {
+		account = AssetAccount(OnChainSource, trade.Account)
+	}
+	entry := Entry{
+		Source:     SourceTrade,
+		Reference:  fmt.Sprintf("%s:%s:%s", trade.Source, trade.Account, trade.ID),
+		Timestamp:  trade.Timestamp,
+		OccurredAt: trade.Timestamp,
+		Description: fmt.Sprintf("%s %s: bought %s %s, sold %s %s", trade.Source, trade.Account,
+			formatAmount(trade.BoughtAmount), trade.Bought, formatAmount(trade.SoldAmount), trade.Sold),
+	}
+	if trade.BoughtAmount > 0 {
+		entry.Postings = append(entry.Postings,
+			debit(account, trade.Bought, trade.BoughtAmount),
+			credit(TradingAccount, trade.Bought, trade.BoughtAmount),
+		)
+	}
+	if trade.SoldAmount > 0 {
+		entry.Postings = append(entry.Postings,
+			debit(TradingAccount, trade.Sold, trade.SoldAmount),
+			credit(account, trade.Sold, trade.SoldAmount),
+		)
+	}
+	if trade.FeeAmount > 0 && trade.FeeToken != "" {
+		entry.Postings = append(entry.Postings,
+			debit(TradingFeeAccount(trade.Source), trade.FeeToken, trade.FeeAmount),
+			credit(account, trade.FeeToken, trade.FeeAmount),
+		)
+	}
+	return entry
+}
+
+// CEXTransferEntry returns the entry of a completed CEX transfer. A withdrawal moves the amount from the CEX
+// account to the in-transit account and the fee to expense, a deposit moves the amount from in transit to
+// the CEX account.
+func CEXTransferEntry(transfer matchercommon.CEXTransfer) Entry {
+	account := AssetAccount(transfer.Exchange, transfer.Account)
+	entry := Entry{
+		Source:     SourceCEXTransfer,
+		Reference:  fmt.Sprintf("%s:%s:%s:%s", transfer.Exchange, transfer.Account, transfer.Direction, transfer.ID),
+		Timestamp:  transfer.Timestamp,
+		OccurredAt: transfer.Timestamp,
+		Description: fmt.Sprintf("%s %s %s %s %s", transfer.Exchange, transfer.Account, transfer.Direction,
+			formatAmount(transfer.Amount), transfer.Asset),
+	}
+	switch transfer.Direction {
+	case matchercommon.Withdrawal:
+		entry.Postings = []Posting{
+			debit(InTransitAccount, transfer.Asset, transfer.Amount),
+			credit(account, transfer.Asset, transfer.Amount),
+		}
+		if transfer.Fee > 0 {
+			entry.Postings = append(entry.Postings,
+				debit(WithdrawalFeeAccount(transfer.Exchange), transfer.Asset, transfer.Fee),
+				credit(account, transfer.Asset, transfer.Fee),
+			)
+		}
+	case matchercommon.Deposit:
+		entry.Postings = []Posting{
+			debit(account, transfer.Asset, transfer.Amount),
+			credit(InTransitAccount, transfer.Asset, transfer.Amount),
+		}
+	}
+	return entry
+}
+
+// OnChainTransferEntry returns the entry of an on-chain transfer of token. A send to a CEX deposit address
+// moves the amount from the sender to the in-transit account, a receipt of a CEX withdrawal moves the amount
+// from in transit to the receiver.
+func OnChainTransferEntry(transfer matchercommon.OnChainTransfer, token string) Entry {
+	entry := Entry{
+		Source: SourceOnChainTransfer,
+		Reference: strings.ToLower(fmt.Sprintf("%s:%s:%s:%s",
+			transfer.Hash, transfer.Token, transfer.From, transfer.To)),
+		Timestamp:  transfer.Timestamp,
+		OccurredAt: transfer.Timestamp,
+		Description: fmt.Sprintf("%s %s %s from %s to %s", transfer.Direction, formatAmount(transfer.Amount),
+			token, transfer.From, transfer.To),
+	}
+	switch transfer.Direction {
+	case matchercommon.Deposit:
+		entry.Postings = []Posting{
+			debit(InTransitAccount, token, transfer.Amount),
+			credit(AssetAccount(OnChainSource, transfer.From), token, transfer.Amount),
+		}
+	case matchercommon.Withdrawal:
+		entry.Postings = []Posting{
+			debit(AssetAccount(OnChainSource, transfer.To), token, transfer.Amount),
+			credit(InTransitAccount, token, transfer.Amount),
+		}
+	}
+	return entry
+}
+
+// GasEntry returns the entry of the gas paid by a reserve address.
+func GasEntry(fee GasFee) Entry {
+	return Entry{
+		Source:      SourceGas,
+		Reference:   strings.ToLower(fee.Hash),
+		Timestamp:   fee.Timestamp,
+		OccurredAt:  fee.Timestamp,
+		Description: fmt.Sprintf("gas of %s", fee.Hash),
+		Postings: []Posting{
+			debit(GasAccount, "ETH", fee.Amount),
+			credit(AssetAccount(OnChainSource, fee.From), "ETH", fee.Amount),
+		},
+	}
+}
+
+// ClosingEntry returns the entry that closes the balances of income and expense accounts to retained
+// earnings at the end of a period. It returns false if there is nothing to close.
+func ClosingEntry(balances []Balance, end time.Time) (Entry, bool) {
+	var (
+		postings []Posting
+		retained = make(map[string]float64)
+		tokens   []string
+	)
+	for _, b := range balances {
+		if !IsNominal(b.Account) {
+			continue
+		}
+		net := b.Debit - b.Credit
+		switch {
+		case net > 0:
+			postings = append(postings, credit(b.Account, b.Token, net))
+		case net < 0:
+			postings = append(postings, debit(b.Account, b.Token, -net))
+		default:
+			continue
+		}
+		if _, ok := retained[b.Token]; !ok {
+			tokens = append(tokens, b.Token)
+		}
+		retained[b.Token] += net
+	}
+	for _, token := range tokens {
+		switch net := retained[token]; {
+		case net > 0:
+			postings = append(postings, debit(RetainedEarningsAccount, token, net))
+		case net < 0:
+			postings = append(postings, credit(RetainedEarningsAccount, token, -net))
+		}
+	}
+	if len(postings) == 0 {
+		return Entry{}, false
+	}
+	return Entry{
+		Source:      SourceClose,
+		Reference:   strconv.FormatUint(timeutil.TimeToTimestampMs(end), 10),
+		Timestamp:   end,
+		OccurredAt:  end,
+		Description: fmt.Sprintf("close period ending %s", end.UTC().Format(time.RFC3339)),
+		Postings:    postings,
+	}, true
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
@@ -0,0 +1,167 @@
+package common
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pnlcommon "github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	matchercommon "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+)
+
+// balances sums the postings of entries per account and token.
+func balances(entries ...Entry) []Balance {
+	var (
+		result []Balance
+		index  = make(map[[2]string]int)
+	)
+	for _, e := range entries {
+		for _, p := range e.Postings {
+			k := [2]string{p.Account, p.Token}
+			i, ok := index[k]
+			if !ok {
+				i = len(result)
+				index[k] = i
+				result = append(result, Balance{Account: p.Account, Token: p.Token})
+			}
+			result[i].Debit += p.Debit
+			result[i].Credit += p.Credit
+		}
+	}
+	return result
+}
+
+func TestEntries(t *testing.T) {
+	var (
+		ts      = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		reserve = "0x63825C174ab367968EC60f061753D3bbD36A0D8F"
+	)
+	trade := TradeEntry(pnlcommon.Trade{
+		ID:           "KNCETH:1",
+		Timestamp:    ts,
+		Source:       "binance",
+		Account:      "binance_1",
+		Bought:       "ETH",
+		BoughtAmount: 1,
+		Sold:         "KNC",
+		SoldAmount:   1000,
+		FeeToken:     "BNB",
+		FeeAmount:    0.01,
+	})
+	require.NoError(t, trade.Validate())
+	assert.Equal(t, "binance:binance_1:KNCETH:1", trade.Reference)
+	assert.Len(t, trade.Postings, 6)
+
+	// legs of zero amount are not posted
+	reserveTrade := TradeEntry(pnlcommon.Trade{
+		ID:         "0xa:1:0",
+		Timestamp:  ts,
+		Source:     pnlcommon.ReserveSource,
+		Account:    "0x63825C174ab367968EC60f061753D3bbD36A0D8F",
+		Bought:     "KNC",
+		Sold:       "ETH",
+		SoldAmount: 1,
+	})
+	require.NoError(t, reserveTrade.Validate())
+	require.Len(t, reserveTrade.Postings, 2)
+	assert.Equal(t, "assets:onchain:0x63825c174ab367968ec60f061753d3bbd36a0d8f", reserveTrade.Postings[1].Account)
+
+	withdrawal := CEXTransferEntry(matchercommon.CEXTransfer{
+		Direction: matchercommon.Withdrawal,
+		Exchange:  "binance",
+		Account:   "binance_1",
+		ID:        "w1",
+		Asset:     "knc",
+		Amount:    500,
+		Fee:       2,
+		Timestamp: ts,
+	})
+	require.NoError(t, withdrawal.Validate())
+	receipt := OnChainTransferEntry(matchercommon.OnChainTransfer{
+		Direction: matchercommon.Withdrawal,
+		Hash:      "0xabc",
+		From:      "0x1",
+		To:        reserve,
+		Token:     "0xdd974D5C2e2928deA5F71b9825b8b646686BD200",
+		Amount:    500,
+		Timestamp: ts.Add(time.Minute),
+	}, "KNC")
+	require.NoError(t, receipt.Validate())
+	gas := GasEntry(GasFee{Hash: "0xdef", From: reserve, Amount: 0.002, Timestamp: ts})
+	require.NoError(t, gas.Validate())
+
+	tb := NewTrialBalance(balances(trade, withdrawal, receipt, gas), ts.Add(time.Hour))
+	for token, totals := range tb.Totals {
+		assert.InDelta(t, totals.Debit, totals.Credit, 1e-12, token)
+	}
+	assert.Contains(t, tb.Rows, TrialBalanceRow{Account: "assets:binance:binance_1", Type: Asset, Token: "KNC", Credit: 1502})
+	assert.Contains(t, tb.Rows, TrialBalanceRow{Account: AssetAccount(OnChainSource, reserve), Type: Asset, Token: "KNC", Debit: 500})
+	assert.Contains(t, tb.Rows, TrialBalanceRow{Account: "expenses:withdrawal_fees:binance", Type: Expense, Token: "KNC", Debit: 2})
+	for _, row := range tb.Rows {
+		// the withdrawal is received, nothing is in transit
+		assert.NotEqual(t, InTransitAccount, row.Account)
+	}
+
+	closing, ok := ClosingEntry(balances(trade, withdrawal, receipt, gas), ts.Add(time.Hour))
+	require.True(t, ok)
+	require.NoError(t, closing.Validate())
+	for _, b := range balances(trade, withdrawal, receipt, gas, closing) {
+		if IsNominal(b.Account) {
+			assert.InDelta(t, b.Debit, b.Credit, 1e-12, b.Account)
+		}
+	}
+	tb = NewTrialBalance(balances(trade, withdrawal, receipt, gas, closing), ts.Add(2*time.Hour))
+	assert.Contains(t, tb.Rows, TrialBalanceRow{Account: RetainedEarningsAccount, Type: Equity, Token: "KNC", Debit: 1002})
+
+	_, ok = ClosingEntry(balances(withdrawal, receipt), ts)
+	assert.True(t, ok)
+	_, ok = ClosingEntry([]Balance{{Account: InTransitAccount, Token: "KNC", Debit: 1}}, ts)
+	assert.False(t, ok)
+}
+
+func TestValidate(t *testing.T) {
+	entry := Entry{
+		Source:    SourceGas,
+		Reference: "0x1",
+		Postings: []Posting{
+			{Account: GasAccount, Token: "ETH", Debit: 1},
+			{Account: "assets:onchain:0x1", Token: "ETH", Credit: 0.5},
+		},
+	}
+	assert.True(t, errors.Is(entry.Validate(), ErrUnbalanced))
+	entry.Postings[1].Token = "KNC"
+	assert.True(t, errors.Is(entry.Validate(), ErrUnbalanced))
+	entry.Postings[1] = Posting{Account: "unknown:onchain:0x1", Token: "ETH", Credit: 1}
+	assert.Error(t, entry.Validate())
+	entry.Postings[1] = Posting{Account: "assets:onchain:0x1", Token: "ETH", Debit: 1, Credit: 1}
+	assert.Error(t, entry.Validate())
+	entry.Postings[1] = Posting{Account: "assets:onchain:0x1", Token: "ETH", Credit: 1}
+	assert.NoError(t, entry.Validate())
+}
+
+func TestGeneralLedger(t *testing.T) {
+	ts := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		GasEntry(GasFee{Hash: "0x1", From: "0xa", Amount: 1, Timestamp: ts}),
+		GasEntry(GasFee{Hash: "0x2", From: "0xb", Amount: 2, Timestamp: ts.Add(time.Minute)}),
+	}
+	opening := []Balance{{Account: "assets:onchain:0xa", Token: "ETH", Debit: 10}}
+
+	gl := NewGeneralLedger(opening, entries, "assets:onchain", "")
+	require.Len(t, gl, 2)
+	assert.Equal(t, "assets:onchain:0xa", gl[0].Account)
+	assert.Equal(t, 10.0, gl[0].Opening)
+	assert.Equal(t, 9.0, gl[0].Closing)
+	require.Len(t, gl[0].Lines, 1)
+	assert.Equal(t, 9.0, gl[0].Lines[0].Balance)
+	assert.Equal(t, -2.0, gl[1].Closing)
+
+	gl = NewGeneralLedger(opening, entries, "assets:onchain:0x", "")
+	assert.Empty(t, gl)
+	gl = NewGeneralLedger(opening, entries, GasAccount, "ETH")
+	require.Len(t, gl, 1)
+	assert.Equal(t, 3.0, gl[0].Closing)
+}
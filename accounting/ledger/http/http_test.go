@@ -0,0 +1,129 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+)
+
+type mockStorage struct {
+	storage.Interface
+	balances []common.Balance
+	entries  []common.Entry
+	closed   time.Time
+}
+
+func (m *mockStorage) GetBalances(_ time.Time) ([]common.Balance, error) {
+	return m.balances, nil
+}
+
+func (m *mockStorage) GetEntries(_, _ time.Time) ([]common.Entry, error) {
+	return m.entries, nil
+}
+
+func (m *mockStorage) ClosePeriod(end time.Time) (common.Period, error) {
+	if !end.After(m.closed) {
+		return common.Period{}, storage.ErrPeriodClosed
+	}
+	period := common.Period{ID: 1, Start: m.closed, End: end, ClosedAt: time.Now()}
+	m.closed = end
+	return period, nil
+}
+
+func TestLedgerHTTP(t *testing.T) {
+	var (
+		ts = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		st = &mockStorage{
+			balances: []common.Balance{
+				{Account: common.GasAccount, Token: "ETH", Debit: 1},
+				{Account: "assets:onchain:0xa", Token: "ETH", Debit: 9, Credit: 10},
+			},
+			entries: []common.Entry{
+				common.GasEntry(common.GasFee{Hash: "0x1", From: "0xa", Amount: 2, Timestamp: ts}),
+			},
+		}
+		s = NewServer(testutil.MustNewDevelopmentSugaredLogger(), "", st)
+	)
+	s.register()
+
+	tests := []httputil.HTTPTestCase{
+		{
+			Msg:      "trial balance",
+			Endpoint: "/ledger/trial-balance",
+			Method:   http.MethodGet,
+			Params:   map[string]string{"at": "1614556800000"},
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, resp.Code)
+				var tb struct {
+					At     uint64                    `json:"at"`
+					Rows   []common.TrialBalanceRow  `json:"rows"`
+					Totals map[string]*common.Totals `json:"totals"`
+				}
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &tb))
+				assert.Equal(t, uint64(1614556800000), tb.At)
+				assert.Len(t, tb.Rows, 2)
+				assert.Equal(t, &common.Totals{Debit: 1, Credit: 1}, tb.Totals["ETH"])
+			},
+		},
+		{
+			Msg:      "general ledger of account",
+			Endpoint: "/ledger/general-ledger",
+			Method:   http.MethodGet,
+			Params:   map[string]string{"from": "1614556800000", "to": "1614643200000", "account": "assets:onchain"},
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, resp.Code)
+				var accounts []struct {
+					Account string  `json:"account"`
+					Opening float64 `json:"opening"`
+					Closing float64 `json:"closing"`
+				}
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &accounts))
+				require.Len(t, accounts, 1)
+				assert.Equal(t, -1.0, accounts[0].Opening)
+				assert.Equal(t, -3.0, accounts[0].Closing)
+			},
+		},
+		{
+			Msg:      "close period",
+			Endpoint: "/ledger/periods",
+			Method:   http.MethodPost,
+			Body:     []byte(`{"end": 1614556800000}`),
+			Assert:   httputil.AssertCode(http.StatusCreated),
+		},
+		{
+			Msg:      "close closed period",
+			Endpoint: "/ledger/periods",
+			Method:   http.MethodPost,
+			Body:     []byte(`{"end": 1614556800000}`),
+			Assert:   httputil.AssertCode(http.StatusConflict),
+		},
+		{
+			Msg:      "close period in the future",
+			Endpoint: "/ledger/periods",
+			Method:   http.MethodPost,
+			Body:     []byte(`{"end": 99999999999999}`),
+			Assert:   httputil.AssertCode(http.StatusBadRequest),
+		},
+		{
+			Msg:      "close period without end",
+			Endpoint: "/ledger/periods",
+			Method:   http.MethodPost,
+			Body:     []byte(`{}`),
+			Assert:   httputil.AssertCode(http.StatusBadRequest),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Msg, func(t *testing.T) { httputil.RunHTTPTestCase(t, tc, s.r) })
+	}
+}
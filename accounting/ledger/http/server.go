@@ -0,0 +1,194 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	maxTimeFrame     = time.Hour * 24 * 90 // 90 days
+	defaultTimeFrame = time.Hour * 24 * 7  // 7 days
+)
+
+// Server is the HTTP server of the reserve ledger.
+type Server struct {
+	sugar   *zap.SugaredLogger
+	r       *gin.Engine
+	host    string
+	storage storage.Interface
+
+	openAPIOptions []openapi.Option
+}
+
+// NewServer creates a new instance of Server.
+func NewServer(sugar *zap.SugaredLogger, host string, storage storage.Interface, openAPIOptions ...openapi.Option) *Server {
+	r := gin.Default()
+	return &Server{sugar: sugar, r: r, host: host, storage: storage, openAPIOptions: openAPIOptions}
+}
+
+func (s *Server) getAccounts(c *gin.Context) {
+	accounts, err := s.storage.GetAccounts()
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	if accounts == nil {
+		accounts = []common.Account{}
+	}
+	c.JSON(http.StatusOK, accounts)
+}
+
+type trialBalanceQuery struct {
+	At uint64 `form:"at"`
+}
+
+func (s *Server) getTrialBalance(c *gin.Context) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+		query  trialBalanceQuery
+		at     = time.Now()
+	)
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	if query.At != 0 {
+		at = timeutil.TimestampMsToTime(query.At)
+	}
+	balances, err := s.storage.GetBalances(at)
+	if err != nil {
+		logger.Errorw("failed to get balances", "at", at, "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, common.NewTrialBalance(balances, at))
+}
+
+type generalLedgerQuery struct {
+	httputil.TimeRangeQuery
+	// Account is the account code or the parent of accounts, e.g. assets:binance.
+	Account string `form:"account"`
+	Token   string `form:"token"`
+}
+
+func (s *Server) getGeneralLedger(c *gin.Context) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+		query  generalLedgerQuery
+	)
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	from, to, err := query.Validate(
+		httputil.TimeRangeQueryWithMaxTimeFrame(maxTimeFrame),
+		httputil.TimeRangeQueryWithDefaultTimeFrame(defaultTimeFrame),
+	)
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	logger = logger.With("from", from, "to", to, "account", query.Account, "token", query.Token)
+
+	opening, err := s.storage.GetBalances(from)
+	if err != nil {
+		logger.Errorw("failed to get opening balances", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	entries, err := s.storage.GetEntries(from, to)
+	if err != nil {
+		logger.Errorw("failed to get entries", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, common.NewGeneralLedger(opening, entries, query.Account, query.Token))
+}
+
+func (s *Server) getPeriods(c *gin.Context) {
+	periods, err := s.storage.GetPeriods()
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	if periods == nil {
+		periods = []common.Period{}
+	}
+	c.JSON(http.StatusOK, periods)
+}
+
+type closePeriodInput struct {
+	// End is the end of the period in milliseconds, it must not be in the future.
+	End uint64 `json:"end" binding:"required"`
+}
+
+func (s *Server) closePeriod(c *gin.Context) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+		input  closePeriodInput
+	)
+	if err := c.ShouldBindJSON(&input); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	end := timeutil.TimestampMsToTime(input.End)
+	if end.After(time.Now()) {
+		httputil.ResponseFailure(c, http.StatusBadRequest, errors.New("period end is in the future"))
+		return
+	}
+	period, err := s.storage.ClosePeriod(end)
+	if err == storage.ErrPeriodClosed {
+		httputil.ResponseFailure(c, http.StatusConflict, err)
+		return
+	} else if err != nil {
+		logger.Errorw("failed to close period", "end", end, "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusCreated, period)
+}
+
+func (s *Server) register() {
+	api := openapi.NewRouter(s.r, openapi.NewSpec("reserve ledger"), s.openAPIOptions...)
+	api.GET("/ledger/accounts", openapi.Endpoint{
+		Summary:  "chart of accounts, the balance of an account is kept per token",
+		Response: []common.Account{},
+	}, s.getAccounts)
+	api.GET("/ledger/trial-balance", openapi.Endpoint{
+		Summary:  "balances of all accounts at a time",
+		Query:    trialBalanceQuery{},
+		Response: common.TrialBalance{},
+	}, s.getTrialBalance)
+	api.GET("/ledger/general-ledger", openapi.Endpoint{
+		Summary:  "postings of accounts in a time range with opening, running and closing balances",
+		Query:    generalLedgerQuery{},
+		Response: []common.LedgerAccount{},
+	}, s.getGeneralLedger)
+	api.GET("/ledger/periods", openapi.Endpoint{
+		Summary:  "closed accounting periods",
+		Response: []common.Period{},
+	}, s.getPeriods)
+	api.POST("/ledger/periods", openapi.Endpoint{
+		Summary:  "close the period from the end of the last closed period",
+		Body:     closePeriodInput{},
+		Response: common.Period{},
+		Status:   http.StatusCreated,
+	}, s.closePeriod)
+}
+
+// Run starts the HTTP server and runs in foreground until terminate by user.
+func (s *Server) Run() error {
+	s.register()
+	return s.r.Run(s.host)
+}
@@ -0,0 +1,153 @@
+// Package poster turns the source records of accounting fetchers into ledger entries.
+package poster
+
+import (
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/storage"
+	pnlstorage "github.com/KyberNetwork/reserve-stats/accounting/pnl/storage"
+	matchercommon "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	matcherstorage "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// Poster posts trades, fees, gas, deposits and withdrawals to the ledger.
+type Poster struct {
+	sugar   *zap.SugaredLogger
+	ledger  storage.Interface
+	trades  pnlstorage.TradesInterface
+	cex     matcherstorage.CEXInterface
+	onChain matcherstorage.OnChainInterface
+	gas     storage.GasInterface
+	matcher *matchercommon.Matcher
+	symbols blockchain.TokenSymbolResolver
+}
+
+// NewPoster creates a new Poster instance.
+func NewPoster(sugar *zap.SugaredLogger, ledger storage.Interface, trades pnlstorage.TradesInterface,
+	cex matcherstorage.CEXInterface, onChain matcherstorage.OnChainInterface, gas storage.GasInterface,
+	matcher *matchercommon.Matcher, symbols blockchain.TokenSymbolResolver) *Poster {
+	return &Poster{
+		sugar:   sugar,
+		ledger:  ledger,
+		trades:  trades,
+		cex:     cex,
+		onChain: onChain,
+		gas:     gas,
+		matcher: matcher,
+		symbols: symbols,
+	}
+}
+
+func inRange(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
+
+func (p *Poster) symbol(token string) (string, error) {
+	address := ethereum.HexToAddress(token)
+	if address == blockchain.ETHAddr {
+		return "ETH", nil
+	}
+	symbol, err := p.symbols.Symbol(address)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(symbol), nil
+}
+
+// transferEntries returns the entries of completed CEX transfers, on-chain sends to CEX deposit addresses and
+// on-chain receipts of CEX withdrawals. Receipts from other addresses are not transfers of CEX accounts.
+func (p *Poster) transferEntries(from, to time.Time) ([]common.Entry, error) {
+	var (
+		window = p.matcher.MatchWindow()
+		result []common.Entry
+	)
+	cexTransfers, err := p.cex.GetTransfers(from.Add(-window), to.Add(window))
+	if err != nil {
+		return nil, err
+	}
+	onChainTransfers, err := p.onChain.GetTransfers(from.Add(-window), to.Add(window))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, transfer := range cexTransfers {
+		if !transfer.Pending && inRange(transfer.Timestamp, from, to) {
+			result = append(result, common.CEXTransferEntry(transfer))
+		}
+	}
+	for _, match := range p.matcher.Match(cexTransfers, onChainTransfers, time.Now()) {
+		transfer := match.OnChain
+		if transfer == nil || !inRange(transfer.Timestamp, from, to) {
+			continue
+		}
+		if match.Direction == matchercommon.Withdrawal && match.CEX == nil {
+			continue
+		}
+		var token string
+		if match.CEX != nil {
+			token = match.CEX.Asset
+		} else if token, err = p.symbol(transfer.Token); err != nil {
+			return nil, err
+		}
+		result = append(result, common.OnChainTransferEntry(*transfer, token))
+	}
+	return result, nil
+}
+
+// Post posts the source records in given time range, records already posted are skipped. It returns the
+// number of entries posted.
+func (p *Poster) Post(from, to time.Time) (int, error) {
+	var (
+		logger = p.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		entries []common.Entry
+		valid   []common.Entry
+	)
+
+	trades, err := p.trades.GetTrades(from, to)
+	if err != nil {
+		return 0, err
+	}
+	for _, trade := range trades {
+		entries = append(entries, common.TradeEntry(trade))
+	}
+
+	transferEntries, err := p.transferEntries(from, to)
+	if err != nil {
+		return 0, err
+	}
+	entries = append(entries, transferEntries...)
+
+	fees, err := p.gas.GetGasFees(from, to)
+	if err != nil {
+		return 0, err
+	}
+	for _, fee := range fees {
+		entries = append(entries, common.GasEntry(fee))
+	}
+
+	// records with zero or missing amounts could not be posted
+	for _, entry := range entries {
+		if err = entry.Validate(); err != nil {
+			logger.Warnw("skipping invalid entry", "error", err)
+			continue
+		}
+		valid = append(valid, entry)
+	}
+	posted, err := p.ledger.InsertEntries(valid)
+	if err != nil {
+		return 0, err
+	}
+	logger.Infow("entries posted", "entries", len(entries), "posted", posted)
+	return posted, nil
+}
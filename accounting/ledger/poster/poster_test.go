@@ -0,0 +1,111 @@
+package poster
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/storage"
+	pnlcommon "github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	matchercommon "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+)
+
+type mockLedger struct {
+	storage.Interface
+	entries []common.Entry
+}
+
+func (m *mockLedger) InsertEntries(entries []common.Entry) (int, error) {
+	m.entries = append(m.entries, entries...)
+	return len(entries), nil
+}
+
+type mockSources struct {
+	trades   []pnlcommon.Trade
+	cex      []matchercommon.CEXTransfer
+	onChain  []matchercommon.OnChainTransfer
+	gasFees  []common.GasFee
+	resolved []ethereum.Address
+}
+
+func (m *mockSources) GetTrades(_, _ time.Time) ([]pnlcommon.Trade, error) {
+	return m.trades, nil
+}
+
+func (m *mockSources) GetGasFees(_, _ time.Time) ([]common.GasFee, error) {
+	return m.gasFees, nil
+}
+
+func (m *mockSources) Symbol(address ethereum.Address) (string, error) {
+	m.resolved = append(m.resolved, address)
+	return "knc", nil
+}
+
+type mockCEX struct{ transfers []matchercommon.CEXTransfer }
+
+func (m *mockCEX) GetTransfers(_, _ time.Time) ([]matchercommon.CEXTransfer, error) {
+	return m.transfers, nil
+}
+
+type mockOnChain struct {
+	transfers []matchercommon.OnChainTransfer
+}
+
+func (m *mockOnChain) GetTransfers(_, _ time.Time) ([]matchercommon.OnChainTransfer, error) {
+	return m.transfers, nil
+}
+
+func TestPost(t *testing.T) {
+	var (
+		from    = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		to      = from.Add(24 * time.Hour)
+		reserve = "0x63825c174ab367968ec60f061753d3bbd36a0d8f"
+		knc     = "0xdd974D5C2e2928deA5F71b9825b8b646686BD200"
+		ledger  = &mockLedger{}
+		sources = &mockSources{
+			trades: []pnlcommon.Trade{
+				{ID: "1", Timestamp: from, Source: "binance", Account: "binance_1", Bought: "ETH", BoughtAmount: 1, Sold: "KNC", SoldAmount: 1000},
+				// leg of zero amount is not posted
+				{ID: "2", Timestamp: from, Source: "binance", Account: "binance_1", Bought: "ETH", Sold: "KNC", SoldAmount: 1000},
+				// invalid trade is skipped
+				{ID: "3", Timestamp: from, Source: "binance", Account: "binance_1", Bought: "ETH", Sold: "KNC"},
+			},
+			gasFees: []common.GasFee{{Hash: "0x1", From: reserve, Amount: 0.01, Timestamp: from}},
+		}
+		cex = &mockCEX{transfers: []matchercommon.CEXTransfer{
+			{Direction: matchercommon.Withdrawal, Exchange: "binance", Account: "binance_1", ID: "w1", Asset: "KNC", Amount: 500, TxHash: "0xa", Timestamp: from},
+			{Direction: matchercommon.Deposit, Exchange: "binance", Account: "binance_1", ID: "d1", Asset: "KNC", Amount: 100, TxHash: "0xb", Pending: true, Timestamp: from},
+		}}
+		onChain = &mockOnChain{transfers: []matchercommon.OnChainTransfer{
+			{Direction: matchercommon.Withdrawal, Hash: "0xa", From: "0x1", To: reserve, Token: knc, Symbol: "KNC", Amount: 500, Timestamp: from.Add(time.Minute)},
+			{Direction: matchercommon.Deposit, Hash: "0xb", From: reserve, To: "0x2", Token: knc, Symbol: "KNC", Amount: 100, Timestamp: from.Add(time.Minute)},
+			// receipt not matched with a CEX withdrawal is not a transfer of CEX accounts
+			{Direction: matchercommon.Withdrawal, Hash: "0xc", From: "0x3", To: reserve, Token: knc, Symbol: "KNC", Amount: 1, Timestamp: from.Add(time.Minute)},
+			{Direction: matchercommon.Deposit, Hash: "0xd", From: reserve, To: "0x2", Token: knc, Symbol: "KNC", Amount: 1, Timestamp: to.Add(time.Minute)},
+		}}
+	)
+	p := NewPoster(zap.NewNop().Sugar(), ledger, sources, cex, onChain, sources, matchercommon.NewMatcher(), sources)
+	posted, err := p.Post(from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 6, posted)
+
+	var references []string
+	for _, entry := range ledger.entries {
+		references = append(references, string(entry.Source)+" "+entry.Reference)
+	}
+	assert.ElementsMatch(t, []string{
+		"trade binance:binance_1:1",
+		"trade binance:binance_1:2",
+		"cex_transfer binance:binance_1:withdrawal:w1",
+		"onchain_transfer 0xa:" + strings.ToLower(knc) + ":0x1:" + reserve,
+		"onchain_transfer 0xb:" + strings.ToLower(knc) + ":" + reserve + ":0x2",
+		"gas 0x1",
+	}, references)
+	assert.Empty(t, sources.resolved)
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/client"
+	txstorage "github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// GasInterface returns the gas paid by reserve addresses.
+type GasInterface interface {
+	GetGasFees(from, to time.Time) ([]common.GasFee, error)
+}
+
+// Gas reads the normal transactions stored by accounting-reserve-transaction-fetcher.
+type Gas struct {
+	sugar     *zap.SugaredLogger
+	txStorage txstorage.ReserveTransactionStorage
+	addresses client.Interface
+	formatter blockchain.TokenAmountFormatterInterface
+}
+
+// NewGas creates a new Gas instance.
+func NewGas(sugar *zap.SugaredLogger, txStorage txstorage.ReserveTransactionStorage, addresses client.Interface,
+	formatter blockchain.TokenAmountFormatterInterface) *Gas {
+	return &Gas{sugar: sugar, txStorage: txStorage, addresses: addresses, formatter: formatter}
+}
+
+// GetGasFees returns the gas paid by transactions sent by reserve addresses in given time range, failed
+// transactions pay gas too.
+func (g *Gas) GetGasFees(from, to time.Time) ([]common.GasFee, error) {
+	var (
+		logger = g.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		result []common.GasFee
+		own    = make(map[ethereum.Address]struct{})
+	)
+	addresses, err := g.addresses.ReserveAddresses()
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addresses {
+		if addr.Type != cexcommon.CEXDepositAddress {
+			own[addr.Address] = struct{}{}
+		}
+	}
+
+	txs, err := g.txStorage.GetNormalTx(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range txs {
+		sender := ethereum.HexToAddress(tx.From)
+		if _, ok := own[sender]; !ok || tx.GasPrice == nil || tx.GasUsed == 0 {
+			continue
+		}
+		fee := new(big.Int).Mul(tx.GasPrice, big.NewInt(int64(tx.GasUsed)))
+		amount, err := g.formatter.FromWei(blockchain.ETHAddr, fee)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, common.GasFee{
+			Hash:      strings.ToLower(tx.Hash),
+			From:      sender.Hex(),
+			Amount:    amount,
+			Timestamp: tx.Timestamp,
+		})
+	}
+	logger.Debugw("gas fees loaded", "transactions", len(result))
+	return result, nil
+}
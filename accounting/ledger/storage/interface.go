@@ -0,0 +1,31 @@
+// Package storage contains the storage of ledger entries and the readers of gas fees of reserve addresses.
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/common"
+)
+
+// ErrPeriodClosed is returned when closing a period that ends before the last closed period.
+var ErrPeriodClosed = errors.New("period is already closed")
+
+// Interface is the storage of ledger entries and periods.
+type Interface interface {
+	// InsertEntries stores the entries that are not stored yet, entries of closed periods are posted at
+	// the end of the last closed period. It returns the number of stored entries.
+	InsertEntries(entries []common.Entry) (int, error)
+	// GetLastOccurredAt returns the time of the latest source record posted, it is zero if there is none.
+	GetLastOccurredAt() (time.Time, error)
+	// GetBalances returns the balances of all accounts of entries posted before at.
+	GetBalances(at time.Time) ([]common.Balance, error)
+	// GetEntries returns the entries posted in from..to sorted by timestamp.
+	GetEntries(from, to time.Time) ([]common.Entry, error)
+	// GetAccounts returns the chart of accounts.
+	GetAccounts() ([]common.Account, error)
+	// GetPeriods returns the closed periods sorted by end time.
+	GetPeriods() ([]common.Period, error)
+	// ClosePeriod closes the period ending at end by posting the closing entry.
+	ClosePeriod(end time.Time) (common.Period, error)
+}
@@ -0,0 +1,319 @@
+package postgres
+
+import (
+	"database/sql"
+	_ "embed" // embed database schema
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
+)
+
+//go:embed schema.sql
+var schema string
+
+// Storage is the PostgreSQL storage of ledger entries and periods.
+type Storage struct {
+	sugar *zap.SugaredLogger
+	db    *sqlx.DB
+}
+
+// NewStorage creates the storage and initializes the database schema.
+func NewStorage(sugar *zap.SugaredLogger, db *sqlx.DB) (*Storage, error) {
+	logger := sugar.With("func", caller.GetCurrentFunctionName())
+	logger.Debugw("initializing database schema", "query", schema)
+	if _, err := db.Exec(schema); err != nil {
+		return nil, err
+	}
+	return &Storage{sugar: sugar, db: db}, nil
+}
+
+const (
+	insertEntryStmt = `INSERT INTO ledger_entries (source, reference, timestamp, occurred_at, description)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT ON CONSTRAINT ledger_entries_reference_key DO NOTHING
+	RETURNING id;`
+	insertPostingsStmt = `INSERT INTO ledger_postings (entry_id, account, token, debit, credit)
+	VALUES (
+		$1,
+		unnest($2::TEXT[]),
+		unnest($3::TEXT[]),
+		unnest($4::NUMERIC[]),
+		unnest($5::NUMERIC[])
+	);`
+	lastEndStmt     = `SELECT MAX(end_time) FROM ledger_periods;`
+	getBalancesStmt = `SELECT p.account, p.token, SUM(p.debit) AS debit, SUM(p.credit) AS credit
+	FROM ledger_postings AS p JOIN ledger_entries AS e ON e.id = p.entry_id
+	WHERE e.timestamp < $1
+	GROUP BY p.account, p.token
+	ORDER BY p.account, p.token;`
+)
+
+// insertEntry stores the entry if it is not stored yet, it returns true if the entry is stored.
+func insertEntry(tx *sqlx.Tx, entry common.Entry) (bool, error) {
+	var (
+		id       uint64
+		accounts []string
+		tokens   []string
+		debits   []float64
+		credits  []float64
+	)
+	err := tx.Get(&id, insertEntryStmt, entry.Source, entry.Reference, entry.Timestamp, entry.OccurredAt, entry.Description)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, p := range entry.Postings {
+		accounts = append(accounts, p.Account)
+		tokens = append(tokens, p.Token)
+		debits = append(debits, p.Debit)
+		credits = append(credits, p.Credit)
+	}
+	if _, err = tx.Exec(insertPostingsStmt, id, pq.StringArray(accounts), pq.StringArray(tokens),
+		pq.Float64Array(debits), pq.Float64Array(credits)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func lastEnd(q sqlx.Queryer) (time.Time, error) {
+	var dbResult pq.NullTime
+	if err := sqlx.Get(q, &dbResult, lastEndStmt); err != nil {
+		return time.Time{}, err
+	}
+	if !dbResult.Valid {
+		return time.Time{}, nil
+	}
+	return dbResult.Time, nil
+}
+
+// InsertEntries stores the entries that are not stored yet, an entry is identified by its source and
+// reference. Entries of closed periods are posted at the end of the last closed period.
+func (s *Storage) InsertEntries(entries []common.Entry) (inserted int, err error) {
+	logger := s.sugar.With(
+		"func", caller.GetCurrentFunctionName(),
+		"number of entries", len(entries),
+	)
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	for _, entry := range entries {
+		if err = entry.Validate(); err != nil {
+			return 0, err
+		}
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+
+	// a period could not be closed while its entries are being inserted
+	if _, err = tx.Exec(`LOCK TABLE ledger_periods IN SHARE MODE;`); err != nil {
+		return 0, err
+	}
+	closedUntil, err := lastEnd(tx)
+	if err != nil {
+		return 0, err
+	}
+	for _, entry := range entries {
+		if entry.Timestamp.Before(closedUntil) {
+			entry.Timestamp = closedUntil
+		}
+		ok, err := insertEntry(tx, entry)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			inserted++
+		}
+	}
+	logger.Debugw("entries inserted", "inserted", inserted, "closed_until", closedUntil)
+	return inserted, nil
+}
+
+// GetLastOccurredAt returns the time of the latest source record posted, closing entries are not source
+// records.
+func (s *Storage) GetLastOccurredAt() (time.Time, error) {
+	var (
+		logger   = s.sugar.With("func", caller.GetCurrentFunctionName())
+		dbResult pq.NullTime
+	)
+	const selectStmt = `SELECT MAX(occurred_at) FROM ledger_entries WHERE source <> $1;`
+	logger.Debugw("querying last occurred at", "query", selectStmt)
+	if err := s.db.Get(&dbResult, selectStmt, common.SourceClose); err != nil {
+		return time.Time{}, err
+	}
+	if !dbResult.Valid {
+		return time.Time{}, nil
+	}
+	return dbResult.Time, nil
+}
+
+// GetBalances returns the balances of all accounts of entries posted before at.
+func (s *Storage) GetBalances(at time.Time) ([]common.Balance, error) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName(), "at", at)
+		result []common.Balance
+	)
+	logger.Debugw("querying balances", "query", getBalancesStmt)
+	if err := s.db.Select(&result, getBalancesStmt, at); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type postingDB struct {
+	ID          uint64    `db:"id"`
+	Source      string    `db:"source"`
+	Reference   string    `db:"reference"`
+	Timestamp   time.Time `db:"timestamp"`
+	OccurredAt  time.Time `db:"occurred_at"`
+	Description string    `db:"description"`
+	Account     string    `db:"account"`
+	Token       string    `db:"token"`
+	Debit       float64   `db:"debit"`
+	Credit      float64   `db:"credit"`
+}
+
+// GetEntries returns the entries posted in from..to sorted by timestamp.
+func (s *Storage) GetEntries(from, to time.Time) ([]common.Entry, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		dbResult []postingDB
+		result   []common.Entry
+	)
+	const selectStmt = `SELECT e.id, e.source, e.reference, e.timestamp, e.occurred_at, e.description,
+	p.account, p.token, p.debit, p.credit
+	FROM ledger_entries AS e JOIN ledger_postings AS p ON p.entry_id = e.id
+	WHERE e.timestamp >= $1 AND e.timestamp < $2
+	ORDER BY e.timestamp, e.id;`
+	logger.Debugw("querying entries", "query", selectStmt)
+	if err := s.db.Select(&dbResult, selectStmt, from, to); err != nil {
+		return nil, err
+	}
+	for _, r := range dbResult {
+		if len(result) == 0 || result[len(result)-1].ID != r.ID {
+			result = append(result, common.Entry{
+				ID:          r.ID,
+				Source:      common.Source(r.Source),
+				Reference:   r.Reference,
+				Timestamp:   r.Timestamp,
+				OccurredAt:  r.OccurredAt,
+				Description: r.Description,
+			})
+		}
+		last := &result[len(result)-1]
+		last.Postings = append(last.Postings, common.Posting{
+			Account: r.Account,
+			Token:   r.Token,
+			Debit:   r.Debit,
+			Credit:  r.Credit,
+		})
+	}
+	return result, nil
+}
+
+type accountDB struct {
+	Account string `db:"account"`
+	Token   string `db:"token"`
+}
+
+// GetAccounts returns the accounts and tokens that have postings.
+func (s *Storage) GetAccounts() ([]common.Account, error) {
+	var (
+		logger   = s.sugar.With("func", caller.GetCurrentFunctionName())
+		dbResult []accountDB
+		result   []common.Account
+	)
+	const selectStmt = `SELECT DISTINCT account, token FROM ledger_postings ORDER BY account, token;`
+	logger.Debugw("querying accounts", "query", selectStmt)
+	if err := s.db.Select(&dbResult, selectStmt); err != nil {
+		return nil, err
+	}
+	for _, r := range dbResult {
+		typ, _ := common.TypeOf(r.Account)
+		result = append(result, common.Account{Code: r.Account, Type: typ, Token: r.Token})
+	}
+	return result, nil
+}
+
+type periodDB struct {
+	ID       uint64    `db:"id"`
+	End      time.Time `db:"end_time"`
+	ClosedAt time.Time `db:"closed_at"`
+}
+
+// GetPeriods returns the closed periods sorted by end time, the first period starts from the beginning.
+func (s *Storage) GetPeriods() ([]common.Period, error) {
+	var (
+		logger   = s.sugar.With("func", caller.GetCurrentFunctionName())
+		dbResult []periodDB
+		result   []common.Period
+		start    time.Time
+	)
+	const selectStmt = `SELECT id, end_time, closed_at FROM ledger_periods ORDER BY end_time;`
+	logger.Debugw("querying periods", "query", selectStmt)
+	if err := s.db.Select(&dbResult, selectStmt); err != nil {
+		return nil, err
+	}
+	for _, r := range dbResult {
+		result = append(result, common.Period{ID: r.ID, Start: start, End: r.End, ClosedAt: r.ClosedAt})
+		start = r.End
+	}
+	return result, nil
+}
+
+// ClosePeriod closes the period from the end of the last closed period to end. The balances of income and
+// expense accounts are closed to retained earnings by an entry posted at end.
+func (s *Storage) ClosePeriod(end time.Time) (period common.Period, err error) {
+	logger := s.sugar.With("func", caller.GetCurrentFunctionName(), "end", end)
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return common.Period{}, err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+
+	if _, err = tx.Exec(`LOCK TABLE ledger_periods IN EXCLUSIVE MODE;`); err != nil {
+		return common.Period{}, err
+	}
+	start, err := lastEnd(tx)
+	if err != nil {
+		return common.Period{}, err
+	}
+	if !end.After(start) {
+		return common.Period{}, storage.ErrPeriodClosed
+	}
+
+	var balances []common.Balance
+	if err = tx.Select(&balances, getBalancesStmt, end); err != nil {
+		return common.Period{}, err
+	}
+	if entry, ok := common.ClosingEntry(balances, end); ok {
+		if _, err = insertEntry(tx, entry); err != nil {
+			return common.Period{}, err
+		}
+	}
+
+	period = common.Period{Start: start, End: end, ClosedAt: time.Now()}
+	const insertStmt = `INSERT INTO ledger_periods (end_time, closed_at) VALUES ($1, $2) RETURNING id;`
+	if err = tx.Get(&period.ID, insertStmt, period.End, period.ClosedAt); err != nil {
+		return common.Period{}, err
+	}
+	logger.Infow("period closed", "start", start, "id", period.ID)
+	return period, nil
+}
@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq" // sql driver name: "postgres"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/ledger/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+)
+
+func TestStorage(t *testing.T) {
+	sugar := testutil.MustNewDevelopmentSugaredLogger()
+	db, teardown := testutil.MustNewDevelopmentDB()
+	defer func() {
+		assert.NoError(t, teardown())
+	}()
+
+	s, err := NewStorage(sugar, db)
+	require.NoError(t, err)
+
+	last, err := s.GetLastOccurredAt()
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	ts := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []common.Entry{
+		common.GasEntry(common.GasFee{Hash: "0x1", From: "0xa", Amount: 0.5, Timestamp: ts}),
+		common.GasEntry(common.GasFee{Hash: "0x2", From: "0xa", Amount: 0.25, Timestamp: ts.Add(time.Hour)}),
+	}
+	inserted, err := s.InsertEntries(entries)
+	require.NoError(t, err)
+	assert.Equal(t, 2, inserted)
+	// inserting twice does not duplicate entries
+	inserted, err = s.InsertEntries(entries)
+	require.NoError(t, err)
+	assert.Equal(t, 0, inserted)
+
+	last, err = s.GetLastOccurredAt()
+	require.NoError(t, err)
+	assert.True(t, ts.Add(time.Hour).Equal(last))
+
+	stored, err := s.GetEntries(ts, ts.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Len(t, stored[0].Postings, 2)
+
+	balances, err := s.GetBalances(ts.Add(2 * time.Hour))
+	require.NoError(t, err)
+	assert.Contains(t, balances, common.Balance{Account: common.GasAccount, Token: "ETH", Debit: 0.75})
+
+	accounts, err := s.GetAccounts()
+	require.NoError(t, err)
+	assert.Len(t, accounts, 2)
+
+	period, err := s.ClosePeriod(ts.Add(30 * time.Minute))
+	require.NoError(t, err)
+	assert.True(t, period.Start.IsZero())
+	_, err = s.ClosePeriod(ts)
+	assert.Equal(t, storage.ErrPeriodClosed, err)
+
+	// the entry of closed period is posted at the end of the period
+	inserted, err = s.InsertEntries([]common.Entry{
+		common.GasEntry(common.GasFee{Hash: "0x3", From: "0xa", Amount: 1, Timestamp: ts}),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, inserted)
+	stored, err = s.GetEntries(period.End, period.End.Add(time.Millisecond))
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	assert.Equal(t, common.SourceClose, stored[0].Source)
+	assert.True(t, ts.Equal(stored[1].OccurredAt))
+
+	balances, err = s.GetBalances(period.End.Add(time.Millisecond))
+	require.NoError(t, err)
+	assert.Contains(t, balances, common.Balance{Account: common.GasAccount, Token: "ETH", Debit: 1.5, Credit: 0.5})
+	assert.Contains(t, balances, common.Balance{Account: common.RetainedEarningsAccount, Token: "ETH", Debit: 0.5})
+
+	periods, err := s.GetPeriods()
+	require.NoError(t, err)
+	require.Len(t, periods, 1)
+	assert.Equal(t, period.ID, periods[0].ID)
+}
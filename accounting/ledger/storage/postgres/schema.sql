@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS ledger_entries
+(
+    id          SERIAL PRIMARY KEY,
+    source      TEXT        NOT NULL,
+    reference   TEXT        NOT NULL,
+    timestamp   TIMESTAMPTZ NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    description TEXT        NOT NULL,
+    CONSTRAINT ledger_entries_reference_key UNIQUE (source, reference)
+);
+CREATE INDEX IF NOT EXISTS ledger_entries_timestamp_idx ON ledger_entries (timestamp);
+
+CREATE TABLE IF NOT EXISTS ledger_postings
+(
+    entry_id INTEGER NOT NULL REFERENCES ledger_entries (id),
+    account  TEXT    NOT NULL,
+    token    TEXT    NOT NULL,
+    debit    NUMERIC NOT NULL DEFAULT 0,
+    credit   NUMERIC NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS ledger_postings_entry_id_idx ON ledger_postings (entry_id);
+
+CREATE TABLE IF NOT EXISTS ledger_periods
+(
+    id        SERIAL PRIMARY KEY,
+    end_time  TIMESTAMPTZ NOT NULL UNIQUE,
+    closed_at TIMESTAMPTZ NOT NULL
+);
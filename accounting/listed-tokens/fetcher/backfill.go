@@ -0,0 +1,239 @@
+package fetcher
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/listed-tokens/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/contracts"
+)
+
+// Backfiller stores listed tokens snapshots of past blocks, built from ListReservePairs events of KyberStorage
+// contracts and from conversion rates contracts of reserves.
+type Backfiller struct {
+	sugar         *zap.SugaredLogger
+	ethClient     *ethclient.Client
+	fetcher       *Fetcher
+	storage       storage.Interface
+	kyberStorages []ethereum.Address
+	blockTimes    map[uint64]time.Time
+}
+
+// NewBackfiller creates a new Backfiller instance.
+func NewBackfiller(sugar *zap.SugaredLogger, ethClient *ethclient.Client, fetcher *Fetcher, storage storage.Interface,
+	kyberStorages []ethereum.Address) *Backfiller {
+	return &Backfiller{
+		sugar:         sugar,
+		ethClient:     ethClient,
+		fetcher:       fetcher,
+		storage:       storage,
+		kyberStorages: kyberStorages,
+		blockTimes:    make(map[uint64]time.Time),
+	}
+}
+
+// blockTime returns the timestamp of the block.
+func (b *Backfiller) blockTime(block uint64) (time.Time, error) {
+	if ts, ok := b.blockTimes[block]; ok {
+		return ts, nil
+	}
+	header, err := b.ethClient.HeaderByNumber(context.Background(), new(big.Int).SetUint64(block))
+	if err != nil {
+		return time.Time{}, err
+	}
+	ts := time.Unix(int64(header.Time), 0).UTC()
+	b.blockTimes[block] = ts
+	return ts, nil
+}
+
+func (b *Backfiller) save(reserve ethereum.Address, block uint64, tokens []ethereum.Address, source string) error {
+	ts, err := b.blockTime(block)
+	if err != nil {
+		return err
+	}
+	saved, err := b.storage.SaveSnapshot(common.ListedTokensSnapshot{
+		Reserve:     reserve,
+		BlockNumber: block,
+		Timestamp:   ts,
+		Tokens:      tokens,
+		Source:      source,
+	})
+	if err != nil {
+		return err
+	}
+	b.sugar.Debugw("backfilled listed tokens snapshot",
+		"reserve", reserve,
+		"block", block,
+		"source", source,
+		"saved", saved)
+	return nil
+}
+
+// deployed returns true if the contract has code at the block.
+func (b *Backfiller) deployed(contract ethereum.Address, block uint64) (bool, error) {
+	code, err := b.ethClient.CodeAt(context.Background(), contract, new(big.Int).SetUint64(block))
+	if err != nil {
+		return false, err
+	}
+	return len(code) != 0, nil
+}
+
+type pairEvent struct {
+	token    ethereum.Address
+	block    uint64
+	logIndex uint
+	add      bool
+}
+
+// pairEvents returns ListReservePairs events of the reserves in block range, grouped by reserve and ordered.
+func (b *Backfiller) pairEvents(reserves []ethereum.Address, fromBlock, toBlock uint64) (map[ethereum.Address][]pairEvent, error) {
+	var (
+		tracked = make(map[ethereum.Address]struct{}, len(reserves))
+		result  = make(map[ethereum.Address][]pairEvent)
+	)
+	for _, reserve := range reserves {
+		tracked[reserve] = struct{}{}
+	}
+
+	for _, addr := range b.kyberStorages {
+		filterer, err := contracts.NewKyberStorageFilterer(addr, b.ethClient)
+		if err != nil {
+			return nil, err
+		}
+		it, err := filterer.FilterListReservePairs(&bind.FilterOpts{Start: fromBlock, End: &toBlock}, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() {
+			if _, ok := tracked[it.Event.Reserve]; !ok {
+				continue
+			}
+			// pairs are listed as ETH-token and token-ETH
+			token := it.Event.Dest
+			if token == blockchain.ETHAddr {
+				token = it.Event.Src
+			}
+			result[it.Event.Reserve] = append(result[it.Event.Reserve], pairEvent{
+				token:    token,
+				block:    it.Event.Raw.BlockNumber,
+				logIndex: it.Event.Raw.Index,
+				add:      it.Event.Add,
+			})
+		}
+		if err = it.Error(); err != nil {
+			return nil, err
+		}
+		if err = it.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	for reserve := range result {
+		events := result[reserve]
+		sort.Slice(events, func(i, j int) bool {
+			if events[i].block != events[j].block {
+				return events[i].block < events[j].block
+			}
+			return events[i].logIndex < events[j].logIndex
+		})
+	}
+	return result, nil
+}
+
+// BackfillFromKyberStorage stores a snapshot for every block in range having ListReservePairs events of the
+// reserves. Events are applied on top of the stored kyber storage snapshot before fromBlock, or an empty token set if
+// there is none.
+func (b *Backfiller) BackfillFromKyberStorage(reserves []ethereum.Address, fromBlock, toBlock uint64) error {
+	var logger = b.sugar.With(
+		"func", caller.GetCurrentFunctionName(),
+		"from_block", fromBlock,
+		"to_block", toBlock,
+	)
+
+	eventsByReserve, err := b.pairEvents(reserves, fromBlock, toBlock)
+	if err != nil {
+		return err
+	}
+
+	for reserve, events := range eventsByReserve {
+		logger.Infow("backfilling listed tokens from kyber storage events", "reserve", reserve, "events", len(events))
+		tokens := make(map[ethereum.Address]struct{})
+		if fromBlock > 0 {
+			base, err := b.storage.GetSourceSnapshotAtBlock(reserve, common.ListedTokensSourceKyberStorage, fromBlock-1)
+			switch err {
+			case nil:
+				for _, token := range base.Tokens {
+					tokens[token] = struct{}{}
+				}
+			case storage.ErrNotFound:
+			default:
+				return err
+			}
+		}
+
+		for i, event := range events {
+			if event.add {
+				tokens[event.token] = struct{}{}
+			} else {
+				delete(tokens, event.token)
+			}
+			// snapshot is taken after the last event of each block
+			if i+1 < len(events) && events[i+1].block == event.block {
+				continue
+			}
+			var listed []ethereum.Address
+			for token := range tokens {
+				listed = append(listed, token)
+			}
+			if err = b.save(reserve, event.block, listed, common.ListedTokensSourceKyberStorage); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// BackfillFromReserve stores snapshots of tokens listed in conversion rates contracts of the reserves,
+// read at every step blocks in range. Blocks the reserve is not deployed yet are skipped.
+func (b *Backfiller) BackfillFromReserve(reserves []ethereum.Address, fromBlock, toBlock, step uint64) error {
+	var logger = b.sugar.With(
+		"func", caller.GetCurrentFunctionName(),
+		"from_block", fromBlock,
+		"to_block", toBlock,
+		"step", step,
+	)
+
+	for _, reserve := range reserves {
+		logger.Infow("backfilling listed tokens from reserve contract", "reserve", reserve)
+		for block := fromBlock; block <= toBlock; block += step {
+			tokens, err := b.fetcher.GetListedTokenAddresses(new(big.Int).SetUint64(block), reserve)
+			if err != nil {
+				deployed, dErr := b.deployed(reserve, block)
+				if dErr != nil {
+					return dErr
+				}
+				if deployed {
+					return err
+				}
+				logger.Debugw("reserve contract is not deployed at block, skipping",
+					"reserve", reserve,
+					"block", block)
+				continue
+			}
+			if err = b.save(reserve, block, tokens, common.ListedTokensSourceReserve); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
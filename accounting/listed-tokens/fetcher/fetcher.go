@@ -67,16 +67,9 @@ func updateListedToken(listedToken map[string]common.ListedToken, symbol, name s
 	return listedToken
 }
 
-// GetListedToken return listed token for a reserve address
-func (f *Fetcher) GetListedToken(block *big.Int, reserveAddr ethereum.Address,
-	tokenSymbol *blockchain.TokenInfoGetter) ([]common.ListedToken, error) {
-	var (
-		logger       = f.sugar.With("func", caller.GetCurrentFunctionName())
-		result       = make(map[string]common.ListedToken)
-		returnResult []common.ListedToken
-	)
+// GetListedTokenAddresses return addresses of tokens listed in conversion rates contract of a reserve
+func (f *Fetcher) GetListedTokenAddresses(block *big.Int, reserveAddr ethereum.Address) ([]ethereum.Address, error) {
 	// step 1: get conversionRatesContract address
-	logger.Infow("reserve address", "reserve", reserveAddr)
 	reserveContractClient, err := contracts.NewReserve(reserveAddr, f.ethClient)
 	if err != nil {
 		return nil, err
@@ -92,7 +85,19 @@ func (f *Fetcher) GetListedToken(block *big.Int, reserveAddr ethereum.Address,
 	if err != nil {
 		return nil, err
 	}
-	listedTokens, err := conversionRateContractClient.GetListedTokens(callOpts)
+	return conversionRateContractClient.GetListedTokens(callOpts)
+}
+
+// GetListedToken return listed token for a reserve address
+func (f *Fetcher) GetListedToken(block *big.Int, reserveAddr ethereum.Address,
+	tokenSymbol *blockchain.TokenInfoGetter) ([]common.ListedToken, error) {
+	var (
+		logger       = f.sugar.With("func", caller.GetCurrentFunctionName())
+		result       = make(map[string]common.ListedToken)
+		returnResult []common.ListedToken
+	)
+	logger.Infow("reserve address", "reserve", reserveAddr)
+	listedTokens, err := f.GetListedTokenAddresses(block, reserveAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -7,32 +7,42 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	"github.com/KyberNetwork/reserve-stats/accounting/listed-tokens/storage"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
 	_ "github.com/KyberNetwork/reserve-stats/lib/httputil/validators" // import custom validator functions
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 	ethereum "github.com/ethereum/go-ethereum/common"
 )
 
-//Server struct for listed token api
+// Server struct for listed token api
 type Server struct {
-	sugar   *zap.SugaredLogger
-	r       *gin.Engine
-	host    string
-	storage storage.Interface
+	sugar          *zap.SugaredLogger
+	r              *gin.Engine
+	host           string
+	storage        storage.Interface
+	openAPIOptions []openapi.Option
 }
 
 type reserveTokenQuery struct {
 	Reserve string `form:"reserve"`
 }
 
-//NewServer return new server object
-func NewServer(sugar *zap.SugaredLogger, host string, storage storage.Interface) *Server {
+type reserveTokenResponse struct {
+	Version     uint64               `json:"version"`
+	BlockNumber uint64               `json:"block_number"`
+	Data        []common.ListedToken `json:"data"`
+}
+
+// NewServer return new server object
+func NewServer(sugar *zap.SugaredLogger, host string, storage storage.Interface, openAPIOptions ...openapi.Option) *Server {
 	r := gin.Default()
 	return &Server{
-		sugar:   sugar,
-		r:       r,
-		host:    host,
-		storage: storage,
+		sugar:          sugar,
+		r:              r,
+		host:           host,
+		storage:        storage,
+		openAPIOptions: openAPIOptions,
 	}
 }
 
@@ -52,19 +62,44 @@ func (s *Server) getReserveToken(c *gin.Context) {
 	}
 	c.JSON(
 		http.StatusOK,
-		gin.H{
-			"version":      version,
-			"block_number": blockNumber,
-			"data":         listedTokens,
+		reserveTokenResponse{
+			Version:     version,
+			BlockNumber: blockNumber,
+			Data:        listedTokens,
 		},
 	)
 }
 
 func (s *Server) register() {
-	s.r.GET("/reserve/tokens", s.getReserveToken)
+	api := openapi.NewRouter(s.r, openapi.NewSpec("listed tokens"), s.openAPIOptions...)
+	api.GET("/reserve/tokens", openapi.Endpoint{
+		Summary:  "tokens listed in reserve",
+		Query:    reserveTokenQuery{},
+		Response: reserveTokenResponse{},
+	}, s.getReserveToken)
+	api.GET("/reserve/tokens/versions", openapi.Endpoint{
+		Summary:  "snapshots of tokens listed in reserve",
+		Query:    reserveSnapshotsQuery{},
+		Response: []common.ListedTokensSnapshot{},
+	}, s.getSnapshots)
+	api.GET("/reserve/tokens/events", openapi.Endpoint{
+		Summary:  "token listing and delisting events of reserve",
+		Query:    listingEventsQuery{},
+		Response: []common.ListingEvent{},
+	}, s.getListingEvents)
+	api.GET("/reserve/tokens/at", openapi.Endpoint{
+		Summary:  "tokens listed in reserve at a block or time",
+		Query:    tokensAtQuery{},
+		Response: common.ListedTokensSnapshot{},
+	}, s.getTokensAt)
+	api.GET("/reserve/tokens/diff", openapi.Endpoint{
+		Summary:  "tokens added and removed between two versions of reserve",
+		Query:    tokensDiffQuery{},
+		Response: common.ListedTokensDiff{},
+	}, s.getTokensDiff)
 }
 
-//Run server
+// Run server
 func (s *Server) Run() error {
 	s.register()
 	return s.r.Run(s.host)
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/listed-tokens/storage"
+	"github.com/KyberNetwork/reserve-stats/accounting/listed-tokens/timeline"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// responseSnapshotError responds 404 for missing snapshots and 500 for other errors.
+func responseSnapshotError(c *gin.Context, err error) {
+	if err == storage.ErrNotFound {
+		httputil.ResponseFailure(c, http.StatusNotFound, err)
+		return
+	}
+	httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+}
+
+type reserveSnapshotsQuery struct {
+	Reserve string `form:"reserve" binding:"required,isAddress"`
+}
+
+func (s *Server) getSnapshots(c *gin.Context) {
+	var query reserveSnapshotsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	snapshots, err := s.storage.GetSnapshots(ethereum.HexToAddress(query.Reserve))
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	if snapshots == nil {
+		snapshots = []common.ListedTokensSnapshot{}
+	}
+	c.JSON(http.StatusOK, snapshots)
+}
+
+type listingEventsQuery struct {
+	Reserve string `form:"reserve" binding:"required,isAddress"`
+	// Token filters events of a token, default is all tokens.
+	Token string `form:"token" binding:"omitempty,isAddress"`
+	// Source filters events found in snapshots of a source, default is all sources.
+	Source string `form:"source" binding:"omitempty,oneof=reserve kyber_storage"`
+}
+
+func (s *Server) getListingEvents(c *gin.Context) {
+	var query listingEventsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	snapshots, err := s.storage.GetSnapshots(ethereum.HexToAddress(query.Reserve))
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	if query.Source != "" {
+		snapshots = timeline.FilterSource(snapshots, query.Source)
+	}
+	events := timeline.Events(snapshots)
+	if query.Token != "" {
+		events = timeline.FilterToken(events, ethereum.HexToAddress(query.Token))
+	}
+	if events == nil {
+		events = []common.ListingEvent{}
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+type tokensAtQuery struct {
+	Reserve string `form:"reserve" binding:"required,isAddress"`
+	// Block is the block number to return the listed tokens at.
+	Block uint64 `form:"block"`
+	// Timestamp is the time in millis to return the listed tokens at, used if block is not provided.
+	Timestamp uint64 `form:"timestamp"`
+}
+
+func (s *Server) getTokensAt(c *gin.Context) {
+	var (
+		query    tokensAtQuery
+		snapshot common.ListedTokensSnapshot
+		err      error
+	)
+	if err = c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	reserve := ethereum.HexToAddress(query.Reserve)
+	switch {
+	case query.Block != 0:
+		snapshot, err = s.storage.GetSnapshotAtBlock(reserve, query.Block)
+	case query.Timestamp != 0:
+		snapshot, err = s.storage.GetSnapshotAtTime(reserve, timeutil.TimestampMsToTime(query.Timestamp))
+	default:
+		httputil.ResponseFailure(c, http.StatusBadRequest, errors.New("either block or timestamp is required"))
+		return
+	}
+	if err != nil {
+		responseSnapshotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, &snapshot)
+}
+
+type tokensDiffQuery struct {
+	Reserve     string `form:"reserve" binding:"required,isAddress"`
+	FromVersion uint64 `form:"from_version" binding:"required"`
+	ToVersion   uint64 `form:"to_version" binding:"required"`
+}
+
+func (s *Server) getTokensDiff(c *gin.Context) {
+	var query tokensDiffQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	reserve := ethereum.HexToAddress(query.Reserve)
+	from, err := s.storage.GetSnapshot(reserve, query.FromVersion)
+	if err != nil {
+		responseSnapshotError(c, err)
+		return
+	}
+	to, err := s.storage.GetSnapshot(reserve, query.ToVersion)
+	if err != nil {
+		responseSnapshotError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, timeline.Diff(from, to))
+}
@@ -1,14 +1,34 @@
 package storage
 
 import (
+	"errors"
 	"math/big"
+	"time"
 
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	ethereum "github.com/ethereum/go-ethereum/common"
 )
 
+// ErrNotFound is returned when the requested snapshot does not exist.
+var ErrNotFound = errors.New("listed tokens snapshot not found")
+
 //Interface represent interface for accounting lsited token service
 type Interface interface {
 	CreateOrUpdate(tokens []common.ListedToken, blockNumber *big.Int, reserve ethereum.Address) error
 	GetTokens(reserve ethereum.Address) ([]common.ListedToken, uint64, uint64, error)
+	// SaveSnapshot stores the snapshot and returns true if it is saved, versions of the reserve follow block
+	// order so the snapshots after a backfilled one get the next versions. The snapshot is skipped if the
+	// reserve has a snapshot at the block or the tokens are unchanged from the snapshot of the same source
+	// before it.
+	SaveSnapshot(snapshot common.ListedTokensSnapshot) (bool, error)
+	// GetSnapshots returns all snapshots of the reserve, ordered by block number.
+	GetSnapshots(reserve ethereum.Address) ([]common.ListedTokensSnapshot, error)
+	// GetSnapshot returns the snapshot of the reserve with given version.
+	GetSnapshot(reserve ethereum.Address, version uint64) (common.ListedTokensSnapshot, error)
+	// GetSnapshotAtBlock returns the latest snapshot of the reserve at or before the block.
+	GetSnapshotAtBlock(reserve ethereum.Address, blockNumber uint64) (common.ListedTokensSnapshot, error)
+	// GetSourceSnapshotAtBlock returns the latest snapshot of the reserve from the source at or before the block.
+	GetSourceSnapshotAtBlock(reserve ethereum.Address, source string, blockNumber uint64) (common.ListedTokensSnapshot, error)
+	// GetSnapshotAtTime returns the latest snapshot of the reserve at or before the time.
+	GetSnapshotAtTime(reserve ethereum.Address, at time.Time) (common.ListedTokensSnapshot, error)
 }
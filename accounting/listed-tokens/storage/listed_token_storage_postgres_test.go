@@ -123,3 +123,115 @@ func TestListedTokenStorage(t *testing.T) {
 	assert.Equal(t, uint64(3), version)
 	assert.Equal(t, blockNumber.Uint64(), storedBlockNumber)
 }
+
+func TestListedTokensSnapshotStorage(t *testing.T) {
+	logger := testutil.MustNewDevelopmentSugaredLogger()
+
+	var (
+		reserve = ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		knc     = ethereum.HexToAddress("0xdd974D5C2e2928deA5F71b9825b8b646686BD200")
+		appc    = ethereum.HexToAddress("0x1a7a8BD9106F2B8D977E08582DC7d24c723ab0DB")
+		first   = common.ListedTokensSnapshot{
+			Reserve:     reserve,
+			BlockNumber: 7442895,
+			Timestamp:   timeutil.TimestampMsToTime(1553241328000).UTC(),
+			Tokens:      []ethereum.Address{knc, appc},
+			Source:      common.ListedTokensSourceReserve,
+		}
+		second = common.ListedTokensSnapshot{
+			Reserve:     reserve,
+			BlockNumber: 7442999,
+			Timestamp:   timeutil.TimestampMsToTime(1553242588000).UTC(),
+			Tokens:      []ethereum.Address{knc},
+			Source:      common.ListedTokensSourceReserve,
+		}
+		backfilled = common.ListedTokensSnapshot{
+			Reserve:     reserve,
+			BlockNumber: 7442000,
+			Timestamp:   timeutil.TimestampMsToTime(1553229388000).UTC(),
+			Tokens:      []ethereum.Address{appc},
+			Source:      common.ListedTokensSourceKyberStorage,
+		}
+		// same tokens as the previous snapshot of reserve source, changed from the one of kyber storage source
+		kyberStorageChanged = common.ListedTokensSnapshot{
+			Reserve:     reserve,
+			BlockNumber: 7443000,
+			Timestamp:   timeutil.TimestampMsToTime(1553242600000).UTC(),
+			Tokens:      []ethereum.Address{knc},
+			Source:      common.ListedTokensSourceKyberStorage,
+		}
+	)
+
+	db, teardown := testutil.MustNewDevelopmentDB()
+	storage, err := NewDB(logger, db)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, teardown())
+	}()
+
+	saved, err := storage.SaveSnapshot(first)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	// unchanged tokens are not saved
+	unchanged := first
+	unchanged.BlockNumber++
+	unchanged.Tokens = []ethereum.Address{appc, knc}
+	saved, err = storage.SaveSnapshot(unchanged)
+	require.NoError(t, err)
+	assert.False(t, saved)
+
+	saved, err = storage.SaveSnapshot(second)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	saved, err = storage.SaveSnapshot(backfilled)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	saved, err = storage.SaveSnapshot(kyberStorageChanged)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	// a snapshot at a block having one is not saved, whatever its source is
+	sameBlock := kyberStorageChanged
+	sameBlock.Tokens = []ethereum.Address{appc}
+	sameBlock.Source = common.ListedTokensSourceReserve
+	saved, err = storage.SaveSnapshot(sameBlock)
+	require.NoError(t, err)
+	assert.False(t, saved)
+
+	snapshots, err := storage.GetSnapshots(reserve)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 4)
+	// versions follow block order, backfilled snapshot is the first one
+	assert.Equal(t, []uint64{1, 2, 3, 4},
+		[]uint64{snapshots[0].Version, snapshots[1].Version, snapshots[2].Version, snapshots[3].Version})
+	assert.Equal(t, common.ListedTokensSourceKyberStorage, snapshots[0].Source)
+
+	bySource, err := storage.GetSourceSnapshotAtBlock(reserve, common.ListedTokensSourceKyberStorage, 7442999)
+	require.NoError(t, err)
+	assert.Equal(t, backfilled.BlockNumber, bySource.BlockNumber)
+	_, err = storage.GetSourceSnapshotAtBlock(reserve, common.ListedTokensSourceReserve, 7442894)
+	assert.Equal(t, ErrNotFound, err)
+
+	atBlock, err := storage.GetSnapshotAtBlock(reserve, 7442998)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), atBlock.Version)
+	assert.ElementsMatch(t, []ethereum.Address{knc, appc}, atBlock.Tokens)
+
+	atTime, err := storage.GetSnapshotAtTime(reserve, timeutil.TimestampMsToTime(1553242588000))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), atTime.Version)
+
+	_, err = storage.GetSnapshotAtBlock(reserve, 7441999)
+	assert.Equal(t, ErrNotFound, err)
+
+	byVersion, err := storage.GetSnapshot(reserve, 1)
+	require.NoError(t, err)
+	assert.Equal(t, backfilled.BlockNumber, byVersion.BlockNumber)
+
+	_, err = storage.GetSnapshot(reserve, 5)
+	assert.Equal(t, ErrNotFound, err)
+}
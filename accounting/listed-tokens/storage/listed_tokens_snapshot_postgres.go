@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/lib/pq"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
+)
+
+type listedTokensSnapshotRecord struct {
+	Version     uint64         `db:"version"`
+	Reserve     string         `db:"reserve"`
+	BlockNumber uint64         `db:"block_number"`
+	Timestamp   time.Time      `db:"timestamp"`
+	Tokens      pq.StringArray `db:"tokens"`
+	Source      string         `db:"source"`
+}
+
+// Snapshot converts listedTokensSnapshotRecord instance to a common.ListedTokensSnapshot.
+func (r *listedTokensSnapshotRecord) Snapshot() common.ListedTokensSnapshot {
+	snapshot := common.ListedTokensSnapshot{
+		Version:     r.Version,
+		Reserve:     ethereum.HexToAddress(r.Reserve),
+		BlockNumber: r.BlockNumber,
+		Timestamp:   r.Timestamp.UTC(),
+		Tokens:      []ethereum.Address{},
+		Source:      r.Source,
+	}
+	for _, token := range r.Tokens {
+		snapshot.Tokens = append(snapshot.Tokens, ethereum.HexToAddress(token))
+	}
+	return snapshot
+}
+
+// sortedHexes returns hex of tokens, sorted and without duplications.
+func sortedHexes(tokens []ethereum.Address) []string {
+	var (
+		seen   = make(map[ethereum.Address]struct{}, len(tokens))
+		result = []string{}
+	)
+	for _, token := range tokens {
+		if _, ok := seen[token]; ok {
+			continue
+		}
+		seen[token] = struct{}{}
+		result = append(result, token.Hex())
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+const selectSnapshotQuery = `SELECT snapshot.version,
+       reserve.address AS reserve,
+       snapshot.block_number,
+       snapshot.timestamp,
+       snapshot.tokens,
+       snapshot.source
+FROM "listed_tokens_snapshots" AS snapshot
+         JOIN "listed_tokens_reserves" AS reserve ON reserve.id = snapshot.reserve_id
+WHERE reserve.address = $1`
+
+//SaveSnapshot stores the snapshot if the tokens changed from the previous snapshot of the reserve from the same
+// source, versions of the reserve are renumbered in block order
+func (ltd *ListedTokenDB) SaveSnapshot(snapshot common.ListedTokensSnapshot) (saved bool, err error) {
+	var (
+		logger = ltd.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"reserve", snapshot.Reserve,
+			"block_number", snapshot.BlockNumber,
+		)
+		reserveID uint64
+		sameBlock bool
+		previous  []listedTokensSnapshotRecord
+		tokens    = sortedHexes(snapshot.Tokens)
+	)
+
+	const upsertReserveQuery = `INSERT INTO "listed_tokens_reserves"(address)
+VALUES ($1)
+ON CONFLICT (address) DO UPDATE SET address = EXCLUDED.address
+RETURNING id;`
+
+	const sameBlockQuery = `SELECT EXISTS(SELECT 1
+              FROM "listed_tokens_snapshots"
+              WHERE reserve_id = $1
+                AND block_number = $2);`
+
+	previousQuery := selectSnapshotQuery + ` AND snapshot.block_number < $2
+  AND snapshot.source = $3
+ORDER BY snapshot.block_number DESC
+LIMIT 1;`
+
+	const insertQuery = `INSERT INTO "listed_tokens_snapshots"(reserve_id, version, block_number, timestamp, tokens, source)
+SELECT $1, COALESCE(MAX(version), 0) + 1, $2, $3, $4, $5
+FROM "listed_tokens_snapshots"
+WHERE reserve_id = $1;`
+
+	// versions are renumbered in block order as a backfilled snapshot could be before the stored ones, changed
+	// versions are negated first to not conflict with the unchanged ones.
+	const negateVersionsQuery = `UPDATE "listed_tokens_snapshots" AS snapshot
+SET version = -ordered.version
+FROM (SELECT id, ROW_NUMBER() OVER (ORDER BY block_number) AS version
+      FROM "listed_tokens_snapshots"
+      WHERE reserve_id = $1) AS ordered
+WHERE snapshot.id = ordered.id
+  AND snapshot.version <> ordered.version;`
+	const renumberVersionsQuery = `UPDATE "listed_tokens_snapshots"
+SET version = -version
+WHERE reserve_id = $1
+  AND version < 0;`
+
+	tx, err := ltd.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+
+	if err = tx.Get(&reserveID, upsertReserveQuery, snapshot.Reserve.Hex()); err != nil {
+		return false, err
+	}
+
+	if err = tx.Get(&sameBlock, sameBlockQuery, reserveID, snapshot.BlockNumber); err != nil {
+		return false, err
+	}
+	if sameBlock {
+		logger.Debug("snapshot at block already exists")
+		return false, nil
+	}
+
+	logger.Debugw("querying previous snapshot", "query", previousQuery)
+	if err = tx.Select(&previous, previousQuery, snapshot.Reserve.Hex(), snapshot.BlockNumber, snapshot.Source); err != nil {
+		return false, err
+	}
+	if len(previous) != 0 && equalStrings(previous[0].Tokens, tokens) {
+		logger.Debug("listed tokens are unchanged from previous snapshot of the source")
+		return false, nil
+	}
+
+	logger.Debugw("saving listed tokens snapshot", "query", insertQuery)
+	if _, err = tx.Exec(insertQuery,
+		reserveID,
+		snapshot.BlockNumber,
+		snapshot.Timestamp.UTC(),
+		pq.StringArray(tokens),
+		snapshot.Source,
+	); err != nil {
+		return false, err
+	}
+	if _, err = tx.Exec(negateVersionsQuery, reserveID); err != nil {
+		return false, err
+	}
+	if _, err = tx.Exec(renumberVersionsQuery, reserveID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetSnapshots returns all snapshots of the reserve ordered by block number
+func (ltd *ListedTokenDB) GetSnapshots(reserve ethereum.Address) ([]common.ListedTokensSnapshot, error) {
+	var (
+		logger = ltd.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"reserve", reserve,
+		)
+		records []listedTokensSnapshotRecord
+		result  []common.ListedTokensSnapshot
+	)
+	query := selectSnapshotQuery + ` ORDER BY snapshot.block_number;`
+	logger.Debugw("querying listed tokens snapshots", "query", query)
+	if err := ltd.db.Select(&records, query, reserve.Hex()); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		result = append(result, record.Snapshot())
+	}
+	return result, nil
+}
+
+func (ltd *ListedTokenDB) getSnapshot(query string, args ...interface{}) (common.ListedTokensSnapshot, error) {
+	var (
+		logger = ltd.sugar.With("func", caller.GetCurrentFunctionName())
+		record listedTokensSnapshotRecord
+	)
+	logger.Debugw("querying listed tokens snapshot", "query", query, "args", args)
+	err := ltd.db.Get(&record, query, args...)
+	if err == sql.ErrNoRows {
+		return common.ListedTokensSnapshot{}, ErrNotFound
+	} else if err != nil {
+		return common.ListedTokensSnapshot{}, err
+	}
+	return record.Snapshot(), nil
+}
+
+// GetSnapshot returns the snapshot of the reserve with given version
+func (ltd *ListedTokenDB) GetSnapshot(reserve ethereum.Address, version uint64) (common.ListedTokensSnapshot, error) {
+	return ltd.getSnapshot(selectSnapshotQuery+` AND snapshot.version = $2;`, reserve.Hex(), version)
+}
+
+// GetSnapshotAtBlock returns the latest snapshot of the reserve at or before the block
+func (ltd *ListedTokenDB) GetSnapshotAtBlock(reserve ethereum.Address, blockNumber uint64) (common.ListedTokensSnapshot, error) {
+	return ltd.getSnapshot(selectSnapshotQuery+` AND snapshot.block_number <= $2
+ORDER BY snapshot.block_number DESC
+LIMIT 1;`, reserve.Hex(), blockNumber)
+}
+
+// GetSourceSnapshotAtBlock returns the latest snapshot of the reserve from the source at or before the block
+func (ltd *ListedTokenDB) GetSourceSnapshotAtBlock(reserve ethereum.Address, source string, blockNumber uint64) (common.ListedTokensSnapshot, error) {
+	return ltd.getSnapshot(selectSnapshotQuery+` AND snapshot.source = $2
+  AND snapshot.block_number <= $3
+ORDER BY snapshot.block_number DESC
+LIMIT 1;`, reserve.Hex(), source, blockNumber)
+}
+
+// GetSnapshotAtTime returns the latest snapshot of the reserve at or before the time
+func (ltd *ListedTokenDB) GetSnapshotAtTime(reserve ethereum.Address, at time.Time) (common.ListedTokensSnapshot, error) {
+	return ltd.getSnapshot(selectSnapshotQuery+` AND snapshot.timestamp <= $2
+ORDER BY snapshot.block_number DESC
+LIMIT 1;`, reserve.Hex(), at.UTC())
+}
@@ -41,6 +41,22 @@ CREATE TABLE IF NOT EXISTS "listed_tokens_reserves_tokens"
     PRIMARY KEY (token_id, reserve_id)
 );
 
+-- listed_tokens_snapshots table stores the tokens listed in a reserve from block_number until the next snapshot
+CREATE TABLE IF NOT EXISTS "listed_tokens_snapshots"
+(
+    id           SERIAL PRIMARY KEY,
+    reserve_id   INT       NOT NULL REFERENCES "listed_tokens_reserves" (id),
+    version      INT       NOT NULL,
+    block_number BIGINT    NOT NULL,
+    timestamp    TIMESTAMP NOT NULL,
+    tokens       TEXT[]    NOT NULL,
+    source       TEXT      NOT NULL,
+    UNIQUE (reserve_id, version),
+    UNIQUE (reserve_id, block_number)
+);
+
+CREATE INDEX IF NOT EXISTS "listed_tokens_snapshots_timestamp_idx" ON "listed_tokens_snapshots" (reserve_id, timestamp);
+
 -- save_token function saves or update given token to database and return TRUE if anything changes recorded to database.
 CREATE OR REPLACE FUNCTION save_token(_address "listed_tokens".address%TYPE,
                                       _name "listed_tokens".name%TYPE,
@@ -0,0 +1,95 @@
+package timeline
+
+import (
+	"sort"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+)
+
+// difference returns the tokens in a but not in b, sorted by address.
+func difference(a, b []ethereum.Address) []ethereum.Address {
+	var (
+		inB    = make(map[ethereum.Address]struct{}, len(b))
+		result []ethereum.Address
+	)
+	for _, token := range b {
+		inB[token] = struct{}{}
+	}
+	for _, token := range a {
+		if _, ok := inB[token]; !ok {
+			result = append(result, token)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Hex() < result[j].Hex()
+	})
+	return result
+}
+
+// Diff returns the tokens added and removed from snapshot from to snapshot to of a reserve.
+func Diff(from, to common.ListedTokensSnapshot) common.ListedTokensDiff {
+	return common.ListedTokensDiff{
+		Reserve:     to.Reserve,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Added:       difference(to.Tokens, from.Tokens),
+		Removed:     difference(from.Tokens, to.Tokens),
+	}
+}
+
+// Events returns the listing and delisting events of snapshots of a reserve, ordered by block number. Snapshots
+// of different sources could disagree, so events are found between snapshots of the same source and labelled with
+// it. All tokens of the first snapshot of a source are considered listed at its block.
+func Events(snapshots []common.ListedTokensSnapshot) []common.ListingEvent {
+	var (
+		events []common.ListingEvent
+		prev   = make(map[string]common.ListedTokensSnapshot)
+	)
+	for _, snapshot := range snapshots {
+		diff := Diff(prev[snapshot.Source], snapshot)
+		for _, token := range diff.Added {
+			events = append(events, newEvent(snapshot, token, true))
+		}
+		for _, token := range diff.Removed {
+			events = append(events, newEvent(snapshot, token, false))
+		}
+		prev[snapshot.Source] = snapshot
+	}
+	return events
+}
+
+func newEvent(snapshot common.ListedTokensSnapshot, token ethereum.Address, listed bool) common.ListingEvent {
+	return common.ListingEvent{
+		Reserve:     snapshot.Reserve,
+		Token:       token,
+		Listed:      listed,
+		BlockNumber: snapshot.BlockNumber,
+		Timestamp:   snapshot.Timestamp,
+		Version:     snapshot.Version,
+		Source:      snapshot.Source,
+	}
+}
+
+// FilterSource returns the snapshots of given source only.
+func FilterSource(snapshots []common.ListedTokensSnapshot, source string) []common.ListedTokensSnapshot {
+	var result []common.ListedTokensSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot.Source == source {
+			result = append(result, snapshot)
+		}
+	}
+	return result
+}
+
+// FilterToken returns the events of given token only.
+func FilterToken(events []common.ListingEvent, token ethereum.Address) []common.ListingEvent {
+	var result []common.ListingEvent
+	for _, event := range events {
+		if event.Token == token {
+			result = append(result, event)
+		}
+	}
+	return result
+}
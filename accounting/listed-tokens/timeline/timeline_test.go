@@ -0,0 +1,113 @@
+package timeline
+
+import (
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func TestEventsAndDiff(t *testing.T) {
+	var (
+		reserve  = ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		knc      = ethereum.HexToAddress("0xdd974D5C2e2928deA5F71b9825b8b646686BD200")
+		appc     = ethereum.HexToAddress("0x1a7a8BD9106F2B8D977E08582DC7d24c723ab0DB")
+		omg      = ethereum.HexToAddress("0xd26114cd6EE289AccF82350c8d8487fedB8A0C07")
+		snapshot = []common.ListedTokensSnapshot{
+			{
+				Version:     1,
+				Reserve:     reserve,
+				BlockNumber: 7442895,
+				Timestamp:   timeutil.TimestampMsToTime(1553241328000).UTC(),
+				Tokens:      []ethereum.Address{knc, appc},
+			},
+			{
+				Version:     2,
+				Reserve:     reserve,
+				BlockNumber: 7442899,
+				Timestamp:   timeutil.TimestampMsToTime(1553241388000).UTC(),
+				Tokens:      []ethereum.Address{knc, omg},
+			},
+			{
+				Version:     3,
+				Reserve:     reserve,
+				BlockNumber: 7443000,
+				Timestamp:   timeutil.TimestampMsToTime(1553242588000).UTC(),
+				Tokens:      []ethereum.Address{omg},
+			},
+		}
+	)
+
+	events := Events(snapshot)
+	assert.Equal(t, []common.ListingEvent{
+		{Reserve: reserve, Token: appc, Listed: true, BlockNumber: 7442895, Timestamp: snapshot[0].Timestamp, Version: 1},
+		{Reserve: reserve, Token: knc, Listed: true, BlockNumber: 7442895, Timestamp: snapshot[0].Timestamp, Version: 1},
+		{Reserve: reserve, Token: omg, Listed: true, BlockNumber: 7442899, Timestamp: snapshot[1].Timestamp, Version: 2},
+		{Reserve: reserve, Token: appc, Listed: false, BlockNumber: 7442899, Timestamp: snapshot[1].Timestamp, Version: 2},
+		{Reserve: reserve, Token: knc, Listed: false, BlockNumber: 7443000, Timestamp: snapshot[2].Timestamp, Version: 3},
+	}, events)
+
+	kncEvents := FilterToken(events, knc)
+	assert.Len(t, kncEvents, 2)
+	assert.True(t, kncEvents[0].Listed)
+	assert.False(t, kncEvents[1].Listed)
+
+	diff := Diff(snapshot[0], snapshot[2])
+	assert.Equal(t, common.ListedTokensDiff{
+		Reserve:     reserve,
+		FromVersion: 1,
+		ToVersion:   3,
+		Added:       []ethereum.Address{omg},
+		Removed:     []ethereum.Address{appc, knc},
+	}, diff)
+
+	assert.Empty(t, Diff(snapshot[1], snapshot[1]).Added)
+	assert.Empty(t, Diff(snapshot[1], snapshot[1]).Removed)
+}
+
+func TestEventsSources(t *testing.T) {
+	var (
+		reserve   = ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		knc       = ethereum.HexToAddress("0xdd974D5C2e2928deA5F71b9825b8b646686BD200")
+		appc      = ethereum.HexToAddress("0x1a7a8BD9106F2B8D977E08582DC7d24c723ab0DB")
+		snapshots = []common.ListedTokensSnapshot{
+			{
+				Version:     1,
+				Reserve:     reserve,
+				BlockNumber: 7442000,
+				Tokens:      []ethereum.Address{knc},
+				Source:      common.ListedTokensSourceKyberStorage,
+			},
+			{
+				Version:     2,
+				Reserve:     reserve,
+				BlockNumber: 7442895,
+				Tokens:      []ethereum.Address{knc, appc},
+				Source:      common.ListedTokensSourceReserve,
+			},
+			// kyber storage does not have appc, it is not delisted by comparing to the reserve snapshot
+			{
+				Version:     3,
+				Reserve:     reserve,
+				BlockNumber: 7442999,
+				Tokens:      []ethereum.Address{},
+				Source:      common.ListedTokensSourceKyberStorage,
+			},
+		}
+	)
+
+	assert.Equal(t, []common.ListingEvent{
+		{Reserve: reserve, Token: knc, Listed: true, BlockNumber: 7442000, Version: 1, Source: common.ListedTokensSourceKyberStorage},
+		{Reserve: reserve, Token: appc, Listed: true, BlockNumber: 7442895, Version: 2, Source: common.ListedTokensSourceReserve},
+		{Reserve: reserve, Token: knc, Listed: true, BlockNumber: 7442895, Version: 2, Source: common.ListedTokensSourceReserve},
+		{Reserve: reserve, Token: knc, Listed: false, BlockNumber: 7442999, Version: 3, Source: common.ListedTokensSourceKyberStorage},
+	}, Events(snapshots))
+
+	assert.Equal(t, []common.ListingEvent{
+		{Reserve: reserve, Token: appc, Listed: true, BlockNumber: 7442895, Version: 2, Source: common.ListedTokensSourceReserve},
+		{Reserve: reserve, Token: knc, Listed: true, BlockNumber: 7442895, Version: 2, Source: common.ListedTokensSourceReserve},
+	}, Events(FilterSource(snapshots, common.ListedTokensSourceReserve)))
+}
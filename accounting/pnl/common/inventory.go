@@ -0,0 +1,70 @@
+package common
+
+// lot is a quantity of token acquired at the same cost.
+type lot struct {
+	quantity float64
+	costETH  float64
+	costUSD  float64
+}
+
+// inventory is the holding of a token, lots are ordered by the acquired time.
+type inventory struct {
+	method Method
+	lots   []lot
+}
+
+func newInventory(method Method) *inventory {
+	return &inventory{method: method}
+}
+
+func (inv *inventory) quantity() float64 {
+	var result float64
+	for _, l := range inv.lots {
+		result += l.quantity
+	}
+	return result
+}
+
+func (inv *inventory) cost() (float64, float64) {
+	var costETH, costUSD float64
+	for _, l := range inv.lots {
+		costETH += l.costETH
+		costUSD += l.costUSD
+	}
+	return costETH, costUSD
+}
+
+func (inv *inventory) acquire(quantity, costETH, costUSD float64) {
+	if quantity <= 0 {
+		return
+	}
+	if inv.method == MethodAverage && len(inv.lots) != 0 {
+		inv.lots[0].quantity += quantity
+		inv.lots[0].costETH += costETH
+		inv.lots[0].costUSD += costUSD
+		return
+	}
+	inv.lots = append(inv.lots, lot{quantity: quantity, costETH: costETH, costUSD: costUSD})
+}
+
+// dispose removes quantity from the inventory and returns the cost of removed quantity and the quantity
+// that is not in the inventory.
+func (inv *inventory) dispose(quantity float64) (costETH, costUSD, uncovered float64) {
+	for quantity > 0 && len(inv.lots) != 0 {
+		l := &inv.lots[0]
+		if quantity < l.quantity {
+			ratio := quantity / l.quantity
+			costETH += l.costETH * ratio
+			costUSD += l.costUSD * ratio
+			l.quantity -= quantity
+			l.costETH -= l.costETH * ratio
+			l.costUSD -= l.costUSD * ratio
+			return costETH, costUSD, 0
+		}
+		costETH += l.costETH
+		costUSD += l.costUSD
+		quantity -= l.quantity
+		inv.lots = inv.lots[1:]
+	}
+	return costETH, costUSD, quantity
+}
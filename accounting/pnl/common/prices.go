@@ -0,0 +1,94 @@
+package common
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ethSymbol  = "ETH"
+	wethSymbol = "WETH"
+	day        = 24 * time.Hour
+)
+
+// usdStableCoins are priced at 1 USD if they have no rate.
+var usdStableCoins = map[string]bool{
+	"USDT": true,
+	"USDC": true,
+	"BUSD": true,
+	"DAI":  true,
+}
+
+// NormalizeToken returns the token symbol the inventory is kept in, WETH is kept as ETH.
+func NormalizeToken(token string) string {
+	token = strings.ToUpper(token)
+	if token == wethSymbol {
+		return ethSymbol
+	}
+	return token
+}
+
+type pricePoint struct {
+	date  time.Time
+	price float64
+}
+
+// Prices is the daily prices of tokens in ETH and ETH in USD. The price of a time is the latest daily price
+// at or before it.
+type Prices struct {
+	tokens map[string][]pricePoint
+	ethUSD []pricePoint
+}
+
+// NewPrices creates Prices from daily prices of tokens in ETH keyed by token then date, and daily ETH/USD rates.
+func NewPrices(tokenPrices map[string]map[time.Time]float64, ethUSD map[time.Time]float64) *Prices {
+	p := &Prices{tokens: make(map[string][]pricePoint)}
+	for token, prices := range tokenPrices {
+		p.tokens[NormalizeToken(token)] = toPricePoints(prices)
+	}
+	p.ethUSD = toPricePoints(ethUSD)
+	return p
+}
+
+func toPricePoints(prices map[time.Time]float64) []pricePoint {
+	var points []pricePoint
+	for date, price := range prices {
+		if price <= 0 {
+			continue
+		}
+		points = append(points, pricePoint{date: date, price: price})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].date.Before(points[j].date) })
+	return points
+}
+
+func priceAt(points []pricePoint, ts time.Time) (float64, bool) {
+	i := sort.Search(len(points), func(i int) bool { return points[i].date.After(ts) })
+	if i == 0 {
+		return 0, false
+	}
+	return points[i-1].price, true
+}
+
+// ETHUSD returns the price of ETH in USD at the time.
+func (p *Prices) ETHUSD(ts time.Time) (float64, bool) {
+	return priceAt(p.ethUSD, ts)
+}
+
+// ETHPrice returns the price of token in ETH at the time.
+func (p *Prices) ETHPrice(token string, ts time.Time) (float64, bool) {
+	token = NormalizeToken(token)
+	if token == ethSymbol {
+		return 1, true
+	}
+	if price, ok := priceAt(p.tokens[token], ts); ok {
+		return price, true
+	}
+	if usdStableCoins[token] {
+		if ethUSD, ok := p.ETHUSD(ts); ok {
+			return 1 / ethUSD, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,225 @@
+package common
+
+import (
+	"sort"
+	"time"
+)
+
+// builder keeps the inventory of all tokens while trades are processed in time order.
+type builder struct {
+	method      Method
+	prices      *Prices
+	inventories map[string]*inventory
+	// realized is the realized PnL in ETH and USD of each token in current day.
+	realized map[string]*[2]float64
+	// fees is the value in ETH and USD of each token paid as commission in current day.
+	fees map[string]*[2]float64
+}
+
+func (b *builder) inventory(token string) *inventory {
+	inv, ok := b.inventories[token]
+	if !ok {
+		inv = newInventory(b.method)
+		b.inventories[token] = inv
+	}
+	return inv
+}
+
+func add(amounts map[string]*[2]float64, token string, eth, usd float64) {
+	r, ok := amounts[token]
+	if !ok {
+		r = &[2]float64{}
+		amounts[token] = r
+	}
+	r[0] += eth
+	r[1] += usd
+}
+
+func (b *builder) realize(token string, pnlETH, pnlUSD float64) {
+	add(b.realized, token, pnlETH, pnlUSD)
+}
+
+// feeValue returns the value of trade fee in ETH and USD, the normalized value if the trade has one.
+func (b *builder) feeValue(trade Trade, feeToken string, ethUSD float64) (float64, float64) {
+	if trade.FeeETH != 0 {
+		feeUSD := trade.FeeUSD
+		if feeUSD == 0 {
+			feeUSD = trade.FeeETH * ethUSD
+		}
+		return trade.FeeETH, feeUSD
+	}
+	price, _ := b.prices.ETHPrice(feeToken, trade.Timestamp)
+	return trade.FeeAmount * price, trade.FeeAmount * price * ethUSD
+}
+
+// value returns the value of trade in ETH, from the ETH leg if there is one or the price of a token.
+func (b *builder) value(trade Trade) float64 {
+	switch {
+	case trade.Bought == ethSymbol:
+		return trade.BoughtAmount
+	case trade.Sold == ethSymbol:
+		return trade.SoldAmount
+	}
+	if price, ok := b.prices.ETHPrice(trade.Sold, trade.Timestamp); ok {
+		return trade.SoldAmount * price
+	}
+	if price, ok := b.prices.ETHPrice(trade.Bought, trade.Timestamp); ok {
+		return trade.BoughtAmount * price
+	}
+	return 0
+}
+
+// process applies a trade to inventories. Sold quantity that is not in the inventory is considered at the
+// trade value, so it realizes no PnL.
+func (b *builder) process(trade Trade) {
+	trade.Bought = NormalizeToken(trade.Bought)
+	trade.Sold = NormalizeToken(trade.Sold)
+	ethUSD, _ := b.prices.ETHUSD(trade.Timestamp)
+	valueETH := b.value(trade)
+	valueUSD := valueETH * ethUSD
+
+	if trade.SoldAmount > 0 {
+		costETH, costUSD, uncovered := b.inventory(trade.Sold).dispose(trade.SoldAmount)
+		ratio := uncovered / trade.SoldAmount
+		costETH += valueETH * ratio
+		costUSD += valueUSD * ratio
+		b.realize(trade.Sold, valueETH-costETH, valueUSD-costUSD)
+	}
+	if trade.BoughtAmount > 0 {
+		b.inventory(trade.Bought).acquire(trade.BoughtAmount, valueETH, valueUSD)
+	}
+	if trade.FeeAmount > 0 {
+		feeToken := NormalizeToken(trade.FeeToken)
+		feeETH, feeUSD := b.feeValue(trade, feeToken, ethUSD)
+		costETH, costUSD, uncovered := b.inventory(feeToken).dispose(trade.FeeAmount)
+		ratio := uncovered / trade.FeeAmount
+		costETH += feeETH * ratio
+		costUSD += feeUSD * ratio
+		// the fee is disposed without proceeds, so the realized PnL is net of fees
+		b.realize(feeToken, -costETH, -costUSD)
+		add(b.fees, feeToken, feeETH, feeUSD)
+	}
+}
+
+// transfer applies a transfer to the inventory of its token. Incoming transfers are acquired at market value,
+// outgoing transfers are disposed at cost so they realize no PnL.
+func (b *builder) transfer(transfer Transfer) {
+	token := NormalizeToken(transfer.Token)
+	if !transfer.Incoming {
+		b.inventory(token).dispose(transfer.Amount)
+		return
+	}
+	ethUSD, _ := b.prices.ETHUSD(transfer.Timestamp)
+	price, _ := b.prices.ETHPrice(token, transfer.Timestamp)
+	b.inventory(token).acquire(transfer.Amount, transfer.Amount*price, transfer.Amount*price*ethUSD)
+}
+
+// event is a trade or a transfer, events are processed in time order.
+type event struct {
+	timestamp time.Time
+	trade     *Trade
+	transfer  *Transfer
+}
+
+func (b *builder) processEvent(e event) {
+	if e.trade != nil {
+		b.process(*e.trade)
+		return
+	}
+	b.transfer(*e.transfer)
+}
+
+// statement returns the statement of token at the end of the day.
+func (b *builder) statement(token string, date time.Time) Statement {
+	var (
+		inv              = b.inventory(token)
+		quantity         = inv.quantity()
+		costETH, costUSD = inv.cost()
+		s                = Statement{
+			Date:     date,
+			Quantity: quantity,
+			CostETH:  costETH,
+			CostUSD:  costUSD,
+		}
+	)
+	if r, ok := b.realized[token]; ok {
+		s.RealizedETH = r[0]
+		s.RealizedUSD = r[1]
+	}
+	if f, ok := b.fees[token]; ok {
+		s.FeesETH = f[0]
+		s.FeesUSD = f[1]
+	}
+	if quantity == 0 {
+		return s
+	}
+	price, ok := b.prices.ETHPrice(token, date)
+	if !ok {
+		s.PriceMissing = true
+		return s
+	}
+	ethUSD, _ := b.prices.ETHUSD(date)
+	s.UnrealizedETH = quantity*price - costETH
+	s.UnrealizedUSD = quantity*price*ethUSD - costUSD
+	return s
+}
+
+// BuildReport builds daily PnL statements of days in from..to, from is truncated to the start of its day.
+// Trades and transfers before from build the opening inventory.
+func BuildReport(trades []Trade, transfers []Transfer, prices *Prices, method Method, from, to time.Time) Report {
+	var (
+		b = &builder{
+			method:      method,
+			prices:      prices,
+			inventories: make(map[string]*inventory),
+		}
+		report = Report{
+			Method: method,
+			Tokens: make(map[string][]Statement),
+		}
+		events = make([]event, 0, len(trades)+len(transfers))
+		i      int
+	)
+	from = from.UTC().Truncate(day)
+	for j := range trades {
+		events = append(events, event{timestamp: trades[j].Timestamp, trade: &trades[j]})
+	}
+	for j := range transfers {
+		events = append(events, event{timestamp: transfers[j].Timestamp, transfer: &transfers[j]})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].timestamp.Before(events[j].timestamp) })
+	b.realized = make(map[string]*[2]float64)
+	b.fees = make(map[string]*[2]float64)
+	for ; i < len(events) && events[i].timestamp.Before(from); i++ {
+		b.processEvent(events[i])
+	}
+
+	for date := from; date.Before(to); date = date.Add(day) {
+		end := date.Add(day)
+		b.realized = make(map[string]*[2]float64)
+		b.fees = make(map[string]*[2]float64)
+		for ; i < len(events) && events[i].timestamp.Before(end); i++ {
+			b.processEvent(events[i])
+		}
+
+		overall := Statement{Date: date}
+		for token := range b.inventories {
+			s := b.statement(token, date)
+			if s.Quantity == 0 && s.RealizedETH == 0 && s.RealizedUSD == 0 && s.FeesETH == 0 {
+				continue
+			}
+			report.Tokens[token] = append(report.Tokens[token], s)
+			overall.CostETH += s.CostETH
+			overall.CostUSD += s.CostUSD
+			overall.RealizedETH += s.RealizedETH
+			overall.RealizedUSD += s.RealizedUSD
+			overall.UnrealizedETH += s.UnrealizedETH
+			overall.UnrealizedUSD += s.UnrealizedUSD
+			overall.FeesETH += s.FeesETH
+			overall.FeesUSD += s.FeesUSD
+			overall.PriceMissing = overall.PriceMissing || s.PriceMissing
+		}
+		report.Overall = append(report.Overall, overall)
+	}
+	return report
+}
@@ -0,0 +1,154 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildReport(t *testing.T) {
+	const delta = 0.000001
+	var (
+		day0   = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		day1   = day0.Add(day)
+		prices = NewPrices(
+			map[string]map[time.Time]float64{
+				"KNC": {day0: 0.001, day1: 0.002},
+			},
+			map[time.Time]float64{day0: 2000},
+		)
+		trades = []Trade{
+			// trades are not sorted on purpose
+			{Timestamp: day1.Add(time.Hour), Source: "huobi", Bought: "ETH", BoughtAmount: 2.5, Sold: "KNC", SoldAmount: 1000},
+			{Timestamp: day0.Add(time.Hour), Source: "binance", Bought: "KNC", BoughtAmount: 1000, Sold: "ETH", SoldAmount: 1, FeeToken: "KNC", FeeAmount: 1},
+			{Timestamp: day0.Add(2 * time.Hour), Source: "0x", Bought: "KNC", BoughtAmount: 1000, Sold: "WETH", SoldAmount: 2},
+		}
+	)
+
+	report := BuildReport(trades, nil, prices, MethodFIFO, day0, day1.Add(day))
+	assert.Equal(t, MethodFIFO, report.Method)
+	// ETH inventory is empty at day 0 and it realizes no PnL
+	require.Len(t, report.Tokens["ETH"], 1)
+	require.Len(t, report.Tokens["KNC"], 2)
+	require.Len(t, report.Overall, 2)
+
+	knc := report.Tokens["KNC"][0]
+	assert.Equal(t, day0, knc.Date)
+	assert.InDelta(t, 1999, knc.Quantity, delta)
+	assert.InDelta(t, 2.999, knc.CostETH, delta)
+	// fee is an expense
+	assert.InDelta(t, -0.001, knc.RealizedETH, delta)
+	assert.InDelta(t, -2, knc.RealizedUSD, delta)
+	assert.InDelta(t, 0.001, knc.FeesETH, delta)
+	assert.InDelta(t, 2, knc.FeesUSD, delta)
+	assert.InDelta(t, 0.001, report.Overall[0].FeesETH, delta)
+	assert.InDelta(t, 1.999-2.999, knc.UnrealizedETH, delta)
+
+	knc = report.Tokens["KNC"][1]
+	assert.Equal(t, day1, knc.Date)
+	assert.InDelta(t, 999, knc.Quantity, delta)
+	// the first lot of 999 KNC and 1 KNC of the second lot are sold
+	assert.InDelta(t, 2.5-0.999-0.002, knc.RealizedETH, delta)
+	assert.InDelta(t, (2.5-0.999-0.002)*2000, knc.RealizedUSD, delta)
+	assert.InDelta(t, 0, knc.UnrealizedETH, delta)
+
+	eth := report.Tokens["ETH"][0]
+	assert.Equal(t, day1, eth.Date)
+	assert.InDelta(t, 2.5, eth.Quantity, delta)
+	assert.InDelta(t, 5000, eth.CostUSD, delta)
+	assert.InDelta(t, 0, eth.UnrealizedUSD, delta)
+
+	assert.InDelta(t, knc.RealizedETH, report.Overall[1].RealizedETH, delta)
+	assert.InDelta(t, 0.999*2+2.5, report.Overall[1].CostETH, delta)
+
+	// trades before from are the opening inventory
+	report = BuildReport(trades, nil, prices, MethodAverage, day1, day1.Add(day))
+	require.Len(t, report.Tokens["KNC"], 1)
+	knc = report.Tokens["KNC"][0]
+	assert.InDelta(t, 2.5-2.999*1000/1999, knc.RealizedETH, delta)
+	assert.InDelta(t, 2.999*999/1999, knc.CostETH, delta)
+	require.Len(t, report.Overall, 1)
+}
+
+func TestBuildReportPriceMissing(t *testing.T) {
+	var (
+		day0   = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		prices = NewPrices(nil, map[time.Time]float64{day0: 2000})
+		trades = []Trade{
+			{Timestamp: day0.Add(time.Hour), Bought: "XYZ", BoughtAmount: 10, Sold: "USDT", SoldAmount: 20},
+		}
+	)
+	report := BuildReport(trades, nil, prices, MethodFIFO, day0, day0.Add(day))
+	require.Len(t, report.Tokens["XYZ"], 1)
+	xyz := report.Tokens["XYZ"][0]
+	// USDT is priced at 1 USD
+	assert.InDelta(t, 0.01, xyz.CostETH, 0.000001)
+	assert.InDelta(t, 20, xyz.CostUSD, 0.000001)
+	assert.True(t, xyz.PriceMissing)
+	assert.True(t, report.Overall[0].PriceMissing)
+}
+
+func TestBuildReportNormalizedFee(t *testing.T) {
+	const delta = 0.000001
+	var (
+		day0   = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		prices = NewPrices(
+			map[string]map[time.Time]float64{"BNB": {day0: 0.1}},
+			map[time.Time]float64{day0: 2000},
+		)
+		trades = []Trade{
+			{
+				Timestamp: day0.Add(time.Hour), Bought: "KNC", BoughtAmount: 1000, Sold: "ETH", SoldAmount: 1,
+				FeeToken: "BNB", FeeAmount: 0.01, FeeETH: 0.0013, FeeUSD: 2.5,
+			},
+		}
+	)
+	report := BuildReport(trades, nil, prices, MethodFIFO, day0, day0.Add(day))
+	require.Len(t, report.Tokens["BNB"], 1)
+	bnb := report.Tokens["BNB"][0]
+	// the normalized value is preferred to the daily price
+	assert.InDelta(t, 0.0013, bnb.FeesETH, delta)
+	assert.InDelta(t, 2.5, bnb.FeesUSD, delta)
+	assert.InDelta(t, -0.0013, bnb.RealizedETH, delta)
+	assert.InDelta(t, -2.5, report.Overall[0].RealizedUSD, delta)
+}
+
+func TestBuildReportTransfers(t *testing.T) {
+	const delta = 0.000001
+	var (
+		day0   = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		prices = NewPrices(
+			map[string]map[time.Time]float64{"KNC": {day0: 0.002}},
+			map[time.Time]float64{day0: 2000},
+		)
+		trades = []Trade{
+			{Timestamp: day0.Add(2 * time.Hour), Bought: "ETH", BoughtAmount: 1.5, Sold: "KNC", SoldAmount: 500},
+		}
+		transfers = []Transfer{
+			{Timestamp: day0.Add(time.Hour), Token: "KNC", Amount: 1000, Incoming: true},
+			{Timestamp: day0.Add(3 * time.Hour), Token: "KNC", Amount: 100},
+		}
+	)
+	report := BuildReport(trades, transfers, prices, MethodFIFO, day0, day0.Add(day))
+	require.Len(t, report.Tokens["KNC"], 1)
+	knc := report.Tokens["KNC"][0]
+	// incoming transfer is acquired at market value
+	assert.InDelta(t, 1.5-1, knc.RealizedETH, delta)
+	// outgoing transfer is disposed at cost without PnL
+	assert.InDelta(t, 400, knc.Quantity, delta)
+	assert.InDelta(t, 0.8, knc.CostETH, delta)
+	assert.InDelta(t, 1600, knc.CostUSD, delta)
+}
+
+func TestParseMethod(t *testing.T) {
+	method, err := ParseMethod("")
+	require.NoError(t, err)
+	assert.Equal(t, MethodFIFO, method)
+	method, err = ParseMethod("average")
+	require.NoError(t, err)
+	assert.Equal(t, MethodAverage, method)
+	_, err = ParseMethod("lifo")
+	assert.Error(t, err)
+}
@@ -0,0 +1,106 @@
+// Package common contains the inventory and PnL engine of reserve accounting. Trades of all reserve addresses
+// and CEX accounts build a single inventory per token, transfers between them do not change the inventory.
+// Transfers from external addresses acquire at market value, transfers to external addresses dispose at cost.
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// Method is the cost basis method of inventory.
+type Method string
+
+const (
+	// MethodFIFO disposes the earliest acquired lots first.
+	MethodFIFO Method = "fifo"
+	// MethodAverage disposes at the average cost of the inventory.
+	MethodAverage Method = "average"
+)
+
+// ParseMethod returns the Method of given name, FIFO if the name is empty.
+func ParseMethod(name string) (Method, error) {
+	switch Method(name) {
+	case "", MethodFIFO:
+		return MethodFIFO, nil
+	case MethodAverage:
+		return MethodAverage, nil
+	}
+	return "", fmt.Errorf("invalid cost basis method %q", name)
+}
+
+// ReserveSource is the source of on-chain trades of reserves, the account of a trade is the reserve address.
+const ReserveSource = "reserve"
+
+// Trade is an exchange of tokens by a reserve address or a CEX account. All amounts are positive.
+type Trade struct {
+	// ID is the id of the trade, unique per source and account.
+	ID        string
+	Timestamp time.Time
+	// Source is where the trade happened, e.g. binance, huobi, 0x.
+	Source       string
+	Account      string
+	Bought       string
+	BoughtAmount float64
+	Sold         string
+	SoldAmount   float64
+	// Fee is paid in FeeToken, it is an expense without proceeds.
+	FeeToken  string
+	FeeAmount float64
+	// FeeETH and FeeUSD are the normalized value of the fee at the time of the trade. If they are zero,
+	// the fee is valued at the daily price of FeeToken.
+	FeeETH float64
+	FeeUSD float64
+}
+
+// Transfer is a transfer of a token between a reserve address and an external address. Amount is positive.
+type Transfer struct {
+	// ID is the id of the transfer, unique per account.
+	ID        string
+	Timestamp time.Time
+	Account   string
+	Token     string
+	Amount    float64
+	// Incoming is true if the transfer is from an external address to the reserve address.
+	Incoming bool
+}
+
+// Statement is the daily PnL statement of a token or all tokens. Realized PnL and fees are of the day,
+// quantity, cost and unrealized PnL are at the end of the day. Realized PnL is net of fees, fees are the
+// value at the time of trades of the token paid as commission.
+type Statement struct {
+	Date          time.Time `json:"date"`
+	Quantity      float64   `json:"quantity,omitempty"`
+	CostETH       float64   `json:"cost_eth"`
+	CostUSD       float64   `json:"cost_usd"`
+	RealizedETH   float64   `json:"realized_eth"`
+	RealizedUSD   float64   `json:"realized_usd"`
+	UnrealizedETH float64   `json:"unrealized_eth"`
+	UnrealizedUSD float64   `json:"unrealized_usd"`
+	FeesETH       float64   `json:"fees_eth"`
+	FeesUSD       float64   `json:"fees_usd"`
+	// PriceMissing is true if the token has no price at the end of the day, the unrealized PnL is zero.
+	PriceMissing bool `json:"price_missing,omitempty"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Statement to format date in unix millis instead of RFC3339.
+func (s Statement) MarshalJSON() ([]byte, error) {
+	type AliasStatement Statement
+	return json.Marshal(struct {
+		Date uint64 `json:"date"`
+		AliasStatement
+	}{
+		AliasStatement: (AliasStatement)(s),
+		Date:           timeutil.TimeToTimestampMs(s.Date),
+	})
+}
+
+// Report is the daily PnL statements per token and overall, the overall statements have no quantity.
+type Report struct {
+	Method  Method                 `json:"method"`
+	Tokens  map[string][]Statement `json:"tokens"`
+	Overall []Statement            `json:"overall"`
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+const (
+	maxTimeFrame     = time.Hour * 24 * 90 // 90 days
+	defaultTimeFrame = time.Hour * 24 * 7  // 7 days
+)
+
+// Server is the HTTP server of reserve inventory and PnL statements.
+type Server struct {
+	sugar     *zap.SugaredLogger
+	r         *gin.Engine
+	host      string
+	trades    storage.TradesInterface
+	transfers storage.TransfersInterface
+	prices    storage.PricesInterface
+	// inventoryFrom is the time inventory is built from, trades before it are ignored.
+	// If it is zero, the inventory is built from the start of each query.
+	inventoryFrom time.Time
+
+	openAPIOptions []openapi.Option
+}
+
+// NewServer creates a new instance of Server.
+func NewServer(sugar *zap.SugaredLogger, host string, trades storage.TradesInterface, transfers storage.TransfersInterface,
+	prices storage.PricesInterface, inventoryFrom time.Time, openAPIOptions ...openapi.Option) *Server {
+	r := gin.Default()
+	return &Server{
+		sugar:         sugar,
+		r:             r,
+		host:          host,
+		trades:        trades,
+		transfers:     transfers,
+		prices:        prices,
+		inventoryFrom: inventoryFrom,
+
+		openAPIOptions: openAPIOptions,
+	}
+}
+
+type pnlQuery struct {
+	httputil.TimeRangeQuery
+	Method string   `form:"method"`
+	Tokens []string `form:"token"`
+}
+
+func (s *Server) getPnL(c *gin.Context) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+		query  pnlQuery
+	)
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	from, to, err := query.Validate(
+		httputil.TimeRangeQueryWithMaxTimeFrame(maxTimeFrame),
+		httputil.TimeRangeQueryWithDefaultTimeFrame(defaultTimeFrame),
+	)
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	method, err := common.ParseMethod(query.Method)
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	inventoryFrom := from
+	if !s.inventoryFrom.IsZero() && s.inventoryFrom.Before(from) {
+		inventoryFrom = s.inventoryFrom
+	}
+
+	logger = logger.With("from", from, "to", to, "inventory_from", inventoryFrom, "method", method)
+	logger.Debug("building PnL report")
+
+	trades, err := s.trades.GetTrades(inventoryFrom, to)
+	if err != nil {
+		logger.Errorw("failed to get trades", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	transfers, err := s.transfers.GetTransfers(inventoryFrom, to)
+	if err != nil {
+		logger.Errorw("failed to get transfers", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	prices, err := s.prices.GetPrices(inventoryFrom, to)
+	if err != nil {
+		logger.Errorw("failed to get prices", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	report := common.BuildReport(trades, transfers, prices, method, from, to)
+	if len(query.Tokens) != 0 {
+		filtered := make(map[string][]common.Statement)
+		for _, token := range query.Tokens {
+			token = common.NormalizeToken(token)
+			if statements, ok := report.Tokens[token]; ok {
+				filtered[token] = statements
+			}
+		}
+		report.Tokens = filtered
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func (s *Server) register() {
+	api := openapi.NewRouter(s.r, openapi.NewSpec("reserve pnl"), s.openAPIOptions...)
+	api.GET("/pnl", openapi.Endpoint{
+		Summary:  "daily inventory and PnL statements in ETH and USD per token and overall",
+		Query:    pnlQuery{},
+		Response: common.Report{},
+	}, s.getPnL)
+}
+
+// Run starts the HTTP server and runs in foreground until terminate by user.
+func (s *Server) Run() error {
+	s.register()
+	return s.r.Run(s.host)
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	rrstorage "github.com/KyberNetwork/reserve-stats/accounting/reserve-rate/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+const (
+	ethSymbol = "ETH"
+	usdSymbol = "USD"
+)
+
+// PricesInterface returns the daily prices of tokens in ETH and ETH in USD.
+type PricesInterface interface {
+	GetPrices(from, to time.Time) (*common.Prices, error)
+}
+
+// ReserveRates reads daily prices from rates stored by accounting-reserve-rate-fetcher.
+type ReserveRates struct {
+	sugar *zap.SugaredLogger
+	rs    rrstorage.Interface
+}
+
+// NewReserveRates creates a new ReserveRates instance.
+func NewReserveRates(sugar *zap.SugaredLogger, rs rrstorage.Interface) *ReserveRates {
+	return &ReserveRates{sugar: sugar, rs: rs}
+}
+
+// GetPrices returns the daily prices in given time range. Reserve rates are the amount of token for 1 ETH,
+// the price of a token is the inverse of its average rate of all reserves.
+func (r *ReserveRates) GetPrices(from, to time.Time) (*common.Prices, error) {
+	var (
+		logger = r.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		sums        = make(map[string]map[time.Time][2]float64)
+		tokenPrices = make(map[string]map[time.Time]float64)
+		ethUSD      = make(map[time.Time]float64)
+	)
+	reserveRates, err := r.rs.GetRates(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, rates := range reserveRates {
+		for date, quotes := range rates {
+			for token, rate := range quotes[ethSymbol] {
+				if rate <= 0 {
+					continue
+				}
+				if _, ok := sums[token]; !ok {
+					sums[token] = make(map[time.Time][2]float64)
+				}
+				sum := sums[token][date]
+				sums[token][date] = [2]float64{sum[0] + rate, sum[1] + 1}
+			}
+		}
+	}
+	for token, dates := range sums {
+		tokenPrices[token] = make(map[time.Time]float64)
+		for date, sum := range dates {
+			tokenPrices[token][date] = sum[1] / sum[0]
+		}
+	}
+
+	usdRates, err := r.rs.GetETHUSDRates(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for date, quotes := range usdRates {
+		ethUSD[date] = quotes[usdSymbol][ethSymbol]
+	}
+	logger.Debugw("prices loaded", "tokens", len(tokenPrices), "days", len(ethUSD))
+	return common.NewPrices(tokenPrices, ethUSD), nil
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/client"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+	tradelogcommon "github.com/KyberNetwork/reserve-stats/tradelogs/common"
+)
+
+// tradeLogsTimeFrame is the maximum time frame of a trade logs query.
+const tradeLogsTimeFrame = 24 * time.Hour
+
+// TradeLogsInterface returns the trade logs routed through reserves.
+type TradeLogsInterface interface {
+	GetReserveTradeLogs(fromTime, toTime uint64, reserves []ethereum.Address) ([]tradelogcommon.TradelogV4, error)
+}
+
+// ReserveTradesInterface returns the on-chain trades of reserves.
+type ReserveTradesInterface interface {
+	GetReserveTrades(from, to time.Time) ([]common.Trade, error)
+}
+
+// ReserveTrades reads the on-chain trades of reserves from trade logs API.
+type ReserveTrades struct {
+	sugar     *zap.SugaredLogger
+	tradeLogs TradeLogsInterface
+	addresses client.Interface
+	symbols   blockchain.TokenSymbolResolver
+	formatter blockchain.TokenAmountFormatterInterface
+}
+
+// NewReserveTrades creates a new ReserveTrades instance.
+func NewReserveTrades(sugar *zap.SugaredLogger, tradeLogs TradeLogsInterface, addresses client.Interface,
+	symbols blockchain.TokenSymbolResolver, formatter blockchain.TokenAmountFormatterInterface) *ReserveTrades {
+	return &ReserveTrades{
+		sugar:     sugar,
+		tradeLogs: tradeLogs,
+		addresses: addresses,
+		symbols:   symbols,
+		formatter: formatter,
+	}
+}
+
+// splitTrade returns the trade of a reserve split, the reserve receives the source token of the split.
+func (rt *ReserveTrades) splitTrade(log tradelogcommon.TradelogV4, split tradelogcommon.TradeSplit) (common.Trade, error) {
+	bought, err := rt.symbols.Symbol(split.SrcToken)
+	if err != nil {
+		return common.Trade{}, err
+	}
+	sold, err := rt.symbols.Symbol(split.DstToken)
+	if err != nil {
+		return common.Trade{}, err
+	}
+	boughtAmount, err := rt.formatter.FromWei(split.SrcToken, split.SrcAmount)
+	if err != nil {
+		return common.Trade{}, err
+	}
+	soldAmount, err := rt.formatter.FromWei(split.DstToken, split.DstAmount)
+	if err != nil {
+		return common.Trade{}, err
+	}
+	return common.Trade{
+		ID:           fmt.Sprintf("%s:%d:%d", log.TransactionHash.Hex(), log.Index, split.Index),
+		Timestamp:    log.Timestamp,
+		Source:       common.ReserveSource,
+		Account:      split.ReserveAddress.Hex(),
+		Bought:       bought,
+		BoughtAmount: boughtAmount,
+		Sold:         sold,
+		SoldAmount:   soldAmount,
+	}, nil
+}
+
+// GetReserveTrades returns the splits of trade logs routed through reserve addresses in given time range,
+// each split is a trade of its reserve.
+func (rt *ReserveTrades) GetReserveTrades(from, to time.Time) ([]common.Trade, error) {
+	var (
+		logger = rt.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		result   []common.Trade
+		reserves []ethereum.Address
+		own      = make(map[ethereum.Address]struct{})
+		// seen is the ids of loaded trades, trade logs at the boundary of batches are returned twice
+		seen = make(map[string]struct{})
+	)
+	addresses, err := rt.addresses.ReserveAddresses(cexcommon.Reserve)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addresses {
+		reserves = append(reserves, addr.Address)
+		own[addr.Address] = struct{}{}
+	}
+	if len(reserves) == 0 {
+		logger.Debug("no reserve address")
+		return nil, nil
+	}
+
+	for start := from; start.Before(to); start = start.Add(tradeLogsTimeFrame) {
+		end := start.Add(tradeLogsTimeFrame)
+		if end.After(to) {
+			end = to
+		}
+		logs, err := rt.tradeLogs.GetReserveTradeLogs(timeutil.TimeToTimestampMs(start),
+			timeutil.TimeToTimestampMs(end), reserves)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			for _, split := range log.Split {
+				if _, ok := own[split.ReserveAddress]; !ok {
+					continue
+				}
+				trade, err := rt.splitTrade(log, split)
+				if err != nil {
+					return nil, err
+				}
+				if _, ok := seen[trade.ID]; ok {
+					continue
+				}
+				seen[trade.ID] = struct{}{}
+				result = append(result, trade)
+			}
+		}
+	}
+	logger.Debugw("reserve trades loaded", "trades", len(result))
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+	tradelogcommon "github.com/KyberNetwork/reserve-stats/tradelogs/common"
+)
+
+type mockTradeLogs struct {
+	logs    []tradelogcommon.TradelogV4
+	queries [][2]uint64
+}
+
+func (m *mockTradeLogs) GetReserveTradeLogs(fromTime, toTime uint64, _ []ethereum.Address) ([]tradelogcommon.TradelogV4, error) {
+	m.queries = append(m.queries, [2]uint64{fromTime, toTime})
+	var result []tradelogcommon.TradelogV4
+	for _, log := range m.logs {
+		ts := timeutil.TimeToTimestampMs(log.Timestamp)
+		if ts >= fromTime && ts <= toTime {
+			result = append(result, log)
+		}
+	}
+	return result, nil
+}
+
+func TestReserveTrades(t *testing.T) {
+	var (
+		reserve   = ethereum.HexToAddress("0x1")
+		other     = ethereum.HexToAddress("0x2")
+		token     = ethereum.HexToAddress("0x3")
+		oneToken  = big.NewInt(1000000000000000000)
+		from      = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		addresses = &mockAddresses{addresses: []cexcommon.ReserveAddress{
+			{Address: reserve, Type: cexcommon.Reserve},
+		}}
+		symbols   = mockSymbols{token: "KNC", blockchain.ETHAddr: "ETH"}
+		tradeLogs = &mockTradeLogs{logs: []tradelogcommon.TradelogV4{
+			{
+				// at the boundary of two batches
+				Timestamp:       from.Add(24 * time.Hour),
+				TransactionHash: ethereum.HexToHash("0xa"),
+				Index:           2,
+				Split: []tradelogcommon.TradeSplit{
+					{ReserveAddress: reserve, SrcToken: token, DstToken: blockchain.ETHAddr,
+						SrcAmount: big.NewInt(0).Mul(oneToken, big.NewInt(1000)), DstAmount: oneToken, Index: 0},
+					{ReserveAddress: other, SrcToken: token, DstToken: blockchain.ETHAddr,
+						SrcAmount: oneToken, DstAmount: oneToken, Index: 1},
+				},
+			},
+		}}
+	)
+	rt := NewReserveTrades(zap.NewNop().Sugar(), tradeLogs, addresses, symbols, blockchain.NewMockTokenAmountFormatter())
+	trades, err := rt.GetReserveTrades(from, from.Add(36*time.Hour))
+	require.NoError(t, err)
+	// time range is queried in batches of a day
+	assert.Len(t, tradeLogs.queries, 2)
+	require.Len(t, trades, 1)
+	assert.Equal(t, common.Trade{
+		ID:           ethereum.HexToHash("0xa").Hex() + ":2:0",
+		Timestamp:    from.Add(24 * time.Hour),
+		Source:       "reserve",
+		Account:      reserve.Hex(),
+		Bought:       "KNC",
+		BoughtAmount: 1000,
+		Sold:         "ETH",
+		SoldAmount:   1,
+	}, trades[0])
+}
@@ -0,0 +1,310 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/tradestorage"
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	cexstorage "github.com/KyberNetwork/reserve-stats/accounting/cex/storage"
+	commissionstorage "github.com/KyberNetwork/reserve-stats/accounting/commission/storage"
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	huobistorage "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage"
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/zerox"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	zeroxSource  = "0x"
+	zeroxAccount = "0xRFQ"
+)
+
+// quoteAssets are the quote assets of CEX market symbols, a symbol is split by the first matching suffix.
+var quoteAssets = []string{"USDT", "BUSD", "USDC", "TUSD", "DAI", "BTC", "ETH", "BNB"}
+
+// TradesInterface returns the trades of all reserve addresses and CEX accounts.
+type TradesInterface interface {
+	GetTrades(from, to time.Time) ([]common.Trade, error)
+}
+
+// ZeroxInterface returns the 0x fills of reserve, the maker receives the input token of the fill.
+type ZeroxInterface interface {
+	Get0xTrades(fromTime, toTime int64) ([]zerox.SimpleTradelog, error)
+}
+
+// Trades reads trades stored by accounting fetchers.
+type Trades struct {
+	sugar *zap.SugaredLogger
+	bs    tradestorage.Interface
+	hs    huobistorage.Interface
+	cs    cexstorage.Interface
+	zs    ZeroxInterface
+	cms   commissionstorage.Interface
+	rt    ReserveTradesInterface
+}
+
+// TradesOption is the option of Trades constructor.
+type TradesOption func(*Trades)
+
+// WithCommissions sets the storage of normalized commissions, the fee of a trade is valued at its stored
+// commission instead of the daily price of the fee token.
+func WithCommissions(cms commissionstorage.Interface) TradesOption {
+	return func(t *Trades) {
+		t.cms = cms
+	}
+}
+
+// WithReserveTrades sets the source of on-chain trades of reserves.
+func WithReserveTrades(rt ReserveTradesInterface) TradesOption {
+	return func(t *Trades) {
+		t.rt = rt
+	}
+}
+
+// NewTrades creates a new Trades instance.
+func NewTrades(sugar *zap.SugaredLogger, bs tradestorage.Interface, hs huobistorage.Interface, cs cexstorage.Interface, zs ZeroxInterface,
+	options ...TradesOption) *Trades {
+	t := &Trades{sugar: sugar, bs: bs, hs: hs, cs: cs, zs: zs}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// BinanceTradeID returns the id of a binance trade, binance trade ids are unique per symbol.
+func BinanceTradeID(symbol string, id uint64) string {
+	return fmt.Sprintf("%s:%d", symbol, id)
+}
+
+// splitSymbol returns the base and quote of a market symbol, for example: KNCETH --> KNC, ETH.
+func splitSymbol(symbol string) (string, string, bool) {
+	symbol = strings.ToUpper(symbol)
+	for _, quote := range quoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote), quote, true
+		}
+	}
+	return "", "", false
+}
+
+func parseAmounts(amounts ...string) ([]float64, bool) {
+	var result []float64
+	for _, amount := range amounts {
+		if amount == "" {
+			result = append(result, 0)
+			continue
+		}
+		value, err := strconv.ParseFloat(amount, 64)
+		if err != nil {
+			return nil, false
+		}
+		result = append(result, value)
+	}
+	return result, true
+}
+
+// newTrade returns the trade of buying or selling base for quote.
+func newTrade(timestamp time.Time, source, account, base, quote string, isBuy bool, baseAmount, quoteAmount float64) common.Trade {
+	trade := common.Trade{
+		Timestamp:    timestamp,
+		Source:       source,
+		Account:      account,
+		Bought:       base,
+		BoughtAmount: baseAmount,
+		Sold:         quote,
+		SoldAmount:   quoteAmount,
+	}
+	if !isBuy {
+		trade.Bought, trade.Sold = quote, base
+		trade.BoughtAmount, trade.SoldAmount = quoteAmount, baseAmount
+	}
+	return trade
+}
+
+func binanceTrade(account string, trade binance.TradeHistory) (common.Trade, bool) {
+	base, quote, ok := splitSymbol(trade.Symbol)
+	if !ok {
+		return common.Trade{}, false
+	}
+	amounts, ok := parseAmounts(trade.Price, trade.Quantity, trade.QuoteQuantity, trade.Commission)
+	if !ok {
+		return common.Trade{}, false
+	}
+	price, quantity, quoteQuantity, commission := amounts[0], amounts[1], amounts[2], amounts[3]
+	if quoteQuantity == 0 {
+		quoteQuantity = price * quantity
+	}
+	result := newTrade(timeutil.TimestampMsToTime(trade.Time), cexcommon.Binance.String(), account,
+		base, quote, trade.IsBuyer, quantity, quoteQuantity)
+	result.ID = BinanceTradeID(trade.Symbol, trade.ID)
+	result.FeeToken = trade.CommissionAsset
+	result.FeeAmount = commission
+	return result, true
+}
+
+func huobiTrade(account string, trade huobi.TradeHistory) (common.Trade, bool) {
+	base, quote, ok := splitSymbol(trade.Symbol)
+	if !ok {
+		return common.Trade{}, false
+	}
+	amounts, ok := parseAmounts(trade.FieldAmount, trade.FieldCashAmount, trade.FieldFees)
+	if !ok || amounts[0] == 0 {
+		return common.Trade{}, false
+	}
+	isBuy := strings.HasPrefix(trade.Type, string(cex.Buy))
+	result := newTrade(timeutil.TimestampMsToTime(trade.FinishedAt), cexcommon.Huobi.String(), account,
+		base, quote, isBuy, amounts[0], amounts[1])
+	result.ID = strconv.FormatInt(trade.ID, 10)
+	// huobi charges fee in the received asset
+	result.FeeToken = result.Bought
+	result.FeeAmount = amounts[2]
+	return result, true
+}
+
+func cexTrade(exchange, account string, trade cex.Trade) (common.Trade, bool) {
+	amounts, ok := parseAmounts(trade.Price, trade.Quantity, trade.Fee)
+	if !ok {
+		return common.Trade{}, false
+	}
+	price, quantity, fee := amounts[0], amounts[1], amounts[2]
+	result := newTrade(trade.Timestamp, exchange, account,
+		trade.BaseAsset, trade.QuoteAsset, trade.Side == cex.Buy, quantity, price*quantity)
+	result.ID = trade.ID
+	result.FeeToken = trade.FeeAsset
+	result.FeeAmount = fee
+	return result, true
+}
+
+func zeroxTrade(trade zerox.SimpleTradelog) common.Trade {
+	return common.Trade{
+		ID:           fmt.Sprintf("%s:%s:%s", trade.Tx, trade.InputToken, trade.OutputToken),
+		Timestamp:    time.Unix(trade.Timestamp, 0),
+		Source:       zeroxSource,
+		Account:      zeroxAccount,
+		Bought:       trade.InputToken,
+		BoughtAmount: trade.InputAmount,
+		Sold:         trade.OutputToken,
+		SoldAmount:   trade.OutputAmount,
+	}
+}
+
+// GetTrades returns Binance spot and margin, Huobi, registered exchanges, 0x and reserve trades in given time
+// range.
+// Trades of unknown symbols are skipped.
+func (t *Trades) GetTrades(from, to time.Time) ([]common.Trade, error) {
+	var (
+		logger = t.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		result  []common.Trade
+		skipped int
+	)
+
+	binanceTrades, err := t.bs.GetTradeHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	binanceMarginTrades, err := t.bs.GetMarginTradeHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, accountTrades := range []map[string][]binance.TradeHistory{binanceTrades, binanceMarginTrades} {
+		for account, trades := range accountTrades {
+			for _, trade := range trades {
+				if tr, ok := binanceTrade(account, trade); ok {
+					result = append(result, tr)
+				} else {
+					skipped++
+				}
+			}
+		}
+	}
+
+	huobiTrades, err := t.hs.GetTradeHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for account, trades := range huobiTrades {
+		for _, trade := range trades {
+			if tr, ok := huobiTrade(account, trade); ok {
+				result = append(result, tr)
+			} else {
+				skipped++
+			}
+		}
+	}
+
+	for _, exchange := range cex.Names() {
+		exchangeTrades, err := t.cs.GetTrades(exchange, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for account, trades := range exchangeTrades {
+			for _, trade := range trades {
+				if tr, ok := cexTrade(exchange, account, trade); ok {
+					result = append(result, tr)
+				} else {
+					skipped++
+				}
+			}
+		}
+	}
+
+	zeroxTrades, err := t.zs.Get0xTrades(int64(timeutil.TimeToTimestampMs(from)), int64(timeutil.TimeToTimestampMs(to)))
+	if err != nil {
+		return nil, err
+	}
+	for _, trade := range zeroxTrades {
+		result = append(result, zeroxTrade(trade))
+	}
+
+	if t.rt != nil {
+		reserveTrades, err := t.rt.GetReserveTrades(from, to)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, reserveTrades...)
+	}
+	if err := t.setCommissions(from, to, result); err != nil {
+		return nil, err
+	}
+	logger.Debugw("trades loaded", "trades", len(result), "skipped", skipped)
+	return result, nil
+}
+
+type tradeKey struct {
+	source, account, id string
+}
+
+// setCommissions sets the fee value of trades which have a stored commission with price.
+func (t *Trades) setCommissions(from, to time.Time, trades []common.Trade) error {
+	if t.cms == nil {
+		return nil
+	}
+	commissions, err := t.cms.GetCommissions(from, to)
+	if err != nil {
+		return err
+	}
+	values := make(map[tradeKey][2]float64)
+	for _, c := range commissions {
+		if !c.PriceMissing {
+			values[tradeKey{source: c.Source, account: c.Account, id: c.TradeID}] = [2]float64{c.ETH, c.USD}
+		}
+	}
+	for i := range trades {
+		if v, ok := values[tradeKey{source: trades[i].Source, account: trades[i].Account, id: trades[i].ID}]; ok {
+			trades[i].FeeETH, trades[i].FeeUSD = v[0], v[1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/commission"
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func TestSplitSymbol(t *testing.T) {
+	base, quote, ok := splitSymbol("kncusdt")
+	require.True(t, ok)
+	assert.Equal(t, "KNC", base)
+	assert.Equal(t, "USDT", quote)
+
+	base, quote, ok = splitSymbol("ETHBTC")
+	require.True(t, ok)
+	assert.Equal(t, "ETH", base)
+	assert.Equal(t, "BTC", quote)
+
+	_, _, ok = splitSymbol("ETH")
+	assert.False(t, ok)
+}
+
+func TestCEXTrades(t *testing.T) {
+	const timestamp = 1614556800000
+	trade, ok := binanceTrade("binance_1", binance.TradeHistory{
+		Symbol:          "KNCETH",
+		ID:              12,
+		Price:           "0.001",
+		Quantity:        "1000",
+		Commission:      "0.01",
+		CommissionAsset: "BNB",
+		Time:            timestamp,
+		IsBuyer:         false,
+	})
+	require.True(t, ok)
+	assert.Equal(t, common.Trade{
+		ID:           "KNCETH:12",
+		Timestamp:    timeutil.TimestampMsToTime(timestamp),
+		Source:       "binance",
+		Account:      "binance_1",
+		Bought:       "ETH",
+		BoughtAmount: 1,
+		Sold:         "KNC",
+		SoldAmount:   1000,
+		FeeToken:     "BNB",
+		FeeAmount:    0.01,
+	}, trade)
+
+	trade, ok = huobiTrade("huobi_1", huobi.TradeHistory{
+		ID:              34,
+		Symbol:          "kncbtc",
+		Type:            "buy-limit",
+		FieldAmount:     "100",
+		FieldCashAmount: "0.003",
+		FieldFees:       "0.2",
+		FinishedAt:      timestamp,
+	})
+	require.True(t, ok)
+	assert.Equal(t, common.Trade{
+		ID:           "34",
+		Timestamp:    timeutil.TimestampMsToTime(timestamp),
+		Source:       "huobi",
+		Account:      "huobi_1",
+		Bought:       "KNC",
+		BoughtAmount: 100,
+		Sold:         "BTC",
+		SoldAmount:   0.003,
+		FeeToken:     "KNC",
+		FeeAmount:    0.2,
+	}, trade)
+
+	_, ok = huobiTrade("huobi_1", huobi.TradeHistory{Symbol: "kncbtc", Type: "buy-limit", FieldAmount: "0"})
+	assert.False(t, ok)
+}
+
+type stubCommissions []commission.Commission
+
+func (s stubCommissions) UpdateCommissions([]commission.Commission) error { return nil }
+
+func (s stubCommissions) GetCommissions(time.Time, time.Time) ([]commission.Commission, error) {
+	return s, nil
+}
+
+func (s stubCommissions) GetLastTimestamp() (time.Time, error) { return time.Time{}, nil }
+
+func TestSetCommissions(t *testing.T) {
+	trades := []common.Trade{
+		{ID: "KNCETH:12", Source: "binance", Account: "binance_1", FeeToken: "BNB", FeeAmount: 0.01},
+		{ID: "34", Source: "huobi", Account: "huobi_1", FeeToken: "XYZ", FeeAmount: 1},
+		{ID: "KNCETH:12", Source: "binance", Account: "binance_2", FeeToken: "BNB", FeeAmount: 0.01},
+	}
+	tr := NewTrades(zap.NewNop().Sugar(), nil, nil, nil, nil, WithCommissions(stubCommissions{
+		{Source: "binance", Account: "binance_1", TradeID: BinanceTradeID("KNCETH", 12), ETH: 0.0013, USD: 2.5},
+		{Source: "huobi", Account: "huobi_1", TradeID: "34", PriceMissing: true},
+	}))
+	require.NoError(t, tr.setCommissions(time.Time{}, time.Now(), trades))
+	assert.Equal(t, 0.0013, trades[0].FeeETH)
+	assert.Equal(t, 2.5, trades[0].FeeUSD)
+	// commissions without price and trades of other accounts are not valued
+	assert.Zero(t, trades[1].FeeETH)
+	assert.Zero(t, trades[2].FeeETH)
+}
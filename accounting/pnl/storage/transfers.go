@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/client"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage"
+	matchercommon "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// cexWithdrawalWindow is the duration before a time range CEX withdrawals are loaded from, a withdrawal is
+// recorded by the exchange before its on-chain transfer.
+const cexWithdrawalWindow = 24 * time.Hour
+
+// ownAddressTypes are the types of addresses whose balances are in the inventory.
+var ownAddressTypes = []cexcommon.AddressType{
+	cexcommon.Reserve,
+	cexcommon.IntermediateOperator,
+	cexcommon.CompanyWallet,
+	cexcommon.DepositOperator,
+}
+
+// TransfersInterface returns the transfers between reserve addresses and external addresses.
+type TransfersInterface interface {
+	GetTransfers(from, to time.Time) ([]common.Transfer, error)
+}
+
+// CEXTransfersInterface returns the deposits and withdrawals of CEX accounts.
+type CEXTransfersInterface interface {
+	GetTransfers(from, to time.Time) ([]matchercommon.CEXTransfer, error)
+}
+
+// Transfers reads ERC20 transfers, normal and internal transactions stored by
+// accounting-reserve-transaction-fetcher.
+type Transfers struct {
+	sugar     *zap.SugaredLogger
+	txStorage storage.ReserveTransactionStorage
+	addresses client.Interface
+	symbols   blockchain.TokenSymbolResolver
+	formatter blockchain.TokenAmountFormatterInterface
+	cex       CEXTransfersInterface
+}
+
+// TransfersOption is the option of Transfers constructor.
+type TransfersOption func(*Transfers)
+
+// WithCEXTransfers sets the source of CEX withdrawals, incoming transfers of CEX withdrawals are moved from
+// CEX accounts so they are not external.
+func WithCEXTransfers(cex CEXTransfersInterface) TransfersOption {
+	return func(t *Transfers) {
+		t.cex = cex
+	}
+}
+
+// NewTransfers creates a new Transfers instance.
+func NewTransfers(sugar *zap.SugaredLogger, txStorage storage.ReserveTransactionStorage, addresses client.Interface,
+	symbols blockchain.TokenSymbolResolver, formatter blockchain.TokenAmountFormatterInterface,
+	options ...TransfersOption) *Transfers {
+	t := &Transfers{
+		sugar:     sugar,
+		txStorage: txStorage,
+		addresses: addresses,
+		symbols:   symbols,
+		formatter: formatter,
+	}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// internalAddresses returns own and CEX deposit addresses, transfers between them do not change the inventory.
+func (t *Transfers) internalAddresses() (map[ethereum.Address]struct{}, map[ethereum.Address]struct{}, error) {
+	var (
+		own      = make(map[ethereum.Address]struct{})
+		internal = make(map[ethereum.Address]struct{})
+	)
+	addresses, err := t.addresses.ReserveAddresses(ownAddressTypes...)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, addr := range addresses {
+		own[addr.Address] = struct{}{}
+		internal[addr.Address] = struct{}{}
+	}
+	cexDeposit, err := t.addresses.ReserveAddresses(cexcommon.CEXDepositAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, addr := range cexDeposit {
+		internal[addr.Address] = struct{}{}
+	}
+	return own, internal, nil
+}
+
+// cexWithdrawalHashes returns the tx hashes of CEX withdrawals in given time range.
+func (t *Transfers) cexWithdrawalHashes(from, to time.Time) (map[string]struct{}, error) {
+	hashes := make(map[string]struct{})
+	if t.cex == nil {
+		return hashes, nil
+	}
+	transfers, err := t.cex.GetTransfers(from.Add(-cexWithdrawalWindow), to)
+	if err != nil {
+		return nil, err
+	}
+	for _, transfer := range transfers {
+		if transfer.Direction == matchercommon.Withdrawal && transfer.TxHash != "" {
+			hashes[matchercommon.NormalizeHash(transfer.TxHash)] = struct{}{}
+		}
+	}
+	return hashes, nil
+}
+
+// GetTransfers returns the ERC20 and ether transfers between own addresses and external addresses in given time
+// range. Failed transactions, settlements of trades, transfers between own and CEX deposit addresses and CEX
+// withdrawals are skipped.
+func (t *Transfers) GetTransfers(from, to time.Time) ([]common.Transfer, error) {
+	var (
+		logger = t.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		result  []common.Transfer
+		skipped int
+	)
+	own, internal, err := t.internalAddresses()
+	if err != nil {
+		return nil, err
+	}
+	withdrawals, err := t.cexWithdrawalHashes(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	add := func(id, hash string, fromAddr, toAddr, token ethereum.Address, value *big.Int, timestamp time.Time) error {
+		if value == nil || value.Sign() == 0 {
+			return nil
+		}
+		_, fromOwn := own[fromAddr]
+		_, toOwn := own[toAddr]
+		_, fromInternal := internal[fromAddr]
+		_, toInternal := internal[toAddr]
+		var (
+			account  ethereum.Address
+			incoming bool
+		)
+		switch {
+		case toOwn && !fromInternal:
+			if _, ok := withdrawals[matchercommon.NormalizeHash(hash)]; ok {
+				skipped++
+				return nil
+			}
+			account, incoming = toAddr, true
+		case fromOwn && !toInternal:
+			account = fromAddr
+		default:
+			return nil
+		}
+		symbol, err := t.symbols.Symbol(token)
+		if err != nil {
+			return err
+		}
+		amount, err := t.formatter.FromWei(token, value)
+		if err != nil {
+			return err
+		}
+		result = append(result, common.Transfer{
+			ID:        id,
+			Timestamp: timestamp,
+			Account:   account.Hex(),
+			Token:     symbol,
+			Amount:    amount,
+			Incoming:  incoming,
+		})
+		return nil
+	}
+
+	erc20Transfers, err := t.txStorage.GetERC20Transfer(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range erc20Transfers {
+		if tx.IsTrade {
+			continue
+		}
+		id := fmt.Sprintf("%s:%s:%s:%s", tx.Hash.Hex(), tx.ContractAddress.Hex(), tx.From.Hex(), tx.To.Hex())
+		if err = add(id, tx.Hash.Hex(), tx.From, tx.To, tx.ContractAddress, tx.Value, tx.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+
+	normalTxs, err := t.txStorage.GetNormalTx(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range normalTxs {
+		if tx.IsError != 0 {
+			continue
+		}
+		if err = add(tx.Hash, tx.Hash, ethereum.HexToAddress(tx.From), ethereum.HexToAddress(tx.To),
+			blockchain.ETHAddr, tx.Value, tx.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+
+	internalTxs, err := t.txStorage.GetInternalTx(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range internalTxs {
+		if tx.IsError != 0 || tx.IsTrade {
+			continue
+		}
+		id := fmt.Sprintf("%s:%s:%s", tx.Hash, tx.From, tx.To)
+		if err = add(id, tx.Hash, ethereum.HexToAddress(tx.From), ethereum.HexToAddress(tx.To),
+			blockchain.ETHAddr, tx.Value, tx.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugw("transfers loaded", "transfers", len(result), "cex_withdrawals", skipped)
+	return result, nil
+}
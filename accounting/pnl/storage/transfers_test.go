@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/pnl/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage"
+	matchercommon "github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+)
+
+type mockAddresses struct {
+	addresses []cexcommon.ReserveAddress
+}
+
+func (m *mockAddresses) ReserveAddresses(types ...cexcommon.AddressType) ([]cexcommon.ReserveAddress, error) {
+	var result []cexcommon.ReserveAddress
+	for _, addr := range m.addresses {
+		for _, typ := range types {
+			if addr.Type == typ {
+				result = append(result, addr)
+			}
+		}
+	}
+	return result, nil
+}
+
+type mockSymbols map[ethereum.Address]string
+
+func (m mockSymbols) Symbol(address ethereum.Address) (string, error) {
+	return m[address], nil
+}
+
+type mockTxStorage struct {
+	storage.ReserveTransactionStorage
+	erc20Transfers []cexcommon.ERC20Transfer
+	normalTxs      []cexcommon.NormalTx
+	internalTxs    []cexcommon.InternalTx
+}
+
+func (m *mockTxStorage) GetERC20Transfer(_, _ time.Time) ([]cexcommon.ERC20Transfer, error) {
+	return m.erc20Transfers, nil
+}
+
+func (m *mockTxStorage) GetNormalTx(_, _ time.Time) ([]cexcommon.NormalTx, error) {
+	return m.normalTxs, nil
+}
+
+func (m *mockTxStorage) GetInternalTx(_, _ time.Time) ([]cexcommon.InternalTx, error) {
+	return m.internalTxs, nil
+}
+
+type mockCEXTransfers []matchercommon.CEXTransfer
+
+func (m mockCEXTransfers) GetTransfers(_, _ time.Time) ([]matchercommon.CEXTransfer, error) {
+	return m, nil
+}
+
+func TestTransfers(t *testing.T) {
+	var (
+		reserve    = ethereum.HexToAddress("0x1")
+		wallet     = ethereum.HexToAddress("0x2")
+		cexDeposit = ethereum.HexToAddress("0x3")
+		external   = ethereum.HexToAddress("0x4")
+		token      = ethereum.HexToAddress("0x5")
+		oneToken   = big.NewInt(1000000000000000000)
+		ts         = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		addresses  = &mockAddresses{addresses: []cexcommon.ReserveAddress{
+			{Address: reserve, Type: cexcommon.Reserve},
+			{Address: wallet, Type: cexcommon.CompanyWallet},
+			{Address: cexDeposit, Type: cexcommon.CEXDepositAddress},
+		}}
+		symbols   = mockSymbols{token: "KNC", blockchain.ETHAddr: "ETH"}
+		txStorage = &mockTxStorage{
+			erc20Transfers: []cexcommon.ERC20Transfer{
+				{Hash: ethereum.HexToHash("0xa"), From: external, To: reserve, ContractAddress: token, Value: oneToken, Timestamp: ts},
+				// transfers between own and CEX deposit addresses are internal
+				{Hash: ethereum.HexToHash("0xb"), From: reserve, To: wallet, ContractAddress: token, Value: oneToken, Timestamp: ts},
+				{Hash: ethereum.HexToHash("0xc"), From: reserve, To: cexDeposit, ContractAddress: token, Value: oneToken, Timestamp: ts},
+				// settlements of trades are reserve trades
+				{Hash: ethereum.HexToHash("0xd"), From: reserve, To: external, ContractAddress: token, Value: oneToken, Timestamp: ts, IsTrade: true},
+				// CEX withdrawal is moved from a CEX account
+				{Hash: ethereum.HexToHash("0xe"), From: external, To: wallet, ContractAddress: token, Value: oneToken, Timestamp: ts},
+			},
+			normalTxs: []cexcommon.NormalTx{
+				{Hash: "0xf", From: wallet.Hex(), To: external.Hex(), Value: oneToken, Timestamp: ts},
+				{Hash: "0x10", From: wallet.Hex(), To: external.Hex(), Value: oneToken, Timestamp: ts, IsError: 1},
+			},
+			internalTxs: []cexcommon.InternalTx{
+				{Hash: "0x11", From: external.Hex(), To: reserve.Hex(), Value: oneToken, Timestamp: ts, IsTrade: true},
+			},
+		}
+		cex = mockCEXTransfers{
+			{Direction: matchercommon.Withdrawal, TxHash: ethereum.HexToHash("0xe").Hex()[2:]},
+		}
+	)
+	s := NewTransfers(zap.NewNop().Sugar(), txStorage, addresses, symbols, blockchain.NewMockTokenAmountFormatter(),
+		WithCEXTransfers(cex))
+	transfers, err := s.GetTransfers(ts, ts.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+	assert.Equal(t, common.Transfer{
+		ID:        ethereum.HexToHash("0xa").Hex() + ":" + token.Hex() + ":" + external.Hex() + ":" + reserve.Hex(),
+		Timestamp: ts,
+		Account:   reserve.Hex(),
+		Token:     "KNC",
+		Amount:    1,
+		Incoming:  true,
+	}, transfers[0])
+	assert.Equal(t, "ETH", transfers[1].Token)
+	assert.Equal(t, wallet.Hex(), transfers[1].Account)
+	assert.False(t, transfers[1].Incoming)
+}
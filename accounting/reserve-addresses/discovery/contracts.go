@@ -0,0 +1,127 @@
+package discovery
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethereum "github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/lib/contracts"
+)
+
+// Found is an address found in contracts of a reserve.
+type Found struct {
+	Address ethereum.Address
+	Type    common.AddressType
+	// Description is where the address is found.
+	Description string
+}
+
+// ContractReader returns the addresses managing a reserve.
+type ContractReader interface {
+	Read(reserve ethereum.Address) ([]Found, error)
+}
+
+// managedContract is the common methods of reserve, conversion rates and sanity rates contracts.
+type managedContract interface {
+	Admin(opts *bind.CallOpts) (ethereum.Address, error)
+	GetOperators(opts *bind.CallOpts) ([]ethereum.Address, error)
+	GetAlerters(opts *bind.CallOpts) ([]ethereum.Address, error)
+}
+
+// NodeContractReader reads the operators, alerters and admin of reserve, conversion rates and sanity
+// rates contracts from an Ethereum node.
+type NodeContractReader struct {
+	backend bind.ContractBackend
+}
+
+// NewNodeContractReader creates a new instance of NodeContractReader.
+func NewNodeContractReader(backend bind.ContractBackend) *NodeContractReader {
+	return &NodeContractReader{backend: backend}
+}
+
+// Read returns the addresses managing the reserve contract and its conversion rates and sanity rates
+// contracts. Sanity rates contract is skipped if the reserve has none.
+func (r *NodeContractReader) Read(reserve ethereum.Address) ([]Found, error) {
+	reserveContract, err := contracts.NewReserve(reserve, r.backend)
+	if err != nil {
+		return nil, err
+	}
+	result, err := read(reserveContract, "reserve", reserve, common.ReserveOperator)
+	if err != nil {
+		return nil, err
+	}
+
+	conversionRates, err := reserveContract.ConversionRatesContract(nil)
+	if err != nil {
+		return nil, err
+	}
+	conversionRatesContract, err := contracts.NewConversionRates(conversionRates, r.backend)
+	if err != nil {
+		return nil, err
+	}
+	found, err := read(conversionRatesContract, fmt.Sprintf("conversion rates %s of reserve", conversionRates.String()), reserve,
+		common.PricingOperator)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, found...)
+
+	sanityRates, err := reserveContract.SanityRatesContract(nil)
+	if err != nil {
+		return nil, err
+	}
+	if sanityRates == (ethereum.Address{}) {
+		return result, nil
+	}
+	sanityRatesContract, err := contracts.NewSanityRates(sanityRates, r.backend)
+	if err != nil {
+		return nil, err
+	}
+	found, err = read(sanityRatesContract, fmt.Sprintf("sanity rates %s of reserve", sanityRates.String()), reserve,
+		common.SanityOperator)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, found...), nil
+}
+
+// read returns the operators, alerters and admin of contract, operators are typed as operatorType.
+func read(contract managedContract, name string, reserve ethereum.Address, operatorType common.AddressType) ([]Found, error) {
+	var result []Found
+	operators, err := contract.GetOperators(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, operator := range operators {
+		result = append(result, Found{
+			Address:     operator,
+			Type:        operatorType,
+			Description: fmt.Sprintf("operator of %s %s", name, reserve.String()),
+		})
+	}
+	alerters, err := contract.GetAlerters(nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, alerter := range alerters {
+		result = append(result, Found{
+			Address:     alerter,
+			Type:        common.Alerter,
+			Description: fmt.Sprintf("alerter of %s %s", name, reserve.String()),
+		})
+	}
+	admin, err := contract.Admin(nil)
+	if err != nil {
+		return nil, err
+	}
+	if admin != (ethereum.Address{}) {
+		result = append(result, Found{
+			Address:     admin,
+			Type:        common.Admin,
+			Description: fmt.Sprintf("admin of %s %s", name, reserve.String()),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package discovery
+
+import (
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// discoveredTypes are the address types managed by discovery, in priority order when an address is found
+// with more than one type.
+var discoveredTypes = []common.AddressType{
+	common.PricingOperator,
+	common.SanityOperator,
+	common.ReserveOperator,
+	common.Alerter,
+	common.Admin,
+}
+
+// Storage is the reserve addresses storage used by Discoverer.
+type Storage interface {
+	GetAll() ([]*common.ReserveAddress, int64, error)
+	GetDeleted() ([]*common.ReserveAddress, error)
+	Propose(proposals []common.AddressProposal) (int, error)
+}
+
+// Discoverer proposes the operators, alerters and admins of registered reserves as new reserve addresses,
+// and the stored ones no longer found in contracts for removal.
+type Discoverer struct {
+	sugar  *zap.SugaredLogger
+	reader ContractReader
+	st     Storage
+}
+
+// NewDiscoverer creates a new instance of Discoverer.
+func NewDiscoverer(sugar *zap.SugaredLogger, reader ContractReader, st Storage) *Discoverer {
+	return &Discoverer{sugar: sugar, reader: reader, st: st}
+}
+
+// Run reads the contracts of registered reserves and stores the proposals. It returns the number of new
+// proposals, the ones already pending or rejected are not stored again. Reserves which could not be read,
+// like bridge reserves, are skipped, it returns an error only if no reserve could be read.
+func (d *Discoverer) Run() (int, error) {
+	var logger = d.sugar.With("func", caller.GetCurrentFunctionName())
+
+	addrs, _, err := d.st.GetAll()
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := d.st.GetDeleted()
+	if err != nil {
+		return 0, err
+	}
+	var (
+		found    []Found
+		reserves int
+		skipped  []ethereum.Address
+		readErr  error
+	)
+	for _, addr := range addrs {
+		if addr.Type != common.Reserve {
+			continue
+		}
+		reserves++
+		logger.Infow("reading reserve contracts", "reserve", addr.Address.String())
+		reserveFound, err := d.reader.Read(addr.Address)
+		if err != nil {
+			logger.Warnw("failed to read reserve contracts, skip it", "reserve", addr.Address.String(), "error", err)
+			skipped = append(skipped, addr.Address)
+			readErr = err
+			continue
+		}
+		found = append(found, reserveFound...)
+	}
+	if reserves != 0 && len(skipped) == reserves {
+		return 0, readErr
+	}
+
+	proposals := Propose(addrs, deleted, found, skipped)
+	logger.Infow("found address changes", "found", len(found), "skipped", len(skipped), "proposals", len(proposals))
+	stored, err := d.st.Propose(proposals)
+	if err != nil {
+		return 0, err
+	}
+	logger.Infow("stored address proposals", "stored", stored)
+	return stored, nil
+}
+
+// mentionsAny returns true if the description contains any of the addresses.
+func mentionsAny(description string, addrs []ethereum.Address) bool {
+	description = strings.ToLower(description)
+	for _, addr := range addrs {
+		if strings.Contains(description, strings.ToLower(addr.String())) {
+			return true
+		}
+	}
+	return false
+}
+
+// Propose returns the proposals adding found addresses which are not registered, and removing registered
+// addresses of discovered types which are not found. A found address which is deleted is proposed to
+// restore the deleted one, registered addresses already being deleted are not proposed to remove. An address
+// found with many types is proposed with the first of discoveredTypes.
+//
+// Registered addresses are not linked to reserves, so if any reserve is skipped, only the addresses whose
+// description names a read reserve, as descriptions of found addresses do, are proposed to remove.
+func Propose(registered, deleted []*common.ReserveAddress, found []Found, skipped []ethereum.Address) []common.AddressProposal {
+	var (
+		read       []ethereum.Address
+		isSkipped  = make(map[ethereum.Address]bool)
+		priority   = make(map[common.AddressType]int)
+		byAddress  = make(map[ethereum.Address]Found)
+		order      []ethereum.Address
+		known      = make(map[ethereum.Address]bool)
+		deletedIDs = make(map[ethereum.Address]uint64)
+		proposals  []common.AddressProposal
+		discovered = func(typ common.AddressType) bool {
+			_, ok := priority[typ]
+			return ok
+		}
+	)
+	for i, typ := range discoveredTypes {
+		priority[typ] = i
+	}
+	for _, reserve := range skipped {
+		isSkipped[reserve] = true
+	}
+	for _, f := range found {
+		current, ok := byAddress[f.Address]
+		if !ok {
+			order = append(order, f.Address)
+		}
+		if !ok || priority[f.Type] < priority[current.Type] {
+			byAddress[f.Address] = f
+		}
+	}
+	for _, addr := range registered {
+		known[addr.Address] = true
+		if addr.Type == common.Reserve && !isSkipped[addr.Address] {
+			read = append(read, addr.Address)
+		}
+	}
+	for _, addr := range deleted {
+		deletedIDs[addr.Address] = addr.ID
+	}
+
+	for _, address := range order {
+		if known[address] {
+			continue
+		}
+		f := byAddress[address]
+		proposals = append(proposals, common.AddressProposal{
+			Action:      common.AddressProposalAdd,
+			Address:     f.Address,
+			Type:        f.Type,
+			AddressID:   deletedIDs[address],
+			Description: f.Description,
+		})
+	}
+	for _, addr := range registered {
+		if _, ok := byAddress[addr.Address]; ok || !discovered(addr.Type) || !addr.EffectiveUntil.IsZero() {
+			continue
+		}
+		if len(skipped) != 0 && (!mentionsAny(addr.Description, read) || mentionsAny(addr.Description, skipped)) {
+			continue
+		}
+		proposals = append(proposals, common.AddressProposal{
+			Action:      common.AddressProposalRemove,
+			Address:     addr.Address,
+			Type:        addr.Type,
+			AddressID:   addr.ID,
+			Description: addr.Description,
+		})
+	}
+	return proposals
+}
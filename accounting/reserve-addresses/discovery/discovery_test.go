@@ -0,0 +1,165 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+)
+
+type stubReader map[ethereum.Address][]Found
+
+func (r stubReader) Read(reserve ethereum.Address) ([]Found, error) {
+	return r[reserve], nil
+}
+
+// unreadableReader fails to read the contracts of reserves not in stubReader, like bridge reserves.
+type unreadableReader stubReader
+
+func (r unreadableReader) Read(reserve ethereum.Address) ([]Found, error) {
+	found, ok := r[reserve]
+	if !ok {
+		return nil, errors.New("no contract code at given address")
+	}
+	return found, nil
+}
+
+type stubStorage struct {
+	addrs     []*common.ReserveAddress
+	deleted   []*common.ReserveAddress
+	proposals []common.AddressProposal
+}
+
+func (s *stubStorage) GetAll() ([]*common.ReserveAddress, int64, error) {
+	return s.addrs, 1, nil
+}
+
+func (s *stubStorage) GetDeleted() ([]*common.ReserveAddress, error) {
+	return s.deleted, nil
+}
+
+func (s *stubStorage) Propose(proposals []common.AddressProposal) (int, error) {
+	s.proposals = append(s.proposals, proposals...)
+	return len(proposals), nil
+}
+
+func TestDiscoverer(t *testing.T) {
+	var (
+		reserve  = ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		operator = ethereum.HexToAddress("0x8bc3da587def887b5c822105729ee1d6af05a5ca")
+		admin    = ethereum.HexToAddress("0x2Fe82F4e23e670DB6FE97e657c885D54F0cE0b90")
+		removed  = ethereum.HexToAddress("0x9224016462b204c57eb70e1d69652f60bcaf53a8")
+		wallet   = ethereum.HexToAddress("0x0c8fd73eaf6089ef1b91231d0a07d0d2ca2b9d66")
+		alerter  = ethereum.HexToAddress("0x31cF5d400653cbBa0C4874eE0E034BD800763c04")
+		restored = ethereum.HexToAddress("0x4f32bbe8dfc9efd54345fc936f9fef1048746fcf")
+		deleting = ethereum.HexToAddress("0xdd974d5c2e2928dea5f71b9825b8b646686bd200")
+		st       = &stubStorage{
+			addrs: []*common.ReserveAddress{
+				{ID: 1, Address: reserve, Type: common.Reserve},
+				{ID: 2, Address: operator, Type: common.PricingOperator},
+				{ID: 3, Address: removed, Type: common.SanityOperator, Description: "old sanity operator"},
+				{ID: 4, Address: wallet, Type: common.CompanyWallet},
+				// deleted effective until a future time, not proposed to remove again
+				{ID: 6, Address: deleting, Type: common.Alerter, EffectiveUntil: time.Now().Add(time.Hour)},
+			},
+			deleted: []*common.ReserveAddress{
+				{ID: 5, Address: restored, Type: common.SanityOperator, Description: "deleted sanity operator"},
+			},
+		}
+		reader = stubReader{reserve: {
+			{Address: operator, Type: common.PricingOperator},
+			{Address: restored, Type: common.SanityOperator, Description: "sanity operator of reserve"},
+			{Address: alerter, Type: common.Alerter, Description: "alerter of reserve"},
+			// found as admin and operator, proposed once with the type of higher priority
+			{Address: admin, Type: common.Admin, Description: "admin of reserve"},
+			{Address: admin, Type: common.ReserveOperator, Description: "operator of reserve"},
+		}}
+	)
+
+	d := NewDiscoverer(testutil.MustNewDevelopmentSugaredLogger(), reader, st)
+	stored, err := d.Run()
+	require.NoError(t, err)
+	assert.Equal(t, 4, stored)
+	assert.Equal(t, []common.AddressProposal{
+		{
+			Action:      common.AddressProposalAdd,
+			Address:     restored,
+			Type:        common.SanityOperator,
+			AddressID:   5,
+			Description: "sanity operator of reserve",
+		},
+		{
+			Action:      common.AddressProposalAdd,
+			Address:     alerter,
+			Type:        common.Alerter,
+			Description: "alerter of reserve",
+		},
+		{
+			Action:      common.AddressProposalAdd,
+			Address:     admin,
+			Type:        common.ReserveOperator,
+			Description: "operator of reserve",
+		},
+		{
+			Action:      common.AddressProposalRemove,
+			Address:     removed,
+			Type:        common.SanityOperator,
+			AddressID:   3,
+			Description: "old sanity operator",
+		},
+	}, st.proposals)
+}
+
+func TestDiscovererSkipsUnreadableReserve(t *testing.T) {
+	var (
+		reserve        = ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		bridge         = ethereum.HexToAddress("0x21433Dec9Cb634A23c6A4BbcCe08c83f5aC2EC18")
+		operator       = ethereum.HexToAddress("0x8bc3da587def887b5c822105729ee1d6af05a5ca")
+		removed        = ethereum.HexToAddress("0x9224016462b204c57eb70e1d69652f60bcaf53a8")
+		bridgeOperator = ethereum.HexToAddress("0x0c8fd73eaf6089ef1b91231d0a07d0d2ca2b9d66")
+		manual         = ethereum.HexToAddress("0x31cF5d400653cbBa0C4874eE0E034BD800763c04")
+		st             = &stubStorage{
+			addrs: []*common.ReserveAddress{
+				{ID: 1, Address: reserve, Type: common.Reserve},
+				{ID: 2, Address: bridge, Type: common.Reserve},
+				{ID: 3, Address: operator, Type: common.PricingOperator},
+				{ID: 4, Address: removed, Type: common.PricingOperator,
+					Description: fmt.Sprintf("operator of reserve %s", reserve.String())},
+				// could only be confirmed by the skipped reserve
+				{ID: 5, Address: bridgeOperator, Type: common.ReserveOperator,
+					Description: fmt.Sprintf("operator of reserve %s", bridge.String())},
+				// not known which reserve it belongs to
+				{ID: 6, Address: manual, Type: common.Alerter, Description: "alerter"},
+			},
+		}
+		reader = unreadableReader{reserve: {
+			{Address: operator, Type: common.PricingOperator},
+		}}
+	)
+
+	d := NewDiscoverer(testutil.MustNewDevelopmentSugaredLogger(), reader, st)
+	stored, err := d.Run()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+	assert.Equal(t, []common.AddressProposal{
+		{
+			Action:      common.AddressProposalRemove,
+			Address:     removed,
+			Type:        common.PricingOperator,
+			AddressID:   4,
+			Description: fmt.Sprintf("operator of reserve %s", reserve.String()),
+		},
+	}, st.proposals)
+
+	// it fails if no reserve could be read
+	d = NewDiscoverer(testutil.MustNewDevelopmentSugaredLogger(), unreadableReader{}, st)
+	_, err = d.Run()
+	assert.Error(t, err)
+}
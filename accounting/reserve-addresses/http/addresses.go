@@ -4,21 +4,47 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	ethereum "github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/storage"
+	"github.com/KyberNetwork/reserve-stats/gateway/permission"
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
 	_ "github.com/KyberNetwork/reserve-stats/lib/httputil/validators" // import custom validator functions
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
 )
 
 type createInput struct {
 	Address     string `json:"address" binding:"required"`
 	Type        string `json:"type" binding:"required"`
 	Description string `json:"description"`
+	// EffectiveFrom is the time in millis the address is used in accounting from, default is unbounded.
+	EffectiveFrom uint64 `json:"effective_from"`
+}
+
+// changedBy returns the API key id of request made through gateway, it is the author of the changes.
+func changedBy(c *gin.Context) string {
+	keyID, err := permission.GetKeyID(c.Request)
+	if err != nil {
+		return ""
+	}
+	return string(keyID)
+}
+
+// millisToTime returns the time of unix millis or zero time if millis is zero.
+func millisToTime(millis uint64) time.Time {
+	if millis == 0 {
+		return time.Time{}
+	}
+	return timeutil.TimestampMsToTime(millis)
+}
+
+type createResponse struct {
+	ID uint64 `json:"id"`
 }
 
 func (s *Server) create(c *gin.Context) {
@@ -57,7 +83,7 @@ func (s *Server) create(c *gin.Context) {
 		return
 	}
 
-	id, err := s.storage.Create(address, addressType, input.Description)
+	id, err := s.storage.Create(address, addressType, input.Description, millisToTime(input.EffectiveFrom), changedBy(c))
 	if err == storage.ErrExists {
 		httputil.ResponseFailure(
 			c,
@@ -73,7 +99,12 @@ func (s *Server) create(c *gin.Context) {
 		)
 		return
 	}
-	c.JSON(http.StatusCreated, gin.H{"id": id})
+	c.JSON(http.StatusCreated, createResponse{ID: id})
+}
+
+// idParam describes the id parameter, it is parsed by getIDParam.
+type idParam struct {
+	ID uint64 `uri:"id"`
 }
 
 // getIDParam gets and validates the id parameter from given context.
@@ -124,8 +155,32 @@ func (s *Server) get(c *gin.Context) {
 	c.JSON(http.StatusOK, ra)
 }
 
+type getAllQuery struct {
+	// At is the time in millis to return the addresses effective at, default is now.
+	At uint64 `form:"at"`
+}
+
 func (s *Server) getAll(c *gin.Context) {
-	addrs, version, err := s.storage.GetAll()
+	var (
+		query   getAllQuery
+		addrs   []*common.ReserveAddress
+		version int64
+		err     error
+	)
+	if err = c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusBadRequest,
+			err,
+		)
+		return
+	}
+
+	if query.At != 0 {
+		addrs, version, err = s.storage.GetAt(timeutil.TimestampMsToTime(query.At))
+	} else {
+		addrs, version, err = s.storage.GetAll()
+	}
 	if err != nil {
 		httputil.ResponseFailure(
 			c,
@@ -141,13 +196,18 @@ func (s *Server) getAll(c *gin.Context) {
 
 	c.JSON(
 		http.StatusOK,
-		gin.H{
-			"version": version,
-			"data":    addrs,
+		getAllResponse{
+			Version: version,
+			Data:    addrs,
 		},
 	)
 }
 
+type getAllResponse struct {
+	Version int64                    `json:"version"`
+	Data    []*common.ReserveAddress `json:"data"`
+}
+
 type updateInput struct {
 	Address     string  `json:"address"`
 	Type        *string `json:"type"`
@@ -204,7 +264,68 @@ func (s *Server) update(c *gin.Context) {
 
 	address := ethereum.HexToAddress(input.Address)
 
-	if err = s.storage.Update(id, address, addressType, input.Description); err == storage.ErrNotExists {
+	if err = s.storage.Update(id, address, addressType, input.Description, changedBy(c)); err == storage.ErrNotExists {
+		httputil.ResponseFailure(
+			c,
+			http.StatusNotFound,
+			err,
+		)
+		return
+	} else if err == storage.ErrExists {
+		httputil.ResponseFailure(
+			c,
+			http.StatusConflict,
+			err,
+		)
+		return
+	} else if err != nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusInternalServerError,
+			err,
+		)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+type deleteQuery struct {
+	// EffectiveUntil is the time in millis the address is no longer used in accounting, default is now.
+	EffectiveUntil uint64 `form:"effective_until"`
+}
+
+func (s *Server) delete(c *gin.Context) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+		query  deleteQuery
+	)
+
+	id, err := getIDParam(c)
+	if err != nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusBadRequest,
+			err,
+		)
+		return
+	}
+	if err = c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusBadRequest,
+			err,
+		)
+		return
+	}
+	effectiveUntil := time.Now()
+	if query.EffectiveUntil != 0 {
+		effectiveUntil = timeutil.TimestampMsToTime(query.EffectiveUntil)
+	}
+
+	logger = logger.With("id", id, "effective_until", effectiveUntil)
+	logger.Debug("deleting reserve address")
+
+	if err = s.storage.Delete(id, effectiveUntil, changedBy(c)); err == storage.ErrNotExists {
 		httputil.ResponseFailure(
 			c,
 			http.StatusNotFound,
@@ -221,3 +342,75 @@ func (s *Server) update(c *gin.Context) {
 	}
 	c.Status(http.StatusNoContent)
 }
+
+func (s *Server) restore(c *gin.Context) {
+	var logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+
+	id, err := getIDParam(c)
+	if err != nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusBadRequest,
+			err,
+		)
+		return
+	}
+
+	logger.Debugw("restoring reserve address", "id", id)
+
+	switch err = s.storage.Restore(id, changedBy(c)); err {
+	case nil:
+		c.Status(http.StatusNoContent)
+	case storage.ErrNotExists:
+		httputil.ResponseFailure(
+			c,
+			http.StatusNotFound,
+			err,
+		)
+	case storage.ErrExists:
+		httputil.ResponseFailure(
+			c,
+			http.StatusConflict,
+			err,
+		)
+	default:
+		httputil.ResponseFailure(
+			c,
+			http.StatusInternalServerError,
+			err,
+		)
+	}
+}
+
+func (s *Server) getVersions(c *gin.Context) {
+	id, err := getIDParam(c)
+	if err != nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusBadRequest,
+			err,
+		)
+		return
+	}
+
+	versions, err := s.storage.GetVersions(id)
+	if err == storage.ErrNotExists {
+		httputil.ResponseFailure(
+			c,
+			http.StatusNotFound,
+			err,
+		)
+		return
+	} else if err != nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusInternalServerError,
+			err,
+		)
+		return
+	}
+	if versions == nil {
+		versions = []common.ReserveAddressVersion{}
+	}
+	c.JSON(http.StatusOK, versions)
+}
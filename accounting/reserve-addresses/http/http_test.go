@@ -17,10 +17,12 @@ import (
 
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	rcommon "github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/storage"
 	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/storage/postgresql"
 	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
 	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
 )
 
 var (
@@ -63,7 +65,7 @@ func TestReserveAddressGetAll(t *testing.T) {
 	)
 
 	t.Log("creating a test reserve address")
-	id1, err := tst.Create(testAddress1, common.Reserve, testDescription1)
+	id1, err := tst.Create(testAddress1, common.Reserve, testDescription1, time.Time{}, "")
 	require.NoError(t, err)
 
 	tests = []httputil.HTTPTestCase{
@@ -94,7 +96,7 @@ func TestReserveAddressGetAll(t *testing.T) {
 	}
 
 	t.Log("creating a test reserve address")
-	id2, err := tst.Create(testAddress2, common.PricingOperator, testDescription2)
+	id2, err := tst.Create(testAddress2, common.PricingOperator, testDescription2, time.Time{}, "")
 	require.NoError(t, err)
 
 	tests = []httputil.HTTPTestCase{
@@ -231,7 +233,7 @@ func TestReserveAddressesGet(t *testing.T) {
 	)
 
 	t.Log("creating a test reserve address")
-	id, err := tst.Create(testAddress, common.PricingOperator, testDescription)
+	id, err := tst.Create(testAddress, common.PricingOperator, testDescription, time.Time{}, "")
 	require.NoError(t, err)
 
 	var tests = []httputil.HTTPTestCase{
@@ -286,7 +288,7 @@ func TestReserveAddressUpdate(t *testing.T) {
 	)
 
 	t.Log("creating a test reserve address")
-	id, err := tst.Create(testAddress, common.SanityOperator, testDescription)
+	id, err := tst.Create(testAddress, common.SanityOperator, testDescription, time.Time{}, "")
 	require.NoError(t, err)
 
 	var tests = []httputil.HTTPTestCase{
@@ -555,6 +557,358 @@ func TestVersion(t *testing.T) {
 	}
 }
 
+func TestReserveAddressHistory(t *testing.T) {
+	var (
+		testAddress     = ethereum.HexToAddress("0x4d0c4a5ea2e9fd9b1ab8e1ea0a3a47c0a4c8c9c1")
+		testDescription = "test intermediate operator"
+	)
+
+	t.Log("creating a test reserve address")
+	id, err := tst.Create(testAddress, common.IntermediateOperator, testDescription, time.Time{}, "")
+	require.NoError(t, err)
+	require.NoError(t, tst.Update(id, ethereum.Address{}, nil, "updated intermediate operator", "test-key"))
+	updatedAt := time.Now()
+
+	findAddress := func(t *testing.T, resp *httptest.ResponseRecorder) *common.ReserveAddress {
+		t.Helper()
+		require.Equal(t, http.StatusOK, resp.Code)
+		var response rcommon.AllAddressesResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		for i := range response.Data {
+			if response.Data[i].ID == id {
+				return &response.Data[i]
+			}
+		}
+		return nil
+	}
+
+	var tests = []httputil.HTTPTestCase{
+		{
+			Msg:      "get versions of reserve address",
+			Endpoint: fmt.Sprintf("/addresses/%d/versions", id),
+			Method:   http.MethodGet,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				t.Helper()
+				require.Equal(t, http.StatusOK, resp.Code)
+				var versions []common.ReserveAddressVersion
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&versions))
+				require.Len(t, versions, 2)
+				assert.Equal(t, common.ReserveAddressCreated, versions[0].Action)
+				assert.Nil(t, versions[0].Before)
+				assert.Equal(t, testDescription, versions[0].After.Description)
+				assert.Equal(t, common.ReserveAddressUpdated, versions[1].Action)
+				assert.Equal(t, "test-key", versions[1].ChangedBy)
+				assert.Equal(t, testDescription, versions[1].Before.Description)
+				assert.Equal(t, "updated intermediate operator", versions[1].After.Description)
+			},
+		},
+		{
+			Msg:      "get versions of non existing address",
+			Endpoint: fmt.Sprintf("/addresses/%d/versions", id+100),
+			Method:   http.MethodGet,
+			Assert:   httputil.AssertCode(http.StatusNotFound),
+		},
+		{
+			Msg:      "delete reserve address",
+			Endpoint: fmt.Sprintf("/addresses/%d?effective_until=%d", id, timeutil.TimeToTimestampMs(updatedAt.Add(time.Hour))),
+			Method:   http.MethodDelete,
+			Assert:   httputil.AssertCode(http.StatusNoContent),
+		},
+		{
+			Msg:      "delete non existing address",
+			Endpoint: fmt.Sprintf("/addresses/%d", id+100),
+			Method:   http.MethodDelete,
+			Assert:   httputil.AssertCode(http.StatusNotFound),
+		},
+		{
+			Msg:      "deleted address is effective until its effective until time",
+			Endpoint: "/addresses",
+			Method:   http.MethodGet,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				t.Helper()
+				addr := findAddress(t, resp)
+				require.NotNil(t, addr)
+				assert.Equal(t, "updated intermediate operator", addr.Description)
+				assert.False(t, addr.EffectiveUntil.IsZero())
+			},
+		},
+		{
+			Msg:      "get addresses after effective until time",
+			Endpoint: fmt.Sprintf("/addresses?at=%d", timeutil.TimeToTimestampMs(updatedAt.Add(2*time.Hour))),
+			Method:   http.MethodGet,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				t.Helper()
+				assert.Nil(t, findAddress(t, resp))
+			},
+		},
+		{
+			Msg:      "get addresses before update",
+			Endpoint: fmt.Sprintf("/addresses?at=%d", timeutil.TimeToTimestampMs(tts.Add(-time.Hour))),
+			Method:   http.MethodGet,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				t.Helper()
+				addr := findAddress(t, resp)
+				require.NotNil(t, addr)
+				assert.Equal(t, testDescription, addr.Description)
+			},
+		},
+		{
+			Msg:      "get addresses with invalid time",
+			Endpoint: "/addresses?at=invalid",
+			Method:   http.MethodGet,
+			Assert:   httputil.AssertCode(http.StatusBadRequest),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Msg, func(t *testing.T) { httputil.RunHTTPTestCase(t, tc, ts.r) })
+	}
+}
+
+func TestReserveAddressRecreate(t *testing.T) {
+	var (
+		testAddress = ethereum.HexToAddress("0x7a3c3b2e6c1f4d5e8a9b0c1d2e3f405162738495")
+		deletedAt   = time.Now().Add(-time.Hour)
+	)
+
+	t.Log("creating and deleting a test reserve address")
+	id, err := tst.Create(testAddress, common.CompanyWallet, "old wallet", time.Time{}, "")
+	require.NoError(t, err)
+	require.NoError(t, tst.Delete(id, deletedAt, ""))
+
+	// deleted address is created again as a new address
+	newID, err := tst.Create(testAddress, common.CompanyWallet, "new wallet", deletedAt, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, id, newID)
+	_, err = tst.Create(testAddress, common.CompanyWallet, "duplicated wallet", time.Time{}, "")
+	assert.Equal(t, storage.ErrExists, err)
+
+	// the old address could not be restored while the new one is in use
+	assert.Equal(t, storage.ErrExists, tst.Restore(id, ""))
+
+	old, _, err := tst.GetAt(deletedAt.Add(-time.Minute))
+	require.NoError(t, err)
+	var found bool
+	for _, addr := range old {
+		if addr.ID == id {
+			found = true
+			assert.Equal(t, "old wallet", addr.Description)
+		}
+		assert.NotEqual(t, newID, addr.ID)
+	}
+	assert.True(t, found)
+
+	// updating a deleted address does not restore it
+	require.NoError(t, tst.Delete(newID, time.Now(), ""))
+	require.NoError(t, tst.Update(newID, ethereum.Address{}, nil, "updated wallet", ""))
+	updated, err := tst.Get(newID)
+	require.NoError(t, err)
+	assert.False(t, updated.EffectiveUntil.IsZero())
+	assert.Equal(t, "updated wallet", updated.Description)
+
+	require.NoError(t, tst.Restore(newID, ""))
+	restored, err := tst.Get(newID)
+	require.NoError(t, err)
+	assert.True(t, restored.EffectiveUntil.IsZero())
+	assert.Equal(t, "updated wallet", restored.Description)
+}
+
+func TestReserveAddressRecreateBeforeEffectiveUntil(t *testing.T) {
+	var (
+		testAddress    = ethereum.HexToAddress("0x2f9ec37d6ccfff1cab21733bdadede11c823ccb0")
+		effectiveUntil = time.Now().Add(time.Hour)
+	)
+
+	id, err := tst.Create(testAddress, common.CompanyWallet, "wallet", time.Time{}, "")
+	require.NoError(t, err)
+	require.NoError(t, tst.Delete(id, effectiveUntil, ""))
+
+	// the address is still effective until the end of its effective period
+	_, err = tst.Create(testAddress, common.CompanyWallet, "new wallet", time.Time{}, "")
+	assert.Equal(t, storage.ErrExists, err)
+	_, err = tst.Create(testAddress, common.CompanyWallet, "new wallet", effectiveUntil.Add(-time.Minute), "")
+	assert.Equal(t, storage.ErrExists, err)
+
+	newID, err := tst.Create(testAddress, common.CompanyWallet, "new wallet", effectiveUntil, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, id, newID)
+
+	// restore endpoint rejects restoring the old address while the new one is in use
+	tests := []httputil.HTTPTestCase{
+		{
+			Msg:      "restore address in use",
+			Endpoint: fmt.Sprintf("/addresses/%d/restore", id),
+			Method:   http.MethodPost,
+			Assert:   httputil.AssertCode(http.StatusConflict),
+		},
+		{
+			Msg:      "restore not existing address",
+			Endpoint: "/addresses/9999999/restore",
+			Method:   http.MethodPost,
+			Assert:   httputil.AssertCode(http.StatusNotFound),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Msg, func(t *testing.T) { httputil.RunHTTPTestCase(t, tc, ts.r) })
+	}
+}
+
+func TestAddressProposals(t *testing.T) {
+	var (
+		addAddress    = ethereum.HexToAddress("0x1d2a3b9f2b1b9f6fa3d1c9c6d47bda04d1e3f5a1")
+		removeAddress = ethereum.HexToAddress("0x5e2b1b1f7c4e0f9c2d3a6b8e9f0a1b2c3d4e5f60")
+		proposals     []common.AddressProposal
+	)
+
+	t.Log("creating a test reserve address")
+	removeID, err := tst.Create(removeAddress, common.Alerter, "old alerter", time.Time{}, "")
+	require.NoError(t, err)
+
+	proposed := []common.AddressProposal{
+		{Action: common.AddressProposalAdd, Address: addAddress, Type: common.PricingOperator, Description: "operator of reserve"},
+		{Action: common.AddressProposalRemove, Address: removeAddress, Type: common.Alerter, AddressID: removeID},
+	}
+	stored, err := tst.Propose(proposed)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored)
+	// pending proposals are not stored again
+	stored, err = tst.Propose(proposed)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored)
+
+	var tests = []httputil.HTTPTestCase{
+		{
+			Msg:      "get pending proposals",
+			Endpoint: "/address-proposals",
+			Method:   http.MethodGet,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				t.Helper()
+				require.Equal(t, http.StatusOK, resp.Code)
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&proposals))
+				require.Len(t, proposals, 2)
+				assert.Equal(t, common.AddressProposalAdd, proposals[0].Action)
+				assert.Equal(t, addAddress, proposals[0].Address)
+				assert.Equal(t, common.AddressProposalPending, proposals[0].Status)
+				assert.Equal(t, removeID, proposals[1].AddressID)
+			},
+		},
+		{
+			Msg:      "get proposals of invalid status",
+			Endpoint: "/address-proposals?status=invalid",
+			Method:   http.MethodGet,
+			Assert:   httputil.AssertCode(http.StatusBadRequest),
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Msg, func(t *testing.T) { httputil.RunHTTPTestCase(t, tc, ts.r) })
+	}
+	require.Len(t, proposals, 2)
+
+	tests = []httputil.HTTPTestCase{
+		{
+			Msg:      "approve add proposal",
+			Endpoint: fmt.Sprintf("/address-proposals/%d/approve", proposals[0].ID),
+			Method:   http.MethodPost,
+			Assert:   httputil.AssertCode(http.StatusNoContent),
+		},
+		{
+			Msg:      "approve decided proposal",
+			Endpoint: fmt.Sprintf("/address-proposals/%d/approve", proposals[0].ID),
+			Method:   http.MethodPost,
+			Assert:   httputil.AssertCode(http.StatusConflict),
+		},
+		{
+			Msg:      "reject remove proposal",
+			Endpoint: fmt.Sprintf("/address-proposals/%d/reject", proposals[1].ID),
+			Method:   http.MethodPost,
+			Assert:   httputil.AssertCode(http.StatusNoContent),
+		},
+		{
+			Msg:      "reject non existing proposal",
+			Endpoint: fmt.Sprintf("/address-proposals/%d/reject", proposals[1].ID+100),
+			Method:   http.MethodPost,
+			Assert:   httputil.AssertCode(http.StatusNotFound),
+		},
+		{
+			Msg:      "approved address is created, rejected one is kept",
+			Endpoint: "/addresses",
+			Method:   http.MethodGet,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				t.Helper()
+				require.Equal(t, http.StatusOK, resp.Code)
+				var response rcommon.AllAddressesResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+				types := make(map[ethereum.Address]common.AddressType)
+				for _, addr := range response.Data {
+					types[addr.Address] = addr.Type
+				}
+				assert.Equal(t, common.PricingOperator, types[addAddress])
+				assert.Equal(t, common.Alerter, types[removeAddress])
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Msg, func(t *testing.T) { httputil.RunHTTPTestCase(t, tc, ts.r) })
+	}
+
+	// rejected proposals are not stored again
+	stored, err = tst.Propose(proposed[1:])
+	require.NoError(t, err)
+	assert.Equal(t, 0, stored)
+}
+
+func TestAddressProposalRestore(t *testing.T) {
+	var address = ethereum.HexToAddress("0x7a1c4e0b9d6f3a2e8c5b4d1f0e9a8b7c6d5e4f32")
+
+	t.Log("creating and deleting a test reserve address")
+	id, err := tst.Create(address, common.SanityOperator, "old sanity operator", time.Time{}, "")
+	require.NoError(t, err)
+	require.NoError(t, tst.Delete(id, time.Now(), ""))
+
+	deleted, err := tst.GetDeleted()
+	require.NoError(t, err)
+	var found bool
+	for _, addr := range deleted {
+		if addr.Address == address {
+			found = true
+			assert.Equal(t, id, addr.ID)
+		}
+	}
+	require.True(t, found)
+
+	_, err = tst.Propose([]common.AddressProposal{
+		{Action: common.AddressProposalAdd, Address: address, Type: common.PricingOperator, AddressID: id,
+			Description: "operator of reserve"},
+	})
+	require.NoError(t, err)
+	pending, err := tst.GetProposals(common.AddressProposalPending)
+	require.NoError(t, err)
+	var proposalID uint64
+	for _, p := range pending {
+		if p.Address == address {
+			proposalID = p.ID
+		}
+	}
+	require.NotZero(t, proposalID)
+	require.NoError(t, tst.ApproveProposal(proposalID, ""))
+
+	// the deleted address is restored instead of created again
+	restored, err := tst.Get(id)
+	require.NoError(t, err)
+	assert.True(t, restored.EffectiveUntil.IsZero())
+	assert.Equal(t, common.PricingOperator, restored.Type)
+	assert.Equal(t, "operator of reserve", restored.Description)
+	deleted, err = tst.GetDeleted()
+	require.NoError(t, err)
+	for _, addr := range deleted {
+		assert.NotEqual(t, address, addr.Address)
+	}
+}
+
 func TestMain(m *testing.M) {
 	var err error
 	tts = time.Now().UTC()
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+)
+
+type getProposalsQuery struct {
+	// Status is the status of proposals to return, default is pending. all returns proposals of any status.
+	Status string `form:"status"`
+}
+
+func (s *Server) getProposals(c *gin.Context) {
+	var query getProposalsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusBadRequest,
+			err,
+		)
+		return
+	}
+
+	var status common.AddressProposalStatus
+	switch common.AddressProposalStatus(query.Status) {
+	case "":
+		status = common.AddressProposalPending
+	case "all":
+	case common.AddressProposalPending, common.AddressProposalApproved, common.AddressProposalRejected:
+		status = common.AddressProposalStatus(query.Status)
+	default:
+		httputil.ResponseFailure(
+			c,
+			http.StatusBadRequest,
+			fmt.Errorf("invalid status: %s", query.Status),
+		)
+		return
+	}
+
+	proposals, err := s.storage.GetProposals(status)
+	if err != nil {
+		httputil.ResponseFailure(
+			c,
+			http.StatusInternalServerError,
+			err,
+		)
+		return
+	}
+	if proposals == nil {
+		proposals = []common.AddressProposal{}
+	}
+	c.JSON(http.StatusOK, proposals)
+}
+
+// decideProposal returns the handler approving or rejecting a proposal with decide.
+func (s *Server) decideProposal(decide func(id uint64, decidedBy string) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+
+		id, err := getIDParam(c)
+		if err != nil {
+			httputil.ResponseFailure(
+				c,
+				http.StatusBadRequest,
+				err,
+			)
+			return
+		}
+
+		decidedBy := changedBy(c)
+		logger.Infow("deciding address proposal", "id", id, "decided_by", decidedBy, "path", c.FullPath())
+		switch err = decide(id, decidedBy); err {
+		case nil:
+			c.Status(http.StatusNoContent)
+		case storage.ErrNotExists:
+			httputil.ResponseFailure(
+				c,
+				http.StatusNotFound,
+				err,
+			)
+		case storage.ErrProposalDecided, storage.ErrExists:
+			httputil.ResponseFailure(
+				c,
+				http.StatusConflict,
+				err,
+			)
+		default:
+			httputil.ResponseFailure(
+				c,
+				http.StatusInternalServerError,
+				err,
+			)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package http
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 // Server is the HTTP server of accounting reserve addresses service.
@@ -13,19 +17,71 @@ type Server struct {
 	r       *gin.Engine
 	host    string
 	storage storage.Interface
+
+	openAPIOptions []openapi.Option
 }
 
 // NewServer creates a new instance of Server from given parameters.
-func NewServer(sugar *zap.SugaredLogger, host string, storage storage.Interface) *Server {
+func NewServer(sugar *zap.SugaredLogger, host string, storage storage.Interface, openAPIOptions ...openapi.Option) *Server {
 	r := gin.Default()
-	return &Server{sugar: sugar, r: r, host: host, storage: storage}
+	return &Server{sugar: sugar, r: r, host: host, storage: storage, openAPIOptions: openAPIOptions}
 }
 
 func (s *Server) register() {
-	s.r.POST("/addresses", s.create)
-	s.r.GET("/addresses/:id", s.get)
-	s.r.GET("/addresses", s.getAll)
-	s.r.PUT("/addresses/:id", s.update)
+	api := openapi.NewRouter(s.r, openapi.NewSpec("reserve addresses"), s.openAPIOptions...)
+	api.POST("/addresses", openapi.Endpoint{
+		Summary:  "create reserve address",
+		Body:     createInput{},
+		Response: createResponse{},
+		Status:   http.StatusCreated,
+	}, s.create)
+	api.GET("/addresses/:id", openapi.Endpoint{
+		Summary:  "reserve address of id",
+		URI:      idParam{},
+		Response: common.ReserveAddress{},
+	}, s.get)
+	api.GET("/addresses", openapi.Endpoint{
+		Summary:  "reserve addresses effective now or at the given time",
+		Query:    getAllQuery{},
+		Response: getAllResponse{},
+	}, s.getAll)
+	api.GET("/addresses/:id/versions", openapi.Endpoint{
+		Summary:  "changes of reserve address, oldest first",
+		URI:      idParam{},
+		Response: []common.ReserveAddressVersion{},
+	}, s.getVersions)
+	api.PUT("/addresses/:id", openapi.Endpoint{
+		Summary: "update reserve address",
+		URI:     idParam{},
+		Body:    updateInput{},
+		Status:  http.StatusNoContent,
+	}, s.update)
+	api.DELETE("/addresses/:id", openapi.Endpoint{
+		Summary: "end the effective period of reserve address, it is kept for queries of earlier times",
+		URI:     idParam{},
+		Query:   deleteQuery{},
+		Status:  http.StatusNoContent,
+	}, s.delete)
+	api.POST("/addresses/:id/restore", openapi.Endpoint{
+		Summary: "clear the effective until time of deleted reserve address",
+		URI:     idParam{},
+		Status:  http.StatusNoContent,
+	}, s.restore)
+	api.GET("/address-proposals", openapi.Endpoint{
+		Summary:  "addresses found in reserve contracts proposed to add or remove",
+		Query:    getProposalsQuery{},
+		Response: []common.AddressProposal{},
+	}, s.getProposals)
+	api.POST("/address-proposals/:id/approve", openapi.Endpoint{
+		Summary: "approve a pending proposal and apply it to reserve addresses",
+		URI:     idParam{},
+		Status:  http.StatusNoContent,
+	}, s.decideProposal(s.storage.ApproveProposal))
+	api.POST("/address-proposals/:id/reject", openapi.Endpoint{
+		Summary: "reject a pending proposal, it is not proposed again",
+		URI:     idParam{},
+		Status:  http.StatusNoContent,
+	}, s.decideProposal(s.storage.RejectProposal))
 }
 
 // Run starts the HTTP server and runs in foreground until terminate by user.
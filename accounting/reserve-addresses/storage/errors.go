@@ -8,4 +8,7 @@ var (
 
 	// ErrExists is the error returned when the record is already exists in database.
 	ErrExists = errors.New("already exists")
+
+	// ErrProposalDecided is the error returned when approving or rejecting a proposal which is not pending.
+	ErrProposalDecided = errors.New("proposal is already approved or rejected")
 )
@@ -1,15 +1,39 @@
 package storage
 
 import (
+	"time"
+
 	ethereum "github.com/ethereum/go-ethereum/common"
 
 	"github.com/KyberNetwork/reserve-stats/accounting/common"
 )
 
-// Interface is the common interface of reserve addresses backend storage.
+// Interface is the common interface of reserve addresses backend storage. Every change is stored as a
+// version with the id of its author, changedBy.
 type Interface interface {
-	Create(address ethereum.Address, addressType common.AddressType, description string) (uint64, error)
+	Create(address ethereum.Address, addressType common.AddressType, description string, effectiveFrom time.Time, changedBy string) (uint64, error)
 	Get(id uint64) (*common.ReserveAddress, error)
+	// GetAll returns the addresses effective now.
 	GetAll() ([]*common.ReserveAddress, int64, error)
-	Update(id uint64, address ethereum.Address, addressType *common.AddressType, description string) error
+	// GetDeleted returns the addresses whose effective period is ended and are not created again, the last
+	// deleted one of each address.
+	GetDeleted() ([]*common.ReserveAddress, error)
+	// GetAt returns the addresses effective at the time, with the values they had at the time.
+	GetAt(at time.Time) ([]*common.ReserveAddress, int64, error)
+	Update(id uint64, address ethereum.Address, addressType *common.AddressType, description string, changedBy string) error
+	// Delete ends the effective period of the address at effectiveUntil, it is kept for queries of earlier times.
+	Delete(id uint64, effectiveUntil time.Time, changedBy string) error
+	// Restore clears the effective until time of the deleted address.
+	Restore(id uint64, changedBy string) error
+	// GetVersions returns the changes of the address, oldest first.
+	GetVersions(id uint64) ([]common.ReserveAddressVersion, error)
+
+	// Propose stores the proposals which are not pending or rejected already, it returns the number stored.
+	Propose(proposals []common.AddressProposal) (int, error)
+	// GetProposals returns the proposals of status, or all proposals if status is empty.
+	GetProposals(status common.AddressProposalStatus) ([]common.AddressProposal, error)
+	// ApproveProposal applies the pending proposal to addresses and marks it approved.
+	ApproveProposal(id uint64, decidedBy string) error
+	// RejectProposal marks the pending proposal rejected.
+	RejectProposal(id uint64, decidedBy string) error
 }
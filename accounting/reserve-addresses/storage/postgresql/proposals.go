@@ -0,0 +1,200 @@
+package postgresql
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
+)
+
+const selectProposalStmt = `SELECT id, action, address, type, address_id, description, status, proposed_at, decided_by, decided_at
+FROM address_proposals`
+
+// Propose stores the proposals which are not pending or rejected already, it returns the number stored.
+func (s *Storage) Propose(proposals []common.AddressProposal) (stored int, err error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"proposals", len(proposals),
+		)
+		insertStmt = `INSERT INTO "address_proposals" (action, address, type, address_id, description, proposed_at)
+SELECT $1, $2, $3, $4::INTEGER, $5, $6::TIMESTAMP
+WHERE NOT EXISTS(SELECT 1
+                 FROM address_proposals
+                 WHERE action = $1
+                   AND address = $2
+                   AND status IN ($7, $8))`
+	)
+	logger.Debug("storing address proposals")
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+
+	now := time.Now().UTC()
+	for _, p := range proposals {
+		var addressID interface{}
+		if p.AddressID != 0 {
+			addressID = p.AddressID
+		}
+		res, err := tx.Exec(insertStmt,
+			string(p.Action),
+			p.Address.String(),
+			p.Type.String(),
+			addressID,
+			p.Description,
+			now,
+			string(common.AddressProposalPending),
+			string(common.AddressProposalRejected),
+		)
+		if err != nil {
+			return 0, err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		stored += int(rows)
+	}
+	return stored, nil
+}
+
+// GetProposals returns the proposals of status, or all proposals if status is empty.
+func (s *Storage) GetProposals(status common.AddressProposalStatus) ([]common.AddressProposal, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"status", status,
+		)
+		stored  []AddressProposal
+		results []common.AddressProposal
+	)
+	logger.Debug("querying address proposals")
+	queryStmt := selectProposalStmt + ` WHERE $1 = '' OR status = $1 ORDER BY id`
+	if err := s.db.Select(&stored, queryStmt, string(status)); err != nil {
+		return nil, err
+	}
+	for i := range stored {
+		result, err := stored[i].Common()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ApproveProposal applies the pending proposal to addresses and marks it approved. Add proposals create
+// the address with unbounded effective period as it is in use since set in contracts, or restore the deleted
+// address of proposal, remove proposals delete the address effective until now.
+func (s *Storage) ApproveProposal(id uint64, decidedBy string) (err error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"id", id,
+			"decided_by", decidedBy,
+		)
+		now = time.Now().UTC()
+	)
+	logger.Debug("approving address proposal")
+
+	proposal, err := s.getProposal(id)
+	if err != nil {
+		return err
+	}
+	// resolving contract creation time is done before transaction as it queries etherscan
+	var params []interface{}
+	if proposal.Action == common.AddressProposalAdd && proposal.AddressID == 0 {
+		params, err = s.createParams(logger, proposal.Address, proposal.Type, proposal.Description, time.Time{})
+		if err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+
+	if err = decideTx(tx, id, common.AddressProposalApproved, decidedBy, now); err != nil {
+		return err
+	}
+	switch proposal.Action {
+	case common.AddressProposalAdd:
+		if proposal.AddressID != 0 {
+			err = restoreTx(tx, proposal.AddressID, proposal.Type, proposal.Description, decidedBy)
+			break
+		}
+		_, err = createTx(tx, params, decidedBy)
+	case common.AddressProposalRemove:
+		err = deleteTx(tx, proposal.AddressID, now, decidedBy)
+	}
+	return err
+}
+
+// RejectProposal marks the pending proposal rejected.
+func (s *Storage) RejectProposal(id uint64, decidedBy string) (err error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"id", id,
+			"decided_by", decidedBy,
+		)
+	)
+	logger.Debug("rejecting address proposal")
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+
+	return decideTx(tx, id, common.AddressProposalRejected, decidedBy, time.Now().UTC())
+}
+
+func (s *Storage) getProposal(id uint64) (common.AddressProposal, error) {
+	var stored AddressProposal
+	if err := s.db.Get(&stored, selectProposalStmt+` WHERE id = $1`, id); err == sql.ErrNoRows {
+		return common.AddressProposal{}, storage.ErrNotExists
+	} else if err != nil {
+		return common.AddressProposal{}, err
+	}
+	return stored.Common()
+}
+
+// decideTx sets the status of the pending proposal in transaction.
+func decideTx(tx *sqlx.Tx, id uint64, status common.AddressProposalStatus, decidedBy string, decidedAt time.Time) error {
+	const updateStmt = `UPDATE address_proposals
+SET status     = $1,
+    decided_by = $2,
+    decided_at = $3
+WHERE id = $4
+  AND status = $5`
+	res, err := tx.Exec(updateStmt, string(status), decidedBy, decidedAt, id, string(common.AddressProposalPending))
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		var exists bool
+		if err = tx.Get(&exists, `SELECT EXISTS(SELECT 1 FROM address_proposals WHERE id = $1)`, id); err != nil {
+			return err
+		}
+		if !exists {
+			return storage.ErrNotExists
+		}
+		return storage.ErrProposalDecided
+	}
+	return nil
+}
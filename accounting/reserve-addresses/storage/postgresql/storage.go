@@ -2,6 +2,9 @@ package postgresql
 
 import (
 	"database/sql"
+	"encoding/json"
+	"reflect"
+	"time"
 
 	ethereum "github.com/ethereum/go-ethereum/common"
 	"github.com/jmoiron/sqlx"
@@ -12,6 +15,7 @@ import (
 	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/storage"
 	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
 )
 
 // Storage implements accounting reserve addresses storage.Interface with PostgreSQL as storage engine.
@@ -64,7 +68,48 @@ CREATE TRIGGER version_trigger
     AFTER INSERT OR UPDATE
     ON addresses
     FOR EACH ROW
-EXECUTE PROCEDURE inc_version();`
+EXECUTE PROCEDURE inc_version();
+ALTER TABLE "addresses" ADD COLUMN IF NOT EXISTS effective_from TIMESTAMP;
+ALTER TABLE "addresses" ADD COLUMN IF NOT EXISTS effective_until TIMESTAMP;
+--create history table, every change of an address is stored as a version
+CREATE TABLE IF NOT EXISTS "addresses_history"
+(
+  id          SERIAL PRIMARY KEY,
+  address_id  INTEGER   NOT NULL REFERENCES addresses (id),
+  action      TEXT      NOT NULL,
+  changed_by  TEXT      NOT NULL DEFAULT '',
+  changed_at  TIMESTAMP NOT NULL,
+  before      JSONB,
+  after       JSONB     NOT NULL
+);
+CREATE INDEX IF NOT EXISTS addresses_history_address_id_idx ON addresses_history (address_id);
+--addresses created before history is recorded get their current values as the first version
+INSERT INTO "addresses_history" (address_id, action, changed_at, after)
+SELECT id,
+       'create',
+       last_updated,
+       json_build_object('id', id, 'address', address, 'type', type, 'description', COALESCE(description, ''),
+                         'timestamp', (EXTRACT(EPOCH FROM timestamp) * 1000)::BIGINT)
+FROM addresses a
+WHERE NOT EXISTS(SELECT 1 FROM addresses_history h WHERE h.address_id = a.id);
+--create proposals table, addresses found in reserve contracts are proposed for review
+CREATE TABLE IF NOT EXISTS "address_proposals"
+(
+  id          SERIAL PRIMARY KEY,
+  action      TEXT      NOT NULL,
+  address     TEXT      NOT NULL,
+  type        TEXT      NOT NULL,
+  address_id  INTEGER REFERENCES addresses (id),
+  description TEXT      NOT NULL DEFAULT '',
+  status      TEXT      NOT NULL DEFAULT 'pending',
+  proposed_at TIMESTAMP NOT NULL,
+  decided_by  TEXT      NOT NULL DEFAULT '',
+  decided_at  TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS address_proposals_status_idx ON address_proposals (status);
+--a deleted address could be created again, only addresses that are not deleted are unique
+ALTER TABLE "addresses" DROP CONSTRAINT IF EXISTS addresses_address_key;
+CREATE UNIQUE INDEX IF NOT EXISTS addresses_address_active_idx ON addresses (address) WHERE effective_until IS NULL;`
 
 	logger.Debugw("initializing database schema")
 	if _, err := db.Exec(schemaFmt); err != nil {
@@ -74,21 +119,79 @@ EXECUTE PROCEDURE inc_version();`
 	return &Storage{sugar: sugar, db: db, resolv: resolv}, nil
 }
 
+const selectAddressStmt = `SELECT id, address, type, description, timestamp, effective_from, effective_until
+FROM addresses`
+
+// nullTime returns nil for zero time to store it as NULL.
+func nullTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC()
+}
+
+// getTx returns the stored address of id in transaction, the row is locked for update.
+func getTx(tx *sqlx.Tx, id uint64) (*common.ReserveAddress, error) {
+	var addr ReserveAddress
+	if err := tx.Get(&addr, selectAddressStmt+` WHERE id = $1 FOR UPDATE`, id); err == sql.ErrNoRows {
+		return nil, storage.ErrNotExists
+	} else if err != nil {
+		return nil, err
+	}
+	return addr.Common()
+}
+
+// insertVersion stores a change of address in transaction.
+func insertVersion(tx *sqlx.Tx, action common.ReserveAddressAction, changedBy string, before, after *common.ReserveAddress) error {
+	const insertStmt = `INSERT INTO "addresses_history" (address_id, action, changed_by, changed_at, before, after)
+VALUES ($1, $2, $3, $4, $5, $6)`
+	var beforeData []byte
+	if before != nil {
+		data, err := json.Marshal(before)
+		if err != nil {
+			return err
+		}
+		beforeData = data
+	}
+	afterData, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(insertStmt, after.ID, string(action), changedBy, time.Now().UTC(), beforeData, afterData)
+	return err
+}
+
 // Create creates a new address and store to database.
-func (s *Storage) Create(address ethereum.Address, addressType common.AddressType, description string) (uint64, error) {
+func (s *Storage) Create(address ethereum.Address, addressType common.AddressType, description string, effectiveFrom time.Time,
+	changedBy string) (id uint64, err error) {
 	var (
 		logger = s.sugar.With(
 			"func", caller.GetCurrentFunctionName(),
 			"address", address.String(),
 			"type", addressType.String(),
 			"description", description,
+			"changed_by", changedBy,
 		)
-		insertFmt = `INSERT INTO "addresses" (address, type, description, timestamp, last_updated)
-VALUES ($1, $2, $3, $4, NOW()) RETURNING id`
-		id uint64
 	)
 	logger.Debugw("creating new address")
 
+	params, err := s.createParams(logger, address, addressType, description, effectiveFrom)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+
+	return createTx(tx, params, changedBy)
+}
+
+// createParams returns the parameters of insert statement of createTx.
+func (s *Storage) createParams(logger *zap.SugaredLogger, address ethereum.Address, addressType common.AddressType,
+	description string, effectiveFrom time.Time) ([]interface{}, error) {
 	params := []interface{}{
 		address.String(),
 		addressType.String(),
@@ -103,25 +206,115 @@ VALUES ($1, $2, $3, $4, NOW()) RETURNING id`
 	case nil:
 		params = append(params, ts.UTC())
 	default:
-		return 0, err
+		return nil, err
 	}
+	return append(params, nullTime(effectiveFrom)), nil
+}
 
-	if err = s.db.Get(&id, insertFmt, params...); err != nil {
-		// check if return error is a known pq error
-		pErr, ok := err.(*pq.Error)
-		if !ok {
-			return 0, err
-		}
+// uniqueViolation returns ErrExists if err is an unique violation of the address.
+func uniqueViolation(err error) error {
+	// https://www.postgresql.org/docs/9.3/errcodes-appendix.html
+	// 23505: unique_violation
+	if pErr, ok := err.(*pq.Error); ok && pErr.Code == "23505" {
+		return storage.ErrExists
+	}
+	return err
+}
 
-		// https://www.postgresql.org/docs/9.3/errcodes-appendix.html
-		// 23505: unique_violation
-		if pErr.Code == "23505" {
-			return 0, storage.ErrExists
-		}
+// restoreTx clears the effective until time of the deleted address in transaction, setting its type and
+// description, and stores the change as a version. It returns ErrExists if another row of the address is
+// still effective.
+func restoreTx(tx *sqlx.Tx, id uint64, addressType common.AddressType, description string, changedBy string) error {
+	const (
+		existsStmt = `SELECT EXISTS(SELECT 1
+              FROM addresses
+              WHERE address = $1
+                AND id <> $2
+                AND (effective_until IS NULL OR effective_until > NOW()))`
+		updateStmt = `UPDATE addresses
+SET type            = $1,
+    description     = $2,
+    effective_until = NULL,
+    last_updated    = NOW()
+WHERE id = $3`
+	)
+	before, err := getTx(tx, id)
+	if err != nil {
+		return err
+	}
+	var exists bool
+	if err = tx.Get(&exists, existsStmt, before.Address.String(), id); err != nil {
+		return err
+	}
+	if exists {
+		return storage.ErrExists
+	}
+	if _, err = tx.Exec(updateStmt, addressType.String(), description, id); err != nil {
+		return uniqueViolation(err)
+	}
+	after, err := getTx(tx, id)
+	if err != nil {
+		return err
+	}
+	return insertVersion(tx, common.ReserveAddressUpdated, changedBy, before, after)
+}
+
+// Restore clears the effective until time of the deleted address, it returns ErrExists if the address is
+// created again and still effective.
+func (s *Storage) Restore(id uint64, changedBy string) (err error) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName(),
+			"id", id,
+			"changed_by", changedBy,
+		)
+	)
+	logger.Debug("restoring reserve address")
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
 
-		return 0, pErr
+	addr, err := getTx(tx, id)
+	if err != nil {
+		return err
 	}
-	return id, err
+	return restoreTx(tx, id, addr.Type, addr.Description, changedBy)
+}
+
+// createTx stores a new address in transaction with its first version. A deleted address is created as a new
+// address, the deleted one is kept for the queries of its effective period. It returns ErrExists if a row of
+// the address is still effective at the effective from time of the new one, or now if it is not given.
+func createTx(tx *sqlx.Tx, params []interface{}, changedBy string) (uint64, error) {
+	const (
+		existsStmt = `SELECT EXISTS(SELECT 1
+              FROM addresses
+              WHERE address = $1
+                AND (effective_until IS NULL OR effective_until > COALESCE($2::TIMESTAMP, NOW())))`
+		insertFmt = `INSERT INTO "addresses" (address, type, description, timestamp, effective_from, last_updated)
+VALUES ($1, $2, $3, $4, $5, NOW()) RETURNING id`
+	)
+	var (
+		id     uint64
+		exists bool
+	)
+	if err := tx.Get(&exists, existsStmt, params[0], params[4]); err != nil {
+		return 0, err
+	}
+	if exists {
+		return 0, storage.ErrExists
+	}
+	if err := tx.Get(&id, insertFmt, params...); err != nil {
+		return 0, uniqueViolation(err)
+	}
+	after, err := getTx(tx, id)
+	if err != nil {
+		return 0, err
+	}
+	if err = insertVersion(tx, common.ReserveAddressCreated, changedBy, nil, after); err != nil {
+		return 0, err
+	}
+	return id, nil
 }
 
 // Get returns the stored reserve address with matching id.
@@ -131,9 +324,7 @@ func (s *Storage) Get(id uint64) (*common.ReserveAddress, error) {
 			"id", id,
 		)
 		addr      = &ReserveAddress{}
-		queryStmt = `SELECT id, address, type, description, timestamp
-FROM addresses
-WHERE id = $1`
+		queryStmt = selectAddressStmt + ` WHERE id = $1`
 	)
 
 	if err := s.db.Get(addr, queryStmt, id); err == sql.ErrNoRows {
@@ -150,21 +341,18 @@ WHERE id = $1`
 	return ra, nil
 }
 
-// GetAll returns all stored reserve addresses in database.
+// GetAll returns all reserve addresses effective now.
 // It returns no error if there is nothing in database.
 func (s *Storage) GetAll() ([]*common.ReserveAddress, int64, error) {
 	var (
 		logger    = s.sugar.With("func", caller.GetCurrentFunctionName())
 		stored    []*ReserveAddress
 		results   []*common.ReserveAddress
-		queryStmt = `SELECT id, address, type, description, timestamp
-FROM addresses`
-		queryVersionStmt = `SELECT version FROM addresses_version WHERE id = 1`
-		version          int64
+		queryStmt = selectAddressStmt + ` WHERE effective_until IS NULL OR effective_until > $1 ORDER BY id`
 	)
 
 	logger.Debug("querying all stored reserve addresses")
-	if err := s.db.Select(&stored, queryStmt); err != nil {
+	if err := s.db.Select(&stored, queryStmt, time.Now().UTC()); err != nil {
 		return nil, 0, err
 	}
 
@@ -175,21 +363,125 @@ FROM addresses`
 		}
 		results = append(results, result)
 	}
-	if len(results) > 0 {
-		if err := s.db.Get(&version, queryVersionStmt); err != nil {
+	version, err := s.version(len(results))
+	if err != nil {
+		return nil, 0, err
+	}
+	return results, version, nil
+}
+
+// GetDeleted returns the last deleted version of the addresses which are not effective now.
+func (s *Storage) GetDeleted() ([]*common.ReserveAddress, error) {
+	var (
+		logger    = s.sugar.With("func", caller.GetCurrentFunctionName())
+		stored    []*ReserveAddress
+		results   []*common.ReserveAddress
+		queryStmt = `SELECT *
+FROM (SELECT DISTINCT ON (address) id, address, type, description, timestamp, effective_from, effective_until
+      FROM addresses a
+      WHERE effective_until <= $1
+        AND NOT EXISTS(SELECT 1
+                       FROM addresses e
+                       WHERE e.address = a.address
+                         AND (e.effective_until IS NULL OR e.effective_until > $1))
+      ORDER BY address, effective_until DESC, id DESC) deleted
+ORDER BY id`
+	)
+
+	logger.Debug("querying deleted reserve addresses")
+	if err := s.db.Select(&stored, queryStmt, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	for _, r := range stored {
+		result, err := r.Common()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// version returns the version of the address set, it is increased by every change.
+func (s *Storage) version(count int) (int64, error) {
+	const queryVersionStmt = `SELECT version FROM addresses_version WHERE id = 1`
+	var version int64
+	if count == 0 {
+		return 0, nil
+	}
+	if err := s.db.Get(&version, queryVersionStmt); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
+
+// GetAt returns the reserve addresses effective at the time. Address values are of the last version at
+// the time, or the first version for addresses stored later. The effective period is always the current
+// one, so deletes with an earlier effective until time also apply to older queries.
+func (s *Storage) GetAt(at time.Time) ([]*common.ReserveAddress, int64, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"at", at,
+		)
+		stored   []*ReserveAddress
+		versions []ReserveAddressVersion
+		results  []*common.ReserveAddress
+		values   = make(map[uint64]*common.ReserveAddress)
+	)
+	at = at.UTC()
+
+	logger.Debug("querying reserve addresses effective at time")
+	const effectiveCond = `(effective_from IS NULL OR effective_from <= $1)
+AND (effective_until IS NULL OR effective_until > $1)`
+	if err := s.db.Select(&stored, selectAddressStmt+` WHERE `+effectiveCond+` ORDER BY id`, at); err != nil {
+		return nil, 0, err
+	}
+	// the version of an address at the time is its last change before the time, or its first version
+	const queryVersionsStmt = `SELECT DISTINCT ON (address_id) id, address_id, action, changed_by, changed_at, before, after
+FROM addresses_history
+WHERE address_id IN (SELECT id FROM addresses WHERE ` + effectiveCond + `)
+ORDER BY address_id, changed_at > $1, CASE WHEN changed_at <= $1 THEN -id ELSE id END`
+	if err := s.db.Select(&versions, queryVersionsStmt, at); err != nil {
+		return nil, 0, err
+	}
+	for i := range versions {
+		version, err := versions[i].Common()
+		if err != nil {
 			return nil, 0, err
 		}
+		values[versions[i].AddressID] = version.After
+	}
+
+	for _, r := range stored {
+		result, err := r.Common()
+		if err != nil {
+			return nil, 0, err
+		}
+		if value, ok := values[result.ID]; ok {
+			result.Address = value.Address
+			result.Type = value.Type
+			result.Description = value.Description
+			result.Timestamp = value.Timestamp
+		}
+		results = append(results, result)
+	}
+	version, err := s.version(len(results))
+	if err != nil {
+		return nil, 0, err
 	}
 	return results, version, nil
 }
 
 // Update updates the reserve address with given information. If given data is zero, it won't be updated to database.
-func (s *Storage) Update(id uint64, address ethereum.Address, addressType *common.AddressType, description string) error {
+// The effective period is not changed, deleted addresses are restored by Restore.
+func (s *Storage) Update(id uint64, address ethereum.Address, addressType *common.AddressType, description string, changedBy string) (err error) {
 	var (
 		logger = s.sugar.With("func", caller.GetCurrentFunctionName(),
 			"id", id,
 			"address", address.String(),
 			"description", description,
+			"changed_by", changedBy,
 		)
 		queryStmt = `UPDATE addresses
 SET address     = COALESCE($1, address),
@@ -238,12 +530,92 @@ WHERE id = $5 RETURNING id;`
 	params = append(params, id)
 
 	logger.Debug("updating reserve address record in database")
+	tx, err := s.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+
+	before, err := getTx(tx, id)
+	if err != nil {
+		return err
+	}
 	var updatedID uint64
-	if err = s.db.Get(&updatedID, queryStmt, params...); err == sql.ErrNoRows {
+	if err = tx.Get(&updatedID, queryStmt, params...); err == sql.ErrNoRows {
 		return storage.ErrNotExists
 	} else if err != nil {
+		return uniqueViolation(err)
+	}
+	after, err := getTx(tx, id)
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(before, after) {
+		return nil
+	}
+	return insertVersion(tx, common.ReserveAddressUpdated, changedBy, before, after)
+}
+
+// Delete sets the effective until time of the address, the address is kept for the queries of earlier times.
+func (s *Storage) Delete(id uint64, effectiveUntil time.Time, changedBy string) (err error) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName(),
+			"id", id,
+			"effective_until", effectiveUntil,
+			"changed_by", changedBy,
+		)
+	)
+	logger.Debug("deleting reserve address")
+	tx, err := s.db.Beginx()
+	if err != nil {
 		return err
 	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
 
-	return nil
+	return deleteTx(tx, id, effectiveUntil, changedBy)
+}
+
+// deleteTx sets the effective until time of the address in transaction.
+func deleteTx(tx *sqlx.Tx, id uint64, effectiveUntil time.Time, changedBy string) error {
+	const updateStmt = `UPDATE addresses SET effective_until = $1, last_updated = NOW() WHERE id = $2`
+	before, err := getTx(tx, id)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(updateStmt, effectiveUntil.UTC(), id); err != nil {
+		return err
+	}
+	after, err := getTx(tx, id)
+	if err != nil {
+		return err
+	}
+	return insertVersion(tx, common.ReserveAddressDeleted, changedBy, before, after)
+}
+
+// GetVersions returns the stored changes of the address, oldest first.
+func (s *Storage) GetVersions(id uint64) ([]common.ReserveAddressVersion, error) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName(),
+			"id", id,
+		)
+		stored  []ReserveAddressVersion
+		results []common.ReserveAddressVersion
+	)
+	if _, err := s.Get(id); err != nil {
+		return nil, err
+	}
+	const queryStmt = `SELECT id, address_id, action, changed_by, changed_at, before, after
+FROM addresses_history WHERE address_id = $1 ORDER BY id`
+	logger.Debug("querying reserve address versions")
+	if err := s.db.Select(&stored, queryStmt, id); err != nil {
+		return nil, err
+	}
+	for i := range stored {
+		version, err := stored[i].Common()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, version)
+	}
+	return results, nil
 }
@@ -1,7 +1,10 @@
 package postgresql
 
 import (
+	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	ethereum "github.com/ethereum/go-ethereum/common"
 	"github.com/lib/pq"
@@ -16,6 +19,9 @@ type ReserveAddress struct {
 	Type        string      `json:"type" db:"type"`
 	Description string      `json:"description" db:"description"`
 	Timestamp   pq.NullTime `json:"timestamp" db:"timestamp"`
+
+	EffectiveFrom  pq.NullTime `json:"effective_from" db:"effective_from"`
+	EffectiveUntil pq.NullTime `json:"effective_until" db:"effective_until"`
 }
 
 // Common converts the database presentation of ReserveAddress to common type.
@@ -31,5 +37,75 @@ func (ra *ReserveAddress) Common() (*common.ReserveAddress, error) {
 		Type:        addressType,
 		Description: ra.Description,
 		Timestamp:   ra.Timestamp.Time,
+
+		EffectiveFrom:  ra.EffectiveFrom.Time,
+		EffectiveUntil: ra.EffectiveUntil.Time,
+	}, nil
+}
+
+// ReserveAddressVersion represents a row of addresses_history table.
+type ReserveAddressVersion struct {
+	ID        uint64    `db:"id"`
+	AddressID uint64    `db:"address_id"`
+	Action    string    `db:"action"`
+	ChangedBy string    `db:"changed_by"`
+	ChangedAt time.Time `db:"changed_at"`
+	Before    []byte    `db:"before"`
+	After     []byte    `db:"after"`
+}
+
+// Common converts the database presentation of ReserveAddressVersion to common type.
+func (v *ReserveAddressVersion) Common() (common.ReserveAddressVersion, error) {
+	result := common.ReserveAddressVersion{
+		Version:   v.ID,
+		AddressID: v.AddressID,
+		Action:    common.ReserveAddressAction(v.Action),
+		ChangedBy: v.ChangedBy,
+		ChangedAt: v.ChangedAt,
+	}
+	if len(v.Before) != 0 {
+		result.Before = &common.ReserveAddress{}
+		if err := json.Unmarshal(v.Before, result.Before); err != nil {
+			return common.ReserveAddressVersion{}, err
+		}
+	}
+	result.After = &common.ReserveAddress{}
+	if err := json.Unmarshal(v.After, result.After); err != nil {
+		return common.ReserveAddressVersion{}, err
+	}
+	return result, nil
+}
+
+// AddressProposal represents a row of address_proposals table.
+type AddressProposal struct {
+	ID          uint64        `db:"id"`
+	Action      string        `db:"action"`
+	Address     string        `db:"address"`
+	Type        string        `db:"type"`
+	AddressID   sql.NullInt64 `db:"address_id"`
+	Description string        `db:"description"`
+	Status      string        `db:"status"`
+	ProposedAt  time.Time     `db:"proposed_at"`
+	DecidedBy   string        `db:"decided_by"`
+	DecidedAt   pq.NullTime   `db:"decided_at"`
+}
+
+// Common converts the database presentation of AddressProposal to common type.
+func (p *AddressProposal) Common() (common.AddressProposal, error) {
+	addressType, ok := common.IsValidAddressType(p.Type)
+	if !ok {
+		return common.AddressProposal{}, fmt.Errorf("unknown type: %s", p.Type)
+	}
+	return common.AddressProposal{
+		ID:          p.ID,
+		Action:      common.AddressProposalAction(p.Action),
+		Address:     ethereum.HexToAddress(p.Address),
+		Type:        addressType,
+		AddressID:   uint64(p.AddressID.Int64),
+		Description: p.Description,
+		Status:      common.AddressProposalStatus(p.Status),
+		ProposedAt:  p.ProposedAt,
+		DecidedBy:   p.DecidedBy,
+		DecidedAt:   p.DecidedAt.Time,
 	}, nil
 }
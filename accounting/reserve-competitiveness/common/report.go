@@ -0,0 +1,113 @@
+package common
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	bps       = 10000
+	pairQuote = "ETH-"
+)
+
+// TokenFromPair returns token symbol of a reserve rates pair, for example: ETH-KNC --> KNC.
+func TokenFromPair(pair string) string {
+	return strings.TrimPrefix(pair, pairQuote)
+}
+
+type bucket struct {
+	marketValue, marketAmount float64
+	buyValue, buyAmount       float64
+	sellValue, sellAmount     float64
+	trades                    int
+}
+
+func (b *bucket) spreadPoint(ts time.Time) SpreadPoint {
+	point := SpreadPoint{
+		Timestamp:   ts,
+		MarketPrice: b.marketValue / b.marketAmount,
+		Trades:      b.trades,
+	}
+	if b.buyAmount != 0 {
+		point.BuyPrice = b.buyValue / b.buyAmount
+		point.BuySpreadBps = (point.BuyPrice - point.MarketPrice) / point.MarketPrice * bps
+	}
+	if b.sellAmount != 0 {
+		point.SellPrice = b.sellValue / b.sellAmount
+		point.SellSpreadBps = (point.MarketPrice - point.SellPrice) / point.MarketPrice * bps
+	}
+	return point
+}
+
+// activeRate returns the rate that is active at given time, rates must be sorted by timestamp.
+func activeRate(rates []RatePoint, ts time.Time) (RatePoint, bool) {
+	i := sort.Search(len(rates), func(i int) bool {
+		return rates[i].Timestamp.After(ts)
+	})
+	if i == 0 {
+		return RatePoint{}, false
+	}
+	return rates[i-1], true
+}
+
+// BuildReport compares the reserves rates with the market trades of the same token at the time each trade
+// happened and aggregates the result in buckets of given interval.
+// rates is grouped by reserve then pair, trades is grouped by token symbol.
+func BuildReport(rates map[string]map[string][]RatePoint, trades map[string][]MarketTrade, interval time.Duration) Report {
+	var report = make(Report)
+	for reserve, pairs := range rates {
+		for pair, points := range pairs {
+			var (
+				token   = TokenFromPair(pair)
+				buckets = make(map[time.Time]*bucket)
+			)
+			sort.Slice(points, func(i, j int) bool {
+				return points[i].Timestamp.Before(points[j].Timestamp)
+			})
+			for _, trade := range trades[token] {
+				if trade.Price <= 0 || trade.Amount <= 0 {
+					continue
+				}
+				rate, ok := activeRate(points, trade.Timestamp)
+				if !ok || (rate.BuyRate == 0 && rate.SellRate == 0) {
+					// reserve is not quoting this token at the time
+					continue
+				}
+				ts := trade.Timestamp.Truncate(interval)
+				b, ok := buckets[ts]
+				if !ok {
+					b = &bucket{}
+					buckets[ts] = b
+				}
+				b.trades++
+				b.marketValue += trade.Price * trade.Amount
+				b.marketAmount += trade.Amount
+				if rate.BuyRate != 0 {
+					b.buyValue += trade.Amount / rate.BuyRate
+					b.buyAmount += trade.Amount
+				}
+				if rate.SellRate != 0 {
+					b.sellValue += trade.Amount * rate.SellRate
+					b.sellAmount += trade.Amount
+				}
+			}
+			if len(buckets) == 0 {
+				continue
+			}
+
+			var spreads []SpreadPoint
+			for ts, b := range buckets {
+				spreads = append(spreads, b.spreadPoint(ts))
+			}
+			sort.Slice(spreads, func(i, j int) bool {
+				return spreads[i].Timestamp.Before(spreads[j].Timestamp)
+			})
+			if _, ok := report[reserve]; !ok {
+				report[reserve] = make(map[string][]SpreadPoint)
+			}
+			report[reserve][token] = spreads
+		}
+	}
+	return report
+}
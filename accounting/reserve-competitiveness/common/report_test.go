@@ -0,0 +1,63 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildReport(t *testing.T) {
+	const (
+		reserve = "0x63825c174ab367968EC60f061753D3bbD36A0D8F"
+		delta   = 0.0001
+	)
+	var (
+		start = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+		rates = map[string]map[string][]RatePoint{
+			reserve: {
+				"ETH-KNC": {
+					// rates are not sorted on purpose
+					{Timestamp: start.Add(90 * time.Minute), BuyRate: 0, SellRate: 0},
+					{Timestamp: start.Add(-time.Hour), BuyRate: 990, SellRate: 0.000995},
+				},
+				"ETH-ZRX": {
+					{Timestamp: start.Add(time.Hour), BuyRate: 500, SellRate: 0.0019},
+				},
+			},
+		}
+		trades = map[string][]MarketTrade{
+			"KNC": {
+				{Timestamp: start.Add(10 * time.Minute), Price: 0.001, Amount: 100},
+				{Timestamp: start.Add(20 * time.Minute), Price: 0.0011, Amount: 100},
+				{Timestamp: start.Add(70 * time.Minute), Price: 0.001, Amount: 50},
+				// reserve stopped quoting KNC
+				{Timestamp: start.Add(100 * time.Minute), Price: 0.001, Amount: 50},
+			},
+			"ZRX": {
+				// ZRX rates is not available at this time
+				{Timestamp: start.Add(10 * time.Minute), Price: 0.002, Amount: 10},
+			},
+		}
+	)
+
+	report := BuildReport(rates, trades, time.Hour)
+	require.Len(t, report, 1)
+	require.Len(t, report[reserve], 1)
+	points := report[reserve]["KNC"]
+	require.Len(t, points, 2)
+
+	assert.Equal(t, start, points[0].Timestamp)
+	assert.Equal(t, 2, points[0].Trades)
+	assert.InDelta(t, 0.00105, points[0].MarketPrice, delta)
+	assert.InDelta(t, 1.0/990, points[0].BuyPrice, delta)
+	// reserve sells KNC at 0.00101 while market price is 0.00105, it is cheaper than market
+	assert.InDelta(t, (1.0/990-0.00105)/0.00105*10000, points[0].BuySpreadBps, delta)
+	assert.InDelta(t, (0.00105-0.000995)/0.00105*10000, points[0].SellSpreadBps, delta)
+
+	assert.Equal(t, start.Add(time.Hour), points[1].Timestamp)
+	assert.Equal(t, 1, points[1].Trades)
+	assert.InDelta(t, (1.0/990-0.001)/0.001*10000, points[1].BuySpreadBps, delta)
+	assert.InDelta(t, 50, points[1].SellSpreadBps, delta)
+}
@@ -0,0 +1,57 @@
+package common
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// RatePoint is the rates quoted by a reserve for a token from Timestamp until the next RatePoint.
+type RatePoint struct {
+	Timestamp time.Time
+	// BuyRate is the amount of token received for 1 ETH.
+	BuyRate float64
+	// SellRate is the amount of ETH received for 1 token.
+	SellRate float64
+}
+
+// MarketTrade is a trade of a token against ETH in a centralized exchange.
+type MarketTrade struct {
+	Exchange  string
+	Timestamp time.Time
+	// Price is in ETH per token.
+	Price float64
+	// Amount is in token.
+	Amount float64
+}
+
+// SpreadPoint is the comparison of reserve quoted prices and market prices in a time bucket.
+// Spreads are in basis points, positive spreads mean reserve quotes worse than the market.
+type SpreadPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	// MarketPrice is the volume weighted average price of CEX trades in ETH per token.
+	MarketPrice float64 `json:"market_price"`
+	// BuyPrice is the average price reserve sells token at in ETH per token.
+	BuyPrice     float64 `json:"buy_price"`
+	BuySpreadBps float64 `json:"buy_spread_bps"`
+	// SellPrice is the average price reserve buys token at in ETH per token.
+	SellPrice     float64 `json:"sell_price"`
+	SellSpreadBps float64 `json:"sell_spread_bps"`
+	Trades        int     `json:"trades"`
+}
+
+// MarshalJSON implements custom JSON marshaler for SpreadPoint to format timestamp in unix millis instead of RFC3339.
+func (sp SpreadPoint) MarshalJSON() ([]byte, error) {
+	type AliasSpreadPoint SpreadPoint
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasSpreadPoint
+	}{
+		AliasSpreadPoint: (AliasSpreadPoint)(sp),
+		Timestamp:        timeutil.TimeToTimestampMs(sp.Timestamp),
+	})
+}
+
+// Report is spread points of reserves grouped by reserve address then token symbol.
+type Report map[string]map[string][]SpreadPoint
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-competitiveness/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-competitiveness/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	_ "github.com/KyberNetwork/reserve-stats/lib/httputil/validators" // import custom validator functions
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+const (
+	maxTimeFrame     = time.Hour * 24 * 30 // 30 days
+	defaultTimeFrame = time.Hour * 24      // 1 day
+	defaultInterval  = time.Hour
+	minInterval      = time.Minute
+)
+
+var errIntervalTooSmall = fmt.Errorf("interval must not be smaller than %s", minInterval)
+
+// Server is the HTTP server of reserve competitiveness report.
+type Server struct {
+	sugar  *zap.SugaredLogger
+	r      *gin.Engine
+	host   string
+	rates  storage.RatesInterface
+	market storage.MarketInterface
+
+	openAPIOptions []openapi.Option
+}
+
+// NewServer creates a new instance of Server.
+func NewServer(sugar *zap.SugaredLogger, host string, rates storage.RatesInterface, market storage.MarketInterface, openAPIOptions ...openapi.Option) *Server {
+	r := gin.Default()
+	return &Server{
+		sugar:  sugar,
+		r:      r,
+		host:   host,
+		rates:  rates,
+		market: market,
+
+		openAPIOptions: openAPIOptions,
+	}
+}
+
+type competitivenessQuery struct {
+	httputil.TimeRangeQuery
+	Reserves []string      `form:"reserve"`
+	Tokens   []string      `form:"token"`
+	Interval time.Duration `form:"interval"`
+}
+
+func (s *Server) getCompetitiveness(c *gin.Context) {
+	var (
+		logger   = s.sugar.With("func", caller.GetCurrentFunctionName())
+		query    competitivenessQuery
+		reserves []string
+	)
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	from, to, err := query.Validate(
+		httputil.TimeRangeQueryWithMaxTimeFrame(maxTimeFrame),
+		httputil.TimeRangeQueryWithDefaultTimeFrame(defaultTimeFrame),
+	)
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	if query.Interval == 0 {
+		query.Interval = defaultInterval
+	}
+	if query.Interval < minInterval {
+		httputil.ResponseFailure(c, http.StatusBadRequest, errIntervalTooSmall)
+		return
+	}
+	for _, reserve := range query.Reserves {
+		if !ethereum.IsHexAddress(reserve) {
+			httputil.ResponseFailure(c, http.StatusBadRequest, fmt.Errorf("invalid reserve address %s", reserve))
+			return
+		}
+		// reserve-rates-crawler stores checksum addresses
+		reserves = append(reserves, ethereum.HexToAddress(reserve).Hex())
+	}
+
+	logger = logger.With("from", from, "to", to, "reserves", reserves, "interval", query.Interval)
+	logger.Debug("building reserve competitiveness report")
+
+	rates, err := s.rates.GetRates(reserves, from, to)
+	if err != nil {
+		logger.Errorw("failed to get reserve rates", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	trades, err := s.market.GetMarketTrades(from, to)
+	if err != nil {
+		logger.Errorw("failed to get market trades", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	if len(query.Tokens) != 0 {
+		filtered := make(map[string][]common.MarketTrade)
+		for _, token := range query.Tokens {
+			token = strings.ToUpper(token)
+			filtered[token] = trades[token]
+		}
+		trades = filtered
+	}
+
+	c.JSON(http.StatusOK, common.BuildReport(rates, trades, query.Interval))
+}
+
+func (s *Server) register() {
+	api := openapi.NewRouter(s.r, openapi.NewSpec("reserve competitiveness"), s.openAPIOptions...)
+	api.GET("/reserve-competitiveness", openapi.Endpoint{
+		Summary:  "competitiveness of reserve rates against market trades",
+		Query:    competitivenessQuery{},
+		Response: common.Report{},
+	}, s.getCompetitiveness)
+}
+
+// Run starts the HTTP server and runs in foreground until terminate by user.
+func (s *Server) Run() error {
+	s.register()
+	return s.r.Run(s.host)
+}
@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/binance/storage/tradestorage"
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	huobistorage "github.com/KyberNetwork/reserve-stats/accounting/huobi/storage"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-competitiveness/common"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const ethSymbol = "ETH"
+
+// MarketInterface returns CEX trades against ETH grouped by token symbol.
+type MarketInterface interface {
+	GetMarketTrades(from, to time.Time) (map[string][]common.MarketTrade, error)
+}
+
+// CEXMarket reads market trades from CEX trades stored by accounting fetchers.
+type CEXMarket struct {
+	sugar *zap.SugaredLogger
+	bs    tradestorage.Interface
+	hs    huobistorage.Interface
+}
+
+// NewCEXMarket creates a new CEXMarket instance.
+func NewCEXMarket(sugar *zap.SugaredLogger, bs tradestorage.Interface, hs huobistorage.Interface) *CEXMarket {
+	return &CEXMarket{sugar: sugar, bs: bs, hs: hs}
+}
+
+// tokenOfETHMarket returns token of a market symbol quoted in ETH, for example: KNCETH --> KNC.
+func tokenOfETHMarket(symbol string) (string, bool) {
+	symbol = strings.ToUpper(symbol)
+	if !strings.HasSuffix(symbol, ethSymbol) || len(symbol) == len(ethSymbol) {
+		return "", false
+	}
+	return strings.TrimSuffix(symbol, ethSymbol), true
+}
+
+func binanceMarketTrade(trade binance.TradeHistory) (string, common.MarketTrade, bool) {
+	token, ok := tokenOfETHMarket(trade.Symbol)
+	if !ok {
+		return "", common.MarketTrade{}, false
+	}
+	price, err := strconv.ParseFloat(trade.Price, 64)
+	if err != nil {
+		return "", common.MarketTrade{}, false
+	}
+	amount, err := strconv.ParseFloat(trade.Quantity, 64)
+	if err != nil {
+		return "", common.MarketTrade{}, false
+	}
+	return token, common.MarketTrade{
+		Exchange:  cexcommon.Binance.String(),
+		Timestamp: timeutil.TimestampMsToTime(trade.Time),
+		Price:     price,
+		Amount:    amount,
+	}, true
+}
+
+func huobiMarketTrade(trade huobi.TradeHistory) (string, common.MarketTrade, bool) {
+	token, ok := tokenOfETHMarket(trade.Symbol)
+	if !ok {
+		return "", common.MarketTrade{}, false
+	}
+	amount, err := strconv.ParseFloat(trade.FieldAmount, 64)
+	if err != nil || amount == 0 {
+		return "", common.MarketTrade{}, false
+	}
+	cashAmount, err := strconv.ParseFloat(trade.FieldCashAmount, 64)
+	if err != nil {
+		return "", common.MarketTrade{}, false
+	}
+	return token, common.MarketTrade{
+		Exchange:  cexcommon.Huobi.String(),
+		Timestamp: timeutil.TimestampMsToTime(trade.FinishedAt),
+		// huobi records are orders, use the average filled price
+		Price:  cashAmount / amount,
+		Amount: amount,
+	}, true
+}
+
+// GetMarketTrades returns Binance spot, margin and Huobi trades of ETH markets in given time range.
+func (m *CEXMarket) GetMarketTrades(from, to time.Time) (map[string][]common.MarketTrade, error) {
+	var (
+		logger = m.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		result = make(map[string][]common.MarketTrade)
+	)
+
+	binanceTrades, err := m.bs.GetTradeHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	binanceMarginTrades, err := m.bs.GetMarginTradeHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, accountTrades := range []map[string][]binance.TradeHistory{binanceTrades, binanceMarginTrades} {
+		for _, trades := range accountTrades {
+			for _, trade := range trades {
+				if token, mt, ok := binanceMarketTrade(trade); ok {
+					result[token] = append(result[token], mt)
+				}
+			}
+		}
+	}
+
+	huobiTrades, err := m.hs.GetTradeHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, trades := range huobiTrades {
+		for _, trade := range trades {
+			if token, mt, ok := huobiMarketTrade(trade); ok {
+				result[token] = append(result[token], mt)
+			}
+		}
+	}
+	logger.Debugw("market trades loaded", "tokens", len(result))
+	return result, nil
+}
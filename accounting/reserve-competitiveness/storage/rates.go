@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-competitiveness/common"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// RatesInterface returns the reserve rates.
+type RatesInterface interface {
+	GetRates(reserves []string, from, to time.Time) (map[string]map[string][]common.RatePoint, error)
+}
+
+// ReserveRatesDB reads reserve rates stored by reserve-rates-crawler. It does not manage the schema,
+// reserve_rates table is created by the crawler.
+type ReserveRatesDB struct {
+	sugar *zap.SugaredLogger
+	db    *sqlx.DB
+}
+
+// NewReserveRatesDB creates a new ReserveRatesDB instance.
+func NewReserveRatesDB(sugar *zap.SugaredLogger, db *sqlx.DB) *ReserveRatesDB {
+	return &ReserveRatesDB{sugar: sugar, db: db}
+}
+
+type rateRecord struct {
+	Reserve   string    `db:"reserve"`
+	Pair      string    `db:"pair"`
+	BuyRate   float64   `db:"buy_rate"`
+	SellRate  float64   `db:"sell_rate"`
+	Timestamp time.Time `db:"timestamp"`
+}
+
+// GetRates returns rates of given reserves, or all reserves if empty, that are active in the time range.
+// The last rate before from is included as it is still active at from.
+func (rdb *ReserveRatesDB) GetRates(reserves []string, from, to time.Time) (map[string]map[string][]common.RatePoint, error) {
+	var (
+		logger = rdb.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+			"reserves", reserves,
+		)
+		records []rateRecord
+		result  = make(map[string]map[string][]common.RatePoint)
+	)
+	const query = `SELECT reserve, pair, buy_rate, sell_rate, timestamp
+	FROM reserve_rates
+	WHERE timestamp >= $1 AND timestamp <= $2 AND (cardinality($3::TEXT[]) = 0 OR reserve = ANY($3::TEXT[]))
+	UNION ALL
+	(SELECT DISTINCT ON (reserve, pair) reserve, pair, buy_rate, sell_rate, timestamp
+	FROM reserve_rates
+	WHERE timestamp < $1 AND (cardinality($3::TEXT[]) = 0 OR reserve = ANY($3::TEXT[]))
+	ORDER BY reserve, pair, timestamp DESC);`
+
+	logger.Debugw("querying reserve rates", "query", query)
+	if err := rdb.db.Select(&records, query, from.UTC(), to.UTC(), pq.StringArray(reserves)); err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		pairs, ok := result[r.Reserve]
+		if !ok {
+			pairs = make(map[string][]common.RatePoint)
+			result[r.Reserve] = pairs
+		}
+		pairs[r.Pair] = append(pairs[r.Pair], common.RatePoint{
+			Timestamp: r.Timestamp,
+			BuyRate:   r.BuyRate,
+			SellRate:  r.SellRate,
+		})
+	}
+	return result, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
 	_ "github.com/KyberNetwork/reserve-stats/lib/httputil/validators" // import custom validator functions
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 var (
@@ -20,10 +21,11 @@ var (
 
 // Server is the engine to serve reserve-rate API query
 type Server struct {
-	r     *gin.Engine
-	db    storage.Interface
-	host  string
-	sugar *zap.SugaredLogger
+	r              *gin.Engine
+	db             storage.Interface
+	host           string
+	sugar          *zap.SugaredLogger
+	openAPIOptions []openapi.Option
 }
 
 func (sv *Server) reserveRates(c *gin.Context) {
@@ -84,7 +86,12 @@ func (sv *Server) reserveRates(c *gin.Context) {
 }
 
 func (sv *Server) register() {
-	sv.r.GET("/reserve-rates", sv.reserveRates)
+	api := openapi.NewRouter(sv.r, openapi.NewSpec("accounting reserve rates"), sv.openAPIOptions...)
+	api.GET("/reserve-rates", openapi.Endpoint{
+		Summary:  "rates of reserves and ETH/USD rates",
+		Query:    httputil.TimeRangeQuery{},
+		Response: storage.AccountingRatesReply{},
+	}, sv.reserveRates)
 }
 
 // Run starts HTTP server on preconfigure-host. Return error if occurs
@@ -94,12 +101,13 @@ func (sv *Server) Run() error {
 }
 
 // NewServer create an instance of Server to serve API query
-func NewServer(host string, db storage.Interface, sugar *zap.SugaredLogger) (*Server, error) {
+func NewServer(host string, db storage.Interface, sugar *zap.SugaredLogger, openAPIOptions ...openapi.Option) (*Server, error) {
 	r := gin.Default()
 	return &Server{
-		r:     r,
-		db:    db,
-		host:  host,
-		sugar: sugar,
+		r:              r,
+		db:             db,
+		host:           host,
+		sugar:          sugar,
+		openAPIOptions: openAPIOptions,
 	}, nil
 }
@@ -12,6 +12,7 @@ import (
 	txcommon "github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/common"
 	"github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 const (
@@ -25,6 +26,8 @@ type Server struct {
 	r     *gin.Engine
 	host  string
 	rts   storage.ReserveTransactionStorage
+
+	openAPIOptions []openapi.Option
 }
 
 type getTransactionsQuery struct {
@@ -132,13 +135,18 @@ func (s *Server) getTransactions(c *gin.Context) {
 }
 
 // NewServer creates a new instance of Server.
-func NewServer(sugar *zap.SugaredLogger, host string, rts storage.ReserveTransactionStorage) *Server {
+func NewServer(sugar *zap.SugaredLogger, host string, rts storage.ReserveTransactionStorage, openAPIOptions ...openapi.Option) *Server {
 	r := gin.Default()
-	return &Server{sugar: sugar, r: r, host: host, rts: rts}
+	return &Server{sugar: sugar, r: r, host: host, rts: rts, openAPIOptions: openAPIOptions}
 }
 
 func (s *Server) register() {
-	s.r.GET("/transactions", s.getTransactions)
+	api := openapi.NewRouter(s.r, openapi.NewSpec("reserve transactions"), s.openAPIOptions...)
+	api.GET("/transactions", openapi.Endpoint{
+		Summary:  "transactions of reserve addresses",
+		Query:    getTransactionsQuery{},
+		Response: getTransactionsResponse{},
+	}, s.getTransactions)
 }
 
 // Run starts the HTTP server and runs in foreground until terminate by user.
@@ -0,0 +1,149 @@
+// Package common contains the pricing parameters of tokens in ConversionRates contracts of reserves and
+// the changes between their versions.
+package common
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// StepFunction is a step function of ConversionRates contract. Y[i] is the rate adjustment in bps applied to
+// quantities or imbalances up to X[i].
+type StepFunction struct {
+	X []*big.Int `json:"x"`
+	Y []*big.Int `json:"y"`
+}
+
+// Equal returns true if both step functions have the same steps.
+func (f StepFunction) Equal(other StepFunction) bool {
+	return equalInts(f.X, other.X) && equalInts(f.Y, other.Y)
+}
+
+func equalInts(a, b []*big.Int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !equalInt(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func equalInt(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+// Params is the pricing parameters of a token in ConversionRates contract.
+type Params struct {
+	BaseBuyRate               *big.Int     `json:"base_buy_rate"`
+	BaseSellRate              *big.Int     `json:"base_sell_rate"`
+	MinimalRecordResolution   *big.Int     `json:"minimal_record_resolution"`
+	MaxPerBlockImbalance      *big.Int     `json:"max_per_block_imbalance"`
+	MaxTotalImbalance         *big.Int     `json:"max_total_imbalance"`
+	BuyQtyStepFunction        StepFunction `json:"buy_qty_step_function"`
+	SellQtyStepFunction       StepFunction `json:"sell_qty_step_function"`
+	BuyImbalanceStepFunction  StepFunction `json:"buy_imbalance_step_function"`
+	SellImbalanceStepFunction StepFunction `json:"sell_imbalance_step_function"`
+}
+
+// Change is a parameter changed from a version to the next one.
+type Change struct {
+	Param string      `json:"param"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Diff returns the parameters changed from prev to p, in the order of Params fields.
+func (p Params) Diff(prev Params) []Change {
+	var changes []Change
+	addInt := func(param string, from, to *big.Int) {
+		if !equalInt(from, to) {
+			changes = append(changes, Change{Param: param, From: from, To: to})
+		}
+	}
+	addStepFunction := func(param string, from, to StepFunction) {
+		if !from.Equal(to) {
+			changes = append(changes, Change{Param: param, From: from, To: to})
+		}
+	}
+	addInt("base_buy_rate", prev.BaseBuyRate, p.BaseBuyRate)
+	addInt("base_sell_rate", prev.BaseSellRate, p.BaseSellRate)
+	addInt("minimal_record_resolution", prev.MinimalRecordResolution, p.MinimalRecordResolution)
+	addInt("max_per_block_imbalance", prev.MaxPerBlockImbalance, p.MaxPerBlockImbalance)
+	addInt("max_total_imbalance", prev.MaxTotalImbalance, p.MaxTotalImbalance)
+	addStepFunction("buy_qty_step_function", prev.BuyQtyStepFunction, p.BuyQtyStepFunction)
+	addStepFunction("sell_qty_step_function", prev.SellQtyStepFunction, p.SellQtyStepFunction)
+	addStepFunction("buy_imbalance_step_function", prev.BuyImbalanceStepFunction, p.BuyImbalanceStepFunction)
+	addStepFunction("sell_imbalance_step_function", prev.SellImbalanceStepFunction, p.SellImbalanceStepFunction)
+	return changes
+}
+
+// TokenParams is a snapshot of the pricing parameters of a token in a reserve at a block.
+type TokenParams struct {
+	Reserve         ethereum.Address `json:"reserve"`
+	ConversionRates ethereum.Address `json:"conversion_rates"`
+	Token           ethereum.Address `json:"token"`
+	// Version is the version of parameters of the token in the reserve, starting from 1.
+	Version     uint64    `json:"version"`
+	BlockNumber uint64    `json:"block_number"`
+	Timestamp   time.Time `json:"timestamp"`
+	// TxHash is the setter transaction of the snapshot, empty for the initial snapshot.
+	TxHash string `json:"tx_hash"`
+	Params
+	// Changes is the parameters changed from the previous version, it is only set by Versions.
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// MarshalJSON implements custom JSON marshaller for TokenParams to
+// format timestamp in unix millis instead of RFC3339.
+func (p TokenParams) MarshalJSON() ([]byte, error) {
+	type AliasTokenParams TokenParams
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasTokenParams
+	}{
+		AliasTokenParams: (AliasTokenParams)(p),
+		Timestamp:        timeutil.TimeToTimestampMs(p.Timestamp),
+	})
+}
+
+// UnmarshalJSON implements custom JSON unmarshaller for TokenParams to
+// format timestamp in unix millis instead of RFC3339.
+func (p *TokenParams) UnmarshalJSON(data []byte) error {
+	type AliasTokenParams TokenParams
+	decoded := new(struct {
+		Timestamp uint64 `json:"timestamp"`
+		*AliasTokenParams
+	})
+	decoded.AliasTokenParams = (*AliasTokenParams)(p)
+	if err := json.Unmarshal(data, decoded); err != nil {
+		return err
+	}
+	p.Timestamp = timeutil.TimestampMsToTime(decoded.Timestamp)
+	return nil
+}
+
+// Versions sets the changes of snapshots of a token ordered by block number. Changes of the first snapshot
+// are from empty parameters.
+func Versions(snapshots []TokenParams) []TokenParams {
+	var (
+		result []TokenParams
+		prev   Params
+	)
+	for _, snapshot := range snapshots {
+		snapshot.Changes = snapshot.Params.Diff(prev)
+		prev = snapshot.Params
+		result = append(result, snapshot)
+	}
+	return result
+}
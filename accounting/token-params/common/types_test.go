@@ -0,0 +1,71 @@
+package common
+
+import (
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func TestVersions(t *testing.T) {
+	var (
+		token  = ethereum.HexToAddress("0xdd974D5C2e2928deA5F71b9825b8b646686BD200")
+		params = Params{
+			BaseBuyRate:             big.NewInt(1000),
+			BaseSellRate:            big.NewInt(900),
+			MinimalRecordResolution: big.NewInt(1),
+			MaxPerBlockImbalance:    big.NewInt(100),
+			MaxTotalImbalance:       big.NewInt(200),
+			BuyQtyStepFunction: StepFunction{
+				X: []*big.Int{big.NewInt(10)},
+				Y: []*big.Int{big.NewInt(-5)},
+			},
+		}
+		changed = params
+	)
+	changed.MaxTotalImbalance = big.NewInt(300)
+	changed.BuyQtyStepFunction = StepFunction{
+		X: []*big.Int{big.NewInt(10), big.NewInt(20)},
+		Y: []*big.Int{big.NewInt(-5), big.NewInt(-10)},
+	}
+
+	versions := Versions([]TokenParams{
+		{Token: token, Version: 1, BlockNumber: 1, Params: params},
+		{Token: token, Version: 2, BlockNumber: 2, Params: changed},
+	})
+	require.Len(t, versions, 2)
+	assert.Len(t, versions[0].Changes, 6)
+	assert.Equal(t, []Change{
+		{Param: "max_total_imbalance", From: big.NewInt(200), To: big.NewInt(300)},
+		{Param: "buy_qty_step_function", From: params.BuyQtyStepFunction, To: changed.BuyQtyStepFunction},
+	}, versions[1].Changes)
+
+	assert.Empty(t, params.Diff(params))
+}
+
+func TestTokenParamsJSON(t *testing.T) {
+	params := TokenParams{
+		Reserve:     ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F"),
+		Token:       ethereum.HexToAddress("0xdd974D5C2e2928deA5F71b9825b8b646686BD200"),
+		Version:     1,
+		BlockNumber: 7442895,
+		Timestamp:   timeutil.TimestampMsToTime(1553241328000).UTC(),
+		Params: Params{
+			BaseBuyRate:  big.NewInt(1000),
+			BaseSellRate: big.NewInt(900),
+		},
+	}
+	data, err := params.MarshalJSON()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"timestamp":1553241328000`)
+	assert.Contains(t, string(data), `"base_buy_rate":1000`)
+
+	var decoded TokenParams
+	require.NoError(t, decoded.UnmarshalJSON(data))
+	assert.Equal(t, params.Timestamp, decoded.Timestamp.UTC())
+	assert.Equal(t, params.BaseSellRate, decoded.BaseSellRate)
+}
@@ -0,0 +1,172 @@
+package fetcher
+
+import (
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// Crawler stores snapshots of token pricing parameters of reserves at every block their ConversionRates
+// setters are called.
+type Crawler struct {
+	sugar   *zap.SugaredLogger
+	reader  Reader
+	source  SetterSource
+	storage storage.Interface
+}
+
+// NewCrawler creates a new instance of Crawler.
+func NewCrawler(sugar *zap.SugaredLogger, reader Reader, source SetterSource, storage storage.Interface) *Crawler {
+	return &Crawler{
+		sugar:   sugar,
+		reader:  reader,
+		source:  source,
+		storage: storage,
+	}
+}
+
+func (c *Crawler) save(reserve, conversionRates, token ethereum.Address, block uint64, txHash string) error {
+	params, err := c.reader.Params(conversionRates, token, block)
+	if err != nil {
+		return err
+	}
+	ts, err := c.reader.BlockTime(block)
+	if err != nil {
+		return err
+	}
+	saved, err := c.storage.Save(common.TokenParams{
+		Reserve:         reserve,
+		ConversionRates: conversionRates,
+		Token:           token,
+		BlockNumber:     block,
+		Timestamp:       ts,
+		TxHash:          txHash,
+		Params:          params,
+	})
+	if err != nil {
+		return err
+	}
+	c.sugar.Debugw("token params snapshot",
+		"reserve", reserve,
+		"token", token,
+		"block", block,
+		"saved", saved)
+	return nil
+}
+
+// saveListed stores the snapshots of all tokens listed in the ConversionRates contract at the block.
+func (c *Crawler) saveListed(reserve, conversionRates ethereum.Address, block uint64, txHash string) error {
+	tokens, err := c.reader.ListedTokens(conversionRates, block)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		if err = c.save(reserve, conversionRates, token, block, txHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crawlSetters stores the snapshots of tokens changed by setters of the ConversionRates contract in block range.
+func (c *Crawler) crawlSetters(reserve, conversionRates ethereum.Address, fromBlock, toBlock uint64) error {
+	setters, err := c.source.SetterTxs(reserve, conversionRates, fromBlock, toBlock)
+	if err != nil {
+		return err
+	}
+	c.sugar.Infow("crawling token params",
+		"reserve", reserve,
+		"conversion_rates", conversionRates,
+		"from_block", fromBlock,
+		"to_block", toBlock,
+		"setters", len(setters))
+	for _, setter := range setters {
+		// the changed tokens of internal calls without input are unknown
+		if setter.Tokens == nil {
+			if err = c.saveListed(reserve, conversionRates, setter.BlockNumber, setter.Hash); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, token := range setter.Tokens {
+			if err = c.save(reserve, conversionRates, token, setter.BlockNumber, setter.Hash); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Crawl stores the snapshots of the reserve from the block after the last crawled block to toBlock. If the
+// reserve is never crawled, the parameters of all listed tokens at fromBlock are stored first. When the reserve
+// sets a new ConversionRates contract, the parameters of all its listed tokens are stored at the block and
+// setters of the new contract are crawled after it.
+func (c *Crawler) Crawl(reserve ethereum.Address, fromBlock, toBlock uint64) error {
+	var logger = c.sugar.With(
+		"func", caller.GetCurrentFunctionName(),
+		"reserve", reserve,
+	)
+
+	lastBlock, err := c.storage.LastBlock(reserve)
+	if err != nil {
+		return err
+	}
+
+	if lastBlock == 0 {
+		conversionRates, err := c.reader.ConversionRates(reserve, fromBlock)
+		if err != nil {
+			return err
+		}
+		logger.Infow("storing initial token params", "block", fromBlock)
+		if err = c.saveListed(reserve, conversionRates, fromBlock, ""); err != nil {
+			return err
+		}
+		if err = c.storage.SaveLastBlock(reserve, fromBlock); err != nil {
+			return err
+		}
+		lastBlock = fromBlock
+	}
+
+	if lastBlock >= toBlock {
+		logger.Infow("reserve is already crawled", "last_block", lastBlock, "to_block", toBlock)
+		return nil
+	}
+
+	conversionRates, err := c.reader.ConversionRates(reserve, lastBlock)
+	if err != nil {
+		return err
+	}
+	changes, err := c.reader.ConversionRatesChanges(reserve, lastBlock+1, toBlock)
+	if err != nil {
+		return err
+	}
+	start := lastBlock + 1
+	for _, change := range changes {
+		if change.ConversionRates == conversionRates {
+			continue
+		}
+		if start < change.BlockNumber {
+			if err = c.crawlSetters(reserve, conversionRates, start, change.BlockNumber-1); err != nil {
+				return err
+			}
+		}
+		logger.Infow("conversion rates contract changed",
+			"block", change.BlockNumber,
+			"from", conversionRates,
+			"to", change.ConversionRates)
+		conversionRates = change.ConversionRates
+		if err = c.saveListed(reserve, conversionRates, change.BlockNumber, change.Hash); err != nil {
+			return err
+		}
+		start = change.BlockNumber + 1
+	}
+	if start <= toBlock {
+		if err = c.crawlSetters(reserve, conversionRates, start, toBlock); err != nil {
+			return err
+		}
+	}
+	return c.storage.SaveLastBlock(reserve, toBlock)
+}
@@ -0,0 +1,160 @@
+package fetcher
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/common"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+)
+
+type stubReader struct {
+	// changes set the ConversionRates contract of the reserve from their blocks
+	changes []ConversionRatesChange
+	listed  map[ethereum.Address][]ethereum.Address
+}
+
+func (r *stubReader) BlockTime(block uint64) (time.Time, error) {
+	return time.Unix(int64(block), 0).UTC(), nil
+}
+
+func (r *stubReader) ConversionRates(_ ethereum.Address, block uint64) (ethereum.Address, error) {
+	var result ethereum.Address
+	for _, change := range r.changes {
+		if change.BlockNumber <= block {
+			result = change.ConversionRates
+		}
+	}
+	return result, nil
+}
+
+func (r *stubReader) ConversionRatesChanges(_ ethereum.Address, fromBlock, toBlock uint64) ([]ConversionRatesChange, error) {
+	var result []ConversionRatesChange
+	for _, change := range r.changes {
+		if change.BlockNumber >= fromBlock && change.BlockNumber <= toBlock {
+			result = append(result, change)
+		}
+	}
+	return result, nil
+}
+
+func (r *stubReader) ListedTokens(conversionRates ethereum.Address, _ uint64) ([]ethereum.Address, error) {
+	return r.listed[conversionRates], nil
+}
+
+// Params returns the block as base rates so every snapshot is changed.
+func (r *stubReader) Params(_, _ ethereum.Address, block uint64) (common.Params, error) {
+	rate := new(big.Int).SetUint64(block)
+	return common.Params{BaseBuyRate: rate, BaseSellRate: rate}, nil
+}
+
+type setterRange struct {
+	conversionRates    ethereum.Address
+	fromBlock, toBlock uint64
+}
+
+type stubSetterSource struct {
+	setters map[ethereum.Address][]SetterTx
+	ranges  []setterRange
+}
+
+func (s *stubSetterSource) SetterTxs(_, conversionRates ethereum.Address, fromBlock, toBlock uint64) ([]SetterTx, error) {
+	var result []SetterTx
+	s.ranges = append(s.ranges, setterRange{conversionRates: conversionRates, fromBlock: fromBlock, toBlock: toBlock})
+	for _, setter := range s.setters[conversionRates] {
+		if setter.BlockNumber >= fromBlock && setter.BlockNumber <= toBlock {
+			result = append(result, setter)
+		}
+	}
+	return result, nil
+}
+
+type stubStorage struct {
+	saved     []common.TokenParams
+	lastBlock uint64
+}
+
+func (s *stubStorage) Save(params common.TokenParams) (bool, error) {
+	s.saved = append(s.saved, params)
+	return true, nil
+}
+
+func (s *stubStorage) Get(_, _ ethereum.Address) ([]common.TokenParams, error) {
+	return s.saved, nil
+}
+
+func (s *stubStorage) LastBlock(_ ethereum.Address) (uint64, error) {
+	return s.lastBlock, nil
+}
+
+func (s *stubStorage) SaveLastBlock(_ ethereum.Address, block uint64) error {
+	s.lastBlock = block
+	return nil
+}
+
+func TestCrawlerConversionRatesChange(t *testing.T) {
+	var (
+		reserve  = ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		oldRates = ethereum.HexToAddress("0x798AbDA6Cc246D0EDbA912092A2a3dBd3d11191B")
+		newRates = ethereum.HexToAddress("0x8a6a3f0f2c2a7b2b9b7f0e9b3a51e4b3a0c1f2d3")
+		knc      = ethereum.HexToAddress("0xdd974D5C2e2928deA5F71b9825b8b646686BD200")
+		omg      = ethereum.HexToAddress("0xd26114cd6EE289AccF82350c8d8487fedB8A0C07")
+		reader   = &stubReader{
+			changes: []ConversionRatesChange{
+				{BlockNumber: 100, ConversionRates: oldRates},
+				{BlockNumber: 150, Hash: "0xswitch", ConversionRates: newRates},
+			},
+			listed: map[ethereum.Address][]ethereum.Address{
+				oldRates: {knc},
+				newRates: {knc, omg},
+			},
+		}
+		source = &stubSetterSource{setters: map[ethereum.Address][]SetterTx{
+			oldRates: {
+				{BlockNumber: 120, Hash: "0xold", Method: "setBaseRate", Tokens: []ethereum.Address{knc}},
+			},
+			newRates: {
+				// internal call of a multisig wallet without input
+				{BlockNumber: 170, Hash: "0xmultisig"},
+			},
+		}}
+		st = &stubStorage{lastBlock: 110}
+	)
+
+	crawler := NewCrawler(testutil.MustNewDevelopmentSugaredLogger(), reader, source, st)
+	require.NoError(t, crawler.Crawl(reserve, 0, 200))
+
+	assert.Equal(t, []setterRange{
+		{conversionRates: oldRates, fromBlock: 111, toBlock: 149},
+		{conversionRates: newRates, fromBlock: 151, toBlock: 200},
+	}, source.ranges)
+
+	type savedParams struct {
+		conversionRates ethereum.Address
+		token           ethereum.Address
+		block           uint64
+		txHash          string
+	}
+	var saved []savedParams
+	for _, params := range st.saved {
+		saved = append(saved, savedParams{
+			conversionRates: params.ConversionRates,
+			token:           params.Token,
+			block:           params.BlockNumber,
+			txHash:          params.TxHash,
+		})
+	}
+	assert.Equal(t, []savedParams{
+		{conversionRates: oldRates, token: knc, block: 120, txHash: "0xold"},
+		{conversionRates: newRates, token: knc, block: 150, txHash: "0xswitch"},
+		{conversionRates: newRates, token: omg, block: 150, txHash: "0xswitch"},
+		{conversionRates: newRates, token: knc, block: 170, txHash: "0xmultisig"},
+		{conversionRates: newRates, token: omg, block: 170, txHash: "0xmultisig"},
+	}, saved)
+	assert.Equal(t, uint64(200), st.lastBlock)
+}
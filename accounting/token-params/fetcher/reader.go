@@ -0,0 +1,179 @@
+package fetcher
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/common"
+	"github.com/KyberNetwork/reserve-stats/lib/contracts"
+)
+
+// commands of getStepFunctionData of ConversionRates contract, the length of X is at the command,
+// X[i] at command+1, the length of Y at command+2 and Y[i] at command+3.
+const (
+	buyQtyStepFunctionCommand        = 0
+	sellQtyStepFunctionCommand       = 4
+	buyImbalanceStepFunctionCommand  = 8
+	sellImbalanceStepFunctionCommand = 12
+)
+
+// ConversionRatesChange is a SetContractAddresses event of a reserve setting its ConversionRates contract.
+type ConversionRatesChange struct {
+	BlockNumber     uint64
+	Hash            string
+	ConversionRates ethereum.Address
+}
+
+// Reader reads pricing parameters of tokens from ConversionRates contracts.
+type Reader interface {
+	// BlockTime returns the timestamp of the block.
+	BlockTime(block uint64) (time.Time, error)
+	// ConversionRates returns the ConversionRates contract of the reserve at the block.
+	ConversionRates(reserve ethereum.Address, block uint64) (ethereum.Address, error)
+	// ConversionRatesChanges returns the changes of ConversionRates contract of the reserve in block range, both
+	// ends are inclusive, ordered by block number.
+	ConversionRatesChanges(reserve ethereum.Address, fromBlock, toBlock uint64) ([]ConversionRatesChange, error)
+	// ListedTokens returns the tokens listed in the ConversionRates contract at the block.
+	ListedTokens(conversionRates ethereum.Address, block uint64) ([]ethereum.Address, error)
+	// Params returns the pricing parameters of the token in the ConversionRates contract at the block.
+	Params(conversionRates, token ethereum.Address, block uint64) (common.Params, error)
+}
+
+// NodeReader is a Reader that calls contracts in an Ethereum node.
+type NodeReader struct {
+	backend *ethclient.Client
+}
+
+// NewNodeReader creates a new instance of NodeReader.
+func NewNodeReader(backend *ethclient.Client) *NodeReader {
+	return &NodeReader{backend: backend}
+}
+
+// BlockTime returns the timestamp of the block.
+func (r *NodeReader) BlockTime(block uint64) (time.Time, error) {
+	header, err := r.backend.HeaderByNumber(context.Background(), new(big.Int).SetUint64(block))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(int64(header.Time), 0).UTC(), nil
+}
+
+func callOpts(block uint64) *bind.CallOpts {
+	return &bind.CallOpts{BlockNumber: new(big.Int).SetUint64(block)}
+}
+
+// ConversionRates returns the ConversionRates contract of the reserve at the block.
+func (r *NodeReader) ConversionRates(reserve ethereum.Address, block uint64) (ethereum.Address, error) {
+	reserveContract, err := contracts.NewReserve(reserve, r.backend)
+	if err != nil {
+		return ethereum.Address{}, err
+	}
+	return reserveContract.ConversionRatesContract(callOpts(block))
+}
+
+// ConversionRatesChanges returns the changes of ConversionRates contract of the reserve in block range.
+func (r *NodeReader) ConversionRatesChanges(reserve ethereum.Address, fromBlock, toBlock uint64) ([]ConversionRatesChange, error) {
+	var result []ConversionRatesChange
+	filterer, err := contracts.NewReserveFilterer(reserve, r.backend)
+	if err != nil {
+		return nil, err
+	}
+	it, err := filterer.FilterSetContractAddresses(&bind.FilterOpts{Start: fromBlock, End: &toBlock})
+	if err != nil {
+		return nil, err
+	}
+	for it.Next() {
+		result = append(result, ConversionRatesChange{
+			BlockNumber:     it.Event.Raw.BlockNumber,
+			Hash:            it.Event.Raw.TxHash.Hex(),
+			ConversionRates: it.Event.Rate,
+		})
+	}
+	if err = it.Error(); err != nil {
+		return nil, err
+	}
+	if err = it.Close(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListedTokens returns the tokens listed in the ConversionRates contract at the block.
+func (r *NodeReader) ListedTokens(conversionRates ethereum.Address, block uint64) ([]ethereum.Address, error) {
+	contract, err := contracts.NewConversionRates(conversionRates, r.backend)
+	if err != nil {
+		return nil, err
+	}
+	return contract.GetListedTokens(callOpts(block))
+}
+
+func readStepFunction(contract *contracts.ConversionRates, opts *bind.CallOpts, token ethereum.Address, command int64) (common.StepFunction, error) {
+	readValues := func(lengthCommand int64) ([]*big.Int, error) {
+		length, err := contract.GetStepFunctionData(opts, token, big.NewInt(lengthCommand), big.NewInt(0))
+		if err != nil {
+			return nil, err
+		}
+		values := []*big.Int{}
+		for i := int64(0); i < length.Int64(); i++ {
+			value, err := contract.GetStepFunctionData(opts, token, big.NewInt(lengthCommand+1), big.NewInt(i))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	}
+
+	x, err := readValues(command)
+	if err != nil {
+		return common.StepFunction{}, err
+	}
+	y, err := readValues(command + 2)
+	if err != nil {
+		return common.StepFunction{}, err
+	}
+	return common.StepFunction{X: x, Y: y}, nil
+}
+
+// Params returns the pricing parameters of the token in the ConversionRates contract at the block.
+func (r *NodeReader) Params(conversionRates, token ethereum.Address, block uint64) (common.Params, error) {
+	var (
+		params common.Params
+		opts   = callOpts(block)
+		err    error
+	)
+	contract, err := contracts.NewConversionRates(conversionRates, r.backend)
+	if err != nil {
+		return common.Params{}, err
+	}
+
+	if params.BaseBuyRate, err = contract.GetBasicRate(opts, token, true); err != nil {
+		return common.Params{}, err
+	}
+	if params.BaseSellRate, err = contract.GetBasicRate(opts, token, false); err != nil {
+		return common.Params{}, err
+	}
+	if params.MinimalRecordResolution, params.MaxPerBlockImbalance, params.MaxTotalImbalance, err = contract.GetTokenControlInfo(opts, token); err != nil {
+		return common.Params{}, err
+	}
+
+	for _, f := range []struct {
+		command int64
+		result  *common.StepFunction
+	}{
+		{command: buyQtyStepFunctionCommand, result: &params.BuyQtyStepFunction},
+		{command: sellQtyStepFunctionCommand, result: &params.SellQtyStepFunction},
+		{command: buyImbalanceStepFunctionCommand, result: &params.BuyImbalanceStepFunction},
+		{command: sellImbalanceStepFunctionCommand, result: &params.SellImbalanceStepFunction},
+	} {
+		if *f.result, err = readStepFunction(contract, opts, token, f.command); err != nil {
+			return common.Params{}, err
+		}
+	}
+	return params, nil
+}
@@ -0,0 +1,216 @@
+package fetcher
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/nanmu42/etherscan-api"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/contracts"
+)
+
+// setterTokenArgs is the ConversionRates setter methods changing pricing parameters and their argument
+// of changed tokens, either an address or an array of addresses.
+var setterTokenArgs = map[string]string{
+	"setBaseRate":              "tokens",
+	"setImbalanceStepFunction": "token",
+	"setQtyStepFunction":       "token",
+	"setTokenControlInfo":      "token",
+	"addToken":                 "token",
+}
+
+// SetterTx is a successful transaction calling a ConversionRates setter changing pricing parameters of tokens.
+type SetterTx struct {
+	BlockNumber uint64
+	Timestamp   time.Time
+	Hash        string
+	// Method and Tokens are empty if the call input is not available, e.g. internal calls from multisig
+	// wallets, all listed tokens could be changed.
+	Method string
+	Tokens []ethereum.Address
+}
+
+// SetterSource returns the setter transactions of a ConversionRates contract of the reserve in a block range,
+// both ends are inclusive, ordered by block number.
+type SetterSource interface {
+	SetterTxs(reserve, conversionRates ethereum.Address, fromBlock, toBlock uint64) ([]SetterTx, error)
+}
+
+// decodeSetter returns the setter method and changed tokens of transaction input. It returns an empty method
+// if the input is not a call to a setter changing pricing parameters.
+func decodeSetter(conversionRatesABI abi.ABI, input []byte) (string, []ethereum.Address, error) {
+	if len(input) < 4 {
+		return "", nil, nil
+	}
+	method, err := conversionRatesABI.MethodById(input[:4])
+	if err != nil {
+		// not a method of ConversionRates contract
+		return "", nil, nil
+	}
+	arg, ok := setterTokenArgs[method.Name]
+	if !ok {
+		return "", nil, nil
+	}
+
+	args := make(map[string]interface{})
+	if err = method.Inputs.UnpackIntoMap(args, input[4:]); err != nil {
+		return "", nil, err
+	}
+	switch tokens := args[arg].(type) {
+	case ethereum.Address:
+		return method.Name, []ethereum.Address{tokens}, nil
+	case []ethereum.Address:
+		return method.Name, tokens, nil
+	default:
+		return "", nil, fmt.Errorf("unexpected %s argument of %s: %T", arg, method.Name, tokens)
+	}
+}
+
+// EtherscanSetterSource finds setter transactions in normal and internal transactions sent to ConversionRates
+// contracts from Etherscan API. Setters called by other contracts, e.g. multisig wallets, are internal transactions.
+type EtherscanSetterSource struct {
+	sugar  *zap.SugaredLogger
+	client *etherscan.Client
+	abi    abi.ABI
+}
+
+// NewEtherscanSetterSource creates a new instance of EtherscanSetterSource.
+func NewEtherscanSetterSource(sugar *zap.SugaredLogger, client *etherscan.Client) (*EtherscanSetterSource, error) {
+	conversionRatesABI, err := abi.JSON(strings.NewReader(contracts.ConversionRatesABI))
+	if err != nil {
+		return nil, err
+	}
+	return &EtherscanSetterSource{sugar: sugar, client: client, abi: conversionRatesABI}, nil
+}
+
+// etherscanPageSize is the maximum number of transactions in a page of Etherscan API.
+const etherscanPageSize = 1000
+
+// normalSetterTxs returns the setter transactions sent to ConversionRates contract in block range.
+func (s *EtherscanSetterSource) normalSetterTxs(conversionRates ethereum.Address, startBlock, endBlock int) ([]SetterTx, error) {
+	var result []SetterTx
+	// Etherscan paging starts with index=1
+	for page := 1; ; page++ {
+		txs, err := s.client.NormalTxByAddress(conversionRates.Hex(), &startBlock, &endBlock, page, etherscanPageSize, false)
+		if blockchain.IsEtherscanNotransactionFound(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			if tx.IsError != 0 || !strings.EqualFold(tx.To, conversionRates.Hex()) {
+				continue
+			}
+			input, err := hexutil.Decode(tx.Input)
+			if err != nil {
+				return nil, err
+			}
+			method, tokens, err := decodeSetter(s.abi, input)
+			if err != nil {
+				return nil, err
+			}
+			if method == "" {
+				continue
+			}
+			result = append(result, SetterTx{
+				BlockNumber: uint64(tx.BlockNumber),
+				Timestamp:   time.Time(tx.TimeStamp).UTC(),
+				Hash:        tx.Hash,
+				Method:      method,
+				Tokens:      tokens,
+			})
+		}
+		if len(txs) < etherscanPageSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+// internalSetterTxs returns the calls to ConversionRates contract in block range made by contracts other than
+// the reserve, which only records imbalances of trades. Etherscan does not always return the input of internal
+// calls, calls without input are returned with unknown tokens.
+func (s *EtherscanSetterSource) internalSetterTxs(reserve, conversionRates ethereum.Address, startBlock, endBlock int) ([]SetterTx, error) {
+	var result []SetterTx
+	for page := 1; ; page++ {
+		txs, err := s.client.InternalTxByAddress(conversionRates.Hex(), &startBlock, &endBlock, page, etherscanPageSize, false)
+		if blockchain.IsEtherscanNotransactionFound(err) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		for _, tx := range txs {
+			if tx.IsError != 0 ||
+				!strings.EqualFold(tx.To, conversionRates.Hex()) ||
+				strings.EqualFold(tx.From, reserve.Hex()) {
+				continue
+			}
+			var (
+				method string
+				tokens []ethereum.Address
+			)
+			if tx.Input != "" && tx.Input != "0x" {
+				input, err := hexutil.Decode(tx.Input)
+				if err != nil {
+					return nil, err
+				}
+				if method, tokens, err = decodeSetter(s.abi, input); err != nil {
+					return nil, err
+				}
+				if method == "" {
+					continue
+				}
+			}
+			result = append(result, SetterTx{
+				BlockNumber: uint64(tx.BlockNumber),
+				Timestamp:   time.Time(tx.TimeStamp).UTC(),
+				Hash:        tx.Hash,
+				Method:      method,
+				Tokens:      tokens,
+			})
+		}
+		if len(txs) < etherscanPageSize {
+			break
+		}
+	}
+	return result, nil
+}
+
+// SetterTxs returns the setter transactions of ConversionRates contract in block range, sent to it directly or
+// called by other contracts.
+func (s *EtherscanSetterSource) SetterTxs(reserve, conversionRates ethereum.Address, fromBlock, toBlock uint64) ([]SetterTx, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"reserve", reserve,
+			"conversion_rates", conversionRates,
+			"from_block", fromBlock,
+			"to_block", toBlock,
+		)
+		startBlock = int(fromBlock)
+		endBlock   = int(toBlock)
+	)
+
+	normal, err := s.normalSetterTxs(conversionRates, startBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+	internal, err := s.internalSetterTxs(reserve, conversionRates, startBlock, endBlock)
+	if err != nil {
+		return nil, err
+	}
+	result := append(normal, internal...)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].BlockNumber < result[j].BlockNumber
+	})
+	logger.Debugw("fetched setter transactions", "setters", len(normal), "internal_setters", len(internal))
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package fetcher
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/lib/contracts"
+)
+
+func TestDecodeSetter(t *testing.T) {
+	conversionRatesABI, err := abi.JSON(strings.NewReader(contracts.ConversionRatesABI))
+	require.NoError(t, err)
+
+	var (
+		knc  = ethereum.HexToAddress("0xdd974D5C2e2928deA5F71b9825b8b646686BD200")
+		omg  = ethereum.HexToAddress("0xd26114cd6EE289AccF82350c8d8487fedB8A0C07")
+		xBuy = []*big.Int{big.NewInt(100)}
+		yBuy = []*big.Int{big.NewInt(-10)}
+	)
+
+	input, err := conversionRatesABI.Pack("setQtyStepFunction", knc, xBuy, yBuy, xBuy, yBuy)
+	require.NoError(t, err)
+	method, tokens, err := decodeSetter(conversionRatesABI, input)
+	require.NoError(t, err)
+	assert.Equal(t, "setQtyStepFunction", method)
+	assert.Equal(t, []ethereum.Address{knc}, tokens)
+
+	input, err = conversionRatesABI.Pack("setBaseRate",
+		[]ethereum.Address{knc, omg},
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		[]*big.Int{big.NewInt(1), big.NewInt(2)},
+		[][14]byte{},
+		[][14]byte{},
+		big.NewInt(7442895),
+		[]*big.Int{},
+	)
+	require.NoError(t, err)
+	method, tokens, err = decodeSetter(conversionRatesABI, input)
+	require.NoError(t, err)
+	assert.Equal(t, "setBaseRate", method)
+	assert.Equal(t, []ethereum.Address{knc, omg}, tokens)
+
+	// setCompactData changes rates but not pricing parameters
+	input, err = conversionRatesABI.Pack("setCompactData", [][14]byte{}, [][14]byte{}, big.NewInt(7442895), []*big.Int{})
+	require.NoError(t, err)
+	method, tokens, err = decodeSetter(conversionRatesABI, input)
+	require.NoError(t, err)
+	assert.Empty(t, method)
+	assert.Empty(t, tokens)
+
+	method, _, err = decodeSetter(conversionRatesABI, []byte{})
+	require.NoError(t, err)
+	assert.Empty(t, method)
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	_ "github.com/KyberNetwork/reserve-stats/lib/httputil/validators" // import custom validator functions
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+const (
+	maxTimeFrame     = time.Hour * 24 * 365 // 1 year
+	defaultTimeFrame = time.Hour * 24 * 30  // 30 days
+)
+
+// Server is the HTTP server of token pricing parameters history.
+type Server struct {
+	sugar   *zap.SugaredLogger
+	r       *gin.Engine
+	host    string
+	storage storage.Interface
+
+	openAPIOptions []openapi.Option
+}
+
+// NewServer creates a new instance of Server.
+func NewServer(sugar *zap.SugaredLogger, host string, storage storage.Interface, openAPIOptions ...openapi.Option) *Server {
+	r := gin.Default()
+	return &Server{
+		sugar:   sugar,
+		r:       r,
+		host:    host,
+		storage: storage,
+
+		openAPIOptions: openAPIOptions,
+	}
+}
+
+type tokenParamsQuery struct {
+	httputil.TimeRangeQuery
+	Reserve string `form:"reserve" binding:"required,isAddress"`
+	// Token filters parameters of a token, default is all tokens.
+	Token string `form:"token" binding:"omitempty,isAddress"`
+}
+
+// versionsInRange returns the versions of snapshots ordered by token and block number, with changes from
+// the previous version of the same token, changed in given time range.
+func versionsInRange(snapshots []common.TokenParams, from, to time.Time) []common.TokenParams {
+	var (
+		result = []common.TokenParams{}
+		start  int
+	)
+	for i := range snapshots {
+		if i+1 < len(snapshots) && snapshots[i+1].Token == snapshots[i].Token {
+			continue
+		}
+		for _, version := range common.Versions(snapshots[start : i+1]) {
+			if !version.Timestamp.Before(from) && !version.Timestamp.After(to) {
+				result = append(result, version)
+			}
+		}
+		start = i + 1
+	}
+	return result
+}
+
+func (s *Server) getTokenParams(c *gin.Context) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+		query  tokenParamsQuery
+	)
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	from, to, err := query.Validate(
+		httputil.TimeRangeQueryWithMaxTimeFrame(maxTimeFrame),
+		httputil.TimeRangeQueryWithDefaultTimeFrame(defaultTimeFrame),
+	)
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+
+	reserve := ethereum.HexToAddress(query.Reserve)
+	token := ethereum.HexToAddress(query.Token)
+	logger = logger.With("reserve", reserve, "token", token, "from", from, "to", to)
+	logger.Debug("querying token params")
+
+	snapshots, err := s.storage.Get(reserve, token)
+	if err != nil {
+		logger.Errorw("failed to get token params", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, versionsInRange(snapshots, from, to))
+}
+
+func (s *Server) register() {
+	api := openapi.NewRouter(s.r, openapi.NewSpec("token params"), s.openAPIOptions...)
+	api.GET("/reserve/token-params", openapi.Endpoint{
+		Summary:  "versions of token pricing parameters in ConversionRates contract of reserve with changes",
+		Query:    tokenParamsQuery{},
+		Response: []common.TokenParams{},
+	}, s.getTokenParams)
+}
+
+// Run starts the HTTP server and runs in foreground until terminate by user.
+func (s *Server) Run() error {
+	s.register()
+	return s.r.Run(s.host)
+}
@@ -0,0 +1,21 @@
+package storage
+
+import (
+	ethereum "github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/common"
+)
+
+// Interface is the storage of token pricing parameters history.
+type Interface interface {
+	// Save stores the snapshot with the next version of the token in the reserve and returns true if it is saved.
+	// The snapshot is skipped if the parameters and ConversionRates contract are unchanged from the snapshot before it.
+	Save(params common.TokenParams) (bool, error)
+	// Get returns the snapshots of the reserve ordered by token and block number. All tokens are returned
+	// if token is zero address.
+	Get(reserve, token ethereum.Address) ([]common.TokenParams, error)
+	// LastBlock returns the last block crawled for the reserve, 0 if it is never crawled.
+	LastBlock(reserve ethereum.Address) (uint64, error)
+	// SaveLastBlock stores the last block crawled for the reserve.
+	SaveLastBlock(reserve ethereum.Address, block uint64) error
+}
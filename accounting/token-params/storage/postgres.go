@@ -0,0 +1,224 @@
+package storage
+
+import (
+	"encoding/json"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/jmoiron/sqlx"
+	"github.com/jmoiron/sqlx/types"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/common"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
+)
+
+const schema = `CREATE TABLE IF NOT EXISTS "token_params"
+(
+    id               SERIAL PRIMARY KEY,
+    reserve          TEXT      NOT NULL,
+    token            TEXT      NOT NULL,
+    version          INT       NOT NULL,
+    conversion_rates TEXT      NOT NULL,
+    block_number     BIGINT    NOT NULL,
+    timestamp        TIMESTAMP NOT NULL,
+    tx_hash          TEXT      NOT NULL,
+    params           JSONB     NOT NULL,
+    UNIQUE (reserve, token, version),
+    UNIQUE (reserve, token, block_number)
+);
+
+CREATE TABLE IF NOT EXISTS "token_params_last_block"
+(
+    reserve      TEXT PRIMARY KEY,
+    block_number BIGINT NOT NULL
+);
+`
+
+// TokenParamsDB is the PostgreSQL storage of token pricing parameters history.
+type TokenParamsDB struct {
+	sugar *zap.SugaredLogger
+	db    *sqlx.DB
+}
+
+// NewDB creates a new instance of TokenParamsDB and initializes the database schema.
+func NewDB(sugar *zap.SugaredLogger, db *sqlx.DB) (*TokenParamsDB, error) {
+	var logger = sugar.With("func", caller.GetCurrentFunctionName())
+
+	logger.Debugw("initializing database schema", "query", schema)
+	if _, err := db.Exec(schema); err != nil {
+		return nil, err
+	}
+	logger.Debug("database schema initialized successfully")
+	return &TokenParamsDB{sugar: sugar, db: db}, nil
+}
+
+type tokenParamsRecord struct {
+	Reserve         string         `db:"reserve"`
+	Token           string         `db:"token"`
+	Version         uint64         `db:"version"`
+	ConversionRates string         `db:"conversion_rates"`
+	BlockNumber     uint64         `db:"block_number"`
+	Timestamp       time.Time      `db:"timestamp"`
+	TxHash          string         `db:"tx_hash"`
+	Params          types.JSONText `db:"params"`
+}
+
+// TokenParams converts tokenParamsRecord instance to a common.TokenParams.
+func (r *tokenParamsRecord) TokenParams() (common.TokenParams, error) {
+	params := common.TokenParams{
+		Reserve:         ethereum.HexToAddress(r.Reserve),
+		ConversionRates: ethereum.HexToAddress(r.ConversionRates),
+		Token:           ethereum.HexToAddress(r.Token),
+		Version:         r.Version,
+		BlockNumber:     r.BlockNumber,
+		Timestamp:       r.Timestamp.UTC(),
+		TxHash:          r.TxHash,
+	}
+	if err := json.Unmarshal(r.Params, &params.Params); err != nil {
+		return common.TokenParams{}, err
+	}
+	return params, nil
+}
+
+const selectQuery = `SELECT reserve, token, version, conversion_rates, block_number, timestamp, tx_hash, params
+FROM "token_params"`
+
+// Save stores the snapshot if the parameters or ConversionRates contract changed from the previous snapshot of the token.
+func (tpd *TokenParamsDB) Save(params common.TokenParams) (saved bool, err error) {
+	var (
+		logger = tpd.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"reserve", params.Reserve,
+			"token", params.Token,
+			"block_number", params.BlockNumber,
+		)
+		previous []tokenParamsRecord
+	)
+
+	previousQuery := selectQuery + `
+WHERE reserve = $1 AND token = $2 AND block_number <= $3
+ORDER BY block_number DESC
+LIMIT 1;`
+
+	const insertQuery = `INSERT INTO "token_params"(reserve, token, version, conversion_rates, block_number, timestamp, tx_hash, params)
+SELECT $1, $2, COALESCE(MAX(version), 0) + 1, $3, $4, $5, $6, $7
+FROM "token_params"
+WHERE reserve = $1 AND token = $2;`
+
+	data, err := json.Marshal(params.Params)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := tpd.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer pgsql.CommitOrRollback(tx, logger, &err)
+
+	logger.Debugw("querying previous token params", "query", previousQuery)
+	if err = tx.Select(&previous, previousQuery, params.Reserve.Hex(), params.Token.Hex(), params.BlockNumber); err != nil {
+		return false, err
+	}
+	if len(previous) != 0 {
+		if previous[0].BlockNumber == params.BlockNumber {
+			logger.Debug("token params at block already exist")
+			return false, nil
+		}
+		prev, err := previous[0].TokenParams()
+		if err != nil {
+			return false, err
+		}
+		if prev.ConversionRates == params.ConversionRates && len(params.Params.Diff(prev.Params)) == 0 {
+			logger.Debug("token params are unchanged from previous snapshot")
+			return false, nil
+		}
+	}
+
+	logger.Debugw("saving token params", "query", insertQuery)
+	if _, err = tx.Exec(insertQuery,
+		params.Reserve.Hex(),
+		params.Token.Hex(),
+		params.ConversionRates.Hex(),
+		params.BlockNumber,
+		params.Timestamp.UTC(),
+		params.TxHash,
+		types.JSONText(data),
+	); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Get returns the snapshots of the reserve ordered by token and block number.
+func (tpd *TokenParamsDB) Get(reserve, token ethereum.Address) ([]common.TokenParams, error) {
+	var (
+		logger = tpd.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"reserve", reserve,
+			"token", token,
+		)
+		records []tokenParamsRecord
+		result  []common.TokenParams
+	)
+	query := selectQuery + `
+WHERE reserve = $1 AND ($2 OR token = $3)
+ORDER BY token, block_number;`
+	logger.Debugw("querying token params", "query", query)
+	if err := tpd.db.Select(&records, query, reserve.Hex(), blockchain.IsZeroAddress(token), token.Hex()); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		params, err := record.TokenParams()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, params)
+	}
+	return result, nil
+}
+
+// LastBlock returns the last block crawled for the reserve, 0 if it is never crawled.
+func (tpd *TokenParamsDB) LastBlock(reserve ethereum.Address) (uint64, error) {
+	var (
+		logger = tpd.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"reserve", reserve,
+		)
+		lastBlock uint64
+	)
+	const query = `SELECT COALESCE(MAX(block_number), 0) FROM "token_params_last_block" WHERE reserve = $1;`
+	logger.Debugw("querying last block", "query", query)
+	if err := tpd.db.Get(&lastBlock, query, reserve.Hex()); err != nil {
+		return 0, err
+	}
+	return lastBlock, nil
+}
+
+// SaveLastBlock stores the last block crawled for the reserve.
+func (tpd *TokenParamsDB) SaveLastBlock(reserve ethereum.Address, block uint64) error {
+	var (
+		logger = tpd.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"reserve", reserve,
+			"block", block,
+		)
+	)
+	const query = `INSERT INTO "token_params_last_block"(reserve, block_number)
+VALUES ($1, $2)
+ON CONFLICT (reserve) DO UPDATE SET block_number = EXCLUDED.block_number;`
+	logger.Debugw("saving last block", "query", query)
+	_, err := tpd.db.Exec(query, reserve.Hex(), block)
+	return err
+}
+
+// Close closes the database connection.
+func (tpd *TokenParamsDB) Close() error {
+	if tpd.db != nil {
+		return tpd.db.Close()
+	}
+	return nil
+}
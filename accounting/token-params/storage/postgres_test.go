@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"math/big"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/token-params/common"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func TestTokenParamsStorage(t *testing.T) {
+	var (
+		logger  = testutil.MustNewDevelopmentSugaredLogger()
+		reserve = ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		knc     = ethereum.HexToAddress("0xdd974D5C2e2928deA5F71b9825b8b646686BD200")
+		omg     = ethereum.HexToAddress("0xd26114cd6EE289AccF82350c8d8487fedB8A0C07")
+		params  = common.TokenParams{
+			Reserve:         reserve,
+			ConversionRates: ethereum.HexToAddress("0x798AbDA6Cc246D0EDbA912092A2a3dBd3d11191B"),
+			Token:           knc,
+			BlockNumber:     7442895,
+			Timestamp:       timeutil.TimestampMsToTime(1553241328000).UTC(),
+			Params: common.Params{
+				BaseBuyRate:             big.NewInt(1000),
+				BaseSellRate:            big.NewInt(900),
+				MinimalRecordResolution: big.NewInt(1),
+				MaxPerBlockImbalance:    big.NewInt(100),
+				MaxTotalImbalance:       big.NewInt(200),
+				BuyQtyStepFunction: common.StepFunction{
+					X: []*big.Int{big.NewInt(10)},
+					Y: []*big.Int{big.NewInt(-5)},
+				},
+				SellQtyStepFunction:       common.StepFunction{X: []*big.Int{}, Y: []*big.Int{}},
+				BuyImbalanceStepFunction:  common.StepFunction{X: []*big.Int{}, Y: []*big.Int{}},
+				SellImbalanceStepFunction: common.StepFunction{X: []*big.Int{}, Y: []*big.Int{}},
+			},
+		}
+	)
+
+	db, teardown := testutil.MustNewDevelopmentDB()
+	storage, err := NewDB(logger, db)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, teardown())
+	}()
+
+	lastBlock, err := storage.LastBlock(reserve)
+	require.NoError(t, err)
+	assert.Zero(t, lastBlock)
+
+	saved, err := storage.Save(params)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	// unchanged params are not saved
+	unchanged := params
+	unchanged.BlockNumber++
+	unchanged.TxHash = "0x1"
+	saved, err = storage.Save(unchanged)
+	require.NoError(t, err)
+	assert.False(t, saved)
+
+	changed := unchanged
+	changed.BlockNumber++
+	changed.MaxTotalImbalance = big.NewInt(300)
+	saved, err = storage.Save(changed)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	// params of a new ConversionRates contract are saved even if unchanged
+	replaced := changed
+	replaced.BlockNumber++
+	replaced.ConversionRates = ethereum.HexToAddress("0x8a6a3f0f2c2a7b2b9b7f0e9b3a51e4b3a0c1f2d3")
+	saved, err = storage.Save(replaced)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	other := params
+	other.Token = omg
+	saved, err = storage.Save(other)
+	require.NoError(t, err)
+	assert.True(t, saved)
+
+	stored, err := storage.Get(reserve, knc)
+	require.NoError(t, err)
+	require.Len(t, stored, 3)
+	assert.Equal(t, uint64(1), stored[0].Version)
+	assert.Equal(t, params.Params, stored[0].Params)
+	assert.Equal(t, uint64(2), stored[1].Version)
+	assert.Equal(t, "0x1", stored[1].TxHash)
+	assert.Equal(t, big.NewInt(300), stored[1].MaxTotalImbalance)
+	assert.Equal(t, replaced.ConversionRates, stored[2].ConversionRates)
+
+	all, err := storage.Get(reserve, ethereum.Address{})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+
+	require.NoError(t, storage.SaveLastBlock(reserve, 7442900))
+	require.NoError(t, storage.SaveLastBlock(reserve, 7443000))
+	lastBlock, err = storage.LastBlock(reserve)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7443000), lastBlock)
+}
@@ -0,0 +1,241 @@
+package common
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultInFlightWindow = time.Hour
+	defaultMatchWindow    = 6 * time.Hour
+	defaultTolerance      = 0.001
+)
+
+// Matcher matches CEX transfers with on-chain transfers.
+type Matcher struct {
+	inFlightWindow time.Duration
+	matchWindow    time.Duration
+	tolerance      float64
+}
+
+// Option sets the initialization behavior of Matcher.
+type Option func(m *Matcher)
+
+// WithInFlightWindow sets the duration a transfer found on one side is considered in flight.
+func WithInFlightWindow(window time.Duration) Option {
+	return func(m *Matcher) {
+		m.inFlightWindow = window
+	}
+}
+
+// WithMatchWindow sets the maximum duration between records of a transfer matched by amount and time.
+func WithMatchWindow(window time.Duration) Option {
+	return func(m *Matcher) {
+		m.matchWindow = window
+	}
+}
+
+// WithTolerance sets the relative difference of amounts that are considered the same.
+func WithTolerance(tolerance float64) Option {
+	return func(m *Matcher) {
+		m.tolerance = tolerance
+	}
+}
+
+// NewMatcher creates a new Matcher instance.
+func NewMatcher(options ...Option) *Matcher {
+	m := &Matcher{
+		inFlightWindow: defaultInFlightWindow,
+		matchWindow:    defaultMatchWindow,
+		tolerance:      defaultTolerance,
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// MatchWindow returns the maximum duration between records of a transfer, records in this window around a
+// time range are needed to match the transfers of the time range.
+func (m *Matcher) MatchWindow() time.Duration {
+	return m.matchWindow
+}
+
+func (m *Matcher) sameAmount(a, b float64) bool {
+	return math.Abs(a-b) <= m.tolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// sameAsset returns true if the on-chain transfer is of the asset of the CEX transfer.
+func sameAsset(cexTransfer *CEXTransfer, onChain OnChainTransfer) bool {
+	return onChain.Symbol != "" && strings.EqualFold(cexTransfer.Asset, onChain.Symbol)
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+func earliest(cexTransfer *CEXTransfer, onChain *OnChainTransfer) time.Time {
+	switch {
+	case cexTransfer == nil:
+		return onChain.Timestamp
+	case onChain == nil:
+		return cexTransfer.Timestamp
+	case onChain.Timestamp.Before(cexTransfer.Timestamp):
+		return onChain.Timestamp
+	}
+	return cexTransfer.Timestamp
+}
+
+func (m *Matcher) newMatch(direction Direction, cexTransfer *CEXTransfer, onChain *OnChainTransfer, now time.Time) Match {
+	match := Match{
+		Direction: direction,
+		CEX:       cexTransfer,
+		OnChain:   onChain,
+		Timestamp: earliest(cexTransfer, onChain),
+	}
+	match.Age = now.Sub(match.Timestamp)
+	switch {
+	case cexTransfer != nil && onChain != nil:
+		match.AmountDiff = onChain.Amount - cexTransfer.Amount
+		switch {
+		case !m.sameAmount(onChain.Amount, cexTransfer.Amount):
+			match.Status = StatusAmountMismatched
+		case cexTransfer.Pending:
+			match.Status = StatusInFlight
+		default:
+			match.Status = StatusMatched
+		}
+	case (cexTransfer != nil && cexTransfer.Pending) || match.Age < m.inFlightWindow:
+		match.Status = StatusInFlight
+	default:
+		match.Status = StatusUnmatched
+	}
+	return match
+}
+
+// matchDirection matches transfers of a direction. Unmatched withdrawal receipts and CEX deposits are not
+// reported, they may be transfers of other addresses.
+func (m *Matcher) matchDirection(direction Direction, cexTransfers []CEXTransfer, onChainTransfers []OnChainTransfer, now time.Time) []Match {
+	var (
+		result      []Match
+		byHash      = make(map[string][]int)
+		cexUsed     = make([]bool, len(cexTransfers))
+		onChainUsed = make([]bool, len(onChainTransfers))
+	)
+	for i, t := range onChainTransfers {
+		hash := NormalizeHash(t.Hash)
+		byHash[hash] = append(byHash[hash], i)
+	}
+
+	// a tx could have several transfers, the one of the same asset with the closest amount is matched
+	for i := range cexTransfers {
+		ct := &cexTransfers[i]
+		if ct.TxHash == "" {
+			continue
+		}
+		best := -1
+		for _, j := range byHash[NormalizeHash(ct.TxHash)] {
+			if onChainUsed[j] || !sameAsset(ct, onChainTransfers[j]) {
+				continue
+			}
+			if best == -1 || math.Abs(onChainTransfers[j].Amount-ct.Amount) < math.Abs(onChainTransfers[best].Amount-ct.Amount) {
+				best = j
+			}
+		}
+		if best == -1 {
+			continue
+		}
+		cexUsed[i], onChainUsed[best] = true, true
+		result = append(result, m.newMatch(direction, ct, &onChainTransfers[best], now))
+	}
+
+	// transfers without tx hash are matched by amount and time, the ones with tx hash not found are waiting
+	// for the on-chain transfer to be fetched
+	for i := range cexTransfers {
+		if cexUsed[i] {
+			continue
+		}
+		ct := &cexTransfers[i]
+		best := -1
+		for j, t := range onChainTransfers {
+			if ct.TxHash != "" || onChainUsed[j] || !sameAsset(ct, t) || !m.sameAmount(t.Amount, ct.Amount) ||
+				abs(t.Timestamp.Sub(ct.Timestamp)) > m.matchWindow {
+				continue
+			}
+			if best == -1 || abs(t.Timestamp.Sub(ct.Timestamp)) < abs(onChainTransfers[best].Timestamp.Sub(ct.Timestamp)) {
+				best = j
+			}
+		}
+		if best == -1 {
+			if direction == Withdrawal {
+				result = append(result, m.newMatch(direction, ct, nil, now))
+			}
+			continue
+		}
+		cexUsed[i], onChainUsed[best] = true, true
+		result = append(result, m.newMatch(direction, ct, &onChainTransfers[best], now))
+	}
+
+	if direction == Deposit {
+		for j := range onChainTransfers {
+			if !onChainUsed[j] {
+				result = append(result, m.newMatch(direction, nil, &onChainTransfers[j], now))
+			}
+		}
+	}
+	return result
+}
+
+// Match matches CEX transfers with on-chain transfers at the time now.
+func (m *Matcher) Match(cexTransfers []CEXTransfer, onChainTransfers []OnChainTransfer, now time.Time) []Match {
+	var result []Match
+	for _, direction := range []Direction{Withdrawal, Deposit} {
+		var (
+			cexOfDirection     []CEXTransfer
+			onChainOfDirection []OnChainTransfer
+		)
+		for _, t := range cexTransfers {
+			if t.Direction == direction {
+				cexOfDirection = append(cexOfDirection, t)
+			}
+		}
+		for _, t := range onChainTransfers {
+			if t.Direction == direction {
+				onChainOfDirection = append(onChainOfDirection, t)
+			}
+		}
+		result = append(result, m.matchDirection(direction, cexOfDirection, onChainOfDirection, now)...)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Timestamp.Before(result[j].Timestamp) })
+	return result
+}
+
+// NewReport returns the report of matches with timestamp in from..to.
+func NewReport(matches []Match, from, to time.Time) Report {
+	report := Report{
+		InFlight:         []Match{},
+		Unmatched:        []Match{},
+		AmountMismatched: []Match{},
+	}
+	for _, match := range matches {
+		if match.Timestamp.Before(from) || !match.Timestamp.Before(to) {
+			continue
+		}
+		switch match.Status {
+		case StatusMatched:
+			report.Matched++
+		case StatusInFlight:
+			report.InFlight = append(report.InFlight, match)
+		case StatusUnmatched:
+			report.Unmatched = append(report.Unmatched, match)
+		case StatusAmountMismatched:
+			report.AmountMismatched = append(report.AmountMismatched, match)
+		}
+	}
+	return report
+}
@@ -0,0 +1,93 @@
+package common
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatcher(t *testing.T) {
+	var (
+		t0  = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		now = t0.Add(10 * time.Hour)
+		cex = []CEXTransfer{
+			{Direction: Withdrawal, Exchange: "binance", ID: "w1", Asset: "KNC", TxHash: "ABC", Amount: 100, Timestamp: t0},
+			{Direction: Withdrawal, Exchange: "huobi", ID: "w2", Asset: "KNC", TxHash: "0xdef", Amount: 50, Timestamp: t0},
+			{Direction: Withdrawal, Exchange: "okx", ID: "w3", Asset: "KNC", Amount: 10, Timestamp: t0.Add(time.Hour)},
+			{Direction: Withdrawal, Exchange: "binance", ID: "w4", Asset: "KNC", TxHash: "0x222", Amount: 7, Timestamp: now.Add(-30 * time.Minute)},
+			{Direction: Withdrawal, Exchange: "binance", ID: "w5", Asset: "KNC", TxHash: "0x333", Amount: 7, Timestamp: t0},
+			{Direction: Withdrawal, Exchange: "binance", ID: "w6", Asset: "KNC", Amount: 7, Pending: true, Timestamp: t0},
+			{Direction: Deposit, Exchange: "binance", ID: "d1", Asset: "KNC", TxHash: "0x444", Amount: 20, Pending: true, Timestamp: t0.Add(time.Minute)},
+			// deposit from other addresses is not reported
+			{Direction: Deposit, Exchange: "binance", ID: "d2", Asset: "KNC", TxHash: "0x999", Amount: 20, Timestamp: t0},
+		}
+		onChain = []OnChainTransfer{
+			{Direction: Withdrawal, Hash: "0xabc", Symbol: "KNC", Amount: 100, Timestamp: t0.Add(5 * time.Minute)},
+			{Direction: Withdrawal, Hash: "0xdef", Symbol: "KNC", Amount: 45, Timestamp: t0.Add(5 * time.Minute)},
+			{Direction: Withdrawal, Hash: "0x111", Symbol: "KNC", Amount: 10, Timestamp: t0.Add(70 * time.Minute)},
+			{Direction: Deposit, Hash: "0x444", Symbol: "KNC", Amount: 20, Timestamp: t0},
+			{Direction: Deposit, Hash: "0x555", Symbol: "KNC", Amount: 20, Timestamp: t0},
+		}
+	)
+
+	matches := NewMatcher(WithInFlightWindow(time.Hour)).Match(cex, onChain, now)
+	report := NewReport(matches, t0, now)
+	assert.Equal(t, 2, report.Matched)
+
+	require.Len(t, report.AmountMismatched, 1)
+	assert.Equal(t, "w2", report.AmountMismatched[0].CEX.ID)
+	assert.Equal(t, -5.0, report.AmountMismatched[0].AmountDiff)
+	assert.Equal(t, 10*time.Hour, report.AmountMismatched[0].Age)
+
+	require.Len(t, report.InFlight, 3)
+	// matches are sorted by time
+	assert.Equal(t, "w6", report.InFlight[0].CEX.ID)
+	assert.Equal(t, "d1", report.InFlight[1].CEX.ID)
+	assert.Equal(t, "0x444", report.InFlight[1].OnChain.Hash)
+	assert.Equal(t, "w4", report.InFlight[2].CEX.ID)
+	assert.Nil(t, report.InFlight[2].OnChain)
+
+	require.Len(t, report.Unmatched, 2)
+	assert.Equal(t, "w5", report.Unmatched[0].CEX.ID)
+	assert.Equal(t, Deposit, report.Unmatched[1].Direction)
+	assert.Equal(t, "0x555", report.Unmatched[1].OnChain.Hash)
+	assert.Nil(t, report.Unmatched[1].CEX)
+
+	// matches out of the time range are not reported
+	report = NewReport(matches, t0.Add(time.Hour), now)
+	assert.Equal(t, 1, report.Matched)
+	assert.Len(t, report.InFlight, 1)
+	assert.Empty(t, report.Unmatched)
+}
+
+func TestMatcherAsset(t *testing.T) {
+	var (
+		t0  = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		now = t0.Add(10 * time.Hour)
+		cex = []CEXTransfer{
+			{Direction: Withdrawal, Exchange: "binance", ID: "w1", Asset: "knc", TxHash: "0xabc", Amount: 100, Timestamp: t0},
+			{Direction: Withdrawal, Exchange: "okx", ID: "w2", Asset: "KNC", Amount: 10, Timestamp: t0},
+		}
+		onChain = []OnChainTransfer{
+			// transfers of the same tx hash or the same amount and time but of other tokens
+			{Direction: Withdrawal, Hash: "0xabc", Symbol: "USDT", Amount: 100, Timestamp: t0},
+			{Direction: Withdrawal, Hash: "0x111", Symbol: "ETH", Amount: 10, Timestamp: t0},
+			{Direction: Withdrawal, Hash: "0xabc", Symbol: "KNC", Amount: 90, Timestamp: t0},
+		}
+	)
+	report := NewReport(NewMatcher().Match(cex, onChain, now), t0, now)
+	assert.Zero(t, report.Matched)
+	require.Len(t, report.AmountMismatched, 1)
+	assert.Equal(t, "w1", report.AmountMismatched[0].CEX.ID)
+	assert.Equal(t, "KNC", report.AmountMismatched[0].OnChain.Symbol)
+	require.Len(t, report.Unmatched, 1)
+	assert.Equal(t, "w2", report.Unmatched[0].CEX.ID)
+}
+
+func TestNormalizeHash(t *testing.T) {
+	assert.Equal(t, "0xabc", NormalizeHash("ABC"))
+	assert.Equal(t, "0xabc", NormalizeHash("0xAbc"))
+	assert.Equal(t, "", NormalizeHash(" "))
+}
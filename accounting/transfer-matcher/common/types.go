@@ -0,0 +1,134 @@
+// Package common matches the deposits and withdrawals of CEX accounts with the on-chain transfers of reserve
+// addresses of the same asset, by tx hash first then by amount and time for transfers without tx hash.
+package common
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// Direction is the direction of a transfer between a CEX account and an on-chain address.
+type Direction string
+
+const (
+	// Withdrawal is a transfer from a CEX account to a reserve address.
+	Withdrawal Direction = "withdrawal"
+	// Deposit is a transfer from a reserve address to a CEX account.
+	Deposit Direction = "deposit"
+)
+
+// Status is the matching status of a transfer.
+type Status string
+
+const (
+	// StatusMatched is a transfer found on both sides with the same amount.
+	StatusMatched Status = "matched"
+	// StatusInFlight is a transfer that is pending in CEX or is found on one side within the in-flight window.
+	StatusInFlight Status = "in_flight"
+	// StatusUnmatched is a transfer that is found on one side only after the in-flight window.
+	StatusUnmatched Status = "unmatched"
+	// StatusAmountMismatched is a transfer found on both sides with different amounts.
+	StatusAmountMismatched Status = "amount_mismatched"
+)
+
+// NormalizeHash returns the tx hash in lower case with 0x prefix, exchanges report it in different formats.
+func NormalizeHash(hash string) string {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if hash == "" || strings.HasPrefix(hash, "0x") {
+		return hash
+	}
+	return "0x" + hash
+}
+
+// CEXTransfer is a deposit or withdrawal of a CEX account.
+type CEXTransfer struct {
+	Direction Direction `json:"-"`
+	Exchange  string    `json:"exchange"`
+	Account   string    `json:"account"`
+	ID        string    `json:"id"`
+	Asset     string    `json:"asset"`
+	Amount    float64   `json:"amount"`
+	// Fee is charged by the exchange in addition to Amount.
+	Fee    float64 `json:"fee,omitempty"`
+	TxHash string  `json:"tx_hash,omitempty"`
+	// Pending is true if the transfer is not completed by the exchange.
+	Pending   bool      `json:"pending"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// MarshalJSON implements custom JSON marshaler for CEXTransfer to format timestamp in unix millis.
+func (t CEXTransfer) MarshalJSON() ([]byte, error) {
+	type AliasCEXTransfer CEXTransfer
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasCEXTransfer
+	}{
+		AliasCEXTransfer: (AliasCEXTransfer)(t),
+		Timestamp:        timeutil.TimeToTimestampMs(t.Timestamp),
+	})
+}
+
+// OnChainTransfer is an ERC20 or ether transfer of a reserve address.
+type OnChainTransfer struct {
+	Direction Direction `json:"-"`
+	Hash      string    `json:"hash"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	// Token is the token contract address, or the ETH address for ether.
+	Token string `json:"token"`
+	// Symbol is the upper case symbol of Token, it is compared with the asset of CEX transfers.
+	Symbol    string    `json:"symbol"`
+	Amount    float64   `json:"amount"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// MarshalJSON implements custom JSON marshaler for OnChainTransfer to format timestamp in unix millis.
+func (t OnChainTransfer) MarshalJSON() ([]byte, error) {
+	type AliasOnChainTransfer OnChainTransfer
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasOnChainTransfer
+	}{
+		AliasOnChainTransfer: (AliasOnChainTransfer)(t),
+		Timestamp:            timeutil.TimeToTimestampMs(t.Timestamp),
+	})
+}
+
+// Match is a transfer with its CEX and on-chain records, one of them is nil if it is not found.
+type Match struct {
+	Direction Direction        `json:"direction"`
+	Status    Status           `json:"status"`
+	CEX       *CEXTransfer     `json:"cex,omitempty"`
+	OnChain   *OnChainTransfer `json:"on_chain,omitempty"`
+	// AmountDiff is the on-chain amount minus the CEX amount.
+	AmountDiff float64 `json:"amount_diff,omitempty"`
+	// Timestamp is the time of the earliest record.
+	Timestamp time.Time `json:"timestamp"`
+	// Age is the duration from Timestamp to the matching time.
+	Age time.Duration `json:"age"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Match to format timestamp and age in millis.
+func (m Match) MarshalJSON() ([]byte, error) {
+	type AliasMatch Match
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		Age       int64  `json:"age"`
+		AliasMatch
+	}{
+		AliasMatch: (AliasMatch)(m),
+		Timestamp:  timeutil.TimeToTimestampMs(m.Timestamp),
+		Age:        m.Age.Milliseconds(),
+	})
+}
+
+// Report is the transfers that need attention, matched transfers are only counted.
+type Report struct {
+	Matched          int     `json:"matched"`
+	InFlight         []Match `json:"in_flight"`
+	Unmatched        []Match `json:"unmatched"`
+	AmountMismatched []Match `json:"amount_mismatched"`
+}
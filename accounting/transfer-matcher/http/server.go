@@ -0,0 +1,102 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/storage"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+const (
+	maxTimeFrame     = time.Hour * 24 * 30 // 30 days
+	defaultTimeFrame = time.Hour * 24      // 1 day
+)
+
+// Server is the HTTP server of transfer matching reports.
+type Server struct {
+	sugar   *zap.SugaredLogger
+	r       *gin.Engine
+	host    string
+	cex     storage.CEXInterface
+	onChain storage.OnChainInterface
+	matcher *common.Matcher
+
+	openAPIOptions []openapi.Option
+}
+
+// NewServer creates a new instance of Server.
+func NewServer(sugar *zap.SugaredLogger, host string, cex storage.CEXInterface, onChain storage.OnChainInterface,
+	matcher *common.Matcher, openAPIOptions ...openapi.Option) *Server {
+	r := gin.Default()
+	return &Server{
+		sugar:   sugar,
+		r:       r,
+		host:    host,
+		cex:     cex,
+		onChain: onChain,
+		matcher: matcher,
+
+		openAPIOptions: openAPIOptions,
+	}
+}
+
+func (s *Server) getMatches(c *gin.Context) {
+	var (
+		logger = s.sugar.With("func", caller.GetCurrentFunctionName())
+		query  httputil.TimeRangeQuery
+		now    = time.Now()
+	)
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	from, to, err := query.Validate(
+		httputil.TimeRangeQueryWithMaxTimeFrame(maxTimeFrame),
+		httputil.TimeRangeQueryWithDefaultTimeFrame(defaultTimeFrame),
+	)
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+
+	// records of transfers at the edges of time range could be outside of it
+	window := s.matcher.MatchWindow()
+	logger = logger.With("from", from, "to", to, "window", window)
+	logger.Debug("matching transfers")
+
+	cexTransfers, err := s.cex.GetTransfers(from.Add(-window), to.Add(window))
+	if err != nil {
+		logger.Errorw("failed to get CEX transfers", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	onChainTransfers, err := s.onChain.GetTransfers(from.Add(-window), to.Add(window))
+	if err != nil {
+		logger.Errorw("failed to get on-chain transfers", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, common.NewReport(s.matcher.Match(cexTransfers, onChainTransfers, now), from, to))
+}
+
+func (s *Server) register() {
+	api := openapi.NewRouter(s.r, openapi.NewSpec("transfer matcher"), s.openAPIOptions...)
+	api.GET("/transfer-matches", openapi.Endpoint{
+		Summary:  "CEX deposits and withdrawals matched with on-chain transfers, reporting in-flight, unmatched and mismatched-amount transfers",
+		Query:    httputil.TimeRangeQuery{},
+		Response: common.Report{},
+	}, s.getMatches)
+}
+
+// Run starts the HTTP server and runs in foreground until terminate by user.
+func (s *Server) Run() error {
+	s.register()
+	return s.r.Run(s.host)
+}
@@ -0,0 +1,278 @@
+// Package storage reads the CEX and on-chain transfers stored by accounting fetchers.
+package storage
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/accounting/cex"
+	cexstorage "github.com/KyberNetwork/reserve-stats/accounting/cex/storage"
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const binanceApplyTimeLayout = "2006-01-02 15:04:05"
+
+// CEXInterface returns the deposits and withdrawals of all CEX accounts.
+type CEXInterface interface {
+	GetTransfers(from, to time.Time) ([]common.CEXTransfer, error)
+}
+
+// BinanceDepositsInterface returns the stored Binance deposits.
+type BinanceDepositsInterface interface {
+	GetDepositHistory(fromTime, toTime time.Time) (map[string][]binance.DepositHistory, error)
+}
+
+// BinanceWithdrawalsInterface returns the stored Binance withdrawals.
+type BinanceWithdrawalsInterface interface {
+	GetWithdrawHistory(fromTime, toTime time.Time) (map[string][]binance.WithdrawHistory, error)
+}
+
+// HuobiDepositsInterface returns the stored Huobi deposits.
+type HuobiDepositsInterface interface {
+	GetDepositHistory(from, to time.Time) (map[string][]huobi.DepositHistory, error)
+}
+
+// HuobiWithdrawalsInterface returns the stored Huobi withdrawals.
+type HuobiWithdrawalsInterface interface {
+	GetWithdrawHistory(from, to time.Time) (map[string][]huobi.WithdrawHistory, error)
+}
+
+// CEXTransfers reads deposits and withdrawals of Binance, Huobi and registered exchanges.
+type CEXTransfers struct {
+	sugar              *zap.SugaredLogger
+	binanceDeposits    BinanceDepositsInterface
+	binanceWithdrawals BinanceWithdrawalsInterface
+	huobiDeposits      HuobiDepositsInterface
+	huobiWithdrawals   HuobiWithdrawalsInterface
+	cs                 cexstorage.Interface
+}
+
+// NewCEXTransfers creates a new CEXTransfers instance.
+func NewCEXTransfers(sugar *zap.SugaredLogger,
+	binanceDeposits BinanceDepositsInterface, binanceWithdrawals BinanceWithdrawalsInterface,
+	huobiDeposits HuobiDepositsInterface, huobiWithdrawals HuobiWithdrawalsInterface,
+	cs cexstorage.Interface) *CEXTransfers {
+	return &CEXTransfers{
+		sugar:              sugar,
+		binanceDeposits:    binanceDeposits,
+		binanceWithdrawals: binanceWithdrawals,
+		huobiDeposits:      huobiDeposits,
+		huobiWithdrawals:   huobiWithdrawals,
+		cs:                 cs,
+	}
+}
+
+// parseFee parses the fee of a transfer, it is zero if not reported.
+func parseFee(fee string) (float64, error) {
+	if fee == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(fee, 64)
+}
+
+// binanceDeposit converts a Binance deposit, status: 0 pending, 6 credited but cannot withdraw, 1 success.
+func binanceDeposit(account string, deposit binance.DepositHistory) (common.CEXTransfer, bool) {
+	amount, err := strconv.ParseFloat(deposit.Amount, 64)
+	if err != nil {
+		return common.CEXTransfer{}, false
+	}
+	return common.CEXTransfer{
+		Direction: common.Deposit,
+		Exchange:  cexcommon.Binance.String(),
+		Account:   account,
+		ID:        deposit.TxID,
+		Asset:     strings.ToUpper(deposit.Coin),
+		Amount:    amount,
+		TxHash:    common.NormalizeHash(deposit.TxID),
+		Pending:   deposit.Status == 0,
+		Timestamp: timeutil.TimestampMsToTime(deposit.InsertTime),
+	}, true
+}
+
+// binanceWithdrawal converts a Binance withdrawal, cancelled, rejected and failed withdrawals are skipped.
+func binanceWithdrawal(account string, withdrawal binance.WithdrawHistory) (common.CEXTransfer, bool) {
+	status := cexcommon.BinanceWithdrawStatus(withdrawal.Status)
+	switch status {
+	case cexcommon.Cancelled, cexcommon.Rejected, cexcommon.Failure:
+		return common.CEXTransfer{}, false
+	}
+	amount, err := strconv.ParseFloat(withdrawal.Amount, 64)
+	if err != nil {
+		return common.CEXTransfer{}, false
+	}
+	timestamp, err := time.Parse(binanceApplyTimeLayout, withdrawal.ApplyTime)
+	if err != nil {
+		return common.CEXTransfer{}, false
+	}
+	fee, err := parseFee(withdrawal.TxFee)
+	if err != nil {
+		return common.CEXTransfer{}, false
+	}
+	return common.CEXTransfer{
+		Direction: common.Withdrawal,
+		Exchange:  cexcommon.Binance.String(),
+		Account:   account,
+		ID:        withdrawal.ID,
+		Asset:     strings.ToUpper(withdrawal.Asset),
+		Amount:    amount,
+		Fee:       fee,
+		TxHash:    common.NormalizeHash(withdrawal.TxID),
+		Pending:   status != cexcommon.Completed,
+		Timestamp: timestamp,
+	}, true
+}
+
+// huobiDeposit converts a Huobi deposit, orphan deposits are skipped.
+func huobiDeposit(account string, deposit huobi.DepositHistory) (common.CEXTransfer, bool) {
+	if deposit.State == "orphan" {
+		return common.CEXTransfer{}, false
+	}
+	return common.CEXTransfer{
+		Direction: common.Deposit,
+		Exchange:  cexcommon.Huobi.String(),
+		Account:   account,
+		ID:        strconv.FormatUint(deposit.ID, 10),
+		Asset:     strings.ToUpper(deposit.Currency),
+		Amount:    deposit.Amount,
+		Fee:       deposit.Fee,
+		TxHash:    common.NormalizeHash(deposit.TxHash),
+		Pending:   deposit.State != "safe",
+		Timestamp: timeutil.TimestampMsToTime(deposit.CreatedAt),
+	}, true
+}
+
+// huobiWithdrawal converts a Huobi withdrawal, canceled and rejected withdrawals are skipped.
+func huobiWithdrawal(account string, withdrawal huobi.WithdrawHistory) (common.CEXTransfer, bool) {
+	switch withdrawal.State {
+	case "canceled", "reject", "wallet-reject", "confirm-error":
+		return common.CEXTransfer{}, false
+	}
+	return common.CEXTransfer{
+		Direction: common.Withdrawal,
+		Exchange:  cexcommon.Huobi.String(),
+		Account:   account,
+		ID:        strconv.FormatUint(withdrawal.ID, 10),
+		Asset:     strings.ToUpper(withdrawal.Currency),
+		Amount:    withdrawal.Amount,
+		Fee:       withdrawal.Fee,
+		TxHash:    common.NormalizeHash(withdrawal.TxHash),
+		Pending:   withdrawal.State != "confirmed",
+		Timestamp: timeutil.TimestampMsToTime(withdrawal.CreatedAt),
+	}, true
+}
+
+func cexTransfer(direction common.Direction, exchange, account string, transfer cex.Transfer) (common.CEXTransfer, bool) {
+	if transfer.Status == cex.TransferFailed {
+		return common.CEXTransfer{}, false
+	}
+	amount, err := strconv.ParseFloat(transfer.Amount, 64)
+	if err != nil {
+		return common.CEXTransfer{}, false
+	}
+	fee, err := parseFee(transfer.Fee)
+	if err != nil {
+		return common.CEXTransfer{}, false
+	}
+	return common.CEXTransfer{
+		Direction: direction,
+		Exchange:  exchange,
+		Account:   account,
+		ID:        transfer.ID,
+		Asset:     strings.ToUpper(transfer.Asset),
+		Amount:    amount,
+		Fee:       fee,
+		TxHash:    common.NormalizeHash(transfer.TxID),
+		Pending:   transfer.Status == cex.TransferPending,
+		Timestamp: transfer.Timestamp,
+	}, true
+}
+
+// GetTransfers returns the deposits and withdrawals of CEX accounts in given time range. Failed transfers
+// are skipped.
+func (s *CEXTransfers) GetTransfers(from, to time.Time) ([]common.CEXTransfer, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		result  []common.CEXTransfer
+		skipped int
+	)
+	add := func(transfer common.CEXTransfer, ok bool) {
+		if ok {
+			result = append(result, transfer)
+		} else {
+			skipped++
+		}
+	}
+
+	binanceDeposits, err := s.binanceDeposits.GetDepositHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for account, deposits := range binanceDeposits {
+		for _, deposit := range deposits {
+			add(binanceDeposit(account, deposit))
+		}
+	}
+	binanceWithdrawals, err := s.binanceWithdrawals.GetWithdrawHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for account, withdrawals := range binanceWithdrawals {
+		for _, withdrawal := range withdrawals {
+			add(binanceWithdrawal(account, withdrawal))
+		}
+	}
+
+	huobiDeposits, err := s.huobiDeposits.GetDepositHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for account, deposits := range huobiDeposits {
+		for _, deposit := range deposits {
+			add(huobiDeposit(account, deposit))
+		}
+	}
+	huobiWithdrawals, err := s.huobiWithdrawals.GetWithdrawHistory(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for account, withdrawals := range huobiWithdrawals {
+		for _, withdrawal := range withdrawals {
+			add(huobiWithdrawal(account, withdrawal))
+		}
+	}
+
+	for _, exchange := range cex.Names() {
+		deposits, err := s.cs.GetDeposits(exchange, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for account, transfers := range deposits {
+			for _, transfer := range transfers {
+				add(cexTransfer(common.Deposit, exchange, account, transfer))
+			}
+		}
+		withdrawals, err := s.cs.GetWithdrawals(exchange, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for account, transfers := range withdrawals {
+			for _, transfer := range transfers {
+				add(cexTransfer(common.Withdrawal, exchange, account, transfer))
+			}
+		}
+	}
+	logger.Debugw("transfers loaded", "transfers", len(result), "skipped", skipped)
+	return result, nil
+}
@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-addresses/client"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage"
+	"github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// ownAddressTypes are the types of addresses that receive the withdrawals of CEX accounts.
+var ownAddressTypes = []cexcommon.AddressType{
+	cexcommon.Reserve,
+	cexcommon.IntermediateOperator,
+	cexcommon.CompanyWallet,
+	cexcommon.DepositOperator,
+}
+
+// OnChainInterface returns the on-chain transfers between reserve addresses and CEX accounts.
+type OnChainInterface interface {
+	GetTransfers(from, to time.Time) ([]common.OnChainTransfer, error)
+}
+
+// OnChainTransfers reads ERC20 transfers, normal and internal transactions stored by
+// accounting-reserve-transaction-fetcher.
+type OnChainTransfers struct {
+	sugar     *zap.SugaredLogger
+	txStorage storage.ReserveTransactionStorage
+	addresses client.Interface
+	symbols   blockchain.TokenSymbolResolver
+	formatter blockchain.TokenAmountFormatterInterface
+}
+
+// NewOnChainTransfers creates a new OnChainTransfers instance.
+func NewOnChainTransfers(sugar *zap.SugaredLogger, txStorage storage.ReserveTransactionStorage,
+	addresses client.Interface, symbols blockchain.TokenSymbolResolver,
+	formatter blockchain.TokenAmountFormatterInterface) *OnChainTransfers {
+	return &OnChainTransfers{
+		sugar:     sugar,
+		txStorage: txStorage,
+		addresses: addresses,
+		symbols:   symbols,
+		formatter: formatter,
+	}
+}
+
+// addressBook classifies the addresses of on-chain transfers.
+type addressBook struct {
+	own        map[ethereum.Address]struct{}
+	cexDeposit map[ethereum.Address]struct{}
+}
+
+func (s *OnChainTransfers) newAddressBook() (*addressBook, error) {
+	book := &addressBook{
+		own:        make(map[ethereum.Address]struct{}),
+		cexDeposit: make(map[ethereum.Address]struct{}),
+	}
+	own, err := s.addresses.ReserveAddresses(ownAddressTypes...)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range own {
+		book.own[addr.Address] = struct{}{}
+	}
+	cexDeposit, err := s.addresses.ReserveAddresses(cexcommon.CEXDepositAddress)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range cexDeposit {
+		book.cexDeposit[addr.Address] = struct{}{}
+	}
+	return book, nil
+}
+
+// direction returns Deposit for transfers from own addresses to CEX deposit addresses, Withdrawal for
+// transfers from other addresses to own addresses.
+func (b *addressBook) direction(from, to ethereum.Address) (common.Direction, bool) {
+	_, fromOwn := b.own[from]
+	_, toOwn := b.own[to]
+	_, toCEX := b.cexDeposit[to]
+	_, fromCEX := b.cexDeposit[from]
+	switch {
+	case fromOwn && toCEX:
+		return common.Deposit, true
+	case !fromOwn && !fromCEX && toOwn:
+		return common.Withdrawal, true
+	}
+	return "", false
+}
+
+// symbol returns the upper case symbol of token, ETH for ether.
+func (s *OnChainTransfers) symbol(token ethereum.Address) (string, error) {
+	if token == blockchain.ETHAddr {
+		return "ETH", nil
+	}
+	symbol, err := s.symbols.Symbol(token)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(symbol), nil
+}
+
+func (s *OnChainTransfers) newTransfer(book *addressBook, hash string, from, to, token ethereum.Address,
+	value *big.Int, timestamp time.Time) (common.OnChainTransfer, bool, error) {
+	if value == nil || value.Sign() == 0 {
+		return common.OnChainTransfer{}, false, nil
+	}
+	direction, ok := book.direction(from, to)
+	if !ok {
+		return common.OnChainTransfer{}, false, nil
+	}
+	amount, err := s.formatter.FromWei(token, value)
+	if err != nil {
+		return common.OnChainTransfer{}, false, err
+	}
+	symbol, err := s.symbol(token)
+	if err != nil {
+		return common.OnChainTransfer{}, false, err
+	}
+	return common.OnChainTransfer{
+		Direction: direction,
+		Hash:      common.NormalizeHash(hash),
+		From:      from.Hex(),
+		To:        to.Hex(),
+		Token:     token.Hex(),
+		Symbol:    symbol,
+		Amount:    amount,
+		Timestamp: timestamp,
+	}, true, nil
+}
+
+// GetTransfers returns the ERC20 and ether transfers from reserve addresses to CEX deposit addresses and
+// from other addresses to reserve addresses in given time range. Failed transactions are skipped.
+func (s *OnChainTransfers) GetTransfers(from, to time.Time) ([]common.OnChainTransfer, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", from,
+			"to", to,
+		)
+		result []common.OnChainTransfer
+	)
+	book, err := s.newAddressBook()
+	if err != nil {
+		return nil, err
+	}
+	add := func(transfer common.OnChainTransfer, ok bool, err error) error {
+		if err != nil {
+			return err
+		}
+		if ok {
+			result = append(result, transfer)
+		}
+		return nil
+	}
+
+	erc20Transfers, err := s.txStorage.GetERC20Transfer(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range erc20Transfers {
+		if err = add(s.newTransfer(book, tx.Hash.Hex(), tx.From, tx.To, tx.ContractAddress, tx.Value, tx.Timestamp)); err != nil {
+			return nil, err
+		}
+	}
+
+	normalTxs, err := s.txStorage.GetNormalTx(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range normalTxs {
+		if tx.IsError != 0 {
+			continue
+		}
+		if err = add(s.newTransfer(book, tx.Hash, ethereum.HexToAddress(tx.From), ethereum.HexToAddress(tx.To),
+			blockchain.ETHAddr, tx.Value, tx.Timestamp)); err != nil {
+			return nil, err
+		}
+	}
+
+	internalTxs, err := s.txStorage.GetInternalTx(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range internalTxs {
+		if tx.IsError != 0 {
+			continue
+		}
+		if err = add(s.newTransfer(book, tx.Hash, ethereum.HexToAddress(tx.From), ethereum.HexToAddress(tx.To),
+			blockchain.ETHAddr, tx.Value, tx.Timestamp)); err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugw("transfers loaded", "transfers", len(result))
+	return result, nil
+}
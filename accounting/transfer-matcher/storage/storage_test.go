@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	cexcommon "github.com/KyberNetwork/reserve-stats/accounting/common"
+	"github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage"
+	"github.com/KyberNetwork/reserve-stats/accounting/transfer-matcher/common"
+	"github.com/KyberNetwork/reserve-stats/lib/binance"
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/huobi"
+)
+
+func TestCEXConverters(t *testing.T) {
+	transfer, ok := binanceWithdrawal("main", binance.WithdrawHistory{
+		ID:        "w1",
+		Amount:    "1.5",
+		TxFee:     "0.5",
+		Asset:     "knc",
+		TxID:      "ABC",
+		ApplyTime: "2021-03-01 10:00:00",
+		Status:    6,
+	})
+	require.True(t, ok)
+	assert.Equal(t, common.CEXTransfer{
+		Direction: common.Withdrawal,
+		Exchange:  "binance",
+		Account:   "main",
+		ID:        "w1",
+		Asset:     "KNC",
+		Amount:    1.5,
+		Fee:       0.5,
+		TxHash:    "0xabc",
+		Timestamp: time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC),
+	}, transfer)
+
+	_, ok = binanceWithdrawal("main", binance.WithdrawHistory{Amount: "1", Status: 1, ApplyTime: "2021-03-01 10:00:00"})
+	assert.False(t, ok)
+
+	transfer, ok = binanceDeposit("main", binance.DepositHistory{Amount: "2", Coin: "ETH", TxID: "0xdef", Status: 0})
+	require.True(t, ok)
+	assert.True(t, transfer.Pending)
+	assert.Equal(t, common.Deposit, transfer.Direction)
+
+	transfer, ok = huobiWithdrawal("huobi_v1", huobi.WithdrawHistory{ID: 10, Currency: "eth", Amount: 3, State: "confirmed"})
+	require.True(t, ok)
+	assert.False(t, transfer.Pending)
+	assert.Equal(t, "10", transfer.ID)
+
+	_, ok = huobiWithdrawal("huobi_v1", huobi.WithdrawHistory{State: "wallet-reject"})
+	assert.False(t, ok)
+
+	transfer, ok = huobiDeposit("huobi_v1", huobi.DepositHistory{Currency: "knc", Amount: 3, State: "confirming"})
+	require.True(t, ok)
+	assert.True(t, transfer.Pending)
+}
+
+type mockAddresses struct {
+	addresses []cexcommon.ReserveAddress
+}
+
+func (m *mockAddresses) ReserveAddresses(types ...cexcommon.AddressType) ([]cexcommon.ReserveAddress, error) {
+	var result []cexcommon.ReserveAddress
+	for _, addr := range m.addresses {
+		for _, typ := range types {
+			if addr.Type == typ {
+				result = append(result, addr)
+			}
+		}
+	}
+	return result, nil
+}
+
+type mockTxStorage struct {
+	storage.ReserveTransactionStorage
+	erc20Transfers []cexcommon.ERC20Transfer
+	normalTxs      []cexcommon.NormalTx
+}
+
+func (m *mockTxStorage) GetERC20Transfer(_, _ time.Time) ([]cexcommon.ERC20Transfer, error) {
+	return m.erc20Transfers, nil
+}
+
+func (m *mockTxStorage) GetNormalTx(_, _ time.Time) ([]cexcommon.NormalTx, error) {
+	return m.normalTxs, nil
+}
+
+func (m *mockTxStorage) GetInternalTx(_, _ time.Time) ([]cexcommon.InternalTx, error) {
+	return nil, nil
+}
+
+type mockSymbols map[ethereum.Address]string
+
+func (m mockSymbols) Symbol(address ethereum.Address) (string, error) {
+	return m[address], nil
+}
+
+func TestOnChainTransfers(t *testing.T) {
+	var (
+		reserve    = ethereum.HexToAddress("0x1")
+		cexDeposit = ethereum.HexToAddress("0x2")
+		exchange   = ethereum.HexToAddress("0x3")
+		token      = ethereum.HexToAddress("0x4")
+		oneToken   = big.NewInt(1000000000000000000)
+		ts         = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+		addresses  = &mockAddresses{addresses: []cexcommon.ReserveAddress{
+			{Address: reserve, Type: cexcommon.Reserve},
+			{Address: cexDeposit, Type: cexcommon.CEXDepositAddress},
+		}}
+		txStorage = &mockTxStorage{
+			erc20Transfers: []cexcommon.ERC20Transfer{
+				{Hash: ethereum.HexToHash("0xa"), From: reserve, To: cexDeposit, ContractAddress: token, Value: oneToken, Timestamp: ts},
+				{Hash: ethereum.HexToHash("0xb"), From: exchange, To: reserve, ContractAddress: token, Value: oneToken, Timestamp: ts},
+				// trades with other addresses are not transfers of CEX accounts
+				{Hash: ethereum.HexToHash("0xc"), From: reserve, To: exchange, ContractAddress: token, Value: oneToken, Timestamp: ts},
+			},
+			normalTxs: []cexcommon.NormalTx{
+				{Hash: "0xD", From: reserve.Hex(), To: cexDeposit.Hex(), Value: oneToken, Timestamp: ts},
+				{Hash: "0xe", From: reserve.Hex(), To: cexDeposit.Hex(), Value: oneToken, Timestamp: ts, IsError: 1},
+			},
+		}
+	)
+	s := NewOnChainTransfers(zap.NewNop().Sugar(), txStorage, addresses, mockSymbols{token: "knc"},
+		blockchain.NewMockTokenAmountFormatter())
+	transfers, err := s.GetTransfers(ts, ts.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, transfers, 3)
+	assert.Equal(t, common.Deposit, transfers[0].Direction)
+	assert.Equal(t, 1.0, transfers[0].Amount)
+	assert.Equal(t, "KNC", transfers[0].Symbol)
+	assert.Equal(t, common.Withdrawal, transfers[1].Direction)
+	assert.Equal(t, "0xd", transfers[2].Hash)
+	assert.Equal(t, blockchain.ETHAddr.Hex(), transfers[2].Token)
+	assert.Equal(t, "ETH", transfers[2].Symbol)
+}
@@ -7,9 +7,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/KyberNetwork/reserve-stats/accounting/common"
 	"github.com/KyberNetwork/reserve-stats/accounting/reserve-transaction-fetcher/storage"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
 	_ "github.com/KyberNetwork/reserve-stats/lib/httputil/validators" // import custom validator functions
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 	ethereum "github.com/ethereum/go-ethereum/common"
 )
 
@@ -24,6 +26,8 @@ type Server struct {
 	r     *gin.Engine
 	host  string
 	st    storage.ReserveTransactionStorage
+
+	openAPIOptions []openapi.Option
 }
 
 type getTransactionsQuery struct {
@@ -78,13 +82,18 @@ func (s *Server) getTransactions(c *gin.Context) {
 }
 
 // NewServer creates a new instance of Server.
-func NewServer(sugar *zap.SugaredLogger, host string, st storage.ReserveTransactionStorage) *Server {
+func NewServer(sugar *zap.SugaredLogger, host string, st storage.ReserveTransactionStorage, openAPIOptions ...openapi.Option) *Server {
 	r := gin.Default()
-	return &Server{sugar: sugar, r: r, host: host, st: st}
+	return &Server{sugar: sugar, r: r, host: host, st: st, openAPIOptions: openAPIOptions}
 }
 
 func (s *Server) register() {
-	s.r.GET("/wallet/transactions", s.getTransactions)
+	api := openapi.NewRouter(s.r, openapi.NewSpec("wallet ERC20 transfers"), s.openAPIOptions...)
+	api.GET("/wallet/transactions", openapi.Endpoint{
+		Summary:  "ERC20 transfers of wallet",
+		Query:    getTransactionsQuery{},
+		Response: []common.ERC20Transfer{},
+	}, s.getTransactions)
 }
 
 // Run starts the HTTP server and runs in foreground until terminate by user.
@@ -6,33 +6,42 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
+	"github.com/KyberNetwork/reserve-stats/accounting/zerox"
 	"github.com/KyberNetwork/reserve-stats/accounting/zerox/storage"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 // Server is the HTTP server of accounting CEX getTrades HTTP API.
 type Server struct {
-	sugar *zap.SugaredLogger
-	r     *gin.Engine
-	host  string
-	zs    *storage.ZeroxStorage
+	sugar          *zap.SugaredLogger
+	r              *gin.Engine
+	host           string
+	zs             *storage.ZeroxStorage
+	openAPIOptions []openapi.Option
 }
 
 // NewServer creates a new instance of Server.
-func NewServer(sugar *zap.SugaredLogger, host string, zs *storage.ZeroxStorage) *Server {
+func NewServer(sugar *zap.SugaredLogger, host string, zs *storage.ZeroxStorage, openAPIOptions ...openapi.Option) *Server {
 	r := gin.Default()
 	return &Server{
-		sugar: sugar,
-		r:     r,
-		host:  host,
-		zs:    zs,
+		sugar:          sugar,
+		r:              r,
+		host:           host,
+		zs:             zs,
+		openAPIOptions: openAPIOptions,
 	}
 
 }
 
 func (s *Server) register() {
 	// s.r.GET("/trades", s.getTrades)
-	s.r.GET("/convert_0x_trade", s.getConvert0xTrade)
+	api := openapi.NewRouter(s.r, openapi.NewSpec("0x trades"), s.openAPIOptions...)
+	api.GET("/convert_0x_trade", openapi.Endpoint{
+		Summary:  "0x trades converted to reserve trades",
+		Query:    getConvertTradeQuery{},
+		Response: []zerox.ConvertTrade{},
+	}, s.getConvert0xTrade)
 }
 
 // Run starts the HTTP server and runs in foreground until terminate by user.
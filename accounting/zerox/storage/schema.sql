@@ -22,4 +22,25 @@ CREATE TABLE IF NOT EXISTS convert_trades(
 	original_trade JSONB,
 	convert_trade JSONB,
 	UNIQUE(symbol, timestamp)
-)
+);
+
+ALTER TABLE tradelogs ADD COLUMN IF NOT EXISTS maker_address TEXT NOT NULL DEFAULT '';
+
+-- tradelogs stored before makers became configurable all belong to the original maker
+UPDATE tradelogs SET maker_address = '0xbc33a1f908612640f2849b56b67a4de4d179c151' WHERE maker_address = '';
+
+CREATE TABLE IF NOT EXISTS tradelogs_checkpoints(
+	maker_address TEXT PRIMARY KEY,
+	timestamp BIGINT NOT NULL
+);
+
+-- a transaction may have several fills, they are keyed by the fill id of the subgraph.
+-- Fills stored before are keyed by their transaction, they are replaced by the fills of the
+-- subgraph when their transactions are fetched again.
+ALTER TABLE tradelogs ADD COLUMN IF NOT EXISTS fill_id TEXT NOT NULL DEFAULT '';
+
+UPDATE tradelogs SET fill_id = tx WHERE fill_id = '';
+
+ALTER TABLE tradelogs DROP CONSTRAINT IF EXISTS tradelogs_pkey;
+
+CREATE UNIQUE INDEX IF NOT EXISTS tradelogs_tx_fill_id_key ON tradelogs(tx, fill_id);
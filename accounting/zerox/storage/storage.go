@@ -6,12 +6,15 @@ import (
 	"fmt"
 	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"go.uber.org/zap"
 
 	"github.com/KyberNetwork/reserve-stats/accounting/zerox"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/pgsql"
 )
 
 // ZeroxStorage ...
@@ -34,16 +37,21 @@ func NewZeroxStorage(db *sqlx.DB, sugar *zap.SugaredLogger) (*ZeroxStorage, erro
 	}, nil
 }
 
-// InsertTradelogs ...
-func (zs *ZeroxStorage) InsertTradelogs(tradelogs []zerox.Tradelog) error {
+// InsertTradelogs stores fills of the given maker. Rows stored before fills were keyed by their
+// fill id are keyed by transaction, they are replaced by the fills of their transactions.
+func (zs *ZeroxStorage) InsertTradelogs(maker string, tradelogs []zerox.Tradelog) (err error) {
+	logger := zs.sugar.With("func", caller.GetCurrentFunctionName(), "maker", maker)
 	var (
-		tx, inputTokenSymbol, inputTokenAddress, outputTokenSymbol, outputTokenAddress, takerAddress []string
-		timestamp                                                                                    []int64
-		inputTokenAmount, outputTokenAmount                                                          []float64
+		tx, fillID, inputTokenSymbol, inputTokenAddress, outputTokenSymbol, outputTokenAddress, takerAddress []string
+		timestamp                                                                                            []int64
+		inputTokenAmount, outputTokenAmount                                                                  []float64
 	)
-	query := `INSERT INTO tradelogs (tx, timestamp, input_token_symbol, input_token_address, input_token_amount, output_token_symbol, output_token_address, output_token_amount, taker_address)
+	const deleteLegacyQuery = `DELETE FROM tradelogs
+	WHERE fill_id = tx AND tx = ANY($1::TEXT[]) AND fill_id <> ALL($2::TEXT[]);`
+	query := `INSERT INTO tradelogs (tx, fill_id, timestamp, input_token_symbol, input_token_address, input_token_amount, output_token_symbol, output_token_address, output_token_amount, taker_address, maker_address)
 	VALUES(
 		unnest($1::TEXT[]),
+		unnest($11::TEXT[]),
 		unnest($2::BIGINT[]),
 		unnest($3::TEXT[]),
 		unnest($4::TEXT[]),
@@ -51,11 +59,13 @@ func (zs *ZeroxStorage) InsertTradelogs(tradelogs []zerox.Tradelog) error {
 		unnest($6::TEXT[]),
 		unnest($7::TEXT[]),
 		unnest($8::FLOAT[]),
-		unnest($9::TEXT[])
-	) ON CONFLICT (tx) DO NOTHING;`
+		unnest($9::TEXT[]),
+		$10::TEXT
+	) ON CONFLICT (tx, fill_id) DO NOTHING;`
 
 	for _, trade := range tradelogs {
 		tx = append(tx, trade.Transaction.ID)
+		fillID = append(fillID, trade.ID)
 		ts, err := strconv.ParseInt(trade.Timestamp, 10, 64)
 		if err != nil {
 			zs.sugar.Errorw("failed to parse timestamp", "error", err)
@@ -81,9 +91,18 @@ func (zs *ZeroxStorage) InsertTradelogs(tradelogs []zerox.Tradelog) error {
 		takerAddress = append(takerAddress, trade.Taker.ID)
 	}
 
-	if _, err := zs.db.Exec(query, pq.Array(tx), pq.Array(timestamp), pq.Array(inputTokenSymbol), pq.Array(inputTokenAddress), pq.Array(inputTokenAmount),
-		pq.Array(outputTokenSymbol), pq.Array(outputTokenAddress), pq.Array(outputTokenAmount), pq.Array(takerAddress)); err != nil {
-		zs.sugar.Errorw("failed to insert trades", "error", err)
+	dbTx, err := zs.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer pgsql.CommitOrRollback(dbTx, logger, &err)
+	if _, err = dbTx.Exec(deleteLegacyQuery, pq.Array(tx), pq.Array(fillID)); err != nil {
+		logger.Errorw("failed to delete legacy trades", "error", err)
+		return err
+	}
+	if _, err = dbTx.Exec(query, pq.Array(tx), pq.Array(timestamp), pq.Array(inputTokenSymbol), pq.Array(inputTokenAddress), pq.Array(inputTokenAmount),
+		pq.Array(outputTokenSymbol), pq.Array(outputTokenAddress), pq.Array(outputTokenAmount), pq.Array(takerAddress), strings.ToLower(maker), pq.Array(fillID)); err != nil {
+		logger.Errorw("failed to insert trades", "error", err)
 		return err
 	}
 	return nil
@@ -121,6 +140,33 @@ func (zs *ZeroxStorage) GetLastTradeTimestamp() (int64, error) {
 	return timestamp, nil
 }
 
+// GetCheckpoint returns the unix timestamp up to which fills of the given maker
+// were fetched, or the latest stored fill timestamp if there is no checkpoint yet.
+func (zs *ZeroxStorage) GetCheckpoint(maker string) (int64, error) {
+	var (
+		timestamp int64
+	)
+	const query = `SELECT COALESCE(
+		(SELECT timestamp FROM tradelogs_checkpoints WHERE maker_address = $1),
+		(SELECT MAX(timestamp) FROM tradelogs WHERE maker_address = $1),
+		0);`
+	if err := zs.db.Get(&timestamp, query, strings.ToLower(maker)); err != nil {
+		return 0, err
+	}
+	return timestamp, nil
+}
+
+// UpdateCheckpoint records that fills of the given maker were fetched up to timestamp.
+func (zs *ZeroxStorage) UpdateCheckpoint(maker string, timestamp int64) error {
+	const query = `INSERT INTO tradelogs_checkpoints(maker_address, timestamp) VALUES ($1, $2)
+	ON CONFLICT (maker_address) DO UPDATE SET timestamp = GREATEST(tradelogs_checkpoints.timestamp, EXCLUDED.timestamp);`
+	if _, err := zs.db.Exec(query, strings.ToLower(maker), timestamp); err != nil {
+		zs.sugar.Errorw("failed to update checkpoint", "maker", maker, "error", err)
+		return err
+	}
+	return nil
+}
+
 // InsertConvertTrades ...
 func (zs *ZeroxStorage) InsertConvertTrades(convertTrades zerox.ConvertTrades) error {
 	query := `INSERT INTO convert_trades(original_symbol, symbol, price, timestamp, original_trade, convert_trade, in_token, in_token_amount, out_token, out_token_amount)
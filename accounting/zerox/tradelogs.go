@@ -1,34 +1,76 @@
 package zerox
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"math/big"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
 	"github.com/hasura/go-graphql-client"
 	"go.uber.org/zap"
 
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
 	"github.com/KyberNetwork/reserve-stats/lib/marketdata"
 )
 
+const (
+	// DefaultSubgraphURL is the 0x exchange subgraph proxy used when no endpoint is configured.
+	DefaultSubgraphURL = "https://0x-exchange-proxy.knstats.com/0x-exchange"
+	// DefaultMakerAddress is the maker whose fills were fetched before makers became configurable.
+	DefaultMakerAddress = "0xbc33a1f908612640f2849b56b67a4de4d179c151"
+
+	defaultPageSize   = 1000
+	defaultAttempts   = 3
+	defaultRetryDelay = 5 * time.Second
+)
+
+// BigInt is the subgraph BigInt scalar, encoded as a decimal string.
+type BigInt string
+
 // TradelogClient ...
 type TradelogClient struct {
 	sugar            *zap.SugaredLogger
 	httpClient       *http.Client
 	graphqlClient    *graphql.Client
-	baseURL          string
+	subgraphURL      string
+	pageSize         int
+	attempts         int
+	retryDelay       time.Duration
 	binanceClient    *binance.Client
 	marketDataClient *marketdata.Client
 	symbols          []string
 }
 
+// Option sets the initialization behavior of TradelogClient.
+type Option func(z *TradelogClient)
+
+// WithSubgraphURL sets the 0x exchange subgraph endpoint to query fills from.
+func WithSubgraphURL(subgraphURL string) Option {
+	return func(z *TradelogClient) {
+		z.subgraphURL = subgraphURL
+	}
+}
+
+// WithPageSize sets the number of fills requested per page.
+func WithPageSize(pageSize int) Option {
+	return func(z *TradelogClient) {
+		z.pageSize = pageSize
+	}
+}
+
+// WithRetry sets how many times a failed page is requested and the delay between attempts.
+func WithRetry(attempts int, delay time.Duration) Option {
+	return func(z *TradelogClient) {
+		z.attempts = attempts
+		z.retryDelay = delay
+	}
+}
+
 func updateBinaceSupportedSymbol(binanceClient *binance.Client) ([]string, error) {
 	var (
 		symbols []string
@@ -44,72 +86,106 @@ func updateBinaceSupportedSymbol(binanceClient *binance.Client) ([]string, error
 }
 
 // NewZeroXTradelogClient ...
-func NewZeroXTradelogClient(baseURL string, binanceClient *binance.Client, marketDataClient *marketdata.Client, sugar *zap.SugaredLogger) *TradelogClient {
-	client := http.Client{
-		Timeout: 5 * time.Second,
+func NewZeroXTradelogClient(binanceClient *binance.Client, marketDataClient *marketdata.Client, sugar *zap.SugaredLogger, options ...Option) (*TradelogClient, error) {
+	client := &TradelogClient{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		subgraphURL:      DefaultSubgraphURL,
+		pageSize:         defaultPageSize,
+		attempts:         defaultAttempts,
+		retryDelay:       defaultRetryDelay,
+		sugar:            sugar,
+		binanceClient:    binanceClient,
+		marketDataClient: marketDataClient,
+	}
+	for _, opt := range options {
+		opt(client)
+	}
+	if client.pageSize <= 0 {
+		return nil, fmt.Errorf("invalid page size: %d", client.pageSize)
+	}
+	if client.attempts <= 0 {
+		return nil, fmt.Errorf("invalid number of attempts: %d", client.attempts)
 	}
-	graphqlClient := graphql.NewClient("https://gateway.thegraph.com/api/6c2fe0823843837e65ab636c0a861158/subgraphs/id/0x36c057dd1850fad3c075ba83105e67d2448dedaf-0", &client)
+	client.graphqlClient = graphql.NewClient(client.subgraphURL, client.httpClient)
 
 	symbols, err := updateBinaceSupportedSymbol(binanceClient)
 	if err != nil {
 		sugar.Errorw("failed to get symbols", "error", err)
 	}
-	return &TradelogClient{
-		graphqlClient:    graphqlClient,
-		httpClient:       &client,
-		baseURL:          baseURL,
-		sugar:            sugar,
-		binanceClient:    binanceClient,
-		marketDataClient: marketDataClient,
-		symbols:          symbols,
-	}
+	client.symbols = symbols
+	return client, nil
+}
+
+// nativeOrderFillsQuery is the subgraph query for a page of fills of a maker,
+// ordered by timestamp ascending.
+type nativeOrderFillsQuery struct {
+	Maker struct {
+		NativeOrderFills []Tradelog `graphql:"nativeOrderFills(orderBy: timestamp, orderDirection: asc, first: $first, skip: $skip, where: {timestamp_gte: $from, timestamp_lt: $to})"`
+	} `graphql:"maker(id: $maker)"`
 }
 
-// GetTradelogsFromHTTP ...
-func (z *TradelogClient) GetTradelogsFromHTTP(fromTime, toTime time.Time) ([]Tradelog, error) {
+// GetTradelogs returns fills of the given maker in the [fromTime, toTime) range.
+func (z *TradelogClient) GetTradelogs(maker string, fromTime, toTime time.Time) ([]Tradelog, error) {
 	var (
-		tradelogsResponse TradelogsResponse
-		tradelogs         []Tradelog
+		logger = z.sugar.With("func", caller.GetCurrentFunctionName(),
+			"maker", maker, "from", fromTime.Unix(), "to", toTime.Unix())
+		tradelogs []Tradelog
+		cursor    = fromTime.Unix()
+		skip      = 0
 	)
 	for {
-		z.sugar.Infow("get tradelogs", "from time", fromTime.Unix(), "to time", toTime.Unix())
-		requestBody := []byte(`{"query": "{ maker(id: \"0xbc33a1f908612640f2849b56b67a4de4d179c151\") { nativeOrderFills( orderBy: timestamp, orderDirection: desc, first: 1000, skip: 0, where: { timestamp_lt: ` + fmt.Sprintf("%d", toTime.Unix()) + `, timestamp_gt: ` + fmt.Sprintf("%d", fromTime.Unix()) + ` } ) { timestamp taker{ id } transaction{ id } inputToken{ id, symbol, decimals } outputToken{ id, symbol, decimals } inputTokenAmount, outputTokenAmount } } }"}`)
-		endpoint := fmt.Sprintf("%s/0x-exchange", z.baseURL)
-		request, err := http.NewRequest(
-			http.MethodPost,
-			endpoint,
-			bytes.NewBuffer(requestBody),
-		)
+		logger.Infow("get tradelogs", "cursor", cursor, "skip", skip)
+		fills, err := z.getPageWithRetry(maker, cursor, toTime.Unix(), skip)
 		if err != nil {
 			return nil, err
 		}
-		resp, err := z.httpClient.Do(request)
+		tradelogs = append(tradelogs, fills...)
+		if len(fills) < z.pageSize {
+			break
+		}
+		last, err := strconv.ParseInt(fills[len(fills)-1].Timestamp, 10, 64)
 		if err != nil {
 			return nil, err
 		}
-		switch resp.StatusCode {
-		case http.StatusOK:
-			response, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				return nil, err
-			}
-			if err := json.Unmarshal(response, &tradelogsResponse); err != nil {
-				return nil, err
+		// fills sharing the last timestamp may continue on the next page, so the
+		// next page starts at that timestamp and skips the ones already fetched.
+		if last != cursor {
+			cursor, skip = last, 0
+		}
+		for _, fill := range fills {
+			if fill.Timestamp == fills[len(fills)-1].Timestamp {
+				skip++
 			}
-		default:
-			z.sugar.Errorw("failed get tradelogs with http code", "error code", resp.StatusCode)
 		}
-		tradelogs = append(tradelogs, tradelogsResponse.Data.Maker.NativeOrderFills...)
-		if len(tradelogsResponse.Data.Maker.NativeOrderFills) < 1000 {
-			break
+	}
+	return tradelogs, nil
+}
+
+func (z *TradelogClient) getPageWithRetry(maker string, from, to int64, skip int) ([]Tradelog, error) {
+	var (
+		query     nativeOrderFillsQuery
+		variables = map[string]interface{}{
+			"maker": graphql.ID(strings.ToLower(maker)),
+			"first": graphql.Int(z.pageSize),
+			"skip":  graphql.Int(skip),
+			"from":  BigInt(strconv.FormatInt(from, 10)),
+			"to":    BigInt(strconv.FormatInt(to, 10)),
 		}
-		timestampVal, err := strconv.ParseInt(tradelogsResponse.Data.Maker.NativeOrderFills[0].Timestamp, 10, 64)
-		if err != nil {
-			return tradelogs, err
+		err error
+	)
+	for attempt := 1; attempt <= z.attempts; attempt++ {
+		query = nativeOrderFillsQuery{}
+		if err = z.graphqlClient.Query(context.Background(), &query, variables); err == nil {
+			return query.Maker.NativeOrderFills, nil
+		}
+		z.sugar.Warnw("failed to get tradelogs page", "maker", maker, "attempt", attempt, "error", err)
+		if attempt < z.attempts {
+			time.Sleep(z.retryDelay)
 		}
-		fromTime = time.Unix(timestampVal+1, 0)
 	}
-	return tradelogs, nil
+	return nil, fmt.Errorf("failed to get tradelogs page after %d attempts: %w", z.attempts, err)
 }
 
 // ConvertTrades ...
@@ -0,0 +1,173 @@
+package zerox
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const (
+	testMaker      = "0xbc33a1f908612640f2849b56b67a4de4d179c151"
+	testOtherMaker = "0x0000000000000000000000000000000000000001"
+)
+
+type stubRequest struct {
+	Query     string `json:"query"`
+	Variables struct {
+		Maker string `json:"maker"`
+		First int    `json:"first"`
+		Skip  int    `json:"skip"`
+		From  string `json:"from"`
+		To    string `json:"to"`
+	} `json:"variables"`
+}
+
+// newSubgraphStub serves nativeOrderFills of the given makers the way the
+// subgraph does. The first failures requests are answered with an error.
+func newSubgraphStub(t *testing.T, fills map[string][]Tradelog, failures int32) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		if atomic.AddInt32(&requests, 1) <= failures {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		var req stubRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Contains(t, req.Query, "$maker:ID!")
+		assert.Contains(t, req.Query, "$from:BigInt!")
+		assert.Contains(t, req.Query, "maker(id: $maker)")
+
+		from, err := strconv.ParseInt(req.Variables.From, 10, 64)
+		require.NoError(t, err)
+		to, err := strconv.ParseInt(req.Variables.To, 10, 64)
+		require.NoError(t, err)
+		var matched []Tradelog
+		for _, fill := range fills[req.Variables.Maker] {
+			ts, err := strconv.ParseInt(fill.Timestamp, 10, 64)
+			require.NoError(t, err)
+			if ts >= from && ts < to {
+				matched = append(matched, fill)
+			}
+		}
+		sort.SliceStable(matched, func(i, j int) bool { return matched[i].Timestamp < matched[j].Timestamp })
+		if req.Variables.Skip < len(matched) {
+			matched = matched[req.Variables.Skip:]
+		} else {
+			matched = nil
+		}
+		if len(matched) > req.Variables.First {
+			matched = matched[:req.Variables.First]
+		}
+		var resp struct {
+			Data struct {
+				Maker struct {
+					NativeOrderFills []Tradelog `json:"nativeOrderFills"`
+				} `json:"maker"`
+			} `json:"data"`
+		}
+		resp.Data.Maker.NativeOrderFills = matched
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	return server, &requests
+}
+
+func newTestFill(tx string, timestamp int64) Tradelog {
+	return Tradelog{
+		ID:                tx + "-1",
+		InputToken:        Token{ID: "0xdd974d5c2e2928dea5f71b9825b8b646686bd200", Decimals: "18", Symbol: "KNC"},
+		InputTokenAmount:  "1000000000000000000",
+		OutputToken:       Token{ID: "0xdac17f958d2ee523a2206206994597c13d831ec7", Decimals: "6", Symbol: "USDT"},
+		OutputTokenAmount: "1500000",
+		Timestamp:         strconv.FormatInt(timestamp, 10),
+		Transaction:       Transaction{ID: tx},
+		Taker:             Taker{ID: "0x1111111111111111111111111111111111111111"},
+	}
+}
+
+func newTestClient(t *testing.T, url string, options ...Option) *TradelogClient {
+	binanceClient := binance.NewClient("", "")
+	binanceClient.BaseURL = url
+	client, err := NewZeroXTradelogClient(binanceClient, nil, zap.NewNop().Sugar(),
+		append([]Option{WithSubgraphURL(url)}, options...)...)
+	require.NoError(t, err)
+	return client
+}
+
+func txs(tradelogs []Tradelog) []string {
+	var result []string
+	for _, tradelog := range tradelogs {
+		result = append(result, tradelog.Transaction.ID)
+	}
+	return result
+}
+
+func TestGetTradelogsPaging(t *testing.T) {
+	fills := map[string][]Tradelog{
+		testMaker: {
+			newTestFill("0x01", 100),
+			newTestFill("0x02", 200),
+			newTestFill("0x03", 200),
+			newTestFill("0x04", 200),
+			newTestFill("0x05", 300),
+			newTestFill("0x06", 400),
+		},
+		testOtherMaker: {
+			newTestFill("0x07", 150),
+		},
+	}
+	server, requests := newSubgraphStub(t, fills, 0)
+	defer server.Close()
+
+	client := newTestClient(t, server.URL, WithPageSize(2))
+	// maker addresses are matched case-insensitively
+	tradelogs, err := client.GetTradelogs("0xBC33A1F908612640F2849B56B67A4DE4D179C151", time.Unix(100, 0), time.Unix(400, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0x01", "0x02", "0x03", "0x04", "0x05"}, txs(tradelogs))
+	// fills of a transaction are stored by their ids
+	assert.Equal(t, "0x01-1", tradelogs[0].ID)
+	assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+
+	tradelogs, err = client.GetTradelogs(testOtherMaker, time.Unix(0, 0), time.Unix(1000, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0x07"}, txs(tradelogs))
+}
+
+func TestGetTradelogsRetry(t *testing.T) {
+	fills := map[string][]Tradelog{
+		testMaker: {newTestFill("0x01", 100)},
+	}
+
+	server, _ := newSubgraphStub(t, fills, 2)
+	defer server.Close()
+	client := newTestClient(t, server.URL, WithRetry(3, 0))
+	tradelogs, err := client.GetTradelogs(testMaker, time.Unix(0, 0), time.Unix(1000, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0x01"}, txs(tradelogs))
+
+	server, _ = newSubgraphStub(t, fills, 2)
+	defer server.Close()
+	client = newTestClient(t, server.URL, WithRetry(2, 0))
+	_, err = client.GetTradelogs(testMaker, time.Unix(0, 0), time.Unix(1000, 0))
+	require.Error(t, err)
+}
+
+func TestNewZeroXTradelogClientValidation(t *testing.T) {
+	_, err := NewZeroXTradelogClient(nil, nil, zap.NewNop().Sugar(), WithPageSize(0))
+	assert.Error(t, err)
+	_, err = NewZeroXTradelogClient(nil, nil, zap.NewNop().Sugar(), WithRetry(0, time.Second))
+	assert.Error(t, err)
+}
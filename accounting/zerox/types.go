@@ -19,6 +19,8 @@ type Taker struct {
 
 // Tradelog ...
 type Tradelog struct {
+	// ID is the id of the fill in the subgraph, a transaction may have several fills.
+	ID                string      `json:"id"`
 	InputToken        Token       `json:"inputToken"`
 	InputTokenAmount  string      `json:"inputTokenAmount"`
 	OutputToken       Token       `json:"outputToken"`
@@ -39,15 +41,6 @@ type SimpleTradelog struct {
 	TakerAddress string  `db:"taker_address"`
 }
 
-// TradelogsResponse ...
-type TradelogsResponse struct {
-	Data struct {
-		Maker struct {
-			NativeOrderFills []Tradelog `json:"nativeOrderFills"`
-		}
-	} `json:"data"`
-}
-
 // ConvertTrade ...
 type ConvertTrade struct {
 	Symbol    string  `db:"symbol"`
@@ -10,6 +10,7 @@ import (
 	"github.com/KyberNetwork/reserve-stats/app-names/storage"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 const (
@@ -23,6 +24,7 @@ func main() {
 	app.Version = "0.0.1"
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.AppNames)...)
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(defaultDB)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
 	}
@@ -54,7 +56,7 @@ func run(c *cli.Context) error {
 		}
 	}()
 
-	server, err := http.NewServer(httputil.NewHTTPAddressFromContext(c), appNameDB, sugar)
+	server, err := http.NewServer(httputil.NewHTTPAddressFromContext(c), appNameDB, sugar, openapi.NewOptionsFromContext(c)...)
 	if err != nil {
 		return err
 	}
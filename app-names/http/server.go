@@ -14,14 +14,28 @@ import (
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
 	_ "github.com/KyberNetwork/reserve-stats/lib/httputil/validators" // import custom validator functions
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 )
 
 // Server is the engine to serve reserve-rate API query
 type Server struct {
-	r     *gin.Engine
-	host  string
-	sugar *zap.SugaredLogger
-	db    storage.Interface
+	r              *gin.Engine
+	host           string
+	sugar          *zap.SugaredLogger
+	db             storage.Interface
+	openAPIOptions []openapi.Option
+}
+
+// getAppsQuery is the optional filters of getApps, it is only used to describe the endpoint as
+// the parameters are parsed one by one.
+type getAppsQuery struct {
+	Name    string `form:"name"`
+	Address string `form:"address" binding:"isAddress"`
+	Active  bool   `form:"active"`
+}
+
+type appIDParam struct {
+	ID int64 `uri:"id"`
 }
 
 func (sv *Server) getApps(c *gin.Context) {
@@ -237,11 +251,34 @@ func (sv *Server) deleteApp(c *gin.Context) {
 }
 
 func (sv *Server) register() {
-	sv.r.GET("/applications", sv.getApps)
-	sv.r.GET("/applications/:id", sv.getAddressFromAppID)
-	sv.r.POST("/applications", sv.createApp)
-	sv.r.PUT("/applications/:id", sv.updateApp)
-	sv.r.DELETE("/applications/:id", sv.deleteApp)
+	api := openapi.NewRouter(sv.r, openapi.NewSpec("application names"), sv.openAPIOptions...)
+	api.GET("/applications", openapi.Endpoint{
+		Summary:  "applications matching the filters",
+		Query:    getAppsQuery{},
+		Response: []common.Application{},
+	}, sv.getApps)
+	api.GET("/applications/:id", openapi.Endpoint{
+		Summary:  "application of id",
+		URI:      appIDParam{},
+		Response: common.Application{},
+	}, sv.getAddressFromAppID)
+	api.POST("/applications", openapi.Endpoint{
+		Summary:  "create application or add addresses to the existing one, 200 is returned on update",
+		Body:     common.Application{},
+		Response: common.Application{},
+		Status:   http.StatusCreated,
+	}, sv.createApp)
+	api.PUT("/applications/:id", openapi.Endpoint{
+		Summary:  "update application",
+		URI:      appIDParam{},
+		Body:     common.Application{},
+		Response: common.Application{},
+	}, sv.updateApp)
+	api.DELETE("/applications/:id", openapi.Endpoint{
+		Summary:  "delete application",
+		URI:      appIDParam{},
+		Response: struct{}{},
+	}, sv.deleteApp)
 }
 
 // Run starts HTTP server on preconfigure-host. Return error if occurs
@@ -251,12 +288,13 @@ func (sv *Server) Run() error {
 }
 
 // NewServer create an instance of Server to serve API query
-func NewServer(host string, appNameDB storage.Interface, sugar *zap.SugaredLogger) (*Server, error) {
+func NewServer(host string, appNameDB storage.Interface, sugar *zap.SugaredLogger, openAPIOptions ...openapi.Option) (*Server, error) {
 	r := gin.Default()
 	return &Server{
-		r:     r,
-		db:    appNameDB,
-		host:  host,
-		sugar: sugar,
+		r:              r,
+		db:             appNameDB,
+		host:           host,
+		sugar:          sugar,
+		openAPIOptions: openAPIOptions,
 	}, nil
 }
@@ -0,0 +1,56 @@
+package apikeys
+
+import (
+	"time"
+
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+)
+
+const (
+	enabledFlag = "api-keys-enabled"
+
+	reloadIntervalFlag    = "api-keys-reload-interval"
+	defaultReloadInterval = time.Minute
+)
+
+// NewCliFlags returns cli flags to configure API keys stored in PostgreSQL.
+func NewCliFlags(defaultDB string) []cli.Flag {
+	flags := []cli.Flag{
+		cli.BoolFlag{
+			Name:   enabledFlag,
+			Usage:  "manage API keys in PostgreSQL in addition to read/write keys",
+			EnvVar: "API_KEYS_ENABLED",
+		},
+		cli.DurationFlag{
+			Name:   reloadIntervalFlag,
+			Usage:  "interval to reload API keys from PostgreSQL",
+			EnvVar: "API_KEYS_RELOAD_INTERVAL",
+			Value:  defaultReloadInterval,
+		},
+	}
+	return append(flags, libapp.NewPostgreSQLFlags(defaultDB)...)
+}
+
+// ReloadIntervalFromContext returns the configured keys reload interval.
+func ReloadIntervalFromContext(c *cli.Context) time.Duration {
+	return c.Duration(reloadIntervalFlag)
+}
+
+// NewManagerFromContext creates a Manager from cli flags. Keys are only loaded from PostgreSQL
+// if it is enabled, otherwise the manager only serves the static keys.
+func NewManagerFromContext(c *cli.Context, sugar *zap.SugaredLogger, static []Key, adminKeyID string) (*Manager, error) {
+	var st Storage
+	if c.Bool(enabledFlag) {
+		db, err := libapp.NewDBFromContext(c)
+		if err != nil {
+			return nil, err
+		}
+		if st, err = NewPostgresStorage(sugar, db); err != nil {
+			return nil, err
+		}
+	}
+	return NewManager(sugar, st, static, adminKeyID)
+}
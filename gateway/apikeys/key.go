@@ -0,0 +1,146 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+var (
+	// ErrNotFound is returned when the key does not exist.
+	ErrNotFound = errors.New("api key not found")
+
+	validMethods = map[string]struct{}{
+		"GET":    {},
+		"POST":   {},
+		"PUT":    {},
+		"PATCH":  {},
+		"DELETE": {},
+	}
+)
+
+// Key is an API key that is allowed to access paths matching the patterns with the methods.
+// The ID is used as keyId of the HTTP signature and Secret is the HMAC signing secret.
+type Key struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Owner  string `json:"owner"`
+	Secret string `json:"-"`
+	// Paths are casbin keyMatch patterns, for example: /trade-logs or /reserve/*.
+	Paths   []string `json:"paths"`
+	Methods []string `json:"methods"`
+	// Reserves are the addresses of reserves the key is bound to, requests of the key to trade logs and
+	// reserve rates APIs are restricted to these reserves. The key is not bound if it is empty.
+	Reserves  []string   `json:"reserves"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Revoked   bool       `json:"revoked"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Key to format timestamps in unix millis.
+func (k Key) MarshalJSON() ([]byte, error) {
+	type AliasKey Key
+	var expiresAt *uint64
+	if k.ExpiresAt != nil {
+		ts := timeutil.TimeToTimestampMs(*k.ExpiresAt)
+		expiresAt = &ts
+	}
+	return json.Marshal(struct {
+		ExpiresAt *uint64 `json:"expires_at"`
+		CreatedAt uint64  `json:"created_at"`
+		AliasKey
+	}{
+		AliasKey:  (AliasKey)(k),
+		ExpiresAt: expiresAt,
+		CreatedAt: timeutil.TimeToTimestampMs(k.CreatedAt),
+	})
+}
+
+// Active returns true if the key is not revoked and not expired at given time.
+func (k Key) Active(now time.Time) bool {
+	if k.Revoked {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// Validate checks the scopes of the key and normalizes the methods to upper case.
+func (k *Key) Validate() error {
+	if len(strings.TrimSpace(k.Name)) == 0 {
+		return errors.New("name is required")
+	}
+	if len(k.Paths) == 0 {
+		return errors.New("at least one path is required")
+	}
+	for _, path := range k.Paths {
+		if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, ", \t\r\n") {
+			return fmt.Errorf("invalid path pattern %q", path)
+		}
+	}
+	if len(k.Methods) == 0 {
+		return errors.New("at least one method is required")
+	}
+	for i, method := range k.Methods {
+		method = strings.ToUpper(method)
+		if _, ok := validMethods[method]; !ok {
+			return fmt.Errorf("invalid method %q", method)
+		}
+		k.Methods[i] = method
+	}
+	for i, reserve := range k.Reserves {
+		if !ethereum.IsHexAddress(reserve) {
+			return fmt.Errorf("invalid reserve address %q", reserve)
+		}
+		k.Reserves[i] = ethereum.HexToAddress(reserve).Hex()
+	}
+	return nil
+}
+
+// policies returns the casbin policy lines of the key.
+func (k Key) policies() []string {
+	var (
+		methods = fmt.Sprintf("^(%s)$", strings.Join(k.Methods, "|"))
+		result  []string
+	)
+	for _, path := range k.Paths {
+		result = append(result, fmt.Sprintf("p, %s, %s, %s", k.ID, path, methods))
+	}
+	return result
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// NewStaticKeys returns the read and write keys configured by flags. The read key is allowed to
+// access all endpoints with GET method, the write key is allowed to access all endpoints.
+func NewStaticKeys(readKeyID, readSecret, writeKeyID, writeSecret string) []Key {
+	return []Key{
+		{
+			ID:      readKeyID,
+			Name:    "read",
+			Secret:  readSecret,
+			Paths:   []string{"/*"},
+			Methods: []string{"GET"},
+		},
+		{
+			ID:      writeKeyID,
+			Name:    "write",
+			Secret:  writeSecret,
+			Paths:   []string{"/*"},
+			Methods: []string{"GET", "POST", "PUT", "DELETE"},
+		},
+	}
+}
@@ -0,0 +1,263 @@
+package apikeys
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/KyberNetwork/httpsign-utils/authenticator"
+	"github.com/casbin/casbin"
+	"github.com/gin-gonic/gin"
+	scas "github.com/qiangmzsx/string-adapter"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/permission"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+)
+
+// AdminPathPrefix is the prefix of key management endpoints, only admin key is allowed to access them.
+const AdminPathPrefix = "/admin/"
+
+// ErrStorageNotConfigured is returned when managing keys without a storage.
+var ErrStorageNotConfigured = errors.New("api keys storage is not configured")
+
+// Storage stores API keys.
+type Storage interface {
+	Create(key Key) error
+	Update(key Key) error
+	Revoke(id string) error
+	Get(id string) (Key, error)
+	// List returns all keys, including revoked and expired ones.
+	List() ([]Key, error)
+}
+
+// Manager builds the casbin enforcer and httpsign authenticator of gateway from static keys
+// configured by flags and keys in storage. They are rebuilt on every Reload, so keys changes
+// take effect without restarting the gateway.
+type Manager struct {
+	sugar      *zap.SugaredLogger
+	st         Storage
+	static     []Key
+	adminKeyID string
+
+	mu       sync.RWMutex
+	keys     map[string]Key
+	enforcer *casbin.Enforcer
+	auth     gin.HandlerFunc
+}
+
+// NewManager creates a new Manager instance and loads the keys. The storage is optional,
+// only static keys are used if it is nil. The admin key is allowed to access all endpoints.
+func NewManager(sugar *zap.SugaredLogger, st Storage, static []Key, adminKeyID string) (*Manager, error) {
+	m := &Manager{
+		sugar:      sugar,
+		st:         st,
+		static:     static,
+		adminKeyID: adminKeyID,
+	}
+	if err := m.Reload(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// HasStorage returns true if keys are managed in a storage.
+func (m *Manager) HasStorage() bool {
+	return m.st != nil
+}
+
+// Reload reloads keys from storage and rebuilds the enforcer and authenticator.
+func (m *Manager) Reload() error {
+	var (
+		logger   = m.sugar.With("func", caller.GetCurrentFunctionName())
+		now      = time.Now()
+		all      = make([]Key, len(m.static))
+		keys     = make(map[string]Key)
+		policies []string
+		keyPairs []authenticator.KeyPair
+	)
+	copy(all, m.static)
+	if m.st != nil {
+		stored, err := m.st.List()
+		if err != nil {
+			return err
+		}
+		all = append(all, stored...)
+	}
+
+	for _, key := range all {
+		if len(key.ID) == 0 || !key.Active(now) {
+			continue
+		}
+		if _, ok := keys[key.ID]; ok {
+			logger.Warnw("duplicated api key is ignored", "id", key.ID, "name", key.Name)
+			continue
+		}
+		keys[key.ID] = key
+		policies = append(policies, key.policies()...)
+		keyPairs = append(keyPairs, authenticator.KeyPair{
+			AccessKeyID:     key.ID,
+			SecretAccessKey: key.Secret,
+		})
+	}
+
+	e := casbin.NewEnforcer(casbin.NewModel(permission.Model), scas.NewAdapter(strings.Join(policies, "\n")))
+	if err := e.LoadPolicy(); err != nil {
+		return err
+	}
+	auth, err := authenticator.NewAuthenticator(keyPairs...)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.keys = keys
+	m.enforcer = e
+	m.auth = auth.Authenticated()
+	m.mu.Unlock()
+	logger.Infow("api keys reloaded", "keys", len(keys))
+	return nil
+}
+
+// Run reloads keys periodically to pick up changes made by other gateway instances.
+func (m *Manager) Run(interval time.Duration) {
+	logger := m.sugar.With("func", caller.GetCurrentFunctionName(), "interval", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := m.Reload(); err != nil {
+			logger.Errorw("failed to reload api keys", "error", err)
+		}
+	}
+}
+
+// reloadAfterChange reloads keys after they are changed in storage. The change is already persisted,
+// so failure is only logged and the change takes effect on next periodic reload.
+func (m *Manager) reloadAfterChange() {
+	if err := m.Reload(); err != nil {
+		m.sugar.Errorw("failed to reload api keys after change", "error", err)
+	}
+}
+
+// allowedKey returns the key of request if it is allowed to access the endpoint.
+func (m *Manager) allowedKey(r *http.Request) (Key, bool) {
+	keyID, err := permission.GetKeyID(r)
+	if err != nil {
+		return Key{}, false
+	}
+	m.mu.RLock()
+	key, ok := m.keys[string(keyID)]
+	e := m.enforcer
+	m.mu.RUnlock()
+	// key might be expired since last reload
+	if !ok || !key.Active(time.Now()) {
+		return Key{}, false
+	}
+	if strings.HasPrefix(r.URL.Path, AdminPathPrefix) {
+		return key, key.ID == m.adminKeyID
+	}
+	return key, e.Enforce(key.ID, r.URL.Path, r.Method)
+}
+
+func (m *Manager) allowed(r *http.Request) bool {
+	_, ok := m.allowedKey(r)
+	return ok
+}
+
+// Permission returns the middleware that checks if the key of request is allowed to access the endpoint.
+// The reserves of bound keys are passed to upstreams in the reserve scope header.
+func (m *Manager) Permission() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := m.allowedKey(c.Request)
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		if len(key.Reserves) != 0 {
+			c.Request.Header.Set(httputil.ReserveScopeHeader, strings.Join(key.Reserves, ","))
+		}
+	}
+}
+
+// Authenticated returns the middleware that verifies the HTTP signature of request.
+func (m *Manager) Authenticated() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.mu.RLock()
+		auth := m.auth
+		m.mu.RUnlock()
+		auth(c)
+	}
+}
+
+// Create creates a new key with generated id and secret, the created key is returned with its secret.
+func (m *Manager) Create(key Key) (Key, error) {
+	if m.st == nil {
+		return Key{}, ErrStorageNotConfigured
+	}
+	if err := key.Validate(); err != nil {
+		return Key{}, err
+	}
+	id, err := randomHex(16)
+	if err != nil {
+		return Key{}, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return Key{}, err
+	}
+	key.ID = id
+	key.Secret = secret
+	key.Revoked = false
+	key.CreatedAt = time.Now().UTC()
+	if err = m.st.Create(key); err != nil {
+		return Key{}, err
+	}
+	m.reloadAfterChange()
+	return key, nil
+}
+
+// Update updates name, owner, scopes, reserves and expiry of an existing key.
+func (m *Manager) Update(key Key) error {
+	if m.st == nil {
+		return ErrStorageNotConfigured
+	}
+	if err := key.Validate(); err != nil {
+		return err
+	}
+	if err := m.st.Update(key); err != nil {
+		return err
+	}
+	m.reloadAfterChange()
+	return nil
+}
+
+// Revoke revokes the key, it is no longer accepted by gateway.
+func (m *Manager) Revoke(id string) error {
+	if m.st == nil {
+		return ErrStorageNotConfigured
+	}
+	if err := m.st.Revoke(id); err != nil {
+		return err
+	}
+	m.reloadAfterChange()
+	return nil
+}
+
+// Get returns the stored key with given id.
+func (m *Manager) Get(id string) (Key, error) {
+	if m.st == nil {
+		return Key{}, ErrStorageNotConfigured
+	}
+	return m.st.Get(id)
+}
+
+// List returns all stored keys.
+func (m *Manager) List() ([]Key, error) {
+	if m.st == nil {
+		return nil, ErrStorageNotConfigured
+	}
+	return m.st.List()
+}
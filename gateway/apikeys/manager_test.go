@@ -0,0 +1,162 @@
+package apikeys
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/httpsign-utils/sign"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+)
+
+type mockStorage struct {
+	keys map[string]Key
+}
+
+func newMockStorage() *mockStorage {
+	return &mockStorage{keys: make(map[string]Key)}
+}
+
+func (s *mockStorage) Create(key Key) error {
+	s.keys[key.ID] = key
+	return nil
+}
+
+func (s *mockStorage) Update(key Key) error {
+	stored, ok := s.keys[key.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	key.Secret = stored.Secret
+	key.Revoked = stored.Revoked
+	key.CreatedAt = stored.CreatedAt
+	s.keys[key.ID] = key
+	return nil
+}
+
+func (s *mockStorage) Revoke(id string) error {
+	key, ok := s.keys[id]
+	if !ok {
+		return ErrNotFound
+	}
+	key.Revoked = true
+	s.keys[id] = key
+	return nil
+}
+
+func (s *mockStorage) Get(id string) (Key, error) {
+	key, ok := s.keys[id]
+	if !ok {
+		return Key{}, ErrNotFound
+	}
+	return key, nil
+}
+
+func (s *mockStorage) List() ([]Key, error) {
+	var result []Key
+	for _, key := range s.keys {
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func signedRequest(t *testing.T, method, path, keyID, secret string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, path, nil)
+	require.NoError(t, err)
+	req, err = sign.Sign(req, keyID, secret)
+	require.NoError(t, err)
+	return req
+}
+
+func TestManager(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	sugar := logger.Sugar()
+
+	st := newMockStorage()
+	m, err := NewManager(sugar, st, NewStaticKeys("read", "read-secret", "write", "write-secret"), "write")
+	require.NoError(t, err)
+
+	_, err = m.Create(Key{Name: "invalid", Paths: []string{"trade-logs"}, Methods: []string{"GET"}})
+	assert.Error(t, err)
+
+	partner, err := m.Create(Key{
+		Name:    "partner",
+		Owner:   "partner@example.com",
+		Paths:   []string{"/trade-logs", "/reserve/*"},
+		Methods: []string{"get"},
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, partner.ID)
+	assert.NotEmpty(t, partner.Secret)
+	assert.Equal(t, []string{"GET"}, partner.Methods)
+
+	var tests = []struct {
+		msg     string
+		method  string
+		path    string
+		keyID   string
+		allowed bool
+	}{
+		{msg: "read key reads", method: http.MethodGet, path: "/users", keyID: "read", allowed: true},
+		{msg: "read key writes", method: http.MethodPost, path: "/users", keyID: "read", allowed: false},
+		{msg: "write key writes", method: http.MethodPost, path: "/users", keyID: "write", allowed: true},
+		{msg: "read key closes ledger period", method: http.MethodPost, path: "/ledger/periods", keyID: "read", allowed: false},
+		{msg: "write key closes ledger period", method: http.MethodPost, path: "/ledger/periods", keyID: "write", allowed: true},
+		{msg: "partner in scope", method: http.MethodGet, path: "/reserve/tokens", keyID: partner.ID, allowed: true},
+		{msg: "partner out of scope", method: http.MethodGet, path: "/users", keyID: partner.ID, allowed: false},
+		{msg: "partner method out of scope", method: http.MethodPost, path: "/trade-logs", keyID: partner.ID, allowed: false},
+		{msg: "read key admin", method: http.MethodGet, path: "/admin/keys", keyID: "read", allowed: false},
+		{msg: "write key admin", method: http.MethodGet, path: "/admin/keys", keyID: "write", allowed: true},
+		{msg: "unknown key", method: http.MethodGet, path: "/users", keyID: "unknown", allowed: false},
+	}
+	for _, tc := range tests {
+		req := signedRequest(t, tc.method, tc.path, tc.keyID, "")
+		assert.Equal(t, tc.allowed, m.allowed(req), tc.msg)
+	}
+
+	// authenticator is reloaded with new key secret
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = signedRequest(t, http.MethodGet, "/trade-logs", partner.ID, partner.Secret)
+	m.Authenticated()(c)
+	assert.False(t, c.IsAborted())
+
+	partner.Paths = []string{"/users"}
+	require.NoError(t, m.Update(partner))
+	assert.True(t, m.allowed(signedRequest(t, http.MethodGet, "/users", partner.ID, "")))
+	assert.False(t, m.allowed(signedRequest(t, http.MethodGet, "/trade-logs", partner.ID, "")))
+
+	require.NoError(t, m.Revoke(partner.ID))
+	assert.False(t, m.allowed(signedRequest(t, http.MethodGet, "/users", partner.ID, "")))
+	assert.Equal(t, ErrNotFound, m.Revoke("unknown"))
+
+	_, err = m.Create(Key{Name: "invalid reserve", Paths: []string{"/*"}, Methods: []string{"GET"}, Reserves: []string{"0x1"}})
+	assert.Error(t, err)
+	bound, err := m.Create(Key{
+		Name:     "reserve owner",
+		Paths:    []string{"/trade-logs"},
+		Methods:  []string{"GET"},
+		Reserves: []string{"0x63825c174ab367968ec60f061753d3bbd36a0d8f"},
+	})
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = signedRequest(t, http.MethodGet, "/trade-logs", bound.ID, "")
+	m.Permission()(c)
+	assert.False(t, c.IsAborted())
+	assert.Equal(t, "0x63825c174ab367968EC60f061753D3bbD36A0D8F", c.Request.Header.Get(httputil.ReserveScopeHeader))
+
+	expiresAt := time.Now().Add(-time.Minute)
+	expired := Key{ID: "expired", Name: "expired", Paths: []string{"/*"}, Methods: []string{"GET"}, ExpiresAt: &expiresAt}
+	require.NoError(t, st.Create(expired))
+	require.NoError(t, m.Reload())
+	assert.False(t, m.allowed(signedRequest(t, http.MethodGet, "/users", expired.ID, "")))
+}
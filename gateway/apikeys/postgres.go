@@ -0,0 +1,168 @@
+package apikeys
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS "api_keys" (
+	id TEXT PRIMARY KEY,
+	name TEXT NOT NULL,
+	owner TEXT NOT NULL,
+	secret TEXT NOT NULL,
+	paths TEXT[] NOT NULL,
+	methods TEXT[] NOT NULL,
+	expires_at TIMESTAMP,
+	revoked BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP NOT NULL
+);
+ALTER TABLE "api_keys" ADD COLUMN IF NOT EXISTS reserves TEXT[] NOT NULL DEFAULT '{}';
+`
+
+// PostgresStorage stores API keys in PostgreSQL. Secrets are stored as is as they are required
+// to verify HMAC signatures.
+type PostgresStorage struct {
+	sugar *zap.SugaredLogger
+	db    *sqlx.DB
+}
+
+// NewPostgresStorage creates a new PostgresStorage instance and initializes the schema.
+func NewPostgresStorage(sugar *zap.SugaredLogger, db *sqlx.DB) (*PostgresStorage, error) {
+	if _, err := db.Exec(schema); err != nil {
+		sugar.Errorw("failed to init api keys database", "error", err)
+		return nil, err
+	}
+	return &PostgresStorage{sugar: sugar, db: db}, nil
+}
+
+type keyRecord struct {
+	ID        string         `db:"id"`
+	Name      string         `db:"name"`
+	Owner     string         `db:"owner"`
+	Secret    string         `db:"secret"`
+	Paths     pq.StringArray `db:"paths"`
+	Methods   pq.StringArray `db:"methods"`
+	Reserves  pq.StringArray `db:"reserves"`
+	ExpiresAt pq.NullTime    `db:"expires_at"`
+	Revoked   bool           `db:"revoked"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+func (r keyRecord) toKey() Key {
+	key := Key{
+		ID:        r.ID,
+		Name:      r.Name,
+		Owner:     r.Owner,
+		Secret:    r.Secret,
+		Paths:     r.Paths,
+		Methods:   r.Methods,
+		Reserves:  r.Reserves,
+		Revoked:   r.Revoked,
+		CreatedAt: r.CreatedAt,
+	}
+	if r.ExpiresAt.Valid {
+		expiresAt := r.ExpiresAt.Time
+		key.ExpiresAt = &expiresAt
+	}
+	return key
+}
+
+func nullTime(t *time.Time) pq.NullTime {
+	if t == nil {
+		return pq.NullTime{}
+	}
+	return pq.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// reserves returns the reserves as a non null array as the column is not nullable.
+func reserves(addrs []string) pq.StringArray {
+	if addrs == nil {
+		return pq.StringArray{}
+	}
+	return addrs
+}
+
+// Create stores a new key.
+func (s *PostgresStorage) Create(key Key) error {
+	logger := s.sugar.With("func", caller.GetCurrentFunctionName(), "id", key.ID, "name", key.Name)
+	const query = `INSERT INTO api_keys (id, name, owner, secret, paths, methods, reserves, expires_at, revoked, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`
+	logger.Debugw("creating api key", "query", query)
+	_, err := s.db.Exec(query, key.ID, key.Name, key.Owner, key.Secret,
+		pq.StringArray(key.Paths), pq.StringArray(key.Methods), reserves(key.Reserves), nullTime(key.ExpiresAt),
+		key.Revoked, key.CreatedAt.UTC())
+	return err
+}
+
+// Update updates name, owner, scopes, reserves and expiry of an existing key.
+func (s *PostgresStorage) Update(key Key) error {
+	logger := s.sugar.With("func", caller.GetCurrentFunctionName(), "id", key.ID)
+	const query = `UPDATE api_keys SET name = $2, owner = $3, paths = $4, methods = $5, reserves = $6, expires_at = $7
+	WHERE id = $1;`
+	logger.Debugw("updating api key", "query", query)
+	res, err := s.db.Exec(query, key.ID, key.Name, key.Owner,
+		pq.StringArray(key.Paths), pq.StringArray(key.Methods), reserves(key.Reserves), nullTime(key.ExpiresAt))
+	if err != nil {
+		return err
+	}
+	return checkAffected(res)
+}
+
+// Revoke marks the key as revoked. Revoked keys are kept for auditing.
+func (s *PostgresStorage) Revoke(id string) error {
+	logger := s.sugar.With("func", caller.GetCurrentFunctionName(), "id", id)
+	const query = `UPDATE api_keys SET revoked = TRUE WHERE id = $1;`
+	logger.Debugw("revoking api key", "query", query)
+	res, err := s.db.Exec(query, id)
+	if err != nil {
+		return err
+	}
+	return checkAffected(res)
+}
+
+func checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Get returns the key with given id.
+func (s *PostgresStorage) Get(id string) (Key, error) {
+	var record keyRecord
+	const query = `SELECT * FROM api_keys WHERE id = $1;`
+	if err := s.db.Get(&record, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return Key{}, ErrNotFound
+		}
+		return Key{}, err
+	}
+	return record.toKey(), nil
+}
+
+// List returns all stored keys.
+func (s *PostgresStorage) List() ([]Key, error) {
+	var (
+		records []keyRecord
+		result  = make([]Key, 0)
+	)
+	const query = `SELECT * FROM api_keys ORDER BY created_at;`
+	if err := s.db.Select(&records, query); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		result = append(result, record.toKey())
+	}
+	return result, nil
+}
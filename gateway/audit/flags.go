@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+)
+
+const enabledFlag = "audit-log-enabled"
+
+// NewCliFlags returns cli flags to configure audit logging. The records are stored in the PostgreSQL
+// database of API keys, which flags are returned by apikeys.NewCliFlags.
+func NewCliFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.BoolFlag{
+			Name:   enabledFlag,
+			Usage:  "record mutating requests in PostgreSQL audit log",
+			EnvVar: "AUDIT_LOG_ENABLED",
+		},
+	}
+}
+
+// NewStorageFromContext creates the audit log storage from cli flags, it returns nil if audit logging is disabled.
+func NewStorageFromContext(c *cli.Context, sugar *zap.SugaredLogger) (Storage, error) {
+	if !c.Bool(enabledFlag) {
+		return nil, nil
+	}
+	db, err := libapp.NewDBFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+	st, err := NewPostgresStorage(sugar, db)
+	if err != nil {
+		return nil, err
+	}
+	return st, nil
+}
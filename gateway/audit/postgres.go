@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+	id BIGSERIAL PRIMARY KEY,
+	key_id TEXT NOT NULL,
+	timestamp TIMESTAMP NOT NULL,
+	method TEXT NOT NULL,
+	path TEXT NOT NULL,
+	query TEXT NOT NULL,
+	body_hash TEXT NOT NULL,
+	body TEXT NOT NULL,
+	status INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS "audit_logs_timestamp_idx" ON "audit_logs" (timestamp);
+CREATE INDEX IF NOT EXISTS "audit_logs_key_id_idx" ON "audit_logs" (key_id);
+--records are append-only
+CREATE OR REPLACE FUNCTION audit_logs_append_only() RETURNS TRIGGER AS
+$$
+BEGIN
+	RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE PLPGSQL;
+DROP TRIGGER IF EXISTS audit_logs_append_only_trigger ON audit_logs;
+CREATE TRIGGER audit_logs_append_only_trigger
+	BEFORE UPDATE OR DELETE
+	ON audit_logs
+	FOR EACH ROW
+EXECUTE PROCEDURE audit_logs_append_only();
+DROP TRIGGER IF EXISTS audit_logs_no_truncate_trigger ON audit_logs;
+CREATE TRIGGER audit_logs_no_truncate_trigger
+	BEFORE TRUNCATE
+	ON audit_logs
+	FOR EACH STATEMENT
+EXECUTE PROCEDURE audit_logs_append_only();
+`
+
+// PostgresStorage stores audit records in PostgreSQL, updating or deleting records is rejected by triggers.
+type PostgresStorage struct {
+	sugar *zap.SugaredLogger
+	db    *sqlx.DB
+}
+
+// NewPostgresStorage creates a new PostgresStorage instance and initializes the schema.
+func NewPostgresStorage(sugar *zap.SugaredLogger, db *sqlx.DB) (*PostgresStorage, error) {
+	if _, err := db.Exec(schema); err != nil {
+		sugar.Errorw("failed to init audit logs database", "error", err)
+		return nil, err
+	}
+	return &PostgresStorage{sugar: sugar, db: db}, nil
+}
+
+type auditRecord struct {
+	ID        uint64    `db:"id"`
+	KeyID     string    `db:"key_id"`
+	Timestamp time.Time `db:"timestamp"`
+	Method    string    `db:"method"`
+	Path      string    `db:"path"`
+	Query     string    `db:"query"`
+	BodyHash  string    `db:"body_hash"`
+	Body      string    `db:"body"`
+	Status    int       `db:"status"`
+}
+
+// Store inserts the record and returns its id.
+func (s *PostgresStorage) Store(r Record) (uint64, error) {
+	const query = `INSERT INTO audit_logs (key_id, timestamp, method, path, query, body_hash, body, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	var id uint64
+	err := s.db.Get(&id, query, r.KeyID, r.Timestamp.UTC(), r.Method, r.Path, r.Query, r.BodyHash, r.Body, r.Status)
+	return id, err
+}
+
+// Records returns the records matching the filter, latest first.
+func (s *PostgresStorage) Records(f Filter) ([]Record, error) {
+	const query = `SELECT id, key_id, timestamp, method, path, query, body_hash, body, status
+FROM audit_logs
+WHERE timestamp >= $1
+  AND timestamp <= $2
+  AND ($3 = '' OR key_id = $3)
+  AND left(path, length($4)) = $4
+  AND ($5 = '' OR method = $5)
+ORDER BY id DESC
+LIMIT $6`
+	logger := s.sugar.With("func", caller.GetCurrentFunctionName(), "from", f.From, "to", f.To)
+	var rows []auditRecord
+	if err := s.db.Select(&rows, query, f.From.UTC(), f.To.UTC(), f.KeyID, f.PathPrefix, f.Method,
+		limitOrDefault(f.Limit)); err != nil {
+		logger.Errorw("failed to query audit records", "error", err)
+		return nil, err
+	}
+	records := make([]Record, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, Record(row))
+	}
+	return records, nil
+}
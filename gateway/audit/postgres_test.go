@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+)
+
+func TestPostgresStorage(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	db, teardown := testutil.MustNewDevelopmentDB()
+	defer func() {
+		assert.NoError(t, teardown())
+	}()
+
+	st, err := NewPostgresStorage(logger.Sugar(), db)
+	require.NoError(t, err)
+	// schema initialization is idempotent
+	_, err = NewPostgresStorage(logger.Sugar(), db)
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Millisecond)
+	first := NewRecord("write", "POST", "/applications", nil, "application/json", []byte(`{"name":"a"}`), 200, now)
+	firstID, err := st.Store(first)
+	require.NoError(t, err)
+	second := NewRecord("other", "DELETE", "/addresses/1", nil, "", nil, 404, now.Add(time.Second))
+	_, err = st.Store(second)
+	require.NoError(t, err)
+
+	records, err := st.Records(Filter{From: now.Add(-time.Minute), To: now.Add(time.Minute)})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "/addresses/1", records[0].Path)
+	assert.Equal(t, firstID, records[1].ID)
+	assert.Equal(t, first.Body, records[1].Body)
+	assert.True(t, now.Equal(records[1].Timestamp))
+
+	records, err = st.Records(Filter{From: now.Add(-time.Minute), To: now.Add(time.Minute), KeyID: "write", PathPrefix: "/app"})
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, firstID, records[0].ID)
+
+	records, err = st.Records(Filter{From: now.Add(-time.Minute), To: now.Add(time.Minute), Method: "PUT"})
+	require.NoError(t, err)
+	assert.Len(t, records, 0)
+
+	// records could not be modified
+	_, err = db.Exec(`UPDATE audit_logs SET status = 500 WHERE id = $1`, firstID)
+	assert.Error(t, err)
+	_, err = db.Exec(`DELETE FROM audit_logs`)
+	assert.Error(t, err)
+	_, err = db.Exec(`TRUNCATE audit_logs`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,124 @@
+package audit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	// MaxBodySize is the maximum size of body stored in a record, the body hash is computed from the
+	// whole body.
+	MaxBodySize = 64 * 1024
+	truncated   = "\n...(truncated)"
+
+	defaultLimit = 100
+	// MaxLimit is the maximum number of records returned by a query.
+	MaxLimit = 1000
+)
+
+// Record is an audit record of a mutating request through gateway.
+type Record struct {
+	ID        uint64    `json:"id"`
+	KeyID     string    `json:"key_id"`
+	Timestamp time.Time `json:"-"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Query     string    `json:"query"`
+	// BodyHash is the hex encoded SHA-256 hash of request body.
+	BodyHash string `json:"body_hash"`
+	// Body is the normalized request body: JSON is indented with sorted keys and form is sorted by keys,
+	// so bodies of records could be diffed.
+	Body string `json:"body"`
+	// Status is the response status of upstream.
+	Status int `json:"status"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Record to format timestamp in unix millis.
+func (r Record) MarshalJSON() ([]byte, error) {
+	type AliasRecord Record
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasRecord
+	}{
+		AliasRecord: (AliasRecord)(r),
+		Timestamp:   timeutil.TimeToTimestampMs(r.Timestamp),
+	})
+}
+
+// NewRecord creates an audit record of a request, the query is normalized like the body. The timestamp
+// is truncated to millis, the precision of query time range.
+func NewRecord(keyID, method, path string, query url.Values, contentType string, body []byte, status int, timestamp time.Time) Record {
+	sum := sha256.Sum256(body)
+	return Record{
+		KeyID:     keyID,
+		Timestamp: timestamp.Truncate(time.Millisecond),
+		Method:    method,
+		Path:      path,
+		Query:     query.Encode(),
+		BodyHash:  hex.EncodeToString(sum[:]),
+		Body:      normalizeBody(contentType, body),
+		Status:    status,
+	}
+}
+
+func normalizeBody(contentType string, body []byte) string {
+	normalized := string(body)
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(normalized); err == nil {
+			// Encode sorts by key
+			normalized = values.Encode()
+		}
+	case json.Valid(body):
+		var v interface{}
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&v); err == nil {
+			// maps are marshaled with sorted keys
+			if indented, err := json.MarshalIndent(v, "", "  "); err == nil {
+				normalized = string(indented)
+			}
+		}
+	}
+	if len(normalized) > MaxBodySize {
+		normalized = normalized[:MaxBodySize] + truncated
+	}
+	return normalized
+}
+
+// Filter is the condition of records query.
+type Filter struct {
+	From  time.Time
+	To    time.Time
+	KeyID string
+	// PathPrefix matches records with path starting with it.
+	PathPrefix string
+	Method     string
+	// Limit is the maximum number of returned records, the latest records are returned first.
+	Limit int
+}
+
+// Storage stores audit records, records could not be modified after stored.
+type Storage interface {
+	Store(r Record) (uint64, error)
+	Records(f Filter) ([]Record, error)
+}
+
+func limitOrDefault(limit int) int {
+	switch {
+	case limit <= 0:
+		return defaultLimit
+	case limit > MaxLimit:
+		return MaxLimit
+	default:
+		return limit
+	}
+}
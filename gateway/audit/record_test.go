@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRecord(t *testing.T) {
+	now := time.Now()
+	r := NewRecord("key", "POST", "/applications", url.Values{"b": {"2"}, "a": {"1"}},
+		"application/json; charset=utf-8", []byte(`{"name":"app","addresses":["0x1"],"amount":1.50}`), 200, now)
+	assert.Equal(t, "a=1&b=2", r.Query)
+	assert.Equal(t, `{
+  "addresses": [
+    "0x1"
+  ],
+  "amount": 1.50,
+  "name": "app"
+}`, r.Body)
+	assert.Len(t, r.BodyHash, 64)
+
+	// hash is computed from raw body
+	other := NewRecord("key", "POST", "/applications", nil,
+		"application/json", []byte(`{"amount":1.50,"name":"app","addresses":["0x1"]}`), 200, now)
+	assert.Equal(t, r.Body, other.Body)
+	assert.NotEqual(t, r.BodyHash, other.BodyHash)
+
+	form := NewRecord("key", "PUT", "/big-trades", nil,
+		"application/x-www-form-urlencoded", []byte("threshold=10&address=0x1"), 200, now)
+	assert.Equal(t, "address=0x1&threshold=10", form.Body)
+
+	large := NewRecord("key", "POST", "/addresses", nil,
+		"text/plain", []byte(strings.Repeat("a", MaxBodySize+1)), 200, now)
+	assert.Len(t, large.Body, MaxBodySize+len(truncated))
+}
+
+func TestLimitOrDefault(t *testing.T) {
+	assert.Equal(t, defaultLimit, limitOrDefault(0))
+	assert.Equal(t, 10, limitOrDefault(10))
+	assert.Equal(t, MaxLimit, limitOrDefault(MaxLimit+1))
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/casbin/casbin/util"
+)
+
+const toParam = "to"
+
+// Entry is a cached response.
+type Entry struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag"`
+	Body        []byte `json:"body"`
+}
+
+// NewEntry creates a new cache entry, the ETag is computed from body.
+func NewEntry(status int, contentType string, body []byte) Entry {
+	sum := sha1.Sum(body)
+	return Entry{
+		Status:      status,
+		ContentType: contentType,
+		ETag:        fmt.Sprintf(`"%s"`, hex.EncodeToString(sum[:])),
+		Body:        body,
+	}
+}
+
+// Store stores cached responses.
+type Store interface {
+	// Get returns the entry of given key, nil if not found or expired.
+	Get(key string) (*Entry, error)
+	Set(key string, entry Entry, ttl time.Duration) error
+	// Purge removes all entries with keys having given prefix and returns the number of removed entries.
+	Purge(prefix string) (int, error)
+}
+
+// Key returns the cache key of a request from its path and query. Query parameters are sorted
+// so the same query in different order has the same key.
+func Key(path string, query url.Values) string {
+	normalized := make(url.Values, len(query))
+	for k, values := range query {
+		sorted := make([]string, len(values))
+		copy(sorted, values)
+		sort.Strings(sorted)
+		normalized[k] = sorted
+	}
+	if len(normalized) == 0 {
+		return path
+	}
+	// Encode sorts by key
+	return path + "?" + normalized.Encode()
+}
+
+// Policy decides which requests are cached and for how long.
+type Policy struct {
+	// Paths are keyMatch patterns of cached routes.
+	Paths []string
+	// ClosedRangeTTL is the TTL of responses of time range that ended before now minus Margin,
+	// the data of such ranges are not expected to change.
+	ClosedRangeTTL time.Duration
+	// OpenRangeTTL is the TTL of other responses.
+	OpenRangeTTL time.Duration
+	// Margin is the duration data of a past time range might still be updated, for example
+	// when crawlers are catching up.
+	Margin time.Duration
+}
+
+// Cacheable returns true if responses of given path are cached.
+func (p Policy) Cacheable(path string) bool {
+	for _, pattern := range p.Paths {
+		if util.KeyMatch(path, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// TTL returns the TTL of a response to given query, which has time range in from and to parameters
+// in milliseconds. Request without to parameter is considered as ending at now.
+func (p Policy) TTL(query url.Values, now time.Time) time.Duration {
+	to, err := strconv.ParseInt(query.Get(toParam), 10, 64)
+	if err != nil {
+		return p.OpenRangeTTL
+	}
+	if time.Unix(0, to*int64(time.Millisecond)).Before(now.Add(-p.Margin)) {
+		return p.ClosedRangeTTL
+	}
+	return p.OpenRangeTTL
+}
+
+// MatchETag returns true if the If-None-Match header value matches the ETag.
+func MatchETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func TestKey(t *testing.T) {
+	q1, err := url.ParseQuery("to=2&from=1&reserve=0xb&reserve=0xa")
+	require.NoError(t, err)
+	q2, err := url.ParseQuery("reserve=0xa&from=1&reserve=0xb&to=2")
+	require.NoError(t, err)
+	assert.Equal(t, Key("/reserve-rates", q1), Key("/reserve-rates", q2))
+	assert.Equal(t, "/reserve-rates?from=1&reserve=0xa&reserve=0xb&to=2", Key("/reserve-rates", q1))
+	assert.Equal(t, "/stats", Key("/stats", url.Values{}))
+}
+
+func TestPolicyTTL(t *testing.T) {
+	var (
+		now    = time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+		policy = Policy{
+			Paths:          []string{"/stats", "/top-*"},
+			ClosedRangeTTL: time.Hour,
+			OpenRangeTTL:   time.Minute,
+			Margin:         10 * time.Minute,
+		}
+		toParamOf = func(t time.Time) url.Values {
+			return url.Values{toParam: []string{strconv.FormatUint(timeutil.TimeToTimestampMs(t), 10)}}
+		}
+	)
+	assert.True(t, policy.Cacheable("/top-tokens"))
+	assert.False(t, policy.Cacheable("/users"))
+
+	assert.Equal(t, time.Minute, policy.TTL(url.Values{}, now))
+	assert.Equal(t, time.Minute, policy.TTL(toParamOf(now.Add(-5*time.Minute)), now))
+	assert.Equal(t, time.Hour, policy.TTL(toParamOf(now.Add(-time.Hour)), now))
+}
+
+func TestLRUStore(t *testing.T) {
+	store, err := NewLRUStore(10)
+	require.NoError(t, err)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	entry := NewEntry(200, "application/json", []byte(`{}`))
+	require.NoError(t, store.Set("/stats?from=1", entry, time.Minute))
+	require.NoError(t, store.Set("/stats?from=2", entry, time.Hour))
+	require.NoError(t, store.Set("/top-tokens", entry, time.Hour))
+
+	cached, err := store.Get("/stats?from=1")
+	require.NoError(t, err)
+	require.NotNil(t, cached)
+	assert.Equal(t, entry, *cached)
+	assert.True(t, MatchETag(`W/"other", `+entry.ETag, cached.ETag))
+
+	now = now.Add(2 * time.Minute)
+	cached, err = store.Get("/stats?from=1")
+	require.NoError(t, err)
+	assert.Nil(t, cached)
+
+	purged, err := store.Purge("/stats")
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	cached, err = store.Get("/top-tokens")
+	require.NoError(t, err)
+	assert.NotNil(t, cached)
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli"
+
+	libredis "github.com/KyberNetwork/reserve-stats/lib/redis"
+)
+
+const (
+	storageFlag   = "cache-storage"
+	lruStorage    = "lru"
+	redisStorage  = "redis"
+	lruSizeFlag   = "cache-lru-size"
+	pathsFlag     = "cache-paths"
+	closedTTLFlag = "cache-closed-range-ttl"
+	openTTLFlag   = "cache-open-range-ttl"
+	marginFlag    = "cache-closed-range-margin"
+
+	defaultLRUSize   = 10000
+	defaultClosedTTL = 24 * time.Hour
+	defaultOpenTTL   = 30 * time.Second
+	defaultMargin    = 10 * time.Minute
+)
+
+// defaultPaths are the report endpoints that are queried with the same time ranges repeatedly.
+var defaultPaths = []string{
+	"/stats",
+	"/top-reserves",
+	"/top-tokens",
+	"/top-integrations",
+	"/reserve-rates",
+	"/trade-logs",
+}
+
+// NewCliFlags returns cli flags to configure response caching. Redis connection is configured
+// with lib/redis flags.
+func NewCliFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   storageFlag,
+			Usage:  fmt.Sprintf("response cache storage, %s or %s, caching is disabled if not provided", lruStorage, redisStorage),
+			EnvVar: "CACHE_STORAGE",
+		},
+		cli.IntFlag{
+			Name:   lruSizeFlag,
+			Usage:  "maximum number of responses in LRU cache",
+			EnvVar: "CACHE_LRU_SIZE",
+			Value:  defaultLRUSize,
+		},
+		cli.StringFlag{
+			Name:   pathsFlag,
+			Usage:  "comma separated path patterns of cached endpoints",
+			EnvVar: "CACHE_PATHS",
+			Value:  strings.Join(defaultPaths, ","),
+		},
+		cli.DurationFlag{
+			Name:   closedTTLFlag,
+			Usage:  "TTL of responses of time ranges in the past",
+			EnvVar: "CACHE_CLOSED_RANGE_TTL",
+			Value:  defaultClosedTTL,
+		},
+		cli.DurationFlag{
+			Name:   openTTLFlag,
+			Usage:  "TTL of responses of time ranges touching now",
+			EnvVar: "CACHE_OPEN_RANGE_TTL",
+			Value:  defaultOpenTTL,
+		},
+		cli.DurationFlag{
+			Name:   marginFlag,
+			Usage:  "time ranges ended within this duration from now are considered touching now",
+			EnvVar: "CACHE_CLOSED_RANGE_MARGIN",
+			Value:  defaultMargin,
+		},
+	}
+}
+
+// NewStoreFromContext creates the cache store from cli flags, it returns nil if caching is disabled.
+func NewStoreFromContext(c *cli.Context) (Store, error) {
+	switch c.String(storageFlag) {
+	case "":
+		return nil, nil
+	case lruStorage:
+		return NewLRUStore(c.Int(lruSizeFlag))
+	case redisStorage:
+		client, err := libredis.NewClientFromContext(c)
+		if err != nil {
+			return nil, err
+		}
+		return NewRedisStore(client), nil
+	default:
+		return nil, fmt.Errorf("unsupported cache storage %s", c.String(storageFlag))
+	}
+}
+
+// NewPolicyFromContext creates the cache policy from cli flags.
+func NewPolicyFromContext(c *cli.Context) Policy {
+	var paths []string
+	for _, path := range strings.Split(c.String(pathsFlag), ",") {
+		if path = strings.TrimSpace(path); len(path) != 0 {
+			paths = append(paths, path)
+		}
+	}
+	return Policy{
+		Paths:          paths,
+		ClosedRangeTTL: c.Duration(closedTTLFlag),
+		OpenRangeTTL:   c.Duration(openTTLFlag),
+		Margin:         c.Duration(marginFlag),
+	}
+}
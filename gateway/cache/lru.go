@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+type lruItem struct {
+	entry     Entry
+	expiresAt time.Time
+}
+
+// LRUStore keeps responses in memory, least recently used entries are evicted when it is full.
+type LRUStore struct {
+	c   *lru.Cache
+	now func() time.Time
+}
+
+// NewLRUStore creates a new LRUStore instance that holds at most size entries.
+func NewLRUStore(size int) (*LRUStore, error) {
+	c, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &LRUStore{c: c, now: time.Now}, nil
+}
+
+// Get returns the entry of given key, nil if not found or expired.
+func (s *LRUStore) Get(key string) (*Entry, error) {
+	value, ok := s.c.Get(key)
+	if !ok {
+		return nil, nil
+	}
+	item := value.(lruItem)
+	if !s.now().Before(item.expiresAt) {
+		s.c.Remove(key)
+		return nil, nil
+	}
+	return &item.entry, nil
+}
+
+// Set stores the entry with given TTL.
+func (s *LRUStore) Set(key string, entry Entry, ttl time.Duration) error {
+	s.c.Add(key, lruItem{entry: entry, expiresAt: s.now().Add(ttl)})
+	return nil
+}
+
+// Purge removes all entries with keys having given prefix.
+func (s *LRUStore) Purge(prefix string) (int, error) {
+	var purged int
+	for _, key := range s.c.Keys() {
+		if k, ok := key.(string); ok && strings.HasPrefix(k, prefix) {
+			s.c.Remove(k)
+			purged++
+		}
+	}
+	return purged, nil
+}
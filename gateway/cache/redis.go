@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const (
+	redisKeyPrefix = "gateway:cache:"
+	scanCount      = 1000
+)
+
+// globEscaper escapes special characters of Redis glob-style pattern.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// RedisStore keeps responses in Redis, so they are shared between gateway instances.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a new RedisStore instance.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Get returns the entry of given key, nil if not found or expired.
+func (s *RedisStore) Get(key string) (*Entry, error) {
+	data, err := s.client.Get(redisKeyPrefix + key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var entry Entry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// Set stores the entry with given TTL.
+func (s *RedisStore) Set(key string, entry Entry, ttl time.Duration) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(redisKeyPrefix+key, data, ttl).Err()
+}
+
+// Purge removes all entries with keys having given prefix.
+func (s *RedisStore) Purge(prefix string) (int, error) {
+	var (
+		cursor uint64
+		purged int
+		match  = redisKeyPrefix + globEscaper.Replace(prefix) + "*"
+	)
+	for {
+		keys, next, err := s.client.Scan(cursor, match, scanCount).Result()
+		if err != nil {
+			return purged, err
+		}
+		if len(keys) != 0 {
+			deleted, err := s.client.Del(keys...).Result()
+			if err != nil {
+				return purged, err
+			}
+			purged += int(deleted)
+		}
+		if next == 0 {
+			return purged, nil
+		}
+		cursor = next
+	}
+}
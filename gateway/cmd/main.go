@@ -9,13 +9,20 @@ import (
 	"github.com/go-ozzo/ozzo-validation/is"
 	"github.com/urfave/cli"
 
-	"github.com/KyberNetwork/httpsign-utils/authenticator"
+	"github.com/KyberNetwork/reserve-stats/gateway/apikeys"
+	"github.com/KyberNetwork/reserve-stats/gateway/audit"
+	"github.com/KyberNetwork/reserve-stats/gateway/cache"
 	"github.com/KyberNetwork/reserve-stats/gateway/http"
+	"github.com/KyberNetwork/reserve-stats/gateway/ratelimit"
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	libredis "github.com/KyberNetwork/reserve-stats/lib/redis"
 )
 
 const (
+	defaultAPIKeysDB = "gateway"
+
 	writeAccessKeyFlag = "write-access-key"
 	writeSecretKeyFlag = "write-secret-key"
 	readAccessKeyFlag  = "read-access-key"
@@ -96,6 +103,13 @@ func main() {
 		},
 	)
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.GatewayPort)...)
+	app.Flags = append(app.Flags, apikeys.NewCliFlags(defaultAPIKeysDB)...)
+	app.Flags = append(app.Flags, audit.NewCliFlags()...)
+	app.Flags = append(app.Flags, ratelimit.NewCliFlags()...)
+	app.Flags = append(app.Flags, cache.NewCliFlags()...)
+	app.Flags = append(app.Flags, libredis.NewCliFlags()...)
+	app.Flags = append(app.Flags, http.NewRouteTableCliFlags()...)
+	app.Flags = append(app.Flags, upstream.NewCliFlags()...)
 
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
@@ -109,78 +123,116 @@ func run(c *cli.Context) error {
 	}
 	defer libapp.NewFlusher(logger)()
 
-	err = validation.Validate(c.String(tradeLogsAPIURLFlag),
+	if err := validation.Validate(c.String(writeAccessKeyFlag), validation.Required); err != nil {
+		return fmt.Errorf("access key error: %s", err.Error())
+	}
+
+	if err := validation.Validate(c.String(writeSecretKeyFlag), validation.Required); err != nil {
+		return fmt.Errorf("secret key error: %s", err.Error())
+	}
+	staticKeys := apikeys.NewStaticKeys(
+		c.String(readAccessKeyFlag), c.String(readSecretKeyFlag),
+		c.String(writeAccessKeyFlag), c.String(writeSecretKeyFlag),
+	)
+	keyManager, err := apikeys.NewManagerFromContext(c, logger.Sugar(), staticKeys, c.String(writeAccessKeyFlag))
+	if err != nil {
+		return fmt.Errorf("api keys manager creation error: %s", err)
+	}
+	if keyManager.HasStorage() {
+		go keyManager.Run(apikeys.ReloadIntervalFromContext(c))
+	}
+	auditStorage, err := audit.NewStorageFromContext(c, logger.Sugar())
+	if err != nil {
+		return fmt.Errorf("audit log storage creation error: %s", err)
+	}
+	limiter, err := ratelimit.NewLimiterFromContext(c, logger.Sugar())
+	if err != nil {
+		return fmt.Errorf("rate limiter creation error: %s", err)
+	}
+	cacheStore, err := cache.NewStoreFromContext(c)
+	if err != nil {
+		return fmt.Errorf("response cache creation error: %s", err)
+	}
+	routeTable, err := http.NewRouteTableFromContext(c)
+	if err != nil {
+		return err
+	}
+	// upstream defaults, rate limiter, audit log and response cache must be registered before routes
+	options := []http.Option{http.WithUpstreamDefaults(upstream.NewDefaultConfigFromContext(c))}
+	if limiter != nil {
+		options = append(options, http.WithRateLimiter(limiter))
+	}
+	if auditStorage != nil {
+		options = append(options, http.WithAuditLog(logger.Sugar(), auditStorage))
+	}
+	// the route table has cache policy per route
+	if cacheStore != nil && routeTable == nil {
+		options = append(options, http.WithResponseCache(logger.Sugar(), cacheStore, cache.NewPolicyFromContext(c)))
+	}
+	options = append(options, http.WithAPIKeyManager(keyManager))
+	if routeTable != nil {
+		options = append(options, http.WithRouteTable(logger.Sugar(), *routeTable, cacheStore))
+	} else {
+		urlOptions, err := newURLRouteOptions(c)
+		if err != nil {
+			return err
+		}
+		options = append(options, urlOptions...)
+	}
+	svr, err := http.NewServer(httputil.NewHTTPAddressFromContext(c),
+		keyManager.Authenticated(),
+		keyManager.Permission(),
+		logger,
+		options...,
+	)
+	if err != nil {
+		return err
+	}
+	return svr.Start()
+}
+
+// newURLRouteOptions returns the routes of upstream URL flags, which are used if the route table is not configured.
+func newURLRouteOptions(c *cli.Context) ([]http.Option, error) {
+	err := validation.Validate(c.String(tradeLogsAPIURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid trades log API URL: %s", c.String(tradeLogsAPIURLFlag))
+		return nil, fmt.Errorf("invalid trades log API URL: %s", c.String(tradeLogsAPIURLFlag))
 	}
 
 	err = validation.Validate(c.String(reserveRatesAPIURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid reserve rates API URL: %s", c.String(reserveRatesAPIURLFlag))
+		return nil, fmt.Errorf("invalid reserve rates API URL: %s", c.String(reserveRatesAPIURLFlag))
 	}
 
 	err = validation.Validate(c.String(userAPIURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid user API URL: %s", c.String(userAPIURLFlag))
+		return nil, fmt.Errorf("invalid user API URL: %s", c.String(userAPIURLFlag))
 	}
 
 	err = validation.Validate(c.String(priceAnalyticURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("invalid price analytic API URL: %s", c.String(priceAnalyticURLFlag))
+		return nil, fmt.Errorf("invalid price analytic API URL: %s", c.String(priceAnalyticURLFlag))
 	}
 
 	err = validation.Validate(c.String(appNamesURLFlag),
 		validation.Required,
 		is.URL)
 	if err != nil {
-		return fmt.Errorf("app names API URL: %s", c.String(priceAnalyticURLFlag))
-	}
-
-	if err := validation.Validate(c.String(writeAccessKeyFlag), validation.Required); err != nil {
-		return fmt.Errorf("access key error: %s", err.Error())
+		return nil, fmt.Errorf("app names API URL: %s", c.String(priceAnalyticURLFlag))
 	}
 
-	if err := validation.Validate(c.String(writeSecretKeyFlag), validation.Required); err != nil {
-		return fmt.Errorf("secret key error: %s", err.Error())
-	}
-	keyPairs := []authenticator.KeyPair{
-		{
-			AccessKeyID:     c.String(readAccessKeyFlag),
-			SecretAccessKey: c.String(readSecretKeyFlag),
-		},
-		{
-			AccessKeyID:     c.String(writeAccessKeyFlag),
-			SecretAccessKey: c.String(writeSecretKeyFlag),
-		},
-	}
-	auth, err := authenticator.NewAuthenticator(keyPairs...)
-	if err != nil {
-		return fmt.Errorf("authentication object creation error: %s", err)
-	}
-	perm, err := http.NewPermissioner(c.String(readAccessKeyFlag), c.String(writeAccessKeyFlag))
-	if err != nil {
-		return fmt.Errorf("permission object creation error: %s", err)
-	}
-	svr, err := http.NewServer(httputil.NewHTTPAddressFromContext(c),
-		auth,
-		perm,
-		logger,
+	return []http.Option{
 		http.WithTradeLogURL(c.String(tradeLogsAPIURLFlag)),
 		http.WithReserveRatesURL(c.String(reserveRatesAPIURLFlag)),
 		http.WithPriceAnalyticURL(c.String(priceAnalyticURLFlag)),
 		http.WithUserURL(c.String(userAPIURLFlag)),
 		http.WithAppNamesURL(c.String(appNamesURLFlag)),
-	)
-	if err != nil {
-		return err
-	}
-	return svr.Start()
+	}, nil
 }
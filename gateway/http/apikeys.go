@@ -0,0 +1,185 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/apikeys"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+var errExpiresInPast = errors.New("expires_at must be in the future")
+
+type apiKeyRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Owner   string   `json:"owner"`
+	Paths   []string `json:"paths" binding:"required"`
+	Methods []string `json:"methods" binding:"required"`
+	// Reserves are the addresses of reserves the key is bound to, the key is not bound if it is omitted.
+	Reserves []string `json:"reserves"`
+	// ExpiresAt is the expiry time in millis, the key never expires if it is omitted.
+	ExpiresAt *uint64 `json:"expires_at"`
+}
+
+func (r apiKeyRequest) key(id string) apikeys.Key {
+	key := apikeys.Key{
+		ID:       id,
+		Name:     r.Name,
+		Owner:    r.Owner,
+		Paths:    r.Paths,
+		Methods:  r.Methods,
+		Reserves: r.Reserves,
+	}
+	if r.ExpiresAt != nil {
+		expiresAt := timeutil.TimestampMsToTime(*r.ExpiresAt)
+		key.ExpiresAt = &expiresAt
+	}
+	return key
+}
+
+// createdAPIKey is the response of key creation, the only time secret is returned.
+type createdAPIKey struct {
+	apikeys.Key
+	Secret string `json:"secret"`
+}
+
+// MarshalJSON adds secret to the JSON of key, as the promoted MarshalJSON of apikeys.Key omits it.
+func (k createdAPIKey) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(k.Key)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if fields["secret"], err = json.Marshal(k.Secret); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+type revokedAPIKey struct {
+	ID      string `json:"id"`
+	Revoked bool   `json:"revoked"`
+}
+
+func apiKeyErrorStatus(err error) int {
+	if err == apikeys.ErrNotFound {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+type apiKeysHandler struct {
+	m *apikeys.Manager
+}
+
+func (h apiKeysHandler) list(c *gin.Context) {
+	keys, err := h.m.List()
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h apiKeysHandler) get(c *gin.Context) {
+	key, err := h.m.Get(c.Param("id"))
+	if err != nil {
+		httputil.ResponseFailure(c, apiKeyErrorStatus(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, key)
+}
+
+func (h apiKeysHandler) create(c *gin.Context) {
+	var req apiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	key := req.key("")
+	if err := key.Validate(); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		httputil.ResponseFailure(c, http.StatusBadRequest, errExpiresInPast)
+		return
+	}
+	created, err := h.m.Create(key)
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusCreated, createdAPIKey{Key: created, Secret: created.Secret})
+}
+
+func (h apiKeysHandler) update(c *gin.Context) {
+	var req apiKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	key := req.key(c.Param("id"))
+	if err := key.Validate(); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	if err := h.m.Update(key); err != nil {
+		httputil.ResponseFailure(c, apiKeyErrorStatus(err), err)
+		return
+	}
+	h.get(c)
+}
+
+func (h apiKeysHandler) revoke(c *gin.Context) {
+	if err := h.m.Revoke(c.Param("id")); err != nil {
+		httputil.ResponseFailure(c, apiKeyErrorStatus(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, revokedAPIKey{ID: c.Param("id"), Revoked: true})
+}
+
+// WithAPIKeyManager registers the admin API to manage API keys stored by given manager.
+// The permission middleware of manager only allows the admin key to access these endpoints.
+func WithAPIKeyManager(m *apikeys.Manager) Option {
+	return func(s *Server) error {
+		if !m.HasStorage() {
+			return nil
+		}
+		h := apiKeysHandler{m: m}
+		path := apikeys.AdminPathPrefix + "keys"
+		s.handle(http.MethodGet, path, openapi.Endpoint{
+			Summary:  "all API keys",
+			Response: []apikeys.Key{},
+		}, h.list)
+		s.handle(http.MethodPost, path, openapi.Endpoint{
+			Summary:  "create API key, the secret is only returned on creation",
+			Body:     apiKeyRequest{},
+			Response: createdAPIKey{},
+			Status:   http.StatusCreated,
+		}, h.create)
+		s.handle(http.MethodGet, path+"/:id", openapi.Endpoint{
+			Summary:  "API key of id",
+			Response: apikeys.Key{},
+		}, h.get)
+		s.handle(http.MethodPut, path+"/:id", openapi.Endpoint{
+			Summary:  "update API key",
+			Body:     apiKeyRequest{},
+			Response: apikeys.Key{},
+		}, h.update)
+		s.handle(http.MethodDelete, path+"/:id", openapi.Endpoint{
+			Summary:  "revoke API key",
+			Response: revokedAPIKey{},
+		}, h.revoke)
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/apikeys"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+type memoryAPIKeyStorage struct {
+	keys map[string]apikeys.Key
+}
+
+func (s *memoryAPIKeyStorage) Create(key apikeys.Key) error {
+	s.keys[key.ID] = key
+	return nil
+}
+
+func (s *memoryAPIKeyStorage) Update(key apikeys.Key) error {
+	s.keys[key.ID] = key
+	return nil
+}
+
+func (s *memoryAPIKeyStorage) Revoke(id string) error {
+	key := s.keys[id]
+	key.Revoked = true
+	s.keys[id] = key
+	return nil
+}
+
+func (s *memoryAPIKeyStorage) Get(id string) (apikeys.Key, error) {
+	key, ok := s.keys[id]
+	if !ok {
+		return apikeys.Key{}, apikeys.ErrNotFound
+	}
+	return key, nil
+}
+
+func (s *memoryAPIKeyStorage) List() ([]apikeys.Key, error) {
+	var result []apikeys.Key
+	for _, key := range s.keys {
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func TestCreateAPIKeyReturnsSecret(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	st := &memoryAPIKeyStorage{keys: make(map[string]apikeys.Key)}
+	m, err := apikeys.NewManager(logger.Sugar(), st, apikeys.NewStaticKeys("read", "readSecret", "admin", "adminSecret"), "admin")
+	require.NoError(t, err)
+
+	s := &Server{r: gin.New(), spec: openapi.NewSpec("gateway")}
+	require.NoError(t, WithAPIKeyManager(m)(s))
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/admin/keys",
+		strings.NewReader(`{"name":"reserve","paths":["/trade-logs"],"methods":["GET"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	s.r.ServeHTTP(resp, req)
+	require.Equal(t, http.StatusCreated, resp.Code, resp.Body.String())
+
+	var created struct {
+		ID        string   `json:"id"`
+		Name      string   `json:"name"`
+		Secret    string   `json:"secret"`
+		Methods   []string `json:"methods"`
+		CreatedAt uint64   `json:"created_at"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &created))
+	stored, ok := st.keys[created.ID]
+	require.True(t, ok)
+	assert.NotEmpty(t, created.Secret)
+	assert.Equal(t, stored.Secret, created.Secret)
+	assert.Equal(t, "reserve", created.Name)
+	assert.Equal(t, []string{"GET"}, created.Methods)
+	assert.NotZero(t, created.CreatedAt)
+
+	// secret is not returned by other endpoints
+	resp = httptest.NewRecorder()
+	s.r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/admin/keys/"+created.ID, nil))
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "secret")
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/apikeys"
+	"github.com/KyberNetwork/reserve-stats/gateway/audit"
+	"github.com/KyberNetwork/reserve-stats/gateway/permission"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+const maxAuditTimeFrame = 31 * 24 * time.Hour
+
+var auditedMethods = map[string]struct{}{
+	http.MethodPost:   {},
+	http.MethodPut:    {},
+	http.MethodPatch:  {},
+	http.MethodDelete: {},
+}
+
+// newAuditMiddleware records mutating requests after they are handled. Requests rejected by permission
+// or authentication middlewares are not recorded.
+func newAuditMiddleware(sugar *zap.SugaredLogger, st audit.Storage) gin.HandlerFunc {
+	logger := sugar.With("func", caller.GetCurrentFunctionName())
+	return func(c *gin.Context) {
+		if _, ok := auditedMethods[c.Request.Method]; !ok {
+			return
+		}
+		timestamp := time.Now()
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			if body, err = ioutil.ReadAll(c.Request.Body); err != nil {
+				httputil.ResponseFailure(c, http.StatusBadRequest, err)
+				c.Abort()
+				return
+			}
+			c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		c.Next()
+
+		keyID, _ := permission.GetKeyID(c.Request)
+		record := audit.NewRecord(string(keyID), c.Request.Method, c.Request.URL.Path, c.Request.URL.Query(),
+			c.ContentType(), body, c.Writer.Status(), timestamp)
+		if _, err := st.Store(record); err != nil {
+			logger.Errorw("failed to store audit record",
+				"key_id", keyID, "method", record.Method, "path", record.Path, "error", err)
+		}
+	}
+}
+
+type auditQuery struct {
+	httputil.TimeRangeQuery
+	KeyID      string `form:"key_id"`
+	PathPrefix string `form:"path"`
+	Method     string `form:"method"`
+	Limit      int    `form:"limit" binding:"min=0,max=1000"`
+}
+
+func auditRecords(st audit.Storage) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query auditQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			httputil.ResponseFailure(c, http.StatusBadRequest, err)
+			return
+		}
+		from, to, err := query.Validate(httputil.TimeRangeQueryWithMaxTimeFrame(maxAuditTimeFrame))
+		if err != nil {
+			httputil.ResponseFailure(c, http.StatusBadRequest, err)
+			return
+		}
+		records, err := st.Records(audit.Filter{
+			From:       from,
+			To:         to,
+			KeyID:      query.KeyID,
+			PathPrefix: query.PathPrefix,
+			Method:     query.Method,
+			Limit:      query.Limit,
+		})
+		if err != nil {
+			httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, records)
+	}
+}
+
+// WithAuditLog records mutating requests to the storage and registers GET /admin/audit-logs to query
+// the records. The middleware only applies to routes registered after it, so this option must be given
+// before the route options.
+func WithAuditLog(sugar *zap.SugaredLogger, st audit.Storage) Option {
+	return func(s *Server) error {
+		s.handle(http.MethodGet, apikeys.AdminPathPrefix+"audit-logs", openapi.Endpoint{
+			Summary:  "audit records of mutating requests",
+			Query:    auditQuery{},
+			Response: []audit.Record{},
+		}, auditRecords(st))
+		s.r.Use(newAuditMiddleware(sugar, st))
+		return nil
+	}
+}
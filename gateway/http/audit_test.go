@@ -0,0 +1,100 @@
+package http
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/audit"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+type memoryAuditStorage struct {
+	mu      sync.Mutex
+	records []audit.Record
+}
+
+func (s *memoryAuditStorage) Store(r audit.Record) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r.ID = uint64(len(s.records) + 1)
+	s.records = append(s.records, r)
+	return r.ID, nil
+}
+
+func (s *memoryAuditStorage) Records(f audit.Filter) ([]audit.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var records []audit.Record
+	for i := len(s.records) - 1; i >= 0; i-- {
+		r := s.records[i]
+		if r.Timestamp.Before(f.From) || r.Timestamp.After(f.To) ||
+			(f.KeyID != "" && r.KeyID != f.KeyID) || !strings.HasPrefix(r.Path, f.PathPrefix) {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+func TestAuditLog(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	st := &memoryAuditStorage{}
+
+	s := &Server{r: gin.New(), spec: openapi.NewSpec("gateway")}
+	require.NoError(t, WithAuditLog(logger.Sugar(), st)(s))
+	s.r.POST("/applications", func(c *gin.Context) {
+		body, err := ioutil.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		// upstream receives the whole body
+		assert.JSONEq(t, `{"name":"app","address":"0x1"}`, string(body))
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+	s.r.GET("/applications", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{})
+	})
+
+	do := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", `Signature keyId="write",algorithm="hmac-sha512",headers="(request-target) nonce digest",signature="xxx"`)
+		resp := httptest.NewRecorder()
+		s.r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := do(http.MethodPost, "/applications?dry=false", `{"name":"app","address":"0x1"}`)
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	// only mutating requests are recorded
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/applications", "").Code)
+	require.Len(t, st.records, 1)
+
+	record := st.records[0]
+	assert.Equal(t, "write", record.KeyID)
+	assert.Equal(t, http.MethodPost, record.Method)
+	assert.Equal(t, "/applications", record.Path)
+	assert.Equal(t, "dry=false", record.Query)
+	assert.Equal(t, http.StatusCreated, record.Status)
+	assert.Equal(t, "{\n  \"address\": \"0x1\",\n  \"name\": \"app\"\n}", record.Body)
+
+	resp = do(http.MethodGet, "/admin/audit-logs?key_id=write&path=/app", "")
+	require.Equal(t, http.StatusOK, resp.Code)
+	var records []map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, record.BodyHash, records[0]["body_hash"])
+	assert.NotZero(t, records[0]["timestamp"])
+
+	resp = do(http.MethodGet, "/admin/audit-logs?limit=1001", "")
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
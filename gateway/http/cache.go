@@ -0,0 +1,143 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/apikeys"
+	"github.com/KyberNetwork/reserve-stats/gateway/cache"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+const (
+	cacheStatusHeader = "X-Cache"
+	cacheHit          = "HIT"
+	cacheMiss         = "MISS"
+)
+
+// bufferedWriter buffers the response body, so it could be cached and the ETag header could
+// be set before the body is written.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// Flush is no-op as the body is written after the handler finished.
+func (w *bufferedWriter) Flush() {}
+
+func writeCachedResponse(c *gin.Context, entry cache.Entry, status string) {
+	c.Header(cacheStatusHeader, status)
+	c.Header("ETag", entry.ETag)
+	if cache.MatchETag(c.GetHeader("If-None-Match"), entry.ETag) {
+		c.Writer.Header().Del("Content-Length")
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(entry.Status, entry.ContentType, entry.Body)
+}
+
+func newCacheMiddleware(sugar *zap.SugaredLogger, store cache.Store, policy cache.Policy) gin.HandlerFunc {
+	cacheRoute := newRouteCacheMiddleware(sugar, store, policy)
+	return func(c *gin.Context) {
+		if !policy.Cacheable(c.Request.URL.Path) {
+			return
+		}
+		cacheRoute(c)
+	}
+}
+
+// newRouteCacheMiddleware caches responses of GET requests regardless of the policy paths, it is used
+// for routes with their own cache policy.
+func newRouteCacheMiddleware(sugar *zap.SugaredLogger, store cache.Store, policy cache.Policy) gin.HandlerFunc {
+	logger := sugar.With("func", caller.GetCurrentFunctionName())
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			return
+		}
+		query := c.Request.URL.Query()
+		key := cache.Key(c.Request.URL.Path, query)
+		if scope := c.Request.Header.Get(httputil.ReserveScopeHeader); len(scope) != 0 {
+			// responses of reserve bound keys are filtered by upstreams, they are cached separately
+			key += "#" + scope
+		}
+
+		entry, err := store.Get(key)
+		if err != nil {
+			logger.Errorw("failed to get cached response", "key", key, "error", err)
+		} else if entry != nil {
+			writeCachedResponse(c, *entry, cacheHit)
+			c.Abort()
+			return
+		}
+
+		// cached responses are served to all clients, request uncompressed body from upstream
+		c.Request.Header.Del("Accept-Encoding")
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.Status() != http.StatusOK {
+			if _, err = c.Writer.Write(w.body.Bytes()); err != nil {
+				logger.Errorw("failed to write response", "key", key, "error", err)
+			}
+			return
+		}
+		fresh := cache.NewEntry(w.Status(), w.Header().Get("Content-Type"), w.body.Bytes())
+		if err = store.Set(key, fresh, policy.TTL(query, time.Now())); err != nil {
+			logger.Errorw("failed to cache response", "key", key, "error", err)
+		}
+		writeCachedResponse(c, fresh, cacheMiss)
+	}
+}
+
+type purgeCacheQuery struct {
+	Prefix string `form:"prefix"`
+}
+
+type purgeCacheResponse struct {
+	Purged int `json:"purged"`
+}
+
+var purgeCacheEndpoint = openapi.Endpoint{
+	Summary:  "purge cached responses with keys having the path prefix",
+	Query:    purgeCacheQuery{},
+	Response: purgeCacheResponse{},
+}
+
+func purgeCache(store cache.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		purged, err := store.Purge(c.Query("prefix"))
+		if err != nil {
+			httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.JSON(http.StatusOK, purgeCacheResponse{Purged: purged})
+	}
+}
+
+// WithResponseCache caches responses of endpoints matching the policy and registers
+// DELETE /admin/cache?prefix= to purge cached responses with keys having the path prefix.
+// The middleware only applies to routes registered after it, so this option must be given
+// before the route options.
+func WithResponseCache(sugar *zap.SugaredLogger, store cache.Store, policy cache.Policy) Option {
+	return func(s *Server) error {
+		s.handle(http.MethodDelete, apikeys.AdminPathPrefix+"cache", purgeCacheEndpoint, purgeCache(store))
+		s.r.Use(newCacheMiddleware(sugar, store, policy))
+		return nil
+	}
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/cache"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+func TestResponseCache(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	store, err := cache.NewLRUStore(10)
+	require.NoError(t, err)
+
+	var calls int
+	s := &Server{r: gin.New(), spec: openapi.NewSpec("gateway")}
+	require.NoError(t, WithResponseCache(logger.Sugar(), store, cache.Policy{
+		Paths:          []string{"/stats"},
+		ClosedRangeTTL: time.Hour,
+		OpenRangeTTL:   time.Minute,
+	})(s))
+	s.r.GET("/stats", func(c *gin.Context) {
+		calls++
+		if c.Query("fail") != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"from": c.Query("from")})
+	})
+
+	do := func(method, target, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		resp := httptest.NewRecorder()
+		s.r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := do(http.MethodGet, "/stats?from=1&to=2", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, cacheMiss, resp.Header().Get(cacheStatusHeader))
+	assert.JSONEq(t, `{"from":"1"}`, resp.Body.String())
+	etag := resp.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+
+	// same query in different order is served from cache
+	resp = do(http.MethodGet, "/stats?to=2&from=1", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, cacheHit, resp.Header().Get(cacheStatusHeader))
+	assert.JSONEq(t, `{"from":"1"}`, resp.Body.String())
+	assert.Equal(t, etag, resp.Header().Get("ETag"))
+	assert.Equal(t, 1, calls)
+
+	resp = do(http.MethodGet, "/stats?from=1&to=2", etag)
+	assert.Equal(t, http.StatusNotModified, resp.Code)
+	assert.Empty(t, resp.Body.String())
+
+	// error responses are not cached
+	for i := 0; i < 2; i++ {
+		resp = do(http.MethodGet, "/stats?fail=1", "")
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.JSONEq(t, `{"error":"failed"}`, resp.Body.String())
+	}
+	assert.Equal(t, 3, calls)
+
+	resp = do(http.MethodDelete, "/admin/cache?prefix=/stats", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"purged":1}`, resp.Body.String())
+	resp = do(http.MethodGet, "/stats?from=1&to=2", "")
+	assert.Equal(t, cacheMiss, resp.Header().Get(cacheStatusHeader))
+	assert.Equal(t, 4, calls)
+}
@@ -1,39 +1,91 @@
 package http
 
 import (
-	"net/http/httputil"
-	"net/url"
+	"net/http"
 	"time"
 
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
 	libhttputil "github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 	"github.com/gin-contrib/cors"
-	"github.com/gin-contrib/httpsign"
 	ginzap "github.com/gin-contrib/zap"
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+const (
+	healthPath     = "/health"
+	healthOK       = "ok"
+	healthDegraded = "degraded"
+)
+
 // Server is HTTP server of gateway service.
 type Server struct {
 	r    *gin.Engine
 	addr string
+	// public are the routes accessible without signature, keyed by publicRouteKey.
+	public    map[string]struct{}
+	sugar     *zap.SugaredLogger
+	upstreams *upstream.Registry
+	// spec describes the routes served by gateway itself, the routes proxied to upstreams are described by
+	// the documents of upstreams.
+	spec *openapi.Spec
+	// routeUpstreams are the upstream names of routes configured by route table, keyed by publicRouteKey.
+	routeUpstreams map[string]string
+	openAPICache   *openAPIDocumentCache
 }
 
-func newReverseProxyMW(target string) (gin.HandlerFunc, error) {
-	parsedURL, err := url.Parse(target)
-	if err != nil {
-		return nil, err
-	}
-	proxy := httputil.NewSingleHostReverseProxy(parsedURL)
+func publicRouteKey(method, path string) string {
+	return method + " " + path
+}
 
+// skipPublic wraps a middleware to skip it on public routes. The routes are registered by options
+// after the middleware, so the check is done per request.
+func (svr *Server) skipPublic(mw gin.HandlerFunc) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		proxy.ServeHTTP(c.Writer, c.Request)
-	}, nil
+		if _, ok := svr.public[publicRouteKey(c.Request.Method, c.FullPath())]; ok {
+			return
+		}
+		mw(c)
+	}
+}
+
+// newReverseProxyMW returns the handler proxying requests to the upstream of target URL with default config.
+func (svr *Server) newReverseProxyMW(target string) (gin.HandlerFunc, error) {
+	u := svr.upstreams.Get(target)
+	if u == nil {
+		var err error
+		if u, err = svr.upstreams.Add(target, upstream.Config{URLs: []string{target}}); err != nil {
+			return nil, err
+		}
+	}
+	return newUpstreamProxy(svr.sugar, u, 0, 0), nil
 }
 
-// NewServer creates new instance of gateway HTTP server.
+type healthResponse struct {
+	Status    string            `json:"status"`
+	Upstreams []upstream.Status `json:"upstreams"`
+}
+
+// health reports the state of upstreams, the gateway is degraded if any upstream is unavailable.
+func (svr *Server) health(c *gin.Context) {
+	status := healthOK
+	upstreams := svr.upstreams.Status()
+	for _, u := range upstreams {
+		if !u.Available {
+			status = healthDegraded
+		}
+	}
+	c.JSON(http.StatusOK, healthResponse{
+		Status:    status,
+		Upstreams: upstreams,
+	})
+}
+
+// NewServer creates new instance of gateway HTTP server. The perm middleware checks if the key
+// is allowed to access the endpoint and the auth middleware verifies the request signature.
 func NewServer(addr string,
-	auth *httpsign.Authenticator,
+	auth gin.HandlerFunc,
 	perm gin.HandlerFunc,
 	logger *zap.Logger,
 	options ...Option,
@@ -46,13 +98,31 @@ func NewServer(addr string,
 	corsConfig.MaxAge = 5 * time.Minute
 	r.Use(ginzap.Ginzap(logger, time.RFC3339, true))
 	r.Use(cors.New(corsConfig))
-	r.Use(perm)
-	r.Use(auth.Authenticated())
 
 	server := Server{
-		addr: addr,
-		r:    r,
+		addr:      addr,
+		r:         r,
+		public:    make(map[string]struct{}),
+		sugar:     logger.Sugar(),
+		upstreams: upstream.NewRegistry(logger.Sugar(), upstream.DefaultConfig()),
+		spec:      openapi.NewSpec("gateway"),
+
+		routeUpstreams: make(map[string]string),
+		openAPICache:   &openAPIDocumentCache{ttl: defaultOpenAPIDocumentTTL},
 	}
+	// the reserve scope header is only set by perm middleware for keys bound to reserves
+	r.Use(func(c *gin.Context) {
+		c.Request.Header.Del(libhttputil.ReserveScopeHeader)
+	})
+	r.Use(server.skipPublic(perm))
+	r.Use(server.skipPublic(auth))
+	server.handle(http.MethodGet, healthPath, openapi.Endpoint{
+		Summary:  "state of gateway and its upstreams",
+		Response: healthResponse{},
+	}, server.health)
+	server.public[publicRouteKey(http.MethodGet, healthPath)] = struct{}{}
+	r.GET(openapi.DocumentPath, server.openAPIDocument)
+	server.public[publicRouteKey(http.MethodGet, openapi.DocumentPath)] = struct{}{}
 
 	for _, opt := range options {
 		if err := opt(&server); err != nil {
@@ -62,7 +132,8 @@ func NewServer(addr string,
 	return &server, nil
 }
 
-// Start runs the HTTP gateway server.
+// Start runs health checks of upstreams and the HTTP gateway server.
 func (svr *Server) Start() error {
+	svr.upstreams.Run()
 	return svr.r.Run(svr.addr)
 }
@@ -156,7 +156,7 @@ func TestReverseProxy(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	testServer, err := NewServer(testAddr, auth, perm, logger,
+	testServer, err := NewServer(testAddr, auth.Authenticated(), perm, logger,
 		WithTradeLogURL(testURL),
 		WithReserveRatesURL(testURL),
 		WithUserURL(testURL),
@@ -0,0 +1,117 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+// handle registers the handlers of a route served by gateway itself and describes it in the OpenAPI document.
+func (svr *Server) handle(method, path string, e openapi.Endpoint, handlers ...gin.HandlerFunc) {
+	svr.spec.Add(method, path, e)
+	svr.r.Handle(method, path, handlers...)
+}
+
+// defaultOpenAPIDocumentTTL is the duration the merged OpenAPI document is served from cache.
+const defaultOpenAPIDocumentTTL = time.Minute
+
+// openAPIDocumentCache keeps the merged OpenAPI document to not fetch the documents of all upstreams
+// on every request.
+type openAPIDocumentCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	data    []byte
+	expires time.Time
+}
+
+// fetchOpenAPIDocument fetches the OpenAPI document served by the upstream.
+func fetchOpenAPIDocument(ctx context.Context, u *upstream.Upstream) (openapi.Document, error) {
+	var doc openapi.Document
+	// scheme and host are replaced with the ones of an upstream instance by transport
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+u.Name()+openapi.DocumentPath, nil)
+	if err != nil {
+		return doc, err
+	}
+	client := &http.Client{Transport: u, Timeout: u.Timeout()}
+	resp, err := client.Do(req)
+	if err != nil {
+		return doc, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return doc, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&doc)
+	return doc, err
+}
+
+// mergeOpenAPIDocument returns the OpenAPI document of gateway. The documents of upstreams are merged into the
+// one of gateway, keeping only the operations routed to the upstream. Upstreams failed to serve their documents
+// are skipped.
+func (svr *Server) mergeOpenAPIDocument(ctx context.Context) (openapi.Document, error) {
+	logger := svr.sugar.With("func", caller.GetCurrentFunctionName())
+
+	// routes is the upstream of gateway routes, empty if the upstream is unknown
+	routes := make(map[string]string)
+	for _, route := range svr.r.Routes() {
+		key := publicRouteKey(route.Method, openapi.Path(route.Path))
+		routes[key] = svr.routeUpstreams[publicRouteKey(route.Method, route.Path)]
+	}
+
+	doc, err := svr.spec.Document()
+	if err != nil {
+		return doc, err
+	}
+	for _, u := range svr.upstreams.Upstreams() {
+		upstreamDoc, err := fetchOpenAPIDocument(ctx, u)
+		if err != nil {
+			logger.Warnw("failed to fetch OpenAPI document of upstream", "upstream", u.Name(), "error", err)
+			continue
+		}
+		name := u.Name()
+		upstreamDoc.Filter(func(method, path string) bool {
+			routed, ok := routes[publicRouteKey(method, path)]
+			return ok && (len(routed) == 0 || routed == name)
+		})
+		if err = doc.Merge(name, upstreamDoc); err != nil {
+			logger.Warnw("failed to merge OpenAPI document of upstream", "upstream", u.Name(), "error", err)
+		}
+	}
+	return doc, nil
+}
+
+// openAPIDocument serves the merged OpenAPI document of gateway from cache, the document is merged again
+// once the cache expires. Requests arriving while merging wait for the result instead of fetching again.
+func (svr *Server) openAPIDocument(c *gin.Context) {
+	cache := svr.openAPICache
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.data == nil || !time.Now().Before(cache.expires) {
+		// the document is shared by requests, so it is not bound to the context of this request
+		doc, err := svr.mergeOpenAPIDocument(context.Background())
+		if err != nil {
+			httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+			return
+		}
+		data, err := json.Marshal(doc)
+		if err != nil {
+			httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+			return
+		}
+		cache.data, cache.expires = data, time.Now().Add(cache.ttl)
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", cache.data)
+}
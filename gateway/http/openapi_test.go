@@ -0,0 +1,91 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+func newOpenAPIUpstream(title string, summaries map[string]string) *httptest.Server {
+	r := gin.New()
+	api := openapi.NewRouter(r, openapi.NewSpec(title))
+	for path, summary := range summaries {
+		api.GET(path, openapi.Endpoint{Summary: summary}, func(c *gin.Context) {})
+	}
+	return httptest.NewServer(r)
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	trades := newOpenAPIUpstream("trades", map[string]string{
+		"/trades":            "trades",
+		"/trades/:id":        "trade of id",
+		"/not-routed-trades": "not routed",
+	})
+	defer trades.Close()
+	rates := newOpenAPIUpstream("rates", map[string]string{
+		"/rates":  "rates",
+		"/trades": "trades of rates service",
+	})
+	defer rates.Close()
+	// upstream without document is skipped
+	legacy := httptest.NewServer(http.NotFoundHandler())
+	defer legacy.Close()
+
+	table := RouteTable{
+		Upstreams: map[string]upstream.Config{
+			"trades": {URLs: []string{trades.URL}},
+			"rates":  {URLs: []string{rates.URL}},
+			"legacy": {URLs: []string{legacy.URL}},
+		},
+		Routes: []Route{
+			{Path: "/trades", Methods: []string{"GET"}, Upstream: "trades"},
+			{Path: "/trades/:id", Methods: []string{"GET"}, Upstream: "trades"},
+			{Path: "/rates", Methods: []string{"GET"}, Upstream: "rates"},
+			{Path: "/legacy", Methods: []string{"GET"}, Upstream: "legacy"},
+		},
+	}
+	// only public routes are accessible without authorization
+	deny := func(c *gin.Context) { c.AbortWithStatus(http.StatusUnauthorized) }
+	s, err := NewServer("", deny, deny, logger, WithRouteTable(logger.Sugar(), table, nil))
+	require.NoError(t, err)
+	gateway := httptest.NewServer(s.r)
+	defer gateway.Close()
+
+	getDocument := func() openapi.Document {
+		resp, err := http.Get(gateway.URL + openapi.DocumentPath)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var doc openapi.Document
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+		return doc
+	}
+
+	doc := getDocument()
+	assert.Len(t, doc.Paths, 4)
+	assert.Contains(t, doc.Paths, healthPath)
+	require.Contains(t, doc.Paths, "/trades/{id}")
+	assert.Equal(t, "trades", doc.Paths["/trades"]["get"].Summary)
+	assert.Equal(t, "rates", doc.Paths["/rates"]["get"].Summary)
+	assert.NotContains(t, doc.Paths, "/not-routed-trades")
+	assert.NotContains(t, doc.Paths, "/legacy")
+
+	// the merged document is served from cache until expired
+	rates.Close()
+	assert.Contains(t, getDocument().Paths, "/rates")
+	s.openAPICache.expires = time.Now()
+	assert.NotContains(t, getDocument().Paths, "/rates")
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+const retryDelay = 100 * time.Millisecond
+
+// retryTransport retries idempotent requests on connection errors and gateway errors of upstream.
+// Requests are not retried if the upstream is unavailable.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return err != upstream.ErrUnavailable
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if req.Method != http.MethodGet {
+		return resp, err
+	}
+	for i := 0; i < t.retries && retryable(resp, err); i++ {
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(retryDelay):
+		}
+		resp, err = t.base.RoundTrip(req)
+	}
+	return resp, err
+}
+
+// newUpstreamProxy returns the handler proxying requests to the upstream. It responds 503 without waiting
+// if the upstream is unavailable and 504 if the upstream does not respond in timeout, which is the timeout
+// of upstream if not positive. There is no timeout if both are not positive.
+func newUpstreamProxy(sugar *zap.SugaredLogger, u *upstream.Upstream, timeout time.Duration, retries int) gin.HandlerFunc {
+	logger := sugar.With("func", caller.GetCurrentFunctionName(), "upstream", u.Name())
+	if timeout <= 0 {
+		timeout = u.Timeout()
+	}
+	proxy := &httputil.ReverseProxy{
+		// scheme and host are replaced with the ones of an upstream instance by transport
+		Director: func(req *http.Request) {
+			req.URL.Scheme = "http"
+			req.URL.Host = u.Name()
+			if _, ok := req.Header["User-Agent"]; !ok {
+				// explicitly disable User-Agent so it's not set to default value
+				req.Header.Set("User-Agent", "")
+			}
+		},
+		Transport: &retryTransport{base: u, retries: retries},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logger.Warnw("failed to proxy request", "path", r.URL.Path, "error", err)
+			switch {
+			case err == upstream.ErrUnavailable:
+				w.Header().Set("Content-Type", "application/json; charset=utf-8")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_ = json.NewEncoder(w).Encode(gin.H{"error": fmt.Sprintf("upstream %s is unavailable", u.Name())})
+			case r.Context().Err() == context.DeadlineExceeded:
+				w.WriteHeader(http.StatusGatewayTimeout)
+			default:
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		},
+	}
+
+	return func(c *gin.Context) {
+		req := c.Request
+		if timeout > 0 {
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			defer cancel()
+			req = req.WithContext(ctx)
+		}
+		proxy.ServeHTTP(c.Writer, req)
+	}
+}
@@ -0,0 +1,125 @@
+package http
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
+	libhttputil "github.com/KyberNetwork/reserve-stats/lib/httputil"
+)
+
+func TestUpstreamCircuitBreaking(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	table := RouteTable{
+		Upstreams: map[string]upstream.Config{"broken": {URLs: []string{broken.URL}, FailureThreshold: 1}},
+		Routes:    []Route{{Path: "/broken", Methods: []string{"GET"}, Upstream: "broken"}},
+	}
+	// requests without X-Authorized header are rejected
+	auth := func(c *gin.Context) {
+		if c.GetHeader("X-Authorized") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		}
+	}
+	s, err := NewServer("", auth, func(c *gin.Context) {}, logger, WithRouteTable(logger.Sugar(), table, nil))
+	require.NoError(t, err)
+	gateway := httptest.NewServer(s.r)
+	defer gateway.Close()
+
+	do := func(target string) (int, []byte) {
+		req, err := http.NewRequest(http.MethodGet, gateway.URL+target, nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Authorized", "true")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, body
+	}
+
+	code, body := do("/broken")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Empty(t, body)
+
+	// circuit is open, request fails fast
+	code, body = do("/broken")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.JSONEq(t, `{"error":"upstream broken is unavailable"}`, string(body))
+
+	// health endpoint does not require signature
+	resp, err := http.Get(gateway.URL + healthPath)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var health struct {
+		Status    string            `json:"status"`
+		Upstreams []upstream.Status `json:"upstreams"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&health))
+	assert.Equal(t, healthDegraded, health.Status)
+	require.Len(t, health.Upstreams, 1)
+	assert.Equal(t, "broken", health.Upstreams[0].Name)
+	assert.False(t, health.Upstreams[0].Available)
+	assert.Equal(t, upstream.CircuitOpen, health.Upstreams[0].Instances[0].Circuit)
+}
+
+func TestReserveScopeHeader(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	var scope string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope = r.Header.Get(libhttputil.ReserveScopeHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	table := RouteTable{
+		Upstreams: map[string]upstream.Config{"backend": {URLs: []string{backend.URL}}},
+		Routes:    []Route{{Path: "/trade-logs", Methods: []string{"GET"}, Upstream: "backend"}},
+	}
+	// requests with X-Bound header are of a key bound to reserves
+	perm := func(c *gin.Context) {
+		if c.GetHeader("X-Bound") != "" {
+			c.Request.Header.Set(libhttputil.ReserveScopeHeader, "0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		}
+	}
+	s, err := NewServer("", func(c *gin.Context) {}, perm, logger, WithRouteTable(logger.Sugar(), table, nil))
+	require.NoError(t, err)
+	gateway := httptest.NewServer(s.r)
+	defer gateway.Close()
+
+	do := func(bound bool) {
+		req, err := http.NewRequest(http.MethodGet, gateway.URL+"/trade-logs", nil)
+		require.NoError(t, err)
+		req.Header.Set(libhttputil.ReserveScopeHeader, "0x21433Dec9Cb634A23c6A4BbcCe08c83f5aC2EC18")
+		if bound {
+			req.Header.Set("X-Bound", "true")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	// scope sent by client is removed
+	do(false)
+	assert.Empty(t, scope)
+	do(true)
+	assert.Equal(t, "0x63825c174ab367968EC60f061753D3bbD36A0D8F", scope)
+}
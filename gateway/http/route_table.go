@@ -0,0 +1,206 @@
+package http
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/apikeys"
+	"github.com/KyberNetwork/reserve-stats/gateway/cache"
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
+)
+
+const (
+	// PermissionPrivate routes require a signed request of a key allowed to access the route.
+	PermissionPrivate = "private"
+	// PermissionPublic routes are accessible without signature.
+	PermissionPublic = "public"
+
+	routesConfigFlag = "routes-config"
+
+	maxRetries = 5
+)
+
+var validRouteMethods = map[string]struct{}{
+	http.MethodGet:    {},
+	http.MethodPost:   {},
+	http.MethodPut:    {},
+	http.MethodPatch:  {},
+	http.MethodDelete: {},
+}
+
+// RouteCache is the cache policy of a route, see cache.Policy.
+type RouteCache struct {
+	ClosedRangeTTL time.Duration `yaml:"closed_range_ttl"`
+	OpenRangeTTL   time.Duration `yaml:"open_range_ttl"`
+	Margin         time.Duration `yaml:"margin"`
+}
+
+// Route is a route proxied to an upstream.
+type Route struct {
+	Path    string   `yaml:"path"`
+	Methods []string `yaml:"methods"`
+	// Upstream is the name of upstream in route table.
+	Upstream string `yaml:"upstream"`
+	// Permission is either private (default) or public.
+	Permission string `yaml:"permission"`
+	// Timeout is the maximum duration to wait for upstream response, 0 is the timeout of upstream.
+	Timeout time.Duration `yaml:"timeout"`
+	// Retries is the number of retries of GET requests on connection errors or 502, 503 and 504 responses.
+	Retries int `yaml:"retries"`
+	// Cache enables response caching of GET requests, responses are not cached if not configured.
+	Cache *RouteCache `yaml:"cache"`
+}
+
+// RouteTable is the routing configuration of gateway.
+type RouteTable struct {
+	// Upstreams are upstream services by name, an upstream with single instance could be configured
+	// with its URL only.
+	Upstreams map[string]upstream.Config `yaml:"upstreams"`
+	Routes    []Route                    `yaml:"routes"`
+}
+
+// LoadRouteTable reads the route table from a YAML or JSON file and validates it.
+func LoadRouteTable(path string) (RouteTable, error) {
+	var table RouteTable
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return table, err
+	}
+	// JSON is a subset of YAML
+	if err = yaml.UnmarshalStrict(data, &table); err != nil {
+		return table, err
+	}
+	return table, table.Validate()
+}
+
+// NewRouteTableCliFlags returns cli flags to configure the route table.
+func NewRouteTableCliFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   routesConfigFlag,
+			Usage:  "YAML or JSON file of proxied routes, the upstream URL flags are used if not provided",
+			EnvVar: "ROUTES_CONFIG",
+		},
+	}
+}
+
+// NewRouteTableFromContext loads the route table from cli flags, it returns nil if not configured.
+func NewRouteTableFromContext(c *cli.Context) (*RouteTable, error) {
+	configFile := c.String(routesConfigFlag)
+	if len(configFile) == 0 {
+		return nil, nil
+	}
+	table, err := LoadRouteTable(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid route table %s: %s", configFile, err)
+	}
+	return &table, nil
+}
+
+// Validate checks the route table and normalizes methods and permissions.
+func (t *RouteTable) Validate() error {
+	for name, cfg := range t.Upstreams {
+		if err := cfg.WithDefaults(upstream.DefaultConfig()).Validate(); err != nil {
+			return fmt.Errorf("upstream %s: %s", name, err)
+		}
+	}
+	if len(t.Routes) == 0 {
+		return fmt.Errorf("no routes configured")
+	}
+
+	registered := make(map[string]struct{})
+	for i := range t.Routes {
+		route := &t.Routes[i]
+		if !strings.HasPrefix(route.Path, "/") {
+			return fmt.Errorf("route %q: path must start with /", route.Path)
+		}
+		if route.Path == healthPath || route.Path == openapi.DocumentPath || strings.HasPrefix(route.Path, apikeys.AdminPathPrefix) {
+			return fmt.Errorf("route %s: path is reserved by gateway", route.Path)
+		}
+		if _, ok := t.Upstreams[route.Upstream]; !ok {
+			return fmt.Errorf("route %s: unknown upstream %q", route.Path, route.Upstream)
+		}
+		if len(route.Methods) == 0 {
+			return fmt.Errorf("route %s: no methods", route.Path)
+		}
+		for j, method := range route.Methods {
+			method = strings.ToUpper(method)
+			if _, ok := validRouteMethods[method]; !ok {
+				return fmt.Errorf("route %s: invalid method %q", route.Path, method)
+			}
+			key := method + " " + route.Path
+			if _, ok := registered[key]; ok {
+				return fmt.Errorf("route %s: duplicated method %s", route.Path, method)
+			}
+			registered[key] = struct{}{}
+			route.Methods[j] = method
+		}
+		switch route.Permission {
+		case "":
+			route.Permission = PermissionPrivate
+		case PermissionPrivate, PermissionPublic:
+		default:
+			return fmt.Errorf("route %s: invalid permission %q", route.Path, route.Permission)
+		}
+		if route.Timeout < 0 {
+			return fmt.Errorf("route %s: negative timeout", route.Path)
+		}
+		if route.Retries < 0 || route.Retries > maxRetries {
+			return fmt.Errorf("route %s: retries must be between 0 and %d", route.Path, maxRetries)
+		}
+		if route.Cache != nil && (route.Cache.ClosedRangeTTL <= 0 || route.Cache.OpenRangeTTL <= 0) {
+			return fmt.Errorf("route %s: cache TTLs must be positive", route.Path)
+		}
+	}
+	return nil
+}
+
+// WithRouteTable registers upstreams and routes of the route table. Cache store is required if any route has cache
+// policy, DELETE /admin/cache is registered to purge cached responses if it is given. This option replaces
+// WithResponseCache, which caches responses of all routes matching a global policy.
+func WithRouteTable(sugar *zap.SugaredLogger, table RouteTable, store cache.Store) Option {
+	return func(s *Server) error {
+		if err := table.Validate(); err != nil {
+			return err
+		}
+		if store != nil {
+			s.handle(http.MethodDelete, apikeys.AdminPathPrefix+"cache", purgeCacheEndpoint, purgeCache(store))
+		}
+		for name, cfg := range table.Upstreams {
+			if _, err := s.upstreams.Add(name, cfg); err != nil {
+				return err
+			}
+		}
+		for _, route := range table.Routes {
+			var handlers []gin.HandlerFunc
+			if route.Cache != nil {
+				if store == nil {
+					return fmt.Errorf("route %s: cache storage is not configured", route.Path)
+				}
+				handlers = append(handlers, newRouteCacheMiddleware(sugar, store, cache.Policy{
+					ClosedRangeTTL: route.Cache.ClosedRangeTTL,
+					OpenRangeTTL:   route.Cache.OpenRangeTTL,
+					Margin:         route.Cache.Margin,
+				}))
+			}
+			handlers = append(handlers, newUpstreamProxy(sugar, s.upstreams.Get(route.Upstream), route.Timeout, route.Retries))
+			for _, method := range route.Methods {
+				s.r.Handle(method, route.Path, handlers...)
+				s.routeUpstreams[publicRouteKey(method, route.Path)] = route.Upstream
+				if route.Permission == PermissionPublic {
+					s.public[publicRouteKey(method, route.Path)] = struct{}{}
+				}
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/cache"
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
+)
+
+func TestLoadRouteTableExamples(t *testing.T) {
+	for _, path := range []string{"../routes.example.yaml", "../accounting-routes.example.yaml"} {
+		table, err := LoadRouteTable(path)
+		require.NoError(t, err, path)
+		assert.NotEmpty(t, table.Routes)
+		for _, route := range table.Routes {
+			assert.Equal(t, PermissionPrivate, route.Permission)
+		}
+	}
+}
+
+func TestRouteTableValidate(t *testing.T) {
+	var tests = []struct {
+		name  string
+		table RouteTable
+		ok    bool
+	}{
+		{
+			name: "valid",
+			table: RouteTable{
+				Upstreams: map[string]upstream.Config{"a": {URLs: []string{"http://127.0.0.1:8000"}}},
+				Routes:    []Route{{Path: "/a", Methods: []string{"get", "POST"}, Upstream: "a", Permission: PermissionPublic}},
+			},
+			ok: true,
+		},
+		{
+			name: "unknown upstream",
+			table: RouteTable{
+				Upstreams: map[string]upstream.Config{"a": {URLs: []string{"http://127.0.0.1:8000"}}},
+				Routes:    []Route{{Path: "/a", Methods: []string{"GET"}, Upstream: "b"}},
+			},
+		},
+		{
+			name: "invalid upstream url",
+			table: RouteTable{
+				Upstreams: map[string]upstream.Config{"a": {URLs: []string{"127.0.0.1:8000"}}},
+				Routes:    []Route{{Path: "/a", Methods: []string{"GET"}, Upstream: "a"}},
+			},
+		},
+		{
+			name: "duplicated route",
+			table: RouteTable{
+				Upstreams: map[string]upstream.Config{"a": {URLs: []string{"http://127.0.0.1:8000"}}},
+				Routes: []Route{
+					{Path: "/a", Methods: []string{"GET"}, Upstream: "a"},
+					{Path: "/a", Methods: []string{"get"}, Upstream: "a"},
+				},
+			},
+		},
+		{
+			name: "invalid method",
+			table: RouteTable{
+				Upstreams: map[string]upstream.Config{"a": {URLs: []string{"http://127.0.0.1:8000"}}},
+				Routes:    []Route{{Path: "/a", Methods: []string{"FETCH"}, Upstream: "a"}},
+			},
+		},
+		{
+			name: "invalid permission",
+			table: RouteTable{
+				Upstreams: map[string]upstream.Config{"a": {URLs: []string{"http://127.0.0.1:8000"}}},
+				Routes:    []Route{{Path: "/a", Methods: []string{"GET"}, Upstream: "a", Permission: "admin"}},
+			},
+		},
+		{
+			name: "too many retries",
+			table: RouteTable{
+				Upstreams: map[string]upstream.Config{"a": {URLs: []string{"http://127.0.0.1:8000"}}},
+				Routes:    []Route{{Path: "/a", Methods: []string{"GET"}, Upstream: "a", Retries: maxRetries + 1}},
+			},
+		},
+		{
+			name: "missing cache ttl",
+			table: RouteTable{
+				Upstreams: map[string]upstream.Config{"a": {URLs: []string{"http://127.0.0.1:8000"}}},
+				Routes:    []Route{{Path: "/a", Methods: []string{"GET"}, Upstream: "a", Cache: &RouteCache{OpenRangeTTL: time.Minute}}},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		err := tc.table.Validate()
+		if tc.ok {
+			assert.NoError(t, err, tc.name)
+		} else {
+			assert.Error(t, err, tc.name)
+		}
+	}
+}
+
+func TestRouteTableProxy(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+	store, err := cache.NewLRUStore(10)
+	require.NoError(t, err)
+
+	var flakyCalls, cachedCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			if atomic.AddInt32(&flakyCalls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		case "/cached":
+			atomic.AddInt32(&cachedCalls, 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	table := RouteTable{
+		Upstreams: map[string]upstream.Config{"test": {URLs: []string{server.URL}}},
+		Routes: []Route{
+			{Path: "/flaky", Methods: []string{"GET"}, Upstream: "test", Retries: 1},
+			{Path: "/slow", Methods: []string{"GET"}, Upstream: "test", Timeout: 50 * time.Millisecond},
+			{Path: "/public", Methods: []string{"GET"}, Upstream: "test", Permission: PermissionPublic},
+			{Path: "/private", Methods: []string{"GET"}, Upstream: "test"},
+			{Path: "/cached", Methods: []string{"GET"}, Upstream: "test", Permission: PermissionPublic,
+				Cache: &RouteCache{ClosedRangeTTL: time.Hour, OpenRangeTTL: time.Minute}},
+		},
+	}
+	pass := func(c *gin.Context) {}
+	// only requests with X-Authorized header are allowed
+	auth := func(c *gin.Context) {
+		if c.GetHeader("X-Authorized") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+		}
+	}
+	s, err := NewServer("", auth, pass, logger, WithRouteTable(logger.Sugar(), table, store))
+	require.NoError(t, err)
+
+	// reverse proxy requires a http.CloseNotifier, which httptest.ResponseRecorder is not
+	gateway := httptest.NewServer(s.r)
+	defer gateway.Close()
+
+	do := func(target string, authorized bool) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, gateway.URL+target, nil)
+		require.NoError(t, err)
+		if authorized {
+			req.Header.Set("X-Authorized", "true")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		_, err = ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp
+	}
+
+	assert.Equal(t, http.StatusOK, do("/flaky", true).StatusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&flakyCalls))
+
+	assert.Equal(t, http.StatusGatewayTimeout, do("/slow", true).StatusCode)
+
+	assert.Equal(t, http.StatusOK, do("/public", false).StatusCode)
+	assert.Equal(t, http.StatusUnauthorized, do("/private", false).StatusCode)
+	assert.Equal(t, http.StatusOK, do("/private", true).StatusCode)
+
+	assert.Equal(t, cacheMiss, do("/cached?to=1", false).Header.Get(cacheStatusHeader))
+	assert.Equal(t, cacheHit, do("/cached?to=1", false).Header.Get(cacheStatusHeader))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cachedCalls))
+
+	// cache store is required for routes with cache policy
+	_, err = NewServer("", auth, pass, logger, WithRouteTable(logger.Sugar(), table, nil))
+	assert.Error(t, err)
+}
@@ -1,12 +1,17 @@
 package http
 
+import (
+	"github.com/KyberNetwork/reserve-stats/gateway/ratelimit"
+	"github.com/KyberNetwork/reserve-stats/gateway/upstream"
+)
+
 //Option define initialize behaviour for server
 type Option func(*Server) error
 
 //WithTradeLogURL set TradeLogsProxy for server
 func WithTradeLogURL(tradeLogsURL string) Option {
 	return func(s *Server) error {
-		tradeLogsProxyMW, err := newReverseProxyMW(tradeLogsURL)
+		tradeLogsProxyMW, err := s.newReverseProxyMW(tradeLogsURL)
 		if err != nil {
 			return err
 		}
@@ -26,11 +31,12 @@ func WithTradeLogURL(tradeLogsURL string) Option {
 //WithReserveRatesURL set resreve rate proxy for server
 func WithReserveRatesURL(reserveRatesURL string) Option {
 	return func(s *Server) error {
-		reserveRateProxyMW, err := newReverseProxyMW(reserveRatesURL)
+		reserveRateProxyMW, err := s.newReverseProxyMW(reserveRatesURL)
 		if err != nil {
 			return err
 		}
 		s.r.GET("/reserve-rates", reserveRateProxyMW)
+		s.r.GET("/reserve-availability", reserveRateProxyMW)
 		return nil
 	}
 }
@@ -38,7 +44,7 @@ func WithReserveRatesURL(reserveRatesURL string) Option {
 //WithUserURL set user proxy for server
 func WithUserURL(userURL string) Option {
 	return func(s *Server) error {
-		userProxyMW, err := newReverseProxyMW(userURL)
+		userProxyMW, err := s.newReverseProxyMW(userURL)
 		if err != nil {
 			return err
 		}
@@ -52,7 +58,7 @@ func WithUserURL(userURL string) Option {
 //WithPriceAnalyticURL set price analytic proxy for server
 func WithPriceAnalyticURL(priceAnalyticURL string) Option {
 	return func(s *Server) error {
-		priceProxyMW, err := newReverseProxyMW(priceAnalyticURL)
+		priceProxyMW, err := s.newReverseProxyMW(priceAnalyticURL)
 		if err != nil {
 			return err
 		}
@@ -65,7 +71,7 @@ func WithPriceAnalyticURL(priceAnalyticURL string) Option {
 //WithAppNamesURL set price analytic proxy for server
 func WithAppNamesURL(appNamesURL string) Option {
 	return func(s *Server) error {
-		appNamesProxyMW, err := newReverseProxyMW(appNamesURL)
+		appNamesProxyMW, err := s.newReverseProxyMW(appNamesURL)
 		if err != nil {
 			return err
 		}
@@ -80,13 +86,15 @@ func WithAppNamesURL(appNamesURL string) Option {
 //WithCexTradesURL set cex trade proxy for server
 func WithCexTradesURL(cexTradeURL string) Option {
 	return func(s *Server) error {
-		cexTradeURLMW, err := newReverseProxyMW(cexTradeURL)
+		cexTradeURLMW, err := s.newReverseProxyMW(cexTradeURL)
 		if err != nil {
 			return err
 		}
 		s.r.GET("/trades", cexTradeURLMW)
+		s.r.GET("/balances", cexTradeURLMW)
 		s.r.GET("/convert_to_eth_price", cexTradeURLMW)
 		s.r.GET("/convert_trades", cexTradeURLMW)
+		s.r.GET("/commissions", cexTradeURLMW)
 		return nil
 	}
 }
@@ -94,7 +102,7 @@ func WithCexTradesURL(cexTradeURL string) Option {
 //WithResreveAddressesURL set resreve addresses proxy for server
 func WithResreveAddressesURL(reserveAddressesURL string) Option {
 	return func(s *Server) error {
-		reserveAddressURLMW, err := newReverseProxyMW(reserveAddressesURL)
+		reserveAddressURLMW, err := s.newReverseProxyMW(reserveAddressesURL)
 		if err != nil {
 			return err
 		}
@@ -102,6 +110,12 @@ func WithResreveAddressesURL(reserveAddressesURL string) Option {
 		s.r.GET("/addresses/:id", reserveAddressURLMW)
 		s.r.GET("/addresses", reserveAddressURLMW)
 		s.r.PUT("/addresses/:id", reserveAddressURLMW)
+		s.r.DELETE("/addresses/:id", reserveAddressURLMW)
+		s.r.POST("/addresses/:id/restore", reserveAddressURLMW)
+		s.r.GET("/addresses/:id/versions", reserveAddressURLMW)
+		s.r.GET("/address-proposals", reserveAddressURLMW)
+		s.r.POST("/address-proposals/:id/approve", reserveAddressURLMW)
+		s.r.POST("/address-proposals/:id/reject", reserveAddressURLMW)
 		return nil
 	}
 }
@@ -109,7 +123,7 @@ func WithResreveAddressesURL(reserveAddressesURL string) Option {
 // WithCexWithdrawalURL return withdraw proxy
 func WithCexWithdrawalURL(cexWithdrawalURL string) Option {
 	return func(s *Server) error {
-		cexWithdrawalURLMW, err := newReverseProxyMW(cexWithdrawalURL)
+		cexWithdrawalURLMW, err := s.newReverseProxyMW(cexWithdrawalURL)
 		if err != nil {
 			return err
 		}
@@ -121,7 +135,7 @@ func WithCexWithdrawalURL(cexWithdrawalURL string) Option {
 // WithCexDepositURL return withdraw proxy
 func WithCexDepositURL(cexDepositURL string) Option {
 	return func(s *Server) error {
-		cexDepositURLMW, err := newReverseProxyMW(cexDepositURL)
+		cexDepositURLMW, err := s.newReverseProxyMW(cexDepositURL)
 		if err != nil {
 			return err
 		}
@@ -133,11 +147,15 @@ func WithCexDepositURL(cexDepositURL string) Option {
 //WithReserveTokenURL return reserve token proxy
 func WithReserveTokenURL(reserveTokenURL string) Option {
 	return func(s *Server) error {
-		reserveTokenURLMW, err := newReverseProxyMW(reserveTokenURL)
+		reserveTokenURLMW, err := s.newReverseProxyMW(reserveTokenURL)
 		if err != nil {
 			return err
 		}
 		s.r.GET("/reserve/tokens", reserveTokenURLMW)
+		s.r.GET("/reserve/tokens/versions", reserveTokenURLMW)
+		s.r.GET("/reserve/tokens/events", reserveTokenURLMW)
+		s.r.GET("/reserve/tokens/at", reserveTokenURLMW)
+		s.r.GET("/reserve/tokens/diff", reserveTokenURLMW)
 		return nil
 	}
 }
@@ -145,7 +163,7 @@ func WithReserveTokenURL(reserveTokenURL string) Option {
 //WithReserveTransactionURL return withdraw proxy
 func WithReserveTransactionURL(reserveTransactionURL string) Option {
 	return func(s *Server) error {
-		reserveTransactionURLMW, err := newReverseProxyMW(reserveTransactionURL)
+		reserveTransactionURLMW, err := s.newReverseProxyMW(reserveTransactionURL)
 		if err != nil {
 			return err
 		}
@@ -157,7 +175,7 @@ func WithReserveTransactionURL(reserveTransactionURL string) Option {
 //WithERC20APIURL return withdraw proxy
 func WithERC20APIURL(erc20URL string) Option {
 	return func(s *Server) error {
-		erc20URLMW, err := newReverseProxyMW(erc20URL)
+		erc20URLMW, err := s.newReverseProxyMW(erc20URL)
 		if err != nil {
 			return err
 		}
@@ -165,3 +183,84 @@ func WithERC20APIURL(erc20URL string) Option {
 		return nil
 	}
 }
+
+// WithTokenParamsURL returns token params proxy
+func WithTokenParamsURL(tokenParamsURL string) Option {
+	return func(s *Server) error {
+		tokenParamsURLMW, err := s.newReverseProxyMW(tokenParamsURL)
+		if err != nil {
+			return err
+		}
+		s.r.GET("/reserve/token-params", tokenParamsURLMW)
+		return nil
+	}
+}
+
+// WithReserveCompetitivenessURL returns reserve competitiveness proxy
+func WithReserveCompetitivenessURL(reserveCompetitivenessURL string) Option {
+	return func(s *Server) error {
+		reserveCompetitivenessURLMW, err := s.newReverseProxyMW(reserveCompetitivenessURL)
+		if err != nil {
+			return err
+		}
+		s.r.GET("/reserve-competitiveness", reserveCompetitivenessURLMW)
+		return nil
+	}
+}
+
+// WithPnLURL returns pnl proxy
+func WithPnLURL(pnlURL string) Option {
+	return func(s *Server) error {
+		pnlURLMW, err := s.newReverseProxyMW(pnlURL)
+		if err != nil {
+			return err
+		}
+		s.r.GET("/pnl", pnlURLMW)
+		return nil
+	}
+}
+
+// WithTransferMatcherURL returns transfer matcher proxy
+func WithTransferMatcherURL(transferMatcherURL string) Option {
+	return func(s *Server) error {
+		transferMatcherURLMW, err := s.newReverseProxyMW(transferMatcherURL)
+		if err != nil {
+			return err
+		}
+		s.r.GET("/transfer-matches", transferMatcherURLMW)
+		return nil
+	}
+}
+
+// WithLedgerURL returns ledger proxy
+func WithLedgerURL(ledgerURL string) Option {
+	return func(s *Server) error {
+		ledgerURLMW, err := s.newReverseProxyMW(ledgerURL)
+		if err != nil {
+			return err
+		}
+		s.r.GET("/ledger/accounts", ledgerURLMW)
+		s.r.GET("/ledger/trial-balance", ledgerURLMW)
+		s.r.GET("/ledger/general-ledger", ledgerURLMW)
+		s.r.GET("/ledger/periods", ledgerURLMW)
+		s.r.POST("/ledger/periods", ledgerURLMW)
+		return nil
+	}
+}
+
+// WithRateLimiter limits requests by key id with given limiter. The middleware only applies to
+// routes registered after it, so this option must be given before the route options.
+func WithRateLimiter(l *ratelimit.Limiter) Option {
+	return func(s *Server) error {
+		s.r.Use(l.Middleware())
+		return nil
+	}
+}
+
+// WithUpstreamDefaults sets the default config of upstreams, this option must be given before the route options.
+func WithUpstreamDefaults(defaults upstream.Config) Option {
+	return func(s *Server) error {
+		s.upstreams = upstream.NewRegistry(s.sugar, defaults)
+		return nil
+	}
+}
@@ -9,6 +9,25 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Model is the casbin model of gateway permission. A policy grants a key access to paths
+// matched by keyMatch pattern with HTTP methods matched by regular expression.
+const Model = `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _ , _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub)  && keyMatch(r.obj, p.obj) && regexMatch(r.act, p.act)
+`
+
 const (
 	authorizationHeader = "Authorization"
 	signatureHeader     = "Signature"
@@ -43,7 +62,7 @@ func NewPermissioner(e *casbin.Enforcer) gin.HandlerFunc {
 
 // checkPermission return a gin middleware which check if a request is authorize to continue or not
 func (p *Permissioner) checkPermission(r *http.Request) bool {
-	keyID, err := getKeyID(r)
+	keyID, err := GetKeyID(r)
 	if err != nil {
 		return false
 	}
@@ -66,7 +85,8 @@ func extractKeyID(s string) (KeyID, error) {
 	return KeyID(""), ErrCouldNotGetKeyID
 }
 
-func getKeyID(r *http.Request) (KeyID, error) {
+// GetKeyID returns the key id of a signed request.
+func GetKeyID(r *http.Request) (KeyID, error) {
 	if s := r.Header.Get(authorizationHeader); len(s) > 0 {
 		return extractKeyID(s)
 	}
@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	libredis "github.com/KyberNetwork/reserve-stats/lib/redis"
+)
+
+const (
+	configFlag = "rate-limit-config"
+
+	storageFlag    = "rate-limit-storage"
+	memoryStorage  = "memory"
+	redisStorage   = "redis"
+	defaultStorage = memoryStorage
+)
+
+// NewCliFlags returns cli flags to configure rate limiting. Redis connection is configured
+// with lib/redis flags.
+func NewCliFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   configFlag,
+			Usage:  "JSON file of rate limit groups, rate limiting is disabled if not provided",
+			EnvVar: "RATE_LIMIT_CONFIG",
+		},
+		cli.StringFlag{
+			Name:   storageFlag,
+			Usage:  fmt.Sprintf("rate limit storage, %s or %s", memoryStorage, redisStorage),
+			EnvVar: "RATE_LIMIT_STORAGE",
+			Value:  defaultStorage,
+		},
+	}
+}
+
+// NewLimiterFromContext creates a Limiter from cli flags, it returns nil if rate limiting is not configured.
+func NewLimiterFromContext(c *cli.Context, sugar *zap.SugaredLogger) (*Limiter, error) {
+	configFile := c.String(configFlag)
+	if len(configFile) == 0 {
+		return nil, nil
+	}
+	cfg, err := LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate limit config %s: %s", configFile, err)
+	}
+
+	var store Store
+	switch c.String(storageFlag) {
+	case memoryStorage:
+		store = NewMemoryStore()
+	case redisStorage:
+		client, err := libredis.NewClientFromContext(c)
+		if err != nil {
+			return nil, err
+		}
+		store = NewRedisStore(client)
+	default:
+		return nil, fmt.Errorf("unsupported rate limit storage %s", c.String(storageFlag))
+	}
+	return NewLimiter(sugar, store, cfg), nil
+}
@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"time"
+
+	"github.com/casbin/casbin/util"
+)
+
+// Limit is the rate limit and quota of a key for a route group.
+type Limit struct {
+	// Rate is the number of requests refilled to the token bucket per second.
+	Rate float64 `json:"rate"`
+	// Burst is the capacity of the token bucket.
+	Burst int64 `json:"burst"`
+	// DailyQuota is the maximum number of requests per UTC day, 0 is unlimited.
+	DailyQuota int64 `json:"daily_quota"`
+}
+
+// Group is a group of routes sharing the same limit. Requests to any route of the group consume
+// from the same token bucket and quota of the key.
+type Group struct {
+	Name string `json:"name"`
+	// Paths are keyMatch patterns, for example: /trade-logs or /reserve/*.
+	Paths []string `json:"paths"`
+	Limit
+}
+
+// Config is the rate limit configuration. Routes not in any group are not limited.
+type Config struct {
+	Groups []Group `json:"groups"`
+}
+
+// Validate checks if the configuration is valid.
+func (c Config) Validate() error {
+	names := make(map[string]struct{})
+	for _, group := range c.Groups {
+		if len(group.Name) == 0 {
+			return errors.New("group name is required")
+		}
+		if _, ok := names[group.Name]; ok {
+			return fmt.Errorf("duplicated group %s", group.Name)
+		}
+		names[group.Name] = struct{}{}
+		if len(group.Paths) == 0 {
+			return fmt.Errorf("group %s has no paths", group.Name)
+		}
+		if group.Rate <= 0 || group.Burst <= 0 {
+			return fmt.Errorf("group %s must have positive rate and burst", group.Name)
+		}
+		if group.DailyQuota < 0 {
+			return fmt.Errorf("group %s has negative daily quota", group.Name)
+		}
+	}
+	return nil
+}
+
+// match returns the first group that has a path pattern matching given path.
+func (c Config) match(path string) (Group, bool) {
+	for _, group := range c.Groups {
+		for _, pattern := range group.Paths {
+			if util.KeyMatch(path, pattern) {
+				return group, true
+			}
+		}
+	}
+	return Group{}, false
+}
+
+// LoadConfig reads the configuration from a JSON file.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Result is the result of taking a request from the bucket and quota of a key.
+type Result struct {
+	Allowed bool
+	// RetryAfter is the duration to wait before the next request could be allowed.
+	RetryAfter time.Duration
+	// RemainingQuota is the number of requests left today, -1 if the quota is unlimited.
+	RemainingQuota int64
+}
+
+// Store stores token buckets and quota usages of keys.
+type Store interface {
+	Take(id string, limit Limit, now time.Time) (Result, error)
+}
+
+// bucket is the state of a token bucket.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// nextDay returns the start of next UTC day, when quotas are reset.
+func nextDay(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+}
+
+// take refills the bucket to now and consumes a token if available and the quota is not exhausted.
+// The quota usage should be increased if the request is allowed.
+func take(b *bucket, used int64, limit Limit, now time.Time) Result {
+	var (
+		remaining int64 = -1
+		burst           = float64(limit.Burst)
+	)
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed*limit.Rate)
+		b.last = now
+	}
+	if limit.DailyQuota > 0 {
+		remaining = limit.DailyQuota - used
+		if remaining <= 0 {
+			return Result{RetryAfter: nextDay(now).Sub(now), RemainingQuota: 0}
+		}
+	}
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+		return Result{RetryAfter: wait, RemainingQuota: remaining}
+	}
+	b.tokens--
+	if remaining > 0 {
+		remaining--
+	}
+	return Result{Allowed: true, RemainingQuota: remaining}
+}
@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type quotaUsage struct {
+	day  time.Time
+	used int64
+}
+
+// MemoryStore keeps token buckets and quota usages in memory. It is only suitable for a single
+// gateway instance, use RedisStore to share limits between instances.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	quotas  map[string]*quotaUsage
+}
+
+// NewMemoryStore creates a new MemoryStore instance.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		quotas:  make(map[string]*quotaUsage),
+	}
+}
+
+// Take takes a request from the bucket and quota of given id.
+func (s *MemoryStore) Take(id string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[id]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[id] = b
+	}
+	day := nextDay(now)
+	q, ok := s.quotas[id]
+	if !ok || !q.day.Equal(day) {
+		q = &quotaUsage{day: day}
+		s.quotas[id] = q
+	}
+
+	result := take(b, q.used, limit, now)
+	if result.Allowed {
+		q.used++
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/gateway/permission"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+const (
+	retryAfterHeader     = "Retry-After"
+	quotaLimitHeader     = "X-Quota-Limit"
+	quotaRemainingHeader = "X-Quota-Remaining"
+)
+
+// Limiter limits requests per key id and route group.
+type Limiter struct {
+	sugar *zap.SugaredLogger
+	store Store
+	cfg   Config
+	now   func() time.Time
+}
+
+// NewLimiter creates a new Limiter instance.
+func NewLimiter(sugar *zap.SugaredLogger, store Store, cfg Config) *Limiter {
+	return &Limiter{
+		sugar: sugar,
+		store: store,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// Middleware returns the gin middleware that rejects requests exceeding the limit of their group
+// with 429 status. Requests without key id are passed through to be rejected by permission check.
+// If the store is not available, requests are allowed.
+func (l *Limiter) Middleware() gin.HandlerFunc {
+	logger := l.sugar.With("func", caller.GetCurrentFunctionName())
+	return func(c *gin.Context) {
+		group, ok := l.cfg.match(c.Request.URL.Path)
+		if !ok {
+			return
+		}
+		keyID, err := permission.GetKeyID(c.Request)
+		if err != nil {
+			return
+		}
+
+		result, err := l.store.Take(group.Name+":"+string(keyID), group.Limit, l.now())
+		if err != nil {
+			logger.Errorw("failed to check rate limit, allowing request",
+				"group", group.Name, "key_id", keyID, "error", err)
+			return
+		}
+		if group.DailyQuota > 0 {
+			c.Header(quotaLimitHeader, strconv.FormatInt(group.DailyQuota, 10))
+			c.Header(quotaRemainingHeader, strconv.FormatInt(result.RemainingQuota, 10))
+		}
+		if result.Allowed {
+			return
+		}
+		retryAfter := int64(math.Ceil(result.RetryAfter.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		c.Header(retryAfterHeader, strconv.FormatInt(retryAfter, 10))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+	}
+}
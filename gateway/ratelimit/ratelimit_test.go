@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/httpsign-utils/sign"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	var (
+		store = NewMemoryStore()
+		limit = Limit{Rate: 1, Burst: 2, DailyQuota: 4}
+		now   = time.Date(2020, 6, 1, 23, 59, 0, 0, time.UTC)
+	)
+
+	for i := 0; i < 2; i++ {
+		result, err := store.Take("key", limit, now)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, int64(3-i), result.RemainingQuota)
+	}
+
+	result, err := store.Take("key", limit, now)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, int64(2), result.RemainingQuota)
+
+	// other keys have their own bucket
+	result, err = store.Take("other", limit, now)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	now = now.Add(10 * time.Second)
+	for i := 0; i < 2; i++ {
+		result, err = store.Take("key", limit, now)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	// quota is exhausted until next day
+	now = now.Add(10 * time.Second)
+	result, err = store.Take("key", limit, now)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, int64(0), result.RemainingQuota)
+	assert.Equal(t, 40*time.Second, result.RetryAfter)
+
+	now = now.Add(time.Minute)
+	result, err = store.Take("key", limit, now)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, int64(3), result.RemainingQuota)
+}
+
+func TestLimiterMiddleware(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	cfg := Config{Groups: []Group{
+		{Name: "reports", Paths: []string{"/trade-logs", "/top-*"}, Limit: Limit{Rate: 0.01, Burst: 1, DailyQuota: 10}},
+	}}
+	require.NoError(t, cfg.Validate())
+	limiter := NewLimiter(logger.Sugar(), NewMemoryStore(), cfg)
+
+	r := gin.New()
+	r.Use(limiter.Middleware())
+	handler := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/trade-logs", handler)
+	r.GET("/top-tokens", handler)
+	r.GET("/users", handler)
+
+	do := func(path, keyID string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		req, err = sign.Sign(req, keyID, "secret")
+		require.NoError(t, err)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		return resp
+	}
+
+	resp := do("/trade-logs", "partner")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "10", resp.Header().Get(quotaLimitHeader))
+	assert.Equal(t, "9", resp.Header().Get(quotaRemainingHeader))
+
+	// routes of the same group share the bucket
+	resp = do("/top-tokens", "partner")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "100", resp.Header().Get(retryAfterHeader))
+	assert.Equal(t, "9", resp.Header().Get(quotaRemainingHeader))
+
+	assert.Equal(t, http.StatusOK, do("/top-tokens", "other").Code)
+	assert.Equal(t, http.StatusOK, do("/users", "partner").Code)
+}
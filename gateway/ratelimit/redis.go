@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const redisKeyPrefix = "gateway:ratelimit:"
+
+// takeScript is the Redis version of take function. It is run atomically so instances
+// sharing the same Redis see consistent buckets and quotas.
+//
+// KEYS[1]: bucket hash key, KEYS[2]: quota counter key of current day
+// ARGV: rate per millisecond, burst, daily quota, now in millis, quota key ttl in seconds
+// returns: {allowed, retry after in millis, remaining quota}
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local quota = tonumber(ARGV[3])
+local now = tonumber(ARGV[4])
+local quota_ttl = tonumber(ARGV[5])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate)
+	ts = now
+end
+
+local remaining = -1
+if quota > 0 then
+	local used = tonumber(redis.call('GET', KEYS[2]) or '0')
+	remaining = quota - used
+	if remaining <= 0 then
+		return {0, quota_ttl * 1000, 0}
+	end
+end
+
+local bucket_ttl = math.ceil(burst / rate)
+if tokens < 1 then
+	redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+	redis.call('PEXPIRE', KEYS[1], bucket_ttl)
+	return {0, math.ceil((1 - tokens) / rate), remaining}
+end
+
+tokens = tokens - 1
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(ts))
+redis.call('PEXPIRE', KEYS[1], bucket_ttl)
+if quota > 0 then
+	redis.call('INCR', KEYS[2])
+	redis.call('EXPIRE', KEYS[2], quota_ttl)
+	remaining = remaining - 1
+end
+return {1, 0, remaining}
+`)
+
+// RedisStore keeps token buckets and quota usages in Redis.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a new RedisStore instance.
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Take takes a request from the bucket and quota of given id.
+func (s *RedisStore) Take(id string, limit Limit, now time.Time) (Result, error) {
+	var (
+		day      = nextDay(now)
+		quotaTTL = int64(day.Sub(now)/time.Second) + 1
+		keys     = []string{
+			redisKeyPrefix + id + ":bucket",
+			fmt.Sprintf("%s%s:quota:%s", redisKeyPrefix, id, day.AddDate(0, 0, -1).Format("2006-01-02")),
+		}
+	)
+	reply, err := takeScript.Run(s.client, keys,
+		limit.Rate/1000,
+		limit.Burst,
+		limit.DailyQuota,
+		now.UnixNano()/int64(time.Millisecond),
+		quotaTTL,
+	).Result()
+	if err != nil {
+		return Result{}, err
+	}
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 3 {
+		return Result{}, fmt.Errorf("unexpected reply from redis: %v", reply)
+	}
+	var ints [3]int64
+	for i, v := range values {
+		if ints[i], ok = v.(int64); !ok {
+			return Result{}, fmt.Errorf("unexpected reply from redis: %v", reply)
+		}
+	}
+	return Result{
+		Allowed:        ints[0] == 1,
+		RetryAfter:     time.Duration(ints[1]) * time.Millisecond,
+		RemainingQuota: ints[2],
+	}, nil
+}
@@ -0,0 +1,106 @@
+package upstream
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState string
+
+const (
+	// CircuitClosed allows all requests.
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen rejects all requests until the open timeout passed.
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen allows one trial request, which closes the circuit if succeeded.
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// Breaker is a circuit breaker which opens after a number of consecutive failures.
+type Breaker struct {
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	// trial is true if the trial request of half-open state is in flight.
+	trial bool
+}
+
+// NewBreaker creates a closed circuit breaker.
+func NewBreaker(threshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		now:         time.Now,
+		state:       CircuitClosed,
+	}
+}
+
+// Allow returns true if a request is allowed. A request allowed in half-open state is the trial request,
+// its result must be reported with Success or Failure.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = CircuitHalfOpen
+		b.trial = true
+		return true
+	case CircuitHalfOpen:
+		if b.trial {
+			return false
+		}
+		b.trial = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Success records a successful request and closes the circuit.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = CircuitClosed
+	b.failures = 0
+	b.trial = false
+}
+
+// Failure records a failed request, the circuit is opened if the failed request is the trial request
+// or the number of consecutive failures reaches the threshold.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.threshold {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+		b.trial = false
+	}
+}
+
+// Cancel records a request without result, e.g. canceled by client, which allows another trial request
+// in half-open state.
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// State returns the current state of the circuit.
+func (b *Breaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.openTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
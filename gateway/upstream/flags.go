@@ -0,0 +1,69 @@
+package upstream
+
+import (
+	"github.com/urfave/cli"
+)
+
+const (
+	timeoutFlag          = "upstream-timeout"
+	healthPathFlag       = "upstream-health-path"
+	healthIntervalFlag   = "upstream-health-interval"
+	healthTimeoutFlag    = "upstream-health-timeout"
+	failureThresholdFlag = "upstream-failure-threshold"
+	openTimeoutFlag      = "upstream-open-timeout"
+)
+
+// NewCliFlags returns cli flags to configure the defaults of upstreams.
+func NewCliFlags() []cli.Flag {
+	defaults := DefaultConfig()
+	return []cli.Flag{
+		cli.DurationFlag{
+			Name:   timeoutFlag,
+			Usage:  "maximum duration to wait for upstream response",
+			EnvVar: "UPSTREAM_TIMEOUT",
+			Value:  defaults.Timeout,
+		},
+		cli.StringFlag{
+			Name:   healthPathFlag,
+			Usage:  "path of upstreams probed with GET requests, 5xx response or no response is unhealthy",
+			EnvVar: "UPSTREAM_HEALTH_PATH",
+			Value:  defaults.HealthPath,
+		},
+		cli.DurationFlag{
+			Name:   healthIntervalFlag,
+			Usage:  "interval of upstream health checks",
+			EnvVar: "UPSTREAM_HEALTH_INTERVAL",
+			Value:  defaults.HealthInterval,
+		},
+		cli.DurationFlag{
+			Name:   healthTimeoutFlag,
+			Usage:  "timeout of upstream health checks",
+			EnvVar: "UPSTREAM_HEALTH_TIMEOUT",
+			Value:  defaults.HealthTimeout,
+		},
+		cli.IntFlag{
+			Name:   failureThresholdFlag,
+			Usage:  "number of consecutive failed requests to open the circuit of an upstream instance",
+			EnvVar: "UPSTREAM_FAILURE_THRESHOLD",
+			Value:  defaults.FailureThreshold,
+		},
+		cli.DurationFlag{
+			Name:   openTimeoutFlag,
+			Usage:  "duration requests to an upstream instance with open circuit fail fast before a trial request",
+			EnvVar: "UPSTREAM_OPEN_TIMEOUT",
+			Value:  defaults.OpenTimeout,
+		},
+	}
+}
+
+// NewDefaultConfigFromContext returns the default config of upstreams from cli flags.
+func NewDefaultConfigFromContext(c *cli.Context) Config {
+	return Config{
+		Timeout:          c.Duration(timeoutFlag),
+		HealthPath:       c.String(healthPathFlag),
+		HealthInterval:   c.Duration(healthIntervalFlag),
+		HealthTimeout:    c.Duration(healthTimeoutFlag),
+		FailureThreshold: c.Int(failureThresholdFlag),
+		OpenTimeout:      c.Duration(openTimeoutFlag),
+	}
+}
@@ -0,0 +1,86 @@
+package upstream
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Registry holds the upstreams of gateway.
+type Registry struct {
+	sugar    *zap.SugaredLogger
+	defaults Config
+
+	mu        sync.RWMutex
+	upstreams map[string]*Upstream
+}
+
+// NewRegistry creates an empty registry, upstreams are added with given default config.
+func NewRegistry(sugar *zap.SugaredLogger, defaults Config) *Registry {
+	return &Registry{
+		sugar:     sugar,
+		defaults:  defaults,
+		upstreams: make(map[string]*Upstream),
+	}
+}
+
+// Add creates an upstream with given config, the zero values of config are replaced by the defaults.
+func (r *Registry) Add(name string, cfg Config) (*Upstream, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.upstreams[name]; ok {
+		return nil, fmt.Errorf("upstream %s already exists", name)
+	}
+	u, err := New(r.sugar, name, cfg.WithDefaults(r.defaults))
+	if err != nil {
+		return nil, err
+	}
+	r.upstreams[name] = u
+	return u, nil
+}
+
+// Get returns the upstream of given name, nil if not exists.
+func (r *Registry) Get(name string) *Upstream {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.upstreams[name]
+}
+
+// Upstreams returns all upstreams sorted by name.
+func (r *Registry) Upstreams() []*Upstream {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var upstreams []*Upstream
+	for _, u := range r.upstreams {
+		upstreams = append(upstreams, u)
+	}
+	sort.Slice(upstreams, func(i, j int) bool {
+		return upstreams[i].Name() < upstreams[j].Name()
+	})
+	return upstreams
+}
+
+// Run starts health checks of all upstreams in background.
+func (r *Registry) Run() {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, u := range r.upstreams {
+		go u.Run()
+	}
+}
+
+// Status returns the states of all upstreams sorted by name.
+func (r *Registry) Status() []Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var statuses []Status
+	for _, u := range r.upstreams {
+		statuses = append(statuses, u.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
@@ -0,0 +1,334 @@
+package upstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// ErrUnavailable is returned when all instances of an upstream are unhealthy or have open circuit.
+var ErrUnavailable = errors.New("upstream is unavailable")
+
+// Config is the configuration of an upstream service. Zero values are replaced with the defaults.
+type Config struct {
+	// URLs are the instances of the service, requests are load balanced in round robin.
+	URLs []string `yaml:"urls"`
+	// Timeout is the maximum duration to wait for upstream response.
+	Timeout time.Duration `yaml:"timeout"`
+	// HealthPath is probed with GET requests, instances responding 5xx status or not responding are unhealthy.
+	HealthPath     string        `yaml:"health_path"`
+	HealthInterval time.Duration `yaml:"health_interval"`
+	HealthTimeout  time.Duration `yaml:"health_timeout"`
+	// FailureThreshold is the number of consecutive failed requests to open the circuit of an instance.
+	FailureThreshold int `yaml:"failure_threshold"`
+	// OpenTimeout is the duration the circuit stays open before a trial request is allowed.
+	OpenTimeout time.Duration `yaml:"open_timeout"`
+}
+
+// DefaultConfig returns the default configuration of upstreams without URLs.
+func DefaultConfig() Config {
+	return Config{
+		Timeout:          time.Minute,
+		HealthPath:       "/",
+		HealthInterval:   10 * time.Second,
+		HealthTimeout:    5 * time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// UnmarshalYAML allows an upstream with single instance to be configured with its URL only.
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var singleURL string
+	if err := unmarshal(&singleURL); err == nil {
+		*c = Config{URLs: []string{singleURL}}
+		return nil
+	}
+	type plain Config
+	return unmarshal((*plain)(c))
+}
+
+// WithDefaults returns the config with zero values replaced by given defaults.
+func (c Config) WithDefaults(defaults Config) Config {
+	if c.Timeout == 0 {
+		c.Timeout = defaults.Timeout
+	}
+	if len(c.HealthPath) == 0 {
+		c.HealthPath = defaults.HealthPath
+	}
+	if c.HealthInterval == 0 {
+		c.HealthInterval = defaults.HealthInterval
+	}
+	if c.HealthTimeout == 0 {
+		c.HealthTimeout = defaults.HealthTimeout
+	}
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = defaults.FailureThreshold
+	}
+	if c.OpenTimeout == 0 {
+		c.OpenTimeout = defaults.OpenTimeout
+	}
+	return c
+}
+
+// Validate checks the URLs and durations of the config.
+func (c Config) Validate() error {
+	if len(c.URLs) == 0 {
+		return errors.New("no urls")
+	}
+	for _, rawURL := range c.URLs {
+		u, err := url.Parse(rawURL)
+		if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+			return fmt.Errorf("invalid url %q", rawURL)
+		}
+	}
+	if c.Timeout < 0 || c.HealthInterval < 0 || c.HealthTimeout < 0 || c.OpenTimeout < 0 {
+		return errors.New("negative duration")
+	}
+	if c.FailureThreshold < 0 {
+		return errors.New("negative failure threshold")
+	}
+	if !strings.HasPrefix(c.HealthPath, "/") {
+		return fmt.Errorf("health path %q must start with /", c.HealthPath)
+	}
+	return nil
+}
+
+type instance struct {
+	url     *url.URL
+	breaker *Breaker
+
+	mu        sync.RWMutex
+	healthy   bool
+	lastCheck time.Time
+	lastError string
+}
+
+func (i *instance) isHealthy() bool {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.healthy
+}
+
+// InstanceStatus is the state of an upstream instance.
+type InstanceStatus struct {
+	URL       string       `json:"url"`
+	Healthy   bool         `json:"healthy"`
+	Circuit   CircuitState `json:"circuit"`
+	LastCheck time.Time    `json:"-"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// MarshalJSON implements custom JSON marshaler for InstanceStatus to format timestamps in unix millis.
+func (s InstanceStatus) MarshalJSON() ([]byte, error) {
+	type AliasInstanceStatus InstanceStatus
+	var lastCheck *uint64
+	if !s.LastCheck.IsZero() {
+		ts := timeutil.TimeToTimestampMs(s.LastCheck)
+		lastCheck = &ts
+	}
+	return json.Marshal(struct {
+		LastCheck *uint64 `json:"last_check"`
+		AliasInstanceStatus
+	}{
+		AliasInstanceStatus: (AliasInstanceStatus)(s),
+		LastCheck:           lastCheck,
+	})
+}
+
+// Status is the state of an upstream, which is available if any instance is available.
+type Status struct {
+	Name      string           `json:"name"`
+	Available bool             `json:"available"`
+	Instances []InstanceStatus `json:"instances"`
+}
+
+// Upstream is an upstream service of gateway. It implements http.RoundTripper, which sends requests to its
+// instances in round robin, skipping unhealthy ones and ones having open circuit.
+type Upstream struct {
+	sugar     *zap.SugaredLogger
+	name      string
+	cfg       Config
+	instances []*instance
+	next      uint32
+	transport http.RoundTripper
+}
+
+// New creates an upstream, instances are considered healthy until the first health check.
+func New(sugar *zap.SugaredLogger, name string, cfg Config) (*Upstream, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("upstream %s: %s", name, err)
+	}
+	u := &Upstream{
+		sugar:     sugar,
+		name:      name,
+		cfg:       cfg,
+		transport: http.DefaultTransport,
+	}
+	for _, rawURL := range cfg.URLs {
+		parsed, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		u.instances = append(u.instances, &instance{
+			url:     parsed,
+			breaker: NewBreaker(cfg.FailureThreshold, cfg.OpenTimeout),
+			healthy: true,
+		})
+	}
+	return u, nil
+}
+
+// Name returns the name of upstream.
+func (u *Upstream) Name() string {
+	return u.name
+}
+
+// Timeout returns the maximum duration to wait for upstream response.
+func (u *Upstream) Timeout() time.Duration {
+	return u.cfg.Timeout
+}
+
+func (u *Upstream) pick() *instance {
+	start := atomic.AddUint32(&u.next, 1)
+	for i := range u.instances {
+		inst := u.instances[(int(start)+i)%len(u.instances)]
+		if inst.isHealthy() && inst.breaker.Allow() {
+			return inst
+		}
+	}
+	return nil
+}
+
+func isGatewayError(status int) bool {
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func joinPath(a, b string) string {
+	return strings.TrimSuffix(a, "/") + "/" + strings.TrimPrefix(b, "/")
+}
+
+// RoundTrip sends the request to an available instance, the scheme and host of request URL are replaced
+// with the instance's ones. ErrUnavailable is returned if no instance is available.
+func (u *Upstream) RoundTrip(req *http.Request) (*http.Response, error) {
+	inst := u.pick()
+	if inst == nil {
+		return nil, ErrUnavailable
+	}
+	out := req.Clone(req.Context())
+	out.URL.Scheme = inst.url.Scheme
+	out.URL.Host = inst.url.Host
+	if len(inst.url.Path) != 0 {
+		out.URL.Path = joinPath(inst.url.Path, req.URL.Path)
+		out.URL.RawPath = ""
+	}
+
+	resp, err := u.transport.RoundTrip(out)
+	switch {
+	case err != nil && req.Context().Err() == context.Canceled:
+		inst.breaker.Cancel()
+	case err != nil || isGatewayError(resp.StatusCode):
+		inst.breaker.Failure()
+	default:
+		inst.breaker.Success()
+	}
+	return resp, err
+}
+
+func (u *Upstream) check(inst *instance) {
+	ctx, cancel := context.WithTimeout(context.Background(), u.cfg.HealthTimeout)
+	defer cancel()
+
+	var checkErr error
+	req, err := http.NewRequest(http.MethodGet, joinPath(inst.url.String(), u.cfg.HealthPath), nil)
+	if err != nil {
+		checkErr = err
+	} else {
+		resp, err := u.transport.RoundTrip(req.WithContext(ctx))
+		if err != nil {
+			checkErr = err
+		} else {
+			_ = resp.Body.Close()
+			if resp.StatusCode >= http.StatusInternalServerError {
+				checkErr = fmt.Errorf("unhealthy status %d", resp.StatusCode)
+			}
+		}
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if inst.healthy != (checkErr == nil) {
+		u.sugar.Infow("upstream instance health changed",
+			"func", caller.GetCurrentFunctionName(),
+			"upstream", u.name,
+			"url", inst.url.String(),
+			"healthy", checkErr == nil,
+			"error", checkErr)
+	}
+	inst.healthy = checkErr == nil
+	inst.lastCheck = time.Now()
+	inst.lastError = ""
+	if checkErr != nil {
+		inst.lastError = checkErr.Error()
+	}
+}
+
+// Check probes health of all instances.
+func (u *Upstream) Check() {
+	var wg sync.WaitGroup
+	for _, inst := range u.instances {
+		wg.Add(1)
+		go func(inst *instance) {
+			defer wg.Done()
+			u.check(inst)
+		}(inst)
+	}
+	wg.Wait()
+}
+
+// Run probes health of all instances periodically.
+func (u *Upstream) Run() {
+	u.Check()
+	ticker := time.NewTicker(u.cfg.HealthInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		u.Check()
+	}
+}
+
+// Status returns the current state of upstream and its instances.
+func (u *Upstream) Status() Status {
+	status := Status{Name: u.name}
+	for _, inst := range u.instances {
+		inst.mu.RLock()
+		instStatus := InstanceStatus{
+			URL:       inst.url.String(),
+			Healthy:   inst.healthy,
+			Circuit:   inst.breaker.State(),
+			LastCheck: inst.lastCheck,
+			Error:     inst.lastError,
+		}
+		inst.mu.RUnlock()
+		if instStatus.Healthy && instStatus.Circuit != CircuitOpen {
+			status.Available = true
+		}
+		status.Instances = append(status.Instances, instStatus)
+	}
+	return status
+}
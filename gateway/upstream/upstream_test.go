@@ -0,0 +1,137 @@
+package upstream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	b := NewBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	assert.True(t, b.Allow())
+	b.Failure()
+	assert.Equal(t, CircuitClosed, b.State())
+	b.Failure()
+	assert.Equal(t, CircuitOpen, b.State())
+	assert.False(t, b.Allow())
+
+	// only one trial request is allowed after open timeout
+	now = now.Add(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, b.State())
+	assert.True(t, b.Allow())
+	assert.False(t, b.Allow())
+	b.Failure()
+	assert.Equal(t, CircuitOpen, b.State())
+
+	now = now.Add(time.Minute)
+	assert.True(t, b.Allow())
+	b.Success()
+	assert.Equal(t, CircuitClosed, b.State())
+	assert.True(t, b.Allow())
+}
+
+func TestConfigUnmarshalYAML(t *testing.T) {
+	var upstreams map[string]Config
+	require.NoError(t, yaml.UnmarshalStrict([]byte(`
+single: http://127.0.0.1:8000
+multiple:
+  urls: [http://127.0.0.1:8001, http://127.0.0.1:8002]
+  timeout: 5s
+`), &upstreams))
+	assert.Equal(t, Config{URLs: []string{"http://127.0.0.1:8000"}}, upstreams["single"])
+	assert.Equal(t, Config{
+		URLs:    []string{"http://127.0.0.1:8001", "http://127.0.0.1:8002"},
+		Timeout: 5 * time.Second,
+	}, upstreams["multiple"])
+}
+
+func TestUpstream(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	var healthyCalls, brokenCalls int32
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&healthyCalls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&brokenCalls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+
+	cfg := Config{URLs: []string{healthy.URL, broken.URL}, FailureThreshold: 2}
+	u, err := New(logger.Sugar(), "test", cfg.WithDefaults(DefaultConfig()))
+	require.NoError(t, err)
+
+	do := func() int {
+		req, err := http.NewRequest(http.MethodGet, "http://test/path", nil)
+		require.NoError(t, err)
+		resp, err := u.RoundTrip(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode
+	}
+
+	// requests are load balanced until the circuit of broken instance is open
+	for i := 0; i < 6; i++ {
+		do()
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&brokenCalls))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&healthyCalls))
+	status := u.Status()
+	assert.True(t, status.Available)
+	assert.Equal(t, CircuitOpen, status.Instances[1].Circuit)
+
+	// healthy instance is skipped after failed health check
+	healthy.Close()
+	u.Check()
+	status = u.Status()
+	assert.False(t, status.Available)
+	assert.False(t, status.Instances[0].Healthy)
+	assert.NotEmpty(t, status.Instances[0].Error)
+
+	req, err := http.NewRequest(http.MethodGet, "http://test/path", nil)
+	require.NoError(t, err)
+	_, err = u.RoundTrip(req)
+	assert.Equal(t, ErrUnavailable, err)
+}
+
+func TestRegistry(t *testing.T) {
+	logger, err := zap.NewDevelopment()
+	require.NoError(t, err)
+
+	r := NewRegistry(logger.Sugar(), DefaultConfig())
+	u, err := r.Add("b", Config{URLs: []string{"http://127.0.0.1:8000"}})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig().Timeout, u.Timeout())
+	_, err = r.Add("a", Config{URLs: []string{"http://127.0.0.1:8001"}, Timeout: time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, r.Get("a").Timeout())
+
+	_, err = r.Add("a", Config{URLs: []string{"http://127.0.0.1:8001"}})
+	assert.Error(t, err)
+	_, err = r.Add("c", Config{})
+	assert.Error(t, err)
+
+	statuses := r.Status()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "a", statuses[0].Name)
+	assert.True(t, statuses[0].Available)
+
+	upstreams := r.Upstreams()
+	require.Len(t, upstreams, 2)
+	assert.Equal(t, "a", upstreams[0].Name())
+	assert.Equal(t, u, upstreams[1])
+}
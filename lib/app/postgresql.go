@@ -62,13 +62,19 @@ func NewPostgreSQLFlags(defaultDB string) []cli.Flag {
 
 // NewDBFromContext creates a DB instance from cli flags configuration.
 func NewDBFromContext(c *cli.Context) (*sqlx.DB, error) {
+	return NewDBWithDatabaseFromContext(c, c.String(postgresDatabaseFlag))
+}
+
+// NewDBWithDatabaseFromContext creates a DB instance connecting to given database
+// with the host and credentials from cli flags configuration.
+func NewDBWithDatabaseFromContext(c *cli.Context, database string) (*sqlx.DB, error) {
 	const driverName = "postgres"
 	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
 		c.String(postgresHostFlag),
 		c.Int(postgresPortFlag),
 		c.String(postgresUserFlag),
 		c.String(postgresPasswordFlag),
-		c.String(postgresDatabaseFlag),
+		database,
 	)
 	return sqlx.Connect(driverName, connStr)
 }
@@ -22,9 +22,10 @@ import (
 )
 
 const (
-	endpointPrefix      = "https://api.binance.com"
-	badAPIKeyFormatCode = -2014
-	rejfectedMbxKeyCode = -2015
+	endpointPrefix        = "https://api.binance.com"
+	futuresEndpointPrefix = "https://fapi.binance.com"
+	badAPIKeyFormatCode   = -2014
+	rejfectedMbxKeyCode   = -2015
 )
 
 var (
@@ -46,6 +47,10 @@ type Client struct {
 	sugar       *zap.SugaredLogger
 	rateLimiter Limiter
 	client      *http.Client
+	// spotEndpoint and futuresEndpoint are the base urls of spot (including
+	// margin, wallet and sub-account) and USDⓈ-M futures APIs.
+	spotEndpoint    string
+	futuresEndpoint string
 }
 
 //Option sets the initialization behavior for binance instance
@@ -59,6 +64,15 @@ func WithRateLimiter(limiter Limiter) Option {
 	}
 }
 
+//WithEndpoints alter base urls of spot and USDⓈ-M futures APIs
+func WithEndpoints(spot, futures string) Option {
+	return func(cl *Client) error {
+		cl.spotEndpoint = spot
+		cl.futuresEndpoint = futures
+		return nil
+	}
+}
+
 //WithValidation check if API key is valid by calling GetAccountInfo with its key
 func WithValidation() Option {
 	return func(cl *Client) error {
@@ -76,10 +90,12 @@ func NewBinance(apiKey, secretKey string, sugar *zap.SugaredLogger, options ...O
 		Transport: NewTransportRateLimiter(&http.Client{Timeout: time.Second * 30}),
 	}
 	clnt := &Client{
-		APIKey:    apiKey,
-		SecretKey: secretKey,
-		sugar:     sugar,
-		client:    client,
+		APIKey:          apiKey,
+		SecretKey:       secretKey,
+		sugar:           sugar,
+		client:          client,
+		spotEndpoint:    endpointPrefix,
+		futuresEndpoint: futuresEndpointPrefix,
 	}
 	for _, opt := range options {
 		if err := opt(clnt); err != nil {
@@ -238,7 +254,7 @@ func (bc *Client) GetTradeHistory(symbol string, fromID uint64) ([]TradeHistory,
 		return result, err
 	}
 
-	endpoint := fmt.Sprintf("%s/api/v3/myTrades", endpointPrefix)
+	endpoint := fmt.Sprintf("%s/api/v3/myTrades", bc.spotEndpoint)
 	params := map[string]string{
 		"symbol": symbol,
 	}
@@ -270,7 +286,7 @@ func (bc *Client) GetAssetDetail() (AssetDetailResponse, error) {
 		return result, err
 	}
 
-	endpoint := fmt.Sprintf("%s/wapi/v3/assetDetail.html", endpointPrefix)
+	endpoint := fmt.Sprintf("%s/wapi/v3/assetDetail.html", bc.spotEndpoint)
 	res, err := bc.sendRequest(
 		http.MethodGet,
 		endpoint,
@@ -296,7 +312,7 @@ func (bc *Client) GetWithdrawalHistory(fromTime, toTime time.Time) (WithdrawHist
 		return result, err
 	}
 
-	endpoint := fmt.Sprintf("%s/sapi/v1/capital/withdraw/history", endpointPrefix)
+	endpoint := fmt.Sprintf("%s/sapi/v1/capital/withdraw/history", bc.spotEndpoint)
 
 	params := map[string]string{}
 	if !fromTime.IsZero() {
@@ -333,7 +349,7 @@ func (bc *Client) GetDepositHistory(fromTime, toTime time.Time) ([]DepositHistor
 		return result, err
 	}
 
-	endpoint := fmt.Sprintf("%s/sapi/v1/capital/deposit/hisrec", endpointPrefix)
+	endpoint := fmt.Sprintf("%s/sapi/v1/capital/deposit/hisrec", bc.spotEndpoint)
 
 	params := map[string]string{}
 	if !fromTime.IsZero() {
@@ -373,7 +389,7 @@ func (bc *Client) GetExchangeInfo() (ExchangeInfo, error) {
 		return result, err
 	}
 
-	endpoint := fmt.Sprintf("%s/api/v3/exchangeInfo", endpointPrefix)
+	endpoint := fmt.Sprintf("%s/api/v3/exchangeInfo", bc.spotEndpoint)
 	res, err := bc.sendRequest(
 		http.MethodGet,
 		endpoint,
@@ -399,7 +415,7 @@ func (bc *Client) GetAccountInfo() (AccountInfo, error) {
 		return result, err
 	}
 
-	endpoint := fmt.Sprintf("%s/api/v3/account", endpointPrefix)
+	endpoint := fmt.Sprintf("%s/api/v3/account", bc.spotEndpoint)
 
 	res, err := bc.sendRequest(
 		http.MethodGet,
@@ -416,13 +432,41 @@ func (bc *Client) GetAccountInfo() (AccountInfo, error) {
 	return result, err
 }
 
+// GetMarginAccount return cross margin account balances, it is empty if margin is not enabled
+func (bc *Client) GetMarginAccount() (MarginAccount, error) {
+	var (
+		result MarginAccount
+	)
+	const weight = 10
+	if err := bc.waitN(weight); err != nil {
+		return result, err
+	}
+
+	endpoint := fmt.Sprintf("%s/sapi/v1/margin/account", bc.spotEndpoint)
+	res, err := bc.sendRequest(
+		http.MethodGet,
+		endpoint,
+		nil,
+		true,
+		time.Now(),
+	)
+	if err == Err500 { // margin account is not enabled, same as GetMarginTradeHistory
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(res, &result)
+	return result, err
+}
+
 // GetMarginTradeHistory return margin trade history
 func (bc *Client) GetMarginTradeHistory(symbol string, fromID uint64) ([]TradeHistory, error) {
 	var (
 		result []TradeHistory
 		err    error
 	)
-	endpoint := fmt.Sprintf("%s/sapi/v1/margin/myTrades", endpointPrefix)
+	endpoint := fmt.Sprintf("%s/sapi/v1/margin/myTrades", bc.spotEndpoint)
 	res, err := bc.sendRequest(
 		http.MethodGet,
 		endpoint,
@@ -459,7 +503,7 @@ func (bc *Client) GetAggregatedTrades(symbol string, startTime, endTime uint64,
 		result []AggregatedTrade
 		err    error
 	)
-	endpoint := fmt.Sprintf("%s/api/v3/aggTrades", endpointPrefix)
+	endpoint := fmt.Sprintf("%s/api/v3/aggTrades", bc.spotEndpoint)
 	res, err := bc.sendRequest(
 		http.MethodGet,
 		endpoint,
@@ -42,6 +42,13 @@ func TestBinanceClient(t *testing.T) {
 	toTime := time.Now()
 	_, err = binanceClient.GetWithdrawalHistory(fromTime, toTime)
 	assert.NoError(t, err, "binance client get withdraw history error: %s", err)
+
+	accountInfo, err := binanceClient.GetAccountInfo()
+	assert.NoError(t, err, "binance client get account info error: %s", err)
+	assert.NotEmpty(t, accountInfo.Balances)
+
+	_, err = binanceClient.GetMarginAccount()
+	assert.NoError(t, err, "binance client get margin account error: %s", err)
 }
 
 func TestBinanceClientWithLimiter(t *testing.T) {
@@ -31,7 +31,9 @@ func NewCliFlags() []cli.Flag {
 		},
 		cli.StringFlag{
 			Name:   binanceAccountsConfigFileFlag,
-			Usage:  "config file",
+			Usage: "json file of accounts, each with name, api_key, secret_key and optional sub_accounts having an email, " +
+				"api_key and secret_key of the sub-account. Binance does not serve trade history of a sub-account " +
+				"to its master key, so sub-accounts without their own keys are skipped",
 			EnvVar: "BINANCE_ACCOUNT_CONFIG_FILE",
 		},
 	}
@@ -0,0 +1,149 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+const (
+	// futuresTradesLimit is the maximum number of trades returned by futures trade history API.
+	futuresTradesLimit = 1000
+	// FuturesIncomeLimit is the maximum number of records returned by futures income history API.
+	FuturesIncomeLimit = 1000
+	// subAccountsLimit is the maximum number of sub-accounts returned per page.
+	subAccountsLimit = 200
+)
+
+//GetFuturesExchangeInfo return exchange info of USDⓈ-M futures
+func (bc *Client) GetFuturesExchangeInfo() (ExchangeInfo, error) {
+	var (
+		result ExchangeInfo
+	)
+	const weight = 1
+	if err := bc.waitN(weight); err != nil {
+		return result, err
+	}
+
+	endpoint := fmt.Sprintf("%s/fapi/v1/exchangeInfo", bc.futuresEndpoint)
+	res, err := bc.sendRequest(
+		http.MethodGet,
+		endpoint,
+		map[string]string{},
+		false,
+		time.Now(),
+	)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(res, &result)
+	return result, err
+}
+
+//GetFuturesTradeHistory return USDⓈ-M futures trades of symbol from fromID
+func (bc *Client) GetFuturesTradeHistory(symbol string, fromID uint64) ([]FuturesTradeHistory, error) {
+	var (
+		result []FuturesTradeHistory
+	)
+	const weight = 5
+	//Wait before creating the request to avoid timestamp request outside the recWindow
+	if err := bc.waitN(weight); err != nil {
+		return result, err
+	}
+
+	endpoint := fmt.Sprintf("%s/fapi/v1/userTrades", bc.futuresEndpoint)
+	res, err := bc.sendRequest(
+		http.MethodGet,
+		endpoint,
+		map[string]string{
+			"symbol": symbol,
+			"fromId": strconv.FormatUint(fromID, 10),
+			"limit":  strconv.Itoa(futuresTradesLimit),
+		},
+		true,
+		time.Now(),
+	)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(res, &result)
+	return result, err
+}
+
+//GetFuturesIncomeHistory return USDⓈ-M futures income of incomeType in time range,
+//at most FuturesIncomeLimit records are returned, oldest first
+func (bc *Client) GetFuturesIncomeHistory(incomeType string, fromTime, toTime time.Time) ([]FuturesIncome, error) {
+	var (
+		result []FuturesIncome
+	)
+	const weight = 30
+	//Wait before creating the request to avoid timestamp request outside the recWindow
+	if err := bc.waitN(weight); err != nil {
+		return result, err
+	}
+
+	endpoint := fmt.Sprintf("%s/fapi/v1/income", bc.futuresEndpoint)
+	params := map[string]string{
+		"incomeType": incomeType,
+		"limit":      strconv.Itoa(FuturesIncomeLimit),
+	}
+	if !fromTime.IsZero() {
+		params["startTime"] = strconv.FormatUint(timeutil.TimeToTimestampMs(fromTime), 10)
+	}
+	if !toTime.IsZero() {
+		params["endTime"] = strconv.FormatUint(timeutil.TimeToTimestampMs(toTime), 10)
+	}
+
+	res, err := bc.sendRequest(
+		http.MethodGet,
+		endpoint,
+		params,
+		true,
+		time.Now(),
+	)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(res, &result)
+	return result, err
+}
+
+//GetSubAccounts return all sub-accounts of the master account owning the API key
+func (bc *Client) GetSubAccounts() ([]SubAccount, error) {
+	var (
+		result []SubAccount
+	)
+	const weight = 1
+	endpoint := fmt.Sprintf("%s/sapi/v1/sub-account/list", bc.spotEndpoint)
+	for page := 1; ; page++ {
+		var subAccounts SubAccountList
+		//Wait before creating the request to avoid timestamp request outside the recWindow
+		if err := bc.waitN(weight); err != nil {
+			return result, err
+		}
+		res, err := bc.sendRequest(
+			http.MethodGet,
+			endpoint,
+			map[string]string{
+				"page":  strconv.Itoa(page),
+				"limit": strconv.Itoa(subAccountsLimit),
+			},
+			true,
+			time.Now(),
+		)
+		if err != nil {
+			return result, err
+		}
+		if err = json.Unmarshal(res, &subAccounts); err != nil {
+			return result, err
+		}
+		result = append(result, subAccounts.SubAccounts...)
+		if len(subAccounts.SubAccounts) < subAccountsLimit {
+			return result, nil
+		}
+	}
+}
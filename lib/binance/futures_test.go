@@ -0,0 +1,120 @@
+package binance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewBinance("api-key", "secret-key", zap.NewNop().Sugar(),
+		WithEndpoints(server.URL, server.URL),
+		WithRateLimiter(NewRateLimiter(1000)),
+	)
+	require.NoError(t, err)
+	return client
+}
+
+func assertSigned(t *testing.T, r *http.Request) {
+	t.Helper()
+	assert.Equal(t, "api-key", r.Header.Get("X-MBX-APIKEY"))
+	assert.NotEmpty(t, r.URL.Query().Get("timestamp"))
+	assert.NotEmpty(t, r.URL.Query().Get("signature"))
+}
+
+func TestGetFuturesTradeHistory(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/fapi/v1/userTrades", r.URL.Path)
+		assertSigned(t, r)
+		assert.Equal(t, "BNBUSDT", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "698759", r.URL.Query().Get("fromId"))
+		_, _ = w.Write([]byte(`[{"buyer":false,"commission":"-0.07819010","commissionAsset":"USDT","id":698759,
+			"maker":false,"orderId":25851813,"price":"7.81900","qty":"1.00","quoteQty":"7.81900",
+			"realizedPnl":"-0.91539999","side":"SELL","positionSide":"SHORT","symbol":"BNBUSDT","time":1569514978020}]`))
+	})
+
+	trades, err := client.GetFuturesTradeHistory("BNBUSDT", 698759)
+	require.NoError(t, err)
+	assert.Equal(t, []FuturesTradeHistory{{
+		Symbol:          "BNBUSDT",
+		ID:              698759,
+		OrderID:         25851813,
+		Side:            "SELL",
+		PositionSide:    "SHORT",
+		Price:           "7.81900",
+		Quantity:        "1.00",
+		QuoteQuantity:   "7.81900",
+		RealizedPnl:     "-0.91539999",
+		Commission:      "-0.07819010",
+		CommissionAsset: "USDT",
+		Time:            1569514978020,
+	}}, trades)
+}
+
+func TestGetFuturesIncomeHistory(t *testing.T) {
+	var (
+		from = timeutil.TimestampMsToTime(1569514000000)
+		to   = from.Add(time.Hour)
+	)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/fapi/v1/income", r.URL.Path)
+		assertSigned(t, r)
+		assert.Equal(t, FuturesIncomeFundingFee, r.URL.Query().Get("incomeType"))
+		assert.Equal(t, "1569514000000", r.URL.Query().Get("startTime"))
+		assert.Equal(t, "1569517600000", r.URL.Query().Get("endTime"))
+		_, _ = w.Write([]byte(`[{"symbol":"BNBUSDT","incomeType":"FUNDING_FEE","income":"-0.01000000",
+			"asset":"USDT","info":"","time":1569516000000,"tranId":9689322392,"tradeId":""}]`))
+	})
+
+	incomes, err := client.GetFuturesIncomeHistory(FuturesIncomeFundingFee, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, []FuturesIncome{{
+		Symbol:     "BNBUSDT",
+		IncomeType: FuturesIncomeFundingFee,
+		Income:     "-0.01000000",
+		Asset:      "USDT",
+		Time:       1569516000000,
+		TranID:     9689322392,
+	}}, incomes)
+}
+
+func TestGetSubAccounts(t *testing.T) {
+	var pages []int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/sapi/v1/sub-account/list", r.URL.Path)
+		assertSigned(t, r)
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		require.NoError(t, err)
+		pages = append(pages, page)
+
+		// the first page is full, the second one is not
+		count := subAccountsLimit
+		if page > 1 {
+			count = 1
+		}
+		var list SubAccountList
+		for i := 0; i < count; i++ {
+			list.SubAccounts = append(list.SubAccounts, SubAccount{Email: fmt.Sprintf("sub%d-%d@example.com", page, i)})
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(list))
+	})
+
+	subAccounts, err := client.GetSubAccounts()
+	require.NoError(t, err)
+	assert.Len(t, subAccounts, subAccountsLimit+1)
+	assert.Equal(t, "sub2-0@example.com", subAccounts[subAccountsLimit].Email)
+	assert.Equal(t, []int{1, 2}, pages)
+}
@@ -12,7 +12,7 @@ const (
 	defaultHardLimit = 1200 / 60
 	defaultWafLimit  = 4000 / 60 / 5
 	// defaultMaxWeight should be greater than max weight required by a request in Binance client.
-	defaultMaxWeight = 5
+	defaultMaxWeight = 30
 )
 
 // RateLimiter implements Limiter interface.
@@ -124,8 +124,84 @@ type SymbolFilterType struct {
 
 //AccountInfo is the object to store account info from binance
 type AccountInfo struct {
-	CanTrade    bool   `json:"canTrade"`
-	CanDeposit  bool   `json:"canDeposit"`
-	CanWithdraw bool   `json:"canWithdraw"`
-	UpdateTime  uint64 `json:"updateTime"`
+	CanTrade    bool      `json:"canTrade"`
+	CanDeposit  bool      `json:"canDeposit"`
+	CanWithdraw bool      `json:"canWithdraw"`
+	UpdateTime  uint64    `json:"updateTime"`
+	Balances    []Balance `json:"balances"`
+}
+
+//Balance is the spot balance of an asset in binance account
+type Balance struct {
+	Asset  string `json:"asset"`
+	Free   string `json:"free"`
+	Locked string `json:"locked"`
+}
+
+//MarginAccount is the cross margin account info from binance
+type MarginAccount struct {
+	BorrowEnabled bool          `json:"borrowEnabled"`
+	TradeEnabled  bool          `json:"tradeEnabled"`
+	MarginLevel   string        `json:"marginLevel"`
+	UserAssets    []MarginAsset `json:"userAssets"`
+}
+
+//MarginAsset is the margin balance of an asset in binance account
+type MarginAsset struct {
+	Asset    string `json:"asset"`
+	Free     string `json:"free"`
+	Locked   string `json:"locked"`
+	Borrowed string `json:"borrowed"`
+	Interest string `json:"interest"`
+	NetAsset string `json:"netAsset"`
+}
+
+// USDⓈ-M futures income types of GetFuturesIncomeHistory.
+const (
+	FuturesIncomeTransfer   = "TRANSFER"
+	FuturesIncomeFundingFee = "FUNDING_FEE"
+)
+
+//FuturesTradeHistory is a trade of USDⓈ-M futures account
+type FuturesTradeHistory struct {
+	Symbol          string `json:"symbol"`
+	ID              uint64 `json:"id"`
+	OrderID         int64  `json:"orderId"`
+	Side            string `json:"side"`
+	PositionSide    string `json:"positionSide"`
+	Price           string `json:"price"`
+	Quantity        string `json:"qty"`
+	QuoteQuantity   string `json:"quoteQty"`
+	RealizedPnl     string `json:"realizedPnl"`
+	MarginAsset     string `json:"marginAsset"`
+	Commission      string `json:"commission"`
+	CommissionAsset string `json:"commissionAsset"`
+	Time            uint64 `json:"time"`
+	IsBuyer         bool   `json:"buyer"`
+	IsMaker         bool   `json:"maker"`
+}
+
+//FuturesIncome is an income record of USDⓈ-M futures account, e.g. a funding fee
+//or a transfer between spot and futures wallets. Income is negative for outflows.
+type FuturesIncome struct {
+	Symbol     string `json:"symbol"`
+	IncomeType string `json:"incomeType"`
+	Income     string `json:"income"`
+	Asset      string `json:"asset"`
+	Info       string `json:"info"`
+	Time       uint64 `json:"time"`
+	TranID     int64  `json:"tranId"`
+	TradeID    string `json:"tradeId"`
+}
+
+//SubAccount is a sub-account of a binance master account
+type SubAccount struct {
+	Email      string `json:"email"`
+	IsFreeze   bool   `json:"isFreeze"`
+	CreateTime uint64 `json:"createTime"`
+}
+
+//SubAccountList is the response of sub-account list API
+type SubAccountList struct {
+	SubAccounts []SubAccount `json:"subAccounts"`
 }
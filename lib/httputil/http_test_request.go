@@ -16,6 +16,7 @@ type HTTPTestCase struct {
 	Endpoint string
 	Method   string
 	Params   map[string]string
+	Headers  map[string]string
 	Body     []byte
 	Assert   AssertFn
 }
@@ -29,6 +30,9 @@ func RunHTTPTestCase(t *testing.T, tc HTTPTestCase, handler http.Handler) {
 	}
 
 	req.Header.Add("Content-Type", "application/json")
+	for k, v := range tc.Headers {
+		req.Header.Set(k, v)
+	}
 	q := req.URL.Query()
 	for k, v := range tc.Params {
 		q.Add(k, v)
@@ -53,4 +53,19 @@ const (
 	AccountingCEXDepositPort = 8016
 	// Accounting0xTradesPort ...
 	Accounting0xTradesPort = 8017
+
+	// AccountingReserveCompetitivenessPort is the port number of accounting-reserve-competitiveness-api service
+	AccountingReserveCompetitivenessPort = 8018
+
+	// AccountingPnLPort is the port number of accounting-pnl-api service
+	AccountingPnLPort = 8019
+
+	// AccountingTransferMatcherPort is the port number of accounting-transfer-matcher-api service
+	AccountingTransferMatcherPort = 8020
+
+	// AccountingLedgerPort is the port number of accounting-ledger-api service
+	AccountingLedgerPort = 8021
+
+	// AccountingTokenParamsPort is the port number of accounting-token-params-api service
+	AccountingTokenParamsPort = 8022
 )
@@ -0,0 +1,64 @@
+package httputil
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// ReserveScopeHeader is the header set by gateway to the comma separated addresses of reserves the API key
+// is bound to. Gateway removes the header from client requests, so it is only trusted behind gateway.
+const ReserveScopeHeader = "X-Reserve-Scope"
+
+// ErrReserveScopeNotSupported is returned for requests of reserve bound keys to endpoints could not be
+// filtered by reserve.
+var ErrReserveScopeNotSupported = errors.New("endpoint is not available for reserve bound api keys")
+
+// ReserveScope returns the reserves the request is restricted to, nil if it is not restricted.
+func ReserveScope(r *http.Request) []ethereum.Address {
+	var scope []ethereum.Address
+	for _, addr := range strings.Split(r.Header.Get(ReserveScopeHeader), ",") {
+		addr = strings.TrimSpace(addr)
+		if len(addr) == 0 {
+			continue
+		}
+		scope = append(scope, ethereum.HexToAddress(addr))
+	}
+	return scope
+}
+
+// ScopedReserves returns the reserves a request should be filtered by. The requested reserves are returned
+// as is for unrestricted requests. For restricted requests, all reserves in scope are returned if none
+// is requested and an error is returned if any requested reserve is out of scope.
+func ScopedReserves(r *http.Request, requested []ethereum.Address) ([]ethereum.Address, error) {
+	scope := ReserveScope(r)
+	if len(scope) == 0 {
+		return requested, nil
+	}
+	if len(requested) == 0 {
+		return scope, nil
+	}
+	allowed := make(map[ethereum.Address]struct{}, len(scope))
+	for _, addr := range scope {
+		allowed[addr] = struct{}{}
+	}
+	for _, addr := range requested {
+		if _, ok := allowed[addr]; !ok {
+			return nil, fmt.Errorf("reserve %s is not allowed for api key", addr.Hex())
+		}
+	}
+	return requested, nil
+}
+
+// RejectReserveScope is the middleware rejecting restricted requests, it is used for endpoints that
+// could not be filtered by reserve.
+func RejectReserveScope(c *gin.Context) {
+	if len(ReserveScope(c.Request)) != 0 {
+		ResponseFailure(c, http.StatusForbidden, ErrReserveScopeNotSupported)
+		c.Abort()
+	}
+}
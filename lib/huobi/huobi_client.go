@@ -195,6 +195,34 @@ func (hc *Client) GetAccounts() ([]Account, error) {
 	return result.Data, err
 }
 
+//GetAccountBalance return balances of all currencies in an account
+//details at https://huobiapi.github.io/docs/spot/v1/en/#get-account-balance-of-a-specific-account
+func (hc *Client) GetAccountBalance(accountID int) (AccountBalance, error) {
+	var (
+		result AccountBalanceResponse
+	)
+	endpoint := fmt.Sprintf("%s/v1/account/accounts/%d/balance", huobiEndpoint, accountID)
+	res, err := hc.sendRequest(
+		http.MethodGet,
+		endpoint,
+		map[string]string{},
+		true,
+	)
+	if err != nil {
+		return result.Data, err
+	}
+	if err = json.Unmarshal(res, &result); err != nil {
+		return result.Data, err
+	}
+	if result.Status != StatusOK.String() {
+		return result.Data, fmt.Errorf("received unexpect status: err=%s code=%s msg=%s",
+			result.Status,
+			result.ErrorCode,
+			result.ErrorMessage)
+	}
+	return result.Data, nil
+}
+
 //GetTradeHistory return trade history of an account
 //extras  params included fromID for further querrying.
 //details at https://github.com/huobiapi/API_Docs_en/wiki/REST_Reference#get-v1orderorders--get-order-list
@@ -233,30 +261,50 @@ func (hc *Client) GetTradeHistory(symbol string, startDate, endDate time.Time, e
 	return result, err
 }
 
-//GetWithdrawHistory return withdraw history of an account
-func (hc *Client) GetWithdrawHistory(currency string, fromID uint64) (WithdrawHistoryList, error) {
-	var (
-		result WithdrawHistoryList
-	)
+// getDepositWithdraw queries deposit or withdraw history of a currency from the ID, the result is
+// unmarshalled to v.
+func (hc *Client) getDepositWithdraw(transferType, currency string, fromID uint64, v interface{}) error {
 	endpoint := fmt.Sprintf("%s/v1/query/deposit-withdraw", huobiEndpoint)
 	res, err := hc.sendRequest(
 		http.MethodGet,
 		endpoint,
 		map[string]string{
-			"type":     "withdraw",
+			"type":     transferType,
 			"size":     "20",
 			"from":     strconv.FormatUint(fromID, 10),
 			"currency": strings.ToLower(currency),
 		},
 		true,
 	)
-
 	if err != nil {
+		return err
+	}
+	return json.Unmarshal(res, v)
+}
+
+//GetWithdrawHistory return withdraw history of an account
+func (hc *Client) GetWithdrawHistory(currency string, fromID uint64) (WithdrawHistoryList, error) {
+	var (
+		result WithdrawHistoryList
+	)
+	if err := hc.getDepositWithdraw("withdraw", currency, fromID, &result); err != nil {
 		return result, err
 	}
+	if result.Status != StatusOK.String() {
+		return result, fmt.Errorf("received unexpect status: err=%s code=%s msg=%s",
+			result.Status,
+			result.ErrorCode,
+			result.ErrorMessage)
+	}
+	return result, nil
+}
 
-	err = json.Unmarshal(res, &result)
-	if err != nil {
+//GetDepositHistory return deposit history of an account
+func (hc *Client) GetDepositHistory(currency string, fromID uint64) (DepositHistoryList, error) {
+	var (
+		result DepositHistoryList
+	)
+	if err := hc.getDepositWithdraw("deposit", currency, fromID, &result); err != nil {
 		return result, err
 	}
 	if result.Status != StatusOK.String() {
@@ -265,7 +313,7 @@ func (hc *Client) GetWithdrawHistory(currency string, fromID uint64) (WithdrawHi
 			result.ErrorCode,
 			result.ErrorMessage)
 	}
-	return result, err
+	return result, nil
 }
 
 //GetSymbolsPair return list of pairs for Huobi's data
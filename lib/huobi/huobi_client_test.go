@@ -30,8 +30,13 @@ func TestHuobiClient(t *testing.T) {
 	huobiClient, err := NewClient(huobiAPIKey, huobiSecretKey, sugar)
 	assert.NoError(t, err)
 
-	_, err = huobiClient.GetAccounts()
+	accounts, err := huobiClient.GetAccounts()
 	assert.NoError(t, err, fmt.Sprintf("get account fee error: %s", err))
+	for _, account := range accounts {
+		balance, err := huobiClient.GetAccountBalance(account.ID)
+		assert.NoError(t, err)
+		sugar.Infow("account balance", "type", account.Type, "value", balance)
+	}
 
 	//fixed timestamp for test
 	startDate := time.Date(2018, time.January, 1, 0, 0, 0, 0, time.UTC)
@@ -43,6 +48,10 @@ func TestHuobiClient(t *testing.T) {
 	withdrawHistory, err := huobiClient.GetWithdrawHistory("ETH", 0)
 	assert.NoError(t, err)
 	sugar.Infow("withdraw history", "value", withdrawHistory)
+
+	depositHistory, err := huobiClient.GetDepositHistory("ETH", 0)
+	assert.NoError(t, err)
+	sugar.Infow("deposit history", "value", depositHistory)
 }
 
 func TestHuobiClientWithLimiter(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 type Interface interface {
 	GetTradeHistory(symbol string, startDate, endDate time.Time, extras ...ExtrasTradeHistoryParams) (TradeHistoryList, error)
 	GetWithdrawHistory(currency string, fromID uint64) (WithdrawHistoryList, error)
+	GetDepositHistory(currency string, fromID uint64) (DepositHistoryList, error)
 	GetSymbolsPair() ([]Symbol, error)
 	GetCurrencies() ([]string, error)
 }
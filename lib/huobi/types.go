@@ -21,6 +21,27 @@ type AccountResponse struct {
 	CommonResponse
 }
 
+//AccountBalance is the balance list of a huobi account
+type AccountBalance struct {
+	ID    int                  `json:"id"`
+	Type  string               `json:"type"`
+	State string               `json:"state"`
+	List  []AccountBalanceItem `json:"list"`
+}
+
+//AccountBalanceItem is the balance of a currency in an account, type is trade or frozen
+type AccountBalanceItem struct {
+	Currency string `json:"currency"`
+	Type     string `json:"type"`
+	Balance  string `json:"balance"`
+}
+
+//AccountBalanceResponse response for account balance api
+type AccountBalanceResponse struct {
+	Data AccountBalance `json:"data"`
+	CommonResponse
+}
+
 //TradeHistory is a history of a trade in huobi
 type TradeHistory struct {
 	ID              int64  `json:"id,omitempty"`
@@ -69,6 +90,28 @@ type WithdrawHistoryList struct {
 	CommonResponse
 }
 
+//DepositHistory is history of a deposit
+type DepositHistory struct {
+	ID         uint64  `json:"id"`
+	Type       string  `json:"type"`
+	Currency   string  `json:"currency"`
+	Chain      string  `json:"chain"`
+	TxHash     string  `json:"tx-hash"`
+	Amount     float64 `json:"amount"`
+	Address    string  `json:"address"`
+	AddressTag string  `json:"address-tag"`
+	Fee        float64 `json:"fee"`
+	State      string  `json:"state"`
+	CreatedAt  uint64  `json:"created-at"`
+	UpdatedAt  uint64  `json:"updated-at"`
+}
+
+//DepositHistoryList is a list of deposit history
+type DepositHistoryList struct {
+	Data []DepositHistory `json:"data"`
+	CommonResponse
+}
+
 //SymbolsReply hold huobi's reply data and status
 type SymbolsReply struct {
 	Status string   `json:"status"`
@@ -0,0 +1,27 @@
+package openapi
+
+import (
+	"github.com/urfave/cli"
+)
+
+const validationFlag = "openapi-validation"
+
+// NewCliFlags returns cli flags to configure the OpenAPI router.
+func NewCliFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.BoolFlag{
+			Name:   validationFlag,
+			Usage:  "reject requests not matching the OpenAPI document before handling them",
+			EnvVar: "OPENAPI_VALIDATION",
+		},
+	}
+}
+
+// NewOptionsFromContext returns the router options configured by cli flags.
+func NewOptionsFromContext(c *cli.Context) []Option {
+	var options []Option
+	if c.Bool(validationFlag) {
+		options = append(options, WithValidation())
+	}
+	return options
+}
@@ -0,0 +1,132 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var invalidComponentChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// copy returns a deep copy of the document.
+func (d Document) copy() (Document, error) {
+	var result Document
+	data, err := json.Marshal(d)
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(data, &result)
+	return result, err
+}
+
+// Merge adds the operations and component schemas of other document. Operations already in the document
+// are kept. Component schemas having the same name with different definitions are renamed with the prefix,
+// a number is appended to the renamed name if it is still taken.
+func (d *Document) Merge(prefix string, other Document) error {
+	other, err := other.copy()
+	if err != nil {
+		return err
+	}
+	if d.Paths == nil {
+		d.Paths = make(map[string]PathItem)
+	}
+	if d.Components.Schemas == nil {
+		d.Components.Schemas = make(map[string]*Schema)
+	}
+
+	prefix = strings.Trim(invalidComponentChars.ReplaceAllString(prefix, "_"), "_")
+	var (
+		renames = make(map[string]string)
+		// taken are the names of component schemas after merged, except the conflicted ones of other
+		taken = make(map[string]bool)
+	)
+	for name := range d.Components.Schemas {
+		taken[name] = true
+	}
+	var conflicted []string
+	for name, s := range other.Components.Schemas {
+		if existing, ok := d.Components.Schemas[name]; ok && !reflect.DeepEqual(existing, s) {
+			conflicted = append(conflicted, name)
+			continue
+		}
+		taken[name] = true
+	}
+	// sorted to rename the same way regardless of map iteration order
+	sort.Strings(conflicted)
+	for _, name := range conflicted {
+		renamed := prefix + "." + name
+		for i := 2; taken[renamed]; i++ {
+			renamed = prefix + "." + name + "_" + strconv.Itoa(i)
+		}
+		taken[renamed] = true
+		renames[refPrefix+name] = refPrefix + renamed
+	}
+	other.walk(func(s *Schema) {
+		if renamed, ok := renames[s.Ref]; ok {
+			s.Ref = renamed
+		}
+	})
+	for name, s := range other.Components.Schemas {
+		if renamed, ok := renames[refPrefix+name]; ok {
+			name = renamed[len(refPrefix):]
+		}
+		if _, ok := d.Components.Schemas[name]; !ok {
+			d.Components.Schemas[name] = s
+		}
+	}
+
+	for p, item := range other.Paths {
+		if _, ok := d.Paths[p]; !ok {
+			d.Paths[p] = make(PathItem)
+		}
+		for method, op := range item {
+			if _, ok := d.Paths[p][method]; !ok {
+				d.Paths[p][method] = op
+			}
+		}
+	}
+	return nil
+}
+
+// Filter removes the operations not kept by the function and the component schemas no longer referenced.
+// The method passed to the function is in upper case.
+func (d *Document) Filter(keep func(method, path string) bool) {
+	for p, item := range d.Paths {
+		for method := range item {
+			if !keep(strings.ToUpper(method), p) {
+				delete(item, method)
+			}
+		}
+		if len(item) == 0 {
+			delete(d.Paths, p)
+		}
+	}
+
+	// keeps the components referenced by operations, then the ones referenced by kept components
+	schemas := d.Components.Schemas
+	d.Components.Schemas = make(map[string]*Schema)
+	for {
+		var found []string
+		d.walk(func(s *Schema) {
+			if len(s.Ref) == 0 {
+				return
+			}
+			name := s.Ref[len(refPrefix):]
+			if _, ok := d.Components.Schemas[name]; ok {
+				return
+			}
+			if _, ok := schemas[name]; ok {
+				found = append(found, name)
+			}
+		})
+		if len(found) == 0 {
+			return
+		}
+		for _, name := range found {
+			d.Components.Schemas[name] = schemas[name]
+		}
+	}
+}
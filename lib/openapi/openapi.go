@@ -0,0 +1,146 @@
+// Package openapi builds OpenAPI 3 documents of gin servers from their route registrations and
+// the types requests are bound to and responses are marshaled from.
+//
+// Schemas are generated by reflection following the encoding/json rules. As all APIs of this
+// repository format timestamps in unix millis, time.Time is described as an integer.
+package openapi
+
+const (
+	// Version is the OpenAPI version of generated documents.
+	Version = "3.0.3"
+	// DocumentPath is the path the document is served at.
+	DocumentPath = "/openapi.json"
+
+	mimeJSON = "application/json"
+
+	typeObject  = "object"
+	typeArray   = "array"
+	typeString  = "string"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+
+	inPath  = "path"
+	inQuery = "query"
+)
+
+// Document is an OpenAPI document, only the parts used by this repository are supported.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info is the metadata of API.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem is the operations of a path, keyed by lower case HTTP methods.
+type PathItem map[string]*Operation
+
+// Operation describes an API endpoint.
+type Operation struct {
+	Summary     string              `json:"summary,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a query or path parameter of an operation.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody is the request body of an operation.
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is a response of an operation.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType is the schema of a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the schemas of named types referenced by operations.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema is a JSON schema object of OpenAPI.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+const refPrefix = "#/components/schemas/"
+
+func refTo(name string) *Schema {
+	return &Schema{Ref: refPrefix + name}
+}
+
+// resolve returns the component schema if s is a reference.
+func (d *Document) resolve(s *Schema) *Schema {
+	for s != nil && len(s.Ref) != 0 {
+		s = d.Components.Schemas[s.Ref[len(refPrefix):]]
+	}
+	return s
+}
+
+// walk calls fn on every schema of the document, including the nested ones.
+func (d *Document) walk(fn func(s *Schema)) {
+	var visit func(s *Schema)
+	visit = func(s *Schema) {
+		if s == nil {
+			return
+		}
+		fn(s)
+		visit(s.Items)
+		visit(s.AdditionalProperties)
+		for _, p := range s.Properties {
+			visit(p)
+		}
+	}
+	for _, item := range d.Paths {
+		for _, op := range item {
+			for _, p := range op.Parameters {
+				visit(p.Schema)
+			}
+			if op.RequestBody != nil {
+				for _, mt := range op.RequestBody.Content {
+					visit(mt.Schema)
+				}
+			}
+			for _, resp := range op.Responses {
+				for _, mt := range resp.Content {
+					visit(mt.Schema)
+				}
+			}
+		}
+	}
+	for _, s := range d.Components.Schemas {
+		visit(s)
+	}
+}
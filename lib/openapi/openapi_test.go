@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type timeRange struct {
+	From uint64 `form:"from"`
+	To   uint64 `form:"to"`
+}
+
+type reportQuery struct {
+	timeRange
+	Reserves []string `form:"reserve" binding:"dive,isAddress"`
+	Limit    int      `form:"limit" binding:"omitempty,min=0,max=100"`
+	Format   string   `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+type txParam struct {
+	TxHash string `uri:"tx_hash" binding:"required"`
+}
+
+type trade struct {
+	Timestamp time.Time        `json:"timestamp"`
+	Reserve   ethereum.Address `json:"reserve"`
+	Amount    *big.Int         `json:"amount"`
+	Symbol    string           `json:"symbol,omitempty"`
+	Splits    []trade          `json:"splits"`
+}
+
+type application struct {
+	Name      string   `json:"name" binding:"required"`
+	Addresses []string `json:"addresses" binding:"required"`
+	Limit     *uint64  `json:"limit"`
+}
+
+func newTestRouter(options ...Option) (*gin.Engine, *Spec) {
+	r := gin.New()
+	spec := NewSpec("test API")
+	api := NewRouter(r, spec, options...)
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) }
+	api.GET("/trades", Endpoint{Summary: "list trades", Query: reportQuery{}, Response: []trade{}}, ok)
+	api.GET("/trades/:tx_hash", Endpoint{URI: txParam{}, Response: trade{}}, ok)
+	api.POST("/applications", Endpoint{Body: application{}, Response: application{}, Status: http.StatusCreated}, ok)
+	api.DELETE("/applications/:id", Endpoint{}, ok)
+	return r, spec
+}
+
+func TestSpec(t *testing.T) {
+	r, spec := newTestRouter()
+	doc, err := spec.Document()
+	require.NoError(t, err)
+
+	list := doc.Paths["/trades"]["get"]
+	require.NotNil(t, list)
+	assert.Equal(t, "list trades", list.Summary)
+	names := make(map[string]Parameter)
+	for _, p := range list.Parameters {
+		names[p.Name] = p
+	}
+	assert.Len(t, names, 5)
+	assert.Equal(t, "integer", names["from"].Schema.Type)
+	assert.Equal(t, "array", names["reserve"].Schema.Type)
+	assert.Equal(t, 100.0, *names["limit"].Schema.Maximum)
+	assert.Equal(t, []interface{}{"json", "csv"}, names["format"].Schema.Enum)
+	assert.Equal(t, refPrefix+"trade", list.Responses["200"].Content[mimeJSON].Schema.Items.Ref)
+
+	tradeSchema := doc.Components.Schemas["trade"]
+	require.NotNil(t, tradeSchema)
+	assert.Len(t, tradeSchema.Properties, 5)
+	assert.Equal(t, "integer", tradeSchema.Properties["timestamp"].Type)
+	assert.Equal(t, addressPattern, tradeSchema.Properties["reserve"].Pattern)
+	assert.Equal(t, "integer", tradeSchema.Properties["amount"].Type)
+	assert.Equal(t, refPrefix+"trade", tradeSchema.Properties["splits"].Items.Ref)
+
+	get := doc.Paths["/trades/{tx_hash}"]["get"]
+	require.NotNil(t, get)
+	require.Len(t, get.Parameters, 1)
+	assert.Equal(t, Parameter{Name: "tx_hash", In: "path", Required: true, Schema: &Schema{Type: "string"}}, get.Parameters[0])
+
+	create := doc.Paths["/applications"]["post"]
+	require.NotNil(t, create)
+	assert.Contains(t, create.Responses, "201")
+	assert.Equal(t, []string{"name", "addresses"}, doc.Components.Schemas["application"].Required)
+	remove := doc.Paths["/applications/{id}"]["delete"]
+	require.NotNil(t, remove)
+	assert.Equal(t, "id", remove.Parameters[0].Name)
+
+	// document is served by the router
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, DocumentPath, nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var served Document
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &served))
+	assert.Equal(t, Version, served.OpenAPI)
+	assert.Len(t, served.Paths, 4)
+}
+
+func TestValidation(t *testing.T) {
+	r, _ := newTestRouter(WithValidation())
+
+	var tests = []struct {
+		msg    string
+		method string
+		target string
+		body   string
+		code   int
+	}{
+		{msg: "valid query", method: http.MethodGet, target: "/trades?from=1&limit=10&format=csv&reserve=0x63825c174ab367968EC60f061753D3bbD36A0D8F", code: http.StatusOK},
+		{msg: "invalid integer", method: http.MethodGet, target: "/trades?from=yesterday", code: http.StatusBadRequest},
+		{msg: "exceeds maximum", method: http.MethodGet, target: "/trades?limit=101", code: http.StatusBadRequest},
+		{msg: "not in enum", method: http.MethodGet, target: "/trades?format=xml", code: http.StatusBadRequest},
+		{msg: "path parameter", method: http.MethodGet, target: "/trades/0x1", code: http.StatusOK},
+		{msg: "valid body", method: http.MethodPost, target: "/applications", body: `{"name":"app","addresses":["0x1"],"limit":1}`, code: http.StatusOK},
+		{msg: "missing required property", method: http.MethodPost, target: "/applications", body: `{"name":"app"}`, code: http.StatusBadRequest},
+		{msg: "invalid property type", method: http.MethodPost, target: "/applications", body: `{"name":"app","addresses":[1]}`, code: http.StatusBadRequest},
+		{msg: "negative unsigned", method: http.MethodPost, target: "/applications", body: `{"name":"app","addresses":[],"limit":-1}`, code: http.StatusBadRequest},
+		{msg: "missing body", method: http.MethodPost, target: "/applications", code: http.StatusBadRequest},
+	}
+	for _, tc := range tests {
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, httptest.NewRequest(tc.method, tc.target, bytes.NewBufferString(tc.body)))
+		assert.Equal(t, tc.code, resp.Code, tc.msg)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	_, spec := newTestRouter()
+	other := NewSpec("other API")
+	type trade struct {
+		ID uint64 `json:"id"`
+	}
+	other.Add(http.MethodGet, "/other-trades", Endpoint{Response: []trade{}})
+	other.Add(http.MethodGet, "/trades", Endpoint{Summary: "conflicted"})
+
+	doc, err := spec.Document()
+	require.NoError(t, err)
+	otherDoc, err := other.Document()
+	require.NoError(t, err)
+	require.NoError(t, doc.Merge("http://127.0.0.1:8000", otherDoc))
+	assert.Equal(t, "list trades", doc.Paths["/trades"]["get"].Summary)
+	// conflicted component is renamed
+	ref := doc.Paths["/other-trades"]["get"].Responses["200"].Content[mimeJSON].Schema.Items.Ref
+	assert.Equal(t, refPrefix+"http_127.0.0.1_8000.trade", ref)
+	assert.Contains(t, doc.Components.Schemas, "http_127.0.0.1_8000.trade")
+	assert.Contains(t, doc.Components.Schemas, "trade")
+
+	// conflicted component is renamed again if the renamed name is taken
+	third := NewSpec("third API")
+	{
+		type trade struct {
+			Hash string `json:"hash"`
+		}
+		third.Add(http.MethodGet, "/third-trades", Endpoint{Response: trade{}})
+	}
+	thirdDoc, err := third.Document()
+	require.NoError(t, err)
+	require.NoError(t, doc.Merge("http://127.0.0.1:8000", thirdDoc))
+	ref = doc.Paths["/third-trades"]["get"].Responses["200"].Content[mimeJSON].Schema.Ref
+	assert.Equal(t, refPrefix+"http_127.0.0.1_8000.trade_2", ref)
+	assert.Contains(t, doc.Components.Schemas["http_127.0.0.1_8000.trade"].Properties, "id")
+	assert.Contains(t, doc.Components.Schemas["http_127.0.0.1_8000.trade_2"].Properties, "hash")
+	doc.Filter(func(method, path string) bool {
+		return path != "/third-trades"
+	})
+
+	doc.Filter(func(method, path string) bool {
+		return method == http.MethodGet && path == "/other-trades"
+	})
+	assert.Len(t, doc.Paths, 1)
+	assert.Len(t, doc.Components.Schemas, 2)
+	assert.Contains(t, doc.Components.Schemas, errorSchema)
+}
@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"encoding"
+	"fmt"
+	"math/big"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	addressPattern = "^0x[0-9a-fA-F]{40}$"
+	hashPattern    = "^0x[0-9a-fA-F]{64}$"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	bigIntType        = reflect.TypeOf(big.Int{})
+	addressType       = reflect.TypeOf(ethereum.Address{})
+	hashType          = reflect.TypeOf(ethereum.Hash{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// generator generates schemas of Go types, named struct types are added to components and referenced.
+type generator struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newGenerator(schemas map[string]*Schema) *generator {
+	return &generator{
+		schemas: schemas,
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+func (g *generator) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return &Schema{Type: typeInteger, Format: "int64", Description: "unix timestamp in milliseconds"}
+	case bigIntType:
+		return &Schema{Type: typeInteger}
+	case addressType:
+		return &Schema{Type: typeString, Pattern: addressPattern}
+	case hashType:
+		return &Schema{Type: typeString, Pattern: hashPattern}
+	}
+	if t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return &Schema{Type: typeString}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: typeBoolean}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: typeInteger, Format: "int32"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: typeInteger, Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: typeInteger, Minimum: float(0)}
+	case reflect.Float32:
+		return &Schema{Type: typeNumber, Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: typeNumber, Format: "double"}
+	case reflect.String:
+		return &Schema{Type: typeString}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: typeString, Format: "byte"}
+		}
+		return &Schema{Type: typeArray, Items: g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: typeObject, AdditionalProperties: g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if len(t.Name()) == 0 {
+			return g.objectSchema(t)
+		}
+		return g.structRef(t)
+	default:
+		// interfaces could be anything
+		return &Schema{}
+	}
+}
+
+// structRef adds the schema of named struct type to components and returns the reference.
+func (g *generator) structRef(t reflect.Type) *Schema {
+	if name, ok := g.names[t]; ok {
+		return refTo(name)
+	}
+	name := t.Name()
+	if _, ok := g.schemas[name]; ok {
+		name = path.Base(t.PkgPath()) + "." + t.Name()
+	}
+	for i := 2; ; i++ {
+		if _, ok := g.schemas[name]; !ok {
+			break
+		}
+		name = fmt.Sprintf("%s.%s%d", path.Base(t.PkgPath()), t.Name(), i)
+	}
+	// registered before generating fields for recursive types
+	s := &Schema{}
+	g.names[t] = name
+	g.schemas[name] = s
+	*s = *g.objectSchema(t)
+	return refTo(name)
+}
+
+func (g *generator) objectSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: typeObject, Properties: make(map[string]*Schema)}
+	g.addFields(s, t)
+	return s
+}
+
+// addFields adds the fields of struct type to the object schema following encoding/json rules,
+// fields of embedded structs are promoted.
+func (g *generator) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, opts := parseTag(f.Tag.Get("json"))
+		if name == "-" && len(opts) == 0 {
+			continue
+		}
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && len(name) == 0 && ft.Kind() == reflect.Struct && ft != timeType && ft != bigIntType {
+			g.addFields(s, ft)
+			continue
+		}
+		if len(f.PkgPath) != 0 {
+			// unexported
+			continue
+		}
+		if len(name) == 0 {
+			name = f.Name
+		}
+		prop := g.schemaOf(f.Type)
+		if hasOption(opts, typeString) {
+			prop = &Schema{Type: typeString}
+		}
+		if applyBinding(prop, f.Tag.Get("binding")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+func parseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+func hasOption(opts []string, option string) bool {
+	for _, opt := range opts {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+// applyBinding applies the rules of gin binding tag to the schema, it returns true if the value is required.
+func applyBinding(s *Schema, tag string) bool {
+	var required bool
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			name, arg = rule[:i], rule[i+1:]
+		}
+		numeric := s.Type == typeInteger || s.Type == typeNumber
+		switch name {
+		case "required":
+			required = true
+		case "min", "gte":
+			if v, err := strconv.ParseFloat(arg, 64); err == nil && numeric {
+				s.Minimum = &v
+			}
+		case "max", "lte":
+			if v, err := strconv.ParseFloat(arg, 64); err == nil && numeric {
+				s.Maximum = &v
+			}
+		case "oneof":
+			for _, v := range strings.Fields(arg) {
+				s.Enum = append(s.Enum, enumValue(s.Type, v))
+			}
+		case "isAddress":
+			if s.Type == typeString {
+				s.Pattern = addressPattern
+			}
+		}
+	}
+	return required
+}
+
+func enumValue(typ, v string) interface{} {
+	switch typ {
+	case typeInteger:
+		if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return i
+		}
+	case typeNumber:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return v
+}
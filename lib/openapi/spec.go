@@ -0,0 +1,251 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultVersion = "1.0.0"
+	errorSchema    = "Error"
+)
+
+// Endpoint describes the types a route binds requests to and marshals responses from. Only the types
+// of the values are used.
+type Endpoint struct {
+	Summary string
+	// Query is the struct the query string is bound to, parameters are generated from form tags.
+	Query interface{}
+	// URI is the struct the path parameters are bound to with uri tags. Path parameters without
+	// uri tags are described as strings.
+	URI interface{}
+	// Body is the type the JSON request body is bound to.
+	Body interface{}
+	// Response is the type of JSON response on success, the response has no content if it is nil.
+	Response interface{}
+	// Status is the response status on success, default to 200.
+	Status int
+}
+
+// Spec builds the OpenAPI document of a server.
+type Spec struct {
+	mu  sync.RWMutex
+	doc Document
+	gen *generator
+}
+
+// NewSpec creates an empty spec.
+func NewSpec(title string) *Spec {
+	schemas := map[string]*Schema{
+		errorSchema: {
+			Type:       typeObject,
+			Properties: map[string]*Schema{"error": {Type: typeString}},
+			Required:   []string{"error"},
+		},
+	}
+	return &Spec{
+		doc: Document{
+			OpenAPI:    Version,
+			Info:       Info{Title: title, Version: defaultVersion},
+			Paths:      make(map[string]PathItem),
+			Components: Components{Schemas: schemas},
+		},
+		gen: newGenerator(schemas),
+	}
+}
+
+// Path converts gin path to OpenAPI path, for example: /trade-logs/:tx_hash to /trade-logs/{tx_hash}.
+func Path(ginPath string) string {
+	segments := strings.Split(ginPath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func pathParams(ginPath string) []string {
+	var params []string
+	for _, segment := range strings.Split(ginPath, "/") {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+		}
+	}
+	return params
+}
+
+// Add describes the route of given method and gin path.
+func (s *Spec) Add(method, ginPath string, e Endpoint) *Operation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op := &Operation{
+		Summary: e.Summary,
+		Responses: map[string]Response{
+			"default": {
+				Description: "error",
+				Content:     map[string]MediaType{mimeJSON: {Schema: refTo(errorSchema)}},
+			},
+		},
+	}
+
+	if e.URI != nil {
+		op.Parameters = append(op.Parameters, s.gen.parameters(reflect.TypeOf(e.URI), inPath, "uri")...)
+	}
+	for _, name := range pathParams(ginPath) {
+		if !hasParameter(op.Parameters, name, inPath) {
+			op.Parameters = append(op.Parameters, Parameter{Name: name, In: inPath, Schema: &Schema{Type: typeString}})
+		}
+	}
+	for i := range op.Parameters {
+		// path parameters are always required
+		op.Parameters[i].Required = true
+	}
+	if e.Query != nil {
+		op.Parameters = append(op.Parameters, s.gen.parameters(reflect.TypeOf(e.Query), inQuery, "form")...)
+	}
+
+	if e.Body != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{mimeJSON: {Schema: s.gen.schemaOf(reflect.TypeOf(e.Body))}},
+		}
+	}
+
+	status := e.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	resp := Response{Description: http.StatusText(status)}
+	if e.Response != nil {
+		resp.Content = map[string]MediaType{mimeJSON: {Schema: s.gen.schemaOf(reflect.TypeOf(e.Response))}}
+	}
+	op.Responses[strconv.Itoa(status)] = resp
+
+	p := Path(ginPath)
+	if _, ok := s.doc.Paths[p]; !ok {
+		s.doc.Paths[p] = make(PathItem)
+	}
+	s.doc.Paths[p][strings.ToLower(method)] = op
+	return op
+}
+
+func hasParameter(params []Parameter, name, in string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// parameters returns the parameters of struct fields with the tag, fields of embedded structs are included.
+func (g *generator) parameters(t reflect.Type, in, tagName string) []Parameter {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var params []Parameter
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _ := parseTag(f.Tag.Get(tagName))
+		if f.Anonymous && len(name) == 0 {
+			params = append(params, g.parameters(f.Type, in, tagName)...)
+			continue
+		}
+		if len(name) == 0 || name == "-" || len(f.PkgPath) != 0 {
+			continue
+		}
+		schema := g.schemaOf(f.Type)
+		if f.Type == durationType {
+			// durations are marshaled to JSON as integers but bound from parameters with time.ParseDuration
+			schema = &Schema{Type: typeString, Format: "duration"}
+		}
+		params = append(params, Parameter{
+			Name:     name,
+			In:       in,
+			Required: applyBinding(schema, f.Tag.Get("binding")),
+			Schema:   schema,
+		})
+	}
+	return params
+}
+
+// Document returns a copy of the document.
+func (s *Spec) Document() (Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.doc.copy()
+}
+
+// Handler serves the document in JSON.
+func (s *Spec) Handler(c *gin.Context) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	c.JSON(http.StatusOK, s.doc)
+}
+
+// Router registers routes to gin router and describes them in the spec.
+type Router struct {
+	r        gin.IRoutes
+	spec     *Spec
+	validate bool
+}
+
+// Option configures the Router.
+type Option func(r *Router)
+
+// WithValidation validates requests against the spec before passing them to handlers, invalid requests
+// are rejected with 400.
+func WithValidation() Option {
+	return func(r *Router) {
+		r.validate = true
+	}
+}
+
+// NewRouter creates a Router and registers the document at DocumentPath.
+func NewRouter(r gin.IRoutes, spec *Spec, options ...Option) *Router {
+	router := &Router{r: r, spec: spec}
+	for _, opt := range options {
+		opt(router)
+	}
+	r.GET(DocumentPath, spec.Handler)
+	return router
+}
+
+// Handle registers the handlers and describes the route in the spec.
+func (r *Router) Handle(method, path string, e Endpoint, handlers ...gin.HandlerFunc) {
+	op := r.spec.Add(method, path, e)
+	if r.validate {
+		handlers = append([]gin.HandlerFunc{r.spec.validator(op)}, handlers...)
+	}
+	r.r.Handle(method, path, handlers...)
+}
+
+// GET is a shortcut for Handle(http.MethodGet, path, e, handlers...).
+func (r *Router) GET(path string, e Endpoint, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodGet, path, e, handlers...)
+}
+
+// POST is a shortcut for Handle(http.MethodPost, path, e, handlers...).
+func (r *Router) POST(path string, e Endpoint, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPost, path, e, handlers...)
+}
+
+// PUT is a shortcut for Handle(http.MethodPut, path, e, handlers...).
+func (r *Router) PUT(path string, e Endpoint, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodPut, path, e, handlers...)
+}
+
+// DELETE is a shortcut for Handle(http.MethodDelete, path, e, handlers...).
+func (r *Router) DELETE(path string, e Endpoint, handlers ...gin.HandlerFunc) {
+	r.Handle(http.MethodDelete, path, e, handlers...)
+}
@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+)
+
+// patterns caches compiled patterns of schemas.
+var patterns sync.Map
+
+func matchPattern(pattern, value string) (bool, error) {
+	re, ok := patterns.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		re, _ = patterns.LoadOrStore(pattern, compiled)
+	}
+	return re.(*regexp.Regexp).MatchString(value), nil
+}
+
+// validator returns the middleware rejecting requests not matching the operation.
+func (s *Spec) validator(op *Operation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.mu.RLock()
+		err := s.doc.validateRequest(op, c)
+		s.mu.RUnlock()
+		if err != nil {
+			httputil.ResponseFailure(c, http.StatusBadRequest, err)
+			c.Abort()
+		}
+	}
+}
+
+func (d *Document) validateRequest(op *Operation, c *gin.Context) error {
+	query := c.Request.URL.Query()
+	for _, p := range op.Parameters {
+		var values []string
+		switch p.In {
+		case inPath:
+			if v := c.Param(p.Name); len(v) != 0 {
+				values = []string{v}
+			}
+		case inQuery:
+			values = query[p.Name]
+		}
+		if len(values) == 0 {
+			if p.Required {
+				return fmt.Errorf("missing required %s parameter %s", p.In, p.Name)
+			}
+			continue
+		}
+		schema := d.resolve(p.Schema)
+		if schema != nil && schema.Type == typeArray {
+			for _, v := range values {
+				if err := d.validateString(p.Name, v, schema.Items); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := d.validateString(p.Name, values[0], schema); err != nil {
+			return err
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+	mt, ok := op.RequestBody.Content[mimeJSON]
+	if !ok {
+		return nil
+	}
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(c.Request.Body); err != nil {
+			return err
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			return fmt.Errorf("missing request body")
+		}
+		return nil
+	}
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return fmt.Errorf("invalid request body: %s", err)
+	}
+	return d.validateValue("body", v, mt.Schema)
+}
+
+// validateString validates parameter value against the schema.
+func (d *Document) validateString(name, value string, s *Schema) error {
+	s = d.resolve(s)
+	if s == nil {
+		return nil
+	}
+	switch s.Type {
+	case typeInteger, typeNumber:
+		return d.validateValue(name, json.Number(value), s)
+	case typeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s: expected boolean, got %q", name, value)
+		}
+		return nil
+	default:
+		return d.validateValue(name, value, s)
+	}
+}
+
+// validateValue validates decoded JSON value against the schema.
+func (d *Document) validateValue(name string, v interface{}, s *Schema) error {
+	s = d.resolve(s)
+	// null is accepted for all types as they are decoded to zero values
+	if s == nil || v == nil {
+		return nil
+	}
+	switch s.Type {
+	case typeObject:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object", name)
+		}
+		for _, required := range s.Required {
+			if _, ok := m[required]; !ok {
+				return fmt.Errorf("%s.%s is required", name, required)
+			}
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			prop, ok := s.Properties[k]
+			if !ok {
+				prop = s.AdditionalProperties
+			}
+			if err := d.validateValue(name+"."+k, m[k], prop); err != nil {
+				return err
+			}
+		}
+	case typeArray:
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array", name)
+		}
+		for i, item := range items {
+			if err := d.validateValue(fmt.Sprintf("%s[%d]", name, i), item, s.Items); err != nil {
+				return err
+			}
+		}
+	case typeString:
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string", name)
+		}
+		if len(s.Pattern) != 0 {
+			matched, err := matchPattern(s.Pattern, str)
+			if err != nil {
+				return err
+			}
+			if !matched {
+				return fmt.Errorf("%s: %q does not match %s", name, str, s.Pattern)
+			}
+		}
+	case typeInteger, typeNumber:
+		num, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: expected %s", name, s.Type)
+		}
+		f, err := num.Float64()
+		if err != nil {
+			return fmt.Errorf("%s: expected %s, got %q", name, s.Type, num.String())
+		}
+		if _, ok = new(big.Int).SetString(num.String(), 10); s.Type == typeInteger && !ok {
+			return fmt.Errorf("%s: expected integer, got %q", name, num.String())
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			return fmt.Errorf("%s: %s is less than minimum %v", name, num.String(), *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return fmt.Errorf("%s: %s is greater than maximum %v", name, num.String(), *s.Maximum)
+		}
+	case typeBoolean:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", name)
+		}
+	}
+	if len(s.Enum) != 0 && !inEnum(v, s.Enum) {
+		return fmt.Errorf("%s: %v is not one of %v", name, v, s.Enum)
+	}
+	return nil
+}
+
+func inEnum(v interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package alert
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+	"github.com/KyberNetwork/reserve-stats/reserverates/common"
+)
+
+// RuleName is the identifier of a rule that triggered an alert.
+type RuleName string
+
+const (
+	// BuyAboveSanity fires when buy reserve rate is higher than buy sanity rate.
+	BuyAboveSanity RuleName = "buy_above_sanity"
+	// SellBelowSanity fires when sell reserve rate is lower than sell sanity rate.
+	SellBelowSanity RuleName = "sell_below_sanity"
+	// SpreadInversion fires when buy rate * sell rate > 1, the reserve is giving away arbitrage.
+	SpreadInversion RuleName = "spread_inversion"
+	// RateJump fires when a rate changes more than configured threshold versus previous block range.
+	RateJump RuleName = "rate_jump"
+	// ZeroRate fires when a rate stays at zero for configured number of blocks.
+	ZeroRate RuleName = "zero_rate"
+)
+
+// Alert is a single rule violation of a reserve pair at a block.
+type Alert struct {
+	Rule      RuleName                 `json:"rule"`
+	Reserve   string                   `json:"reserve"`
+	Pair      string                   `json:"pair"`
+	Block     uint64                   `json:"block"`
+	Timestamp time.Time                `json:"timestamp"`
+	Message   string                   `json:"message"`
+	Rates     common.ReserveRateEntry  `json:"rates"`
+	Previous  *common.ReserveRateEntry `json:"previous,omitempty"`
+	// PreviousFromBlock and PreviousToBlock is the block range of previous rates, if available.
+	PreviousFromBlock uint64 `json:"previous_from_block,omitempty"`
+	PreviousToBlock   uint64 `json:"previous_to_block,omitempty"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Alert to format timestamp in unix millis instead of RFC3339.
+func (a Alert) MarshalJSON() ([]byte, error) {
+	type AliasAlert Alert
+	return json.Marshal(struct {
+		Timestamp uint64 `json:"timestamp"`
+		AliasAlert
+	}{
+		AliasAlert: (AliasAlert)(a),
+		Timestamp:  timeutil.TimeToTimestampMs(a.Timestamp),
+	})
+}
@@ -0,0 +1,224 @@
+package alert
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/reserverates/common"
+)
+
+// pairState is what engine remembers about a reserve pair between blocks.
+type pairState struct {
+	rate      common.ReserveRateEntry
+	fromBlock uint64
+	toBlock   uint64
+
+	// previous is the rate of the block range before current one.
+	previous          *common.ReserveRateEntry
+	previousFromBlock uint64
+	previousToBlock   uint64
+
+	zeroBlocks uint64
+	// firing keeps the rules that are currently violated to not sending the same alert every block.
+	firing map[RuleName]bool
+}
+
+// Engine runs configured rules against crawled reserve rates and sends violations to sinks.
+// Rates must be given to engine in block order.
+type Engine struct {
+	sugar       *zap.SugaredLogger
+	rules       []Rule
+	sinks       []Sink
+	blkTimeRsv  blockchain.BlockTimeResolverInterface
+	mu          sync.Mutex
+	states      map[string]map[string]*pairState
+	lastBlock   uint64
+	hasLastSeen bool
+}
+
+// EngineOption is option to Engine constructor.
+type EngineOption func(*Engine)
+
+// WithSinks configures the sinks that alerts will be sent to.
+func WithSinks(sinks ...Sink) EngineOption {
+	return func(e *Engine) {
+		e.sinks = append(e.sinks, sinks...)
+	}
+}
+
+// WithBlockTimeResolver configures engine to resolve alert timestamp from block number.
+// If not provided, the processing time is used.
+func WithBlockTimeResolver(blkTimeRsv blockchain.BlockTimeResolverInterface) EngineOption {
+	return func(e *Engine) {
+		e.blkTimeRsv = blkTimeRsv
+	}
+}
+
+// NewEngine creates a new Engine instance with given rules.
+func NewEngine(sugar *zap.SugaredLogger, rules []Rule, options ...EngineOption) *Engine {
+	e := &Engine{
+		sugar:  sugar,
+		rules:  rules,
+		states: make(map[string]map[string]*pairState),
+	}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// NewDefaultRules returns all rules supported by engine.
+func NewDefaultRules(jumpThreshold float64, zeroRateBlocks uint64) []Rule {
+	return []Rule{
+		NewBuyAboveSanityRule(),
+		NewSellBelowSanityRule(),
+		NewSpreadInversionRule(),
+		NewRateJumpRule(jumpThreshold),
+		NewZeroRateRule(zeroRateBlocks),
+	}
+}
+
+func (e *Engine) state(reserve, pair string) *pairState {
+	pairs, ok := e.states[reserve]
+	if !ok {
+		pairs = make(map[string]*pairState)
+		e.states[reserve] = pairs
+	}
+	st, ok := pairs[pair]
+	if !ok {
+		st = &pairState{firing: make(map[RuleName]bool)}
+		pairs[pair] = st
+	}
+	return st
+}
+
+func (st *pairState) observe(block uint64, rate common.ReserveRateEntry) observation {
+	var changed bool
+	switch {
+	case st.fromBlock == 0 && st.toBlock == 0:
+		st.fromBlock, st.toBlock = block, block+1
+	case st.rate != rate:
+		previous := st.rate
+		st.previous = &previous
+		st.previousFromBlock, st.previousToBlock = st.fromBlock, st.toBlock
+		st.fromBlock, st.toBlock = block, block+1
+		changed = true
+	default:
+		st.toBlock = block + 1
+	}
+	st.rate = rate
+
+	if rate.BuyReserveRate == 0 || rate.SellReserveRate == 0 {
+		st.zeroBlocks++
+	} else {
+		st.zeroBlocks = 0
+	}
+
+	return observation{
+		current:    rate,
+		previous:   st.previous,
+		changed:    changed,
+		zeroBlocks: st.zeroBlocks,
+	}
+}
+
+// Process checks rates of all reserves at given block, sends the new alerts to all configured sinks
+// and returns them. See Check for when an alert is reported.
+func (e *Engine) Process(block uint64, rates map[string]map[string]common.ReserveRateEntry) []Alert {
+	alerts := e.Check(block, rates)
+	e.Send(block, alerts)
+	return alerts
+}
+
+// Check checks rates of all reserves at given block and returns the new alerts without sending them.
+// An alert is only returned once when its rule starts to be violated, it will be returned again only
+// after the rule is back to normal and violated again.
+func (e *Engine) Check(block uint64, rates map[string]map[string]common.ReserveRateEntry) []Alert {
+	var (
+		logger = e.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"block", block,
+		)
+		alerts []Alert
+	)
+
+	e.mu.Lock()
+	if e.hasLastSeen && block <= e.lastBlock {
+		logger.Warnw("ignoring rates of already processed block", "last_block", e.lastBlock)
+		e.mu.Unlock()
+		return nil
+	}
+	e.lastBlock, e.hasLastSeen = block, true
+
+	for reserve, pairs := range rates {
+		for pair, rate := range pairs {
+			st := e.state(reserve, pair)
+			obs := st.observe(block, rate)
+			for _, rule := range e.rules {
+				msg, violated := rule.check(obs)
+				if !violated {
+					delete(st.firing, rule.Name())
+					continue
+				}
+				if st.firing[rule.Name()] {
+					continue
+				}
+				st.firing[rule.Name()] = true
+				alerts = append(alerts, Alert{
+					Rule:              rule.Name(),
+					Reserve:           reserve,
+					Pair:              pair,
+					Block:             block,
+					Message:           msg,
+					Rates:             rate,
+					Previous:          obs.previous,
+					PreviousFromBlock: st.previousFromBlock,
+					PreviousToBlock:   st.previousToBlock,
+				})
+			}
+		}
+	}
+	e.mu.Unlock()
+
+	if len(alerts) == 0 {
+		return nil
+	}
+	logger.Infow("reserve rates alerts detected", "alerts", len(alerts))
+	return alerts
+}
+
+// Send stamps alerts of given block with the block time and delivers them to all configured sinks.
+// It might block on slow sinks, so callers should not hold any lock while calling it.
+func (e *Engine) Send(block uint64, alerts []Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+	logger := e.sugar.With(
+		"func", caller.GetCurrentFunctionName(),
+		"block", block,
+	)
+
+	timestamp := time.Now()
+	if e.blkTimeRsv != nil {
+		ts, err := e.blkTimeRsv.Resolve(block)
+		if err != nil {
+			logger.Warnw("failed to resolve block time, using current time", "err", err)
+		} else {
+			timestamp = ts
+		}
+	}
+	for i := range alerts {
+		alerts[i].Timestamp = timestamp
+	}
+
+	for _, sink := range e.sinks {
+		if err := sink.Send(alerts); err != nil {
+			// failing to deliver alerts should not stop crawling rates
+			logger.Errorw("failed to send alerts", "sink", sink.Name(), "err", err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package alert
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/reserverates/common"
+)
+
+const (
+	testReserve = "0x63825c174ab367968EC60f061753D3bbD36A0D8F"
+	testPair    = "ETH-KNC"
+)
+
+func ratesAt(rate common.ReserveRateEntry) map[string]map[string]common.ReserveRateEntry {
+	return map[string]map[string]common.ReserveRateEntry{
+		testReserve: {testPair: rate},
+	}
+}
+
+func rulesOf(alerts []Alert) []RuleName {
+	var names []RuleName
+	for _, a := range alerts {
+		names = append(names, a.Rule)
+	}
+	return names
+}
+
+func TestRules(t *testing.T) {
+	var tests = []struct {
+		name     string
+		rule     Rule
+		obs      observation
+		violated bool
+	}{
+		{
+			name:     "buy rate above sanity",
+			rule:     NewBuyAboveSanityRule(),
+			obs:      observation{current: common.ReserveRateEntry{BuyReserveRate: 101, BuySanityRate: 100}},
+			violated: true,
+		},
+		{
+			name: "buy sanity rate not configured",
+			rule: NewBuyAboveSanityRule(),
+			obs:  observation{current: common.ReserveRateEntry{BuyReserveRate: 101}},
+		},
+		{
+			name:     "sell rate below sanity",
+			rule:     NewSellBelowSanityRule(),
+			obs:      observation{current: common.ReserveRateEntry{SellReserveRate: 0.009, SellSanityRate: 0.01}},
+			violated: true,
+		},
+		{
+			name: "sell rate above sanity",
+			rule: NewSellBelowSanityRule(),
+			obs:  observation{current: common.ReserveRateEntry{SellReserveRate: 0.011, SellSanityRate: 0.01}},
+		},
+		{
+			name:     "spread inverted",
+			rule:     NewSpreadInversionRule(),
+			obs:      observation{current: common.ReserveRateEntry{BuyReserveRate: 100, SellReserveRate: 0.0101}},
+			violated: true,
+		},
+		{
+			name: "normal spread",
+			rule: NewSpreadInversionRule(),
+			obs:  observation{current: common.ReserveRateEntry{BuyReserveRate: 99, SellReserveRate: 0.01}},
+		},
+		{
+			name: "rate jump",
+			rule: NewRateJumpRule(0.1),
+			obs: observation{
+				current:  common.ReserveRateEntry{BuyReserveRate: 120, SellReserveRate: 0.01},
+				previous: &common.ReserveRateEntry{BuyReserveRate: 100, SellReserveRate: 0.01},
+				changed:  true,
+			},
+			violated: true,
+		},
+		{
+			name: "rate changed within threshold",
+			rule: NewRateJumpRule(0.1),
+			obs: observation{
+				current:  common.ReserveRateEntry{BuyReserveRate: 105, SellReserveRate: 0.0099},
+				previous: &common.ReserveRateEntry{BuyReserveRate: 100, SellReserveRate: 0.01},
+				changed:  true,
+			},
+		},
+		{
+			name:     "zero rate long enough",
+			rule:     NewZeroRateRule(3),
+			obs:      observation{zeroBlocks: 3},
+			violated: true,
+		},
+		{
+			name: "zero rate not long enough",
+			rule: NewZeroRateRule(3),
+			obs:  observation{zeroBlocks: 2},
+		},
+	}
+
+	for _, tc := range tests {
+		_, violated := tc.rule.check(tc.obs)
+		assert.Equal(t, tc.violated, violated, tc.name)
+	}
+}
+
+func TestEngineProcess(t *testing.T) {
+	var (
+		sugar  = testutil.MustNewDevelopmentSugaredLogger()
+		ts     = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		engine = NewEngine(sugar, NewDefaultRules(0.1, 2),
+			WithBlockTimeResolver(blockchain.NewMockBlockTimeResolve(ts)))
+		normal = common.ReserveRateEntry{
+			BuyReserveRate:  100,
+			BuySanityRate:   110,
+			SellReserveRate: 0.0098,
+			SellSanityRate:  0.009,
+		}
+		jumped = common.ReserveRateEntry{
+			BuyReserveRate:  130,
+			BuySanityRate:   110,
+			SellReserveRate: 0.0098,
+			SellSanityRate:  0.009,
+		}
+		zero = common.ReserveRateEntry{BuySanityRate: 110, SellSanityRate: 0.009}
+	)
+
+	assert.Empty(t, engine.Process(10, ratesAt(normal)))
+	assert.Empty(t, engine.Process(11, ratesAt(normal)))
+
+	alerts := engine.Process(12, ratesAt(jumped))
+	assert.ElementsMatch(t, []RuleName{BuyAboveSanity, RateJump, SpreadInversion}, rulesOf(alerts))
+	for _, a := range alerts {
+		assert.Equal(t, testReserve, a.Reserve)
+		assert.Equal(t, testPair, a.Pair)
+		assert.Equal(t, uint64(12), a.Block)
+		assert.Equal(t, ts, a.Timestamp)
+		assert.Equal(t, uint64(10), a.PreviousFromBlock)
+		assert.Equal(t, uint64(12), a.PreviousToBlock)
+		require.NotNil(t, a.Previous)
+		assert.Equal(t, normal, *a.Previous)
+	}
+
+	// still violating but alerts are already sent
+	assert.Empty(t, engine.Process(13, ratesAt(jumped)))
+	// already processed block is ignored
+	assert.Empty(t, engine.Process(13, ratesAt(normal)))
+
+	// back to normal rate, rate jump rule fires again
+	assert.Equal(t, []RuleName{RateJump}, rulesOf(engine.Process(14, ratesAt(normal))))
+
+	assert.Empty(t, engine.Process(15, ratesAt(zero)))
+	assert.Equal(t, []RuleName{ZeroRate}, rulesOf(engine.Process(16, ratesAt(zero))))
+	assert.Empty(t, engine.Process(17, ratesAt(zero)))
+}
+
+func TestWebhookSink(t *testing.T) {
+	var (
+		sugar    = testutil.MustNewDevelopmentSugaredLogger()
+		received []map[string]interface{}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var payload struct {
+			Alerts []map[string]interface{} `json:"alerts"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		received = append(received, payload.Alerts...)
+	}))
+	defer server.Close()
+
+	engine := NewEngine(sugar, []Rule{NewSpreadInversionRule()},
+		WithSinks(NewWebhookSink(sugar, server.URL, time.Second)))
+	alerts := engine.Process(1, ratesAt(common.ReserveRateEntry{BuyReserveRate: 2, SellReserveRate: 1}))
+	require.Len(t, alerts, 1)
+	require.Len(t, received, 1)
+	assert.Equal(t, string(SpreadInversion), received[0]["rule"])
+	assert.Equal(t, testReserve, received[0]["reserve"])
+	assert.Equal(t, testPair, received[0]["pair"])
+	assert.Equal(t, float64(1), received[0]["block"])
+
+	failing := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		http.Error(rw, "internal error", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	assert.Error(t, NewWebhookSink(sugar, failing.URL, time.Second).Send(alerts))
+}
@@ -0,0 +1,98 @@
+package alert
+
+import (
+	"fmt"
+
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
+)
+
+const (
+	webhookURLsFlag = "alert-webhook-urls"
+
+	webhookTimeoutFlag = "alert-webhook-timeout"
+
+	jumpThresholdFlag        = "alert-jump-threshold"
+	defaultJumpThreshold     = 0.1
+	zeroRateBlocksFlag       = "alert-zero-rate-blocks"
+	defaultZeroRateBlocks    = 10
+	disabledRulesFlag        = "alert-disabled-rules"
+	disabledRulesFlagExample = "--alert-disabled-rules=rate_jump,zero_rate"
+)
+
+// NewCliFlags returns flags to configure reserve rates alert engine.
+func NewCliFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.StringSliceFlag{
+			Name:   webhookURLsFlag,
+			Usage:  "list of webhook urls to send reserve rates alerts to, alerting is disabled if empty",
+			EnvVar: "ALERT_WEBHOOK_URLS",
+		},
+		cli.DurationFlag{
+			Name:   webhookTimeoutFlag,
+			Usage:  "timeout of each webhook request",
+			EnvVar: "ALERT_WEBHOOK_TIMEOUT",
+			Value:  defaultWebhookTimeout,
+		},
+		cli.Float64Flag{
+			Name:   jumpThresholdFlag,
+			Usage:  "ratio of rate change versus previous block range to be considered a jump, 0.1 means 10%",
+			EnvVar: "ALERT_JUMP_THRESHOLD",
+			Value:  defaultJumpThreshold,
+		},
+		cli.IntFlag{
+			Name:   zeroRateBlocksFlag,
+			Usage:  "number of consecutive blocks a rate is zero before alerting",
+			EnvVar: "ALERT_ZERO_RATE_BLOCKS",
+			Value:  defaultZeroRateBlocks,
+		},
+		cli.StringSliceFlag{
+			Name:   disabledRulesFlag,
+			Usage:  fmt.Sprintf("list of rules to disable. Example: %s", disabledRulesFlagExample),
+			EnvVar: "ALERT_DISABLED_RULES",
+		},
+	}
+}
+
+// NewEngineFromContext creates alert engine from cli flags. It returns nil if no sink is configured.
+func NewEngineFromContext(c *cli.Context, sugar *zap.SugaredLogger, blkTimeRsv blockchain.BlockTimeResolverInterface) (*Engine, error) {
+	urls := c.StringSlice(webhookURLsFlag)
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	threshold := c.Float64(jumpThresholdFlag)
+	if threshold <= 0 {
+		return nil, fmt.Errorf("flag %s: must be positive, got %f", jumpThresholdFlag, threshold)
+	}
+	zeroBlocks := c.Int(zeroRateBlocksFlag)
+	if zeroBlocks <= 0 {
+		return nil, fmt.Errorf("flag %s: must be positive, got %d", zeroRateBlocksFlag, zeroBlocks)
+	}
+
+	var (
+		disabled = make(map[RuleName]bool)
+		rules    []Rule
+		sinks    []Sink
+	)
+	for _, name := range c.StringSlice(disabledRulesFlag) {
+		disabled[RuleName(name)] = true
+	}
+	for _, rule := range NewDefaultRules(threshold, uint64(zeroBlocks)) {
+		if disabled[rule.Name()] {
+			delete(disabled, rule.Name())
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	for name := range disabled {
+		return nil, fmt.Errorf("flag %s: unknown rule %s", disabledRulesFlag, name)
+	}
+
+	for _, url := range urls {
+		sinks = append(sinks, NewWebhookSink(sugar, url, c.Duration(webhookTimeoutFlag)))
+	}
+	return NewEngine(sugar, rules, WithSinks(sinks...), WithBlockTimeResolver(blkTimeRsv)), nil
+}
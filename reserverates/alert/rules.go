@@ -0,0 +1,145 @@
+package alert
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/KyberNetwork/reserve-stats/reserverates/common"
+)
+
+// observation is the input of rules: current rates of a reserve pair and what engine knows about its history.
+type observation struct {
+	current  common.ReserveRateEntry
+	previous *common.ReserveRateEntry
+	// changed is true if current rate starts a new block range.
+	changed bool
+	// zeroBlocks is the number of consecutive blocks, including current one, that buy or sell rate is zero.
+	zeroBlocks uint64
+}
+
+// Rule checks an observation and returns a message describing the violation if any.
+type Rule interface {
+	Name() RuleName
+	check(obs observation) (string, bool)
+}
+
+type buyAboveSanityRule struct{}
+
+// NewBuyAboveSanityRule returns rule that fires when buy rate is higher than buy sanity rate.
+func NewBuyAboveSanityRule() Rule {
+	return buyAboveSanityRule{}
+}
+
+func (buyAboveSanityRule) Name() RuleName { return BuyAboveSanity }
+
+func (buyAboveSanityRule) check(obs observation) (string, bool) {
+	rate := obs.current
+	// sanity rate is not configured for this pair
+	if rate.BuySanityRate == 0 {
+		return "", false
+	}
+	if rate.BuyReserveRate > rate.BuySanityRate {
+		return fmt.Sprintf("buy rate %f is higher than buy sanity rate %f", rate.BuyReserveRate, rate.BuySanityRate), true
+	}
+	return "", false
+}
+
+type sellBelowSanityRule struct{}
+
+// NewSellBelowSanityRule returns rule that fires when sell rate is lower than sell sanity rate.
+func NewSellBelowSanityRule() Rule {
+	return sellBelowSanityRule{}
+}
+
+func (sellBelowSanityRule) Name() RuleName { return SellBelowSanity }
+
+func (sellBelowSanityRule) check(obs observation) (string, bool) {
+	rate := obs.current
+	if rate.SellSanityRate == 0 || rate.SellReserveRate == 0 {
+		return "", false
+	}
+	if rate.SellReserveRate < rate.SellSanityRate {
+		return fmt.Sprintf("sell rate %f is lower than sell sanity rate %f", rate.SellReserveRate, rate.SellSanityRate), true
+	}
+	return "", false
+}
+
+type spreadInversionRule struct{}
+
+// NewSpreadInversionRule returns rule that fires when buy rate * sell rate > 1.
+func NewSpreadInversionRule() Rule {
+	return spreadInversionRule{}
+}
+
+func (spreadInversionRule) Name() RuleName { return SpreadInversion }
+
+func (spreadInversionRule) check(obs observation) (string, bool) {
+	rate := obs.current
+	product := rate.BuyReserveRate * rate.SellReserveRate
+	if product > 1 {
+		return fmt.Sprintf("spread is inverted, buy rate %f * sell rate %f = %f",
+			rate.BuyReserveRate, rate.SellReserveRate, product), true
+	}
+	return "", false
+}
+
+type rateJumpRule struct {
+	threshold float64
+}
+
+// NewRateJumpRule returns rule that fires when buy or sell rate changes more than threshold (0.1 means 10%)
+// comparing to previous block range. The rule is only checked at the first block of a range.
+func NewRateJumpRule(threshold float64) Rule {
+	return rateJumpRule{threshold: threshold}
+}
+
+func (rateJumpRule) Name() RuleName { return RateJump }
+
+func changeRatio(previous, current float64) float64 {
+	if previous == 0 {
+		return 0
+	}
+	return math.Abs(current-previous) / previous
+}
+
+func (r rateJumpRule) check(obs observation) (string, bool) {
+	if obs.previous == nil || !obs.changed {
+		return "", false
+	}
+	// moving from or to zero rate is covered by zero rate rule
+	if obs.current.BuyReserveRate != 0 {
+		if change := changeRatio(obs.previous.BuyReserveRate, obs.current.BuyReserveRate); change > r.threshold {
+			return fmt.Sprintf("buy rate changed %.2f%% from %f to %f",
+				change*100, obs.previous.BuyReserveRate, obs.current.BuyReserveRate), true
+		}
+	}
+	if obs.current.SellReserveRate != 0 {
+		if change := changeRatio(obs.previous.SellReserveRate, obs.current.SellReserveRate); change > r.threshold {
+			return fmt.Sprintf("sell rate changed %.2f%% from %f to %f",
+				change*100, obs.previous.SellReserveRate, obs.current.SellReserveRate), true
+		}
+	}
+	return "", false
+}
+
+type zeroRateRule struct {
+	blocks uint64
+}
+
+// NewZeroRateRule returns rule that fires when buy or sell rate is zero for at least given number of blocks.
+func NewZeroRateRule(blocks uint64) Rule {
+	if blocks == 0 {
+		blocks = 1
+	}
+	return zeroRateRule{blocks: blocks}
+}
+
+func (zeroRateRule) Name() RuleName { return ZeroRate }
+
+func (r zeroRateRule) check(obs observation) (string, bool) {
+	if obs.zeroBlocks >= r.blocks {
+		return fmt.Sprintf("rate is zero for %d blocks (buy rate %f, sell rate %f)",
+			obs.zeroBlocks, obs.current.BuyReserveRate, obs.current.SellReserveRate), true
+	}
+	return "", false
+}
@@ -0,0 +1,70 @@
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultWebhookTimeout = 10 * time.Second
+
+// Sink is the destination of alerts.
+type Sink interface {
+	Name() string
+	Send(alerts []Alert) error
+}
+
+// WebhookSink posts alerts as JSON to an HTTP endpoint.
+type WebhookSink struct {
+	sugar  *zap.SugaredLogger
+	url    string
+	client *http.Client
+}
+
+type webhookPayload struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+// NewWebhookSink creates a new WebhookSink instance that posts to given url.
+func NewWebhookSink(sugar *zap.SugaredLogger, url string, timeout time.Duration) *WebhookSink {
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+	return &WebhookSink{
+		sugar:  sugar,
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Name returns the url of webhook.
+func (ws *WebhookSink) Name() string {
+	return ws.url
+}
+
+// Send posts alerts to webhook, returns error if webhook does not response with 2xx status code.
+func (ws *WebhookSink) Send(alerts []Alert) error {
+	body, err := json.Marshal(webhookPayload{Alerts: alerts})
+	if err != nil {
+		return err
+	}
+	resp, err := ws.client.Post(ws.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cErr := resp.Body.Close(); cErr != nil {
+			ws.sugar.Errorw("failed to close body", "err", cErr.Error())
+		}
+	}()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 	"github.com/KyberNetwork/reserve-stats/reserverates/http"
 	"github.com/KyberNetwork/reserve-stats/reserverates/storage"
 	"github.com/KyberNetwork/reserve-stats/reserverates/storage/postgres"
@@ -22,6 +23,7 @@ func newServerCli() *cli.App {
 	app.Usage = "server for query rate API"
 	app.Flags = append(app.Flags, httputil.NewHTTPCliFlags(httputil.ReserveRatesPort)...)
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(defaultPostgresDB)...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 	app.Action = func(c *cli.Context) error {
 		if err := libapp.Validate(c); err != nil {
 			return err
@@ -43,7 +45,7 @@ func newServerCli() *cli.App {
 		}
 
 		hostStr := httputil.NewHTTPAddressFromContext(c)
-		server, err := http.NewServer(hostStr, rateStorage, sugar)
+		server, err := http.NewServer(hostStr, rateStorage, sugar, openapi.NewOptionsFromContext(c)...)
 		if err != nil {
 			return err
 		}
@@ -18,6 +18,8 @@ import (
 	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
 	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
 	"github.com/KyberNetwork/reserve-stats/lib/contracts"
+	"github.com/KyberNetwork/reserve-stats/reserverates/alert"
+	"github.com/KyberNetwork/reserve-stats/reserverates/discovery"
 	"github.com/KyberNetwork/reserve-stats/reserverates/storage"
 	"github.com/KyberNetwork/reserve-stats/reserverates/storage/postgres"
 	"github.com/KyberNetwork/reserve-stats/reserverates/workers"
@@ -99,6 +101,8 @@ func main() {
 		blockchain.NewEthereumNodeFlags(),
 	)
 	app.Flags = append(app.Flags, libapp.NewPostgreSQLFlags(defaultPostgresDB)...)
+	app.Flags = append(app.Flags, alert.NewCliFlags()...)
+	app.Flags = append(app.Flags, discovery.NewCliFlags()...)
 
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
@@ -145,6 +149,16 @@ func run(c *cli.Context) error {
 		return err
 	}
 
+	var poolOptions []workers.PoolOption
+	alertEngine, err := alert.NewEngineFromContext(c, sugar, blockTimeResolver)
+	if err != nil {
+		return err
+	}
+	if alertEngine != nil {
+		sugar.Info("reserve rates alerting is enabled")
+		poolOptions = append(poolOptions, workers.WithAlertEngine(alertEngine))
+	}
+
 	if c.String(fromBlockFlag) == "" {
 		sugar.Info("no from block flag provided, checking last stored block")
 	} else {
@@ -168,20 +182,25 @@ func run(c *cli.Context) error {
 	attempts := c.Int(attemptsFlag)
 	delayTime := c.Duration(delayFlag)
 
-	addrs := c.StringSlice(addressesFlag)
-	if len(addrs) == 0 {
-		addr := contracts.InternalReserveAddress().MustGetOneFromContext(c)
-		addrs = append(addrs, addr.Hex())
-		sugar.Infow("using internal reserve address as user does not input any", "address", addr.Hex())
-	}
-
 	var ethAddrs []ethereum.Address
-	for _, addr := range addrs {
+	for _, addr := range c.StringSlice(addressesFlag) {
 		if !ethereum.IsHexAddress(addr) {
 			return fmt.Errorf("non etherum address input %s", addr)
 		}
 		ethAddrs = append(ethAddrs, ethereum.HexToAddress(addr))
 	}
+
+	tracker, err := discovery.NewTrackerFromContext(c, sugar, ethClient, ethAddrs)
+	if err != nil {
+		return err
+	}
+	if tracker != nil {
+		sugar.Infow("reserve discovery is enabled", "static_addresses", len(ethAddrs))
+	} else if len(ethAddrs) == 0 {
+		addr := contracts.InternalReserveAddress().MustGetOneFromContext(c)
+		ethAddrs = append(ethAddrs, addr)
+		sugar.Infow("using internal reserve address as user does not input any", "address", addr.Hex())
+	}
 	for {
 		currentHeader, fErr := ethClient.HeaderByNumber(context.Background(), nil)
 		if fErr != nil {
@@ -208,7 +227,14 @@ func run(c *cli.Context) error {
 			sugar.Infow("fetching reserve rates up to latest known block number", "to_block", toBlock.String())
 		}
 
-		pool := workers.NewPool(sugar, maxWorkers, rateStorage)
+		if tracker != nil {
+			// toBlock is exclusive
+			if fErr = tracker.Sync(toBlock.Uint64() - 1); fErr != nil {
+				return fErr
+			}
+		}
+
+		pool := workers.NewPool(sugar, maxWorkers, rateStorage, poolOptions...)
 		doneCh := make(chan struct{})
 
 		go func(fromBlock, toBlock int64) {
@@ -216,7 +242,11 @@ func run(c *cli.Context) error {
 
 			for block := fromBlock; block < toBlock; block++ {
 				jobOrder++
-				pool.Run(workers.NewFetcherJob(c, jobOrder, uint64(block), ethAddrs, attempts))
+				addrs := ethAddrs
+				if tracker != nil {
+					addrs = tracker.Addresses(uint64(block))
+				}
+				pool.Run(workers.NewFetcherJob(c, jobOrder, uint64(block), addrs, attempts))
 			}
 
 			for pool.GetLastCompleteJobOrder() < jobOrder {
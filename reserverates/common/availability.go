@@ -0,0 +1,288 @@
+package common
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
+)
+
+// Outage is a window of consecutive block ranges that a reserve does not quote a pair.
+type Outage struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	FromBlock uint64    `json:"from_block"`
+	ToBlock   uint64    `json:"to_block"`
+	// Ongoing is true if the reserve still does not quote the pair at the end of report time range.
+	Ongoing bool `json:"ongoing"`
+}
+
+// Duration returns the duration of the outage.
+func (o Outage) Duration() time.Duration {
+	return o.End.Sub(o.Start)
+}
+
+// MarshalJSON implements custom JSON marshaler for Outage to format timestamps in unix millis.
+func (o Outage) MarshalJSON() ([]byte, error) {
+	type AliasOutage Outage
+	return json.Marshal(struct {
+		Start    uint64 `json:"start"`
+		End      uint64 `json:"end"`
+		Duration uint64 `json:"duration"`
+		AliasOutage
+	}{
+		AliasOutage: (AliasOutage)(o),
+		Start:       timeutil.TimeToTimestampMs(o.Start),
+		End:         timeutil.TimeToTimestampMs(o.End),
+		Duration:    uint64(o.Duration() / time.Millisecond),
+	})
+}
+
+// Availability is the availability of a reserve for a pair in a time range.
+type Availability struct {
+	TotalBlocks     uint64 `json:"total_blocks"`
+	AvailableBlocks uint64 `json:"available_blocks"`
+	// BlockAvailability is the percentage of blocks that the reserve quotes non-zero buy and sell rates.
+	BlockAvailability float64 `json:"block_availability"`
+	// TimeAvailability is the percentage of time that the reserve quotes non-zero buy and sell rates.
+	TimeAvailability float64 `json:"time_availability"`
+	// LongestOutages are the longest outage windows, longest first.
+	LongestOutages []Outage `json:"longest_outages"`
+	// LastValidQuote is the last time in report time range that the reserve quotes the pair,
+	// nil if the reserve does not quote the pair during the whole time range.
+	LastValidQuote *time.Time `json:"last_valid_quote"`
+	// SinceLastValidQuote is the duration from LastValidQuote to the end of report time range.
+	SinceLastValidQuote time.Duration `json:"since_last_valid_quote"`
+}
+
+// MarshalJSON implements custom JSON marshaler for Availability to format timestamps and durations in millis.
+func (a Availability) MarshalJSON() ([]byte, error) {
+	type AliasAvailability Availability
+	var lastValidQuote *uint64
+	if a.LastValidQuote != nil {
+		ts := timeutil.TimeToTimestampMs(*a.LastValidQuote)
+		lastValidQuote = &ts
+	}
+	return json.Marshal(struct {
+		LastValidQuote      *uint64 `json:"last_valid_quote"`
+		SinceLastValidQuote uint64  `json:"since_last_valid_quote"`
+		AliasAvailability
+	}{
+		AliasAvailability:   (AliasAvailability)(a),
+		LastValidQuote:      lastValidQuote,
+		SinceLastValidQuote: uint64(a.SinceLastValidQuote / time.Millisecond),
+	})
+}
+
+// AvailabilityReport is the availability of reserves, grouped by reserve address and pair.
+type AvailabilityReport map[string]map[string]Availability
+
+func isAvailable(entry ReserveRateEntry) bool {
+	return entry.BuyReserveRate != 0 && entry.SellReserveRate != 0
+}
+
+func percentage(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return part / total * 100
+}
+
+// fallbackBlockTime is the block time used to estimate the end of the last range when there is no other
+// range to compute the average block time from.
+const fallbackBlockTime = 13 * time.Second
+
+// averageBlockTime returns the average block time of all ranges except the last one, which end time is unknown.
+func averageBlockTime(sorted []ReserveRates) time.Duration {
+	var (
+		duration time.Duration
+		blocks   uint64
+	)
+	for i := 0; i+1 < len(sorted); i++ {
+		if end := sorted[i+1].Timestamp; end.After(sorted[i].Timestamp) {
+			duration += end.Sub(sorted[i].Timestamp)
+			blocks += sorted[i].ToBlock - sorted[i].FromBlock
+		}
+	}
+	if blocks == 0 {
+		return fallbackBlockTime
+	}
+	return duration / time.Duration(blocks)
+}
+
+// NewAvailability computes availability of a pair from its stored block ranges in time range [from, to].
+// The range that is active at from should be included. A block range lasts until the start of next one,
+// the last range lasts until to, or until its estimated end by average block time if it crosses to.
+// Block counts of ranges partially in the time range are prorated by time.
+func NewAvailability(ranges []ReserveRates, from, to time.Time, maxOutages int) Availability {
+	var (
+		result                   Availability
+		totalTime, availableTime time.Duration
+		totalBlocks, availBlocks float64
+		outages                  []Outage
+		current                  *Outage
+		sorted                   = make([]ReserveRates, len(ranges))
+	)
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].FromBlock < sorted[j].FromBlock })
+	blockTime := averageBlockTime(sorted)
+
+	for i, r := range sorted {
+		end := to
+		if i+1 < len(sorted) {
+			end = sorted[i+1].Timestamp
+		} else if estimated := r.Timestamp.Add(blockTime * time.Duration(r.ToBlock-r.FromBlock)); estimated.After(to) {
+			end = estimated
+		}
+		if end.Before(r.Timestamp) {
+			end = r.Timestamp
+		}
+		start, stop := r.Timestamp, end
+		if start.Before(from) {
+			start = from
+		}
+		if stop.After(to) {
+			stop = to
+		}
+		if !stop.After(start) {
+			continue
+		}
+
+		duration := stop.Sub(start)
+		blocks := float64(r.ToBlock - r.FromBlock)
+		if full := end.Sub(r.Timestamp); full > 0 {
+			blocks = blocks * float64(duration) / float64(full)
+		}
+		totalTime += duration
+		totalBlocks += blocks
+
+		if isAvailable(r.Rates) {
+			availableTime += duration
+			availBlocks += blocks
+			lastValidQuote := stop
+			result.LastValidQuote = &lastValidQuote
+			if current != nil {
+				outages = append(outages, *current)
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil {
+			current = &Outage{Start: start, FromBlock: r.FromBlock}
+		}
+		current.End = stop
+		current.ToBlock = r.ToBlock
+	}
+	if current != nil {
+		current.Ongoing = true
+		outages = append(outages, *current)
+	}
+
+	sort.SliceStable(outages, func(i, j int) bool { return outages[i].Duration() > outages[j].Duration() })
+	if maxOutages >= 0 && len(outages) > maxOutages {
+		outages = outages[:maxOutages]
+	}
+	if outages == nil {
+		outages = []Outage{}
+	}
+
+	result.TotalBlocks = uint64(math.Round(totalBlocks))
+	result.AvailableBlocks = uint64(math.Round(availBlocks))
+	result.BlockAvailability = percentage(availBlocks, totalBlocks)
+	result.TimeAvailability = percentage(float64(availableTime), float64(totalTime))
+	result.LongestOutages = outages
+	if result.LastValidQuote != nil {
+		result.SinceLastValidQuote = to.Sub(*result.LastValidQuote)
+	}
+	return result
+}
+
+// NewAvailabilityReport computes availability of all reserves and pairs in given rates.
+func NewAvailabilityReport(rates map[string]map[string][]ReserveRates, from, to time.Time, maxOutages int) AvailabilityReport {
+	report := make(AvailabilityReport)
+	for reserve, pairs := range rates {
+		report[reserve] = make(map[string]Availability)
+		for pair, ranges := range pairs {
+			report[reserve][pair] = NewAvailability(ranges, from, to, maxOutages)
+		}
+	}
+	return report
+}
+
+var availabilityCSVHeader = []string{
+	"reserve",
+	"pair",
+	"total_blocks",
+	"available_blocks",
+	"block_availability",
+	"time_availability",
+	"longest_outage_start",
+	"longest_outage_end",
+	"longest_outage_duration_seconds",
+	"last_valid_quote",
+	"seconds_since_last_valid_quote",
+}
+
+// WriteCSV writes the report in CSV format, one row per reserve and pair. Times are in RFC3339 format,
+// empty if not available.
+func (r AvailabilityReport) WriteCSV(w io.Writer) error {
+	var reserves []string
+	for reserve := range r {
+		reserves = append(reserves, reserve)
+	}
+	sort.Strings(reserves)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(availabilityCSVHeader); err != nil {
+		return err
+	}
+	for _, reserve := range reserves {
+		var pairs []string
+		for pair := range r[reserve] {
+			pairs = append(pairs, pair)
+		}
+		sort.Strings(pairs)
+
+		for _, pair := range pairs {
+			var (
+				a                              = r[reserve][pair]
+				outageStart, outageEnd         string
+				outageDuration                 string
+				lastValidQuote, sinceLastValid string
+			)
+			if len(a.LongestOutages) != 0 {
+				longest := a.LongestOutages[0]
+				outageStart = longest.Start.UTC().Format(time.RFC3339)
+				outageEnd = longest.End.UTC().Format(time.RFC3339)
+				outageDuration = strconv.FormatFloat(longest.Duration().Seconds(), 'f', -1, 64)
+			}
+			if a.LastValidQuote != nil {
+				lastValidQuote = a.LastValidQuote.UTC().Format(time.RFC3339)
+				sinceLastValid = strconv.FormatFloat(a.SinceLastValidQuote.Seconds(), 'f', -1, 64)
+			}
+			if err := cw.Write([]string{
+				reserve,
+				pair,
+				strconv.FormatUint(a.TotalBlocks, 10),
+				strconv.FormatUint(a.AvailableBlocks, 10),
+				strconv.FormatFloat(a.BlockAvailability, 'f', 2, 64),
+				strconv.FormatFloat(a.TimeAvailability, 'f', 2, 64),
+				outageStart,
+				outageEnd,
+				outageDuration,
+				lastValidQuote,
+				sinceLastValid,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
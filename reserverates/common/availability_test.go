@@ -0,0 +1,118 @@
+package common
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAvailability(t *testing.T) {
+	const delta = 0.0001
+	var (
+		from      = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+		to        = from.Add(10 * time.Hour)
+		available = ReserveRateEntry{BuyReserveRate: 100, SellReserveRate: 0.01}
+		noBuy     = ReserveRateEntry{BuyReserveRate: 0, SellReserveRate: 0.01}
+		zero      = ReserveRateEntry{}
+		ranges    = []ReserveRates{
+			// active at from, half of it is in time range
+			{Timestamp: from.Add(-time.Hour), FromBlock: 0, ToBlock: 200, Rates: available},
+			{Timestamp: from.Add(time.Hour), FromBlock: 200, ToBlock: 300, Rates: noBuy},
+			{Timestamp: from.Add(2 * time.Hour), FromBlock: 300, ToBlock: 400, Rates: zero},
+			{Timestamp: from.Add(4 * time.Hour), FromBlock: 400, ToBlock: 500, Rates: available},
+			{Timestamp: from.Add(6 * time.Hour), FromBlock: 500, ToBlock: 600, Rates: zero},
+		}
+	)
+
+	a := NewAvailability(ranges, from, to, 5)
+	assert.Equal(t, uint64(500), a.TotalBlocks)
+	assert.Equal(t, uint64(200), a.AvailableBlocks)
+	assert.InDelta(t, 40, a.BlockAvailability, delta)
+	assert.InDelta(t, 30, a.TimeAvailability, delta)
+
+	require.Len(t, a.LongestOutages, 2)
+	assert.Equal(t, Outage{
+		Start:     from.Add(6 * time.Hour),
+		End:       to,
+		FromBlock: 500,
+		ToBlock:   600,
+		Ongoing:   true,
+	}, a.LongestOutages[0])
+	assert.Equal(t, Outage{
+		Start:     from.Add(time.Hour),
+		End:       from.Add(4 * time.Hour),
+		FromBlock: 200,
+		ToBlock:   400,
+	}, a.LongestOutages[1])
+
+	require.NotNil(t, a.LastValidQuote)
+	assert.Equal(t, from.Add(6*time.Hour), *a.LastValidQuote)
+	assert.Equal(t, 4*time.Hour, a.SinceLastValidQuote)
+
+	a = NewAvailability(ranges, from, to, 1)
+	assert.Len(t, a.LongestOutages, 1)
+
+	a = NewAvailability(ranges[4:], from.Add(7*time.Hour), to, 5)
+	assert.Nil(t, a.LastValidQuote)
+	assert.Zero(t, a.TimeAvailability)
+}
+
+func TestNewAvailabilityLastRangeCrossesTo(t *testing.T) {
+	const delta = 0.0001
+	var (
+		from   = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+		to     = from.Add(2 * time.Hour)
+		ranges = []ReserveRates{
+			// 100 blocks in an hour, 36 seconds per block
+			{Timestamp: from, FromBlock: 0, ToBlock: 100, Rates: ReserveRateEntry{BuyReserveRate: 100, SellReserveRate: 0.01}},
+			// estimated to end at from + 3h, half of it is in time range
+			{Timestamp: from.Add(time.Hour), FromBlock: 100, ToBlock: 300},
+		}
+	)
+
+	a := NewAvailability(ranges, from, to, 5)
+	assert.Equal(t, uint64(200), a.TotalBlocks)
+	assert.Equal(t, uint64(100), a.AvailableBlocks)
+	assert.InDelta(t, 50, a.BlockAvailability, delta)
+	assert.InDelta(t, 50, a.TimeAvailability, delta)
+	require.Len(t, a.LongestOutages, 1)
+	assert.Equal(t, Outage{
+		Start:     from.Add(time.Hour),
+		End:       to,
+		FromBlock: 100,
+		ToBlock:   300,
+		Ongoing:   true,
+	}, a.LongestOutages[0])
+}
+
+func TestAvailabilityReportWriteCSV(t *testing.T) {
+	var (
+		from   = time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+		to     = from.Add(time.Hour)
+		report = NewAvailabilityReport(map[string]map[string][]ReserveRates{
+			"0x63825c174ab367968EC60f061753D3bbD36A0D8F": {
+				"ETH-ZRX": {{Timestamp: from, FromBlock: 100, ToBlock: 300, Rates: ReserveRateEntry{}}},
+				"ETH-KNC": {{Timestamp: from, FromBlock: 100, ToBlock: 300,
+					Rates: ReserveRateEntry{BuyReserveRate: 100, SellReserveRate: 0.01}}},
+			},
+		}, from, to, 5)
+		buf bytes.Buffer
+	)
+	require.NoError(t, report.WriteCSV(&buf))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, availabilityCSVHeader, records[0])
+	assert.Equal(t, []string{
+		"0x63825c174ab367968EC60f061753D3bbD36A0D8F", "ETH-KNC", "200", "200", "100.00", "100.00",
+		"", "", "", "2020-06-01T01:00:00Z", "0",
+	}, records[1])
+	assert.Equal(t, []string{
+		"0x63825c174ab367968EC60f061753D3bbD36A0D8F", "ETH-ZRX", "200", "0", "0.00", "0.00",
+		"2020-06-01T00:00:00Z", "2020-06-01T01:00:00Z", "3600", "", "",
+	}, records[2])
+}
@@ -0,0 +1,68 @@
+package discovery
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/urfave/cli"
+	"go.uber.org/zap"
+
+	libapp "github.com/KyberNetwork/reserve-stats/lib/app"
+	"github.com/KyberNetwork/reserve-stats/lib/contracts"
+	"github.com/KyberNetwork/reserve-stats/lib/deployment"
+)
+
+const (
+	discoverReservesFlag = "discover-reserves"
+	fromBlockFlag        = "discovery-from-block"
+	reserveTableDBFlag   = "reserve-table-database"
+)
+
+// NewCliFlags returns flags to configure reserve discovery.
+func NewCliFlags() []cli.Flag {
+	return []cli.Flag{
+		cli.BoolFlag{
+			Name:   discoverReservesFlag,
+			Usage:  "follow reserve add/remove events of KyberStorage contract to crawl rates of network reserves",
+			EnvVar: "DISCOVER_RESERVES",
+		},
+		cli.Uint64Flag{
+			Name:   fromBlockFlag,
+			Usage:  "block to start looking for reserve events, default to KyberNetwork v4 starting block",
+			EnvVar: "DISCOVERY_FROM_BLOCK",
+		},
+		cli.StringFlag{
+			Name:   reserveTableDBFlag,
+			Usage:  "trade logs database to read reserve table from, using the same PostgreSQL connection flags. Disabled if empty",
+			EnvVar: "RESERVE_TABLE_DATABASE",
+		},
+	}
+}
+
+// NewTrackerFromContext creates a reserve tracker from cli flags, static addresses are always tracked.
+// It returns nil if reserve discovery is not enabled.
+func NewTrackerFromContext(c *cli.Context, sugar *zap.SugaredLogger, client bind.ContractFilterer, static []ethereum.Address) (*Tracker, error) {
+	if !c.Bool(discoverReservesFlag) {
+		return nil, nil
+	}
+
+	storageSource, err := NewKyberStorageSource(sugar, client, contracts.KyberStorageContractAddress().MustGetFromContext(c))
+	if err != nil {
+		return nil, err
+	}
+	sources := []Source{storageSource}
+
+	if dbName := c.String(reserveTableDBFlag); dbName != "" {
+		db, err := libapp.NewDBWithDatabaseFromContext(c, dbName)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, NewReserveTableSource(sugar, db))
+	}
+
+	fromBlock := c.Uint64(fromBlockFlag)
+	if fromBlock == 0 {
+		startingBlocks := deployment.MustGetStartingBlocksFromContext(c)
+		fromBlock = startingBlocks.V4()
+	}
+	return NewTracker(sugar, static, fromBlock, sources...), nil
+}
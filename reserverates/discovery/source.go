@@ -0,0 +1,140 @@
+package discovery
+
+import (
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/contracts"
+)
+
+// Event is a reserve added to or removed from network at a block.
+type Event struct {
+	Reserve ethereum.Address
+	Block   uint64
+	Removed bool
+}
+
+// Source returns reserve add/remove events in a block range, both ends are inclusive.
+type Source interface {
+	Events(fromBlock, toBlock uint64) ([]Event, error)
+}
+
+// KyberStorageSource reads AddReserveToStorage and RemoveReserveFromStorage events of KyberStorage contracts.
+type KyberStorageSource struct {
+	sugar     *zap.SugaredLogger
+	filterers []*contracts.KyberStorageFilterer
+}
+
+// NewKyberStorageSource creates a new KyberStorageSource instance for given KyberStorage contract addresses.
+func NewKyberStorageSource(sugar *zap.SugaredLogger, client bind.ContractFilterer, addrs []ethereum.Address) (*KyberStorageSource, error) {
+	var filterers []*contracts.KyberStorageFilterer
+	for _, addr := range addrs {
+		filterer, err := contracts.NewKyberStorageFilterer(addr, client)
+		if err != nil {
+			return nil, err
+		}
+		filterers = append(filterers, filterer)
+	}
+	return &KyberStorageSource{sugar: sugar, filterers: filterers}, nil
+}
+
+// Events returns reserve events emitted by KyberStorage contracts in given block range.
+func (s *KyberStorageSource) Events(fromBlock, toBlock uint64) ([]Event, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from_block", fromBlock,
+			"to_block", toBlock,
+		)
+		events []Event
+	)
+	for _, filterer := range s.filterers {
+		opts := &bind.FilterOpts{Start: fromBlock, End: &toBlock}
+
+		added, err := filterer.FilterAddReserveToStorage(opts, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		for added.Next() {
+			events = append(events, Event{
+				Reserve: added.Event.Reserve,
+				Block:   added.Event.Raw.BlockNumber,
+			})
+		}
+		if err = added.Error(); err != nil {
+			return nil, err
+		}
+		if err = added.Close(); err != nil {
+			return nil, err
+		}
+
+		removed, err := filterer.FilterRemoveReserveFromStorage(opts, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		for removed.Next() {
+			events = append(events, Event{
+				Reserve: removed.Event.Reserve,
+				Block:   removed.Event.Raw.BlockNumber,
+				Removed: true,
+			})
+		}
+		if err = removed.Error(); err != nil {
+			return nil, err
+		}
+		if err = removed.Close(); err != nil {
+			return nil, err
+		}
+	}
+	logger.Debugw("fetched reserve events from kyber storage", "events", len(events))
+	return events, nil
+}
+
+// ReserveTableSource reads reserves stored in reserve table of trade logs database by trade logs crawler.
+// The table only records reserve additions so it never returns removed events.
+type ReserveTableSource struct {
+	sugar *zap.SugaredLogger
+	db    *sqlx.DB
+}
+
+// NewReserveTableSource creates a new ReserveTableSource instance.
+func NewReserveTableSource(sugar *zap.SugaredLogger, db *sqlx.DB) *ReserveTableSource {
+	return &ReserveTableSource{sugar: sugar, db: db}
+}
+
+// Events returns the reserves whose first row of reserve table is in given block range. Later rows of a reserve,
+// e.g. rebate wallet updates, are not additions so they would re-add a removed reserve, they are ignored.
+func (s *ReserveTableSource) Events(fromBlock, toBlock uint64) ([]Event, error) {
+	var (
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from_block", fromBlock,
+			"to_block", toBlock,
+		)
+		records []struct {
+			Address     string `db:"address"`
+			BlockNumber uint64 `db:"block_number"`
+		}
+		events []Event
+	)
+	// block_number is 0 for reserves inserted from trades before KyberStorage existed,
+	// the block they were added is unknown so they are ignored.
+	const query = `SELECT address, MIN(block_number) AS block_number
+	FROM reserve
+	GROUP BY address
+	HAVING MIN(block_number) > 0 AND MIN(block_number) >= $1 AND MIN(block_number) <= $2`
+	logger.Debugw("querying reserves from reserve table", "query", query)
+	if err := s.db.Select(&records, query, fromBlock, toBlock); err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		events = append(events, Event{
+			Reserve: ethereum.HexToAddress(r.Address),
+			Block:   r.BlockNumber,
+		})
+	}
+	return events, nil
+}
@@ -0,0 +1,152 @@
+package discovery
+
+import (
+	"sort"
+	"sync"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/KyberNetwork/reserve-stats/lib/caller"
+)
+
+// maxBlockRange is the maximum number of blocks to query events from sources at once.
+const maxBlockRange = 100000
+
+// period is a block range that a reserve is tracked. to is exclusive, 0 means the reserve is still tracked.
+type period struct {
+	from uint64
+	to   uint64
+}
+
+func (p period) contains(block uint64) bool {
+	return block >= p.from && (p.to == 0 || block < p.to)
+}
+
+// Tracker keeps the set of reserves to crawl rates at each block. Reserves are discovered from sources,
+// a reserve is tracked from the block it is added until the block it is removed.
+type Tracker struct {
+	sugar   *zap.SugaredLogger
+	sources []Source
+	// static addresses are configured by user and always tracked.
+	static []ethereum.Address
+
+	mu          sync.RWMutex
+	periods     map[ethereum.Address][]period
+	syncedBlock uint64
+}
+
+// NewTracker creates a new Tracker instance. Events before fromBlock are not fetched.
+func NewTracker(sugar *zap.SugaredLogger, static []ethereum.Address, fromBlock uint64, sources ...Source) *Tracker {
+	var syncedBlock uint64
+	if fromBlock > 0 {
+		syncedBlock = fromBlock - 1
+	}
+	return &Tracker{
+		sugar:       sugar,
+		sources:     sources,
+		static:      static,
+		periods:     make(map[ethereum.Address][]period),
+		syncedBlock: syncedBlock,
+	}
+}
+
+// Sync fetches events from all sources up to given block (inclusive) and updates tracked reserves.
+func (t *Tracker) Sync(toBlock uint64) error {
+	var (
+		logger = t.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"to_block", toBlock,
+		)
+	)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for t.syncedBlock < toBlock {
+		var (
+			from   = t.syncedBlock + 1
+			to     = from + maxBlockRange - 1
+			events []Event
+		)
+		if to > toBlock {
+			to = toBlock
+		}
+		for _, source := range t.sources {
+			sourceEvents, err := source.Events(from, to)
+			if err != nil {
+				return err
+			}
+			events = append(events, sourceEvents...)
+		}
+		t.apply(logger, events)
+		t.syncedBlock = to
+	}
+	return nil
+}
+
+func (t *Tracker) apply(logger *zap.SugaredLogger, events []Event) {
+	// adding is applied before removing if both happen at the same block
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Block != events[j].Block {
+			return events[i].Block < events[j].Block
+		}
+		return !events[i].Removed && events[j].Removed
+	})
+
+	for _, event := range events {
+		var (
+			periods = t.periods[event.Reserve]
+			active  = len(periods) != 0 && periods[len(periods)-1].to == 0
+		)
+		switch {
+		case !event.Removed && !active:
+			logger.Infow("start tracking reserve", "reserve", event.Reserve.Hex(), "block", event.Block)
+			t.periods[event.Reserve] = append(periods, period{from: event.Block})
+		case event.Removed && active:
+			logger.Infow("stop tracking reserve", "reserve", event.Reserve.Hex(), "block", event.Block)
+			periods[len(periods)-1].to = event.Block
+		}
+	}
+}
+
+// SyncedBlock returns the latest block that events are synced.
+func (t *Tracker) SyncedBlock() uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.syncedBlock
+}
+
+// Addresses returns reserves that are tracked at given block.
+func (t *Tracker) Addresses(block uint64) []ethereum.Address {
+	var (
+		seen   = make(map[ethereum.Address]struct{})
+		result []ethereum.Address
+	)
+	for _, addr := range t.static {
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		seen[addr] = struct{}{}
+		result = append(result, addr)
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var discovered []ethereum.Address
+	for addr, periods := range t.periods {
+		if _, ok := seen[addr]; ok {
+			continue
+		}
+		for _, p := range periods {
+			if p.contains(block) {
+				discovered = append(discovered, addr)
+				break
+			}
+		}
+	}
+	// map iteration order is random, sort to have stable result
+	sort.Slice(discovered, func(i, j int) bool {
+		return discovered[i].Hex() < discovered[j].Hex()
+	})
+	return append(result, discovered...)
+}
@@ -0,0 +1,84 @@
+package discovery
+
+import (
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+)
+
+type mockSource struct {
+	events []Event
+	// queries records the block ranges requested
+	queries [][2]uint64
+}
+
+func (s *mockSource) Events(fromBlock, toBlock uint64) ([]Event, error) {
+	var result []Event
+	s.queries = append(s.queries, [2]uint64{fromBlock, toBlock})
+	for _, e := range s.events {
+		if e.Block >= fromBlock && e.Block <= toBlock {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func TestTracker(t *testing.T) {
+	var (
+		sugar    = testutil.MustNewDevelopmentSugaredLogger()
+		static   = ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		reserve1 = ethereum.HexToAddress("0x1111111111111111111111111111111111111111")
+		reserve2 = ethereum.HexToAddress("0x2222222222222222222222222222222222222222")
+		storage  = &mockSource{events: []Event{
+			{Reserve: reserve1, Block: 100},
+			{Reserve: reserve2, Block: 150},
+			{Reserve: reserve1, Block: 200, Removed: true},
+			{Reserve: reserve1, Block: 300},
+		}}
+		// reserve table has the same reserve added, it should not affect the tracked period
+		table = &mockSource{events: []Event{
+			{Reserve: reserve1, Block: 100},
+			{Reserve: static, Block: 120},
+		}}
+	)
+
+	tracker := NewTracker(sugar, []ethereum.Address{static}, 50, storage, table)
+	require.NoError(t, tracker.Sync(250))
+	assert.Equal(t, uint64(250), tracker.SyncedBlock())
+	assert.Equal(t, [][2]uint64{{50, 250}}, storage.queries)
+
+	assert.Equal(t, []ethereum.Address{static}, tracker.Addresses(99))
+	assert.Equal(t, []ethereum.Address{static, reserve1}, tracker.Addresses(100))
+	assert.Equal(t, []ethereum.Address{static, reserve1, reserve2}, tracker.Addresses(199))
+	assert.Equal(t, []ethereum.Address{static, reserve2}, tracker.Addresses(200))
+
+	// reserve is added back, it should only be tracked after syncing
+	assert.Equal(t, []ethereum.Address{static, reserve2}, tracker.Addresses(300))
+	require.NoError(t, tracker.Sync(300))
+	assert.Equal(t, [][2]uint64{{50, 250}, {251, 300}}, storage.queries)
+	assert.Equal(t, []ethereum.Address{static, reserve2}, tracker.Addresses(250))
+	assert.Equal(t, []ethereum.Address{static, reserve1, reserve2}, tracker.Addresses(300))
+
+	// already synced
+	require.NoError(t, tracker.Sync(300))
+	assert.Len(t, storage.queries, 2)
+}
+
+func TestTrackerSyncInChunks(t *testing.T) {
+	var (
+		sugar  = testutil.MustNewDevelopmentSugaredLogger()
+		source = &mockSource{}
+	)
+	tracker := NewTracker(sugar, nil, 1, source)
+	require.NoError(t, tracker.Sync(maxBlockRange*2+10))
+	assert.Equal(t, [][2]uint64{
+		{1, maxBlockRange},
+		{maxBlockRange + 1, maxBlockRange * 2},
+		{maxBlockRange*2 + 1, maxBlockRange*2 + 10},
+	}, source.queries)
+	assert.Empty(t, tracker.Addresses(10))
+}
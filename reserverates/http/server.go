@@ -1,7 +1,9 @@
 package http
 
 import (
+	"fmt"
 	"net/http"
+	"time"
 
 	ethereum "github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
@@ -10,18 +12,27 @@ import (
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
 	_ "github.com/KyberNetwork/reserve-stats/lib/httputil/validators" // import custom validator functions
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 	"github.com/KyberNetwork/reserve-stats/reserverates/common"
 	"github.com/KyberNetwork/reserve-stats/reserverates/storage"
 )
 
 // Server is the engine to serve reserve-rate API query
 type Server struct {
-	r     *gin.Engine
-	db    storage.ReserveRatesStorage
-	host  string
-	sugar *zap.SugaredLogger
+	r              *gin.Engine
+	db             storage.ReserveRatesStorage
+	host           string
+	sugar          *zap.SugaredLogger
+	openAPIOptions []openapi.Option
 }
 
+const (
+	availabilityMaxTimeFrame     = time.Hour * 24 * 90 // 90 days
+	availabilityDefaultTimeFrame = time.Hour * 24      // 1 day
+	defaultMaxOutages            = 5
+	formatCSV                    = "csv"
+)
+
 type reserveRatesQuery struct {
 	httputil.TimeRangeQuery
 	ReserveAddrs []string `form:"reserve"`
@@ -56,6 +67,10 @@ func (sv *Server) reserveRates(c *gin.Context) {
 	for _, rsvAddr := range query.ReserveAddrs {
 		rsvAddrs = append(rsvAddrs, ethereum.HexToAddress(rsvAddr))
 	}
+	if rsvAddrs, err = httputil.ScopedReserves(c.Request, rsvAddrs); err != nil {
+		httputil.ResponseFailure(c, http.StatusForbidden, err)
+		return
+	}
 	result, err := sv.db.GetRatesByTimePoint(rsvAddrs, query.From, query.To)
 	if err != nil {
 		sv.sugar.Errorw(err.Error(), "query", query)
@@ -73,8 +88,83 @@ func (sv *Server) reserveRates(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+type reserveAvailabilityQuery struct {
+	httputil.TimeRangeQuery
+	ReserveAddrs []string `form:"reserve"`
+	Outages      *int     `form:"outages" binding:"omitempty,min=0"`
+	Format       string   `form:"format" binding:"omitempty,oneof=json csv"`
+}
+
+func (sv *Server) reserveAvailability(c *gin.Context) {
+	var (
+		query      reserveAvailabilityQuery
+		logger     = sv.sugar.With("func", caller.GetCurrentFunctionName())
+		rsvAddrs   []ethereum.Address
+		maxOutages = defaultMaxOutages
+	)
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	from, to, err := query.Validate(
+		httputil.TimeRangeQueryWithMaxTimeFrame(availabilityMaxTimeFrame),
+		httputil.TimeRangeQueryWithDefaultTimeFrame(availabilityDefaultTimeFrame),
+	)
+	if err != nil {
+		httputil.ResponseFailure(c, http.StatusBadRequest, err)
+		return
+	}
+	for _, rsvAddr := range query.ReserveAddrs {
+		if !ethereum.IsHexAddress(rsvAddr) {
+			httputil.ResponseFailure(c, http.StatusBadRequest, fmt.Errorf("invalid reserve address %s", rsvAddr))
+			return
+		}
+		rsvAddrs = append(rsvAddrs, ethereum.HexToAddress(rsvAddr))
+	}
+	if rsvAddrs, err = httputil.ScopedReserves(c.Request, rsvAddrs); err != nil {
+		httputil.ResponseFailure(c, http.StatusForbidden, err)
+		return
+	}
+	if query.Outages != nil {
+		maxOutages = *query.Outages
+	}
+
+	logger = logger.With("from", from, "to", to, "reserves", query.ReserveAddrs)
+	logger.Debug("querying reserve rate ranges from database")
+	ranges, err := sv.db.GetRateRanges(rsvAddrs, query.From, query.To)
+	if err != nil {
+		logger.Errorw("failed to get rate ranges", "error", err)
+		httputil.ResponseFailure(c, http.StatusInternalServerError, err)
+		return
+	}
+	report := common.NewAvailabilityReport(ranges, from, to, maxOutages)
+
+	if query.Format != formatCSV {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	c.Header("Content-Type", "text/csv")
+	c.Header("Content-Disposition",
+		fmt.Sprintf("attachment; filename=reserve-availability-%d-%d.csv", query.From, query.To))
+	c.Status(http.StatusOK)
+	if err = report.WriteCSV(c.Writer); err != nil {
+		logger.Errorw("failed to write csv report", "error", err)
+	}
+}
+
 func (sv *Server) register() {
-	sv.r.GET("/reserve-rates", sv.reserveRates)
+	api := openapi.NewRouter(sv.r, openapi.NewSpec("reserve rates"), sv.openAPIOptions...)
+	api.GET("/reserve-rates", openapi.Endpoint{
+		Summary:  "rates of reserves by block, keyed by reserve and token pair",
+		Query:    reserveRatesQuery{},
+		Response: map[string]map[string][]common.ReserveRates{},
+	}, sv.reserveRates)
+	api.GET("/reserve-availability", openapi.Endpoint{
+		Summary:  "availability report of reserves, csv is returned with format=csv",
+		Query:    reserveAvailabilityQuery{},
+		Response: common.AvailabilityReport{},
+	}, sv.reserveAvailability)
 }
 
 // Run starts HTTP server on preconfigure-host. Return error if occurs
@@ -84,12 +174,13 @@ func (sv *Server) Run() error {
 }
 
 // NewServer create an instance of Server to serve API query
-func NewServer(host string, db storage.ReserveRatesStorage, sugar *zap.SugaredLogger) (*Server, error) {
+func NewServer(host string, db storage.ReserveRatesStorage, sugar *zap.SugaredLogger, openAPIOptions ...openapi.Option) (*Server, error) {
 	r := gin.Default()
 	return &Server{
-		r:     r,
-		db:    db,
-		host:  host,
-		sugar: sugar,
+		r:              r,
+		db:             db,
+		host:           host,
+		sugar:          sugar,
+		openAPIOptions: openAPIOptions,
 	}, nil
 }
@@ -9,5 +9,8 @@ import (
 type ReserveRatesStorage interface {
 	UpdateRatesRecords(uint64, map[string]map[string]common.ReserveRateEntry) error
 	GetRatesByTimePoint(addrs []ethereum.Address, fromTime, toTime uint64) (map[string]map[string][]common.ReserveRates, error)
+	// GetRateRanges returns the stored block ranges of given reserves, or all reserves if empty, that are active
+	// in the time range, including the range that is active at fromTime.
+	GetRateRanges(addrs []ethereum.Address, fromTime, toTime uint64) (map[string]map[string][]common.ReserveRates, error)
 	LastBlock() (int64, error)
 }
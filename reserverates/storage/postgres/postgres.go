@@ -12,6 +12,7 @@ import (
 
 	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
 	"github.com/KyberNetwork/reserve-stats/reserverates/common"
 )
 
@@ -253,6 +254,55 @@ func (s *Storage) GetRatesByTimePoint(addrs []ethereum.Address, fromTime, toTime
 	return result, nil
 }
 
+// GetRateRanges returns block ranges of given reserves, or all reserves if addrs is empty, that started in
+// the time range along with the last range of each pair started before fromTime.
+func (s *Storage) GetRateRanges(addrs []ethereum.Address, fromTime, toTime uint64) (map[string]map[string][]common.ReserveRates, error) {
+	var (
+		result = make(map[string]map[string][]common.ReserveRates)
+		logger = s.sugar.With(
+			"func", caller.GetCurrentFunctionName(),
+			"from", fromTime,
+			"to", toTime,
+		)
+		reserves     []string
+		rateResponse []ratesQueryResponse
+	)
+	for _, addr := range addrs {
+		reserves = append(reserves, addr.Hex())
+	}
+	logger = logger.With("reserves", reserves)
+	const query = `SELECT * FROM reserve_rates
+	WHERE timestamp >= $1 AND timestamp <= $2 AND (cardinality($3::TEXT[]) = 0 OR reserve = ANY($3::TEXT[]))
+	UNION ALL
+	(SELECT DISTINCT ON (reserve, pair) * FROM reserve_rates
+	WHERE timestamp < $1 AND (cardinality($3::TEXT[]) = 0 OR reserve = ANY($3::TEXT[]))
+	ORDER BY reserve, pair, from_block DESC);`
+	logger.Infow("get rate ranges", "query", query)
+	if err := s.db.Select(&rateResponse, query,
+		timeutil.TimestampMsToTime(fromTime), timeutil.TimestampMsToTime(toTime), pq.StringArray(reserves)); err != nil {
+		return nil, err
+	}
+	for _, rate := range rateResponse {
+		pairs, ok := result[rate.Reserve]
+		if !ok {
+			pairs = make(map[string][]common.ReserveRates)
+			result[rate.Reserve] = pairs
+		}
+		pairs[rate.Pair] = append(pairs[rate.Pair], common.ReserveRates{
+			Timestamp: rate.Timestamp,
+			FromBlock: rate.FromBlock,
+			ToBlock:   rate.ToBlock,
+			Rates: common.ReserveRateEntry{
+				BuyReserveRate:  rate.BuyRate,
+				SellReserveRate: rate.SellRate,
+				BuySanityRate:   rate.BuySanityRate,
+				SellSanityRate:  rate.SellSanityRate,
+			},
+		})
+	}
+	return result, nil
+}
+
 // LastBlock return last block saved in db
 func (s *Storage) LastBlock() (int64, error) {
 	var (
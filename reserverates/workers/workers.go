@@ -11,6 +11,7 @@ import (
 
 	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
+	"github.com/KyberNetwork/reserve-stats/reserverates/alert"
 	"github.com/KyberNetwork/reserve-stats/reserverates/common"
 	"github.com/KyberNetwork/reserve-stats/reserverates/crawler"
 	"github.com/KyberNetwork/reserve-stats/reserverates/storage"
@@ -107,10 +108,33 @@ type Pool struct {
 	failed                bool // mark as failed, all subsequent persistent storage will be passed
 
 	rateStorage storage.ReserveRatesStorage
+	// alertEngine is optional, rates are checked against alert rules after saved to storage.
+	alertEngine *alert.Engine
+	// alertCh queues detected alerts to be delivered outside of mutex, in block order.
+	alertCh   chan alertBatch
+	alertDone chan struct{}
+}
+
+// alertQueueSize is the number of blocks with alerts that can wait for delivery.
+const alertQueueSize = 100
+
+type alertBatch struct {
+	block  uint64
+	alerts []alert.Alert
+}
+
+// PoolOption is option to Pool constructor.
+type PoolOption func(*Pool)
+
+// WithAlertEngine configures the pool to check saved rates with given alert engine.
+func WithAlertEngine(engine *alert.Engine) PoolOption {
+	return func(p *Pool) {
+		p.alertEngine = engine
+	}
 }
 
 // NewPool returns a pool of workers
-func NewPool(sugar *zap.SugaredLogger, maxWorkers int, rateStorage storage.ReserveRatesStorage) *Pool {
+func NewPool(sugar *zap.SugaredLogger, maxWorkers int, rateStorage storage.ReserveRatesStorage, options ...PoolOption) *Pool {
 	var pool = &Pool{
 		sugar:                 sugar,
 		jobCh:                 make(chan job),
@@ -119,6 +143,14 @@ func NewPool(sugar *zap.SugaredLogger, maxWorkers int, rateStorage storage.Reser
 		lastCompletedJobOrder: 0,
 		rateStorage:           rateStorage,
 	}
+	for _, option := range options {
+		option(pool)
+	}
+	if pool.alertEngine != nil {
+		pool.alertCh = make(chan alertBatch, alertQueueSize)
+		pool.alertDone = make(chan struct{})
+		go pool.deliverAlerts()
+	}
 
 	pool.wg.Add(maxWorkers)
 	for i := 0; i < maxWorkers; i++ {
@@ -158,6 +190,30 @@ func NewPool(sugar *zap.SugaredLogger, maxWorkers int, rateStorage storage.Reser
 	return pool
 }
 
+// queueAlerts puts alerts to delivery queue without blocking, alerts are dropped if the queue is full
+// as a slow sink should not stop saving rates.
+func (p *Pool) queueAlerts(block uint64, alerts []alert.Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+	select {
+	case p.alertCh <- alertBatch{block: block, alerts: alerts}:
+	default:
+		p.sugar.Errorw("alert delivery queue is full, dropping alerts",
+			"func", caller.GetCurrentFunctionName(),
+			"block", block,
+			"alerts", len(alerts))
+	}
+}
+
+// deliverAlerts sends queued alerts to sinks of alert engine until alertCh is closed.
+func (p *Pool) deliverAlerts() {
+	defer close(p.alertDone)
+	for batch := range p.alertCh {
+		p.alertEngine.Send(batch.block, batch.alerts)
+	}
+}
+
 func (p *Pool) markAsFailed(order int) {
 	var (
 		logger = p.sugar.With(
@@ -209,6 +265,11 @@ func (p *Pool) serialSaveTradeLogs( // TODO: rename this as it is rates record n
 				return err
 			}
 
+			// jobs are saved in order, so alert engine always receives rates in block order
+			// alerts are only queued here and delivered by deliverAlerts after the lock is released.
+			if p.alertEngine != nil {
+				p.queueAlerts(blockNumber, p.alertEngine.Check(blockNumber, rates))
+			}
 			p.lastCompletedJobOrder++
 			logger.Infow("save rates to storage success")
 			p.mutex.Unlock()
@@ -247,6 +308,10 @@ func (p *Pool) Shutdown() {
 	close(p.jobCh)
 	p.wg.Wait()
 	close(p.errCh)
+	if p.alertCh != nil {
+		close(p.alertCh)
+		<-p.alertDone
+	}
 }
 
 // ErrCh returns error reporting channel of workers pool.
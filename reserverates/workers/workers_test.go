@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/KyberNetwork/reserve-stats/lib/testutil"
+	"github.com/KyberNetwork/reserve-stats/reserverates/alert"
 	"github.com/KyberNetwork/reserve-stats/reserverates/common"
 )
 
@@ -41,6 +42,10 @@ func (s *mockStorage) GetRatesByTimePoint(addrs []ethereum.Address, fromTime, to
 	return nil, nil
 }
 
+func (s *mockStorage) GetRateRanges(addrs []ethereum.Address, fromTime, toTime uint64) (map[string]map[string][]common.ReserveRates, error) {
+	return nil, nil
+}
+
 func (s *mockStorage) LastBlock() (int64, error) {
 	return 0, nil
 }
@@ -48,17 +53,18 @@ func (s *mockStorage) LastBlock() (int64, error) {
 type mockJob struct {
 	order   int
 	failure bool
+	rates   map[string]map[string]common.ReserveRateEntry
 }
 
 func (j *mockJob) execute(sugar *zap.SugaredLogger) (map[string]map[string]common.ReserveRateEntry, error) {
 	if j.failure {
 		return nil, fmt.Errorf("failed to execute job %d", j.order)
 	}
-	return nil, nil
+	return j.rates, nil
 }
 
 func (j *mockJob) info() (order int, block uint64) {
-	return j.order, uint64(0)
+	return j.order, uint64(j.order)
 }
 
 func newTestWorkerPool(maxWorkers int) *Pool {
@@ -144,3 +150,48 @@ func TestWorkerPoolEncounterErr(t *testing.T) {
 		assert.True(t, ms.Counter() < 2, "no job with order > 2 should trigger database saving")
 	})
 }
+
+// blockingSink blocks every Send until released, like an unresponsive webhook.
+type blockingSink struct {
+	release chan struct{}
+	sent    chan []alert.Alert
+}
+
+func (s *blockingSink) Name() string { return "blocking" }
+
+func (s *blockingSink) Send(alerts []alert.Alert) error {
+	<-s.release
+	s.sent <- alerts
+	return nil
+}
+
+func TestWorkersPoolDeliverAlertsOutsideLock(t *testing.T) {
+	var (
+		sugar = testutil.MustNewDevelopmentSugaredLogger()
+		sink  = &blockingSink{release: make(chan struct{}), sent: make(chan []alert.Alert, 10)}
+		zero  = map[string]map[string]common.ReserveRateEntry{
+			"0x63825c174ab367968EC60f061753D3bbD36A0D8F": {"ETH-KNC": {}},
+		}
+		engine = alert.NewEngine(sugar, []alert.Rule{alert.NewZeroRateRule(1)}, alert.WithSinks(sink))
+		pool   = NewPool(sugar, 2, newMockStorage(), WithAlertEngine(engine))
+		jobs   = []job{&mockJob{order: 1, rates: zero}, &mockJob{order: 2, rates: zero}}
+		doneCh = make(chan struct{})
+	)
+
+	// saving rates must not wait for the sink, which is still blocked
+	sendJobsToWorkerPool(pool, jobs, doneCh)
+	select {
+	case <-doneCh:
+	case <-time.After(10 * time.Second):
+		t.Fatal("saving rates is blocked by alert delivery")
+	}
+	assert.Equal(t, 2, pool.GetLastCompleteJobOrder())
+
+	close(sink.release)
+	pool.Shutdown()
+	require.Len(t, sink.sent, 1)
+	alerts := <-sink.sent
+	require.Len(t, alerts, 1)
+	assert.Equal(t, alert.ZeroRate, alerts[0].Rule)
+	assert.Equal(t, uint64(1), alerts[0].Block)
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/KyberNetwork/httpsign-utils/sign"
 	"github.com/KyberNetwork/reserve-stats/tradelogs/common"
+	ethereum "github.com/ethereum/go-ethereum/common"
 	"go.uber.org/zap"
 )
 
@@ -49,24 +52,41 @@ func NewTradeLogClient(sugar *zap.SugaredLogger, host string, options ...TradeLo
 
 // GetTradeLogs get trade logs from `fromTime` to `toTime`
 func (c *Client) GetTradeLogs(fromTime, toTime uint64) ([]common.TradeLog, error) {
+	var tradeLogs []common.TradeLog
+	err := c.get(fmt.Sprintf("%s/trade-logs?from=%d&to=%d", c.host, fromTime, toTime), &tradeLogs)
+	return tradeLogs, err
+}
+
+// GetReserveTradeLogs get trade logs from `fromTime` to `toTime` routed through the reserves, only the splits
+// and fees of the reserves are returned.
+func (c *Client) GetReserveTradeLogs(fromTime, toTime uint64, reserves []ethereum.Address) ([]common.TradelogV4, error) {
 	var (
-		tradeLogs []common.TradeLog
-		url       = fmt.Sprintf("%s/trade-logs?from=%d&to=%d", c.host, fromTime, toTime)
+		tradeLogs []common.TradelogV4
+		query     = url.Values{}
 	)
+	query.Set("from", strconv.FormatUint(fromTime, 10))
+	query.Set("to", strconv.FormatUint(toTime, 10))
+	for _, reserve := range reserves {
+		query.Add("reserve", reserve.Hex())
+	}
+	err := c.get(fmt.Sprintf("%s/trade-logs?%s", c.host, query.Encode()), &tradeLogs)
+	return tradeLogs, err
+}
 
+func (c *Client) get(url string, result interface{}) error {
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return tradeLogs, err
+		return err
 	}
 	if c.accessKeyID != "" && c.secretAccessKey != "" {
 		req, err = sign.Sign(req, c.accessKeyID, c.secretAccessKey)
 		if err != nil {
-			return tradeLogs, err
+			return err
 		}
 	}
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return tradeLogs, err
+		return err
 	}
 	defer func() {
 		if cErr := resp.Body.Close(); cErr != nil {
@@ -75,11 +95,7 @@ func (c *Client) GetTradeLogs(fromTime, toTime uint64) ([]common.TradeLog, error
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return tradeLogs, fmt.Errorf("unexpcted status code: %d", resp.StatusCode)
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&tradeLogs); err != nil {
-		return tradeLogs, err
+		return fmt.Errorf("unexpcted status code: %d", resp.StatusCode)
 	}
-	return tradeLogs, nil
+	return json.NewDecoder(resp.Body).Decode(result)
 }
@@ -77,3 +77,43 @@ func TestValidGetTradeLog(t *testing.T) {
 		t.Error("Get invalid eth amount", "result", l.EthAmount, "expected", new(big.Int))
 	}
 }
+
+func TestGetReserveTradeLogs(t *testing.T) {
+	var log = []*common.TradelogV4{
+		{
+			Split: []common.TradeSplit{
+				{ReserveAddress: ethereum.HexToAddress(receiverAddress), SrcAmount: big.NewInt(10)},
+			},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		js, err := json.Marshal(log)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		expected := fmt.Sprintf("/trade-logs?from=%d&reserve=%s&to=%d", fromTime,
+			ethereum.HexToAddress(receiverAddress).Hex(), toTime)
+		if req.URL.String() != expected {
+			t.Error("Request to wrong endpoint", "result", req.URL.String(), "expected", expected)
+		}
+		if _, err := rw.Write(js); err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}))
+	defer server.Close()
+
+	tl := newTestTradeLog(server)
+	tradeLogs, err := tl.GetReserveTradeLogs(fromTime, toTime, []ethereum.Address{ethereum.HexToAddress(receiverAddress)})
+	if err != nil {
+		t.Error("Could not get trade logs", "err", err)
+	}
+	if len(tradeLogs) != 1 || len(tradeLogs[0].Split) != 1 {
+		t.Fatal("trade log with split expected", "result", tradeLogs)
+	}
+	if tradeLogs[0].Split[0].SrcAmount.Cmp(big.NewInt(10)) != 0 {
+		t.Error("Get invalid split amount", "result", tradeLogs[0].Split[0].SrcAmount)
+	}
+}
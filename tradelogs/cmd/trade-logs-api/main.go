@@ -10,6 +10,7 @@ import (
 	"github.com/KyberNetwork/reserve-stats/lib/appnames"
 	"github.com/KyberNetwork/reserve-stats/lib/blockchain"
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 	"github.com/KyberNetwork/reserve-stats/lib/userprofile"
 	"github.com/KyberNetwork/reserve-stats/tradelogs/http"
 	"github.com/KyberNetwork/reserve-stats/tradelogs/storage"
@@ -65,6 +66,7 @@ func main() {
 			return err
 		}
 
+		options = append(options, http.WithOpenAPIOptions(openapi.NewOptionsFromContext(c)...))
 		api := http.NewServer(storageInterface, httputil.NewHTTPAddressFromContext(c),
 			sugar, symbolResolver, options...)
 		err = api.Start()
@@ -80,6 +82,7 @@ func main() {
 	app.Flags = append(app.Flags, blockchain.NewEthereumNodeFlags())
 	app.Flags = append(app.Flags, appnames.NewCliFlags()...)
 	app.Flags = append(app.Flags, userprofile.NewCliFlags()...)
+	app.Flags = append(app.Flags, openapi.NewCliFlags()...)
 
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
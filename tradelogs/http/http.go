@@ -2,6 +2,7 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -16,8 +17,10 @@ import (
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
 	libhttputil "github.com/KyberNetwork/reserve-stats/lib/httputil"
 	_ "github.com/KyberNetwork/reserve-stats/lib/httputil/validators" // import custom validator functions
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 	"github.com/KyberNetwork/reserve-stats/lib/timeutil"
 	"github.com/KyberNetwork/reserve-stats/lib/userprofile"
+	"github.com/KyberNetwork/reserve-stats/tradelogs/common"
 	"github.com/KyberNetwork/reserve-stats/tradelogs/storage"
 )
 
@@ -29,6 +32,7 @@ type Server struct {
 	getAddrToAppName func() (map[ethereum.Address]string, error)
 	getUserProfile   func(ethereum.Address) (userprofile.UserProfile, error)
 	symbolResolver   blockchain.TokenSymbolResolver
+	openAPIOptions   []openapi.Option
 }
 
 // NewServer returns an instance of HttpApi to serve trade logs.
@@ -74,6 +78,13 @@ func WithApplicationNames(an lipappnames.AddrToAppName) ServerOption {
 	}
 }
 
+// WithOpenAPIOptions configures the router serving the OpenAPI document.
+func WithOpenAPIOptions(options ...openapi.Option) ServerOption {
+	return func(sv *Server) {
+		sv.openAPIOptions = options
+	}
+}
+
 // WithUserProfile configures the Server instance to use user profile lookup
 func WithUserProfile(up userprofile.Interface) ServerOption {
 	return func(sv *Server) {
@@ -89,8 +100,32 @@ func (sv *Server) getTokenSymbol(tokenAddress ethereum.Address) (string, error)
 	return symbol, nil
 }
 
+// scopedReserves validates the requested reserve addresses and restricts them to the reserve scope of
+// API key. The failure response is written if it returns false.
+func scopedReserves(c *gin.Context, requested []string) ([]ethereum.Address, bool) {
+	var reserves []ethereum.Address
+	for _, addr := range requested {
+		if !ethereum.IsHexAddress(addr) {
+			libhttputil.ResponseFailure(c, http.StatusBadRequest, fmt.Errorf("invalid reserve address %s", addr))
+			return nil, false
+		}
+		reserves = append(reserves, ethereum.HexToAddress(addr))
+	}
+	reserves, err := libhttputil.ScopedReserves(c.Request, reserves)
+	if err != nil {
+		libhttputil.ResponseFailure(c, http.StatusForbidden, err)
+		return nil, false
+	}
+	return reserves, true
+}
+
+type tradeLogsQuery struct {
+	libhttputil.TimeRangeQuery
+	Reserves []string `form:"reserve"`
+}
+
 func (sv *Server) getTradeLogs(c *gin.Context) {
-	var query libhttputil.TimeRangeQuery
+	var query tradeLogsQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
 		libhttputil.ResponseFailure(
 			c,
@@ -105,8 +140,12 @@ func (sv *Server) getTradeLogs(c *gin.Context) {
 		libhttputil.ResponseFailure(c, http.StatusBadRequest, err)
 		return
 	}
+	reserves, ok := scopedReserves(c, query.Reserves)
+	if !ok {
+		return
+	}
 
-	tradeLogs, err := sv.storage.LoadTradeLogs(fromTime, toTime)
+	tradeLogs, err := sv.storage.LoadTradeLogs(fromTime, toTime, reserves)
 	if err != nil {
 		sv.sugar.Errorw(err.Error(), "fromTime", fromTime, "toTime", toTime)
 		libhttputil.ResponseFailure(
@@ -185,6 +224,10 @@ type getSymbolRequest struct {
 	Address string `form:"address" binding:"required"`
 }
 
+type symbolResponse struct {
+	Symbol string `json:"symbol"`
+}
+
 func (sv *Server) getSymbol(c *gin.Context) {
 	var query getSymbolRequest
 	if err := c.ShouldBindQuery(&query); err != nil {
@@ -206,9 +249,7 @@ func (sv *Server) getSymbol(c *gin.Context) {
 
 	c.JSON(
 		http.StatusOK,
-		gin.H{
-			"symbol": symbol,
-		},
+		symbolResponse{Symbol: symbol},
 	)
 }
 
@@ -288,8 +329,14 @@ type getReportRequest struct {
 	Limit uint64 `form:"limit"`
 }
 
+// getReserveReportRequest is the request of reports could be filtered by reserves.
+type getReserveReportRequest struct {
+	getReportRequest
+	Reserves []string `form:"reserve"`
+}
+
 func (sv *Server) getStats(c *gin.Context) {
-	var query getReportRequest
+	var query getReserveReportRequest
 	if err := c.ShouldBindQuery(&query); err != nil {
 		libhttputil.ResponseFailure(c, http.StatusBadRequest, err)
 		return
@@ -299,7 +346,11 @@ func (sv *Server) getStats(c *gin.Context) {
 		libhttputil.ResponseFailure(c, http.StatusBadRequest, err)
 		return
 	}
-	stats, err := sv.storage.GetStats(from, to)
+	reserves, ok := scopedReserves(c, query.Reserves)
+	if !ok {
+		return
+	}
+	stats, err := sv.storage.GetStats(from, to, reserves)
 	if err != nil {
 		libhttputil.ResponseFailure(c, http.StatusInternalServerError, err)
 		return
@@ -356,7 +407,7 @@ func (sv *Server) getTopIntegration(c *gin.Context) {
 }
 
 func (sv *Server) getTopReserves(c *gin.Context) {
-	var query getReportRequest
+	var query getReserveReportRequest
 	if err := c.ShouldBindQuery(&query); err != nil {
 		libhttputil.ResponseFailure(c, http.StatusBadRequest, err)
 		return
@@ -366,7 +417,11 @@ func (sv *Server) getTopReserves(c *gin.Context) {
 		libhttputil.ResponseFailure(c, http.StatusBadRequest, err)
 		return
 	}
-	topReserves, err := sv.storage.GetTopReserves(from, to, query.Limit)
+	reserves, ok := scopedReserves(c, query.Reserves)
+	if !ok {
+		return
+	}
+	topReserves, err := sv.storage.GetTopReserves(from, to, query.Limit, reserves)
 	if err != nil {
 		libhttputil.ResponseFailure(c, http.StatusInternalServerError, err)
 		return
@@ -457,22 +512,65 @@ func (sv *Server) getTokenInfo(c *gin.Context) {
 
 func (sv *Server) setupRouter() *gin.Engine {
 	r := gin.Default()
-	r.GET("/trade-logs", sv.getTradeLogs)
-	r.GET("/trade-logs/:tx_hash", sv.getTradeLogsByTx)
-	r.GET("/token-info", sv.getTokenInfo)
+	api := openapi.NewRouter(r, openapi.NewSpec("trade logs"), sv.openAPIOptions...)
+	api.GET("/trade-logs", openapi.Endpoint{
+		Summary:  "trade logs in time range",
+		Query:    tradeLogsQuery{},
+		Response: []common.TradelogV4{},
+	}, sv.getTradeLogs)
+	// endpoints not filtered by reserve reject requests of reserve bound keys
+	api.GET("/trade-logs/:tx_hash", openapi.Endpoint{
+		Summary:  "trade logs of transaction",
+		URI:      tradeLogsByTxHashParam{},
+		Response: []common.TradelogV4{},
+	}, libhttputil.RejectReserveScope, sv.getTradeLogsByTx)
+	api.GET("/token-info", openapi.Endpoint{
+		Summary:  "traded tokens",
+		Response: []common.TokenInfo{},
+	}, sv.getTokenInfo)
 
 	// token symbol
-	r.GET("/symbol", sv.getSymbol)
-	r.POST("/symbol", sv.updateSymbol)
+	api.GET("/symbol", openapi.Endpoint{
+		Summary:  "symbol of token",
+		Query:    getSymbolRequest{},
+		Response: symbolResponse{},
+	}, sv.getSymbol)
+	api.POST("/symbol", openapi.Endpoint{
+		Summary: "update symbols of tokens",
+		Body:    updateSymbolRequest{},
+	}, sv.updateSymbol)
 
 	// twitter api
-	r.GET("/stats", sv.getStats)
-	r.GET("/top-tokens", sv.getTopTokens)
-	r.GET("/top-integrations", sv.getTopIntegration)
-	r.GET("/top-reserves", sv.getTopReserves)
-
-	r.GET("/big-trades", sv.getBigTrades)
-	r.PUT("/big-trades", sv.updateBigTradesTwitted)
+	api.GET("/stats", openapi.Endpoint{
+		Summary:  "trading stats",
+		Query:    getReserveReportRequest{},
+		Response: common.StatsResponse{},
+	}, sv.getStats)
+	api.GET("/top-tokens", openapi.Endpoint{
+		Summary:  "top traded tokens",
+		Query:    getReportRequest{},
+		Response: common.TopTokens{},
+	}, libhttputil.RejectReserveScope, sv.getTopTokens)
+	api.GET("/top-integrations", openapi.Endpoint{
+		Summary:  "top integrations",
+		Query:    getReportRequest{},
+		Response: common.TopIntegrations{},
+	}, libhttputil.RejectReserveScope, sv.getTopIntegration)
+	api.GET("/top-reserves", openapi.Endpoint{
+		Summary:  "top reserves",
+		Query:    getReserveReportRequest{},
+		Response: common.TopReserves{},
+	}, sv.getTopReserves)
+
+	api.GET("/big-trades", openapi.Endpoint{
+		Summary:  "big trades not twitted yet",
+		Query:    bigTradesQuery{},
+		Response: []common.BigTradeLog{},
+	}, libhttputil.RejectReserveScope, sv.getBigTrades)
+	api.PUT("/big-trades", openapi.Endpoint{
+		Summary: "mark big trades as twitted",
+		Body:    updateBigTradesTwittedRequest{},
+	}, libhttputil.RejectReserveScope, sv.updateBigTradesTwitted)
 
 	return r
 }
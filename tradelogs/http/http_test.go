@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	ethereum "github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/KyberNetwork/reserve-stats/lib/httputil"
+	"github.com/KyberNetwork/reserve-stats/lib/openapi"
 	"github.com/KyberNetwork/reserve-stats/lib/testutil"
 	"github.com/KyberNetwork/reserve-stats/tradelogs/common"
 )
@@ -21,6 +24,8 @@ func (s *mockStorage) GetIntegrationVolume(fromTime, toTime time.Time) (map[uint
 }
 
 type mockStorage struct {
+	// reserves is the reserve filter of last query
+	reserves []ethereum.Address
 }
 
 func (s *mockStorage) GetTokenSymbol(address string) (string, error) {
@@ -38,7 +43,8 @@ func (s *mockStorage) SaveTradeLogs(logs *common.CrawlResult) error {
 	return nil
 }
 
-func (s *mockStorage) LoadTradeLogs(from, to time.Time) ([]common.TradelogV4, error) {
+func (s *mockStorage) LoadTradeLogs(from, to time.Time, reserves []ethereum.Address) ([]common.TradelogV4, error) {
+	s.reserves = reserves
 	return nil, nil
 }
 
@@ -46,7 +52,8 @@ func (s *mockStorage) LoadTradeLogsByTxHash(txHash ethereum.Hash) ([]common.Trad
 	return nil, nil
 }
 
-func (s *mockStorage) GetStats(from, to time.Time) (common.StatsResponse, error) {
+func (s *mockStorage) GetStats(from, to time.Time, reserves []ethereum.Address) (common.StatsResponse, error) {
+	s.reserves = reserves
 	return common.StatsResponse{}, nil
 }
 
@@ -58,7 +65,8 @@ func (s *mockStorage) GetTopIntegrations(from, to time.Time, limit uint64) (comm
 	return common.TopIntegrations{}, nil
 }
 
-func (s *mockStorage) GetTopReserves(from, to time.Time, limit uint64) (common.TopReserves, error) {
+func (s *mockStorage) GetTopReserves(from, to time.Time, limit uint64, reserves []ethereum.Address) (common.TopReserves, error) {
+	s.reserves = reserves
 	return common.TopReserves{}, nil
 }
 
@@ -127,6 +135,88 @@ func TestTradeLogsRoute(t *testing.T) {
 				assert.Contains(t, result.Error, "max time frame exceed")
 			},
 		},
+		{
+			Msg:      "Test OpenAPI document",
+			Endpoint: openapi.DocumentPath,
+			Method:   http.MethodGet,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+
+				var doc openapi.Document
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+				require.Contains(t, doc.Paths, "/trade-logs/{tx_hash}")
+				assert.Contains(t, doc.Paths["/trade-logs/{tx_hash}"], "get")
+				assert.Contains(t, doc.Components.Schemas, "TradelogV4")
+			},
+		},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Msg, func(t *testing.T) { httputil.RunHTTPTestCase(t, tc, router) })
+	}
+}
+
+func TestReserveScope(t *testing.T) {
+	var (
+		st     = &mockStorage{}
+		s      = NewServer(st, "", testutil.MustNewDevelopmentSugaredLogger(), nil)
+		router = s.setupRouter()
+
+		ownReserve   = ethereum.HexToAddress("0x63825c174ab367968EC60f061753D3bbD36A0D8F")
+		otherReserve = ethereum.HexToAddress("0x21433Dec9Cb634A23c6A4BbcCe08c83f5aC2EC18")
+		scope        = map[string]string{httputil.ReserveScopeHeader: ownReserve.Hex()}
+	)
+
+	var tests = []httputil.HTTPTestCase{
+		{
+			Msg:      "scoped trade logs are filtered by reserves of key",
+			Endpoint: "/trade-logs",
+			Method:   http.MethodGet,
+			Headers:  scope,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, []ethereum.Address{ownReserve}, st.reserves)
+			},
+		},
+		{
+			Msg:      "scoped stats of own reserve",
+			Endpoint: "/stats?reserve=" + strings.ToLower(ownReserve.Hex()),
+			Method:   http.MethodGet,
+			Headers:  scope,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, []ethereum.Address{ownReserve}, st.reserves)
+			},
+		},
+		{
+			Msg:      "scoped top reserves of other reserve",
+			Endpoint: "/top-reserves?reserve=" + ownReserve.Hex() + "&reserve=" + otherReserve.Hex(),
+			Method:   http.MethodGet,
+			Headers:  scope,
+			Assert:   httputil.AssertCode(http.StatusForbidden),
+		},
+		{
+			Msg:      "scoped request to endpoint not filtered by reserve",
+			Endpoint: "/top-tokens",
+			Method:   http.MethodGet,
+			Headers:  scope,
+			Assert:   httputil.AssertCode(http.StatusForbidden),
+		},
+		{
+			Msg:      "unscoped request of other reserve",
+			Endpoint: "/top-reserves?reserve=" + otherReserve.Hex(),
+			Method:   http.MethodGet,
+			Assert: func(t *testing.T, resp *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, resp.Code)
+				assert.Equal(t, []ethereum.Address{otherReserve}, st.reserves)
+			},
+		},
+		{
+			Msg:      "invalid reserve address",
+			Endpoint: "/trade-logs?reserve=invalid",
+			Method:   http.MethodGet,
+			Assert:   httputil.AssertCode(http.StatusBadRequest),
+		},
 	}
 	for _, tc := range tests {
 		tc := tc
@@ -25,15 +25,16 @@ const (
 // Interface represent a storage for TradeLogs data
 type Interface interface {
 	LoadTradeLogsByTxHash(tx ethereum.Hash) ([]common.TradelogV4, error)
-	LoadTradeLogs(from, to time.Time) ([]common.TradelogV4, error)
+	// LoadTradeLogs returns trade logs in the time range, trades are filtered by reserves if given.
+	LoadTradeLogs(from, to time.Time, reserves []ethereum.Address) ([]common.TradelogV4, error)
 	LastBlock() (int64, error)
 	SaveTradeLogs(log *common.CrawlResult) error
 	GetTokenSymbol(address string) (string, error)
 	UpdateTokens(tokenAddresses, symbols []string) error
-	GetStats(from, to time.Time) (common.StatsResponse, error)
+	GetStats(from, to time.Time, reserves []ethereum.Address) (common.StatsResponse, error)
 	GetTopTokens(from, to time.Time, limit uint64) (common.TopTokens, error)
 	GetTopIntegrations(from, to time.Time, limit uint64) (common.TopIntegrations, error)
-	GetTopReserves(from, to time.Time, limit uint64) (common.TopReserves, error)
+	GetTopReserves(from, to time.Time, limit uint64, reserves []ethereum.Address) (common.TopReserves, error)
 	GetNotTwittedTrades(from, to time.Time) ([]common.BigTradeLog, error)
 	SaveBigTrades(bigVolume float32, fromBlock uint64) error
 	UpdateBigTradesTwitted(trades []uint64) error
@@ -311,14 +311,25 @@ func (tldb *TradeLogDB) LoadTradeLogsByTxHash(tx ethereum.Hash) ([]common.Tradel
 	return result, nil
 }
 
-// LoadTradeLogs get list of tradelogs by timestamp from time to time
-func (tldb *TradeLogDB) LoadTradeLogs(from, to time.Time) ([]common.TradelogV4, error) {
+// reserveAddresses returns the addresses as an array parameter of reserve filters, an empty array
+// matches all reserves.
+func reserveAddresses(reserves []ethereum.Address) pq.StringArray {
+	result := make(pq.StringArray, 0, len(reserves))
+	for _, reserve := range reserves {
+		result = append(result, reserve.Hex())
+	}
+	return result
+}
+
+// LoadTradeLogs get list of tradelogs by timestamp from time to time, only trades routed through
+// the reserves are returned if reserves are given, with splits and fees of other reserves left out.
+func (tldb *TradeLogDB) LoadTradeLogs(from, to time.Time, reserves []ethereum.Address) ([]common.TradelogV4, error) {
 	var (
 		logger      = tldb.sugar.With("func", caller.GetCurrentFunctionName())
 		queryResult []tradeLogDBData
 		result      = make([]common.TradelogV4, 0)
 	)
-	err := tldb.db.Select(&queryResult, selectTradeLogsQuery, from, to)
+	err := tldb.db.Select(&queryResult, selectTradeLogsQuery, from, to, reserveAddresses(reserves))
 	if err != nil {
 		return nil, err
 	}
@@ -461,9 +472,16 @@ INNER JOIN token AS e ON a.src_address_id = e.id
 INNER JOIN token AS f ON a.dst_address_id = f.id
 INNER JOIN wallet as w on a.wallet_address_id = w.id
 LEFT JOIN fee ON fee.trade_id = a.id
+	AND (cardinality($3::TEXT[]) = 0 OR fee.reserve_address = ANY($3))
 LEFT JOIN split ON split.trade_id = a.id
 INNER JOIN reserve sr ON sr.id = split.reserve_id
+	AND (cardinality($3::TEXT[]) = 0 OR sr.address = ANY($3))
 WHERE a.timestamp >= $1 and a.timestamp <= $2
+AND (cardinality($3::TEXT[]) = 0 OR EXISTS (
+	SELECT 1 FROM split rs
+	INNER JOIN reserve r ON r.id = rs.reserve_id
+	WHERE rs.trade_id = a.id AND r.address = ANY($3)
+))
 GROUP BY a.id;
 `
 
@@ -139,7 +139,7 @@ func TestSaveTradeLogs(t *testing.T) {
 	require.NoError(t, err)
 	require.NoError(t, testStorage.SaveTradeLogs(&result))
 
-	tls, err := testStorage.LoadTradeLogs(timeutil.TimestampMsToTime(1554353231000), timeutil.TimestampMsToTime(1554353231000))
+	tls, err := testStorage.LoadTradeLogs(timeutil.TimestampMsToTime(1554353231000), timeutil.TimestampMsToTime(1554353231000), nil)
 	require.NoError(t, err)
 	require.Equal(t, 1, len(tls))
 }
@@ -191,7 +191,7 @@ func TestSaveTradeLogs_Overwrite(t *testing.T) {
 	tradelog2.EthAmount = big.NewInt(0).Mul(big.NewInt(2), tradelog.EthAmount)
 	require.NoError(t, testStorage.SaveTradeLogs(result))
 
-	tls, err := testStorage.LoadTradeLogs(timestamp, timestamp)
+	tls, err := testStorage.LoadTradeLogs(timestamp, timestamp, nil)
 	require.NoError(t, err)
 	require.Equal(t, len(tls), 1)
 	assert.Equal(t, tradelog2.EthAmount, tls[0].EthAmount)
@@ -216,7 +216,7 @@ func TestTradeLogDB_LoadTradeLogs(t *testing.T) {
 	}()
 	require.NoError(t, loadTestData(testStorage.db, testDataFile))
 
-	tradeLogs, err := testStorage.LoadTradeLogs(timeutil.TimestampMsToTime(fromTime), timeutil.TimestampMsToTime(toTime))
+	tradeLogs, err := testStorage.LoadTradeLogs(timeutil.TimestampMsToTime(fromTime), timeutil.TimestampMsToTime(toTime), nil)
 	require.NoError(t, err)
 	t.Log(len(tradeLogs))
 	for _, log := range tradeLogs {
@@ -239,7 +239,7 @@ func TestTradeLogDB_LoadTradeLogsByTxHash(t *testing.T) {
 	}()
 	require.NoError(t, loadTestData(testStorage.db, testDataFile))
 
-	tradeLogs, err := testStorage.LoadTradeLogs(timeutil.TimestampMsToTime(fromTime), timeutil.TimestampMsToTime(toTime))
+	tradeLogs, err := testStorage.LoadTradeLogs(timeutil.TimestampMsToTime(fromTime), timeutil.TimestampMsToTime(toTime), nil)
 	require.NoError(t, err)
 	require.NotZero(t, len(tradeLogs))
 
@@ -275,3 +275,75 @@ func TestTokenSymbol(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "ETH", symbol)
 }
+
+func TestTradeLogDB_LoadTradeLogsScopedReserves(t *testing.T) {
+	const (
+		dbName = "test_load_trade_log_scoped"
+	)
+	testStorage, err := newTestTradeLogPostgresql(dbName)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, testStorage.tearDown(dbName))
+	}()
+
+	var (
+		timestamp = timeutil.TimestampMsToTime(1554336000000)
+		reserveA  = ethereum.HexToAddress("0x63825c174ab367968ec60f061753d3bbd36a0d8f")
+		reserveB  = ethereum.HexToAddress("0x21433dec9cb634a23c6a4bbcce08c83f5ac2ec18")
+		idA       = [32]byte{0xaa}
+		idB       = [32]byte{0xbb}
+		knc       = ethereum.HexToAddress("0xdd974d5c2e2928dea5f71b9825b8b646686bd200")
+	)
+	tradelog := common.TradelogV4{
+		Timestamp:       timestamp,
+		BlockNumber:     uint64(10000000),
+		TransactionHash: ethereum.HexToHash("0x33dcdbed63556a1d90b7e0f626bfaf20f6f532d2ae8bf24c22abb15c4e1fff01"),
+		User: common.KyberUserInfo{
+			UserAddress: ethereum.HexToAddress("0x85c5c26dc2af5546341fc1988b9d178148b4838b"),
+		},
+		TokenInfo: common.TradeTokenInfo{
+			SrcAddress:  knc,
+			DestAddress: blockchain.ETHAddr,
+		},
+		T2EReserves:  [][32]byte{idA, idB},
+		T2ESrcAmount: []*big.Int{big.NewInt(1e18), big.NewInt(2e18)},
+		T2ERates:     []*big.Int{big.NewInt(1e15), big.NewInt(1e15)},
+		Fees: []common.TradelogFee{
+			{ReserveAddr: reserveA, Burn: big.NewInt(1e15), Index: 1},
+			{ReserveAddr: reserveB, Burn: big.NewInt(2e15), Index: 2},
+		},
+		SrcAmount:         big.NewInt(3e18),
+		DestAmount:        big.NewInt(3e15),
+		EthAmount:         big.NewInt(3e15),
+		OriginalEthAmount: big.NewInt(3e15),
+		Version:           4,
+	}
+	require.NoError(t, testStorage.SaveTradeLogs(&common.CrawlResult{
+		Reserves: []common.Reserve{
+			{Address: reserveA, ReserveID: idA, BlockNumber: 1},
+			{Address: reserveB, ReserveID: idB, BlockNumber: 1},
+		},
+		Trades: []common.TradelogV4{tradelog},
+	}))
+
+	tls, err := testStorage.LoadTradeLogs(timestamp, timestamp, nil)
+	require.NoError(t, err)
+	require.Len(t, tls, 1)
+	assert.Len(t, tls[0].Split, 2)
+	assert.Len(t, tls[0].Fees, 2)
+
+	// the trade is routed through reserve A, but only the split and fee of reserve A are returned
+	tls, err = testStorage.LoadTradeLogs(timestamp, timestamp, []ethereum.Address{reserveA})
+	require.NoError(t, err)
+	require.Len(t, tls, 1)
+	require.Len(t, tls[0].Split, 1)
+	assert.Equal(t, reserveA, tls[0].Split[0].ReserveAddress)
+	require.Len(t, tls[0].Fees, 1)
+	assert.Equal(t, reserveA, tls[0].Fees[0].ReserveAddr)
+
+	tls, err = testStorage.LoadTradeLogs(timestamp, timestamp, []ethereum.Address{
+		ethereum.HexToAddress("0x0000000000000000000000000000000000000001"),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, tls)
+}
@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"time"
 
+	ethereum "github.com/ethereum/go-ethereum/common"
+
 	"github.com/KyberNetwork/reserve-stats/lib/caller"
 	"github.com/KyberNetwork/reserve-stats/tradelogs/common"
 )
 
-// GetStats return tradelogs stats in a time range
-func (tldb *TradeLogDB) GetStats(from, to time.Time) (common.StatsResponse, error) {
+// GetStats return tradelogs stats in a time range, volume and fee are only counted for the reserves
+// if reserves are given.
+func (tldb *TradeLogDB) GetStats(from, to time.Time, reserves []ethereum.Address) (common.StatsResponse, error) {
 	var (
 		logger = tldb.sugar.With(
 			"from", from,
@@ -30,8 +33,11 @@ func (tldb *TradeLogDB) GetStats(from, to time.Time) (common.StatsResponse, erro
 		COALESCE(AVG(split.eth_amount*eth_usd_rate), 0) as average_trade_size
 		FROM tradelogs
 		LEFT JOIN fee ON fee.trade_id = tradelogs.id
+			AND (cardinality($3::TEXT[]) = 0 OR fee.reserve_address = ANY($3))
 		LEFT JOIN split ON split.trade_id = tradelogs.id
+			AND (cardinality($3::TEXT[]) = 0 OR split.reserve_id IN (SELECT id FROM reserve WHERE address = ANY($3)))
 	  WHERE timestamp >= $1 AND timestamp <= $2
+	  AND (cardinality($3::TEXT[]) = 0 OR split.id IS NOT NULL)
 	`
 		statsRecord struct {
 			ETHVolume        float64 `db:"eth_volume"`
@@ -44,7 +50,7 @@ func (tldb *TradeLogDB) GetStats(from, to time.Time) (common.StatsResponse, erro
 		}
 	)
 	logger.Infow("query to get tradelogs stats", "query", query)
-	if err := tldb.db.Get(&statsRecord, query, from, to); err != nil {
+	if err := tldb.db.Get(&statsRecord, query, from, to, reserveAddresses(reserves)); err != nil {
 		return common.StatsResponse{}, err
 	}
 	return common.StatsResponse{
@@ -168,8 +174,8 @@ func (tldb *TradeLogDB) GetTopIntegrations(from, to time.Time, limit uint64) (co
 	return result, nil
 }
 
-// GetTopReserves return top reserves by volume
-func (tldb *TradeLogDB) GetTopReserves(from, to time.Time, limit uint64) (common.TopReserves, error) {
+// GetTopReserves return top reserves by volume, only the reserves are ranked if reserves are given.
+func (tldb *TradeLogDB) GetTopReserves(from, to time.Time, limit uint64, reserves []ethereum.Address) (common.TopReserves, error) {
 	var (
 		logger = tldb.sugar.With(
 			"func", caller.GetCurrentFunctionName(),
@@ -192,6 +198,7 @@ func (tldb *TradeLogDB) GetTopReserves(from, to time.Time, limit uint64) (common
 	  JOIN tradelogs on tradelogs.id = split.trade_id
 	  JOIN reserve on split.reserve_id = reserve.id
 	  WHERE tradelogs.timestamp >= $1 AND tradelogs.timestamp <= $2
+	  AND (cardinality($3::TEXT[]) = 0 OR reserve.address = ANY($3))
 	  GROUP BY reserve.address, reserve.name ORDER BY usd_amount DESC
 		`
 		topReserves []struct {
@@ -204,7 +211,7 @@ func (tldb *TradeLogDB) GetTopReserves(from, to time.Time, limit uint64) (common
 		query += fmt.Sprintf(" LIMIT %d", limit)
 	}
 	logger.Infow("get top reserves", "query", query)
-	if err := tldb.db.Select(&topReserves, query, from, to); err != nil {
+	if err := tldb.db.Select(&topReserves, query, from, to, reserveAddresses(reserves)); err != nil {
 		return common.TopReserves{}, err
 	}
 	var result = make(common.TopReserves)
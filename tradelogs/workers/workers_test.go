@@ -55,7 +55,7 @@ func (s *mockStorage) SaveTradeLogs(log *common.CrawlResult) error {
 	return nil
 }
 
-func (s *mockStorage) LoadTradeLogs(from, to time.Time) ([]common.TradelogV4, error) {
+func (s *mockStorage) LoadTradeLogs(from, to time.Time, reserves []ethereum.Address) ([]common.TradelogV4, error) {
 	return nil, nil
 }
 
@@ -107,7 +107,7 @@ func (s *mockStorage) LoadTradeLogsByTxHash(tx ethereum.Hash) ([]common.Tradelog
 	return nil, nil
 }
 
-func (s *mockStorage) GetStats(from, to time.Time) (common.StatsResponse, error) {
+func (s *mockStorage) GetStats(from, to time.Time, reserves []ethereum.Address) (common.StatsResponse, error) {
 	return common.StatsResponse{}, nil
 }
 
@@ -119,7 +119,7 @@ func (s *mockStorage) GetTopIntegrations(from, to time.Time, limit uint64) (comm
 	return common.TopIntegrations{}, nil
 }
 
-func (s *mockStorage) GetTopReserves(from, to time.Time, limit uint64) (common.TopReserves, error) {
+func (s *mockStorage) GetTopReserves(from, to time.Time, limit uint64, reserves []ethereum.Address) (common.TopReserves, error) {
 	return common.TopReserves{}, nil
 }
 